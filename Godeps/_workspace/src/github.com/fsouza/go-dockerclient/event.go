@@ -1,4 +1,4 @@
-// Copyright 2014 go-dockerclient authors. All rights reserved.
+// Copyright 2015 go-dockerclient authors. All rights reserved.
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
@@ -82,10 +82,7 @@ func (c *Client) RemoveEventListener(listener chan *APIEvents) error {
 		return err
 	}
 	if len(c.eventMonitor.listeners) == 0 {
-		err = c.eventMonitor.disableEventMonitoring()
-		if err != nil {
-			return err
-		}
+		c.eventMonitor.disableEventMonitoring()
 	}
 	return nil
 }
@@ -118,8 +115,6 @@ func (eventState *eventMonitoringState) removeListener(listener chan<- *APIEvent
 }
 
 func (eventState *eventMonitoringState) closeListeners() {
-	eventState.Lock()
-	defer eventState.Unlock()
 	for _, l := range eventState.listeners {
 		close(l)
 		eventState.Add(-1)
@@ -151,9 +146,13 @@ func (eventState *eventMonitoringState) enableEventMonitoring(c *Client) error {
 }
 
 func (eventState *eventMonitoringState) disableEventMonitoring() error {
-	eventState.Wait()
 	eventState.Lock()
 	defer eventState.Unlock()
+
+	eventState.closeListeners()
+
+	eventState.Wait()
+
 	if eventState.enabled {
 		eventState.enabled = false
 		close(eventState.C)
@@ -168,7 +167,9 @@ func (eventState *eventMonitoringState) monitorEvents(c *Client) {
 		time.Sleep(10 * time.Millisecond)
 	}
 	if err = eventState.connectWithRetry(c); err != nil {
-		eventState.terminate()
+		// terminate if connect failed
+		eventState.disableEventMonitoring()
+		return
 	}
 	for eventState.isEnabled() {
 		timeout := time.After(100 * time.Millisecond)
@@ -178,15 +179,14 @@ func (eventState *eventMonitoringState) monitorEvents(c *Client) {
 				return
 			}
 			if ev == EOFEvent {
-				eventState.closeListeners()
-				eventState.terminate()
+				eventState.disableEventMonitoring()
 				return
 			}
+			eventState.updateLastSeen(ev)
 			go eventState.sendEvent(ev)
-			go eventState.updateLastSeen(ev)
 		case err = <-eventState.errC:
 			if err == ErrNoListeners {
-				eventState.terminate()
+				eventState.disableEventMonitoring()
 				return
 			} else if err != nil {
 				defer func() { go eventState.monitorEvents(c) }()
@@ -226,8 +226,8 @@ func (eventState *eventMonitoringState) sendEvent(event *APIEvents) {
 	defer eventState.RUnlock()
 	eventState.Add(1)
 	defer eventState.Done()
-	if eventState.isEnabled() {
-		if eventState.noListeners() {
+	if eventState.enabled {
+		if len(eventState.listeners) == 0 {
 			eventState.errC <- ErrNoListeners
 			return
 		}
@@ -246,10 +246,6 @@ func (eventState *eventMonitoringState) updateLastSeen(e *APIEvents) {
 	}
 }
 
-func (eventState *eventMonitoringState) terminate() {
-	eventState.disableEventMonitoring()
-}
-
 func (c *Client) eventHijack(startTime int64, eventChan chan *APIEvents, errChan chan error) error {
 	uri := "/events"
 	if startTime != 0 {
@@ -199,7 +199,7 @@ func startComponents(firstManifestURL, secondManifestURL, apiVersion string) (st
 	handler.delegate = m.Handler
 
 	// Scheduler
-	schedulerConfigFactory := factory.NewConfigFactory(cl)
+	schedulerConfigFactory := factory.NewConfigFactory(cl, api.DefaultSchedulerName)
 	schedulerConfig, err := schedulerConfigFactory.Create()
 	if err != nil {
 		glog.Fatalf("Couldn't create scheduler config: %v", err)
@@ -387,7 +387,7 @@ containers:
 				glog.Fatalf("%s FAILED: mirror pod has not been created or is not running: %v", desc, err)
 			}
 			// Delete the mirror pod, and wait for it to be recreated.
-			c.Pods(namespace).Delete(podName)
+			c.Pods(namespace).Delete(podName, nil)
 			if err = wait.Poll(time.Second, time.Second*30,
 				podRunning(c, namespace, podName)); err != nil {
 				glog.Fatalf("%s FAILED: mirror pod has not been re-created or is not running: %v", desc, err)
@@ -463,12 +463,14 @@ func runSelfLinkTestOnNamespace(c *client.Client, namespace string) {
 			},
 		},
 		Spec: api.ServiceSpec{
-			Port: 12345,
 			// This is here because validation requires it.
 			Selector: map[string]string{
 				"foo": "bar",
 			},
-			Protocol:        "TCP",
+			Ports: []api.ServicePort{{
+				Port:     12345,
+				Protocol: "TCP",
+			}},
 			SessionAffinity: "None",
 		},
 	}
@@ -525,12 +527,14 @@ func runAtomicPutTest(c *client.Client) {
 			},
 		},
 		Spec: api.ServiceSpec{
-			Port: 12345,
 			// This is here because validation requires it.
 			Selector: map[string]string{
 				"foo": "bar",
 			},
-			Protocol:        "TCP",
+			Ports: []api.ServicePort{{
+				Port:     12345,
+				Protocol: "TCP",
+			}},
 			SessionAffinity: "None",
 		},
 	}
@@ -604,12 +608,14 @@ func runPatchTest(c *client.Client) {
 			},
 		},
 		Spec: api.ServiceSpec{
-			Port: 12345,
 			// This is here because validation requires it.
 			Selector: map[string]string{
 				"foo": "bar",
 			},
-			Protocol:        "TCP",
+			Ports: []api.ServicePort{{
+				Port:     12345,
+				Protocol: "TCP",
+			}},
 			SessionAffinity: "None",
 		},
 	}
@@ -745,8 +751,10 @@ func runServiceTest(client *client.Client) {
 			Selector: map[string]string{
 				"name": "thisisalonglabel",
 			},
-			Port:            8080,
-			Protocol:        "TCP",
+			Ports: []api.ServicePort{{
+				Port:     8080,
+				Protocol: "TCP",
+			}},
 			SessionAffinity: "None",
 		},
 	}
@@ -762,8 +770,10 @@ func runServiceTest(client *client.Client) {
 			Selector: map[string]string{
 				"name": "thisisalonglabel",
 			},
-			Port:            8080,
-			Protocol:        "TCP",
+			Ports: []api.ServicePort{{
+				Port:     8080,
+				Protocol: "TCP",
+			}},
 			SessionAffinity: "None",
 		},
 	}
@@ -782,8 +792,10 @@ func runServiceTest(client *client.Client) {
 			Selector: map[string]string{
 				"name": "thisisalonglabel",
 			},
-			Port:            8080,
-			Protocol:        "TCP",
+			Ports: []api.ServicePort{{
+				Port:     8080,
+				Protocol: "TCP",
+			}},
 			SessionAffinity: "None",
 		},
 	}
@@ -852,7 +864,7 @@ func runSchedulerNoPhantomPodsTest(client *client.Client) {
 	}
 
 	// Delete a pod to free up room.
-	err = client.Pods(api.NamespaceDefault).Delete(bar.Name)
+	err = client.Pods(api.NamespaceDefault).Delete(bar.Name, nil)
 	if err != nil {
 		glog.Fatalf("FAILED: couldn't delete pod %q: %v", bar.Name, err)
 	}
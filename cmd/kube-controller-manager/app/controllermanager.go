@@ -32,11 +32,17 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	nodeControllerPkg "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/controller"
 	replicationControllerPkg "github.com/GoogleCloudPlatform/kubernetes/pkg/controller"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/daemon"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/deployment"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/job"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/master/ports"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/namespace"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/podautoscaler"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/resourcequota"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/service"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/serviceaccount"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volumeclaimbinder"
 	"github.com/cnaize/kubernetes/pkg/api"
 
 	"github.com/golang/glog"
@@ -45,20 +51,27 @@ import (
 
 // CMServer is the main context object for the controller manager.
 type CMServer struct {
-	Port                    int
-	Address                 util.IP
-	ClientConfig            client.Config
-	CloudProvider           string
-	CloudConfigFile         string
-	MinionRegexp            string
-	NodeSyncPeriod          time.Duration
-	ResourceQuotaSyncPeriod time.Duration
-	NamespaceSyncPeriod     time.Duration
-	RegisterRetryCount      int
-	MachineList             util.StringList
-	SyncNodeList            bool
-	SyncNodeStatus          bool
-	PodEvictionTimeout      time.Duration
+	Port                              int
+	Address                           util.IP
+	ClientConfig                      client.Config
+	CloudProvider                     string
+	CloudConfigFile                   string
+	MinionRegexp                      string
+	NodeSyncPeriod                    time.Duration
+	ResourceQuotaSyncPeriod           time.Duration
+	NamespaceSyncPeriod               time.Duration
+	JobSyncPeriod                     time.Duration
+	DaemonSyncPeriod                  time.Duration
+	DeploymentSyncPeriod              time.Duration
+	HorizontalPodAutoscalerSyncPeriod time.Duration
+	PVClaimBinderSyncPeriod           time.Duration
+	ServiceAccountSyncPeriod          time.Duration
+	ServiceAccountKeyFile             string
+	RegisterRetryCount                int
+	MachineList                       util.StringList
+	SyncNodeList                      bool
+	SyncNodeStatus                    bool
+	PodEvictionTimeout                time.Duration
 
 	// TODO: Discover these by pinging the host machines, and rip out these params.
 	NodeMilliCPU int64
@@ -71,17 +84,23 @@ type CMServer struct {
 // NewCMServer creates a new CMServer with a default config.
 func NewCMServer() *CMServer {
 	s := CMServer{
-		Port:                    ports.ControllerManagerPort,
-		Address:                 util.IP(net.ParseIP("127.0.0.1")),
-		NodeSyncPeriod:          10 * time.Second,
-		ResourceQuotaSyncPeriod: 10 * time.Second,
-		NamespaceSyncPeriod:     1 * time.Minute,
-		RegisterRetryCount:      10,
-		PodEvictionTimeout:      5 * time.Minute,
-		NodeMilliCPU:            1000,
-		NodeMemory:              resource.MustParse("3Gi"),
-		SyncNodeList:            true,
-		SyncNodeStatus:          false,
+		Port:                              ports.ControllerManagerPort,
+		Address:                           util.IP(net.ParseIP("127.0.0.1")),
+		NodeSyncPeriod:                    10 * time.Second,
+		ResourceQuotaSyncPeriod:           10 * time.Second,
+		NamespaceSyncPeriod:               1 * time.Minute,
+		JobSyncPeriod:                     job.DefaultSyncPeriod,
+		DaemonSyncPeriod:                  daemon.DefaultSyncPeriod,
+		DeploymentSyncPeriod:              deployment.DefaultSyncPeriod,
+		HorizontalPodAutoscalerSyncPeriod: podautoscaler.DefaultSyncPeriod,
+		PVClaimBinderSyncPeriod:           volumeclaimbinder.DefaultSyncPeriod,
+		ServiceAccountSyncPeriod:          serviceaccount.DefaultSyncPeriod,
+		RegisterRetryCount:                10,
+		PodEvictionTimeout:                5 * time.Minute,
+		NodeMilliCPU:                      1000,
+		NodeMemory:                        resource.MustParse("3Gi"),
+		SyncNodeList:                      true,
+		SyncNodeStatus:                    false,
 		KubeletConfig: client.KubeletConfig{
 			Port:        ports.KubeletPort,
 			EnableHttps: false,
@@ -104,6 +123,13 @@ func (s *CMServer) AddFlags(fs *pflag.FlagSet) {
 		"fewer calls to cloud provider, but may delay addition of new nodes to cluster.")
 	fs.DurationVar(&s.ResourceQuotaSyncPeriod, "resource_quota_sync_period", s.ResourceQuotaSyncPeriod, "The period for syncing quota usage status in the system")
 	fs.DurationVar(&s.NamespaceSyncPeriod, "namespace_sync_period", s.NamespaceSyncPeriod, "The period for syncing namespace life-cycle updates")
+	fs.DurationVar(&s.JobSyncPeriod, "job_sync_period", s.JobSyncPeriod, "The period for syncing jobs with the pods that run them")
+	fs.DurationVar(&s.DaemonSyncPeriod, "daemon_sync_period", s.DaemonSyncPeriod, "The period for syncing daemon sets with the nodes and the pods running on them")
+	fs.DurationVar(&s.DeploymentSyncPeriod, "deployment_sync_period", s.DeploymentSyncPeriod, "The period for syncing deployments with the replication controllers running their revisions")
+	fs.DurationVar(&s.HorizontalPodAutoscalerSyncPeriod, "horizontal_pod_autoscaler_sync_period", s.HorizontalPodAutoscalerSyncPeriod, "The period for syncing the number of replicas of the replication controllers targeted by horizontal pod autoscalers")
+	fs.DurationVar(&s.PVClaimBinderSyncPeriod, "pvclaimbinder_sync_period", s.PVClaimBinderSyncPeriod, "The period for binding persistent volume claims to persistent volumes and recycling released volumes")
+	fs.DurationVar(&s.ServiceAccountSyncPeriod, "service_account_sync_period", s.ServiceAccountSyncPeriod, "The period for syncing service accounts with the namespaces and API token secrets they need")
+	fs.StringVar(&s.ServiceAccountKeyFile, "service_account_private_key_file", s.ServiceAccountKeyFile, "Filename containing a PEM-encoded private RSA key used to sign service account tokens. If unspecified, no tokens are created.")
 	fs.DurationVar(&s.PodEvictionTimeout, "pod_eviction_timeout", s.PodEvictionTimeout, "The grace peroid for deleting pods on failed nodes.")
 	fs.IntVar(&s.RegisterRetryCount, "register_retry_count", s.RegisterRetryCount, ""+
 		"The number of retries for initial node registration.  Retry interval equals node_sync_period.")
@@ -164,6 +190,28 @@ func (s *CMServer) Run(_ []string) error {
 	controllerManager := replicationControllerPkg.NewReplicationManager(kubeClient)
 	controllerManager.Run(replicationControllerPkg.DefaultSyncPeriod)
 
+	jobManager := job.NewJobManager(kubeClient)
+	jobManager.Run(s.JobSyncPeriod)
+
+	daemonManager := daemon.NewDaemonManager(kubeClient)
+	daemonManager.Run(s.DaemonSyncPeriod)
+
+	deploymentManager := deployment.NewDeploymentManager(kubeClient)
+	deploymentManager.Run(s.DeploymentSyncPeriod)
+
+	pvclaimBinder := volumeclaimbinder.NewPersistentVolumeClaimBinder(kubeClient, ProbePersistentVolumePlugins())
+	pvclaimBinder.Run(s.PVClaimBinderSyncPeriod)
+
+	serviceaccount.NewServiceAccountsController(kubeClient, util.NewStringSet(serviceaccount.DefaultServiceAccountName)).Run(s.ServiceAccountSyncPeriod)
+	if len(s.ServiceAccountKeyFile) > 0 {
+		privateKey, err := serviceaccount.ReadPrivateKey(s.ServiceAccountKeyFile)
+		if err != nil {
+			glog.Errorf("Error reading key for service account token controller: %v", err)
+		} else {
+			serviceaccount.NewTokensController(kubeClient, serviceaccount.JWTTokenGenerator(privateKey)).Run(s.ServiceAccountSyncPeriod)
+		}
+	}
+
 	kubeletClient, err := client.NewKubeletClient(&s.KubeletConfig)
 	if err != nil {
 		glog.Fatalf("Failure to start kubelet client: %v", err)
@@ -182,6 +230,10 @@ func (s *CMServer) Run(_ []string) error {
 		s.RegisterRetryCount, s.PodEvictionTimeout)
 	nodeController.Run(s.NodeSyncPeriod, s.SyncNodeList, s.SyncNodeStatus)
 
+	autoscalerManager := podautoscaler.NewHorizontalPodAutoscalerManager(kubeClient, podautoscaler.NewKubeletMetricsClient(kubeletClient),
+		record.FromSource(api.EventSource{Component: "horizontal-pod-autoscaler"}))
+	autoscalerManager.Run(s.HorizontalPodAutoscalerSyncPeriod)
+
 	resourceQuotaManager := resourcequota.NewResourceQuotaManager(kubeClient)
 	resourceQuotaManager.Run(s.ResourceQuotaSyncPeriod)
 
@@ -97,9 +97,15 @@ func (set addressSet) Insert(addr *api.EndpointAddress) {
 
 func hashAddresses(addrs addressSet) string {
 	// Flatten the list of addresses into a string so it can be used as a
-	// map key.
+	// map key.  DeepHashObject does not order maps with pointer keys, so
+	// collapse the set into a sorted slice first to make the hash stable.
+	slice := []api.EndpointAddress{}
+	for k := range addrs {
+		slice = append(slice, *k)
+	}
+	sort.Sort(addrsByIP(slice))
 	hasher := md5.New()
-	util.DeepHashObject(hasher, addrs)
+	util.DeepHashObject(hasher, slice)
 	return hex.EncodeToString(hasher.Sum(nil)[0:])
 }
 
@@ -186,6 +186,19 @@ func TestPackSubsets(t *testing.T) {
 				Addresses: []api.EndpointAddress{{IP: "1.2.3.5"}},
 				Ports:     []api.EndpointPort{{Port: 222}, {Port: 333}},
 			}},
+		}, {
+			name: "two sets, same ips with target refs, different ports",
+			given: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &fooObjRef}, {IP: "1.2.3.5", TargetRef: &barObjRef}},
+				Ports:     []api.EndpointPort{{Port: 111}},
+			}, {
+				Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &fooObjRef}, {IP: "1.2.3.5", TargetRef: &barObjRef}},
+				Ports:     []api.EndpointPort{{Port: 222}},
+			}},
+			expect: []api.EndpointSubset{{
+				Addresses: []api.EndpointAddress{{IP: "1.2.3.4", TargetRef: &fooObjRef}, {IP: "1.2.3.5", TargetRef: &barObjRef}},
+				Ports:     []api.EndpointPort{{Port: 111}, {Port: 222}},
+			}},
 		},
 	}
 
@@ -17,6 +17,7 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
@@ -111,3 +112,29 @@ var standardFinalizers = util.NewStringSet(
 func IsStandardFinalizerName(str string) bool {
 	return standardFinalizers.Has(str)
 }
+
+// NodeSelectorRequirementsAsSelector converts the requirements of a node
+// selector term into a label selector matching the labels of the nodes
+// which satisfy all of them.
+func NodeSelectorRequirementsAsSelector(nsm []NodeSelectorRequirement) (labels.Selector, error) {
+	requirements := labels.LabelSelector{}
+	for _, expr := range nsm {
+		var op labels.Operator
+		switch expr.Operator {
+		case NodeSelectorOpIn:
+			op = labels.InOperator
+		case NodeSelectorOpNotIn:
+			op = labels.NotInOperator
+		case NodeSelectorOpExists:
+			op = labels.ExistsOperator
+		default:
+			return nil, fmt.Errorf("%q is not a valid node selector operator", expr.Operator)
+		}
+		r, err := labels.NewRequirement(expr.Key, op, util.NewStringSet(expr.Values...))
+		if err != nil {
+			return nil, err
+		}
+		requirements = append(requirements, *r)
+	}
+	return requirements, nil
+}
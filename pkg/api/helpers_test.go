@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 
 	"speter.net/go/exp/math/dec/inf"
 )
@@ -85,3 +86,69 @@ func TestIsStandardResource(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeSelectorRequirementsAsSelector(t *testing.T) {
+	testCases := []struct {
+		in        []NodeSelectorRequirement
+		labels    map[string]string
+		matches   bool
+		expectErr bool
+	}{
+		{
+			in:      nil,
+			labels:  map[string]string{"foo": "bar"},
+			matches: true,
+		},
+		{
+			in: []NodeSelectorRequirement{
+				{Key: "foo", Operator: NodeSelectorOpIn, Values: []string{"bar", "baz"}},
+				{Key: "gpu", Operator: NodeSelectorOpExists},
+			},
+			labels:  map[string]string{"foo": "baz", "gpu": "k80"},
+			matches: true,
+		},
+		{
+			in: []NodeSelectorRequirement{
+				{Key: "foo", Operator: NodeSelectorOpIn, Values: []string{"bar", "baz"}},
+				{Key: "gpu", Operator: NodeSelectorOpExists},
+			},
+			labels:  map[string]string{"foo": "baz"},
+			matches: false,
+		},
+		{
+			in: []NodeSelectorRequirement{
+				{Key: "foo", Operator: NodeSelectorOpNotIn, Values: []string{"bar"}},
+			},
+			labels:  map[string]string{},
+			matches: true,
+		},
+		{
+			in: []NodeSelectorRequirement{
+				{Key: "foo", Operator: NodeSelectorOpIn},
+			},
+			expectErr: true,
+		},
+		{
+			in: []NodeSelectorRequirement{
+				{Key: "foo", Operator: "Gt", Values: []string{"1"}},
+			},
+			expectErr: true,
+		},
+	}
+	for i, tc := range testCases {
+		selector, err := NodeSelectorRequirementsAsSelector(tc.in)
+		if tc.expectErr {
+			if err == nil {
+				t.Errorf("case[%d]: expected an error", i)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("case[%d]: unexpected error: %v", i, err)
+			continue
+		}
+		if e, a := tc.matches, selector.Matches(labels.Set(tc.labels)); e != a {
+			t.Errorf("case[%d]: expected %t, got %t", i, e, a)
+		}
+	}
+}
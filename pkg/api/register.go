@@ -56,6 +56,16 @@ func init() {
 		&PersistentVolumeList{},
 		&PersistentVolumeClaim{},
 		&PersistentVolumeClaimList{},
+		&Job{},
+		&JobList{},
+		&DaemonSet{},
+		&DaemonSetList{},
+		&Deployment{},
+		&DeploymentList{},
+		&ServiceAccount{},
+		&ServiceAccountList{},
+		&HorizontalPodAutoscaler{},
+		&HorizontalPodAutoscalerList{},
 		&DeleteOptions{},
 		&ListOptions{},
 	)
@@ -64,36 +74,46 @@ func init() {
 	Scheme.AddKnownTypeWithName("", "MinionList", &NodeList{})
 }
 
-func (*Pod) IsAnAPIObject()                       {}
-func (*PodList) IsAnAPIObject()                   {}
-func (*PodStatusResult) IsAnAPIObject()           {}
-func (*ReplicationController) IsAnAPIObject()     {}
-func (*ReplicationControllerList) IsAnAPIObject() {}
-func (*Service) IsAnAPIObject()                   {}
-func (*ServiceList) IsAnAPIObject()               {}
-func (*Endpoints) IsAnAPIObject()                 {}
-func (*EndpointsList) IsAnAPIObject()             {}
-func (*Node) IsAnAPIObject()                      {}
-func (*NodeInfo) IsAnAPIObject()                  {}
-func (*NodeList) IsAnAPIObject()                  {}
-func (*Binding) IsAnAPIObject()                   {}
-func (*Status) IsAnAPIObject()                    {}
-func (*Event) IsAnAPIObject()                     {}
-func (*EventList) IsAnAPIObject()                 {}
-func (*ContainerManifest) IsAnAPIObject()         {}
-func (*ContainerManifestList) IsAnAPIObject()     {}
-func (*List) IsAnAPIObject()                      {}
-func (*LimitRange) IsAnAPIObject()                {}
-func (*LimitRangeList) IsAnAPIObject()            {}
-func (*ResourceQuota) IsAnAPIObject()             {}
-func (*ResourceQuotaList) IsAnAPIObject()         {}
-func (*Namespace) IsAnAPIObject()                 {}
-func (*NamespaceList) IsAnAPIObject()             {}
-func (*Secret) IsAnAPIObject()                    {}
-func (*SecretList) IsAnAPIObject()                {}
-func (*PersistentVolume) IsAnAPIObject()          {}
-func (*PersistentVolumeList) IsAnAPIObject()      {}
-func (*PersistentVolumeClaim) IsAnAPIObject()     {}
-func (*PersistentVolumeClaimList) IsAnAPIObject() {}
-func (*DeleteOptions) IsAnAPIObject()             {}
-func (*ListOptions) IsAnAPIObject()               {}
+func (*Pod) IsAnAPIObject()                         {}
+func (*PodList) IsAnAPIObject()                     {}
+func (*PodStatusResult) IsAnAPIObject()             {}
+func (*ReplicationController) IsAnAPIObject()       {}
+func (*ReplicationControllerList) IsAnAPIObject()   {}
+func (*Service) IsAnAPIObject()                     {}
+func (*ServiceList) IsAnAPIObject()                 {}
+func (*Endpoints) IsAnAPIObject()                   {}
+func (*EndpointsList) IsAnAPIObject()               {}
+func (*Node) IsAnAPIObject()                        {}
+func (*NodeInfo) IsAnAPIObject()                    {}
+func (*NodeList) IsAnAPIObject()                    {}
+func (*Binding) IsAnAPIObject()                     {}
+func (*Status) IsAnAPIObject()                      {}
+func (*Event) IsAnAPIObject()                       {}
+func (*EventList) IsAnAPIObject()                   {}
+func (*ContainerManifest) IsAnAPIObject()           {}
+func (*ContainerManifestList) IsAnAPIObject()       {}
+func (*List) IsAnAPIObject()                        {}
+func (*LimitRange) IsAnAPIObject()                  {}
+func (*LimitRangeList) IsAnAPIObject()              {}
+func (*ResourceQuota) IsAnAPIObject()               {}
+func (*ResourceQuotaList) IsAnAPIObject()           {}
+func (*Namespace) IsAnAPIObject()                   {}
+func (*NamespaceList) IsAnAPIObject()               {}
+func (*Secret) IsAnAPIObject()                      {}
+func (*SecretList) IsAnAPIObject()                  {}
+func (*PersistentVolume) IsAnAPIObject()            {}
+func (*PersistentVolumeList) IsAnAPIObject()        {}
+func (*PersistentVolumeClaim) IsAnAPIObject()       {}
+func (*PersistentVolumeClaimList) IsAnAPIObject()   {}
+func (*Job) IsAnAPIObject()                         {}
+func (*JobList) IsAnAPIObject()                     {}
+func (*DaemonSet) IsAnAPIObject()                   {}
+func (*DaemonSetList) IsAnAPIObject()               {}
+func (*Deployment) IsAnAPIObject()                  {}
+func (*DeploymentList) IsAnAPIObject()              {}
+func (*ServiceAccount) IsAnAPIObject()              {}
+func (*ServiceAccountList) IsAnAPIObject()          {}
+func (*HorizontalPodAutoscaler) IsAnAPIObject()     {}
+func (*HorizontalPodAutoscalerList) IsAnAPIObject() {}
+func (*DeleteOptions) IsAnAPIObject()               {}
+func (*ListOptions) IsAnAPIObject()                 {}
@@ -41,6 +41,19 @@ func (self *ResourceList) Memory() *resource.Quantity {
 	return &resource.Quantity{}
 }
 
+// Returns the requests, where each resource that has a limit but no request
+// is requested at its limit.
+func (self *ResourceRequirements) EffectiveRequests() ResourceList {
+	requests := ResourceList{}
+	for name, quantity := range self.Limits {
+		requests[name] = quantity
+	}
+	for name, quantity := range self.Requests {
+		requests[name] = quantity
+	}
+	return requests
+}
+
 func GetContainerStatus(statuses []ContainerStatus, name string) (ContainerStatus, bool) {
 	for i := range statuses {
 		if statuses[i].Name == name {
@@ -51,3 +51,29 @@ func TestResourceHelpers(t *testing.T) {
 		t.Errorf("expected memorylimit %d, got %d", memoryLimit, res)
 	}
 }
+
+func TestEffectiveRequests(t *testing.T) {
+	cpuLimit := resource.MustParse("10")
+	cpuRequest := resource.MustParse("500m")
+	memoryLimit := resource.MustParse("10G")
+	resourceSpec := ResourceRequirements{
+		Limits: ResourceList{
+			"cpu":    cpuLimit,
+			"memory": memoryLimit,
+		},
+		Requests: ResourceList{
+			"cpu": cpuRequest,
+		},
+	}
+	requests := resourceSpec.EffectiveRequests()
+	if res := requests.Cpu(); *res != cpuRequest {
+		t.Errorf("expected cpu request %v, got %v", cpuRequest, res)
+	}
+	if res := requests.Memory(); *res != memoryLimit {
+		t.Errorf("expected memory request %v, got %v", memoryLimit, res)
+	}
+	resourceSpec = ResourceRequirements{}
+	if requests := resourceSpec.EffectiveRequests(); len(requests) != 0 {
+		t.Errorf("expected no requests, got %v", requests)
+	}
+}
@@ -17,7 +17,11 @@ limitations under the License.
 package rest
 
 import (
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
@@ -40,12 +44,22 @@ func BeforeDelete(strategy RESTDeleteStrategy, ctx api.Context, obj runtime.Obje
 	if strategy == nil {
 		return false, false, nil
 	}
-	_, _, kerr := objectMetaAndKind(strategy, obj)
+	objectMeta, _, kerr := objectMetaAndKind(strategy, obj)
 	if kerr != nil {
 		return false, false, kerr
 	}
 	if !strategy.CheckGracefulDelete(obj, options) {
 		return false, false, nil
 	}
+	if options.GracePeriodSeconds == nil || *options.GracePeriodSeconds < 0 {
+		return false, false, errors.NewBadRequest("the grace period must be a non-negative integer")
+	}
+	// an object that is already being deleted may only have its grace period shortened
+	if objectMeta.DeletionTimestamp != nil {
+		deadline := util.Now().Add(time.Duration(*options.GracePeriodSeconds) * time.Second)
+		if !deadline.Before(objectMeta.DeletionTimestamp.Time) {
+			return false, true, nil
+		}
+	}
 	return true, false, nil
 }
@@ -119,6 +119,32 @@ func FuzzerFor(t *testing.T, version string, src rand.Source) *fuzz.Fuzzer {
 			// only replicas round trips
 			j.Replicas = int(c.RandUint64())
 		},
+		func(j *api.JobSpec, c fuzz.Continue) {
+			c.FuzzNoCustom(j) // fuzz self without calling this function again
+			// completions and parallelism are defaulted when zero
+			j.Completions = c.Intn(1000) + 1
+			j.Parallelism = c.Intn(1000) + 1
+		},
+		func(s *api.DeploymentStrategy, c fuzz.Continue) {
+			c.FuzzNoCustom(s) // fuzz self without calling this function again
+			// the type and the rolling update parameters are defaulted when unset
+			if c.RandBool() {
+				s.Type = api.RecreateDeploymentStrategyType
+				s.RollingUpdate = nil
+			} else {
+				s.Type = api.RollingUpdateDeploymentStrategyType
+				s.RollingUpdate = &api.RollingUpdateDeployment{
+					MaxUnavailable: util.NewIntOrStringFromInt(c.Intn(10)),
+					MaxSurge:       util.NewIntOrStringFromString(strconv.Itoa(c.Intn(100)) + "%"),
+				}
+			}
+		},
+		func(pv *api.PersistentVolumeSpec, c fuzz.Continue) {
+			c.FuzzNoCustom(pv) // fuzz self without calling this function again
+			// the reclaim policy is defaulted when empty
+			policies := []api.PersistentVolumeReclaimPolicy{api.PersistentVolumeReclaimRetain, api.PersistentVolumeReclaimRecycle}
+			pv.PersistentVolumeReclaimPolicy = policies[c.Rand.Intn(len(policies))]
+		},
 		func(j *api.List, c fuzz.Continue) {
 			c.FuzzNoCustom(j) // fuzz self without calling this function again
 			if j.Items == nil {
@@ -183,6 +209,10 @@ func FuzzerFor(t *testing.T, version string, src rand.Source) *fuzz.Fuzzer {
 			protocols := []api.Protocol{api.ProtocolTCP, api.ProtocolUDP}
 			*p = protocols[c.Rand.Intn(len(protocols))]
 		},
+		func(p *api.ServiceType, c fuzz.Continue) {
+			types := []api.ServiceType{api.ServiceTypeClusterIP, api.ServiceTypeNodePort}
+			*p = types[c.Rand.Intn(len(types))]
+		},
 		func(p *api.AffinityType, c fuzz.Continue) {
 			types := []api.AffinityType{api.AffinityTypeClientIP, api.AffinityTypeNone}
 			*p = types[c.Rand.Intn(len(types))]
@@ -191,6 +221,10 @@ func FuzzerFor(t *testing.T, version string, src rand.Source) *fuzz.Fuzzer {
 			c.FuzzNoCustom(ct)                                          // fuzz self without calling this function again
 			ct.TerminationMessagePath = "/" + ct.TerminationMessagePath // Must be non-empty
 		},
+		func(s *api.ObjectFieldSelector, c fuzz.Continue) {
+			c.FuzzNoCustom(s)                 // fuzz self without calling this function again
+			s.APIVersion = "v" + s.APIVersion // can't be blank
+		},
 		func(e *api.Event, c fuzz.Continue) {
 			c.FuzzNoCustom(e) // fuzz self without calling this function again
 			// Fix event count to 1, otherwise, if a v1beta1 or v1beta2 event has a count set arbitrarily, it's count is ignored
@@ -214,13 +248,13 @@ func FuzzerFor(t *testing.T, version string, src rand.Source) *fuzz.Fuzzer {
 			c.FuzzNoCustom(http)        // fuzz self without calling this function again
 			http.Path = "/" + http.Path // can't be blank
 		},
-		func(ss *api.ServiceSpec, c fuzz.Continue) {
-			c.FuzzNoCustom(ss) // fuzz self without calling this function again
-			switch ss.TargetPort.Kind {
+		func(sp *api.ServicePort, c fuzz.Continue) {
+			c.FuzzNoCustom(sp) // fuzz self without calling this function again
+			switch sp.TargetPort.Kind {
 			case util.IntstrInt:
-				ss.TargetPort.IntVal = 1 + ss.TargetPort.IntVal%65535 // non-zero
+				sp.TargetPort.IntVal = 1 + sp.TargetPort.IntVal%65535 // non-zero
 			case util.IntstrString:
-				ss.TargetPort.StrVal = "x" + ss.TargetPort.StrVal // non-empty
+				sp.TargetPort.StrVal = "x" + sp.TargetPort.StrVal // non-empty
 			}
 		},
 		func(n *api.Node, c fuzz.Continue) {
@@ -161,6 +161,8 @@ const (
 	NamespaceNone string = ""
 	// TerminationMessagePathDefault means the default path to capture the application termination message running in a container
 	TerminationMessagePathDefault string = "/dev/termination-log"
+	// DefaultTerminationGracePeriodSeconds is the grace period used for a pod that does not specify one
+	DefaultTerminationGracePeriodSeconds int64 = 30
 )
 
 // Volume represents a named volume in a pod that may be accessed by any containers in the pod.
@@ -195,6 +197,8 @@ type VolumeSource struct {
 	Secret *SecretVolumeSource `json:"secret"`
 	// NFS represents an NFS mount on the host that shares a pod's lifetime
 	NFS *NFSVolumeSource `json:"nfs"`
+	// PersistentVolumeClaim represents a reference to a PersistentVolumeClaim in the same namespace
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim"`
 
 	ScriptableDisk *ScriptableDiskVolumeSource `json:"scriptableDisk"`
 }
@@ -230,6 +234,9 @@ type PersistentVolumeSpec struct {
 	PersistentVolumeSource `json:",inline"`
 	// holds the binding reference to a PersistentVolumeClaim
 	ClaimRef *ObjectReference `json:"claimRef,omitempty"`
+	// PersistentVolumeReclaimPolicy is what happens to a volume when it is released from its claim.
+	// Defaults to Retain.
+	PersistentVolumeReclaimPolicy PersistentVolumeReclaimPolicy `json:"persistentVolumeReclaimPolicy,omitempty"`
 }
 
 type PersistentVolumeStatus struct {
@@ -311,8 +318,33 @@ const (
 	ClaimPending PersistentVolumeClaimPhase = "Pending"
 	// used for PersistentVolumeClaims that are bound
 	ClaimBound PersistentVolumeClaimPhase = "Bound"
+	// used for PersistentVolumeClaims that lost their volume, which was deleted or
+	// bound to another claim; lost claims are not bound again
+	ClaimLost PersistentVolumeClaimPhase = "Lost"
 )
 
+// PersistentVolumeReclaimPolicy describes a policy for end-of-life maintenance of persistent volumes
+type PersistentVolumeReclaimPolicy string
+
+const (
+	// PersistentVolumeReclaimRetain means the volume is left in the Released phase for manual reclamation
+	// by the administrator.  This is the default policy.
+	PersistentVolumeReclaimRetain PersistentVolumeReclaimPolicy = "Retain"
+	// PersistentVolumeReclaimRecycle means the volume is scrubbed of its contents and made Available again
+	// once it is released from its claim.
+	PersistentVolumeReclaimRecycle PersistentVolumeReclaimPolicy = "Recycle"
+)
+
+// PersistentVolumeClaimVolumeSource references the user's PVC in the same namespace.
+// The volume backing the bound claim is mounted into the pod.
+type PersistentVolumeClaimVolumeSource struct {
+	// ClaimName is the name of a PersistentVolumeClaim in the same namespace as the pod using this volume
+	ClaimName string `json:"claimName"`
+	// Optional: Defaults to false (read/write).  ReadOnly here
+	// will force the ReadOnly setting in VolumeMounts.
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
 // HostPathVolumeSource represents a host directory mapped into a pod.
 type HostPathVolumeSource struct {
 	Path string `json:"path"`
@@ -435,8 +467,38 @@ type VolumeMount struct {
 type EnvVar struct {
 	// Required: This must be a C_IDENTIFIER.
 	Name string `json:"name"`
-	// Optional: defaults to "".
+	// Optional: no more than one of the following may be set.
+	// Optional: Defaults to "".
 	Value string `json:"value,omitempty"`
+	// Optional: Specifies a source the value of this var should come from.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+// Only one of its members may be specified.
+type EnvVarSource struct {
+	// Selects a field of the pod; only name, namespace and the pod IP are supported.
+	FieldRef *ObjectFieldSelector `json:"fieldRef,omitempty"`
+	// Selects a key of a secret in the pod's namespace.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// ObjectFieldSelector selects an APIVersioned field of an object.
+type ObjectFieldSelector struct {
+	// Required: Version of the schema the FieldPath is written in terms of.
+	// If no value is specified, it will be defaulted to the APIVersion of the
+	// enclosing object.
+	APIVersion string `json:"apiVersion"`
+	// Required: Path of the field to select in the specified API version
+	FieldPath string `json:"fieldPath"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	// Required: The name of the secret in the pod's namespace to select from.
+	Name string `json:"name"`
+	// Required: The key of the secret to select from.  Must be a valid secret key.
+	Key string `json:"key"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
@@ -472,6 +534,14 @@ type Probe struct {
 	InitialDelaySeconds int64 `json:"initialDelaySeconds,omitempty"`
 	// Length of time before health checking times out.  In seconds.
 	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty"`
+	// How often to perform the probe.  In seconds.  Zero means on every sync of the pod.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	// Zero means 1.  Must be 1 or zero for liveness probes.
+	SuccessThreshold int `json:"successThreshold,omitempty"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	// Zero means 1.
+	FailureThreshold int `json:"failureThreshold,omitempty"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -497,6 +567,30 @@ type Capabilities struct {
 	Drop []CapabilityType `json:"drop,omitempty"`
 }
 
+// SecurityContext holds security configuration that will be applied to a container.
+type SecurityContext struct {
+	// Optional: The UID to run the entrypoint of the container process.
+	// Defaults to the user specified in the image metadata.
+	RunAsUser *int64 `json:"runAsUser,omitempty"`
+	// Optional: The SELinux labels to apply to the container.
+	// Defaults to the labels allocated by the container runtime.
+	SELinuxOptions *SELinuxOptions `json:"seLinuxOptions,omitempty"`
+	// Optional: Mount the container's root filesystem as read-only. Defaults to false.
+	ReadOnlyRootFilesystem bool `json:"readOnlyRootFilesystem,omitempty"`
+}
+
+// SELinuxOptions are the labels to be applied to the container.
+type SELinuxOptions struct {
+	// SELinux user label
+	User string `json:"user,omitempty"`
+	// SELinux role label
+	Role string `json:"role,omitempty"`
+	// SELinux type label
+	Type string `json:"type,omitempty"`
+	// SELinux level label
+	Level string `json:"level,omitempty"`
+}
+
 // ResourceRequirements describes the compute resource requirements.
 type ResourceRequirements struct {
 	// Limits describes the maximum amount of compute resources required.
@@ -532,6 +626,8 @@ type Container struct {
 	ImagePullPolicy PullPolicy `json:"imagePullPolicy"`
 	// Optional: Capabilities for container.
 	Capabilities Capabilities `json:"capabilities,omitempty"`
+	// Optional: SecurityContext defines the security options the container should be run with.
+	SecurityContext *SecurityContext `json:"securityContext,omitempty"`
 }
 
 // Handler defines a specific action that should be taken
@@ -699,12 +795,138 @@ const (
 	DNSDefault DNSPolicy = "Default"
 )
 
+// Affinity is a group of affinity scheduling rules of a pod.
+type Affinity struct {
+	// NodeAffinity describes the nodes the pod should be scheduled onto.
+	NodeAffinity *NodeAffinity `json:"nodeAffinity,omitempty"`
+	// PodAffinity describes the pods the pod should be co-located with.
+	PodAffinity *PodAffinity `json:"podAffinity,omitempty"`
+	// PodAntiAffinity describes the pods the pod should not be co-located with.
+	PodAntiAffinity *PodAntiAffinity `json:"podAntiAffinity,omitempty"`
+}
+
+// NodeAffinity is a group of node affinity scheduling rules. The rules are
+// only taken into account when the pod is scheduled: a pod keeps running on
+// its node if the labels of the node change afterwards.
+type NodeAffinity struct {
+	// Required is the node selector the node must match for the pod to be
+	// scheduled onto it.
+	Required *NodeSelector `json:"required,omitempty"`
+	// Preferred are the weighted node selector terms the scheduler favors
+	// the nodes matching, without requiring them. The node with the largest
+	// sum of the weights of the terms it matches is the most preferred.
+	Preferred []PreferredSchedulingTerm `json:"preferred,omitempty"`
+}
+
+// NodeSelector is a set of node selector terms, of which a node must match
+// at least one.
+type NodeSelector struct {
+	// Required. The terms are ORed.
+	NodeSelectorTerms []NodeSelectorTerm `json:"nodeSelectorTerms"`
+}
+
+// NodeSelectorTerm is a set of requirements on the labels of a node, all of
+// which the node must match.
+type NodeSelectorTerm struct {
+	// Required. The requirements are ANDed.
+	MatchExpressions []NodeSelectorRequirement `json:"matchExpressions"`
+}
+
+// NodeSelectorOperator is the way a requirement matches the value of a label.
+type NodeSelectorOperator string
+
+const (
+	// NodeSelectorOpIn matches a label whose value is in the values.
+	NodeSelectorOpIn NodeSelectorOperator = "In"
+	// NodeSelectorOpNotIn matches the absence of the label or a value which
+	// is not in the values.
+	NodeSelectorOpNotIn NodeSelectorOperator = "NotIn"
+	// NodeSelectorOpExists matches the presence of the label, whatever its value.
+	NodeSelectorOpExists NodeSelectorOperator = "Exists"
+)
+
+// NodeSelectorRequirement is a requirement on a label of a node.
+type NodeSelectorRequirement struct {
+	// Required. The key of the label, a qualified name.
+	Key string `json:"key"`
+	// Required. The way the value of the label is matched.
+	Operator NodeSelectorOperator `json:"operator"`
+	// The values the label is matched against. Must be non-empty for In and
+	// NotIn, and empty for Exists.
+	Values []string `json:"values,omitempty"`
+}
+
+// PreferredSchedulingTerm is a node selector term with a weight.
+type PreferredSchedulingTerm struct {
+	// Required. The weight of the term, in the range 1-100.
+	Weight int `json:"weight"`
+	// Required. The term the node is matched against.
+	Preference NodeSelectorTerm `json:"preference"`
+}
+
+// PodAffinity is a group of inter-pod affinity scheduling rules, describing
+// the pods the pod should be co-located with. The rules are only taken into
+// account when the pod is scheduled.
+type PodAffinity struct {
+	// Required are the terms the node must satisfy for the pod to be scheduled
+	// onto it: for each term, a pod matched by the term must run in the
+	// topology domain of the node.
+	Required []PodAffinityTerm `json:"required,omitempty"`
+	// Preferred are the weighted terms the scheduler favors the nodes
+	// satisfying, without requiring them.
+	Preferred []WeightedPodAffinityTerm `json:"preferred,omitempty"`
+}
+
+// PodAntiAffinity is a group of inter-pod anti-affinity scheduling rules,
+// describing the pods the pod should not be co-located with. The rules are
+// only taken into account when the pod is scheduled.
+type PodAntiAffinity struct {
+	// Required are the terms the node must satisfy for the pod to be scheduled
+	// onto it: for each term, no pod matched by the term may run in the
+	// topology domain of the node.
+	Required []PodAffinityTerm `json:"required,omitempty"`
+	// Preferred are the weighted terms the scheduler favors the nodes
+	// satisfying, without requiring them.
+	Preferred []WeightedPodAffinityTerm `json:"preferred,omitempty"`
+}
+
+// PodAffinityTerm selects a set of pods and the topology domains, such as the
+// nodes or the zones, the pods are considered co-located in.
+type PodAffinityTerm struct {
+	// Required. LabelSelector selects the pods the term applies to.
+	LabelSelector map[string]string `json:"labelSelector"`
+	// Namespaces are the namespaces of the pods the term applies to. Empty
+	// means the namespace of the pod holding the term.
+	Namespaces []string `json:"namespaces,omitempty"`
+	// Required. TopologyKey is the node label defining the topology domains:
+	// two pods are co-located when their nodes have the same value for it.
+	TopologyKey string `json:"topologyKey"`
+}
+
+// WeightedPodAffinityTerm is a pod affinity term with a weight.
+type WeightedPodAffinityTerm struct {
+	// Required. The weight of the term, in the range 1-100.
+	Weight int `json:"weight"`
+	// Required. The term the node is matched against.
+	PodAffinityTerm PodAffinityTerm `json:"podAffinityTerm"`
+}
+
 // PodSpec is a description of a pod
 type PodSpec struct {
 	Volumes []Volume `json:"volumes"`
 	// Required: there must be at least one container in a pod.
 	Containers    []Container   `json:"containers"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty"`
+	// Optional duration in seconds the pod may be active on the node relative to
+	// StartTime before the system will actively try to mark it failed and kill associated containers.
+	// Value must be a positive integer.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty"`
+	// Optional duration in seconds the pod needs to terminate gracefully. May be decreased in delete request.
+	// Value must be a non-negative integer. The value zero indicates delete immediately.
+	// If this value is nil, DefaultTerminationGracePeriodSeconds will be used instead.
+	// The grace period is the duration in seconds after the processes running in the pod are sent
+	// a termination signal and the time when the processes are forcibly halted with a kill signal.
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty"`
 	// Required: Set DNS policy.
 	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
@@ -718,7 +940,28 @@ type PodSpec struct {
 	// used must be specified.
 	// Optional: Default to false.
 	HostNetwork bool `json:"hostNetwork,omitempty"`
-}
+	// ServiceAccount is the name of the ServiceAccount to use to run this pod.
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// ImagePullSecrets is a list of references to secrets in the same namespace holding
+	// dockercfg credentials used to pull the images of this pod, in addition to the
+	// credentials configured on the node.
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	// Tolerations allow the pod to be scheduled onto nodes with matching taints.
+	Tolerations []Toleration `json:"tolerations,omitempty"`
+	// Affinity holds the affinity scheduling rules of the pod.
+	Affinity *Affinity `json:"affinity,omitempty"`
+	// Priority is the importance of the pod relative to the other pods. When
+	// no node fits the pod, the scheduler may evict pods of lower priority to
+	// make room for it.
+	Priority int `json:"priority,omitempty"`
+	// SchedulerName is the name of the scheduler which places the pod. When
+	// empty, the pod is placed by the default scheduler.
+	SchedulerName string `json:"schedulerName,omitempty"`
+}
+
+// DefaultSchedulerName is the name of the scheduler which places pods that
+// do not name a scheduler.
+const DefaultSchedulerName = "default-scheduler"
 
 // PodStatus represents information about the status of a pod. Status may trail the actual
 // state of a system.
@@ -727,6 +970,8 @@ type PodStatus struct {
 	Conditions []PodCondition `json:"Condition,omitempty"`
 	// A human readable message indicating details about why the pod is in this state.
 	Message string `json:"message,omitempty"`
+	// A brief CamelCase message indicating details about why the pod is in this state. e.g. 'DeadlineExceeded'
+	Reason string `json:"reason,omitempty"`
 
 	// Host is the name of the node that this Pod is currently bound to, or empty if no
 	// assignment has been done.
@@ -734,6 +979,10 @@ type PodStatus struct {
 	HostIP string `json:"hostIP,omitempty"`
 	PodIP  string `json:"podIP,omitempty"`
 
+	// Date and time at which the pod was acknowledged by the Kubelet.
+	// This is before the Kubelet pulled the container image(s) for the pod.
+	StartTime *util.Time `json:"startTime,omitempty"`
+
 	// The list has one entry per container in the manifest. Each entry is
 	// currently the output of `docker inspect`. This output format is *not*
 	// final and should not be relied upon.
@@ -840,6 +1089,277 @@ type ReplicationControllerList struct {
 	Items []ReplicationController `json:"items"`
 }
 
+// JobSpec describes how the job execution will look like.
+type JobSpec struct {
+	// Completions is the desired number of successfully finished pods the
+	// job should be run with.
+	Completions int `json:"completions"`
+
+	// Parallelism is the maximum desired number of pods the job should
+	// run at any given time.
+	Parallelism int `json:"parallelism"`
+
+	// Selector is a label query over pods that should match the pod count.
+	Selector map[string]string `json:"selector"`
+
+	// Template is the object that describes the pod that will be created when
+	// executing a job.
+	Template *PodTemplateSpec `json:"template,omitempty"`
+}
+
+// JobStatus represents the current state of a Job.
+type JobStatus struct {
+	// StartTime represents time when the job was acknowledged by the job controller.
+	StartTime *util.Time `json:"startTime,omitempty"`
+
+	// CompletionTime represents time when the job was completed.
+	CompletionTime *util.Time `json:"completionTime,omitempty"`
+
+	// Active is the number of actively running pods.
+	Active int `json:"active"`
+
+	// Succeeded is the number of pods which reached phase Succeeded.
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of pods which reached phase Failed.
+	Failed int `json:"failed"`
+}
+
+// Job represents the configuration of a single job, which runs pods until
+// a desired number of them have completed successfully.
+type Job struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is a structure defining the expected behavior of a job.
+	Spec JobSpec `json:"spec,omitempty"`
+
+	// Status is a structure describing current status of a job.
+	Status JobStatus `json:"status,omitempty"`
+}
+
+// JobList is a collection of jobs.
+type JobList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []Job `json:"items"`
+}
+
+// DaemonSetSpec is the specification of a daemon set.
+type DaemonSetSpec struct {
+	// Selector is a label query over pods that are managed by the daemon set.
+	Selector map[string]string `json:"selector"`
+
+	// NodeSelector is a label query over nodes that should run a pod of the
+	// daemon set. An empty node selector matches every node.
+	NodeSelector map[string]string `json:"nodeSelector,omitempty"`
+
+	// Template is the object that describes the pod that will be created on
+	// every node matching the node selector.
+	Template *PodTemplateSpec `json:"template,omitempty"`
+}
+
+// DaemonSetStatus represents the current status of a daemon set.
+type DaemonSetStatus struct {
+	// CurrentNumberScheduled is the number of nodes that are running a pod of
+	// the daemon set and are supposed to run it.
+	CurrentNumberScheduled int `json:"currentNumberScheduled"`
+
+	// NumberMisscheduled is the number of nodes that are running a pod of the
+	// daemon set, but are not supposed to run it.
+	NumberMisscheduled int `json:"numberMisscheduled"`
+
+	// DesiredNumberScheduled is the number of nodes that should be running a
+	// pod of the daemon set.
+	DesiredNumberScheduled int `json:"desiredNumberScheduled"`
+}
+
+// DaemonSet represents the configuration of a daemon set, which runs a copy
+// of a pod on every node matching its node selector.
+type DaemonSet struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired behavior of this daemon set.
+	Spec DaemonSetSpec `json:"spec,omitempty"`
+
+	// Status is the current status of this daemon set. This data may be
+	// out of date by some window of time.
+	Status DaemonSetStatus `json:"status,omitempty"`
+}
+
+// DaemonSetList is a collection of daemon sets.
+type DaemonSetList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []DaemonSet `json:"items"`
+}
+
+// DeploymentStrategyType is the kind of strategy used to replace the pods of
+// a deployment when its template changes.
+type DeploymentStrategyType string
+
+const (
+	// RecreateDeploymentStrategyType kills all existing pods before creating new ones.
+	RecreateDeploymentStrategyType DeploymentStrategyType = "Recreate"
+
+	// RollingUpdateDeploymentStrategyType gradually replaces the old pods by new ones.
+	RollingUpdateDeploymentStrategyType DeploymentStrategyType = "RollingUpdate"
+)
+
+// DeploymentStrategy describes how to replace existing pods with new ones.
+type DeploymentStrategy struct {
+	// Type of deployment. Can be "Recreate" or "RollingUpdate".
+	Type DeploymentStrategyType `json:"type,omitempty"`
+
+	// RollingUpdate holds the parameters of the rolling update, it must only
+	// be set when Type is RollingUpdate.
+	RollingUpdate *RollingUpdateDeployment `json:"rollingUpdate,omitempty"`
+}
+
+// RollingUpdateDeployment controls the pace of a rolling update.
+type RollingUpdateDeployment struct {
+	// MaxUnavailable is the maximum number of pods that can be unavailable
+	// during the update, either an absolute number or a percentage of the
+	// desired replicas ("25%"). Percentages are rounded down.
+	MaxUnavailable util.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MaxSurge is the maximum number of pods that can be created above the
+	// desired replicas during the update, either an absolute number or a
+	// percentage of the desired replicas ("25%"). Percentages are rounded up.
+	MaxSurge util.IntOrString `json:"maxSurge,omitempty"`
+}
+
+// RollbackConfig asks for the template of a deployment to be reset to the
+// one of an earlier revision.
+type RollbackConfig struct {
+	// Revision to roll back to. Zero means the revision before the current one.
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// DeploymentSpec is the specification of a deployment.
+type DeploymentSpec struct {
+	// Replicas is the number of desired pods.
+	Replicas int `json:"replicas"`
+
+	// Selector is a label query over pods that are managed by the deployment.
+	Selector map[string]string `json:"selector"`
+
+	// Template is the object that describes the pods that will be created.
+	// Every change to the template is rolled out as a new revision.
+	Template *PodTemplateSpec `json:"template,omitempty"`
+
+	// Strategy is the strategy used to replace the pods of an older revision.
+	Strategy DeploymentStrategy `json:"strategy,omitempty"`
+
+	// Paused stops the rollout of the deployment until it is set back to false.
+	Paused bool `json:"paused,omitempty"`
+
+	// RollbackTo is set to request a rollback. It is cleared by the system
+	// once the template has been reset.
+	RollbackTo *RollbackConfig `json:"rollbackTo,omitempty"`
+}
+
+// DeploymentStatus represents the current status of a deployment.
+type DeploymentStatus struct {
+	// Replicas is the number of pods targeted by the deployment, across all
+	// revisions.
+	Replicas int `json:"replicas,omitempty"`
+
+	// UpdatedReplicas is the number of pods running the current template.
+	UpdatedReplicas int `json:"updatedReplicas,omitempty"`
+
+	// AvailableReplicas is the number of pods targeted by the deployment
+	// which are ready.
+	AvailableReplicas int `json:"availableReplicas,omitempty"`
+
+	// Revision is the revision of the current template.
+	Revision int64 `json:"revision,omitempty"`
+}
+
+// Deployment represents the configuration of a deployment, which rolls out
+// changes of a pod template by managing replication controllers.
+type Deployment struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired behavior of this deployment.
+	Spec DeploymentSpec `json:"spec,omitempty"`
+
+	// Status is the current status of this deployment. This data may be
+	// out of date by some window of time.
+	Status DeploymentStatus `json:"status,omitempty"`
+}
+
+// DeploymentList is a collection of deployments.
+type DeploymentList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []Deployment `json:"items"`
+}
+
+// HorizontalPodAutoscalerSpec is the specification of a horizontal pod autoscaler.
+type HorizontalPodAutoscalerSpec struct {
+	// ScaleRef references the replication controller, in the same namespace,
+	// whose number of replicas is adjusted by the autoscaler.
+	ScaleRef LocalObjectReference `json:"scaleRef"`
+
+	// MinReplicas is the lower limit for the number of replicas.
+	MinReplicas int `json:"minReplicas"`
+
+	// MaxReplicas is the upper limit for the number of replicas. It cannot be
+	// lower than MinReplicas.
+	MaxReplicas int `json:"maxReplicas"`
+
+	// TargetCPUUtilizationPercentage is the target average CPU utilization of
+	// the pods, as a percentage of the CPU they request.
+	TargetCPUUtilizationPercentage int `json:"targetCPUUtilizationPercentage"`
+}
+
+// HorizontalPodAutoscalerStatus represents the current status of a horizontal
+// pod autoscaler.
+type HorizontalPodAutoscalerStatus struct {
+	// CurrentReplicas is the number of replicas last observed by the autoscaler.
+	CurrentReplicas int `json:"currentReplicas"`
+
+	// DesiredReplicas is the number of replicas last computed by the autoscaler.
+	DesiredReplicas int `json:"desiredReplicas"`
+
+	// CurrentCPUUtilizationPercentage is the average CPU utilization of the
+	// pods last observed, as a percentage of the CPU they request. It is not
+	// set if the utilization could not be measured.
+	CurrentCPUUtilizationPercentage *int `json:"currentCPUUtilizationPercentage,omitempty"`
+
+	// LastScaleTime is the last time the autoscaler changed the number of
+	// replicas.
+	LastScaleTime *util.Time `json:"lastScaleTime,omitempty"`
+}
+
+// HorizontalPodAutoscaler adjusts the number of replicas of a replication
+// controller to keep the CPU utilization of its pods close to a target.
+type HorizontalPodAutoscaler struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec defines the desired behavior of this autoscaler.
+	Spec HorizontalPodAutoscalerSpec `json:"spec,omitempty"`
+
+	// Status is the current status of this autoscaler. This data may be
+	// out of date by some window of time.
+	Status HorizontalPodAutoscalerStatus `json:"status,omitempty"`
+}
+
+// HorizontalPodAutoscalerList is a collection of horizontal pod autoscalers.
+type HorizontalPodAutoscalerList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []HorizontalPodAutoscaler `json:"items"`
+}
+
 const (
 	// PortalIPNone - do not assign a portal IP
 	// no proxying required and no environment variables should be created for pods
@@ -865,17 +1385,26 @@ const (
 	AffinityTypeNone AffinityType = "None"
 )
 
+// Service Type string describes ingress methods for a service
+type ServiceType string
+
+const (
+	// ServiceTypeClusterIP means a service will only be accessible inside the
+	// cluster, via the portal IP.
+	ServiceTypeClusterIP ServiceType = "ClusterIP"
+
+	// ServiceTypeNodePort means a service will be exposed on one port of
+	// every node, in addition to 'ClusterIP' type.
+	ServiceTypeNodePort ServiceType = "NodePort"
+)
+
 // ServiceStatus represents the current status of a service
 type ServiceStatus struct{}
 
 // ServiceSpec describes the attributes that a user creates on a service
 type ServiceSpec struct {
-	// Port is the TCP or UDP port that will be made available to each pod for connecting to the pods
-	// proxied by this service.
-	Port int `json:"port"`
-
-	// Required: Supports "TCP" and "UDP".
-	Protocol Protocol `json:"protocol,omitempty"`
+	// Required: The list of ports that are exposed by this service.
+	Ports []ServicePort `json:"ports"`
 
 	// This service will route traffic to pods having labels matching this selector. If empty or not present,
 	// the service is assumed to have endpoints set by an external process and Kubernetes will not modify
@@ -889,6 +1418,9 @@ type ServiceSpec struct {
 	// None can be specified for headless services when proxying is not required
 	PortalIP string `json:"portalIP,omitempty"`
 
+	// Required: Type determines how the service will be exposed.  Valid options: ClusterIP, NodePort
+	Type ServiceType `json:"type,omitempty"`
+
 	// CreateExternalLoadBalancer indicates whether a load balancer should be created for this service.
 	CreateExternalLoadBalancer bool `json:"createExternalLoadBalancer,omitempty"`
 	// PublicIPs are used by external load balancers, or can be set by
@@ -898,17 +1430,34 @@ type ServiceSpec struct {
 	// For hostnames, the user will use a CNAME record (instead of using an A record with the IP)
 	PublicIPs []string `json:"publicIPs,omitempty"`
 
-	// TargetPort is the name or number of the port on the container to direct traffic to.
-	// This is useful if the containers the service points to have multiple open ports.
-	// Optional: If unspecified, the first port on the container will be used.
-	// As of v1beta3 this field will become required in the internal API,
-	// and the versioned APIs must provide a default value.
-	TargetPort util.IntOrString `json:"targetPort,omitempty"`
-
 	// Required: Supports "ClientIP" and "None".  Used to maintain session affinity.
 	SessionAffinity AffinityType `json:"sessionAffinity,omitempty"`
 }
 
+// ServicePort describes a single port exposed by a service.
+type ServicePort struct {
+	// Optional if only one ServicePort is defined on this service: The
+	// name of this port within the service.  This must be a DNS_LABEL.
+	// All ports within a ServiceSpec must have unique names.  This maps to
+	// the 'Name' field in EndpointPort objects.
+	Name string `json:"name"`
+
+	// Required: Supports "TCP" and "UDP".
+	Protocol Protocol `json:"protocol"`
+
+	// Required: The port that will be exposed on the service.
+	Port int `json:"port"`
+
+	// Required: The name or number of the port on the container to direct
+	// traffic to.  The versioned APIs must provide a default value.
+	TargetPort util.IntOrString `json:"targetPort"`
+
+	// The port on each node on which this service is exposed.  Only
+	// meaningful for services of type NodePort.  If zero, a port is
+	// allocated by the master from the cluster-wide node port range.
+	NodePort int `json:"nodePort"`
+}
+
 // Service is a named abstraction of software service (for example, mysql) consisting of local port
 // (for example 3306) that the proxy listens on, and the selector that determines which pods
 // will answer requests sent through the proxy.
@@ -1000,6 +1549,52 @@ type NodeSpec struct {
 
 	// Unschedulable controls node schedulability of new pods. By default node is schedulable.
 	Unschedulable bool `json:"unschedulable,omitempty"`
+
+	// Taints keep the pods which do not tolerate them away from the node.
+	Taints []Taint `json:"taints,omitempty"`
+}
+
+// TaintEffect is the effect of a taint on the pods which do not tolerate it.
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule prevents the pods which do not tolerate the taint
+	// from being scheduled onto the node. Pods already running on the node
+	// are not affected.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+)
+
+// Taint is attached to a node to repel the pods which do not tolerate it.
+type Taint struct {
+	// Required. The key of the taint, a qualified name.
+	Key string `json:"key"`
+	// The value of the taint, a label value.
+	Value string `json:"value,omitempty"`
+	// Required. The effect of the taint on the pods which do not tolerate it.
+	Effect TaintEffect `json:"effect"`
+}
+
+// TolerationOperator is the way a toleration matches the value of a taint.
+type TolerationOperator string
+
+const (
+	// TolerationOpEqual matches a taint with the same key and value.
+	TolerationOpEqual TolerationOperator = "Equal"
+	// TolerationOpExists matches a taint with the same key, whatever its value.
+	TolerationOpExists TolerationOperator = "Exists"
+)
+
+// Toleration allows a pod to be scheduled onto the nodes with a matching taint.
+type Toleration struct {
+	// Required. The key of the taints the toleration applies to.
+	Key string `json:"key"`
+	// Operator is the way the value of the taint is matched. Empty means Equal.
+	Operator TolerationOperator `json:"operator,omitempty"`
+	// The value the taint must have when the operator is Equal. Must be empty
+	// when the operator is Exists.
+	Value string `json:"value,omitempty"`
+	// The effect of the taints the toleration applies to. Empty matches all effects.
+	Effect TaintEffect `json:"effect,omitempty"`
 }
 
 // NodeSystemInfo is a set of ids/uuids to uniquely identify the node.
@@ -1132,6 +1727,15 @@ type Node struct {
 	Status NodeStatus `json:"status,omitempty"`
 }
 
+const (
+	// LabelZoneFailureDomain is the label on nodes and persistent volumes which
+	// names the failure domain (zone) they are in.
+	LabelZoneFailureDomain = "failure-domain.kubernetes.io/zone"
+	// LabelZoneRegion is the label on nodes and persistent volumes which names
+	// the region they are in.
+	LabelZoneRegion = "failure-domain.kubernetes.io/region"
+)
+
 // NodeList is a list of nodes.
 type NodeList struct {
 	TypeMeta `json:",inline"`
@@ -1449,6 +2053,11 @@ type ObjectReference struct {
 	FieldPath string `json:"fieldPath,omitempty"`
 }
 
+// LocalObjectReference contains enough information to let you locate the referenced object inside the same namespace.
+type LocalObjectReference struct {
+	Name string `json:"name,omitempty"`
+}
+
 type EventSource struct {
 	// Component from which the event is generated.
 	Component string `json:"component,omitempty"`
@@ -1625,6 +2234,24 @@ type ResourceQuotaList struct {
 	Items []ResourceQuota `json:"items"`
 }
 
+// ServiceAccount binds together a name understood by users, a principal that can be
+// authenticated and authorized, and the set of secrets pods running as it may use.
+type ServiceAccount struct {
+	TypeMeta   `json:",inline"`
+	ObjectMeta `json:"metadata,omitempty"`
+
+	// Secrets is the list of secrets allowed to be used by pods running using this ServiceAccount
+	Secrets []ObjectReference `json:"secrets"`
+}
+
+// ServiceAccountList is a list of ServiceAccount objects
+type ServiceAccountList struct {
+	TypeMeta `json:",inline"`
+	ListMeta `json:"metadata,omitempty"`
+
+	Items []ServiceAccount `json:"items"`
+}
+
 // Secret holds secret data of a certain type.  The total bytes of the values in
 // the Data field must be less than MaxSecretSize bytes.
 type Secret struct {
@@ -1646,6 +2273,30 @@ type SecretType string
 
 const (
 	SecretTypeOpaque SecretType = "Opaque" // Default; arbitrary user-defined data
+
+	// SecretTypeServiceAccountToken contains a token that identifies a service account to the API
+	//
+	// Required fields:
+	// - Secret.Annotations["kubernetes.io/service-account.name"] - the name of the ServiceAccount the token identifies
+	// - Secret.Annotations["kubernetes.io/service-account.uid"] - the UID of the ServiceAccount the token identifies
+	// - Secret.Data["token"] - a token that identifies the service account to the API
+	SecretTypeServiceAccountToken SecretType = "kubernetes.io/service-account-token"
+
+	// ServiceAccountNameKey is the key of the required annotation for SecretTypeServiceAccountToken secrets
+	ServiceAccountNameKey = "kubernetes.io/service-account.name"
+	// ServiceAccountUIDKey is the key of the required annotation for SecretTypeServiceAccountToken secrets
+	ServiceAccountUIDKey = "kubernetes.io/service-account.uid"
+	// ServiceAccountTokenKey is the key of the required data for SecretTypeServiceAccountToken secrets
+	ServiceAccountTokenKey = "token"
+
+	// SecretTypeDockercfg contains a dockercfg file that follows the same format rules as ~/.dockercfg
+	//
+	// Required fields:
+	// - Secret.Data[".dockercfg"] - a serialized ~/.dockercfg file
+	SecretTypeDockercfg SecretType = "kubernetes.io/dockercfg"
+
+	// DockerConfigKey is the key of the required data for SecretTypeDockercfg secrets
+	DockerConfigKey = ".dockercfg"
 )
 
 type SecretList struct {
@@ -116,7 +116,7 @@ func init() {
 			out.Value = in.Value
 			out.Key = in.Name
 			out.Name = in.Name
-			return nil
+			return s.Convert(&in.ValueFrom, &out.ValueFrom, 0)
 		},
 		func(in *EnvVar, out *newer.EnvVar, s conversion.Scope) error {
 			out.Value = in.Value
@@ -125,7 +125,7 @@ func init() {
 			} else {
 				out.Name = in.Key
 			}
-			return nil
+			return s.Convert(&in.ValueFrom, &out.ValueFrom, 0)
 		},
 
 		// Path & MountType are deprecated.
@@ -192,10 +192,11 @@ func init() {
 				return err
 			}
 			out.Message = in.Message
+			out.Reason = in.Reason
 			out.Host = in.Host
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
-			return nil
+			return s.Convert(&in.StartTime, &out.StartTime, 0)
 		},
 		func(in *PodState, out *newer.PodStatus, s conversion.Scope) error {
 			if err := s.Convert(&in.Status, &out.Phase, 0); err != nil {
@@ -209,10 +210,11 @@ func init() {
 			}
 
 			out.Message = in.Message
+			out.Reason = in.Reason
 			out.Host = in.Host
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
-			return nil
+			return s.Convert(&in.StartTime, &out.StartTime, 0)
 		},
 		func(in *newer.PodSpec, out *PodState, s conversion.Scope) error {
 			if err := s.Convert(&in, &out.Manifest, 0); err != nil {
@@ -577,6 +579,9 @@ func init() {
 			if err := s.Convert(&in.Capabilities, &out.Capabilities, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.SecurityContext, &out.SecurityContext, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		// Internal API does not support CPU to be specified via an explicit field.
@@ -660,6 +665,9 @@ func init() {
 			if err := s.Convert(&in.Capabilities, &out.Capabilities, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.SecurityContext, &out.SecurityContext, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		func(in *newer.PodSpec, out *ContainerManifest, s conversion.Scope) error {
@@ -675,7 +683,22 @@ func init() {
 			out.DNSPolicy = DNSPolicy(in.DNSPolicy)
 			out.Version = "v1beta2"
 			out.HostNetwork = in.HostNetwork
-			return nil
+			out.ServiceAccount = in.ServiceAccount
+			out.Priority = in.Priority
+			out.SchedulerName = in.SchedulerName
+			if err := s.Convert(&in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ImagePullSecrets, &out.ImagePullSecrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Tolerations, &out.Tolerations, 0); err != nil {
+				return err
+			}
+			return s.Convert(&in.Affinity, &out.Affinity, 0)
 		},
 		func(in *ContainerManifest, out *newer.PodSpec, s conversion.Scope) error {
 			if err := s.Convert(&in.Volumes, &out.Volumes, 0); err != nil {
@@ -689,7 +712,22 @@ func init() {
 			}
 			out.DNSPolicy = newer.DNSPolicy(in.DNSPolicy)
 			out.HostNetwork = in.HostNetwork
-			return nil
+			out.ServiceAccount = in.ServiceAccount
+			out.Priority = in.Priority
+			out.SchedulerName = in.SchedulerName
+			if err := s.Convert(&in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ImagePullSecrets, &out.ImagePullSecrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Tolerations, &out.Tolerations, 0); err != nil {
+				return err
+			}
+			return s.Convert(&in.Affinity, &out.Affinity, 0)
 		},
 
 		func(in *newer.Service, out *Service, s conversion.Scope) error {
@@ -703,15 +741,24 @@ func init() {
 				return err
 			}
 
-			out.Port = in.Spec.Port
-			out.Protocol = Protocol(in.Spec.Protocol)
+			// Produce legacy fields.
+			if len(in.Spec.Ports) > 0 {
+				out.PortName = in.Spec.Ports[0].Name
+				out.Port = in.Spec.Ports[0].Port
+				out.Protocol = Protocol(in.Spec.Ports[0].Protocol)
+				out.ContainerPort = in.Spec.Ports[0].TargetPort
+			}
+			// Copy modern fields.
+			if err := s.Convert(&in.Spec.Ports, &out.Ports, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.Spec.Selector, &out.Selector, 0); err != nil {
 				return err
 			}
 			out.CreateExternalLoadBalancer = in.Spec.CreateExternalLoadBalancer
 			out.PublicIPs = in.Spec.PublicIPs
-			out.ContainerPort = in.Spec.TargetPort
 			out.PortalIP = in.Spec.PortalIP
+			out.Type = ServiceType(in.Spec.Type)
 			if err := s.Convert(&in.Spec.SessionAffinity, &out.SessionAffinity, 0); err != nil {
 				return err
 			}
@@ -729,21 +776,49 @@ func init() {
 				return err
 			}
 
-			out.Spec.Port = in.Port
-			out.Spec.Protocol = newer.Protocol(in.Protocol)
+			if len(in.Ports) == 0 && in.Port != 0 {
+				// Use legacy fields to produce modern fields.
+				out.Spec.Ports = append(out.Spec.Ports, newer.ServicePort{
+					Name:       in.PortName,
+					Port:       in.Port,
+					Protocol:   newer.Protocol(in.Protocol),
+					TargetPort: in.ContainerPort,
+				})
+			} else {
+				// Use modern fields, ignore legacy.
+				if err := s.Convert(&in.Ports, &out.Spec.Ports, 0); err != nil {
+					return err
+				}
+			}
 			if err := s.Convert(&in.Selector, &out.Spec.Selector, 0); err != nil {
 				return err
 			}
 			out.Spec.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
 			out.Spec.PublicIPs = in.PublicIPs
-			out.Spec.TargetPort = in.ContainerPort
 			out.Spec.PortalIP = in.PortalIP
+			out.Spec.Type = newer.ServiceType(in.Type)
 			if err := s.Convert(&in.SessionAffinity, &out.Spec.SessionAffinity, 0); err != nil {
 				return err
 			}
 
 			return nil
 		},
+		func(in *newer.ServicePort, out *ServicePort, s conversion.Scope) error {
+			out.Name = in.Name
+			out.Protocol = Protocol(in.Protocol)
+			out.Port = in.Port
+			out.ContainerPort = in.TargetPort
+			out.NodePort = in.NodePort
+			return nil
+		},
+		func(in *ServicePort, out *newer.ServicePort, s conversion.Scope) error {
+			out.Name = in.Name
+			out.Protocol = newer.Protocol(in.Protocol)
+			out.Port = in.Port
+			out.TargetPort = in.ContainerPort
+			out.NodePort = in.NodePort
+			return nil
+		},
 
 		func(in *newer.Node, out *Minion, s conversion.Scope) error {
 			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
@@ -776,6 +851,9 @@ func init() {
 			out.PodCIDR = in.Spec.PodCIDR
 			out.ExternalID = in.Spec.ExternalID
 			out.Unschedulable = in.Spec.Unschedulable
+			if err := s.Convert(&in.Spec.Taints, &out.Taints, 0); err != nil {
+				return err
+			}
 			return s.Convert(&in.Status.Capacity, &out.NodeResources.Capacity, 0)
 		},
 		func(in *Minion, out *newer.Node, s conversion.Scope) error {
@@ -808,6 +886,9 @@ func init() {
 			out.Spec.PodCIDR = in.PodCIDR
 			out.Spec.ExternalID = in.ExternalID
 			out.Spec.Unschedulable = in.Unschedulable
+			if err := s.Convert(&in.Taints, &out.Spec.Taints, 0); err != nil {
+				return err
+			}
 			return s.Convert(&in.NodeResources.Capacity, &out.Status.Capacity, 0)
 		},
 
@@ -972,6 +1053,259 @@ func init() {
 			return nil
 		},
 
+		func(in *newer.Job, out *Job, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *Job, out *newer.Job, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.DaemonSet, out *DaemonSet, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *DaemonSet, out *newer.DaemonSet, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.Deployment, out *Deployment, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *Deployment, out *newer.Deployment, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.HorizontalPodAutoscaler, out *HorizontalPodAutoscaler, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *HorizontalPodAutoscaler, out *newer.HorizontalPodAutoscaler, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.PersistentVolume, out *PersistentVolume, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *PersistentVolume, out *newer.PersistentVolume, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.PersistentVolumeClaim, out *PersistentVolumeClaim, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *PersistentVolumeClaim, out *newer.PersistentVolumeClaim, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.ServiceAccount, out *ServiceAccount, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Secrets, &out.Secrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *ServiceAccount, out *newer.ServiceAccount, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Secrets, &out.Secrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
 		// Object ID <-> Name
 		// TODO: amend the conversion package to allow overriding specific fields.
 		func(in *ObjectReference, out *newer.ObjectReference, s conversion.Scope) error {
@@ -1133,6 +1467,9 @@ func init() {
 			if err := s.Convert(&in.NFS, &out.NFS, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.PersistentVolumeClaim, &out.PersistentVolumeClaim, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.ScriptableDisk, &out.ScriptableDisk, 0); err != nil {
 				return err
 			}
@@ -1157,6 +1494,9 @@ func init() {
 			if err := s.Convert(&in.NFS, &out.NFS, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.PersistentVolumeClaim, &out.PersistentVolumeClaim, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.ScriptableDisk, &out.ScriptableDisk, 0); err != nil {
 				return err
 			}
@@ -1235,6 +1575,9 @@ func init() {
 			}
 			out.InitialDelaySeconds = in.InitialDelaySeconds
 			out.TimeoutSeconds = in.TimeoutSeconds
+			out.PeriodSeconds = in.PeriodSeconds
+			out.SuccessThreshold = in.SuccessThreshold
+			out.FailureThreshold = in.FailureThreshold
 			return nil
 		},
 		func(in *LivenessProbe, out *newer.Probe, s conversion.Scope) error {
@@ -1249,6 +1592,9 @@ func init() {
 			}
 			out.InitialDelaySeconds = in.InitialDelaySeconds
 			out.TimeoutSeconds = in.TimeoutSeconds
+			out.PeriodSeconds = in.PeriodSeconds
+			out.SuccessThreshold = in.SuccessThreshold
+			out.FailureThreshold = in.FailureThreshold
 			return nil
 		},
 
@@ -67,6 +67,19 @@ func init() {
 			if obj.SessionAffinity == "" {
 				obj.SessionAffinity = AffinityTypeNone
 			}
+			if obj.Type == "" {
+				obj.Type = ServiceTypeClusterIP
+			}
+		},
+		func(obj *ObjectFieldSelector) {
+			if obj.APIVersion == "" {
+				obj.APIVersion = "v1beta1"
+			}
+		},
+		func(obj *ServicePort) {
+			if obj.Protocol == "" {
+				obj.Protocol = ProtocolTCP
+			}
 		},
 		func(obj *PodSpec) {
 			if obj.DNSPolicy == "" {
@@ -149,6 +162,30 @@ func init() {
 				obj.ExternalID = obj.ID
 			}
 		},
+		func(obj *JobSpec) {
+			if obj.Completions == 0 {
+				obj.Completions = 1
+			}
+			if obj.Parallelism == 0 {
+				obj.Parallelism = 1
+			}
+		},
+		func(obj *DeploymentStrategy) {
+			if obj.Type == "" {
+				obj.Type = RollingUpdateDeploymentStrategyType
+			}
+			if obj.Type == RollingUpdateDeploymentStrategyType && obj.RollingUpdate == nil {
+				obj.RollingUpdate = &RollingUpdateDeployment{
+					MaxUnavailable: util.NewIntOrStringFromInt(1),
+					MaxSurge:       util.NewIntOrStringFromInt(1),
+				}
+			}
+		},
+		func(obj *PersistentVolumeSpec) {
+			if obj.PersistentVolumeReclaimPolicy == "" {
+				obj.PersistentVolumeReclaimPolicy = PersistentVolumeReclaimRetain
+			}
+		},
 	)
 }
 
@@ -363,6 +363,9 @@ func init() {
 			if err := s.Convert(&in.Capabilities, &out.Capabilities, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.SecurityContext, &out.SecurityContext, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		// Internal API does not support CPU to be specified via an explicit field.
@@ -448,6 +451,9 @@ func init() {
 			if err := s.Convert(&in.Capabilities, &out.Capabilities, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.SecurityContext, &out.SecurityContext, 0); err != nil {
+				return err
+			}
 			return nil
 		},
 		func(in *newer.PodSpec, out *ContainerManifest, s conversion.Scope) error {
@@ -463,7 +469,22 @@ func init() {
 			out.DNSPolicy = DNSPolicy(in.DNSPolicy)
 			out.Version = "v1beta2"
 			out.HostNetwork = in.HostNetwork
-			return nil
+			out.ServiceAccount = in.ServiceAccount
+			out.Priority = in.Priority
+			out.SchedulerName = in.SchedulerName
+			if err := s.Convert(&in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ImagePullSecrets, &out.ImagePullSecrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Tolerations, &out.Tolerations, 0); err != nil {
+				return err
+			}
+			return s.Convert(&in.Affinity, &out.Affinity, 0)
 		},
 		func(in *ContainerManifest, out *newer.PodSpec, s conversion.Scope) error {
 			if err := s.Convert(&in.Volumes, &out.Volumes, 0); err != nil {
@@ -477,7 +498,22 @@ func init() {
 			}
 			out.DNSPolicy = newer.DNSPolicy(in.DNSPolicy)
 			out.HostNetwork = in.HostNetwork
-			return nil
+			out.ServiceAccount = in.ServiceAccount
+			out.Priority = in.Priority
+			out.SchedulerName = in.SchedulerName
+			if err := s.Convert(&in.ActiveDeadlineSeconds, &out.ActiveDeadlineSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TerminationGracePeriodSeconds, &out.TerminationGracePeriodSeconds, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ImagePullSecrets, &out.ImagePullSecrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Tolerations, &out.Tolerations, 0); err != nil {
+				return err
+			}
+			return s.Convert(&in.Affinity, &out.Affinity, 0)
 		},
 
 		func(in *newer.PodStatus, out *PodState, s conversion.Scope) error {
@@ -491,10 +527,11 @@ func init() {
 				return err
 			}
 			out.Message = in.Message
+			out.Reason = in.Reason
 			out.Host = in.Host
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
-			return nil
+			return s.Convert(&in.StartTime, &out.StartTime, 0)
 		},
 		func(in *PodState, out *newer.PodStatus, s conversion.Scope) error {
 			if err := s.Convert(&in.Status, &out.Phase, 0); err != nil {
@@ -507,10 +544,11 @@ func init() {
 				return err
 			}
 			out.Message = in.Message
+			out.Reason = in.Reason
 			out.Host = in.Host
 			out.HostIP = in.HostIP
 			out.PodIP = in.PodIP
-			return nil
+			return s.Convert(&in.StartTime, &out.StartTime, 0)
 		},
 
 		func(in *[]newer.ContainerStatus, out *PodInfo, s conversion.Scope) error {
@@ -635,15 +673,24 @@ func init() {
 				return err
 			}
 
-			out.Port = in.Spec.Port
-			out.Protocol = Protocol(in.Spec.Protocol)
+			// Produce legacy fields.
+			if len(in.Spec.Ports) > 0 {
+				out.PortName = in.Spec.Ports[0].Name
+				out.Port = in.Spec.Ports[0].Port
+				out.Protocol = Protocol(in.Spec.Ports[0].Protocol)
+				out.ContainerPort = in.Spec.Ports[0].TargetPort
+			}
+			// Copy modern fields.
+			if err := s.Convert(&in.Spec.Ports, &out.Ports, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.Spec.Selector, &out.Selector, 0); err != nil {
 				return err
 			}
 			out.CreateExternalLoadBalancer = in.Spec.CreateExternalLoadBalancer
 			out.PublicIPs = in.Spec.PublicIPs
-			out.ContainerPort = in.Spec.TargetPort
 			out.PortalIP = in.Spec.PortalIP
+			out.Type = ServiceType(in.Spec.Type)
 			if err := s.Convert(&in.Spec.SessionAffinity, &out.SessionAffinity, 0); err != nil {
 				return err
 			}
@@ -661,21 +708,49 @@ func init() {
 				return err
 			}
 
-			out.Spec.Port = in.Port
-			out.Spec.Protocol = newer.Protocol(in.Protocol)
+			if len(in.Ports) == 0 && in.Port != 0 {
+				// Use legacy fields to produce modern fields.
+				out.Spec.Ports = append(out.Spec.Ports, newer.ServicePort{
+					Name:       in.PortName,
+					Port:       in.Port,
+					Protocol:   newer.Protocol(in.Protocol),
+					TargetPort: in.ContainerPort,
+				})
+			} else {
+				// Use modern fields, ignore legacy.
+				if err := s.Convert(&in.Ports, &out.Spec.Ports, 0); err != nil {
+					return err
+				}
+			}
 			if err := s.Convert(&in.Selector, &out.Spec.Selector, 0); err != nil {
 				return err
 			}
 			out.Spec.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
 			out.Spec.PublicIPs = in.PublicIPs
-			out.Spec.TargetPort = in.ContainerPort
 			out.Spec.PortalIP = in.PortalIP
+			out.Spec.Type = newer.ServiceType(in.Type)
 			if err := s.Convert(&in.SessionAffinity, &out.Spec.SessionAffinity, 0); err != nil {
 				return err
 			}
 
 			return nil
 		},
+		func(in *newer.ServicePort, out *ServicePort, s conversion.Scope) error {
+			out.Name = in.Name
+			out.Protocol = Protocol(in.Protocol)
+			out.Port = in.Port
+			out.ContainerPort = in.TargetPort
+			out.NodePort = in.NodePort
+			return nil
+		},
+		func(in *ServicePort, out *newer.ServicePort, s conversion.Scope) error {
+			out.Name = in.Name
+			out.Protocol = newer.Protocol(in.Protocol)
+			out.Port = in.Port
+			out.TargetPort = in.ContainerPort
+			out.NodePort = in.NodePort
+			return nil
+		},
 
 		func(in *newer.Node, out *Minion, s conversion.Scope) error {
 			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
@@ -708,6 +783,9 @@ func init() {
 			out.PodCIDR = in.Spec.PodCIDR
 			out.ExternalID = in.Spec.ExternalID
 			out.Unschedulable = in.Spec.Unschedulable
+			if err := s.Convert(&in.Spec.Taints, &out.Taints, 0); err != nil {
+				return err
+			}
 			return s.Convert(&in.Status.Capacity, &out.NodeResources.Capacity, 0)
 		},
 		func(in *Minion, out *newer.Node, s conversion.Scope) error {
@@ -740,6 +818,9 @@ func init() {
 			out.Spec.PodCIDR = in.PodCIDR
 			out.Spec.ExternalID = in.ExternalID
 			out.Spec.Unschedulable = in.Unschedulable
+			if err := s.Convert(&in.Taints, &out.Spec.Taints, 0); err != nil {
+				return err
+			}
 			return s.Convert(&in.NodeResources.Capacity, &out.Status.Capacity, 0)
 		},
 
@@ -904,6 +985,259 @@ func init() {
 			return nil
 		},
 
+		func(in *newer.Job, out *Job, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *Job, out *newer.Job, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.DaemonSet, out *DaemonSet, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *DaemonSet, out *newer.DaemonSet, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.Deployment, out *Deployment, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *Deployment, out *newer.Deployment, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.HorizontalPodAutoscaler, out *HorizontalPodAutoscaler, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *HorizontalPodAutoscaler, out *newer.HorizontalPodAutoscaler, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.PersistentVolume, out *PersistentVolume, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *PersistentVolume, out *newer.PersistentVolume, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.PersistentVolumeClaim, out *PersistentVolumeClaim, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *PersistentVolumeClaim, out *newer.PersistentVolumeClaim, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Spec, &out.Spec, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Status, &out.Status, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
+		func(in *newer.ServiceAccount, out *ServiceAccount, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.ObjectMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Secrets, &out.Secrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+		func(in *ServiceAccount, out *newer.ServiceAccount, s conversion.Scope) error {
+			if err := s.Convert(&in.TypeMeta, &out.TypeMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.TypeMeta, &out.ObjectMeta, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Secrets, &out.Secrets, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Labels, &out.ObjectMeta.Labels, 0); err != nil {
+				return err
+			}
+			return nil
+		},
+
 		// Object ID <-> Name
 		// TODO: amend the conversion package to allow overriding specific fields.
 		func(in *ObjectReference, out *newer.ObjectReference, s conversion.Scope) error {
@@ -1061,6 +1395,9 @@ func init() {
 			if err := s.Convert(&in.NFS, &out.NFS, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.PersistentVolumeClaim, &out.PersistentVolumeClaim, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.ScriptableDisk, &out.ScriptableDisk, 0); err != nil {
 				return err
 			}
@@ -1085,6 +1422,9 @@ func init() {
 			if err := s.Convert(&in.NFS, &out.NFS, 0); err != nil {
 				return err
 			}
+			if err := s.Convert(&in.PersistentVolumeClaim, &out.PersistentVolumeClaim, 0); err != nil {
+				return err
+			}
 			if err := s.Convert(&in.ScriptableDisk, &out.ScriptableDisk, 0); err != nil {
 				return err
 			}
@@ -1163,6 +1503,9 @@ func init() {
 			}
 			out.InitialDelaySeconds = in.InitialDelaySeconds
 			out.TimeoutSeconds = in.TimeoutSeconds
+			out.PeriodSeconds = in.PeriodSeconds
+			out.SuccessThreshold = in.SuccessThreshold
+			out.FailureThreshold = in.FailureThreshold
 			return nil
 		},
 		func(in *LivenessProbe, out *newer.Probe, s conversion.Scope) error {
@@ -1177,6 +1520,9 @@ func init() {
 			}
 			out.InitialDelaySeconds = in.InitialDelaySeconds
 			out.TimeoutSeconds = in.TimeoutSeconds
+			out.PeriodSeconds = in.PeriodSeconds
+			out.SuccessThreshold = in.SuccessThreshold
+			out.FailureThreshold = in.FailureThreshold
 			return nil
 		},
 
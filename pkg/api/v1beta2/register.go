@@ -63,6 +63,16 @@ func init() {
 		&PersistentVolumeList{},
 		&PersistentVolumeClaim{},
 		&PersistentVolumeClaimList{},
+		&Job{},
+		&JobList{},
+		&DaemonSet{},
+		&DaemonSetList{},
+		&Deployment{},
+		&DeploymentList{},
+		&ServiceAccount{},
+		&ServiceAccountList{},
+		&HorizontalPodAutoscaler{},
+		&HorizontalPodAutoscalerList{},
 		&DeleteOptions{},
 		&ListOptions{},
 	)
@@ -71,36 +81,46 @@ func init() {
 	api.Scheme.AddKnownTypeWithName("v1beta2", "NodeList", &MinionList{})
 }
 
-func (*Pod) IsAnAPIObject()                       {}
-func (*PodStatusResult) IsAnAPIObject()           {}
-func (*PodList) IsAnAPIObject()                   {}
-func (*ReplicationController) IsAnAPIObject()     {}
-func (*ReplicationControllerList) IsAnAPIObject() {}
-func (*Service) IsAnAPIObject()                   {}
-func (*ServiceList) IsAnAPIObject()               {}
-func (*Endpoints) IsAnAPIObject()                 {}
-func (*EndpointsList) IsAnAPIObject()             {}
-func (*Minion) IsAnAPIObject()                    {}
-func (*NodeInfo) IsAnAPIObject()                  {}
-func (*MinionList) IsAnAPIObject()                {}
-func (*Binding) IsAnAPIObject()                   {}
-func (*Status) IsAnAPIObject()                    {}
-func (*Event) IsAnAPIObject()                     {}
-func (*EventList) IsAnAPIObject()                 {}
-func (*ContainerManifest) IsAnAPIObject()         {}
-func (*ContainerManifestList) IsAnAPIObject()     {}
-func (*List) IsAnAPIObject()                      {}
-func (*LimitRange) IsAnAPIObject()                {}
-func (*LimitRangeList) IsAnAPIObject()            {}
-func (*ResourceQuota) IsAnAPIObject()             {}
-func (*ResourceQuotaList) IsAnAPIObject()         {}
-func (*Namespace) IsAnAPIObject()                 {}
-func (*NamespaceList) IsAnAPIObject()             {}
-func (*Secret) IsAnAPIObject()                    {}
-func (*SecretList) IsAnAPIObject()                {}
-func (*PersistentVolume) IsAnAPIObject()          {}
-func (*PersistentVolumeList) IsAnAPIObject()      {}
-func (*PersistentVolumeClaim) IsAnAPIObject()     {}
-func (*PersistentVolumeClaimList) IsAnAPIObject() {}
-func (*DeleteOptions) IsAnAPIObject()             {}
-func (*ListOptions) IsAnAPIObject()               {}
+func (*Pod) IsAnAPIObject()                         {}
+func (*PodStatusResult) IsAnAPIObject()             {}
+func (*PodList) IsAnAPIObject()                     {}
+func (*ReplicationController) IsAnAPIObject()       {}
+func (*ReplicationControllerList) IsAnAPIObject()   {}
+func (*Service) IsAnAPIObject()                     {}
+func (*ServiceList) IsAnAPIObject()                 {}
+func (*Endpoints) IsAnAPIObject()                   {}
+func (*EndpointsList) IsAnAPIObject()               {}
+func (*Minion) IsAnAPIObject()                      {}
+func (*NodeInfo) IsAnAPIObject()                    {}
+func (*MinionList) IsAnAPIObject()                  {}
+func (*Binding) IsAnAPIObject()                     {}
+func (*Status) IsAnAPIObject()                      {}
+func (*Event) IsAnAPIObject()                       {}
+func (*EventList) IsAnAPIObject()                   {}
+func (*ContainerManifest) IsAnAPIObject()           {}
+func (*ContainerManifestList) IsAnAPIObject()       {}
+func (*List) IsAnAPIObject()                        {}
+func (*LimitRange) IsAnAPIObject()                  {}
+func (*LimitRangeList) IsAnAPIObject()              {}
+func (*ResourceQuota) IsAnAPIObject()               {}
+func (*ResourceQuotaList) IsAnAPIObject()           {}
+func (*Namespace) IsAnAPIObject()                   {}
+func (*NamespaceList) IsAnAPIObject()               {}
+func (*Secret) IsAnAPIObject()                      {}
+func (*SecretList) IsAnAPIObject()                  {}
+func (*PersistentVolume) IsAnAPIObject()            {}
+func (*PersistentVolumeList) IsAnAPIObject()        {}
+func (*PersistentVolumeClaim) IsAnAPIObject()       {}
+func (*PersistentVolumeClaimList) IsAnAPIObject()   {}
+func (*Job) IsAnAPIObject()                         {}
+func (*JobList) IsAnAPIObject()                     {}
+func (*DaemonSet) IsAnAPIObject()                   {}
+func (*DaemonSetList) IsAnAPIObject()               {}
+func (*Deployment) IsAnAPIObject()                  {}
+func (*DeploymentList) IsAnAPIObject()              {}
+func (*ServiceAccount) IsAnAPIObject()              {}
+func (*ServiceAccountList) IsAnAPIObject()          {}
+func (*HorizontalPodAutoscaler) IsAnAPIObject()     {}
+func (*HorizontalPodAutoscalerList) IsAnAPIObject() {}
+func (*DeleteOptions) IsAnAPIObject()               {}
+func (*ListOptions) IsAnAPIObject()                 {}
@@ -80,6 +80,8 @@ type VolumeSource struct {
 	Secret *SecretVolumeSource `json:"secret" description:"secret to populate volume"`
 	// NFS represents an NFS mount on the host that shares a pod's lifetime
 	NFS *NFSVolumeSource `json:"nfs" description:"NFS volume that will be mounted in the host machine"`
+	// PersistentVolumeClaim represents a reference to a PersistentVolumeClaim in the same namespace
+	PersistentVolumeClaim *PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim" description:"a reference to a persistent volume claim in the same namespace"`
 
 	ScriptableDisk *ScriptableDiskVolumeSource `json:"scriptableDisk"`
 }
@@ -98,6 +100,7 @@ type PersistentVolumeSource struct {
 
 type PersistentVolume struct {
 	TypeMeta `json:",inline"`
+	Labels   map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize persistent volumes"`
 
 	//Spec defines a persistent volume owned by the cluster
 	Spec PersistentVolumeSpec `json:"spec,omitempty" description:"specification of a persistent volume as provisioned by an administrator"`
@@ -114,6 +117,9 @@ type PersistentVolumeSpec struct {
 	PersistentVolumeSource `json:",inline" description:"the actual volume backing the persistent volume"`
 	// holds the binding reference to a PersistentVolumeClaim
 	ClaimRef *ObjectReference `json:"claimRef,omitempty" description:"the binding reference to a persistent volume claim"`
+	// PersistentVolumeReclaimPolicy is what happens to a volume when it is released from its claim.
+	// Defaults to Retain.
+	PersistentVolumeReclaimPolicy PersistentVolumeReclaimPolicy `json:"persistentVolumeReclaimPolicy,omitempty" description:"what happens to the volume when released from its claim; one of Retain (default) or Recycle"`
 }
 
 type PersistentVolumeStatus struct {
@@ -129,6 +135,7 @@ type PersistentVolumeList struct {
 // PersistentVolumeClaim is a user's request for and claim to a persistent volume
 type PersistentVolumeClaim struct {
 	TypeMeta `json:",inline"`
+	Labels   map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize persistent volume claims"`
 
 	// Spec defines the volume requested by a pod author
 	Spec PersistentVolumeClaimSpec `json:"spec,omitempty" description: "the desired characteristics of a volume"`
@@ -192,8 +199,33 @@ const (
 	ClaimPending PersistentVolumeClaimPhase = "Pending"
 	// used for PersistentVolumeClaims that are bound
 	ClaimBound PersistentVolumeClaimPhase = "Bound"
+	// used for PersistentVolumeClaims that lost their volume, which was deleted or
+	// bound to another claim; lost claims are not bound again
+	ClaimLost PersistentVolumeClaimPhase = "Lost"
 )
 
+// PersistentVolumeReclaimPolicy describes a policy for end-of-life maintenance of persistent volumes
+type PersistentVolumeReclaimPolicy string
+
+const (
+	// PersistentVolumeReclaimRetain means the volume is left in the Released phase for manual reclamation
+	// by the administrator.  This is the default policy.
+	PersistentVolumeReclaimRetain PersistentVolumeReclaimPolicy = "Retain"
+	// PersistentVolumeReclaimRecycle means the volume is scrubbed of its contents and made Available again
+	// once it is released from its claim.
+	PersistentVolumeReclaimRecycle PersistentVolumeReclaimPolicy = "Recycle"
+)
+
+// PersistentVolumeClaimVolumeSource references the user's PVC in the same namespace.
+// The volume backing the bound claim is mounted into the pod.
+type PersistentVolumeClaimVolumeSource struct {
+	// ClaimName is the name of a PersistentVolumeClaim in the same namespace as the pod using this volume
+	ClaimName string `json:"claimName" description:"the name of the claim in the same namespace to be mounted as a volume"`
+	// Optional: Defaults to false (read/write).  ReadOnly here
+	// will force the ReadOnly setting in VolumeMounts.
+	ReadOnly bool `json:"readOnly,omitempty" description:"mount volume as read-only when true; default false"`
+}
+
 // HostPathVolumeSource represents bare host directory volume.
 //
 // https://github.com/GoogleCloudPlatform/kubernetes/blob/master/docs/volumes.md#hostdir
@@ -302,8 +334,36 @@ type VolumeMount struct {
 type EnvVar struct {
 	// Required: This must be a C_IDENTIFIER.
 	Name string `json:"name" description:"name of the environment variable; must be a C_IDENTIFIER"`
+	// Optional: no more than one of the following may be set.
 	// Optional: defaults to "".
 	Value string `json:"value,omitempty" description:"value of the environment variable; defaults to empty string"`
+	// Optional: specifies a source the value of this var should come from.
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty" description:"source for the environment variable's value; cannot be used if value is not empty"`
+}
+
+// EnvVarSource represents a source for the value of an EnvVar.
+// Only one of its members may be specified.
+type EnvVarSource struct {
+	// Selects a field of the pod; only name, namespace and the pod IP are supported.
+	FieldRef *ObjectFieldSelector `json:"fieldRef,omitempty" description:"selects a field of the pod; only name, namespace and the pod IP are supported"`
+	// Selects a key of a secret in the pod's namespace.
+	SecretKeyRef *SecretKeySelector `json:"secretKeyRef,omitempty" description:"selects a key of a secret in the pod's namespace"`
+}
+
+// ObjectFieldSelector selects an APIVersioned field of an object.
+type ObjectFieldSelector struct {
+	// Optional: Version of the schema the FieldPath is written in terms of, defaults to v1beta2
+	APIVersion string `json:"apiVersion,omitempty" description:"version of the schema that fieldPath is written in terms of; defaults to v1beta2"`
+	// Required: Path of the field to select in the specified API version
+	FieldPath string `json:"fieldPath" description:"path of the field to select in the specified API version"`
+}
+
+// SecretKeySelector selects a key of a Secret.
+type SecretKeySelector struct {
+	// Required: The name of the secret in the pod's namespace to select from.
+	Name string `json:"name" description:"name of the secret in the pod's namespace to select from"`
+	// Required: The key of the secret to select from.  Must be a valid secret key.
+	Key string `json:"key" description:"the key of the secret to select from; must be a valid secret key"`
 }
 
 // HTTPGetAction describes an action based on HTTP Get requests.
@@ -350,6 +410,12 @@ type LivenessProbe struct {
 	InitialDelaySeconds int64 `json:"initialDelaySeconds,omitempty" description:"number of seconds after the container has started before liveness probes are initiated"`
 	// Length of time before health checking times out.  In seconds.
 	TimeoutSeconds int64 `json:"timeoutSeconds,omitempty" description:"number of seconds after which liveness probes timeout; defaults to 1 second"`
+	// How often to perform the probe.  In seconds.
+	PeriodSeconds int64 `json:"periodSeconds,omitempty" description:"how often (in seconds) to perform the probe; defaults to every sync of the pod"`
+	// Minimum consecutive successes for the probe to be considered successful after having failed.
+	SuccessThreshold int `json:"successThreshold,omitempty" description:"minimum consecutive successes for the probe to be considered successful after having failed; defaults to 1; must be 1 for liveness"`
+	// Minimum consecutive failures for the probe to be considered failed after having succeeded.
+	FailureThreshold int `json:"failureThreshold,omitempty" description:"minimum consecutive failures for the probe to be considered failed after having succeeded; defaults to 1"`
 }
 
 // PullPolicy describes a policy for if/when to pull a container image
@@ -379,6 +445,21 @@ type Capabilities struct {
 	Drop []CapabilityType `json:"drop,omitempty" description:"droped capabilities"`
 }
 
+// SecurityContext holds security configuration that will be applied to a container.
+type SecurityContext struct {
+	RunAsUser              *int64          `json:"runAsUser,omitempty" description:"the user id that runs the entrypoint of the container process; defaults to the user specified in image metadata"`
+	SELinuxOptions         *SELinuxOptions `json:"seLinuxOptions,omitempty" description:"the SELinux labels to apply to the container; defaults to labels allocated by the container runtime"`
+	ReadOnlyRootFilesystem bool            `json:"readOnlyRootFilesystem,omitempty" description:"whether the container's root filesystem is mounted read-only; defaults to false"`
+}
+
+// SELinuxOptions are the labels to be applied to the container.
+type SELinuxOptions struct {
+	User  string `json:"user,omitempty" description:"the SELinux user label that applies to the container"`
+	Role  string `json:"role,omitempty" description:"the SELinux role label that applies to the container"`
+	Type  string `json:"type,omitempty" description:"the SELinux type label that applies to the container"`
+	Level string `json:"level,omitempty" description:"the SELinux level label that applies to the container"`
+}
+
 type ResourceRequirements struct {
 	// Limits describes the maximum amount of compute resources required.
 	Limits ResourceList `json:"limits,omitempty" description:"Maximum amount of compute resources allowed"`
@@ -418,6 +499,8 @@ type Container struct {
 	ImagePullPolicy PullPolicy `json:"imagePullPolicy" description:"image pull policy; one of PullAlways, PullNever, PullIfNotPresent; defaults to PullAlways if :latest tag is specified, or PullIfNotPresent otherwise; cannot be updated"`
 	// Optional: Capabilities for container.
 	Capabilities Capabilities `json:"capabilities,omitempty" description:"capabilities for container; cannot be updated"`
+	// Optional: SecurityContext defines the security options the container should be run with.
+	SecurityContext *SecurityContext `json:"securityContext,omitempty" description:"security options the container should be run with; cannot be updated"`
 }
 
 const (
@@ -622,10 +705,16 @@ type PodState struct {
 	Conditions []PodCondition    `json:"Condition,omitempty" description:"current service state of pod"`
 	// A human readable message indicating details about why the pod is in this state.
 	Message string `json:"message,omitempty" description:"human readable message indicating details about why the pod is in this condition"`
+	// A brief CamelCase message indicating details about why the pod is in this state. e.g. 'DeadlineExceeded'
+	Reason string `json:"reason,omitempty" description:"(brief-CamelCase) reason indicating details about why the pod is in this condition"`
 	Host    string `json:"host,omitempty" description:"host to which the pod is assigned; empty if not yet scheduled; cannot be updated"`
 	HostIP  string `json:"hostIP,omitempty" description:"IP address of the host to which the pod is assigned; empty if not yet scheduled"`
 	PodIP   string `json:"podIP,omitempty" description:"IP address allocated to the pod; routable at least within the cluster; empty if not yet allocated"`
 
+	// Date and time at which the pod was acknowledged by the Kubelet.
+	// This is before the Kubelet pulled the container image(s) for the pod.
+	StartTime *util.Time `json:"startTime,omitempty" description:"RFC 3339 date and time at which the pod was acknowledged by the Kubelet; this is before the Kubelet pulled the container image(s) for the pod"`
+
 	// The key of this map is the *name* of the container within the manifest; it has one
 	// entry per container in the manifest. The value of this map is ContainerStatus for
 	// the container.
@@ -688,6 +777,175 @@ type PodTemplate struct {
 	Annotations  map[string]string `json:"annotations,omitempty" description:"map of string keys and values that can be used by external tooling to store and retrieve arbitrary metadata about pods created from the template"`
 }
 
+// JobSpec describes how the job execution will look like.
+type JobSpec struct {
+	Completions int               `json:"completions,omitempty" description:"number of successfully finished pods the job should be run with; defaults to 1"`
+	Parallelism int               `json:"parallelism,omitempty" description:"maximum number of pods the job should run at any given time; defaults to 1"`
+	Selector    map[string]string `json:"selector,omitempty" description:"label keys and values that must match in order to be controlled by this job"`
+	Template    *PodTemplate      `json:"template,omitempty" description:"template for pods to be created when executing the job"`
+}
+
+// JobStatus represents the current state of a Job.
+type JobStatus struct {
+	StartTime      *util.Time `json:"startTime,omitempty" description:"time when the job was acknowledged by the job controller"`
+	CompletionTime *util.Time `json:"completionTime,omitempty" description:"time when the job was completed"`
+	Active         int        `json:"active,omitempty" description:"number of actively running pods"`
+	Succeeded      int        `json:"succeeded,omitempty" description:"number of pods which reached phase Succeeded"`
+	Failed         int        `json:"failed,omitempty" description:"number of pods which reached phase Failed"`
+}
+
+// Job represents the configuration of a single job, which runs pods until
+// a desired number of them have completed successfully.
+type Job struct {
+	TypeMeta `json:",inline"`
+	Labels   map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize jobs"`
+	Spec     JobSpec           `json:"spec,omitempty" description:"specification of the desired behavior of the job"`
+	Status   JobStatus         `json:"status,omitempty" description:"most recently observed status of the job; populated by the system, read-only"`
+}
+
+// JobList is a collection of jobs.
+type JobList struct {
+	TypeMeta `json:",inline"`
+	Items    []Job `json:"items" description:"list of jobs"`
+}
+
+// DaemonSetSpec is the specification of a daemon set.
+type DaemonSetSpec struct {
+	Selector     map[string]string `json:"selector,omitempty" description:"label keys and values that must match in order to be controlled by this daemon set"`
+	NodeSelector map[string]string `json:"nodeSelector,omitempty" description:"label keys and values that a node must match in order to run a pod of this daemon set; empty matches every node"`
+	Template     *PodTemplate      `json:"template,omitempty" description:"template for the pod that will be created on every node matching the node selector"`
+}
+
+// DaemonSetStatus represents the current status of a daemon set.
+type DaemonSetStatus struct {
+	CurrentNumberScheduled int `json:"currentNumberScheduled" description:"number of nodes that are running a pod of the daemon set and are supposed to run it"`
+	NumberMisscheduled     int `json:"numberMisscheduled" description:"number of nodes that are running a pod of the daemon set but are not supposed to run it"`
+	DesiredNumberScheduled int `json:"desiredNumberScheduled" description:"number of nodes that should be running a pod of the daemon set"`
+}
+
+// DaemonSet represents the configuration of a daemon set, which runs a copy
+// of a pod on every node matching its node selector.
+type DaemonSet struct {
+	TypeMeta `json:",inline"`
+	Labels   map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize daemon sets"`
+	Spec     DaemonSetSpec     `json:"spec,omitempty" description:"specification of the desired behavior of the daemon set"`
+	Status   DaemonSetStatus   `json:"status,omitempty" description:"most recently observed status of the daemon set; populated by the system, read-only"`
+}
+
+// DaemonSetList is a collection of daemon sets.
+type DaemonSetList struct {
+	TypeMeta `json:",inline"`
+	Items    []DaemonSet `json:"items" description:"list of daemon sets"`
+}
+
+// DeploymentStrategyType is the kind of strategy used to replace the pods of
+// a deployment when its template changes.
+type DeploymentStrategyType string
+
+const (
+	// RecreateDeploymentStrategyType kills all existing pods before creating new ones.
+	RecreateDeploymentStrategyType DeploymentStrategyType = "Recreate"
+
+	// RollingUpdateDeploymentStrategyType gradually replaces the old pods by new ones.
+	RollingUpdateDeploymentStrategyType DeploymentStrategyType = "RollingUpdate"
+)
+
+// DeploymentStrategy describes how to replace existing pods with new ones.
+type DeploymentStrategy struct {
+	Type          DeploymentStrategyType   `json:"type,omitempty" description:"type of deployment; Recreate or RollingUpdate; defaults to RollingUpdate"`
+	RollingUpdate *RollingUpdateDeployment `json:"rollingUpdate,omitempty" description:"parameters of the rolling update; only allowed when type is RollingUpdate"`
+}
+
+// RollingUpdateDeployment controls the pace of a rolling update.
+type RollingUpdateDeployment struct {
+	MaxUnavailable util.IntOrString `json:"maxUnavailable,omitempty" description:"maximum number or percentage of desired pods that can be unavailable during the update; defaults to 1"`
+	MaxSurge       util.IntOrString `json:"maxSurge,omitempty" description:"maximum number or percentage of pods that can be created above the desired replicas during the update; defaults to 1"`
+}
+
+// RollbackConfig asks for the template of a deployment to be reset to the
+// one of an earlier revision.
+type RollbackConfig struct {
+	Revision int64 `json:"revision,omitempty" description:"revision to roll back to; 0 means the revision before the current one"`
+}
+
+// DeploymentSpec is the specification of a deployment.
+type DeploymentSpec struct {
+	Replicas   int                `json:"replicas,omitempty" description:"number of desired pods"`
+	Selector   map[string]string  `json:"selector,omitempty" description:"label keys and values that must match in order to be controlled by this deployment"`
+	Template   *PodTemplate       `json:"template,omitempty" description:"template for the pods that will be created; every change is rolled out as a new revision"`
+	Strategy   DeploymentStrategy `json:"strategy,omitempty" description:"strategy used to replace the pods of an older revision"`
+	Paused     bool               `json:"paused,omitempty" description:"stops the rollout of the deployment until set back to false"`
+	RollbackTo *RollbackConfig    `json:"rollbackTo,omitempty" description:"requests a rollback to an earlier revision; cleared by the system once the template has been reset"`
+}
+
+// DeploymentStatus represents the current status of a deployment.
+type DeploymentStatus struct {
+	Replicas          int   `json:"replicas,omitempty" description:"number of pods targeted by the deployment across all revisions"`
+	UpdatedReplicas   int   `json:"updatedReplicas,omitempty" description:"number of pods running the current template"`
+	AvailableReplicas int   `json:"availableReplicas,omitempty" description:"number of ready pods targeted by the deployment"`
+	Revision          int64 `json:"revision,omitempty" description:"revision of the current template"`
+}
+
+// Deployment represents the configuration of a deployment, which rolls out
+// changes of a pod template by managing replication controllers.
+type Deployment struct {
+	TypeMeta `json:",inline"`
+	Labels   map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize deployments"`
+	Spec     DeploymentSpec    `json:"spec,omitempty" description:"specification of the desired behavior of the deployment"`
+	Status   DeploymentStatus  `json:"status,omitempty" description:"most recently observed status of the deployment; populated by the system, read-only"`
+}
+
+// DeploymentList is a collection of deployments.
+type DeploymentList struct {
+	TypeMeta `json:",inline"`
+	Items    []Deployment `json:"items" description:"list of deployments"`
+}
+
+// HorizontalPodAutoscalerSpec is the specification of a horizontal pod autoscaler.
+type HorizontalPodAutoscalerSpec struct {
+	ScaleRef                       LocalObjectReference `json:"scaleRef" description:"reference to the replication controller, in the same namespace, whose number of replicas is adjusted"`
+	MinReplicas                    int                  `json:"minReplicas" description:"lower limit for the number of replicas"`
+	MaxReplicas                    int                  `json:"maxReplicas" description:"upper limit for the number of replicas; cannot be lower than minReplicas"`
+	TargetCPUUtilizationPercentage int                  `json:"targetCPUUtilizationPercentage" description:"target average CPU utilization of the pods, as a percentage of the CPU they request"`
+}
+
+// HorizontalPodAutoscalerStatus represents the current status of a horizontal
+// pod autoscaler.
+type HorizontalPodAutoscalerStatus struct {
+	CurrentReplicas                 int        `json:"currentReplicas" description:"number of replicas last observed by the autoscaler"`
+	DesiredReplicas                 int        `json:"desiredReplicas" description:"number of replicas last computed by the autoscaler"`
+	CurrentCPUUtilizationPercentage *int       `json:"currentCPUUtilizationPercentage,omitempty" description:"average CPU utilization of the pods last observed, as a percentage of the CPU they request; unset if it could not be measured"`
+	LastScaleTime                   *util.Time `json:"lastScaleTime,omitempty" description:"last time the autoscaler changed the number of replicas"`
+}
+
+// HorizontalPodAutoscaler adjusts the number of replicas of a replication
+// controller to keep the CPU utilization of its pods close to a target.
+type HorizontalPodAutoscaler struct {
+	TypeMeta `json:",inline"`
+	Labels   map[string]string             `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize horizontal pod autoscalers"`
+	Spec     HorizontalPodAutoscalerSpec   `json:"spec,omitempty" description:"specification of the desired behavior of the autoscaler"`
+	Status   HorizontalPodAutoscalerStatus `json:"status,omitempty" description:"most recently observed status of the autoscaler; populated by the system, read-only"`
+}
+
+// HorizontalPodAutoscalerList is a collection of horizontal pod autoscalers.
+type HorizontalPodAutoscalerList struct {
+	TypeMeta `json:",inline"`
+	Items    []HorizontalPodAutoscaler `json:"items" description:"list of horizontal pod autoscalers"`
+}
+
+// Service Type string describes ingress methods for a service
+type ServiceType string
+
+const (
+	// ServiceTypeClusterIP means a service will only be accessible inside the
+	// cluster, via the portal IP.
+	ServiceTypeClusterIP ServiceType = "ClusterIP"
+
+	// ServiceTypeNodePort means a service will be exposed on one port of
+	// every node, in addition to 'ClusterIP' type.
+	ServiceTypeNodePort ServiceType = "NodePort"
+)
+
 // Session Affinity Type string
 type AffinityType string
 
@@ -719,8 +977,16 @@ type ServiceList struct {
 type Service struct {
 	TypeMeta `json:",inline"`
 
-	// Required.
-	Port int `json:"port" description:"port exposed by the service"`
+	// These fields are retained for backwards compatibility.  For
+	// multi-port services, use the Ports field instead.  Upon a create or
+	// update operation, the following logic applies:
+	//   * If Ports is specified, Port, PortName, Protocol, and
+	//     ContainerPort will be overwritten by the first member of Ports.
+	//   * If Ports is not specified, Port, PortName, Protocol, and
+	//     ContainerPort will be used to generate Ports.
+	Port int `json:"port" description:"port exposed by the service; retained for backwards compatibility, use ports for multi-port services"`
+	// Optional: The name of the first port.
+	PortName string `json:"portName,omitempty" description:"name of the first port; optional"`
 	// Optional: Defaults to "TCP".
 	Protocol Protocol `json:"protocol,omitempty" description:"protocol for port; must be UDP or TCP; TCP if unspecified"`
 
@@ -748,11 +1014,43 @@ type Service struct {
 	// None can be specified for headless services when proxying is not required
 	PortalIP string `json:"portalIP,omitempty" description:"IP address of the service; usually assigned by the system; if specified, it will be allocated to the service if unused, and creation of the service will fail otherwise; cannot be updated; 'None' can be specified for a headless service when proxying is not required"`
 
+	// Optional: Type determines how the service will be exposed.  Valid options: ClusterIP, NodePort
+	Type ServiceType `json:"type,omitempty" description:"type of this service; must be ClusterIP or NodePort; defaults to ClusterIP"`
+
 	// DEPRECATED: has no implementation.
 	ProxyPort int `json:"proxyPort,omitempty" description:"if non-zero, a pre-allocated host port used for this service by the proxy on each node; assigned by the master and ignored on input"`
 
 	// Optional: Supports "ClientIP" and "None".  Used to maintain session affinity.
 	SessionAffinity AffinityType `json:"sessionAffinity,omitempty" description:"enable client IP based session affinity; must be ClientIP or None; defaults to None"`
+
+	// The ports exposed by this service.  See the backwards compatibility
+	// notes above.
+	Ports []ServicePort `json:"ports,omitempty" description:"ports to be exposed on the service; if specified, the legacy port fields are overwritten by the first member"`
+}
+
+// ServicePort describes a single port exposed by a service.
+type ServicePort struct {
+	// Optional if only one ServicePort is defined on this service: The
+	// name of this port within the service.  This must be a DNS_LABEL.
+	// All ports within a Service must have unique names.  This maps to
+	// the 'Name' field in EndpointPort objects.
+	Name string `json:"name,omitempty" description:"the name of this port; optional if only one port is defined"`
+
+	// Optional: Supports "TCP" and "UDP".  Defaults to "TCP".
+	Protocol Protocol `json:"protocol,omitempty" description:"the protocol used by this port; must be UDP or TCP; TCP if unspecified"`
+
+	// Required: The port that will be exposed on the service.
+	Port int `json:"port" description:"the port number that is exposed"`
+
+	// Optional: The name or number of the port on the container to direct
+	// traffic to.  If unspecified, the first port on the container will be
+	// used.
+	ContainerPort util.IntOrString `json:"containerPort,omitempty" description:"the port to access on the containers belonging to pods targeted by the service; defaults to the container's first open port"`
+
+	// Optional: The port on each node on which this service is exposed.
+	// Only valid for services of type NodePort.  If unspecified, a port is
+	// allocated by the system.
+	NodePort int `json:"nodePort,omitempty" description:"the port on each node on which this service is exposed when type=NodePort; usually assigned by the system; if specified, it will be allocated to the service if unused, and creation of the service will fail otherwise"`
 }
 
 // EndpointObjectReference is a reference to an object exposing the endpoint
@@ -971,6 +1269,43 @@ type Minion struct {
 	Labels map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize minions; labels of a minion assigned by the scheduler must match the scheduled pod's nodeSelector"`
 	// External ID of the node
 	ExternalID string `json:"externalID,omitempty" description:"external id of the node assigned by some machine database (e.g. a cloud provider). Defaults to node name when empty."`
+	// Taints keep the pods which do not tolerate them away from the node.
+	Taints []Taint `json:"taints,omitempty" description:"taints of the node, repelling the pods which do not tolerate them"`
+}
+
+// TaintEffect is the effect of a taint on the pods which do not tolerate it.
+type TaintEffect string
+
+const (
+	// TaintEffectNoSchedule prevents the pods which do not tolerate the taint
+	// from being scheduled onto the node. Pods already running on the node
+	// are not affected.
+	TaintEffectNoSchedule TaintEffect = "NoSchedule"
+)
+
+// Taint is attached to a node to repel the pods which do not tolerate it.
+type Taint struct {
+	Key    string      `json:"key" description:"key of the taint; must be a qualified name"`
+	Value  string      `json:"value,omitempty" description:"value of the taint; must be a label value"`
+	Effect TaintEffect `json:"effect" description:"effect of the taint on the pods which do not tolerate it; only NoSchedule is supported"`
+}
+
+// TolerationOperator is the way a toleration matches the value of a taint.
+type TolerationOperator string
+
+const (
+	// TolerationOpEqual matches a taint with the same key and value.
+	TolerationOpEqual TolerationOperator = "Equal"
+	// TolerationOpExists matches a taint with the same key, whatever its value.
+	TolerationOpExists TolerationOperator = "Exists"
+)
+
+// Toleration allows a pod to be scheduled onto the nodes with a matching taint.
+type Toleration struct {
+	Key      string             `json:"key" description:"key of the taints the toleration applies to"`
+	Operator TolerationOperator `json:"operator,omitempty" description:"way the value of the taint is matched; one of Equal or Exists; defaults to Equal"`
+	Value    string             `json:"value,omitempty" description:"value the taint must have when the operator is Equal; must be empty when the operator is Exists"`
+	Effect   TaintEffect        `json:"effect,omitempty" description:"effect of the taints the toleration applies to; empty matches all effects"`
 }
 
 // MinionList is a list of minions.
@@ -1238,6 +1573,11 @@ type ObjectReference struct {
 	FieldPath string `json:"fieldPath,omitempty" description:"if referring to a piece of an object instead of an entire object, this string should contain a valid JSON/Go field access statement, such as desiredState.manifest.containers[2]"`
 }
 
+// LocalObjectReference contains enough information to let you locate the referenced object inside the same namespace.
+type LocalObjectReference struct {
+	Name string `json:"name,omitempty" description:"name of the referent"`
+}
+
 // Event is a report of an event somewhere in the cluster.
 // TODO: Decide whether to store these separately or with the object they apply to.
 //
@@ -1313,12 +1653,24 @@ type ContainerManifest struct {
 	Volumes       []Volume      `json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
 	Containers    []Container   `json:"containers" description:"list of containers belonging to the pod; cannot be updated; containers cannot currently be added or removed"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
+	// Optional duration in seconds the pod may be active on the node relative to
+	// StartTime before the system will actively try to mark it failed and kill associated containers.
+	// Value must be a positive integer.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" description:"optional duration in seconds the pod may be active on the node relative to StartTime before the system will actively try to mark it failed and kill associated containers; value must be a positive integer"`
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; may be decreased in delete request; value must be non-negative integer; the value zero indicates delete immediately; if this value is not set, the default grace period will be used instead; the grace period is the duration in seconds after the processes running in the pod are sent a termination signal and the time when the processes are forcibly halted with a kill signal"`
 	// Optional: Set DNS policy.  Defaults to "ClusterFirst"
 	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty" description:"DNS policy for containers within the pod; one of 'ClusterFirst' or 'Default'"`
 	// Uses the host's network namespace. If this option is set, the ports that will be
 	// used must be specified.
 	// Optional: Default to false.
 	HostNetwork bool `json:"hostNetwork,omitempty" description:"host networking requested for this pod"`
+	// ServiceAccount is the name of the ServiceAccount to use to run this pod.
+	ServiceAccount   string                 `json:"serviceAccount,omitempty" description:"name of the ServiceAccount to use to run this pod"`
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" description:"list of references to secrets in the same namespace available for pulling the container images"`
+	Tolerations      []Toleration           `json:"tolerations,omitempty" description:"tolerations of the pod, allowing it to be scheduled onto nodes with matching taints"`
+	Affinity         *Affinity              `json:"affinity,omitempty" description:"affinity scheduling rules of the pod"`
+	Priority         int                    `json:"priority,omitempty" description:"importance of the pod relative to the other pods; when no node fits the pod, the scheduler may evict pods of lower priority to make room for it"`
+	SchedulerName    string                 `json:"schedulerName,omitempty" description:"name of the scheduler which places the pod; the default scheduler if not specified"`
 }
 
 // ContainerManifestList is used to communicate container manifests to kubelet.
@@ -1344,12 +1696,99 @@ const (
 	DNSDefault DNSPolicy = "Default"
 )
 
+// Affinity is a group of affinity scheduling rules of a pod.
+type Affinity struct {
+	NodeAffinity    *NodeAffinity    `json:"nodeAffinity,omitempty" description:"node affinity scheduling rules of the pod"`
+	PodAffinity     *PodAffinity     `json:"podAffinity,omitempty" description:"inter-pod affinity scheduling rules of the pod"`
+	PodAntiAffinity *PodAntiAffinity `json:"podAntiAffinity,omitempty" description:"inter-pod anti-affinity scheduling rules of the pod"`
+}
+
+// NodeAffinity is a group of node affinity scheduling rules. The rules are
+// only taken into account when the pod is scheduled.
+type NodeAffinity struct {
+	Required  *NodeSelector             `json:"required,omitempty" description:"node selector the node must match for the pod to be scheduled onto it"`
+	Preferred []PreferredSchedulingTerm `json:"preferred,omitempty" description:"weighted node selector terms; the scheduler favors the nodes with the largest sum of the weights of the terms they match"`
+}
+
+// NodeSelector is a set of node selector terms, of which a node must match
+// at least one.
+type NodeSelector struct {
+	NodeSelectorTerms []NodeSelectorTerm `json:"nodeSelectorTerms" description:"list of node selector terms; the terms are ORed"`
+}
+
+// NodeSelectorTerm is a set of requirements on the labels of a node, all of
+// which the node must match.
+type NodeSelectorTerm struct {
+	MatchExpressions []NodeSelectorRequirement `json:"matchExpressions" description:"list of requirements on the labels of the node; the requirements are ANDed"`
+}
+
+// NodeSelectorOperator is the way a requirement matches the value of a label.
+type NodeSelectorOperator string
+
+const (
+	// NodeSelectorOpIn matches a label whose value is in the values.
+	NodeSelectorOpIn NodeSelectorOperator = "In"
+	// NodeSelectorOpNotIn matches the absence of the label or a value which
+	// is not in the values.
+	NodeSelectorOpNotIn NodeSelectorOperator = "NotIn"
+	// NodeSelectorOpExists matches the presence of the label, whatever its value.
+	NodeSelectorOpExists NodeSelectorOperator = "Exists"
+)
+
+// NodeSelectorRequirement is a requirement on a label of a node.
+type NodeSelectorRequirement struct {
+	Key      string               `json:"key" description:"key of the label"`
+	Operator NodeSelectorOperator `json:"operator" description:"the way the value of the label is matched; one of In, NotIn or Exists"`
+	Values   []string             `json:"values,omitempty" description:"values the label is matched against; must be non-empty for In and NotIn, and empty for Exists"`
+}
+
+// PreferredSchedulingTerm is a node selector term with a weight.
+type PreferredSchedulingTerm struct {
+	Weight     int              `json:"weight" description:"weight of the term, in the range 1-100"`
+	Preference NodeSelectorTerm `json:"preference" description:"node selector term the node is matched against"`
+}
+
+// PodAffinity is a group of inter-pod affinity scheduling rules, describing
+// the pods the pod should be co-located with. The rules are only taken into
+// account when the pod is scheduled.
+type PodAffinity struct {
+	Required  []PodAffinityTerm         `json:"required,omitempty" description:"terms the node must satisfy for the pod to be scheduled onto it; for each term, a pod matched by the term must run in the topology domain of the node"`
+	Preferred []WeightedPodAffinityTerm `json:"preferred,omitempty" description:"weighted terms the scheduler favors the nodes satisfying, without requiring them"`
+}
+
+// PodAntiAffinity is a group of inter-pod anti-affinity scheduling rules,
+// describing the pods the pod should not be co-located with. The rules are
+// only taken into account when the pod is scheduled.
+type PodAntiAffinity struct {
+	Required  []PodAffinityTerm         `json:"required,omitempty" description:"terms the node must satisfy for the pod to be scheduled onto it; for each term, no pod matched by the term may run in the topology domain of the node"`
+	Preferred []WeightedPodAffinityTerm `json:"preferred,omitempty" description:"weighted terms the scheduler favors the nodes satisfying, without requiring them"`
+}
+
+// PodAffinityTerm selects a set of pods and the topology domains, such as the
+// nodes or the zones, the pods are considered co-located in.
+type PodAffinityTerm struct {
+	LabelSelector map[string]string `json:"labelSelector" description:"label selector of the pods the term applies to"`
+	Namespaces    []string          `json:"namespaces,omitempty" description:"namespaces of the pods the term applies to; empty means the namespace of the pod holding the term"`
+	TopologyKey   string            `json:"topologyKey" description:"node label defining the topology domains; two pods are co-located when their nodes have the same value for it"`
+}
+
+// WeightedPodAffinityTerm is a pod affinity term with a weight.
+type WeightedPodAffinityTerm struct {
+	Weight          int             `json:"weight" description:"weight of the term, in the range 1-100"`
+	PodAffinityTerm PodAffinityTerm `json:"podAffinityTerm" description:"pod affinity term the node is matched against"`
+}
+
 // PodSpec is a description of a pod
 type PodSpec struct {
 	Volumes []Volume `json:"volumes" description:"list of volumes that can be mounted by containers belonging to the pod"`
 	// Required: there must be at least one container in a pod.
 	Containers    []Container   `json:"containers" description:"list of containers belonging to the pod; containers cannot currently be added or removed; there must be at least one container in a Pod"`
 	RestartPolicy RestartPolicy `json:"restartPolicy,omitempty" description:"restart policy for all containers within the pod; one of RestartPolicyAlways, RestartPolicyOnFailure, RestartPolicyNever"`
+	// Optional duration in seconds the pod may be active on the node relative to
+	// StartTime before the system will actively try to mark it failed and kill associated containers.
+	// Value must be a positive integer.
+	ActiveDeadlineSeconds *int64 `json:"activeDeadlineSeconds,omitempty" description:"optional duration in seconds the pod may be active on the node relative to StartTime before the system will actively try to mark it failed and kill associated containers; value must be a positive integer"`
+	TerminationGracePeriodSeconds *int64 `json:"terminationGracePeriodSeconds,omitempty" description:"optional duration in seconds the pod needs to terminate gracefully; may be decreased in delete request; value must be non-negative integer; the value zero indicates delete immediately; if this value is not set, the default grace period will be used instead; the grace period is the duration in seconds after the processes running in the pod are sent a termination signal and the time when the processes are forcibly halted with a kill signal"`
 	// Optional: Set DNS policy.  Defaults to "ClusterFirst"
 	DNSPolicy DNSPolicy `json:"dnsPolicy,omitempty" description:"DNS policy for containers within the pod; one of 'ClusterFirst' or 'Default'"`
 	// NodeSelector is a selector which must be true for the pod to fit on a node
@@ -1363,6 +1802,13 @@ type PodSpec struct {
 	// used must be specified.
 	// Optional: Default to false.
 	HostNetwork bool `json:"hostNetwork,omitempty" description:"host networking requested for this pod"`
+	// ServiceAccount is the name of the ServiceAccount to use to run this pod.
+	ServiceAccount   string                 `json:"serviceAccount,omitempty" description:"name of the ServiceAccount to use to run this pod"`
+	ImagePullSecrets []LocalObjectReference `json:"imagePullSecrets,omitempty" description:"list of references to secrets in the same namespace available for pulling the container images"`
+	Tolerations      []Toleration           `json:"tolerations,omitempty" description:"tolerations of the pod, allowing it to be scheduled onto nodes with matching taints"`
+	Affinity         *Affinity              `json:"affinity,omitempty" description:"affinity scheduling rules of the pod"`
+	Priority         int                    `json:"priority,omitempty" description:"importance of the pod relative to the other pods; when no node fits the pod, the scheduler may evict pods of lower priority to make room for it"`
+	SchedulerName    string                 `json:"schedulerName,omitempty" description:"name of the scheduler which places the pod; the default scheduler if not specified"`
 }
 
 // List holds a list of objects, which may not be known by the server.
@@ -1476,6 +1922,22 @@ type ScriptableDiskVolumeSource struct {
 	Params string `json:"params"`
 }
 
+// ServiceAccount binds together a name understood by users, a principal that can be
+// authenticated and authorized, and the set of secrets pods running as it may use.
+type ServiceAccount struct {
+	TypeMeta `json:",inline"`
+	Labels   map[string]string `json:"labels,omitempty" description:"map of string keys and values that can be used to organize and categorize service accounts"`
+
+	// Secrets is the list of secrets allowed to be used by pods running using this ServiceAccount
+	Secrets []ObjectReference `json:"secrets" description:"list of secrets that can be used by pods running as this service account"`
+}
+
+// ServiceAccountList is a list of ServiceAccount objects
+type ServiceAccountList struct {
+	TypeMeta `json:",inline"`
+	Items    []ServiceAccount `json:"items" description:"list of service accounts"`
+}
+
 // Secret holds secret data of a certain type.  The total bytes of the values in
 // the Data field must be less than MaxSecretSize bytes.
 //
@@ -1498,6 +1960,30 @@ type SecretType string
 
 const (
 	SecretTypeOpaque SecretType = "Opaque" // Default; arbitrary user-defined data
+
+	// SecretTypeServiceAccountToken contains a token that identifies a service account to the API
+	//
+	// Required fields:
+	// - Secret.Annotations["kubernetes.io/service-account.name"] - the name of the ServiceAccount the token identifies
+	// - Secret.Annotations["kubernetes.io/service-account.uid"] - the UID of the ServiceAccount the token identifies
+	// - Secret.Data["token"] - a token that identifies the service account to the API
+	SecretTypeServiceAccountToken SecretType = "kubernetes.io/service-account-token"
+
+	// ServiceAccountNameKey is the key of the required annotation for SecretTypeServiceAccountToken secrets
+	ServiceAccountNameKey = "kubernetes.io/service-account.name"
+	// ServiceAccountUIDKey is the key of the required annotation for SecretTypeServiceAccountToken secrets
+	ServiceAccountUIDKey = "kubernetes.io/service-account.uid"
+	// ServiceAccountTokenKey is the key of the required data for SecretTypeServiceAccountToken secrets
+	ServiceAccountTokenKey = "token"
+
+	// SecretTypeDockercfg contains a dockercfg file that follows the same format rules as ~/.dockercfg
+	//
+	// Required fields:
+	// - Secret.Data[".dockercfg"] - a serialized ~/.dockercfg file
+	SecretTypeDockercfg SecretType = "kubernetes.io/dockercfg"
+
+	// DockerConfigKey is the key of the required data for SecretTypeDockercfg secrets
+	DockerConfigKey = ".dockercfg"
 )
 
 type SecretList struct {
@@ -19,12 +19,69 @@ package v1beta3
 import (
 	"fmt"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
 	newer "github.com/cnaize/kubernetes/pkg/api"
 )
 
 func init() {
+	err := newer.Scheme.AddConversionFuncs(
+		func(in *newer.ServiceSpec, out *ServiceSpec, s conversion.Scope) error {
+			// Produce legacy fields.
+			if len(in.Ports) > 0 {
+				out.Port = in.Ports[0].Port
+				out.Protocol = Protocol(in.Ports[0].Protocol)
+				out.TargetPort = in.Ports[0].TargetPort
+			}
+			// Copy modern fields.
+			if err := s.Convert(&in.Ports, &out.Ports, 0); err != nil {
+				return err
+			}
+			if err := s.Convert(&in.Selector, &out.Selector, 0); err != nil {
+				return err
+			}
+			out.PortalIP = in.PortalIP
+			out.Type = ServiceType(in.Type)
+			out.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
+			if err := s.Convert(&in.PublicIPs, &out.PublicIPs, 0); err != nil {
+				return err
+			}
+			out.SessionAffinity = AffinityType(in.SessionAffinity)
+			return nil
+		},
+		func(in *ServiceSpec, out *newer.ServiceSpec, s conversion.Scope) error {
+			if len(in.Ports) == 0 && in.Port != 0 {
+				// Use legacy fields to produce modern fields.
+				out.Ports = append(out.Ports, newer.ServicePort{
+					Port:       in.Port,
+					Protocol:   newer.Protocol(in.Protocol),
+					TargetPort: in.TargetPort,
+				})
+			} else {
+				// Use modern fields, ignore legacy.
+				if err := s.Convert(&in.Ports, &out.Ports, 0); err != nil {
+					return err
+				}
+			}
+			if err := s.Convert(&in.Selector, &out.Selector, 0); err != nil {
+				return err
+			}
+			out.PortalIP = in.PortalIP
+			out.Type = newer.ServiceType(in.Type)
+			out.CreateExternalLoadBalancer = in.CreateExternalLoadBalancer
+			if err := s.Convert(&in.PublicIPs, &out.PublicIPs, 0); err != nil {
+				return err
+			}
+			out.SessionAffinity = newer.AffinityType(in.SessionAffinity)
+			return nil
+		},
+	)
+	if err != nil {
+		// If one of the conversion functions is malformed, detect it immediately.
+		panic(err)
+	}
+
 	// Add field conversion funcs.
-	err := newer.Scheme.AddFieldLabelConversionFunc("v1beta3", "Pod",
+	err = newer.Scheme.AddFieldLabelConversionFunc("v1beta3", "Pod",
 		func(label, value string) (string, string, error) {
 			switch label {
 			case "name",
@@ -59,6 +59,9 @@ func init() {
 			if obj.Spec.SessionAffinity == "" {
 				obj.Spec.SessionAffinity = AffinityTypeNone
 			}
+			if obj.Spec.Type == "" {
+				obj.Spec.Type = ServiceTypeClusterIP
+			}
 		},
 		func(obj *PodSpec) {
 			if obj.DNSPolicy == "" {
@@ -103,6 +106,20 @@ func init() {
 				obj.TargetPort = util.NewIntOrStringFromInt(obj.Port)
 			}
 		},
+		func(obj *ObjectFieldSelector) {
+			if obj.APIVersion == "" {
+				obj.APIVersion = "v1beta3"
+			}
+		},
+		func(obj *ServicePort) {
+			if obj.Protocol == "" {
+				obj.Protocol = ProtocolTCP
+			}
+			if obj.TargetPort.Kind == util.IntstrInt && obj.TargetPort.IntVal == 0 ||
+				obj.TargetPort.Kind == util.IntstrString && obj.TargetPort.StrVal == "" {
+				obj.TargetPort = util.NewIntOrStringFromInt(obj.Port)
+			}
+		},
 		func(obj *NamespaceStatus) {
 			if obj.Phase == "" {
 				obj.Phase = NamespaceActive
@@ -113,6 +130,30 @@ func init() {
 				obj.Spec.ExternalID = obj.Name
 			}
 		},
+		func(obj *JobSpec) {
+			if obj.Completions == 0 {
+				obj.Completions = 1
+			}
+			if obj.Parallelism == 0 {
+				obj.Parallelism = 1
+			}
+		},
+		func(obj *DeploymentStrategy) {
+			if obj.Type == "" {
+				obj.Type = RollingUpdateDeploymentStrategyType
+			}
+			if obj.Type == RollingUpdateDeploymentStrategyType && obj.RollingUpdate == nil {
+				obj.RollingUpdate = &RollingUpdateDeployment{
+					MaxUnavailable: util.NewIntOrStringFromInt(1),
+					MaxSurge:       util.NewIntOrStringFromInt(1),
+				}
+			}
+		},
+		func(obj *PersistentVolumeSpec) {
+			if obj.PersistentVolumeReclaimPolicy == "" {
+				obj.PersistentVolumeReclaimPolicy = PersistentVolumeReclaimRetain
+			}
+		},
 	)
 }
 
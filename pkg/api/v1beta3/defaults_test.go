@@ -50,6 +50,9 @@ func TestSetDefaultService(t *testing.T) {
 	if svc2.Spec.SessionAffinity != current.AffinityTypeNone {
 		t.Errorf("Expected default sesseion affinity type:%s, got: %s", current.AffinityTypeNone, svc2.Spec.SessionAffinity)
 	}
+	if svc2.Spec.Type != current.ServiceTypeClusterIP {
+		t.Errorf("Expected default type:%s, got: %s", current.ServiceTypeClusterIP, svc2.Spec.Type)
+	}
 }
 
 func TestSetDefaultSecret(t *testing.T) {
@@ -100,6 +103,29 @@ func TestSetDefaulServiceTargetPort(t *testing.T) {
 	}
 }
 
+func TestSetDefaultServicePort(t *testing.T) {
+	in := &current.Service{Spec: current.ServiceSpec{
+		Ports: []current.ServicePort{
+			{Name: "http", Port: 80},
+			{Name: "dns", Protocol: current.ProtocolUDP, Port: 53, TargetPort: util.NewIntOrStringFromString("p")},
+		},
+	}}
+	obj := roundTrip(t, runtime.Object(in))
+	out := obj.(*current.Service)
+	if out.Spec.Ports[0].Protocol != current.ProtocolTCP {
+		t.Errorf("Expected protocol %s, got %s", current.ProtocolTCP, out.Spec.Ports[0].Protocol)
+	}
+	if out.Spec.Ports[0].TargetPort != util.NewIntOrStringFromInt(80) {
+		t.Errorf("Expected TargetPort to be defaulted, got %s", out.Spec.Ports[0].TargetPort)
+	}
+	if out.Spec.Ports[1].Protocol != current.ProtocolUDP {
+		t.Errorf("Expected protocol %s, got %s", current.ProtocolUDP, out.Spec.Ports[1].Protocol)
+	}
+	if out.Spec.Ports[1].TargetPort != util.NewIntOrStringFromString("p") {
+		t.Errorf("Expected TargetPort to be unchanged, got %s", out.Spec.Ports[1].TargetPort)
+	}
+}
+
 func TestSetDefaultNamespace(t *testing.T) {
 	s := &current.Namespace{}
 	obj2 := roundTrip(t, runtime.Object(s))
@@ -136,6 +162,33 @@ func TestSetDefaultPodSpecHostNetwork(t *testing.T) {
 	}
 }
 
+func TestSetDefaultObjectFieldSelectorAPIVersion(t *testing.T) {
+	s := current.PodSpec{
+		Containers: []current.Container{
+			{
+				Env: []current.EnvVar{
+					{
+						ValueFrom: &current.EnvVarSource{
+							FieldRef: &current.ObjectFieldSelector{},
+						},
+					},
+				},
+			},
+		},
+	}
+	pod := &current.Pod{
+		Spec: s,
+	}
+	obj2 := roundTrip(t, runtime.Object(pod))
+	pod2 := obj2.(*current.Pod)
+	s2 := pod2.Spec
+
+	apiVersion := s2.Containers[0].Env[0].ValueFrom.FieldRef.APIVersion
+	if apiVersion != "v1beta3" {
+		t.Errorf("Expected default APIVersion v1beta3, got: %v", apiVersion)
+	}
+}
+
 func TestSetDefaultNodeExternalID(t *testing.T) {
 	name := "node0"
 	n := &current.Node{}
@@ -17,9 +17,11 @@ limitations under the License.
 package validation
 
 import (
+	"encoding/json"
 	"fmt"
 	"net"
 	"path"
+	"strconv"
 	"strings"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
@@ -44,6 +46,7 @@ var qualifiedNameErrorMsg string = fmt.Sprintf("must have at most %d characters
 var dnsSubdomainErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS1123SubdomainMaxLength, util.DNS1123SubdomainFmt)
 var dns1123LabelErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS1123LabelMaxLength, util.DNS1123LabelFmt)
 var dns952LabelErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS952LabelMaxLength, util.DNS952LabelFmt)
+var secretKeyErrorMsg string = fmt.Sprintf("must have at most %d characters and match regex %s", util.DNS1123SubdomainMaxLength, util.SecretKeyFmt)
 var pdPartitionErrorMsg string = intervalErrorMsg(0, 255)
 var portRangeErrorMsg string = intervalErrorMsg(0, 65536)
 
@@ -108,6 +111,34 @@ func ValidateReplicationControllerName(name string, prefix bool) (bool, string)
 	return nameIsDNSSubdomain(name, prefix)
 }
 
+// ValidateJobName can be used to check whether the given job name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
+func ValidateJobName(name string, prefix bool) (bool, string) {
+	return nameIsDNSSubdomain(name, prefix)
+}
+
+// ValidateDaemonSetName can be used to check whether the given daemon set name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
+func ValidateDaemonSetName(name string, prefix bool) (bool, string) {
+	return nameIsDNSSubdomain(name, prefix)
+}
+
+// ValidateDeploymentName can be used to check whether the given deployment name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
+func ValidateDeploymentName(name string, prefix bool) (bool, string) {
+	return nameIsDNSSubdomain(name, prefix)
+}
+
+// ValidateHorizontalPodAutoscalerName can be used to check whether the given horizontal pod autoscaler name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
+func ValidateHorizontalPodAutoscalerName(name string, prefix bool) (bool, string) {
+	return nameIsDNSSubdomain(name, prefix)
+}
+
 // ValidateServiceName can be used to check whether the given service name is valid.
 // Prefix indicates this name will be used as part of generation, in which case
 // trailing dashes are allowed.
@@ -151,6 +182,13 @@ func ValidateSecretName(name string, prefix bool) (bool, string) {
 	return nameIsDNSSubdomain(name, prefix)
 }
 
+// ValidateServiceAccountName can be used to check whether the given service account name is valid.
+// Prefix indicates this name will be used as part of generation, in which case
+// trailing dashes are allowed.
+func ValidateServiceAccountName(name string, prefix bool) (bool, string) {
+	return nameIsDNSSubdomain(name, prefix)
+}
+
 // ValidateEndpointsName can be used to check whether the given endpoints name is valid.
 // Prefix indicates this name will be used as part of generation, in which case
 // trailing dashes are allowed.
@@ -305,6 +343,10 @@ func validateSource(source *api.VolumeSource) errs.ValidationErrorList {
 		numVolumes++
 		allErrs = append(allErrs, validateNFS(source.NFS).Prefix("nfs")...)
 	}
+	if source.PersistentVolumeClaim != nil {
+		numVolumes++
+		allErrs = append(allErrs, validatePersistentClaimVolumeSource(source.PersistentVolumeClaim).Prefix("persistentVolumeClaim")...)
+	}
 	if source.ScriptableDisk != nil {
 		numVolumes++
 		allErrs = append(allErrs, validateScriptableDisk(source.ScriptableDisk).Prefix("scriptableDisk")...)
@@ -353,6 +395,14 @@ func validateSecretVolumeSource(secretSource *api.SecretVolumeSource) errs.Valid
 	return allErrs
 }
 
+func validatePersistentClaimVolumeSource(claim *api.PersistentVolumeClaimVolumeSource) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if claim.ClaimName == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("claimName"))
+	}
+	return allErrs
+}
+
 func validateNFS(nfs *api.NFSVolumeSource) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	if nfs.Server == "" {
@@ -375,8 +425,18 @@ func validateScriptableDisk(sd *api.ScriptableDiskVolumeSource) errs.ValidationE
 	return allErrs
 }
 
+var supportedReclaimPolicies = util.NewStringSet(string(api.PersistentVolumeReclaimRetain), string(api.PersistentVolumeReclaimRecycle))
+var supportedVolumePhases = util.NewStringSet(string(api.VolumeAvailable), string(api.VolumeBound), string(api.VolumeReleased))
+var supportedClaimPhases = util.NewStringSet(string(api.ClaimPending), string(api.ClaimBound), string(api.ClaimLost))
+
 func ValidatePersistentVolumeName(name string, prefix bool) (bool, string) {
-	return util.IsDNS1123Label(name), name
+	if prefix {
+		name = maskTrailingDash(name)
+	}
+	if util.IsDNS1123Label(name) {
+		return true, ""
+	}
+	return false, dns1123LabelErrorMsg
 }
 
 func ValidatePersistentVolume(pv *api.PersistentVolume) errs.ValidationErrorList {
@@ -402,6 +462,31 @@ func ValidatePersistentVolume(pv *api.PersistentVolume) errs.ValidationErrorList
 	if numVolumes != 1 {
 		allErrs = append(allErrs, errs.NewFieldInvalid("", pv.Spec.PersistentVolumeSource, "exactly 1 volume type is required"))
 	}
+	if len(pv.Spec.PersistentVolumeReclaimPolicy) > 0 && !supportedReclaimPolicies.Has(string(pv.Spec.PersistentVolumeReclaimPolicy)) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("spec.persistentVolumeReclaimPolicy", pv.Spec.PersistentVolumeReclaimPolicy))
+	}
+	return allErrs
+}
+
+// ValidatePersistentVolumeUpdate tests to see if the update is legal for an end user to make.
+// newPv is updated with fields that cannot be changed.
+func ValidatePersistentVolumeUpdate(newPv, oldPv *api.PersistentVolume) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPv.ObjectMeta, &newPv.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidatePersistentVolume(newPv)...)
+	newPv.Status = oldPv.Status
+	return allErrs
+}
+
+// ValidatePersistentVolumeStatusUpdate tests to see if the status update is legal for an end user to make.
+// newPv is updated with fields that cannot be changed.
+func ValidatePersistentVolumeStatusUpdate(newPv, oldPv *api.PersistentVolume) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPv.ObjectMeta, &newPv.ObjectMeta).Prefix("metadata")...)
+	if len(newPv.Status.Phase) > 0 && !supportedVolumePhases.Has(string(newPv.Status.Phase)) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("status.phase", newPv.Status.Phase))
+	}
+	newPv.Spec = oldPv.Spec
 	return allErrs
 }
 
@@ -416,6 +501,28 @@ func ValidatePersistentVolumeClaim(pvc *api.PersistentVolumeClaim) errs.Validati
 	return allErrs
 }
 
+// ValidatePersistentVolumeClaimUpdate tests to see if the update is legal for an end user to make.
+// newPvc is updated with fields that cannot be changed.
+func ValidatePersistentVolumeClaimUpdate(newPvc, oldPvc *api.PersistentVolumeClaim) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPvc.ObjectMeta, &newPvc.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidatePersistentVolumeClaim(newPvc)...)
+	newPvc.Status = oldPvc.Status
+	return allErrs
+}
+
+// ValidatePersistentVolumeClaimStatusUpdate tests to see if the status update is legal for an end user to make.
+// newPvc is updated with fields that cannot be changed.
+func ValidatePersistentVolumeClaimStatusUpdate(newPvc, oldPvc *api.PersistentVolumeClaim) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldPvc.ObjectMeta, &newPvc.ObjectMeta).Prefix("metadata")...)
+	if len(newPvc.Status.Phase) > 0 && !supportedClaimPhases.Has(string(newPvc.Status.Phase)) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("status.phase", newPvc.Status.Phase))
+	}
+	newPvc.Spec = oldPvc.Spec
+	return allErrs
+}
+
 var supportedPortProtocols = util.NewStringSet(string(api.ProtocolTCP), string(api.ProtocolUDP))
 
 func validatePorts(ports []api.ContainerPort) errs.ValidationErrorList {
@@ -462,11 +569,71 @@ func validateEnv(vars []api.EnvVar) errs.ValidationErrorList {
 		if !util.IsCIdentifier(ev.Name) {
 			vErrs = append(vErrs, errs.NewFieldInvalid("name", ev.Name, cIdentifierErrorMsg))
 		}
+		vErrs = append(vErrs, validateEnvVarValueFrom(ev).Prefix("valueFrom")...)
 		allErrs = append(allErrs, vErrs.PrefixIndex(i)...)
 	}
 	return allErrs
 }
 
+// validEnvDownwardAPIFieldPathExpressions holds the pod fields that may be
+// projected into a container's environment.
+var validEnvDownwardAPIFieldPathExpressions = util.NewStringSet("metadata.name", "metadata.namespace", "status.podIP")
+
+func validateEnvVarValueFrom(ev api.EnvVar) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if ev.ValueFrom == nil {
+		return allErrs
+	}
+	if len(ev.Value) != 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("", "", "sources cannot be specified when value is not empty"))
+	}
+
+	numSources := 0
+	if ev.ValueFrom.FieldRef != nil {
+		numSources++
+		allErrs = append(allErrs, validateObjectFieldSelector(ev.ValueFrom.FieldRef, &validEnvDownwardAPIFieldPathExpressions).Prefix("fieldRef")...)
+	}
+	if ev.ValueFrom.SecretKeyRef != nil {
+		numSources++
+		allErrs = append(allErrs, validateSecretKeySelector(ev.ValueFrom.SecretKeyRef).Prefix("secretKeyRef")...)
+	}
+	if numSources != 1 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("", "", "must specify exactly one source"))
+	}
+	return allErrs
+}
+
+func validateSecretKeySelector(s *api.SecretKeySelector) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if len(s.Name) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("name"))
+	} else if !util.IsDNS1123Subdomain(s.Name) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("name", s.Name, dnsSubdomainErrorMsg))
+	}
+	if len(s.Key) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("key"))
+	} else if !util.IsSecretKey(s.Key) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("key", s.Key, secretKeyErrorMsg))
+	}
+	return allErrs
+}
+
+func validateObjectFieldSelector(fs *api.ObjectFieldSelector, expressions *util.StringSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if len(fs.APIVersion) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("apiVersion"))
+	}
+	if len(fs.FieldPath) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("fieldPath"))
+	} else if !expressions.Has(fs.FieldPath) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("fieldPath", fs.FieldPath))
+	}
+	return allErrs
+}
+
 func validateVolumeMounts(mounts []api.VolumeMount, volumes util.StringSet) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 
@@ -498,6 +665,23 @@ func validateProbe(probe *api.Probe) errs.ValidationErrorList {
 	if probe.TimeoutSeconds < 0 {
 		allErrs = append(allErrs, errs.NewFieldInvalid("timeout", probe.TimeoutSeconds, "may not be less than zero"))
 	}
+	if probe.PeriodSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("periodSeconds", probe.PeriodSeconds, "may not be less than zero"))
+	}
+	if probe.SuccessThreshold < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("successThreshold", probe.SuccessThreshold, "may not be less than zero"))
+	}
+	if probe.FailureThreshold < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("failureThreshold", probe.FailureThreshold, "may not be less than zero"))
+	}
+	return allErrs
+}
+
+func validateLivenessProbe(probe *api.Probe) errs.ValidationErrorList {
+	allErrs := validateProbe(probe)
+	if probe != nil && probe.SuccessThreshold > 1 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("successThreshold", probe.SuccessThreshold, "must be 1 for liveness probes"))
+	}
 	return allErrs
 }
 
@@ -609,6 +793,17 @@ func validatePullPolicy(ctr *api.Container) errs.ValidationErrorList {
 	return allErrors
 }
 
+func validateSecurityContext(sc *api.SecurityContext) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if sc == nil {
+		return allErrs
+	}
+	if sc.RunAsUser != nil && *sc.RunAsUser < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("runAsUser", *sc.RunAsUser, "must be a non-negative integer"))
+	}
+	return allErrs
+}
+
 func validateContainers(containers []api.Container, volumes util.StringSet) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 
@@ -637,13 +832,14 @@ func validateContainers(containers []api.Container, volumes util.StringSet) errs
 		if ctr.Lifecycle != nil {
 			cErrs = append(cErrs, validateLifecycle(ctr.Lifecycle).Prefix("lifecycle")...)
 		}
-		cErrs = append(cErrs, validateProbe(ctr.LivenessProbe).Prefix("livenessProbe")...)
+		cErrs = append(cErrs, validateLivenessProbe(ctr.LivenessProbe).Prefix("livenessProbe")...)
 		cErrs = append(cErrs, validateProbe(ctr.ReadinessProbe).Prefix("readinessProbe")...)
 		cErrs = append(cErrs, validatePorts(ctr.Ports).Prefix("ports")...)
 		cErrs = append(cErrs, validateEnv(ctr.Env).Prefix("env")...)
 		cErrs = append(cErrs, validateVolumeMounts(ctr.VolumeMounts, volumes).Prefix("volumeMounts")...)
 		cErrs = append(cErrs, validatePullPolicy(&ctr).Prefix("pullPolicy")...)
 		cErrs = append(cErrs, validateResourceRequirements(&ctr).Prefix("resources")...)
+		cErrs = append(cErrs, validateSecurityContext(ctr.SecurityContext).Prefix("securityContext")...)
 		allErrs = append(allErrs, cErrs.PrefixIndex(i)...)
 	}
 	// Check for colliding ports across all containers.
@@ -743,6 +939,176 @@ func ValidatePodSpec(spec *api.PodSpec) errs.ValidationErrorList {
 	allErrs = append(allErrs, validateDNSPolicy(&spec.DNSPolicy).Prefix("dnsPolicy")...)
 	allErrs = append(allErrs, ValidateLabels(spec.NodeSelector, "nodeSelector")...)
 	allErrs = append(allErrs, validateHostNetwork(spec.HostNetwork, spec.Containers).Prefix("hostNetwork")...)
+	if len(spec.ServiceAccount) > 0 {
+		if ok, msg := ValidateServiceAccountName(spec.ServiceAccount, false); !ok {
+			allErrs = append(allErrs, errs.NewFieldInvalid("serviceAccount", spec.ServiceAccount, msg))
+		}
+	}
+	allErrs = append(allErrs, validateImagePullSecrets(spec.ImagePullSecrets).Prefix("imagePullSecrets")...)
+	allErrs = append(allErrs, validateTolerations(spec.Tolerations).Prefix("tolerations")...)
+	if spec.Affinity != nil {
+		allErrs = append(allErrs, validateAffinity(spec.Affinity).Prefix("affinity")...)
+	}
+	if len(spec.SchedulerName) > 0 && !util.IsDNS1123Subdomain(spec.SchedulerName) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("schedulerName", spec.SchedulerName, dnsSubdomainErrorMsg))
+	}
+	if spec.ActiveDeadlineSeconds != nil && *spec.ActiveDeadlineSeconds <= 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("activeDeadlineSeconds", *spec.ActiveDeadlineSeconds, "must be a positive integer"))
+	}
+	if spec.TerminationGracePeriodSeconds != nil && *spec.TerminationGracePeriodSeconds < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("terminationGracePeriodSeconds", *spec.TerminationGracePeriodSeconds, "must be a non-negative integer"))
+	}
+	return allErrs
+}
+
+func validateImagePullSecrets(imagePullSecrets []api.LocalObjectReference) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, ref := range imagePullSecrets {
+		if len(ref.Name) == 0 {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("[%d].name", i)))
+		} else if ok, msg := ValidateSecretName(ref.Name, false); !ok {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("[%d].name", i), ref.Name, msg))
+		}
+	}
+	return allErrs
+}
+
+var supportedTaintEffects = util.NewStringSet(string(api.TaintEffectNoSchedule))
+
+func validateTolerations(tolerations []api.Toleration) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i, toleration := range tolerations {
+		tErrs := errs.ValidationErrorList{}
+		if len(toleration.Key) == 0 {
+			tErrs = append(tErrs, errs.NewFieldRequired("key"))
+		} else if !util.IsQualifiedName(toleration.Key) {
+			tErrs = append(tErrs, errs.NewFieldInvalid("key", toleration.Key, qualifiedNameErrorMsg))
+		}
+		switch toleration.Operator {
+		case "", api.TolerationOpEqual:
+			if !util.IsValidLabelValue(toleration.Value) {
+				tErrs = append(tErrs, errs.NewFieldInvalid("value", toleration.Value, labelValueErrorMsg))
+			}
+		case api.TolerationOpExists:
+			if len(toleration.Value) > 0 {
+				tErrs = append(tErrs, errs.NewFieldInvalid("value", toleration.Value, "must be empty when the operator is Exists"))
+			}
+		default:
+			tErrs = append(tErrs, errs.NewFieldNotSupported("operator", toleration.Operator))
+		}
+		if len(toleration.Effect) > 0 && !supportedTaintEffects.Has(string(toleration.Effect)) {
+			tErrs = append(tErrs, errs.NewFieldNotSupported("effect", toleration.Effect))
+		}
+		allErrs = append(allErrs, tErrs.PrefixIndex(i)...)
+	}
+	return allErrs
+}
+
+func validateAffinity(affinity *api.Affinity) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if affinity.NodeAffinity != nil {
+		allErrs = append(allErrs, validateNodeAffinity(affinity.NodeAffinity).Prefix("nodeAffinity")...)
+	}
+	if affinity.PodAffinity != nil {
+		allErrs = append(allErrs, validatePodAffinityTerms(affinity.PodAffinity.Required, affinity.PodAffinity.Preferred).Prefix("podAffinity")...)
+	}
+	if affinity.PodAntiAffinity != nil {
+		allErrs = append(allErrs, validatePodAffinityTerms(affinity.PodAntiAffinity.Required, affinity.PodAntiAffinity.Preferred).Prefix("podAntiAffinity")...)
+	}
+	return allErrs
+}
+
+func validatePodAffinityTerms(required []api.PodAffinityTerm, preferred []api.WeightedPodAffinityTerm) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	for i := range required {
+		allErrs = append(allErrs, validatePodAffinityTerm(&required[i]).PrefixIndex(i).Prefix("required")...)
+	}
+	for i, term := range preferred {
+		pErrs := errs.ValidationErrorList{}
+		if term.Weight < 1 || term.Weight > 100 {
+			pErrs = append(pErrs, errs.NewFieldInvalid("weight", term.Weight, "must be in the range 1-100"))
+		}
+		pErrs = append(pErrs, validatePodAffinityTerm(&term.PodAffinityTerm).Prefix("podAffinityTerm")...)
+		allErrs = append(allErrs, pErrs.PrefixIndex(i).Prefix("preferred")...)
+	}
+	return allErrs
+}
+
+func validatePodAffinityTerm(term *api.PodAffinityTerm) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(term.LabelSelector) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("labelSelector"))
+	}
+	allErrs = append(allErrs, ValidateLabels(term.LabelSelector, "labelSelector")...)
+	for i, namespace := range term.Namespaces {
+		if ok, msg := ValidateNamespaceName(namespace, false); !ok {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("namespaces[%d]", i), namespace, msg))
+		}
+	}
+	if len(term.TopologyKey) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("topologyKey"))
+	} else if !util.IsQualifiedName(term.TopologyKey) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("topologyKey", term.TopologyKey, qualifiedNameErrorMsg))
+	}
+	return allErrs
+}
+
+func validateNodeAffinity(affinity *api.NodeAffinity) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if affinity.Required != nil {
+		rErrs := errs.ValidationErrorList{}
+		if len(affinity.Required.NodeSelectorTerms) == 0 {
+			rErrs = append(rErrs, errs.NewFieldRequired("nodeSelectorTerms"))
+		}
+		for i := range affinity.Required.NodeSelectorTerms {
+			rErrs = append(rErrs, validateNodeSelectorTerm(&affinity.Required.NodeSelectorTerms[i]).PrefixIndex(i).Prefix("nodeSelectorTerms")...)
+		}
+		allErrs = append(allErrs, rErrs.Prefix("required")...)
+	}
+	for i, term := range affinity.Preferred {
+		pErrs := errs.ValidationErrorList{}
+		if term.Weight < 1 || term.Weight > 100 {
+			pErrs = append(pErrs, errs.NewFieldInvalid("weight", term.Weight, "must be in the range 1-100"))
+		}
+		pErrs = append(pErrs, validateNodeSelectorTerm(&term.Preference).Prefix("preference")...)
+		allErrs = append(allErrs, pErrs.PrefixIndex(i).Prefix("preferred")...)
+	}
+	return allErrs
+}
+
+func validateNodeSelectorTerm(term *api.NodeSelectorTerm) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(term.MatchExpressions) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("matchExpressions"))
+	}
+	for i, expr := range term.MatchExpressions {
+		eErrs := errs.ValidationErrorList{}
+		if len(expr.Key) == 0 {
+			eErrs = append(eErrs, errs.NewFieldRequired("key"))
+		} else if !util.IsQualifiedName(expr.Key) {
+			eErrs = append(eErrs, errs.NewFieldInvalid("key", expr.Key, qualifiedNameErrorMsg))
+		}
+		switch expr.Operator {
+		case api.NodeSelectorOpIn, api.NodeSelectorOpNotIn:
+			if len(expr.Values) == 0 {
+				eErrs = append(eErrs, errs.NewFieldRequired("values"))
+			}
+		case api.NodeSelectorOpExists:
+			if len(expr.Values) > 0 {
+				eErrs = append(eErrs, errs.NewFieldInvalid("values", expr.Values, "must be empty when the operator is Exists"))
+			}
+		case "":
+			eErrs = append(eErrs, errs.NewFieldRequired("operator"))
+		default:
+			eErrs = append(eErrs, errs.NewFieldNotSupported("operator", expr.Operator))
+		}
+		for j, value := range expr.Values {
+			if !util.IsValidLabelValue(value) {
+				eErrs = append(eErrs, errs.NewFieldInvalid(fmt.Sprintf("values[%d]", j), value, labelValueErrorMsg))
+			}
+		}
+		allErrs = append(allErrs, eErrs.PrefixIndex(i).Prefix("matchExpressions")...)
+	}
 	return allErrs
 }
 
@@ -792,24 +1158,21 @@ func ValidatePodStatusUpdate(newPod, oldPod *api.Pod) errs.ValidationErrorList {
 }
 
 var supportedSessionAffinityType = util.NewStringSet(string(api.AffinityTypeClientIP), string(api.AffinityTypeNone))
+var supportedServiceType = util.NewStringSet(string(api.ServiceTypeClusterIP), string(api.ServiceTypeNodePort))
 
 // ValidateService tests if required fields in the service are set.
 func ValidateService(service *api.Service) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
 	allErrs = append(allErrs, ValidateObjectMeta(&service.ObjectMeta, true, ValidateServiceName).Prefix("metadata")...)
 
-	if !util.IsValidPortNum(service.Spec.Port) {
-		allErrs = append(allErrs, errs.NewFieldInvalid("spec.port", service.Spec.Port, portRangeErrorMsg))
+	if len(service.Spec.Ports) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("spec.ports"))
 	}
-	if len(service.Spec.Protocol) == 0 {
-		allErrs = append(allErrs, errs.NewFieldRequired("spec.protocol"))
-	} else if !supportedPortProtocols.Has(strings.ToUpper(string(service.Spec.Protocol))) {
-		allErrs = append(allErrs, errs.NewFieldNotSupported("spec.protocol", service.Spec.Protocol))
-	}
-	if service.Spec.TargetPort.Kind == util.IntstrInt && service.Spec.TargetPort.IntVal != 0 && !util.IsValidPortNum(service.Spec.TargetPort.IntVal) {
-		allErrs = append(allErrs, errs.NewFieldInvalid("spec.containerPort", service.Spec.Port, portRangeErrorMsg))
-	} else if service.Spec.TargetPort.Kind == util.IntstrString && len(service.Spec.TargetPort.StrVal) == 0 {
-		allErrs = append(allErrs, errs.NewFieldRequired("spec.containerPort"))
+	allPortNames := util.StringSet{}
+	allNodePorts := util.StringSet{}
+	for i := range service.Spec.Ports {
+		allErrs = append(allErrs, validateServicePort(&service.Spec.Ports[i], len(service.Spec.Ports) > 1, &allPortNames).PrefixIndex(i).Prefix("spec.ports")...)
+		allErrs = append(allErrs, validateServiceNodePort(&service.Spec.Ports[i], service.Spec.Type, &allNodePorts).PrefixIndex(i).Prefix("spec.ports")...)
 	}
 
 	if service.Spec.Selector != nil {
@@ -822,6 +1185,14 @@ func ValidateService(service *api.Service) errs.ValidationErrorList {
 		allErrs = append(allErrs, errs.NewFieldNotSupported("spec.sessionAffinity", service.Spec.SessionAffinity))
 	}
 
+	if service.Spec.Type == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("spec.type"))
+	} else if !supportedServiceType.Has(string(service.Spec.Type)) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("spec.type", service.Spec.Type))
+	} else if service.Spec.Type == api.ServiceTypeNodePort && service.Spec.PortalIP == api.PortalIPNone {
+		allErrs = append(allErrs, errs.NewFieldInvalid("spec.type", service.Spec.Type, "headless services can not be exposed on node ports"))
+	}
+
 	if api.IsServiceIPSet(service) {
 		if ip := net.ParseIP(service.Spec.PortalIP); ip == nil {
 			allErrs = append(allErrs, errs.NewFieldInvalid("spec.portalIP", service.Spec.PortalIP, "portalIP should be empty, 'None', or a valid IP address"))
@@ -839,6 +1210,63 @@ func ValidateService(service *api.Service) errs.ValidationErrorList {
 	return allErrs
 }
 
+func validateServicePort(sp *api.ServicePort, requireName bool, allNames *util.StringSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if requireName && sp.Name == "" {
+		allErrs = append(allErrs, errs.NewFieldRequired("name"))
+	} else if sp.Name != "" {
+		if !util.IsDNS1123Label(sp.Name) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("name", sp.Name, dns1123LabelErrorMsg))
+		} else if allNames.Has(sp.Name) {
+			allErrs = append(allErrs, errs.NewFieldDuplicate("name", sp.Name))
+		} else {
+			allNames.Insert(sp.Name)
+		}
+	}
+
+	if !util.IsValidPortNum(sp.Port) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("port", sp.Port, portRangeErrorMsg))
+	}
+
+	if len(sp.Protocol) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("protocol"))
+	} else if !supportedPortProtocols.Has(strings.ToUpper(string(sp.Protocol))) {
+		allErrs = append(allErrs, errs.NewFieldNotSupported("protocol", sp.Protocol))
+	}
+
+	if sp.TargetPort.Kind == util.IntstrInt && sp.TargetPort.IntVal != 0 && !util.IsValidPortNum(sp.TargetPort.IntVal) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("targetPort", sp.TargetPort, portRangeErrorMsg))
+	} else if sp.TargetPort.Kind == util.IntstrString && len(sp.TargetPort.StrVal) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("targetPort"))
+	}
+
+	return allErrs
+}
+
+// validateServiceNodePort checks that a node port is only requested by
+// NodePort services and that no two ports of a service claim the same one.
+func validateServiceNodePort(sp *api.ServicePort, serviceType api.ServiceType, allNodePorts *util.StringSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if sp.NodePort == 0 {
+		return allErrs
+	}
+	if serviceType != api.ServiceTypeNodePort {
+		allErrs = append(allErrs, errs.NewFieldInvalid("nodePort", sp.NodePort, "may only be set when spec.type is NodePort"))
+	} else if !util.IsValidPortNum(sp.NodePort) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("nodePort", sp.NodePort, portRangeErrorMsg))
+	} else {
+		key := strconv.Itoa(sp.NodePort)
+		if allNodePorts.Has(key) {
+			allErrs = append(allErrs, errs.NewFieldDuplicate("nodePort", sp.NodePort))
+		} else {
+			allNodePorts.Insert(key)
+		}
+	}
+	return allErrs
+}
+
 // ValidateServiceUpdate tests if required fields in the service are set during an update
 func ValidateServiceUpdate(oldService, service *api.Service) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
@@ -898,6 +1326,329 @@ func ValidateReplicationControllerSpec(spec *api.ReplicationControllerSpec) errs
 	return allErrs
 }
 
+// ValidateJob tests if required fields in the job are set.
+func ValidateJob(job *api.Job) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&job.ObjectMeta, true, ValidateJobName).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateJobSpec(&job.Spec).Prefix("spec")...)
+	return allErrs
+}
+
+// ValidateJobUpdate tests to see if the update is legal for an end user to make.
+// job is updated with fields that cannot be changed.
+func ValidateJobUpdate(oldJob, job *api.Job) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldJob.ObjectMeta, &job.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateJobSpec(&job.Spec).Prefix("spec")...)
+	if job.Spec.Completions != oldJob.Spec.Completions {
+		allErrs = append(allErrs, errs.NewFieldInvalid("spec.completions", job.Spec.Completions, "field is immutable"))
+	}
+	if !api.Semantic.DeepEqual(job.Spec.Selector, oldJob.Spec.Selector) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("spec.selector", job.Spec.Selector, "field is immutable"))
+	}
+	if !api.Semantic.DeepEqual(job.Spec.Template, oldJob.Spec.Template) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("spec.template", "", "field is immutable"))
+	}
+	job.Status = oldJob.Status
+	return allErrs
+}
+
+// ValidateJobStatusUpdate tests to see if the status update is legal for an end user to make.
+// newJob is updated with fields that cannot be changed.
+func ValidateJobStatusUpdate(newJob, oldJob *api.Job) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldJob.ObjectMeta, &newJob.ObjectMeta).Prefix("metadata")...)
+	if newJob.Status.Active < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.active", newJob.Status.Active, isNegativeErrorMsg))
+	}
+	if newJob.Status.Succeeded < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.succeeded", newJob.Status.Succeeded, isNegativeErrorMsg))
+	}
+	if newJob.Status.Failed < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.failed", newJob.Status.Failed, isNegativeErrorMsg))
+	}
+	newJob.Spec = oldJob.Spec
+	return allErrs
+}
+
+// ValidateJobSpec tests if required fields in the job spec are set.
+func ValidateJobSpec(spec *api.JobSpec) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if spec.Completions < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("completions", spec.Completions, isNegativeErrorMsg))
+	}
+	if spec.Parallelism < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("parallelism", spec.Parallelism, isNegativeErrorMsg))
+	}
+
+	selector := labels.Set(spec.Selector).AsSelector()
+	if selector.Empty() {
+		allErrs = append(allErrs, errs.NewFieldRequired("selector"))
+	}
+
+	if spec.Template == nil {
+		allErrs = append(allErrs, errs.NewFieldRequired("template"))
+	} else {
+		labels := labels.Set(spec.Template.Labels)
+		if !selector.Matches(labels) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("template.labels", spec.Template.Labels, "selector does not match template"))
+		}
+		allErrs = append(allErrs, ValidatePodTemplateSpec(spec.Template, spec.Parallelism).Prefix("template")...)
+		// RestartPolicy has already been first-order validated as per ValidatePodTemplateSpec().
+		if spec.Template.Spec.RestartPolicy != api.RestartPolicyOnFailure &&
+			spec.Template.Spec.RestartPolicy != api.RestartPolicyNever {
+			allErrs = append(allErrs, errs.NewFieldNotSupported("template.restartPolicy", spec.Template.Spec.RestartPolicy))
+		}
+	}
+	return allErrs
+}
+
+// ValidateDaemonSet tests if required fields in the daemon set are set.
+func ValidateDaemonSet(ds *api.DaemonSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&ds.ObjectMeta, true, ValidateDaemonSetName).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateDaemonSetSpec(&ds.Spec).Prefix("spec")...)
+	return allErrs
+}
+
+// ValidateDaemonSetUpdate tests to see if the update is legal for an end user to make.
+// ds is updated with fields that cannot be changed.
+func ValidateDaemonSetUpdate(oldDS, ds *api.DaemonSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldDS.ObjectMeta, &ds.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateDaemonSetSpec(&ds.Spec).Prefix("spec")...)
+	if !api.Semantic.DeepEqual(ds.Spec.Selector, oldDS.Spec.Selector) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("spec.selector", ds.Spec.Selector, "field is immutable"))
+	}
+	ds.Status = oldDS.Status
+	return allErrs
+}
+
+// ValidateDaemonSetStatusUpdate tests to see if the status update is legal for an end user to make.
+// newDS is updated with fields that cannot be changed.
+func ValidateDaemonSetStatusUpdate(newDS, oldDS *api.DaemonSet) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldDS.ObjectMeta, &newDS.ObjectMeta).Prefix("metadata")...)
+	if newDS.Status.CurrentNumberScheduled < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.currentNumberScheduled", newDS.Status.CurrentNumberScheduled, isNegativeErrorMsg))
+	}
+	if newDS.Status.NumberMisscheduled < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.numberMisscheduled", newDS.Status.NumberMisscheduled, isNegativeErrorMsg))
+	}
+	if newDS.Status.DesiredNumberScheduled < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.desiredNumberScheduled", newDS.Status.DesiredNumberScheduled, isNegativeErrorMsg))
+	}
+	newDS.Spec = oldDS.Spec
+	return allErrs
+}
+
+// ValidateDaemonSetSpec tests if required fields in the daemon set spec are set.
+func ValidateDaemonSetSpec(spec *api.DaemonSetSpec) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	selector := labels.Set(spec.Selector).AsSelector()
+	if selector.Empty() {
+		allErrs = append(allErrs, errs.NewFieldRequired("selector"))
+	}
+	allErrs = append(allErrs, ValidateLabels(spec.NodeSelector, "nodeSelector")...)
+
+	if spec.Template == nil {
+		allErrs = append(allErrs, errs.NewFieldRequired("template"))
+	} else {
+		labels := labels.Set(spec.Template.Labels)
+		if !selector.Matches(labels) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("template.labels", spec.Template.Labels, "selector does not match template"))
+		}
+		allErrs = append(allErrs, ValidatePodTemplateSpec(spec.Template, 0).Prefix("template")...)
+		// RestartPolicy has already been first-order validated as per ValidatePodTemplateSpec().
+		if spec.Template.Spec.RestartPolicy != api.RestartPolicyAlways {
+			allErrs = append(allErrs, errs.NewFieldNotSupported("template.restartPolicy", spec.Template.Spec.RestartPolicy))
+		}
+		// The daemon set binds its pods to nodes itself.
+		if len(spec.Template.Spec.Host) != 0 {
+			allErrs = append(allErrs, errs.NewFieldInvalid("template.spec.host", spec.Template.Spec.Host, "must be empty, pods are bound to nodes by the daemon set"))
+		}
+	}
+	return allErrs
+}
+
+// ValidateDeployment tests if required fields in the deployment are set.
+func ValidateDeployment(deployment *api.Deployment) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&deployment.ObjectMeta, true, ValidateDeploymentName).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateDeploymentSpec(&deployment.Spec).Prefix("spec")...)
+	return allErrs
+}
+
+// ValidateDeploymentUpdate tests to see if the update is legal for an end user to make.
+// deployment is updated with fields that cannot be changed.
+func ValidateDeploymentUpdate(oldDeployment, deployment *api.Deployment) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldDeployment.ObjectMeta, &deployment.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateDeploymentSpec(&deployment.Spec).Prefix("spec")...)
+	if !api.Semantic.DeepEqual(deployment.Spec.Selector, oldDeployment.Spec.Selector) {
+		allErrs = append(allErrs, errs.NewFieldInvalid("spec.selector", deployment.Spec.Selector, "field is immutable"))
+	}
+	deployment.Status = oldDeployment.Status
+	return allErrs
+}
+
+// ValidateDeploymentStatusUpdate tests to see if the status update is legal for an end user to make.
+// newDeployment is updated with fields that cannot be changed.
+func ValidateDeploymentStatusUpdate(newDeployment, oldDeployment *api.Deployment) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldDeployment.ObjectMeta, &newDeployment.ObjectMeta).Prefix("metadata")...)
+	status := newDeployment.Status
+	if status.Replicas < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.replicas", status.Replicas, isNegativeErrorMsg))
+	}
+	if status.UpdatedReplicas < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.updatedReplicas", status.UpdatedReplicas, isNegativeErrorMsg))
+	}
+	if status.AvailableReplicas < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.availableReplicas", status.AvailableReplicas, isNegativeErrorMsg))
+	}
+	if status.Revision < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.revision", status.Revision, isNegativeErrorMsg))
+	}
+	newDeployment.Spec = oldDeployment.Spec
+	return allErrs
+}
+
+// ValidateDeploymentSpec tests if required fields in the deployment spec are set.
+func ValidateDeploymentSpec(spec *api.DeploymentSpec) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+
+	if spec.Replicas < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("replicas", spec.Replicas, isNegativeErrorMsg))
+	}
+	selector := labels.Set(spec.Selector).AsSelector()
+	if selector.Empty() {
+		allErrs = append(allErrs, errs.NewFieldRequired("selector"))
+	}
+
+	if spec.Template == nil {
+		allErrs = append(allErrs, errs.NewFieldRequired("template"))
+	} else {
+		labels := labels.Set(spec.Template.Labels)
+		if !selector.Matches(labels) {
+			allErrs = append(allErrs, errs.NewFieldInvalid("template.labels", spec.Template.Labels, "selector does not match template"))
+		}
+		allErrs = append(allErrs, ValidatePodTemplateSpec(spec.Template, spec.Replicas).Prefix("template")...)
+		// RestartPolicy has already been first-order validated as per ValidatePodTemplateSpec().
+		if spec.Template.Spec.RestartPolicy != api.RestartPolicyAlways {
+			allErrs = append(allErrs, errs.NewFieldNotSupported("template.restartPolicy", spec.Template.Spec.RestartPolicy))
+		}
+	}
+
+	allErrs = append(allErrs, validateDeploymentStrategy(&spec.Strategy).Prefix("strategy")...)
+	if spec.RollbackTo != nil && spec.RollbackTo.Revision < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("rollbackTo.revision", spec.RollbackTo.Revision, isNegativeErrorMsg))
+	}
+	return allErrs
+}
+
+func validateDeploymentStrategy(strategy *api.DeploymentStrategy) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	switch strategy.Type {
+	case api.RecreateDeploymentStrategyType:
+		if strategy.RollingUpdate != nil {
+			allErrs = append(allErrs, errs.NewFieldInvalid("rollingUpdate", strategy.RollingUpdate, "may not be set when type is Recreate"))
+		}
+	case api.RollingUpdateDeploymentStrategyType:
+		if strategy.RollingUpdate == nil {
+			allErrs = append(allErrs, errs.NewFieldRequired("rollingUpdate"))
+			break
+		}
+		maxUnavailable, unavailableErrs := validateIntOrPercent(&strategy.RollingUpdate.MaxUnavailable, "rollingUpdate.maxUnavailable")
+		allErrs = append(allErrs, unavailableErrs...)
+		if strategy.RollingUpdate.MaxUnavailable.Kind == util.IntstrString && maxUnavailable > 100 {
+			allErrs = append(allErrs, errs.NewFieldInvalid("rollingUpdate.maxUnavailable", strategy.RollingUpdate.MaxUnavailable.StrVal, "must not be greater than 100%"))
+		}
+		maxSurge, surgeErrs := validateIntOrPercent(&strategy.RollingUpdate.MaxSurge, "rollingUpdate.maxSurge")
+		allErrs = append(allErrs, surgeErrs...)
+		if len(unavailableErrs) == 0 && len(surgeErrs) == 0 && maxUnavailable == 0 && maxSurge == 0 {
+			// Neither an old pod could be removed nor a new one added.
+			allErrs = append(allErrs, errs.NewFieldInvalid("rollingUpdate.maxUnavailable", strategy.RollingUpdate.MaxUnavailable.String(), "must not be 0 when maxSurge is 0"))
+		}
+	default:
+		allErrs = append(allErrs, errs.NewFieldNotSupported("type", strategy.Type))
+	}
+	return allErrs
+}
+
+// ValidateHorizontalPodAutoscaler tests if required fields in the horizontal pod autoscaler are set.
+func ValidateHorizontalPodAutoscaler(autoscaler *api.HorizontalPodAutoscaler) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&autoscaler.ObjectMeta, true, ValidateHorizontalPodAutoscalerName).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateHorizontalPodAutoscalerSpec(&autoscaler.Spec).Prefix("spec")...)
+	return allErrs
+}
+
+// ValidateHorizontalPodAutoscalerUpdate tests to see if the update is legal for an end user to make.
+// autoscaler is updated with fields that cannot be changed.
+func ValidateHorizontalPodAutoscalerUpdate(oldAutoscaler, autoscaler *api.HorizontalPodAutoscaler) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldAutoscaler.ObjectMeta, &autoscaler.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateHorizontalPodAutoscalerSpec(&autoscaler.Spec).Prefix("spec")...)
+	autoscaler.Status = oldAutoscaler.Status
+	return allErrs
+}
+
+// ValidateHorizontalPodAutoscalerStatusUpdate tests to see if the status update is legal for an end user to make.
+// newAutoscaler is updated with fields that cannot be changed.
+func ValidateHorizontalPodAutoscalerStatusUpdate(newAutoscaler, oldAutoscaler *api.HorizontalPodAutoscaler) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldAutoscaler.ObjectMeta, &newAutoscaler.ObjectMeta).Prefix("metadata")...)
+	status := newAutoscaler.Status
+	if status.CurrentReplicas < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.currentReplicas", status.CurrentReplicas, isNegativeErrorMsg))
+	}
+	if status.DesiredReplicas < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.desiredReplicas", status.DesiredReplicas, isNegativeErrorMsg))
+	}
+	if status.CurrentCPUUtilizationPercentage != nil && *status.CurrentCPUUtilizationPercentage < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("status.currentCPUUtilizationPercentage", *status.CurrentCPUUtilizationPercentage, isNegativeErrorMsg))
+	}
+	newAutoscaler.Spec = oldAutoscaler.Spec
+	return allErrs
+}
+
+// ValidateHorizontalPodAutoscalerSpec tests if required fields in the horizontal pod autoscaler spec are set.
+func ValidateHorizontalPodAutoscalerSpec(spec *api.HorizontalPodAutoscalerSpec) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	if len(spec.ScaleRef.Name) == 0 {
+		allErrs = append(allErrs, errs.NewFieldRequired("scaleRef.name"))
+	} else if ok, msg := ValidateReplicationControllerName(spec.ScaleRef.Name, false); !ok {
+		allErrs = append(allErrs, errs.NewFieldInvalid("scaleRef.name", spec.ScaleRef.Name, msg))
+	}
+	if spec.MinReplicas < 1 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("minReplicas", spec.MinReplicas, "must be greater than or equal to 1"))
+	}
+	if spec.MaxReplicas < spec.MinReplicas {
+		allErrs = append(allErrs, errs.NewFieldInvalid("maxReplicas", spec.MaxReplicas, "must be greater than or equal to minReplicas"))
+	}
+	if spec.TargetCPUUtilizationPercentage < 1 {
+		allErrs = append(allErrs, errs.NewFieldInvalid("targetCPUUtilizationPercentage", spec.TargetCPUUtilizationPercentage, "must be greater than or equal to 1"))
+	}
+	return allErrs
+}
+
+// validateIntOrPercent checks that value is a non-negative integer or
+// percentage, and returns that integer or percentage.
+func validateIntOrPercent(value *util.IntOrString, field string) (int, errs.ValidationErrorList) {
+	allErrs := errs.ValidationErrorList{}
+	v, err := util.GetValueFromIntOrPercent(value, 100, false)
+	if err != nil {
+		return 0, append(allErrs, errs.NewFieldInvalid(field, value.String(), err.Error()))
+	}
+	if v < 0 {
+		allErrs = append(allErrs, errs.NewFieldInvalid(field, value.String(), isNegativeErrorMsg))
+	}
+	return v, allErrs
+}
+
 // ValidatePodTemplateSpec validates the spec of a pod template
 func ValidatePodTemplateSpec(spec *api.PodTemplateSpec, replicas int) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
@@ -934,10 +1685,41 @@ func ValidateMinion(node *api.Node) errs.ValidationErrorList {
 		allErrs = append(allErrs, errs.NewFieldRequired("spec.ExternalID"))
 	}
 
+	allErrs = append(allErrs, validateTaints(node.Spec.Taints).Prefix("spec.taints")...)
+
 	// TODO(rjnagal): Ignore PodCIDR till its completely implemented.
 	return allErrs
 }
 
+func validateTaints(taints []api.Taint) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	seen := util.NewStringSet()
+	for i, taint := range taints {
+		tErrs := errs.ValidationErrorList{}
+		if len(taint.Key) == 0 {
+			tErrs = append(tErrs, errs.NewFieldRequired("key"))
+		} else if !util.IsQualifiedName(taint.Key) {
+			tErrs = append(tErrs, errs.NewFieldInvalid("key", taint.Key, qualifiedNameErrorMsg))
+		}
+		if !util.IsValidLabelValue(taint.Value) {
+			tErrs = append(tErrs, errs.NewFieldInvalid("value", taint.Value, labelValueErrorMsg))
+		}
+		if len(taint.Effect) == 0 {
+			tErrs = append(tErrs, errs.NewFieldRequired("effect"))
+		} else if !supportedTaintEffects.Has(string(taint.Effect)) {
+			tErrs = append(tErrs, errs.NewFieldNotSupported("effect", taint.Effect))
+		}
+		// A node holds at most one taint per key and effect.
+		id := taint.Key + ":" + string(taint.Effect)
+		if seen.Has(id) {
+			tErrs = append(tErrs, errs.NewFieldDuplicate("key", taint.Key))
+		}
+		seen.Insert(id)
+		allErrs = append(allErrs, tErrs.PrefixIndex(i)...)
+	}
+	return allErrs
+}
+
 // ValidateMinionUpdate tests to make sure a minion update can be applied.  Modifies oldMinion.
 func ValidateMinionUpdate(oldMinion *api.Node, minion *api.Node) errs.ValidationErrorList {
 	allErrs := errs.ValidationErrorList{}
@@ -956,6 +1738,9 @@ func ValidateMinionUpdate(oldMinion *api.Node, minion *api.Node) errs.Validation
 	oldMinion.Status.Capacity = minion.Status.Capacity
 	// Allow users to unschedule node
 	oldMinion.Spec.Unschedulable = minion.Spec.Unschedulable
+	// Allow users to taint node
+	allErrs = append(allErrs, validateTaints(minion.Spec.Taints).Prefix("spec.taints")...)
+	oldMinion.Spec.Taints = minion.Spec.Taints
 	// Clear status
 	oldMinion.Status = minion.Status
 
@@ -1011,8 +1796,8 @@ func ValidateSecret(secret *api.Secret) errs.ValidationErrorList {
 
 	totalSize := 0
 	for key, value := range secret.Data {
-		if !util.IsDNS1123Subdomain(key) {
-			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("data[%s]", key), key, cIdentifierErrorMsg))
+		if !util.IsSecretKey(key) {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("data[%s]", key), key, secretKeyErrorMsg))
 		}
 
 		totalSize += len(value)
@@ -1022,6 +1807,42 @@ func ValidateSecret(secret *api.Secret) errs.ValidationErrorList {
 		allErrs = append(allErrs, errs.NewFieldForbidden("data", "Maximum secret size exceeded"))
 	}
 
+	if secret.Type == api.SecretTypeServiceAccountToken {
+		if value := secret.Annotations[api.ServiceAccountNameKey]; len(value) == 0 {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("metadata.annotations[%s]", api.ServiceAccountNameKey)))
+		}
+	}
+
+	if secret.Type == api.SecretTypeDockercfg {
+		dockercfgBytes, exists := secret.Data[api.DockerConfigKey]
+		if !exists {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("data[%s]", api.DockerConfigKey)))
+		} else if err := json.Unmarshal(dockercfgBytes, &map[string]interface{}{}); err != nil {
+			allErrs = append(allErrs, errs.NewFieldInvalid(fmt.Sprintf("data[%s]", api.DockerConfigKey), "<secret contents redacted>", err.Error()))
+		}
+	}
+
+	return allErrs
+}
+
+// ValidateServiceAccount tests if required fields in the ServiceAccount are set.
+func ValidateServiceAccount(serviceAccount *api.ServiceAccount) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMeta(&serviceAccount.ObjectMeta, true, ValidateServiceAccountName).Prefix("metadata")...)
+	for i, secret := range serviceAccount.Secrets {
+		if len(secret.Name) == 0 {
+			allErrs = append(allErrs, errs.NewFieldRequired(fmt.Sprintf("secrets[%d].name", i)))
+		}
+	}
+	return allErrs
+}
+
+// ValidateServiceAccountUpdate tests if required fields in the ServiceAccount are set and
+// that the update is legal for an end user to make.
+func ValidateServiceAccountUpdate(oldServiceAccount, newServiceAccount *api.ServiceAccount) errs.ValidationErrorList {
+	allErrs := errs.ValidationErrorList{}
+	allErrs = append(allErrs, ValidateObjectMetaUpdate(&oldServiceAccount.ObjectMeta, &newServiceAccount.ObjectMeta).Prefix("metadata")...)
+	allErrs = append(allErrs, ValidateServiceAccount(newServiceAccount)...)
 	return allErrs
 }
 
@@ -1043,6 +1864,17 @@ func validateResourceRequirements(container *api.Container) errs.ValidationError
 		}
 		allErrs = append(allErrs, errs...)
 	}
+	for resourceName, quantity := range container.Resources.Requests {
+		requestErrs := validateResourceName(resourceName.String(), fmt.Sprintf("resources.requests[%s]", resourceName))
+		if api.IsStandardResourceName(resourceName.String()) {
+			requestErrs = append(requestErrs, validateBasicResource(quantity).Prefix(fmt.Sprintf("Resource %s: ", resourceName))...)
+		}
+		// A container cannot be guaranteed more than it is allowed to use.
+		if limit, ok := container.Resources.Limits[resourceName]; ok && quantity.MilliValue() > limit.MilliValue() {
+			requestErrs = append(requestErrs, errs.NewFieldInvalid(fmt.Sprintf("resources.requests[%s]", resourceName), quantity.String(), "must be less than or equal to the limit"))
+		}
+		allErrs = append(allErrs, requestErrs...)
+	}
 
 	return allErrs
 }
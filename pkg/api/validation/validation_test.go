@@ -280,6 +280,30 @@ func TestValidatePersistentVolumes(t *testing.T) {
 				},
 			}),
 		},
+		"recycle-policy": {
+			isExpectedFailure: false,
+			volume: testVolume("foo", "", api.PersistentVolumeSpec{
+				Capacity: api.ResourceList{
+					api.ResourceName(api.ResourceStorage): resource.MustParse("10G"),
+				},
+				PersistentVolumeSource: api.PersistentVolumeSource{
+					HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+				},
+				PersistentVolumeReclaimPolicy: api.PersistentVolumeReclaimRecycle,
+			}),
+		},
+		"unsupported-reclaim-policy": {
+			isExpectedFailure: true,
+			volume: testVolume("foo", "", api.PersistentVolumeSpec{
+				Capacity: api.ResourceList{
+					api.ResourceName(api.ResourceStorage): resource.MustParse("10G"),
+				},
+				PersistentVolumeSource: api.PersistentVolumeSource{
+					HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+				},
+				PersistentVolumeReclaimPolicy: "Shred",
+			}),
+		},
 	}
 
 	for name, scenario := range scenarios {
@@ -294,6 +318,34 @@ func TestValidatePersistentVolumes(t *testing.T) {
 
 }
 
+func TestValidatePersistentVolumeStatusUpdate(t *testing.T) {
+	spec := api.PersistentVolumeSpec{
+		Capacity: api.ResourceList{
+			api.ResourceName(api.ResourceStorage): resource.MustParse("10G"),
+		},
+		PersistentVolumeSource: api.PersistentVolumeSource{
+			HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+		},
+	}
+	oldVolume := testVolume("foo", "", spec)
+	oldVolume.ResourceVersion = "1"
+
+	newVolume := testVolume("foo", "", api.PersistentVolumeSpec{})
+	newVolume.ResourceVersion = "1"
+	newVolume.Status.Phase = api.VolumeBound
+	if errs := ValidatePersistentVolumeStatusUpdate(newVolume, oldVolume); len(errs) != 0 {
+		t.Errorf("Unexpected failure: %v", errs)
+	}
+	if !api.Semantic.DeepEqual(newVolume.Spec, spec) {
+		t.Errorf("Expected the spec to be reset to %#v, got %#v", spec, newVolume.Spec)
+	}
+
+	newVolume.Status.Phase = "Lost"
+	if errs := ValidatePersistentVolumeStatusUpdate(newVolume, oldVolume); len(errs) == 0 {
+		t.Errorf("Expected failure for an unsupported phase")
+	}
+}
+
 func testVolumeClaim(name string, namespace string, spec api.PersistentVolumeClaimSpec) *api.PersistentVolumeClaim {
 	return &api.PersistentVolumeClaim{
 		ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace},
@@ -375,12 +427,13 @@ func TestValidateVolumes(t *testing.T) {
 		{Name: "gcepd", VolumeSource: api.VolumeSource{GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{"my-PD", "ext4", 1, false}}},
 		{Name: "gitrepo", VolumeSource: api.VolumeSource{GitRepo: &api.GitRepoVolumeSource{"my-repo", "hashstring"}}},
 		{Name: "secret", VolumeSource: api.VolumeSource{Secret: &api.SecretVolumeSource{"my-secret"}}},
+		{Name: "claim", VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: "my-claim"}}},
 	}
 	names, errs := validateVolumes(successCase)
 	if len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
 	}
-	if len(names) != len(successCase) || !names.HasAll("abc", "123", "abc-123", "empty", "gcepd", "gitrepo", "secret") {
+	if len(names) != len(successCase) || !names.HasAll("abc", "123", "abc-123", "empty", "gcepd", "gitrepo", "secret", "claim") {
 		t.Errorf("wrong names result: %v", names)
 	}
 	emptyVS := api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}
@@ -393,6 +446,7 @@ func TestValidateVolumes(t *testing.T) {
 		"name > 63 characters": {[]api.Volume{{Name: strings.Repeat("a", 64), VolumeSource: emptyVS}}, errors.ValidationErrorTypeInvalid, "[0].name"},
 		"name not a DNS label": {[]api.Volume{{Name: "a.b.c", VolumeSource: emptyVS}}, errors.ValidationErrorTypeInvalid, "[0].name"},
 		"name not unique":      {[]api.Volume{{Name: "abc", VolumeSource: emptyVS}, {Name: "abc", VolumeSource: emptyVS}}, errors.ValidationErrorTypeDuplicate, "[1].name"},
+		"missing claim name":   {[]api.Volume{{Name: "claim", VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{}}}}, errors.ValidationErrorTypeRequired, "[0].source.persistentVolumeClaim.claimName"},
 	}
 	for k, v := range errorCases {
 		_, errs := validateVolumes(v.V)
@@ -479,6 +533,42 @@ func TestValidateEnv(t *testing.T) {
 		{Name: "ABC", Value: "value"},
 		{Name: "AbC_123", Value: "value"},
 		{Name: "abc", Value: ""},
+		{
+			Name: "abc",
+			ValueFrom: &api.EnvVarSource{
+				FieldRef: &api.ObjectFieldSelector{
+					APIVersion: "v1beta3",
+					FieldPath:  "metadata.name",
+				},
+			},
+		},
+		{
+			Name: "POD_IP",
+			ValueFrom: &api.EnvVarSource{
+				FieldRef: &api.ObjectFieldSelector{
+					APIVersion: "v1beta3",
+					FieldPath:  "status.podIP",
+				},
+			},
+		},
+		{
+			Name: "PASSWORD",
+			ValueFrom: &api.EnvVarSource{
+				SecretKeyRef: &api.SecretKeySelector{
+					Name: "a-secret",
+					Key:  "password",
+				},
+			},
+		},
+		{
+			Name: "DOCKERCFG",
+			ValueFrom: &api.EnvVarSource{
+				SecretKeyRef: &api.SecretKeySelector{
+					Name: "a-secret",
+					Key:  api.DockerConfigKey,
+				},
+			},
+		},
 	}
 	if errs := validateEnv(successCase); len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
@@ -502,6 +592,129 @@ func TestValidateEnv(t *testing.T) {
 	}
 }
 
+func TestValidateEnvVarValueFrom(t *testing.T) {
+	errorCases := map[string]struct {
+		envs  []api.EnvVar
+		field string
+	}{
+		"value and valueFrom": {
+			envs: []api.EnvVar{{
+				Name:  "abc",
+				Value: "foo",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+						FieldPath:  "metadata.name",
+					},
+				},
+			}},
+			field: "[0].valueFrom",
+		},
+		"missing source": {
+			envs:  []api.EnvVar{{Name: "abc", ValueFrom: &api.EnvVarSource{}}},
+			field: "[0].valueFrom",
+		},
+		"multiple sources": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+						FieldPath:  "metadata.name",
+					},
+					SecretKeyRef: &api.SecretKeySelector{
+						Name: "a-secret",
+						Key:  "a-key",
+					},
+				},
+			}},
+			field: "[0].valueFrom",
+		},
+		"missing secret name": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					SecretKeyRef: &api.SecretKeySelector{
+						Key: "a-key",
+					},
+				},
+			}},
+			field: "[0].valueFrom.secretKeyRef.name",
+		},
+		"invalid secret key": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					SecretKeyRef: &api.SecretKeySelector{
+						Name: "a-secret",
+						Key:  "A_Key",
+					},
+				},
+			}},
+			field: "[0].valueFrom.secretKeyRef.key",
+		},
+		"secret key with two leading dots": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					SecretKeyRef: &api.SecretKeySelector{
+						Name: "a-secret",
+						Key:  "..dockercfg",
+					},
+				},
+			}},
+			field: "[0].valueFrom.secretKeyRef.key",
+		},
+		"missing apiVersion": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			}},
+			field: "[0].valueFrom.fieldRef.apiVersion",
+		},
+		"missing fieldPath": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+					},
+				},
+			}},
+			field: "[0].valueFrom.fieldRef.fieldPath",
+		},
+		"unsupported fieldPath": {
+			envs: []api.EnvVar{{
+				Name: "abc",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+						FieldPath:  "metadata.labels",
+					},
+				},
+			}},
+			field: "[0].valueFrom.fieldRef.fieldPath",
+		},
+	}
+	for k, v := range errorCases {
+		errs := validateEnv(v.envs)
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+			continue
+		}
+		for i := range errs {
+			field := errs[i].(*errors.ValidationError).Field
+			if field != v.field {
+				t.Errorf("%s: expected errors to have field %s, got %s", k, v.field, field)
+			}
+		}
+	}
+}
+
 func TestValidateVolumeMounts(t *testing.T) {
 	volumes := util.NewStringSet("abc", "123", "abc-123")
 
@@ -532,6 +745,7 @@ func TestValidateProbe(t *testing.T) {
 		nil,
 		{TimeoutSeconds: 10, InitialDelaySeconds: 0, Handler: handler},
 		{TimeoutSeconds: 0, InitialDelaySeconds: 10, Handler: handler},
+		{PeriodSeconds: 10, SuccessThreshold: 2, FailureThreshold: 3, Handler: handler},
 	}
 	for _, p := range successCases {
 		if errs := validateProbe(p); len(errs) != 0 {
@@ -544,6 +758,9 @@ func TestValidateProbe(t *testing.T) {
 		{TimeoutSeconds: 10, InitialDelaySeconds: -10, Handler: handler},
 		{TimeoutSeconds: -10, InitialDelaySeconds: 10, Handler: handler},
 		{TimeoutSeconds: -10, InitialDelaySeconds: -10, Handler: handler},
+		{PeriodSeconds: -1, Handler: handler},
+		{SuccessThreshold: -1, Handler: handler},
+		{FailureThreshold: -1, Handler: handler},
 	}
 	for _, p := range errorCases {
 		if errs := validateProbe(p); len(errs) == 0 {
@@ -552,6 +769,30 @@ func TestValidateProbe(t *testing.T) {
 	}
 }
 
+func TestValidateLivenessProbe(t *testing.T) {
+	handler := api.Handler{Exec: &api.ExecAction{Command: []string{"echo"}}}
+	successCases := []*api.Probe{
+		nil,
+		{Handler: handler},
+		{SuccessThreshold: 1, FailureThreshold: 3, Handler: handler},
+	}
+	for _, p := range successCases {
+		if errs := validateLivenessProbe(p); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := []*api.Probe{
+		{SuccessThreshold: 2, Handler: handler},
+		{FailureThreshold: -1, Handler: handler},
+	}
+	for _, p := range errorCases {
+		if errs := validateLivenessProbe(p); len(errs) == 0 {
+			t.Errorf("expected failure for %v", p)
+		}
+	}
+}
+
 func TestValidateHandler(t *testing.T) {
 	successCases := []api.Handler{
 		{Exec: &api.ExecAction{Command: []string{"echo"}}},
@@ -632,6 +873,8 @@ func getResourceLimits(cpu, memory string) api.ResourceList {
 
 func TestValidateContainers(t *testing.T) {
 	volumes := util.StringSet{}
+	runAsUser := int64(1000)
+	badRunAsUser := int64(-1)
 	capabilities.SetForTests(capabilities.Capabilities{
 		AllowPrivileged: true,
 	})
@@ -662,7 +905,31 @@ func TestValidateContainers(t *testing.T) {
 			},
 			ImagePullPolicy: "IfNotPresent",
 		},
+		{
+			Name:  "resources-request-limit-test",
+			Image: "image",
+			Resources: api.ResourceRequirements{
+				Requests: api.ResourceList{
+					api.ResourceName(api.ResourceCPU):    resource.MustParse("500m"),
+					api.ResourceName(api.ResourceMemory): resource.MustParse("1G"),
+				},
+				Limits: api.ResourceList{
+					api.ResourceName(api.ResourceCPU): resource.MustParse("1"),
+				},
+			},
+			ImagePullPolicy: "IfNotPresent",
+		},
 		{Name: "abc-1234", Image: "image", Privileged: true, ImagePullPolicy: "IfNotPresent"},
+		{
+			Name:  "security-context",
+			Image: "image",
+			SecurityContext: &api.SecurityContext{
+				RunAsUser:              &runAsUser,
+				SELinuxOptions:         &api.SELinuxOptions{User: "user", Role: "role", Type: "type", Level: "level"},
+				ReadOnlyRootFilesystem: true,
+			},
+			ImagePullPolicy: "IfNotPresent",
+		},
 	}
 	if errs := validateContainers(successCase, volumes); len(errs) != 0 {
 		t.Errorf("expected success: %v", errs)
@@ -689,6 +956,9 @@ func TestValidateContainers(t *testing.T) {
 		"invalid env var name": {
 			{Name: "abc", Image: "image", Env: []api.EnvVar{{Name: "ev.1"}}, ImagePullPolicy: "IfNotPresent"},
 		},
+		"negative run as user": {
+			{Name: "abc", Image: "image", SecurityContext: &api.SecurityContext{RunAsUser: &badRunAsUser}, ImagePullPolicy: "IfNotPresent"},
+		},
 		"unknown volume name": {
 			{Name: "abc", Image: "image", VolumeMounts: []api.VolumeMount{{Name: "anything", MountPath: "/foo"}},
 				ImagePullPolicy: "IfNotPresent"},
@@ -810,6 +1080,27 @@ func TestValidateContainers(t *testing.T) {
 				ImagePullPolicy: "IfNotPresent",
 			},
 		},
+		"Request invalid": {
+			{
+				Name:  "abc-123",
+				Image: "image",
+				Resources: api.ResourceRequirements{
+					Requests: getResourceLimits("-10", "0"),
+				},
+				ImagePullPolicy: "IfNotPresent",
+			},
+		},
+		"Request over limit": {
+			{
+				Name:  "abc-123",
+				Image: "image",
+				Resources: api.ResourceRequirements{
+					Requests: getResourceLimits("2", "10G"),
+					Limits:   getResourceLimits("1", "10G"),
+				},
+				ImagePullPolicy: "IfNotPresent",
+			},
+		},
 	}
 	for k, v := range errorCases {
 		if errs := validateContainers(v, volumes); len(errs) == 0 {
@@ -856,6 +1147,10 @@ func TestValidateDNSPolicy(t *testing.T) {
 }
 
 func TestValidatePodSpec(t *testing.T) {
+	activeDeadlineSeconds := int64(30)
+	badActiveDeadlineSeconds := int64(0)
+	zeroTerminationGracePeriodSeconds := int64(0)
+	badTerminationGracePeriodSeconds := int64(-1)
 	successCases := []api.PodSpec{
 		{ // Populate basic fields, leave defaults for most.
 			Volumes:       []api.Volume{{Name: "vol", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}}},
@@ -885,6 +1180,56 @@ func TestValidatePodSpec(t *testing.T) {
 			RestartPolicy: api.RestartPolicyAlways,
 			DNSPolicy:     api.DNSClusterFirst,
 		},
+		{ // Populate ServiceAccount.
+			Containers:     []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:  api.RestartPolicyAlways,
+			DNSPolicy:      api.DNSClusterFirst,
+			ServiceAccount: "default",
+		},
+		{ // Populate ActiveDeadlineSeconds.
+			Containers:            []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:         api.RestartPolicyAlways,
+			DNSPolicy:             api.DNSClusterFirst,
+			ActiveDeadlineSeconds: &activeDeadlineSeconds,
+		},
+		{ // Populate TerminationGracePeriodSeconds.
+			Containers:                    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:                 api.RestartPolicyAlways,
+			DNSPolicy:                     api.DNSClusterFirst,
+			TerminationGracePeriodSeconds: &zeroTerminationGracePeriodSeconds,
+		},
+		{ // Populate ImagePullSecrets.
+			Containers:       []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:    api.RestartPolicyAlways,
+			DNSPolicy:        api.DNSClusterFirst,
+			ImagePullSecrets: []api.LocalObjectReference{{Name: "registry-creds"}},
+		},
+		{ // Populate Tolerations.
+			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Tolerations:   []api.Toleration{{Key: "dedicated", Value: "team-a", Effect: api.TaintEffectNoSchedule}},
+		},
+		{ // Populate Affinity.
+			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					Required: &api.NodeSelector{
+						NodeSelectorTerms: []api.NodeSelectorTerm{
+							{MatchExpressions: []api.NodeSelectorRequirement{{Key: "memory", Operator: api.NodeSelectorOpIn, Values: []string{"high"}}}},
+						},
+					},
+				},
+			},
+		},
+		{ // Populate SchedulerName.
+			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			SchedulerName: "batch-scheduler",
+		},
 	}
 	for i := range successCases {
 		if errs := ValidatePodSpec(&successCases[i]); len(errs) != 0 {
@@ -918,6 +1263,12 @@ func TestValidatePodSpec(t *testing.T) {
 			DNSPolicy:     api.DNSClusterFirst,
 			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
 		},
+		"bad scheduler name": {
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			SchedulerName: "Batch_Scheduler",
+		},
 		"with hostNetwork hostPort not equal to containerPort": {
 			Containers: []api.Container{
 				{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent", Ports: []api.ContainerPort{
@@ -928,6 +1279,52 @@ func TestValidatePodSpec(t *testing.T) {
 			RestartPolicy: api.RestartPolicyAlways,
 			DNSPolicy:     api.DNSClusterFirst,
 		},
+		"bad service account name": {
+			Containers:     []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:  api.RestartPolicyAlways,
+			DNSPolicy:      api.DNSClusterFirst,
+			ServiceAccount: "Not_Valid",
+		},
+		"bad activeDeadlineSeconds": {
+			Containers:            []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:         api.RestartPolicyAlways,
+			DNSPolicy:             api.DNSClusterFirst,
+			ActiveDeadlineSeconds: &badActiveDeadlineSeconds,
+		},
+		"bad terminationGracePeriodSeconds": {
+			Containers:                    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:                 api.RestartPolicyAlways,
+			DNSPolicy:                     api.DNSClusterFirst,
+			TerminationGracePeriodSeconds: &badTerminationGracePeriodSeconds,
+		},
+		"empty imagePullSecrets name": {
+			Containers:       []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:    api.RestartPolicyAlways,
+			DNSPolicy:        api.DNSClusterFirst,
+			ImagePullSecrets: []api.LocalObjectReference{{}},
+		},
+		"bad imagePullSecrets name": {
+			Containers:       []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy:    api.RestartPolicyAlways,
+			DNSPolicy:        api.DNSClusterFirst,
+			ImagePullSecrets: []api.LocalObjectReference{{Name: "Not_Valid"}},
+		},
+		"bad toleration": {
+			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Tolerations:   []api.Toleration{{Key: "a", Operator: api.TolerationOpExists, Value: "b"}},
+		},
+		"bad node affinity": {
+			Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Affinity: &api.Affinity{
+				NodeAffinity: &api.NodeAffinity{
+					Preferred: []api.PreferredSchedulingTerm{{Weight: 0}},
+				},
+			},
+		},
 	}
 	for k, v := range failureCases {
 		if errs := ValidatePodSpec(&v); len(errs) == 0 {
@@ -1306,17 +1703,64 @@ func TestValidateService(t *testing.T) {
 			},
 			numErrs: 1,
 		},
+		{
+			name: "missing type",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = ""
+			},
+			numErrs: 1,
+		},
+		{
+			name: "invalid type",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = "Bogus"
+			},
+			numErrs: 1,
+		},
+		{
+			name: "node port on ClusterIP service",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].NodePort = 30123
+			},
+			numErrs: 1,
+		},
+		{
+			name: "invalid node port",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = api.ServiceTypeNodePort
+				s.Spec.Ports[0].NodePort = 65536
+			},
+			numErrs: 1,
+		},
+		{
+			name: "duplicate node port",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = api.ServiceTypeNodePort
+				s.Spec.Ports[0].Name = "a"
+				s.Spec.Ports[0].NodePort = 30123
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "b", Port: 81, Protocol: "TCP", NodePort: 30123})
+			},
+			numErrs: 1,
+		},
+		{
+			name: "headless NodePort service",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = api.ServiceTypeNodePort
+				s.Spec.PortalIP = "None"
+			},
+			numErrs: 1,
+		},
 		{
 			name: "missing protocol",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Protocol = ""
+				s.Spec.Ports[0].Protocol = ""
 			},
 			numErrs: 1,
 		},
 		{
 			name: "invalid protocol",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Protocol = "INVALID"
+				s.Spec.Ports[0].Protocol = "INVALID"
 			},
 			numErrs: 1,
 		},
@@ -1330,28 +1774,57 @@ func TestValidateService(t *testing.T) {
 		{
 			name: "missing port",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Port = 0
+				s.Spec.Ports[0].Port = 0
 			},
 			numErrs: 1,
 		},
 		{
 			name: "invalid port",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Port = 65536
+				s.Spec.Ports[0].Port = 65536
 			},
 			numErrs: 1,
 		},
 		{
 			name: "missing targetPort string",
 			makeSvc: func(s *api.Service) {
-				s.Spec.TargetPort = util.NewIntOrStringFromString("")
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromString("")
 			},
 			numErrs: 1,
 		},
 		{
 			name: "invalid targetPort int",
 			makeSvc: func(s *api.Service) {
-				s.Spec.TargetPort = util.NewIntOrStringFromInt(65536)
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromInt(65536)
+			},
+			numErrs: 1,
+		},
+		{
+			name: "missing ports",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports = nil
+			},
+			numErrs: 1,
+		},
+		{
+			name: "missing port name with multiple ports",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "p", Port: 12345, Protocol: "TCP"})
+			},
+			numErrs: 1,
+		},
+		{
+			name: "invalid port name",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].Name = "INVALID"
+			},
+			numErrs: 1,
+		},
+		{
+			name: "dup port name",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].Name = "p"
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "p", Port: 12345, Protocol: "TCP"})
 			},
 			numErrs: 1,
 		},
@@ -1393,15 +1866,38 @@ func TestValidateService(t *testing.T) {
 		{
 			name: "valid 2",
 			makeSvc: func(s *api.Service) {
-				s.Spec.Protocol = "UDP"
-				s.Spec.TargetPort = util.NewIntOrStringFromInt(12345)
+				s.Spec.Ports[0].Protocol = "UDP"
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromInt(12345)
 			},
 			numErrs: 0,
 		},
 		{
 			name: "valid 3",
 			makeSvc: func(s *api.Service) {
-				s.Spec.TargetPort = util.NewIntOrStringFromString("http")
+				s.Spec.Ports[0].TargetPort = util.NewIntOrStringFromString("http")
+			},
+			numErrs: 0,
+		},
+		{
+			name: "valid named ports",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Ports[0].Name = "http"
+				s.Spec.Ports = append(s.Spec.Ports, api.ServicePort{Name: "dns", Port: 53, Protocol: "UDP", TargetPort: util.NewIntOrStringFromString("dns")})
+			},
+			numErrs: 0,
+		},
+		{
+			name: "valid NodePort service",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = api.ServiceTypeNodePort
+				s.Spec.Ports[0].NodePort = 30123
+			},
+			numErrs: 0,
+		},
+		{
+			name: "valid NodePort service - unallocated",
+			makeSvc: func(s *api.Service) {
+				s.Spec.Type = api.ServiceTypeNodePort
 			},
 			numErrs: 0,
 		},
@@ -1430,10 +1926,10 @@ func TestValidateService(t *testing.T) {
 				Annotations: map[string]string{},
 			},
 			Spec: api.ServiceSpec{
+				Ports:           []api.ServicePort{{Port: 8675, Protocol: "TCP"}},
 				Selector:        map[string]string{"key": "val"},
 				SessionAffinity: "None",
-				Port:            8675,
-				Protocol:        "TCP",
+				Type:            api.ServiceTypeClusterIP,
 			},
 		}
 		tc.makeSvc(&svc)
@@ -1813,22 +2309,673 @@ func TestValidateReplicationController(t *testing.T) {
 	}
 }
 
-func TestValidateMinion(t *testing.T) {
+func TestValidateJob(t *testing.T) {
 	validSelector := map[string]string{"a": "b"}
-	invalidSelector := map[string]string{"NoUppercaseOrSpecialCharsLike=Equals": "b"}
-	successCases := []api.Node{
+	validPodTemplateSpec := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels: validSelector,
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicyOnFailure,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+		},
+	}
+	successCases := []api.Job{
 		{
-			ObjectMeta: api.ObjectMeta{
-				Name:   "abc",
-				Labels: validSelector,
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Completions: 1,
+				Parallelism: 1,
+				Selector:    validSelector,
+				Template:    &validPodTemplateSpec,
 			},
-			Status: api.NodeStatus{
-				Addresses: []api.NodeAddress{
-					{Type: api.NodeLegacyHostIP, Address: "something"},
-				},
-				Capacity: api.ResourceList{
-					api.ResourceName(api.ResourceCPU):    resource.MustParse("10"),
-					api.ResourceName(api.ResourceMemory): resource.MustParse("10G"),
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc-123", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Completions: 5,
+				Parallelism: 2,
+				Selector:    validSelector,
+				Template: &api.PodTemplateSpec{
+					ObjectMeta: api.ObjectMeta{
+						Labels: validSelector,
+					},
+					Spec: api.PodSpec{
+						RestartPolicy: api.RestartPolicyNever,
+						DNSPolicy:     api.DNSClusterFirst,
+						Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+					},
+				},
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateJob(&successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]api.Job{
+		"zero-length name": {
+			ObjectMeta: api.ObjectMeta{Name: "", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"missing-namespace": {
+			ObjectMeta: api.ObjectMeta{Name: "abc-123"},
+			Spec: api.JobSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"empty selector": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"selector_doesnt_match": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Selector: map[string]string{"foo": "bar"},
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"missing template": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Selector: validSelector,
+			},
+		},
+		"negative_completions": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Completions: -1,
+				Selector:    validSelector,
+				Template:    &validPodTemplateSpec,
+			},
+		},
+		"negative_parallelism": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Parallelism: -1,
+				Selector:    validSelector,
+				Template:    &validPodTemplateSpec,
+			},
+		},
+		"invalid restart policy": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.JobSpec{
+				Selector: validSelector,
+				Template: &api.PodTemplateSpec{
+					ObjectMeta: api.ObjectMeta{
+						Labels: validSelector,
+					},
+					Spec: api.PodSpec{
+						RestartPolicy: api.RestartPolicyAlways,
+						DNSPolicy:     api.DNSClusterFirst,
+						Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+					},
+				},
+			},
+		},
+	}
+	for k, v := range errorCases {
+		errs := ValidateJob(&v)
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+		for i := range errs {
+			field := errs[i].(*errors.ValidationError).Field
+			if !strings.HasPrefix(field, "spec.template.") &&
+				field != "metadata.name" &&
+				field != "metadata.namespace" &&
+				field != "spec.selector" &&
+				field != "spec.template" &&
+				field != "spec.completions" &&
+				field != "spec.parallelism" {
+				t.Errorf("%s: missing prefix for: %v", k, errs[i])
+			}
+		}
+	}
+}
+
+func TestValidateJobUpdate(t *testing.T) {
+	validSelector := map[string]string{"a": "b"}
+	validPodTemplateSpec := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels: validSelector,
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicyOnFailure,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+		},
+	}
+	oldJob := api.Job{
+		ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault, ResourceVersion: "1"},
+		Spec: api.JobSpec{
+			Completions: 2,
+			Parallelism: 1,
+			Selector:    validSelector,
+			Template:    &validPodTemplateSpec,
+		},
+	}
+
+	scaled := oldJob
+	scaled.Spec.Parallelism = 2
+	if errs := ValidateJobUpdate(&oldJob, &scaled); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	changedCompletions := oldJob
+	changedCompletions.Spec.Completions = 3
+	if errs := ValidateJobUpdate(&oldJob, &changedCompletions); len(errs) == 0 {
+		t.Errorf("expected failure when changing completions")
+	}
+
+	changedSelector := oldJob
+	changedSelector.Spec.Selector = map[string]string{"a": "b", "c": "d"}
+	if errs := ValidateJobUpdate(&oldJob, &changedSelector); len(errs) == 0 {
+		t.Errorf("expected failure when changing selector")
+	}
+}
+
+func TestValidateDaemonSet(t *testing.T) {
+	validSelector := map[string]string{"a": "b"}
+	validPodTemplateSpec := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels: validSelector,
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+		},
+	}
+	successCases := []api.DaemonSet{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+			},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc-123", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector:     validSelector,
+				NodeSelector: map[string]string{"role": "logging"},
+				Template:     &validPodTemplateSpec,
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateDaemonSet(&successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]api.DaemonSet{
+		"zero-length name": {
+			ObjectMeta: api.ObjectMeta{Name: "", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"missing-namespace": {
+			ObjectMeta: api.ObjectMeta{Name: "abc-123"},
+			Spec: api.DaemonSetSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"empty selector": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"selector_doesnt_match": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector: map[string]string{"foo": "bar"},
+				Template: &validPodTemplateSpec,
+			},
+		},
+		"missing template": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector: validSelector,
+			},
+		},
+		"invalid node selector": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector:     validSelector,
+				NodeSelector: map[string]string{"NoUppercaseOrSpecialCharsLike=Equals": "b"},
+				Template:     &validPodTemplateSpec,
+			},
+		},
+		"invalid restart policy": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector: validSelector,
+				Template: &api.PodTemplateSpec{
+					ObjectMeta: api.ObjectMeta{
+						Labels: validSelector,
+					},
+					Spec: api.PodSpec{
+						RestartPolicy: api.RestartPolicyOnFailure,
+						DNSPolicy:     api.DNSClusterFirst,
+						Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+					},
+				},
+			},
+		},
+		"template with host": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DaemonSetSpec{
+				Selector: validSelector,
+				Template: &api.PodTemplateSpec{
+					ObjectMeta: api.ObjectMeta{
+						Labels: validSelector,
+					},
+					Spec: api.PodSpec{
+						Host:          "node-1",
+						RestartPolicy: api.RestartPolicyAlways,
+						DNSPolicy:     api.DNSClusterFirst,
+						Containers:    []api.Container{{Name: "ctr", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+					},
+				},
+			},
+		},
+	}
+	for k, v := range errorCases {
+		errs := ValidateDaemonSet(&v)
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+		for i := range errs {
+			field := errs[i].(*errors.ValidationError).Field
+			if !strings.HasPrefix(field, "spec.template.") &&
+				!strings.HasPrefix(field, "spec.nodeSelector") &&
+				field != "metadata.name" &&
+				field != "metadata.namespace" &&
+				field != "spec.selector" &&
+				field != "spec.template" {
+				t.Errorf("%s: missing prefix for: %v", k, errs[i])
+			}
+		}
+	}
+}
+
+func TestValidateDaemonSetUpdate(t *testing.T) {
+	validSelector := map[string]string{"a": "b"}
+	validPodTemplateSpec := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels: validSelector,
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+		},
+	}
+	oldDS := api.DaemonSet{
+		ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault, ResourceVersion: "1"},
+		Spec: api.DaemonSetSpec{
+			Selector: validSelector,
+			Template: &validPodTemplateSpec,
+		},
+	}
+
+	retargeted := oldDS
+	retargeted.Spec.NodeSelector = map[string]string{"role": "logging"}
+	if errs := ValidateDaemonSetUpdate(&oldDS, &retargeted); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	changedSelector := oldDS
+	changedSelector.Spec.Selector = map[string]string{"a": "b", "c": "d"}
+	if errs := ValidateDaemonSetUpdate(&oldDS, &changedSelector); len(errs) == 0 {
+		t.Errorf("expected failure when changing selector")
+	}
+}
+
+func validDeploymentStrategy() api.DeploymentStrategy {
+	return api.DeploymentStrategy{
+		Type: api.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &api.RollingUpdateDeployment{
+			MaxUnavailable: util.NewIntOrStringFromInt(1),
+			MaxSurge:       util.NewIntOrStringFromString("25%"),
+		},
+	}
+}
+
+func TestValidateDeployment(t *testing.T) {
+	validSelector := map[string]string{"a": "b"}
+	validPodTemplateSpec := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels: validSelector,
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+		},
+	}
+	successCases := []api.Deployment{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Replicas: 3,
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "abc-123", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector:   validSelector,
+				Template:   &validPodTemplateSpec,
+				Strategy:   api.DeploymentStrategy{Type: api.RecreateDeploymentStrategyType},
+				Paused:     true,
+				RollbackTo: &api.RollbackConfig{Revision: 2},
+			},
+		},
+	}
+	for _, successCase := range successCases {
+		if errs := ValidateDeployment(&successCase); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]api.Deployment{
+		"zero-length name": {
+			ObjectMeta: api.ObjectMeta{Name: "", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		"missing-namespace": {
+			ObjectMeta: api.ObjectMeta{Name: "abc-123"},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		"negative_replicas": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Replicas: -1,
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		"empty selector": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Template: &validPodTemplateSpec,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		"selector_doesnt_match": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: map[string]string{"foo": "bar"},
+				Template: &validPodTemplateSpec,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		"missing template": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Strategy: validDeploymentStrategy(),
+			},
+		},
+		"invalid strategy type": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: api.DeploymentStrategy{Type: "Abrupt"},
+			},
+		},
+		"rolling update parameters with recreate": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: api.DeploymentStrategy{
+					Type:          api.RecreateDeploymentStrategyType,
+					RollingUpdate: validDeploymentStrategy().RollingUpdate,
+				},
+			},
+		},
+		"missing rolling update parameters": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: api.DeploymentStrategy{Type: api.RollingUpdateDeploymentStrategyType},
+			},
+		},
+		"invalid max unavailable": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: api.DeploymentStrategy{
+					Type: api.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &api.RollingUpdateDeployment{
+						MaxUnavailable: util.NewIntOrStringFromString("110%"),
+						MaxSurge:       util.NewIntOrStringFromInt(1),
+					},
+				},
+			},
+		},
+		"invalid max surge": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: api.DeploymentStrategy{
+					Type: api.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &api.RollingUpdateDeployment{
+						MaxUnavailable: util.NewIntOrStringFromInt(1),
+						MaxSurge:       util.NewIntOrStringFromString("many"),
+					},
+				},
+			},
+		},
+		"zero max unavailable and max surge": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector: validSelector,
+				Template: &validPodTemplateSpec,
+				Strategy: api.DeploymentStrategy{
+					Type: api.RollingUpdateDeploymentStrategyType,
+					RollingUpdate: &api.RollingUpdateDeployment{
+						MaxUnavailable: util.NewIntOrStringFromInt(0),
+						MaxSurge:       util.NewIntOrStringFromString("0%"),
+					},
+				},
+			},
+		},
+		"negative rollback revision": {
+			ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault},
+			Spec: api.DeploymentSpec{
+				Selector:   validSelector,
+				Template:   &validPodTemplateSpec,
+				Strategy:   validDeploymentStrategy(),
+				RollbackTo: &api.RollbackConfig{Revision: -1},
+			},
+		},
+	}
+	for k, v := range errorCases {
+		errs := ValidateDeployment(&v)
+		if len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+		for i := range errs {
+			field := errs[i].(*errors.ValidationError).Field
+			if !strings.HasPrefix(field, "spec.template.") &&
+				!strings.HasPrefix(field, "spec.strategy.") &&
+				field != "metadata.name" &&
+				field != "metadata.namespace" &&
+				field != "spec.replicas" &&
+				field != "spec.selector" &&
+				field != "spec.template" &&
+				field != "spec.rollbackTo.revision" {
+				t.Errorf("%s: missing prefix for: %v", k, errs[i])
+			}
+		}
+	}
+}
+
+func TestValidateDeploymentUpdate(t *testing.T) {
+	validSelector := map[string]string{"a": "b"}
+	validPodTemplateSpec := api.PodTemplateSpec{
+		ObjectMeta: api.ObjectMeta{
+			Labels: validSelector,
+		},
+		Spec: api.PodSpec{
+			RestartPolicy: api.RestartPolicyAlways,
+			DNSPolicy:     api.DNSClusterFirst,
+			Containers:    []api.Container{{Name: "abc", Image: "image", ImagePullPolicy: "IfNotPresent"}},
+		},
+	}
+	oldDeployment := api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "abc", Namespace: api.NamespaceDefault, ResourceVersion: "1"},
+		Spec: api.DeploymentSpec{
+			Replicas: 2,
+			Selector: validSelector,
+			Template: &validPodTemplateSpec,
+			Strategy: validDeploymentStrategy(),
+		},
+	}
+
+	paused := oldDeployment
+	paused.Spec.Replicas = 5
+	paused.Spec.Paused = true
+	if errs := ValidateDeploymentUpdate(&oldDeployment, &paused); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+
+	changedSelector := oldDeployment
+	changedSelector.Spec.Selector = map[string]string{"a": "b", "c": "d"}
+	if errs := ValidateDeploymentUpdate(&oldDeployment, &changedSelector); len(errs) == 0 {
+		t.Errorf("expected failure when changing selector")
+	}
+}
+
+func validHorizontalPodAutoscaler() api.HorizontalPodAutoscaler {
+	return api.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: "frontend", Namespace: api.NamespaceDefault, ResourceVersion: "1"},
+		Spec: api.HorizontalPodAutoscalerSpec{
+			ScaleRef:                       api.LocalObjectReference{Name: "frontend-rc"},
+			MinReplicas:                    1,
+			MaxReplicas:                    5,
+			TargetCPUUtilizationPercentage: 70,
+		},
+	}
+}
+
+func TestValidateHorizontalPodAutoscaler(t *testing.T) {
+	valid := validHorizontalPodAutoscaler()
+	minEqualsMax := validHorizontalPodAutoscaler()
+	minEqualsMax.Spec.MaxReplicas = 1
+	for _, autoscaler := range []api.HorizontalPodAutoscaler{valid, minEqualsMax} {
+		if errs := ValidateHorizontalPodAutoscaler(&autoscaler); len(errs) != 0 {
+			t.Errorf("expected success: %v", errs)
+		}
+	}
+
+	errorCases := map[string]func(*api.HorizontalPodAutoscaler){
+		"missing name":            func(a *api.HorizontalPodAutoscaler) { a.Name = "" },
+		"missing scaleRef":        func(a *api.HorizontalPodAutoscaler) { a.Spec.ScaleRef.Name = "" },
+		"invalid scaleRef":        func(a *api.HorizontalPodAutoscaler) { a.Spec.ScaleRef.Name = "Not_Valid" },
+		"zero minReplicas":        func(a *api.HorizontalPodAutoscaler) { a.Spec.MinReplicas = 0 },
+		"maxReplicas below min":   func(a *api.HorizontalPodAutoscaler) { a.Spec.MinReplicas = 3; a.Spec.MaxReplicas = 2 },
+		"zero target utilization": func(a *api.HorizontalPodAutoscaler) { a.Spec.TargetCPUUtilizationPercentage = 0 },
+	}
+	for name, mutate := range errorCases {
+		autoscaler := validHorizontalPodAutoscaler()
+		mutate(&autoscaler)
+		if errs := ValidateHorizontalPodAutoscaler(&autoscaler); len(errs) == 0 {
+			t.Errorf("%s: expected failure", name)
+		}
+	}
+}
+
+func TestValidateHorizontalPodAutoscalerUpdate(t *testing.T) {
+	oldAutoscaler := validHorizontalPodAutoscaler()
+	oldAutoscaler.Status.CurrentReplicas = 2
+
+	resized := validHorizontalPodAutoscaler()
+	resized.Spec.MaxReplicas = 10
+	if errs := ValidateHorizontalPodAutoscalerUpdate(&oldAutoscaler, &resized); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	if resized.Status.CurrentReplicas != 2 {
+		t.Errorf("expected the status to be preserved, got %#v", resized.Status)
+	}
+
+	invalid := validHorizontalPodAutoscaler()
+	invalid.Spec.MaxReplicas = 0
+	if errs := ValidateHorizontalPodAutoscalerUpdate(&oldAutoscaler, &invalid); len(errs) == 0 {
+		t.Errorf("expected failure for an invalid spec")
+	}
+}
+
+func TestValidateHorizontalPodAutoscalerStatusUpdate(t *testing.T) {
+	oldAutoscaler := validHorizontalPodAutoscaler()
+	utilization := 50
+
+	newAutoscaler := validHorizontalPodAutoscaler()
+	newAutoscaler.Spec.MaxReplicas = 10
+	newAutoscaler.Status = api.HorizontalPodAutoscalerStatus{CurrentReplicas: 2, DesiredReplicas: 3, CurrentCPUUtilizationPercentage: &utilization}
+	if errs := ValidateHorizontalPodAutoscalerStatusUpdate(&newAutoscaler, &oldAutoscaler); len(errs) != 0 {
+		t.Errorf("expected success: %v", errs)
+	}
+	if newAutoscaler.Spec.MaxReplicas != oldAutoscaler.Spec.MaxReplicas {
+		t.Errorf("expected the spec to be preserved, got %#v", newAutoscaler.Spec)
+	}
+
+	negative := validHorizontalPodAutoscaler()
+	negative.Status.DesiredReplicas = -1
+	if errs := ValidateHorizontalPodAutoscalerStatusUpdate(&negative, &oldAutoscaler); len(errs) == 0 {
+		t.Errorf("expected failure for negative desired replicas")
+	}
+}
+
+func TestValidateMinion(t *testing.T) {
+	validSelector := map[string]string{"a": "b"}
+	invalidSelector := map[string]string{"NoUppercaseOrSpecialCharsLike=Equals": "b"}
+	successCases := []api.Node{
+		{
+			ObjectMeta: api.ObjectMeta{
+				Name:   "abc",
+				Labels: validSelector,
+			},
+			Status: api.NodeStatus{
+				Addresses: []api.NodeAddress{
+					{Type: api.NodeLegacyHostIP, Address: "something"},
+				},
+				Capacity: api.ResourceList{
+					api.ResourceName(api.ResourceCPU):    resource.MustParse("10"),
+					api.ResourceName(api.ResourceMemory): resource.MustParse("10G"),
 					api.ResourceName("my.org/gpu"):       resource.MustParse("10"),
 				},
 			},
@@ -1926,6 +3073,148 @@ func TestValidateMinion(t *testing.T) {
 	}
 }
 
+func TestValidateTaints(t *testing.T) {
+	successCases := [][]api.Taint{
+		{},
+		{{Key: "dedicated", Value: "team-a", Effect: api.TaintEffectNoSchedule}},
+		{{Key: "example.com/maintenance", Effect: api.TaintEffectNoSchedule}},
+		{
+			{Key: "dedicated", Value: "team-a", Effect: api.TaintEffectNoSchedule},
+			{Key: "gpu", Value: "none", Effect: api.TaintEffectNoSchedule},
+		},
+	}
+	for i, taints := range successCases {
+		if errs := validateTaints(taints); len(errs) != 0 {
+			t.Errorf("%d: expected success: %v", i, errs)
+		}
+	}
+
+	errorCases := map[string][]api.Taint{
+		"missing key":    {{Value: "a", Effect: api.TaintEffectNoSchedule}},
+		"invalid key":    {{Key: "a b", Effect: api.TaintEffectNoSchedule}},
+		"invalid value":  {{Key: "a", Value: "a b", Effect: api.TaintEffectNoSchedule}},
+		"missing effect": {{Key: "a"}},
+		"invalid effect": {{Key: "a", Effect: "NoExecute"}},
+		"duplicate": {
+			{Key: "a", Value: "b", Effect: api.TaintEffectNoSchedule},
+			{Key: "a", Value: "c", Effect: api.TaintEffectNoSchedule},
+		},
+	}
+	for k, taints := range errorCases {
+		if errs := validateTaints(taints); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
+}
+
+func TestValidateTolerations(t *testing.T) {
+	successCases := [][]api.Toleration{
+		{},
+		{{Key: "dedicated", Value: "team-a"}},
+		{{Key: "dedicated", Operator: api.TolerationOpEqual, Value: "team-a", Effect: api.TaintEffectNoSchedule}},
+		{{Key: "example.com/maintenance", Operator: api.TolerationOpExists}},
+	}
+	for i, tolerations := range successCases {
+		if errs := validateTolerations(tolerations); len(errs) != 0 {
+			t.Errorf("%d: expected success: %v", i, errs)
+		}
+	}
+
+	errorCases := map[string][]api.Toleration{
+		"missing key":          {{Value: "a"}},
+		"invalid key":          {{Key: "a b"}},
+		"invalid value":        {{Key: "a", Value: "a b"}},
+		"value with Exists":    {{Key: "a", Operator: api.TolerationOpExists, Value: "b"}},
+		"unsupported operator": {{Key: "a", Operator: "In"}},
+		"unsupported effect":   {{Key: "a", Effect: "NoExecute"}},
+	}
+	for k, tolerations := range errorCases {
+		if errs := validateTolerations(tolerations); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
+}
+
+func TestValidateNodeAffinity(t *testing.T) {
+	term := func(exprs ...api.NodeSelectorRequirement) api.NodeSelectorTerm {
+		return api.NodeSelectorTerm{MatchExpressions: exprs}
+	}
+	required := func(terms ...api.NodeSelectorTerm) *api.NodeSelector {
+		return &api.NodeSelector{NodeSelectorTerms: terms}
+	}
+	in := api.NodeSelectorRequirement{Key: "memory", Operator: api.NodeSelectorOpIn, Values: []string{"high"}}
+	notIn := api.NodeSelectorRequirement{Key: "example.com/gpu", Operator: api.NodeSelectorOpNotIn, Values: []string{"k80", "p100"}}
+	exists := api.NodeSelectorRequirement{Key: "ssd", Operator: api.NodeSelectorOpExists}
+
+	successCases := []api.NodeAffinity{
+		{},
+		{Required: required(term(in, notIn), term(exists))},
+		{Preferred: []api.PreferredSchedulingTerm{{Weight: 1, Preference: term(in)}, {Weight: 100, Preference: term(exists)}}},
+		{Required: required(term(notIn)), Preferred: []api.PreferredSchedulingTerm{{Weight: 10, Preference: term(exists)}}},
+	}
+	for i := range successCases {
+		if errs := validateNodeAffinity(&successCases[i]); len(errs) != 0 {
+			t.Errorf("%d: expected success: %v", i, errs)
+		}
+	}
+
+	errorCases := map[string]api.NodeAffinity{
+		"no required terms":      {Required: required()},
+		"no match expressions":   {Required: required(term())},
+		"missing key":            {Required: required(term(api.NodeSelectorRequirement{Operator: api.NodeSelectorOpExists}))},
+		"invalid key":            {Required: required(term(api.NodeSelectorRequirement{Key: "a b", Operator: api.NodeSelectorOpExists}))},
+		"missing operator":       {Required: required(term(api.NodeSelectorRequirement{Key: "a", Values: []string{"b"}}))},
+		"unsupported operator":   {Required: required(term(api.NodeSelectorRequirement{Key: "a", Operator: "Gt", Values: []string{"1"}}))},
+		"In without values":      {Required: required(term(api.NodeSelectorRequirement{Key: "a", Operator: api.NodeSelectorOpIn}))},
+		"Exists with values":     {Required: required(term(api.NodeSelectorRequirement{Key: "a", Operator: api.NodeSelectorOpExists, Values: []string{"b"}}))},
+		"invalid value":          {Required: required(term(api.NodeSelectorRequirement{Key: "a", Operator: api.NodeSelectorOpIn, Values: []string{"b c"}}))},
+		"zero weight":            {Preferred: []api.PreferredSchedulingTerm{{Preference: term(in)}}},
+		"weight over 100":        {Preferred: []api.PreferredSchedulingTerm{{Weight: 101, Preference: term(in)}}},
+		"empty preferred term":   {Preferred: []api.PreferredSchedulingTerm{{Weight: 1}}},
+		"bad preferred operator": {Preferred: []api.PreferredSchedulingTerm{{Weight: 1, Preference: term(api.NodeSelectorRequirement{Key: "a", Operator: "Lt"})}}},
+	}
+	for k, affinity := range errorCases {
+		if errs := validateNodeAffinity(&affinity); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
+}
+
+func TestValidatePodAffinity(t *testing.T) {
+	term := api.PodAffinityTerm{LabelSelector: map[string]string{"app": "db"}, TopologyKey: "kubernetes.io/hostname"}
+	successCases := []api.Affinity{
+		{},
+		{PodAffinity: &api.PodAffinity{Required: []api.PodAffinityTerm{term}}},
+		{PodAntiAffinity: &api.PodAntiAffinity{Required: []api.PodAffinityTerm{term}}},
+		{PodAffinity: &api.PodAffinity{Preferred: []api.WeightedPodAffinityTerm{{Weight: 100, PodAffinityTerm: term}}}},
+		{PodAntiAffinity: &api.PodAntiAffinity{Preferred: []api.WeightedPodAffinityTerm{{
+			Weight:          1,
+			PodAffinityTerm: api.PodAffinityTerm{LabelSelector: map[string]string{"app": "cache"}, Namespaces: []string{"default", "frontend"}, TopologyKey: "zone"},
+		}}}},
+	}
+	for i := range successCases {
+		if errs := validateAffinity(&successCases[i]); len(errs) != 0 {
+			t.Errorf("%d: expected success: %v", i, errs)
+		}
+	}
+
+	errorCases := map[string]api.Affinity{
+		"missing label selector": {PodAffinity: &api.PodAffinity{Required: []api.PodAffinityTerm{{TopologyKey: "zone"}}}},
+		"invalid label selector": {PodAffinity: &api.PodAffinity{Required: []api.PodAffinityTerm{{LabelSelector: map[string]string{"a b": "c"}, TopologyKey: "zone"}}}},
+		"missing topology key":   {PodAntiAffinity: &api.PodAntiAffinity{Required: []api.PodAffinityTerm{{LabelSelector: map[string]string{"app": "db"}}}}},
+		"invalid topology key":   {PodAntiAffinity: &api.PodAntiAffinity{Required: []api.PodAffinityTerm{{LabelSelector: map[string]string{"app": "db"}, TopologyKey: "a b"}}}},
+		"invalid namespace":      {PodAffinity: &api.PodAffinity{Required: []api.PodAffinityTerm{{LabelSelector: map[string]string{"app": "db"}, Namespaces: []string{"Foo"}, TopologyKey: "zone"}}}},
+		"zero weight":            {PodAffinity: &api.PodAffinity{Preferred: []api.WeightedPodAffinityTerm{{PodAffinityTerm: term}}}},
+		"weight over 100":        {PodAntiAffinity: &api.PodAntiAffinity{Preferred: []api.WeightedPodAffinityTerm{{Weight: 101, PodAffinityTerm: term}}}},
+		"invalid preferred term": {PodAntiAffinity: &api.PodAntiAffinity{Preferred: []api.WeightedPodAffinityTerm{{Weight: 1}}}},
+	}
+	for k, affinity := range errorCases {
+		if errs := validateAffinity(&affinity); len(errs) == 0 {
+			t.Errorf("expected failure for %s", k)
+		}
+	}
+}
+
 func TestValidateMinionUpdate(t *testing.T) {
 	tests := []struct {
 		oldMinion api.Node
@@ -2058,6 +3347,30 @@ func TestValidateMinionUpdate(t *testing.T) {
 				Unschedulable: true,
 			},
 		}, true},
+		{api.Node{
+			ObjectMeta: api.ObjectMeta{
+				Name: "foo",
+			},
+		}, api.Node{
+			ObjectMeta: api.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: api.NodeSpec{
+				Taints: []api.Taint{{Key: "dedicated", Value: "team-a", Effect: api.TaintEffectNoSchedule}},
+			},
+		}, true},
+		{api.Node{
+			ObjectMeta: api.ObjectMeta{
+				Name: "foo",
+			},
+		}, api.Node{
+			ObjectMeta: api.ObjectMeta{
+				Name: "foo",
+			},
+			Spec: api.NodeSpec{
+				Taints: []api.Taint{{Key: "dedicated", Value: "team-a"}},
+			},
+		}, false},
 	}
 	for i, test := range tests {
 		test.oldMinion.ObjectMeta.ResourceVersion = "1"
@@ -2636,12 +3949,17 @@ func TestValidateSecret(t *testing.T) {
 	}
 
 	var (
-		emptyName   = validSecret()
-		invalidName = validSecret()
-		emptyNs     = validSecret()
-		invalidNs   = validSecret()
-		overMaxSize = validSecret()
-		invalidKey  = validSecret()
+		emptyName    = validSecret()
+		invalidName  = validSecret()
+		emptyNs      = validSecret()
+		invalidNs    = validSecret()
+		overMaxSize  = validSecret()
+		invalidKey   = validSecret()
+		tokenSecret  = validSecret()
+		noTokenName  = validSecret()
+		dockercfg    = validSecret()
+		noDockercfg  = validSecret()
+		badDockercfg = validSecret()
 	)
 
 	emptyName.Name = ""
@@ -2652,6 +3970,14 @@ func TestValidateSecret(t *testing.T) {
 		"over": make([]byte, api.MaxSecretSize+1),
 	}
 	invalidKey.Data["a..b"] = []byte("whoops")
+	tokenSecret.Type = api.SecretTypeServiceAccountToken
+	tokenSecret.Annotations = map[string]string{api.ServiceAccountNameKey: "default"}
+	noTokenName.Type = api.SecretTypeServiceAccountToken
+	dockercfg.Type = api.SecretTypeDockercfg
+	dockercfg.Data[api.DockerConfigKey] = []byte(`{"registry.example.com": {"auth": "Zm9vOmJhcg==", "email": "foo@example.com"}}`)
+	noDockercfg.Type = api.SecretTypeDockercfg
+	badDockercfg.Type = api.SecretTypeDockercfg
+	badDockercfg.Data[api.DockerConfigKey] = []byte("not json")
 
 	tests := map[string]struct {
 		secret api.Secret
@@ -2664,6 +3990,11 @@ func TestValidateSecret(t *testing.T) {
 		"invalid namespace": {invalidNs, false},
 		"over max size":     {overMaxSize, false},
 		"invalid key":       {invalidKey, false},
+		"token":             {tokenSecret, true},
+		"token no account":  {noTokenName, false},
+		"dockercfg":         {dockercfg, true},
+		"dockercfg missing": {noDockercfg, false},
+		"dockercfg invalid": {badDockercfg, false},
 	}
 
 	for name, tc := range tests {
@@ -2677,6 +4008,48 @@ func TestValidateSecret(t *testing.T) {
 	}
 }
 
+func TestValidateServiceAccount(t *testing.T) {
+	validServiceAccount := func() api.ServiceAccount {
+		return api.ServiceAccount{
+			ObjectMeta: api.ObjectMeta{Name: "default", Namespace: "bar"},
+			Secrets:    []api.ObjectReference{{Name: "default-token-abcde"}},
+		}
+	}
+
+	var (
+		emptyName     = validServiceAccount()
+		invalidName   = validServiceAccount()
+		emptyNs       = validServiceAccount()
+		unnamedSecret = validServiceAccount()
+	)
+
+	emptyName.Name = ""
+	invalidName.Name = "NoUppercaseOrSpecialCharsLike=Equals"
+	emptyNs.Namespace = ""
+	unnamedSecret.Secrets = []api.ObjectReference{{}}
+
+	tests := map[string]struct {
+		serviceAccount api.ServiceAccount
+		valid          bool
+	}{
+		"valid":           {validServiceAccount(), true},
+		"empty name":      {emptyName, false},
+		"invalid name":    {invalidName, false},
+		"empty namespace": {emptyNs, false},
+		"unnamed secret":  {unnamedSecret, false},
+	}
+
+	for name, tc := range tests {
+		errs := ValidateServiceAccount(&tc.serviceAccount)
+		if tc.valid && len(errs) > 0 {
+			t.Errorf("%v: Unexpected error: %v", name, errs)
+		}
+		if !tc.valid && len(errs) == 0 {
+			t.Errorf("%v: Unexpected non-error", name)
+		}
+	}
+}
+
 func TestValidateEndpoints(t *testing.T) {
 	// TODO: implement this
 }
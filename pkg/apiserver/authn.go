@@ -17,11 +17,48 @@ limitations under the License.
 package apiserver
 
 import (
+	"crypto/rsa"
+
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator/bearertoken"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/serviceaccount"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/auth/authenticator/request/union"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/auth/authenticator/token/tokenfile"
 )
 
+// NewAuthenticator returns an authenticator.Request accepting the tokens in tokenAuthFile and the
+// service account tokens signed by the key in serviceAccountKeyFile, or nil if neither is given.
+// If serviceAccountLookup is true, service account tokens are only accepted while their service
+// account and secret still exist.
+func NewAuthenticator(tokenAuthFile, serviceAccountKeyFile string, serviceAccountLookup bool, serviceAccountTokenGetter serviceaccount.ServiceAccountTokenGetter) (authenticator.Request, error) {
+	authenticators := []authenticator.Request{}
+
+	tokenAuthenticator, err := NewAuthenticatorFromTokenFile(tokenAuthFile)
+	if err != nil {
+		return nil, err
+	}
+	if tokenAuthenticator != nil {
+		authenticators = append(authenticators, tokenAuthenticator)
+	}
+
+	if len(serviceAccountKeyFile) != 0 {
+		serviceAccountAuthenticator, err := newServiceAccountAuthenticator(serviceAccountKeyFile, serviceAccountLookup, serviceAccountTokenGetter)
+		if err != nil {
+			return nil, err
+		}
+		authenticators = append(authenticators, serviceAccountAuthenticator)
+	}
+
+	switch len(authenticators) {
+	case 0:
+		return nil, nil
+	case 1:
+		return authenticators[0], nil
+	default:
+		return union.New(authenticators...), nil
+	}
+}
+
 // NewAuthenticatorFromTokenFile returns an authenticator.Request or an error
 func NewAuthenticatorFromTokenFile(tokenAuthFile string) (authenticator.Request, error) {
 	var authenticator authenticator.Request
@@ -34,3 +71,14 @@ func NewAuthenticatorFromTokenFile(tokenAuthFile string) (authenticator.Request,
 	}
 	return authenticator, nil
 }
+
+// newServiceAccountAuthenticator returns an authenticator.Request for service account tokens
+// signed by the key in keyFile, or an error.
+func newServiceAccountAuthenticator(keyFile string, lookup bool, getter serviceaccount.ServiceAccountTokenGetter) (authenticator.Request, error) {
+	publicKey, err := serviceaccount.ReadPublicKey(keyFile)
+	if err != nil {
+		return nil, err
+	}
+	tokenAuthenticator := serviceaccount.JWTTokenAuthenticator([]*rsa.PublicKey{publicKey}, lookup, getter)
+	return bearertoken.New(tokenAuthenticator), nil
+}
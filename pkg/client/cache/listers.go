@@ -128,4 +128,17 @@ func (s *StoreToServiceLister) GetPodServices(pod api.Pod) (services []api.Servi
 	return
 }
 
+// StoreToPersistentVolumeLister makes a Store have the List method of the client.PersistentVolumeInterface
+// The Store must contain (only) PersistentVolumes.
+type StoreToPersistentVolumeLister struct {
+	Store
+}
+
+func (s *StoreToPersistentVolumeLister) List() (volumes api.PersistentVolumeList, err error) {
+	for _, m := range s.Store.List() {
+		volumes.Items = append(volumes.Items, *(m.(*api.PersistentVolume)))
+	}
+	return volumes, nil
+}
+
 // TODO: add StoreToEndpointsLister for use in kube-proxy.
@@ -32,6 +32,10 @@ import (
 type Interface interface {
 	PodsNamespacer
 	ReplicationControllersNamespacer
+	JobsNamespacer
+	DaemonSetsNamespacer
+	DeploymentsNamespacer
+	HorizontalPodAutoscalersNamespacer
 	ServicesNamespacer
 	EndpointsNamespacer
 	VersionInterface
@@ -41,12 +45,31 @@ type Interface interface {
 	ResourceQuotasNamespacer
 	SecretsNamespacer
 	NamespacesInterface
+	PersistentVolumesInterface
+	PersistentVolumeClaimsNamespacer
+	ServiceAccountsNamespacer
 }
 
 func (c *Client) ReplicationControllers(namespace string) ReplicationControllerInterface {
 	return newReplicationControllers(c, namespace)
 }
 
+func (c *Client) Jobs(namespace string) JobInterface {
+	return newJobs(c, namespace)
+}
+
+func (c *Client) DaemonSets(namespace string) DaemonSetInterface {
+	return newDaemonSets(c, namespace)
+}
+
+func (c *Client) Deployments(namespace string) DeploymentInterface {
+	return newDeployments(c, namespace)
+}
+
+func (c *Client) HorizontalPodAutoscalers(namespace string) HorizontalPodAutoscalerInterface {
+	return newHorizontalPodAutoscalers(c, namespace)
+}
+
 func (c *Client) Nodes() NodeInterface {
 	return newNodes(c)
 }
@@ -83,6 +106,18 @@ func (c *Client) Namespaces() NamespaceInterface {
 	return newNamespaces(c)
 }
 
+func (c *Client) PersistentVolumes() PersistentVolumeInterface {
+	return newPersistentVolumes(c)
+}
+
+func (c *Client) PersistentVolumeClaims(namespace string) PersistentVolumeClaimInterface {
+	return newPersistentVolumeClaims(c, namespace)
+}
+
+func (c *Client) ServiceAccounts(namespace string) ServiceAccountInterface {
+	return newServiceAccounts(c, namespace)
+}
+
 // VersionInterface has a method to retrieve the server version.
 type VersionInterface interface {
 	ServerVersion() (*version.Info, error)
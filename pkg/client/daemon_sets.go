@@ -0,0 +1,106 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// DaemonSetsNamespacer has methods to work with DaemonSet resources in a namespace
+type DaemonSetsNamespacer interface {
+	DaemonSets(namespace string) DaemonSetInterface
+}
+
+// DaemonSetInterface has methods to work with DaemonSet resources.
+type DaemonSetInterface interface {
+	List(selector labels.Selector) (*api.DaemonSetList, error)
+	Get(name string) (*api.DaemonSet, error)
+	Delete(name string) error
+	Create(daemonSet *api.DaemonSet) (*api.DaemonSet, error)
+	Update(daemonSet *api.DaemonSet) (*api.DaemonSet, error)
+	UpdateStatus(daemonSet *api.DaemonSet) (*api.DaemonSet, error)
+	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// daemonSets implements DaemonSetsNamespacer interface
+type daemonSets struct {
+	r  *Client
+	ns string
+}
+
+// newDaemonSets returns a daemonSets
+func newDaemonSets(c *Client, namespace string) *daemonSets {
+	return &daemonSets{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List takes a selector, and returns the list of daemon sets that match that selector.
+func (c *daemonSets) List(selector labels.Selector) (result *api.DaemonSetList, err error) {
+	result = &api.DaemonSetList{}
+	err = c.r.Get().Namespace(c.ns).Resource("daemonSets").LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), selector).Do().Into(result)
+	return
+}
+
+// Get takes the name of the daemon set, and returns the corresponding DaemonSet object, and an error if it occurs
+func (c *daemonSets) Get(name string) (result *api.DaemonSet, err error) {
+	result = &api.DaemonSet{}
+	err = c.r.Get().Namespace(c.ns).Resource("daemonSets").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the daemon set, and returns an error if one occurs
+func (c *daemonSets) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Resource("daemonSets").Name(name).Do().Error()
+}
+
+// Create takes the representation of a daemon set.  Returns the server's representation of the daemon set, and an error, if it occurs.
+func (c *daemonSets) Create(daemonSet *api.DaemonSet) (result *api.DaemonSet, err error) {
+	result = &api.DaemonSet{}
+	err = c.r.Post().Namespace(c.ns).Resource("daemonSets").Body(daemonSet).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a daemon set to update spec.  Returns the server's representation of the daemon set, and an error, if it occurs.
+func (c *daemonSets) Update(daemonSet *api.DaemonSet) (result *api.DaemonSet, err error) {
+	result = &api.DaemonSet{}
+	err = c.r.Put().Namespace(c.ns).Resource("daemonSets").Name(daemonSet.Name).Body(daemonSet).Do().Into(result)
+	return
+}
+
+// Status takes the representation of a daemon set to update status.  Returns the server's representation of the daemon set, and an error, if it occurs.
+func (c *daemonSets) UpdateStatus(daemonSet *api.DaemonSet) (result *api.DaemonSet, err error) {
+	result = &api.DaemonSet{}
+	err = c.r.Put().Namespace(c.ns).Resource("daemonSets").Name(daemonSet.Name).SubResource("status").Body(daemonSet).Do().Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested resource
+func (c *daemonSets) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Namespace(c.ns).
+		Resource("daemonSets").
+		Param("resourceVersion", resourceVersion).
+		LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(c.r.APIVersion()), field).
+		Watch()
+}
@@ -35,22 +35,33 @@ type FakeAction struct {
 // Fake implements Interface. Meant to be embedded into a struct to get a default
 // implementation. This makes faking out just the method you want to test easier.
 type Fake struct {
-	Actions             []FakeAction
-	PodsList            api.PodList
-	CtrlList            api.ReplicationControllerList
-	Ctrl                api.ReplicationController
-	ServiceList         api.ServiceList
-	EndpointsList       api.EndpointsList
-	MinionsList         api.NodeList
-	EventsList          api.EventList
-	LimitRangesList     api.LimitRangeList
-	ResourceQuotaStatus api.ResourceQuota
-	ResourceQuotasList  api.ResourceQuotaList
-	NamespacesList      api.NamespaceList
-	SecretList          api.SecretList
-	Secret              api.Secret
-	Err                 error
-	Watch               watch.Interface
+	Actions                       []FakeAction
+	PodsList                      api.PodList
+	CtrlList                      api.ReplicationControllerList
+	Ctrl                          api.ReplicationController
+	ServiceList                   api.ServiceList
+	EndpointsList                 api.EndpointsList
+	MinionsList                   api.NodeList
+	EventsList                    api.EventList
+	LimitRangesList               api.LimitRangeList
+	ResourceQuotaStatus           api.ResourceQuota
+	ResourceQuotasList            api.ResourceQuotaList
+	JobStatus                     api.Job
+	JobsList                      api.JobList
+	DaemonSetStatus               api.DaemonSet
+	DaemonSetsList                api.DaemonSetList
+	DeploymentStatus              api.Deployment
+	DeploymentsList               api.DeploymentList
+	HorizontalPodAutoscalerStatus api.HorizontalPodAutoscaler
+	HorizontalPodAutoscalersList  api.HorizontalPodAutoscalerList
+	PersistentVolumesList         api.PersistentVolumeList
+	PersistentVolumeClaimsList    api.PersistentVolumeClaimList
+	ServiceAccountsList           api.ServiceAccountList
+	NamespacesList                api.NamespaceList
+	SecretList                    api.SecretList
+	Secret                        api.Secret
+	Err                           error
+	Watch                         watch.Interface
 }
 
 func (c *Fake) LimitRanges(namespace string) LimitRangeInterface {
@@ -65,6 +76,34 @@ func (c *Fake) ReplicationControllers(namespace string) ReplicationControllerInt
 	return &FakeReplicationControllers{Fake: c, Namespace: namespace}
 }
 
+func (c *Fake) Jobs(namespace string) JobInterface {
+	return &FakeJobs{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) DaemonSets(namespace string) DaemonSetInterface {
+	return &FakeDaemonSets{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) Deployments(namespace string) DeploymentInterface {
+	return &FakeDeployments{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) HorizontalPodAutoscalers(namespace string) HorizontalPodAutoscalerInterface {
+	return &FakeHorizontalPodAutoscalers{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) PersistentVolumes() PersistentVolumeInterface {
+	return &FakePersistentVolumes{Fake: c}
+}
+
+func (c *Fake) PersistentVolumeClaims(namespace string) PersistentVolumeClaimInterface {
+	return &FakePersistentVolumeClaims{Fake: c, Namespace: namespace}
+}
+
+func (c *Fake) ServiceAccounts(namespace string) ServiceAccountInterface {
+	return &FakeServiceAccounts{Fake: c, Namespace: namespace}
+}
+
 func (c *Fake) Nodes() NodeInterface {
 	return &FakeNodes{Fake: c}
 }
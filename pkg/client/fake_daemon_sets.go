@@ -0,0 +1,67 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// FakeDaemonSets implements DaemonSetInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakeDaemonSets struct {
+	Fake      *Fake
+	Namespace string
+}
+
+func (c *FakeDaemonSets) List(selector labels.Selector) (*api.DaemonSetList, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "list-daemonSets"})
+	return api.Scheme.CopyOrDie(&c.Fake.DaemonSetsList).(*api.DaemonSetList), nil
+}
+
+func (c *FakeDaemonSets) Get(name string) (*api.DaemonSet, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-daemonSet", Value: name})
+	return &api.DaemonSet{ObjectMeta: api.ObjectMeta{Name: name, Namespace: c.Namespace}}, nil
+}
+
+func (c *FakeDaemonSets) Delete(name string) error {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-daemonSet", Value: name})
+	return nil
+}
+
+func (c *FakeDaemonSets) Create(daemonSet *api.DaemonSet) (*api.DaemonSet, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "create-daemonSet"})
+	return &api.DaemonSet{}, nil
+}
+
+func (c *FakeDaemonSets) Update(daemonSet *api.DaemonSet) (*api.DaemonSet, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "update-daemonSet", Value: daemonSet.Name})
+	return &api.DaemonSet{}, nil
+}
+
+func (c *FakeDaemonSets) UpdateStatus(daemonSet *api.DaemonSet) (*api.DaemonSet, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "update-status-daemonSet", Value: daemonSet.Name})
+	c.Fake.DaemonSetStatus = *daemonSet
+	return &api.DaemonSet{}, nil
+}
+
+func (c *FakeDaemonSets) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "watch-daemonSet", Value: resourceVersion})
+	return c.Fake.Watch, nil
+}
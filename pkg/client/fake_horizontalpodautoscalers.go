@@ -0,0 +1,67 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// FakeHorizontalPodAutoscalers implements HorizontalPodAutoscalerInterface. Meant to be embedded into a struct to get a default
+// implementation. This makes faking out just the methods you want to test easier.
+type FakeHorizontalPodAutoscalers struct {
+	Fake      *Fake
+	Namespace string
+}
+
+func (c *FakeHorizontalPodAutoscalers) List(selector labels.Selector) (*api.HorizontalPodAutoscalerList, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "list-horizontalpodautoscalers"})
+	return api.Scheme.CopyOrDie(&c.Fake.HorizontalPodAutoscalersList).(*api.HorizontalPodAutoscalerList), nil
+}
+
+func (c *FakeHorizontalPodAutoscalers) Get(name string) (*api.HorizontalPodAutoscaler, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "get-horizontalpodautoscaler", Value: name})
+	return &api.HorizontalPodAutoscaler{ObjectMeta: api.ObjectMeta{Name: name, Namespace: c.Namespace}}, nil
+}
+
+func (c *FakeHorizontalPodAutoscalers) Delete(name string) error {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-horizontalpodautoscaler", Value: name})
+	return nil
+}
+
+func (c *FakeHorizontalPodAutoscalers) Create(autoscaler *api.HorizontalPodAutoscaler) (*api.HorizontalPodAutoscaler, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "create-horizontalpodautoscaler"})
+	return &api.HorizontalPodAutoscaler{}, nil
+}
+
+func (c *FakeHorizontalPodAutoscalers) Update(autoscaler *api.HorizontalPodAutoscaler) (*api.HorizontalPodAutoscaler, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "update-horizontalpodautoscaler", Value: autoscaler})
+	return &api.HorizontalPodAutoscaler{}, nil
+}
+
+func (c *FakeHorizontalPodAutoscalers) UpdateStatus(autoscaler *api.HorizontalPodAutoscaler) (*api.HorizontalPodAutoscaler, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "update-status-horizontalpodautoscaler", Value: autoscaler.Name})
+	c.Fake.HorizontalPodAutoscalerStatus = *autoscaler
+	return &api.HorizontalPodAutoscaler{}, nil
+}
+
+func (c *FakeHorizontalPodAutoscalers) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "watch-horizontalpodautoscaler", Value: resourceVersion})
+	return c.Fake.Watch, nil
+}
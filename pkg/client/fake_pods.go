@@ -40,7 +40,7 @@ func (c *FakePods) Get(name string) (*api.Pod, error) {
 	return &api.Pod{ObjectMeta: api.ObjectMeta{Name: name, Namespace: c.Namespace}}, nil
 }
 
-func (c *FakePods) Delete(name string) error {
+func (c *FakePods) Delete(name string, options *api.DeleteOptions) error {
 	c.Fake.Actions = append(c.Fake.Actions, FakeAction{Action: "delete-pod", Value: name})
 	return nil
 }
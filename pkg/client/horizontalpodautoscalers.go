@@ -0,0 +1,106 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// HorizontalPodAutoscalersNamespacer has methods to work with HorizontalPodAutoscaler resources in a namespace
+type HorizontalPodAutoscalersNamespacer interface {
+	HorizontalPodAutoscalers(namespace string) HorizontalPodAutoscalerInterface
+}
+
+// HorizontalPodAutoscalerInterface has methods to work with HorizontalPodAutoscaler resources.
+type HorizontalPodAutoscalerInterface interface {
+	List(selector labels.Selector) (*api.HorizontalPodAutoscalerList, error)
+	Get(name string) (*api.HorizontalPodAutoscaler, error)
+	Delete(name string) error
+	Create(autoscaler *api.HorizontalPodAutoscaler) (*api.HorizontalPodAutoscaler, error)
+	Update(autoscaler *api.HorizontalPodAutoscaler) (*api.HorizontalPodAutoscaler, error)
+	UpdateStatus(autoscaler *api.HorizontalPodAutoscaler) (*api.HorizontalPodAutoscaler, error)
+	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// horizontalPodAutoscalers implements HorizontalPodAutoscalersNamespacer interface
+type horizontalPodAutoscalers struct {
+	r  *Client
+	ns string
+}
+
+// newHorizontalPodAutoscalers returns a horizontalPodAutoscalers
+func newHorizontalPodAutoscalers(c *Client, namespace string) *horizontalPodAutoscalers {
+	return &horizontalPodAutoscalers{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List takes a selector, and returns the list of horizontalPodAutoscalers that match that selector.
+func (c *horizontalPodAutoscalers) List(selector labels.Selector) (result *api.HorizontalPodAutoscalerList, err error) {
+	result = &api.HorizontalPodAutoscalerList{}
+	err = c.r.Get().Namespace(c.ns).Resource("horizontalPodAutoscalers").LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), selector).Do().Into(result)
+	return
+}
+
+// Get takes the name of the autoscaler, and returns the corresponding HorizontalPodAutoscaler object, and an error if it occurs
+func (c *horizontalPodAutoscalers) Get(name string) (result *api.HorizontalPodAutoscaler, err error) {
+	result = &api.HorizontalPodAutoscaler{}
+	err = c.r.Get().Namespace(c.ns).Resource("horizontalPodAutoscalers").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the autoscaler, and returns an error if one occurs
+func (c *horizontalPodAutoscalers) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Resource("horizontalPodAutoscalers").Name(name).Do().Error()
+}
+
+// Create takes the representation of a autoscaler.  Returns the server's representation of the autoscaler, and an error, if it occurs.
+func (c *horizontalPodAutoscalers) Create(autoscaler *api.HorizontalPodAutoscaler) (result *api.HorizontalPodAutoscaler, err error) {
+	result = &api.HorizontalPodAutoscaler{}
+	err = c.r.Post().Namespace(c.ns).Resource("horizontalPodAutoscalers").Body(autoscaler).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a autoscaler to update spec.  Returns the server's representation of the autoscaler, and an error, if it occurs.
+func (c *horizontalPodAutoscalers) Update(autoscaler *api.HorizontalPodAutoscaler) (result *api.HorizontalPodAutoscaler, err error) {
+	result = &api.HorizontalPodAutoscaler{}
+	err = c.r.Put().Namespace(c.ns).Resource("horizontalPodAutoscalers").Name(autoscaler.Name).Body(autoscaler).Do().Into(result)
+	return
+}
+
+// Status takes the representation of a autoscaler to update status.  Returns the server's representation of the autoscaler, and an error, if it occurs.
+func (c *horizontalPodAutoscalers) UpdateStatus(autoscaler *api.HorizontalPodAutoscaler) (result *api.HorizontalPodAutoscaler, err error) {
+	result = &api.HorizontalPodAutoscaler{}
+	err = c.r.Put().Namespace(c.ns).Resource("horizontalPodAutoscalers").Name(autoscaler.Name).SubResource("status").Body(autoscaler).Do().Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested resource
+func (c *horizontalPodAutoscalers) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Namespace(c.ns).
+		Resource("horizontalPodAutoscalers").
+		Param("resourceVersion", resourceVersion).
+		LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(c.r.APIVersion()), field).
+		Watch()
+}
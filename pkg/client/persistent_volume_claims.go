@@ -0,0 +1,106 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// PersistentVolumeClaimsNamespacer has methods to work with PersistentVolumeClaim resources in a namespace
+type PersistentVolumeClaimsNamespacer interface {
+	PersistentVolumeClaims(namespace string) PersistentVolumeClaimInterface
+}
+
+// PersistentVolumeClaimInterface has methods to work with PersistentVolumeClaim resources.
+type PersistentVolumeClaimInterface interface {
+	List(selector labels.Selector) (*api.PersistentVolumeClaimList, error)
+	Get(name string) (*api.PersistentVolumeClaim, error)
+	Delete(name string) error
+	Create(persistentVolumeClaim *api.PersistentVolumeClaim) (*api.PersistentVolumeClaim, error)
+	Update(persistentVolumeClaim *api.PersistentVolumeClaim) (*api.PersistentVolumeClaim, error)
+	UpdateStatus(persistentVolumeClaim *api.PersistentVolumeClaim) (*api.PersistentVolumeClaim, error)
+	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// persistentVolumeClaims implements PersistentVolumeClaimsNamespacer interface
+type persistentVolumeClaims struct {
+	r  *Client
+	ns string
+}
+
+// newPersistentVolumeClaims returns a persistentVolumeClaims
+func newPersistentVolumeClaims(c *Client, namespace string) *persistentVolumeClaims {
+	return &persistentVolumeClaims{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List takes a selector, and returns the list of persistent volume claims that match that selector.
+func (c *persistentVolumeClaims) List(selector labels.Selector) (result *api.PersistentVolumeClaimList, err error) {
+	result = &api.PersistentVolumeClaimList{}
+	err = c.r.Get().Namespace(c.ns).Resource("persistentVolumeClaims").LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), selector).Do().Into(result)
+	return
+}
+
+// Get takes the name of the persistent volume claim, and returns the corresponding PersistentVolumeClaim object, and an error if it occurs
+func (c *persistentVolumeClaims) Get(name string) (result *api.PersistentVolumeClaim, err error) {
+	result = &api.PersistentVolumeClaim{}
+	err = c.r.Get().Namespace(c.ns).Resource("persistentVolumeClaims").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the persistent volume claim, and returns an error if one occurs
+func (c *persistentVolumeClaims) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Resource("persistentVolumeClaims").Name(name).Do().Error()
+}
+
+// Create takes the representation of a persistent volume claim.  Returns the server's representation of the persistent volume claim, and an error, if it occurs.
+func (c *persistentVolumeClaims) Create(persistentVolumeClaim *api.PersistentVolumeClaim) (result *api.PersistentVolumeClaim, err error) {
+	result = &api.PersistentVolumeClaim{}
+	err = c.r.Post().Namespace(c.ns).Resource("persistentVolumeClaims").Body(persistentVolumeClaim).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a persistent volume claim to update spec.  Returns the server's representation of the persistent volume claim, and an error, if it occurs.
+func (c *persistentVolumeClaims) Update(persistentVolumeClaim *api.PersistentVolumeClaim) (result *api.PersistentVolumeClaim, err error) {
+	result = &api.PersistentVolumeClaim{}
+	err = c.r.Put().Namespace(c.ns).Resource("persistentVolumeClaims").Name(persistentVolumeClaim.Name).Body(persistentVolumeClaim).Do().Into(result)
+	return
+}
+
+// Status takes the representation of a persistent volume claim to update status.  Returns the server's representation of the persistent volume claim, and an error, if it occurs.
+func (c *persistentVolumeClaims) UpdateStatus(persistentVolumeClaim *api.PersistentVolumeClaim) (result *api.PersistentVolumeClaim, err error) {
+	result = &api.PersistentVolumeClaim{}
+	err = c.r.Put().Namespace(c.ns).Resource("persistentVolumeClaims").Name(persistentVolumeClaim.Name).SubResource("status").Body(persistentVolumeClaim).Do().Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested resource
+func (c *persistentVolumeClaims) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Namespace(c.ns).
+		Resource("persistentVolumeClaims").
+		Param("resourceVersion", resourceVersion).
+		LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(c.r.APIVersion()), field).
+		Watch()
+}
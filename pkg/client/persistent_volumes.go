@@ -0,0 +1,103 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// PersistentVolumesInterface has methods to work with PersistentVolume resources.
+// Persistent volumes are not namespaced.
+type PersistentVolumesInterface interface {
+	PersistentVolumes() PersistentVolumeInterface
+}
+
+// PersistentVolumeInterface has methods to work with PersistentVolume resources.
+type PersistentVolumeInterface interface {
+	List(selector labels.Selector) (*api.PersistentVolumeList, error)
+	Get(name string) (*api.PersistentVolume, error)
+	Delete(name string) error
+	Create(persistentVolume *api.PersistentVolume) (*api.PersistentVolume, error)
+	Update(persistentVolume *api.PersistentVolume) (*api.PersistentVolume, error)
+	UpdateStatus(persistentVolume *api.PersistentVolume) (*api.PersistentVolume, error)
+	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// persistentVolumes implements PersistentVolumesInterface interface
+type persistentVolumes struct {
+	r *Client
+}
+
+// newPersistentVolumes returns a persistentVolumes
+func newPersistentVolumes(c *Client) *persistentVolumes {
+	return &persistentVolumes{c}
+}
+
+// List takes a selector, and returns the list of persistent volumes that match that selector.
+func (c *persistentVolumes) List(selector labels.Selector) (result *api.PersistentVolumeList, err error) {
+	result = &api.PersistentVolumeList{}
+	err = c.r.Get().Resource("persistentVolumes").LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), selector).Do().Into(result)
+	return
+}
+
+// Get takes the name of the persistent volume, and returns the corresponding PersistentVolume object, and an error if it occurs
+func (c *persistentVolumes) Get(name string) (result *api.PersistentVolume, err error) {
+	result = &api.PersistentVolume{}
+	err = c.r.Get().Resource("persistentVolumes").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the persistent volume, and returns an error if one occurs
+func (c *persistentVolumes) Delete(name string) error {
+	return c.r.Delete().Resource("persistentVolumes").Name(name).Do().Error()
+}
+
+// Create takes the representation of a persistent volume.  Returns the server's representation of the persistent volume, and an error, if it occurs.
+func (c *persistentVolumes) Create(persistentVolume *api.PersistentVolume) (result *api.PersistentVolume, err error) {
+	result = &api.PersistentVolume{}
+	err = c.r.Post().Resource("persistentVolumes").Body(persistentVolume).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a persistent volume to update spec.  Returns the server's representation of the persistent volume, and an error, if it occurs.
+func (c *persistentVolumes) Update(persistentVolume *api.PersistentVolume) (result *api.PersistentVolume, err error) {
+	result = &api.PersistentVolume{}
+	err = c.r.Put().Resource("persistentVolumes").Name(persistentVolume.Name).Body(persistentVolume).Do().Into(result)
+	return
+}
+
+// Status takes the representation of a persistent volume to update status.  Returns the server's representation of the persistent volume, and an error, if it occurs.
+func (c *persistentVolumes) UpdateStatus(persistentVolume *api.PersistentVolume) (result *api.PersistentVolume, err error) {
+	result = &api.PersistentVolume{}
+	err = c.r.Put().Resource("persistentVolumes").Name(persistentVolume.Name).SubResource("status").Body(persistentVolume).Do().Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested resource
+func (c *persistentVolumes) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Namespace(api.NamespaceAll).
+		Resource("persistentVolumes").
+		Param("resourceVersion", resourceVersion).
+		LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(c.r.APIVersion()), field).
+		Watch()
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func getPersistentVolumesResourceName() string {
+	if api.PreV1Beta3(testapi.Version()) {
+		return "persistentVolumes"
+	}
+	return "persistentvolumes"
+}
+
+func validPersistentVolumeSpec() api.PersistentVolumeSpec {
+	return api.PersistentVolumeSpec{
+		Capacity: api.ResourceList{
+			api.ResourceStorage: resource.MustParse("10G"),
+		},
+		PersistentVolumeSource: api.PersistentVolumeSource{
+			HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+		},
+		PersistentVolumeReclaimPolicy: api.PersistentVolumeReclaimRecycle,
+	}
+}
+
+func TestPersistentVolumeCreate(t *testing.T) {
+	persistentVolume := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "abc"},
+		Spec:       validPersistentVolumeSpec(),
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "POST",
+			Path:   testapi.ResourcePath(getPersistentVolumesResourceName(), "", ""),
+			Query:  buildQueryValues("", nil),
+			Body:   persistentVolume,
+		},
+		Response: Response{StatusCode: 200, Body: persistentVolume},
+	}
+
+	response, err := c.Setup().PersistentVolumes().Create(persistentVolume)
+	c.Validate(t, response, err)
+}
+
+func TestPersistentVolumeGet(t *testing.T) {
+	persistentVolume := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "abc"},
+		Spec:       validPersistentVolumeSpec(),
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   testapi.ResourcePath(getPersistentVolumesResourceName(), "", "abc"),
+			Query:  buildQueryValues("", nil),
+			Body:   nil,
+		},
+		Response: Response{StatusCode: 200, Body: persistentVolume},
+	}
+
+	response, err := c.Setup().PersistentVolumes().Get("abc")
+	c.Validate(t, response, err)
+}
+
+func TestPersistentVolumeList(t *testing.T) {
+	persistentVolumeList := &api.PersistentVolumeList{
+		Items: []api.PersistentVolume{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo"},
+				Spec:       validPersistentVolumeSpec(),
+			},
+		},
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   testapi.ResourcePath(getPersistentVolumesResourceName(), "", ""),
+			Query:  buildQueryValues("", nil),
+			Body:   nil,
+		},
+		Response: Response{StatusCode: 200, Body: persistentVolumeList},
+	}
+	response, err := c.Setup().PersistentVolumes().List(labels.Everything())
+	c.Validate(t, response, err)
+}
+
+func TestPersistentVolumeUpdate(t *testing.T) {
+	persistentVolume := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{
+			Name:            "abc",
+			ResourceVersion: "1",
+		},
+		Spec: validPersistentVolumeSpec(),
+	}
+	c := &testClient{
+		Request:  testRequest{Method: "PUT", Path: testapi.ResourcePath(getPersistentVolumesResourceName(), "", "abc"), Query: buildQueryValues("", nil)},
+		Response: Response{StatusCode: 200, Body: persistentVolume},
+	}
+	response, err := c.Setup().PersistentVolumes().Update(persistentVolume)
+	c.Validate(t, response, err)
+}
+
+func TestPersistentVolumeStatusUpdate(t *testing.T) {
+	persistentVolume := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{
+			Name:            "abc",
+			ResourceVersion: "1",
+		},
+		Spec: validPersistentVolumeSpec(),
+		Status: api.PersistentVolumeStatus{
+			Phase: api.VolumeBound,
+		},
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "PUT",
+			Path:   testapi.ResourcePath(getPersistentVolumesResourceName(), "", "abc") + "/status",
+			Query:  buildQueryValues("", nil)},
+		Response: Response{StatusCode: 200, Body: persistentVolume},
+	}
+	response, err := c.Setup().PersistentVolumes().UpdateStatus(persistentVolume)
+	c.Validate(t, response, err)
+}
+
+func TestPersistentVolumeDelete(t *testing.T) {
+	c := &testClient{
+		Request:  testRequest{Method: "DELETE", Path: testapi.ResourcePath(getPersistentVolumesResourceName(), "", "foo"), Query: buildQueryValues("", nil)},
+		Response: Response{StatusCode: 200},
+	}
+	err := c.Setup().PersistentVolumes().Delete("foo")
+	c.Validate(t, nil, err)
+}
+
+func TestPersistentVolumeWatch(t *testing.T) {
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   "/api/" + testapi.Version() + "/watch/persistentVolumes",
+			Query:  url.Values{"resourceVersion": []string{}}},
+		Response: Response{StatusCode: 200},
+	}
+	_, err := c.Setup().PersistentVolumes().Watch(labels.Everything(), fields.Everything(), "")
+	c.Validate(t, nil, err)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// ServiceAccountsNamespacer has methods to work with ServiceAccount resources in a namespace
+type ServiceAccountsNamespacer interface {
+	ServiceAccounts(namespace string) ServiceAccountInterface
+}
+
+// ServiceAccountInterface has methods to work with ServiceAccount resources.
+type ServiceAccountInterface interface {
+	List(label labels.Selector, field fields.Selector) (*api.ServiceAccountList, error)
+	Get(name string) (*api.ServiceAccount, error)
+	Delete(name string) error
+	Create(serviceAccount *api.ServiceAccount) (*api.ServiceAccount, error)
+	Update(serviceAccount *api.ServiceAccount) (*api.ServiceAccount, error)
+	Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+}
+
+// serviceAccounts implements ServiceAccountsNamespacer interface
+type serviceAccounts struct {
+	r  *Client
+	ns string
+}
+
+// newServiceAccounts returns a serviceAccounts
+func newServiceAccounts(c *Client, namespace string) *serviceAccounts {
+	return &serviceAccounts{
+		r:  c,
+		ns: namespace,
+	}
+}
+
+// List takes label and field selectors, and returns the list of service accounts that match them.
+func (c *serviceAccounts) List(label labels.Selector, field fields.Selector) (result *api.ServiceAccountList, err error) {
+	result = &api.ServiceAccountList{}
+	err = c.r.Get().
+		Namespace(c.ns).
+		Resource("serviceAccounts").
+		LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(c.r.APIVersion()), field).
+		Do().
+		Into(result)
+	return
+}
+
+// Get takes the name of the service account, and returns the corresponding ServiceAccount object, and an error if it occurs
+func (c *serviceAccounts) Get(name string) (result *api.ServiceAccount, err error) {
+	result = &api.ServiceAccount{}
+	err = c.r.Get().Namespace(c.ns).Resource("serviceAccounts").Name(name).Do().Into(result)
+	return
+}
+
+// Delete takes the name of the service account, and returns an error if one occurs
+func (c *serviceAccounts) Delete(name string) error {
+	return c.r.Delete().Namespace(c.ns).Resource("serviceAccounts").Name(name).Do().Error()
+}
+
+// Create takes the representation of a service account.  Returns the server's representation of the service account, and an error, if it occurs.
+func (c *serviceAccounts) Create(serviceAccount *api.ServiceAccount) (result *api.ServiceAccount, err error) {
+	result = &api.ServiceAccount{}
+	err = c.r.Post().Namespace(c.ns).Resource("serviceAccounts").Body(serviceAccount).Do().Into(result)
+	return
+}
+
+// Update takes the representation of a service account to update.  Returns the server's representation of the service account, and an error, if it occurs.
+func (c *serviceAccounts) Update(serviceAccount *api.ServiceAccount) (result *api.ServiceAccount, err error) {
+	result = &api.ServiceAccount{}
+	err = c.r.Put().Namespace(c.ns).Resource("serviceAccounts").Name(serviceAccount.Name).Body(serviceAccount).Do().Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested resource
+func (c *serviceAccounts) Watch(label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return c.r.Get().
+		Prefix("watch").
+		Namespace(c.ns).
+		Resource("serviceAccounts").
+		Param("resourceVersion", resourceVersion).
+		LabelsSelectorParam(api.LabelSelectorQueryParam(c.r.APIVersion()), label).
+		FieldsSelectorParam(api.FieldSelectorQueryParam(c.r.APIVersion()), field).
+		Watch()
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func getServiceAccountsResourceName() string {
+	if api.PreV1Beta3(testapi.Version()) {
+		return "serviceAccounts"
+	}
+	return "serviceaccounts"
+}
+
+func TestServiceAccountCreate(t *testing.T) {
+	ns := api.NamespaceDefault
+	serviceAccount := &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{
+			Name:      "abc",
+			Namespace: "foo",
+		},
+		Secrets: []api.ObjectReference{{Name: "abc-token-defgh"}},
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "POST",
+			Path:   testapi.ResourcePath(getServiceAccountsResourceName(), ns, ""),
+			Query:  buildQueryValues(ns, nil),
+			Body:   serviceAccount,
+		},
+		Response: Response{StatusCode: 200, Body: serviceAccount},
+	}
+
+	response, err := c.Setup().ServiceAccounts(ns).Create(serviceAccount)
+	c.Validate(t, response, err)
+}
+
+func TestServiceAccountGet(t *testing.T) {
+	ns := api.NamespaceDefault
+	serviceAccount := &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{
+			Name:      "abc",
+			Namespace: "foo",
+		},
+		Secrets: []api.ObjectReference{{Name: "abc-token-defgh"}},
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   testapi.ResourcePath(getServiceAccountsResourceName(), ns, "abc"),
+			Query:  buildQueryValues(ns, nil),
+			Body:   nil,
+		},
+		Response: Response{StatusCode: 200, Body: serviceAccount},
+	}
+
+	response, err := c.Setup().ServiceAccounts(ns).Get("abc")
+	c.Validate(t, response, err)
+}
+
+func TestServiceAccountList(t *testing.T) {
+	ns := api.NamespaceDefault
+
+	serviceAccountList := &api.ServiceAccountList{
+		Items: []api.ServiceAccount{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo"},
+				Secrets:    []api.ObjectReference{{Name: "foo-token-defgh"}},
+			},
+		},
+	}
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   testapi.ResourcePath(getServiceAccountsResourceName(), ns, ""),
+			Query:  buildQueryValues(ns, nil),
+			Body:   nil,
+		},
+		Response: Response{StatusCode: 200, Body: serviceAccountList},
+	}
+	response, err := c.Setup().ServiceAccounts(ns).List(labels.Everything(), fields.Everything())
+	c.Validate(t, response, err)
+}
+
+func TestServiceAccountUpdate(t *testing.T) {
+	ns := api.NamespaceDefault
+	serviceAccount := &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{
+			Name:            "abc",
+			Namespace:       "foo",
+			ResourceVersion: "1",
+		},
+		Secrets: []api.ObjectReference{{Name: "abc-token-defgh"}},
+	}
+	c := &testClient{
+		Request:  testRequest{Method: "PUT", Path: testapi.ResourcePath(getServiceAccountsResourceName(), ns, "abc"), Query: buildQueryValues(ns, nil)},
+		Response: Response{StatusCode: 200, Body: serviceAccount},
+	}
+	response, err := c.Setup().ServiceAccounts(ns).Update(serviceAccount)
+	c.Validate(t, response, err)
+}
+
+func TestServiceAccountDelete(t *testing.T) {
+	ns := api.NamespaceDefault
+	c := &testClient{
+		Request:  testRequest{Method: "DELETE", Path: testapi.ResourcePath(getServiceAccountsResourceName(), ns, "foo"), Query: buildQueryValues(ns, nil)},
+		Response: Response{StatusCode: 200},
+	}
+	err := c.Setup().ServiceAccounts(ns).Delete("foo")
+	c.Validate(t, nil, err)
+}
+
+func TestServiceAccountWatch(t *testing.T) {
+	c := &testClient{
+		Request: testRequest{
+			Method: "GET",
+			Path:   "/api/" + testapi.Version() + "/watch/serviceAccounts",
+			Query:  url.Values{"resourceVersion": []string{}}},
+		Response: Response{StatusCode: 200},
+	}
+	_, err := c.Setup().ServiceAccounts(api.NamespaceAll).Watch(labels.Everything(), fields.Everything(), "")
+	c.Validate(t, nil, err)
+}
@@ -489,3 +489,15 @@ func (self *AWSCloud) GetZone() (cloudprovider.Zone, error) {
 		Region:        self.region.Name,
 	}, nil
 }
+
+// GetZoneByInstance implements Zones.GetZoneByInstance
+func (self *AWSCloud) GetZoneByInstance(name string) (cloudprovider.Zone, error) {
+	inst, err := self.getInstancesByDnsName(name)
+	if err != nil {
+		return cloudprovider.Zone{}, err
+	}
+	return cloudprovider.Zone{
+		FailureDomain: inst.AvailZone,
+		Region:        self.region.Name,
+	}, nil
+}
@@ -263,3 +263,33 @@ func TestGetResources(t *testing.T) {
 		t.Errorf("Should return nil resources when unknown instance type")
 	}
 }
+
+func TestGetZoneByInstance(t *testing.T) {
+	instances := make([]ec2.Instance, 2)
+	instances[0].PrivateDNSName = "instance1"
+	instances[0].AvailZone = "us-west-2a"
+	instances[0].State.Name = "running"
+	instances[1].PrivateDNSName = "instance2"
+	instances[1].AvailZone = "us-west-2b"
+	instances[1].State.Name = "running"
+
+	region := aws.Regions["us-west-2"]
+	aws := mockInstancesResp(instances)
+	aws.region = region
+	for _, instance := range instances {
+		zone, err := aws.GetZoneByInstance(instance.PrivateDNSName)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if zone.Region != "us-west-2" {
+			t.Errorf("Unexpected region: %s", zone.Region)
+		}
+		if zone.FailureDomain != instance.AvailZone {
+			t.Errorf("Expected FailureDomain %s, got %s", instance.AvailZone, zone.FailureDomain)
+		}
+	}
+
+	if _, err := aws.GetZoneByInstance("instance3"); err == nil {
+		t.Errorf("Should error when no instance found")
+	}
+}
@@ -146,6 +146,16 @@ func (nc *NodeController) Run(period time.Duration, syncNodeList, syncNodeStatus
 		glog.Errorf("Error registering node list %+v: %v", nodes, err)
 	}
 
+	// Start labeling nodes with the zone of their instance, including the
+	// nodes registered by kubelets.
+	if nc.cloud != nil {
+		go util.Forever(func() {
+			if err = nc.SyncNodeZones(); err != nil {
+				glog.Errorf("Error syncing node zones: %v", err)
+			}
+		}, period)
+	}
+
 	// Start syncing node list from cloudprovider.
 	if syncNodeList && nc.isRunningCloudProvider() {
 		go util.Forever(func() {
@@ -246,6 +256,51 @@ func (nc *NodeController) SyncCloudNodes() error {
 	return nil
 }
 
+// SyncNodeZones labels the nodes which are not labeled with a zone yet with the
+// failure domain and region of their instance. The zone of an instance never
+// changes, so labeled nodes are left alone.
+func (nc *NodeController) SyncNodeZones() error {
+	if _, ok := nc.cloud.Zones(); !ok {
+		return nil
+	}
+	nodes, err := nc.kubeClient.Nodes().List()
+	if err != nil {
+		return err
+	}
+	for i := range nodes.Items {
+		node := &nodes.Items[i]
+		if hasZoneLabels(node) {
+			continue
+		}
+		zoneLabels, err := nc.getZoneLabels(node.Name)
+		if err != nil {
+			glog.Errorf("error getting zone of node %s: %v", node.Name, err)
+			continue
+		}
+		if len(zoneLabels) == 0 {
+			continue
+		}
+		if node.Labels == nil {
+			node.Labels = map[string]string{}
+		}
+		for key, value := range zoneLabels {
+			node.Labels[key] = value
+		}
+		glog.V(2).Infof("labeling node %s with zone %v", node.Name, zoneLabels)
+		if _, err = nc.kubeClient.Nodes().Update(node); err != nil {
+			glog.Errorf("error updating node %s: %v", node.Name, err)
+		}
+	}
+	return nil
+}
+
+// hasZoneLabels returns true if the node is labeled with a failure domain or a region.
+func hasZoneLabels(node *api.Node) bool {
+	_, hasFailureDomain := node.Labels[api.LabelZoneFailureDomain]
+	_, hasRegion := node.Labels[api.LabelZoneRegion]
+	return hasFailureDomain || hasRegion
+}
+
 // SyncProbedNodeStatus synchronizes cluster nodes status to master server.
 func (nc *NodeController) SyncProbedNodeStatus() error {
 	nodes, err := nc.kubeClient.Nodes().List()
@@ -558,6 +613,12 @@ func (nc *NodeController) GetCloudNodesWithSpec() (*api.NodeList, error) {
 	for i := range matches {
 		node := api.Node{}
 		node.Name = matches[i]
+		zoneLabels, err := nc.getZoneLabels(node.Name)
+		if err != nil {
+			glog.Errorf("error getting zone of instance %s: %v", node.Name, err)
+		} else if len(zoneLabels) > 0 {
+			node.Labels = zoneLabels
+		}
 		resources, err := instances.GetNodeResources(matches[i])
 		if err != nil {
 			return nil, err
@@ -579,6 +640,27 @@ func (nc *NodeController) GetCloudNodesWithSpec() (*api.NodeList, error) {
 	return result, nil
 }
 
+// getZoneLabels returns the labels naming the failure domain and region which the
+// cloudprovider reports for the instance, or nil if it doesn't support zones.
+func (nc *NodeController) getZoneLabels(name string) (map[string]string, error) {
+	zones, ok := nc.cloud.Zones()
+	if !ok {
+		return nil, nil
+	}
+	zone, err := zones.GetZoneByInstance(name)
+	if err != nil {
+		return nil, err
+	}
+	zoneLabels := map[string]string{}
+	if zone.FailureDomain != "" {
+		zoneLabels[api.LabelZoneFailureDomain] = zone.FailureDomain
+	}
+	if zone.Region != "" {
+		zoneLabels[api.LabelZoneRegion] = zone.Region
+	}
+	return zoneLabels, nil
+}
+
 // deletePods will delete all pods from master running on given node.
 func (nc *NodeController) deletePods(nodeID string) error {
 	glog.V(2).Infof("Delete all pods from %v", nodeID)
@@ -592,7 +674,7 @@ func (nc *NodeController) deletePods(nodeID string) error {
 			continue
 		}
 		glog.V(2).Infof("Delete pod %v", pod.Name)
-		if err := nc.kubeClient.Pods(pod.Namespace).Delete(pod.Name); err != nil {
+		if err := nc.kubeClient.Pods(pod.Namespace).Delete(pod.Name, api.NewDeleteOptions(0)); err != nil {
 			glog.Errorf("Error deleting pod %v: %v", pod.Name, err)
 		}
 	}
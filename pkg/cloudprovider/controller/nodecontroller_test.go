@@ -30,6 +30,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	fake_cloud "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/fake"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
@@ -391,6 +392,40 @@ func TestCreateGetCloudNodesWithSpec(t *testing.T) {
 				},
 			},
 		},
+		{
+			fakeCloud: &fake_cloud.FakeCloud{
+				Machines:      []string{"node0", "node1"},
+				NodeResources: &api.NodeResources{Capacity: resourceList},
+				Zone:          cloudprovider.Zone{FailureDomain: "us-central1-a", Region: "us-central1"},
+				InstanceZones: map[string]cloudprovider.Zone{
+					"node1": {FailureDomain: "us-central1-b", Region: "us-central1"},
+				},
+			},
+			expectedNodes: &api.NodeList{
+				Items: []api.Node{
+					{
+						ObjectMeta: api.ObjectMeta{
+							Name: "node0",
+							Labels: map[string]string{
+								api.LabelZoneFailureDomain: "us-central1-a",
+								api.LabelZoneRegion:        "us-central1",
+							},
+						},
+						Status: api.NodeStatus{Capacity: resourceList},
+					},
+					{
+						ObjectMeta: api.ObjectMeta{
+							Name: "node1",
+							Labels: map[string]string{
+								api.LabelZoneFailureDomain: "us-central1-b",
+								api.LabelZoneRegion:        "us-central1",
+							},
+						},
+						Status: api.NodeStatus{Capacity: resourceList},
+					},
+				},
+			},
+		},
 	}
 
 	for _, item := range table {
@@ -513,6 +548,38 @@ func TestSyncCloudNodes(t *testing.T) {
 	}
 }
 
+func TestSyncNodeZones(t *testing.T) {
+	labeled := newNode("node2")
+	labeled.Labels = map[string]string{api.LabelZoneFailureDomain: "us-central1-f", api.LabelZoneRegion: "us-central1"}
+	fakeNodeHandler := &FakeNodeHandler{
+		// node0 and node1 registered themselves, node2 was labeled already.
+		Existing: []*api.Node{newNode("node0"), newNode("node1"), labeled},
+	}
+	fakeCloud := &fake_cloud.FakeCloud{
+		InstanceZones: map[string]cloudprovider.Zone{
+			"node0": {FailureDomain: "us-central1-a", Region: "us-central1"},
+			"node1": {FailureDomain: "us-central1-b", Region: "us-central1"},
+			"node2": {FailureDomain: "us-central1-a", Region: "us-central1"},
+		},
+	}
+	nodeController := NewNodeController(fakeCloud, "", nil, &api.NodeResources{}, fakeNodeHandler, nil, nil, 10, time.Minute)
+	if err := nodeController.SyncNodeZones(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	expected := map[string]string{"node0": "us-central1-a", "node1": "us-central1-b"}
+	updated := map[string]string{}
+	for _, node := range fakeNodeHandler.UpdatedNodes {
+		updated[node.Name] = node.Labels[api.LabelZoneFailureDomain]
+		if region := node.Labels[api.LabelZoneRegion]; region != "us-central1" {
+			t.Errorf("expected node %s to be labeled with region us-central1, got %q", node.Name, region)
+		}
+	}
+	if !reflect.DeepEqual(expected, updated) {
+		t.Errorf("expected zones %v, got %v", expected, updated)
+	}
+}
+
 func TestSyncCloudNodesEvictPods(t *testing.T) {
 	table := []struct {
 		fakeNodeHandler      *FakeNodeHandler
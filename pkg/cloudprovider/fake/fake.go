@@ -29,7 +29,7 @@ type FakeBalancer struct {
 	Name       string
 	Region     string
 	ExternalIP net.IP
-	Port       int
+	Ports      []int
 	Hosts      []string
 }
 
@@ -46,6 +46,7 @@ type FakeCloud struct {
 	MasterName    string
 	ExternalIP    net.IP
 	Balancers     []FakeBalancer
+	InstanceZones map[string]cloudprovider.Zone
 
 	cloudprovider.Zone
 }
@@ -95,9 +96,9 @@ func (f *FakeCloud) TCPLoadBalancerExists(name, region string) (bool, error) {
 
 // CreateTCPLoadBalancer is a test-spy implementation of TCPLoadBalancer.CreateTCPLoadBalancer.
 // It adds an entry "create" into the internal method call record.
-func (f *FakeCloud) CreateTCPLoadBalancer(name, region string, externalIP net.IP, port int, hosts []string, affinityType api.AffinityType) (string, error) {
+func (f *FakeCloud) CreateTCPLoadBalancer(name, region string, externalIP net.IP, ports []int, hosts []string, affinityType api.AffinityType) (string, error) {
 	f.addCall("create")
-	f.Balancers = append(f.Balancers, FakeBalancer{name, region, externalIP, port, hosts})
+	f.Balancers = append(f.Balancers, FakeBalancer{name, region, externalIP, ports, hosts})
 	return f.ExternalIP.String(), f.Err
 }
 
@@ -148,6 +149,17 @@ func (f *FakeCloud) GetZone() (cloudprovider.Zone, error) {
 	return f.Zone, f.Err
 }
 
+// GetZoneByInstance is a test-spy implementation of Zones.GetZoneByInstance.
+// It adds an entry "get-zone-by-instance" into the internal method call record.
+// It returns the zone mapped to the instance, if not found, it returns Zone.
+func (f *FakeCloud) GetZoneByInstance(instance string) (cloudprovider.Zone, error) {
+	f.addCall("get-zone-by-instance")
+	if zone, found := f.InstanceZones[instance]; found {
+		return zone, f.Err
+	}
+	return f.Zone, f.Err
+}
+
 func (f *FakeCloud) GetNodeResources(name string) (*api.NodeResources, error) {
 	f.addCall("get-node-resources")
 	return f.NodeResources, f.Err
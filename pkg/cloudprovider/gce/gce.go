@@ -228,7 +228,10 @@ func translateAffinityType(affinityType api.AffinityType) GCEAffinityType {
 }
 
 // CreateTCPLoadBalancer is an implementation of TCPLoadBalancer.CreateTCPLoadBalancer.
-func (gce *GCECloud) CreateTCPLoadBalancer(name, region string, externalIP net.IP, port int, hosts []string, affinityType api.AffinityType) (string, error) {
+func (gce *GCECloud) CreateTCPLoadBalancer(name, region string, externalIP net.IP, ports []int, hosts []string, affinityType api.AffinityType) (string, error) {
+	if len(ports) == 0 {
+		return "", fmt.Errorf("no ports specified for load balancer %s", name)
+	}
 	pool, err := gce.makeTargetPool(name, region, hosts, translateAffinityType(affinityType))
 	if err != nil {
 		return "", err
@@ -236,7 +239,7 @@ func (gce *GCECloud) CreateTCPLoadBalancer(name, region string, externalIP net.I
 	req := &compute.ForwardingRule{
 		Name:       name,
 		IPProtocol: "TCP",
-		PortRange:  strconv.Itoa(port),
+		PortRange:  portRange(ports),
 		Target:     pool,
 	}
 	if len(externalIP) > 0 {
@@ -257,6 +260,25 @@ func (gce *GCECloud) CreateTCPLoadBalancer(name, region string, externalIP net.I
 	return fwd.IPAddress, nil
 }
 
+// portRange returns the smallest GCE port range covering all of ports.  A
+// forwarding rule can only carry a single range, so any ports in between are
+// forwarded as well.
+func portRange(ports []int) string {
+	min, max := ports[0], ports[0]
+	for _, port := range ports[1:] {
+		if port < min {
+			min = port
+		}
+		if port > max {
+			max = port
+		}
+	}
+	if min == max {
+		return strconv.Itoa(min)
+	}
+	return fmt.Sprintf("%d-%d", min, max)
+}
+
 // UpdateTCPLoadBalancer is an implementation of TCPLoadBalancer.UpdateTCPLoadBalancer.
 func (gce *GCECloud) UpdateTCPLoadBalancer(name, region string, hosts []string) error {
 	var refs []*compute.InstanceReference
@@ -448,6 +470,24 @@ func (gce *GCECloud) GetZone() (cloudprovider.Zone, error) {
 	}, nil
 }
 
+// GetZoneByInstance is an implementation of Zones.GetZoneByInstance.
+func (gce *GCECloud) GetZoneByInstance(name string) (cloudprovider.Zone, error) {
+	inst, err := gce.getInstanceByName(name)
+	if err != nil {
+		return cloudprovider.Zone{}, err
+	}
+	// The zone of an instance is the URL of the zone resource.
+	zone := path.Base(inst.Zone)
+	region, err := getGceRegion(zone)
+	if err != nil {
+		return cloudprovider.Zone{}, err
+	}
+	return cloudprovider.Zone{
+		FailureDomain: zone,
+		Region:        region,
+	}, nil
+}
+
 func (gce *GCECloud) AttachDisk(diskName string, readOnly bool) error {
 	disk, err := gce.getDisk(diskName)
 	if err != nil {
@@ -486,6 +526,33 @@ func (gce *GCECloud) getDisk(diskName string) (*compute.Disk, error) {
 	return gce.service.Disks.Get(gce.projectID, gce.zone, diskName).Do()
 }
 
+// GetDiskZone returns the zone of the persistent disk, which may be in any zone
+// of the project.
+func (gce *GCECloud) GetDiskZone(diskName string) (cloudprovider.Zone, error) {
+	res, err := gce.service.Disks.AggregatedList(gce.projectID).Filter("name eq " + diskName).Do()
+	if err != nil {
+		return cloudprovider.Zone{}, err
+	}
+	for _, scoped := range res.Items {
+		for _, disk := range scoped.Disks {
+			if disk.Name != diskName {
+				continue
+			}
+			// The zone of a disk is the URL of the zone resource.
+			zone := path.Base(disk.Zone)
+			region, err := getGceRegion(zone)
+			if err != nil {
+				return cloudprovider.Zone{}, err
+			}
+			return cloudprovider.Zone{
+				FailureDomain: zone,
+				Region:        region,
+			}, nil
+		}
+	}
+	return cloudprovider.Zone{}, fmt.Errorf("disk %s not found", diskName)
+}
+
 // getGceRegion returns region of the gce zone. Zone names
 // are of the form: ${region-name}-${ix}.
 // For example "us-central1-b" has a region of "us-central1".
@@ -485,8 +485,17 @@ func (lb *LoadBalancer) TCPLoadBalancerExists(name, region string) (bool, error)
 // a list of regions (from config) and query/create loadbalancers in
 // each region.
 
-func (lb *LoadBalancer) CreateTCPLoadBalancer(name, region string, externalIP net.IP, port int, hosts []string, affinity api.AffinityType) (string, error) {
-	glog.V(4).Infof("CreateTCPLoadBalancer(%v, %v, %v, %v, %v, %v)", name, region, externalIP, port, hosts, affinity)
+func (lb *LoadBalancer) CreateTCPLoadBalancer(name, region string, externalIP net.IP, ports []int, hosts []string, affinity api.AffinityType) (string, error) {
+	glog.V(4).Infof("CreateTCPLoadBalancer(%v, %v, %v, %v, %v, %v)", name, region, externalIP, ports, hosts, affinity)
+
+	if len(ports) == 0 {
+		return "", fmt.Errorf("no ports specified for load balancer %s", name)
+	}
+	if len(ports) > 1 {
+		// TODO: Support multiple ports with one VIP per port.
+		return "", fmt.Errorf("multiple ports are not yet supported in openstack load balancers")
+	}
+	port := ports[0]
 
 	var persistence *vips.SessionPersistence
 	switch affinity {
@@ -665,3 +674,9 @@ func (os *OpenStack) GetZone() (cloudprovider.Zone, error) {
 
 	return cloudprovider.Zone{Region: os.region}, nil
 }
+
+// GetZoneByInstance returns the zone of the current region, which all the
+// instances we list are in.
+func (os *OpenStack) GetZoneByInstance(name string) (cloudprovider.Zone, error) {
+	return cloudprovider.Zone{Region: os.region}, nil
+}
@@ -413,3 +413,9 @@ func (os *Rackspace) GetZone() (cloudprovider.Zone, error) {
 
 	return cloudprovider.Zone{Region: os.region}, nil
 }
+
+// GetZoneByInstance returns the zone of the current region, which all the
+// instances we list are in.
+func (os *Rackspace) GetZoneByInstance(name string) (cloudprovider.Zone, error) {
+	return cloudprovider.Zone{Region: os.region}, nil
+}
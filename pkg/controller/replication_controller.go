@@ -97,7 +97,7 @@ func (r RealPodControl) createReplica(namespace string, controller api.Replicati
 }
 
 func (r RealPodControl) deletePod(namespace, podID string) error {
-	return r.kubeClient.Pods(namespace).Delete(podID)
+	return r.kubeClient.Pods(namespace).Delete(podID, nil)
 }
 
 // NewReplicationManager creates a new ReplicationManager.
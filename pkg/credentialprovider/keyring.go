@@ -17,10 +17,13 @@ limitations under the License.
 package credentialprovider
 
 import (
+	"fmt"
 	"net/url"
 	"sort"
 	"strings"
 
+	"github.com/cnaize/kubernetes/pkg/api"
+
 	docker "github.com/fsouza/go-dockerclient"
 	"github.com/golang/glog"
 )
@@ -144,6 +147,52 @@ func (dk *lazyDockerKeyring) Lookup(image string) (docker.AuthConfiguration, boo
 	return keyring.Lookup(image)
 }
 
+// UnionDockerKeyring delegates to a set of keyrings, returning the
+// credentials of the first keyring that has a match for the image.
+type UnionDockerKeyring []DockerKeyring
+
+// Lookup implements the DockerKeyring method for fetching credentials
+// based on image name.
+func (k UnionDockerKeyring) Lookup(image string) (docker.AuthConfiguration, bool) {
+	for _, subKeyring := range k {
+		if subKeyring == nil {
+			continue
+		}
+		if auth, ok := subKeyring.Lookup(image); ok {
+			return auth, true
+		}
+	}
+
+	return docker.AuthConfiguration{}, false
+}
+
+// MakeDockerKeyring builds a keyring from the dockercfg data held in the given
+// secrets, which takes precedence over the credentials of defaultKeyring.
+func MakeDockerKeyring(passedSecrets []api.Secret, defaultKeyring DockerKeyring) (DockerKeyring, error) {
+	passedCredentials := []DockerConfig{}
+	for _, passedSecret := range passedSecrets {
+		dockercfgBytes, exists := passedSecret.Data[api.DockerConfigKey]
+		if !exists || len(dockercfgBytes) == 0 {
+			continue
+		}
+		cfg, err := readDockerConfigFileFromBytes(dockercfgBytes)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read dockercfg from secret %q: %v", passedSecret.Name, err)
+		}
+		passedCredentials = append(passedCredentials, cfg)
+	}
+
+	if len(passedCredentials) == 0 {
+		return defaultKeyring, nil
+	}
+
+	basicKeyring := &BasicDockerKeyring{}
+	for _, currCredentials := range passedCredentials {
+		basicKeyring.Add(currCredentials)
+	}
+	return UnionDockerKeyring{basicKeyring, defaultKeyring}, nil
+}
+
 type FakeKeyring struct {
 	auth docker.AuthConfiguration
 	ok   bool
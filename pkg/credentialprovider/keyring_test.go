@@ -20,6 +20,10 @@ import (
 	"encoding/base64"
 	"fmt"
 	"testing"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+
+	docker "github.com/fsouza/go-dockerclient"
 )
 
 func TestDockerKeyringFromBytes(t *testing.T) {
@@ -259,3 +263,59 @@ func TestLazyKeyring(t *testing.T) {
 		t.Errorf("Unexpected number of Provide calls: %v", provider.Count)
 	}
 }
+
+func TestMakeDockerKeyringFromSecrets(t *testing.T) {
+	auth := base64.StdEncoding.EncodeToString([]byte("foo:bar"))
+	secrets := []api.Secret{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "registry-creds"},
+			Type:       api.SecretTypeDockercfg,
+			Data: map[string][]byte{
+				api.DockerConfigKey: []byte(fmt.Sprintf(`{"registry.example.com": {"email": "foo@example.com", "auth": %q}}`, auth)),
+			},
+		},
+	}
+	defaultKeyring := &FakeKeyring{auth: docker.AuthConfiguration{Username: "node"}, ok: true}
+
+	keyring, err := MakeDockerKeyring(secrets, defaultKeyring)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	val, ok := keyring.Lookup("registry.example.com/foo/bar")
+	if !ok {
+		t.Fatalf("Expected credentials for registry.example.com")
+	}
+	if val.Username != "foo" || val.Password != "bar" || val.Email != "foo@example.com" {
+		t.Errorf("Unexpected credentials from secret: %#v", val)
+	}
+
+	val, ok = keyring.Lookup("other.example.com/foo/bar")
+	if !ok || val.Username != "node" {
+		t.Errorf("Expected to fall back to the node keyring, got %#v, %v", val, ok)
+	}
+}
+
+func TestMakeDockerKeyringWithoutSecrets(t *testing.T) {
+	defaultKeyring := &FakeKeyring{}
+	keyring, err := MakeDockerKeyring(nil, defaultKeyring)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if keyring != defaultKeyring {
+		t.Errorf("Expected the default keyring, got %#v", keyring)
+	}
+}
+
+func TestMakeDockerKeyringInvalidSecret(t *testing.T) {
+	secrets := []api.Secret{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "bad-creds"},
+			Type:       api.SecretTypeDockercfg,
+			Data:       map[string][]byte{api.DockerConfigKey: []byte("not json")},
+		},
+	}
+	if _, err := MakeDockerKeyring(secrets, &FakeKeyring{}); err == nil {
+		t.Errorf("Expected an error for an unparseable dockercfg")
+	}
+}
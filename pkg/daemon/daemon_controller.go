@@ -0,0 +1,404 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/controller"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/validation"
+	"github.com/golang/glog"
+)
+
+// DaemonManager is responsible for synchronizing DaemonSet objects stored in
+// the system with the pods running them on the nodes of the cluster.
+type DaemonManager struct {
+	kubeClient client.Interface
+	podControl PodControlInterface
+	syncTime   <-chan time.Time
+
+	// To allow injection of syncDaemonSet for testing.
+	syncHandler func(ds api.DaemonSet) error
+
+	// syncLock serializes the syncs, so that two passes never both see a node
+	// without a pod and both create one.
+	syncLock sync.Mutex
+	// nodeLabels holds the labels of the nodes seen by watchNodes, by name.
+	nodeLabels map[string]labels.Set
+}
+
+// PodControlInterface is an interface that knows how to add or delete pods
+// created as an interface to allow testing.
+type PodControlInterface interface {
+	// createPod creates a new pod from the template of the given daemon set,
+	// bound to the given node.
+	createPod(namespace string, ds api.DaemonSet, nodeName string) error
+	// deletePod deletes the pod identified by podID.
+	deletePod(namespace string, podID string) error
+}
+
+// RealPodControl is the default implementation of PodControlInterface.
+type RealPodControl struct {
+	kubeClient client.Interface
+}
+
+// Time period of main daemon controller sync loop
+const DefaultSyncPeriod = 10 * time.Second
+
+// newDaemonPod returns a pod built from the template of the daemon set which
+// is bound directly to the named node, bypassing the scheduler.
+func newDaemonPod(ds api.DaemonSet, nodeName string) (*api.Pod, error) {
+	desiredLabels := make(labels.Set)
+	for k, v := range ds.Spec.Template.Labels {
+		desiredLabels[k] = v
+	}
+	desiredAnnotations := make(labels.Set)
+	for k, v := range ds.Spec.Template.Annotations {
+		desiredAnnotations[k] = v
+	}
+
+	// use the dash (if the name isn't too long) to make the pod name a bit prettier
+	prefix := fmt.Sprintf("%s-", ds.Name)
+	if ok, _ := validation.ValidatePodName(prefix, true); !ok {
+		prefix = ds.Name
+	}
+
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Labels:       desiredLabels,
+			Annotations:  desiredAnnotations,
+			GenerateName: prefix,
+		},
+	}
+	if err := api.Scheme.Convert(&ds.Spec.Template.Spec, &pod.Spec); err != nil {
+		return nil, fmt.Errorf("unable to convert pod template: %v", err)
+	}
+	if labels.Set(pod.Labels).AsSelector().Empty() {
+		return nil, fmt.Errorf("unable to create pod for daemon set %s, no labels", ds.Name)
+	}
+	pod.Spec.Host = nodeName
+	return pod, nil
+}
+
+func (r RealPodControl) createPod(namespace string, ds api.DaemonSet, nodeName string) error {
+	pod, err := newDaemonPod(ds, nodeName)
+	if err != nil {
+		return err
+	}
+	if _, err := r.kubeClient.Pods(namespace).Create(pod); err != nil {
+		return fmt.Errorf("unable to create pod for daemon set %s on node %s: %v", ds.Name, nodeName, err)
+	}
+	return nil
+}
+
+func (r RealPodControl) deletePod(namespace, podID string) error {
+	return r.kubeClient.Pods(namespace).Delete(podID, nil)
+}
+
+// NewDaemonManager creates a new DaemonManager.
+func NewDaemonManager(kubeClient client.Interface) *DaemonManager {
+	dm := &DaemonManager{
+		kubeClient: kubeClient,
+		podControl: RealPodControl{
+			kubeClient: kubeClient,
+		},
+		nodeLabels: map[string]labels.Set{},
+	}
+	dm.syncHandler = dm.syncDaemonSet
+	return dm
+}
+
+// Run begins watching and syncing.
+func (dm *DaemonManager) Run(period time.Duration) {
+	dm.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { dm.watchDaemonSets(&resourceVersion) }, period)
+	nodeResourceVersion := ""
+	go util.Forever(func() { dm.watchNodes(&nodeResourceVersion) }, period)
+}
+
+// resourceVersion is a pointer to the resource version to use/update.
+func (dm *DaemonManager) watchDaemonSets(resourceVersion *string) {
+	watching, err := dm.kubeClient.DaemonSets(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-dm.syncTime:
+			dm.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from watch during sync: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			glog.V(4).Infof("Got watch: %#v", event)
+			ds, ok := event.Object.(*api.DaemonSet)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = ds.ResourceVersion
+			// A deleted daemon set leaves its pods behind, there is nothing left to sync.
+			if event.Type == watch.Deleted {
+				continue
+			}
+			glog.V(4).Infof("About to sync from watch: %v", ds.Name)
+			dm.syncDaemonSets([]api.DaemonSet{*ds})
+		}
+	}
+}
+
+// watchNodes resyncs the daemon sets which select a node that joins, leaves
+// or changes its labels, so that pods follow the set of matching nodes.
+// Other node updates, such as status heartbeats, are ignored.
+// resourceVersion is a pointer to the resource version to use/update.
+func (dm *DaemonManager) watchNodes(resourceVersion *string) {
+	if len(*resourceVersion) == 0 {
+		// Start from the current nodes, so that their first updates are not
+		// taken for nodes joining.
+		nodes, err := dm.kubeClient.Nodes().List()
+		if err != nil {
+			util.HandleError(fmt.Errorf("unable to list nodes: %v", err))
+			time.Sleep(5 * time.Second)
+			return
+		}
+		dm.nodeLabels = map[string]labels.Set{}
+		for i := range nodes.Items {
+			dm.nodeLabels[nodes.Items[i].Name] = labels.Set(nodes.Items[i].Labels)
+		}
+		*resourceVersion = nodes.ResourceVersion
+	}
+	watching, err := dm.kubeClient.Nodes().Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch nodes: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		event, open := <-watching.ResultChan()
+		if !open {
+			return
+		}
+		if event.Type == watch.Error {
+			util.HandleError(fmt.Errorf("error from node watch: %v", errors.FromObject(event.Object)))
+			*resourceVersion = ""
+			continue
+		}
+		node, ok := event.Object.(*api.Node)
+		if !ok {
+			util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+			continue
+		}
+		*resourceVersion = node.ResourceVersion
+		dm.handleNodeEvent(event.Type, node)
+	}
+}
+
+// handleNodeEvent records the node, and syncs the daemon sets which selected
+// the node before the event and no longer do, or the other way around.
+func (dm *DaemonManager) handleNodeEvent(eventType watch.EventType, node *api.Node) {
+	oldLabels, existed := dm.nodeLabels[node.Name]
+	newLabels, exists := labels.Set(node.Labels), eventType != watch.Deleted
+	if exists {
+		dm.nodeLabels[node.Name] = newLabels
+	} else {
+		delete(dm.nodeLabels, node.Name)
+	}
+	if existed == exists && (!exists || labelsEqual(oldLabels, newLabels)) {
+		return
+	}
+
+	list, err := dm.kubeClient.DaemonSets(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list daemon sets: %v", err))
+		return
+	}
+	affected := []api.DaemonSet{}
+	for _, ds := range list.Items {
+		nodeSelector := labels.Set(ds.Spec.NodeSelector).AsSelector()
+		wasSelected := existed && nodeSelector.Matches(oldLabels)
+		isSelected := exists && nodeSelector.Matches(newLabels)
+		if wasSelected != isSelected {
+			affected = append(affected, ds)
+		}
+	}
+	glog.V(4).Infof("Node %s %s, syncing %d daemon sets", node.Name, eventType, len(affected))
+	dm.syncDaemonSets(affected)
+}
+
+// labelsEqual returns true if both sets hold the same labels.
+func labelsEqual(a, b labels.Set) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if value, found := b[k]; !found || value != v {
+			return false
+		}
+	}
+	return true
+}
+
+// syncDaemonSet creates a pod on every node selected by the daemon set which
+// does not run one yet, deletes duplicate pods and pods on nodes which are
+// gone or no longer selected, and records what it observed in the status of
+// the daemon set.
+func (dm *DaemonManager) syncDaemonSet(ds api.DaemonSet) error {
+	if ds.Spec.Template == nil {
+		return fmt.Errorf("daemon set %s/%s has no pod template", ds.Namespace, ds.Name)
+	}
+	nodeList, err := dm.kubeClient.Nodes().List()
+	if err != nil {
+		return err
+	}
+	s := labels.Set(ds.Spec.Selector).AsSelector()
+	podList, err := dm.kubeClient.Pods(ds.Namespace).List(s)
+	if err != nil {
+		return err
+	}
+	nodeToPods := map[string][]api.Pod{}
+	for _, pod := range controller.FilterActivePods(podList.Items) {
+		nodeToPods[pod.Spec.Host] = append(nodeToPods[pod.Spec.Host], pod)
+	}
+
+	nodeSelector := labels.Set(ds.Spec.NodeSelector).AsSelector()
+	status := api.DaemonSetStatus{}
+	nodesNeedingPods := []string{}
+	podsToDelete := []api.Pod{}
+	for _, node := range nodeList.Items {
+		daemonPods := nodeToPods[node.Name]
+		delete(nodeToPods, node.Name)
+		if nodeSelector.Matches(labels.Set(node.Labels)) {
+			status.DesiredNumberScheduled++
+			if len(daemonPods) == 0 {
+				nodesNeedingPods = append(nodesNeedingPods, node.Name)
+				continue
+			}
+			status.CurrentNumberScheduled++
+			// Keep a single pod per node.
+			podsToDelete = append(podsToDelete, daemonPods[1:]...)
+		} else if len(daemonPods) > 0 {
+			status.NumberMisscheduled++
+			podsToDelete = append(podsToDelete, daemonPods...)
+		}
+	}
+	// What is left runs on nodes which have left the cluster, or was never
+	// bound to a node at all.
+	for host, daemonPods := range nodeToPods {
+		if host != "" {
+			status.NumberMisscheduled++
+		}
+		podsToDelete = append(podsToDelete, daemonPods...)
+	}
+
+	wait := sync.WaitGroup{}
+	if len(nodesNeedingPods) > 0 {
+		glog.V(2).Infof("Daemon set %q is missing pods on %d nodes, creating them", ds.Name, len(nodesNeedingPods))
+	}
+	wait.Add(len(nodesNeedingPods))
+	for i := range nodesNeedingPods {
+		go func(ix int) {
+			defer wait.Done()
+			if err := dm.podControl.createPod(ds.Namespace, ds, nodesNeedingPods[ix]); err != nil {
+				util.HandleError(err)
+			}
+		}(i)
+	}
+	if len(podsToDelete) > 0 {
+		glog.V(2).Infof("Daemon set %q has %d extra pods, deleting them", ds.Name, len(podsToDelete))
+	}
+	wait.Add(len(podsToDelete))
+	for i := range podsToDelete {
+		go func(ix int) {
+			defer wait.Done()
+			if err := dm.podControl.deletePod(ds.Namespace, podsToDelete[ix].Name); err != nil {
+				util.HandleError(fmt.Errorf("unable to delete pod %s of daemon set %s: %v", podsToDelete[ix].Name, ds.Name, err))
+			}
+		}(i)
+	}
+	wait.Wait()
+
+	if !api.Semantic.DeepEqual(status, ds.Status) {
+		ds.Status = status
+		if _, err := dm.kubeClient.DaemonSets(ds.Namespace).UpdateStatus(&ds); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (dm *DaemonManager) synchronize() {
+	// TODO: remove this method completely and rely on the watch.
+	// Add resource version tracking to watch to make this work.
+	list, err := dm.kubeClient.DaemonSets(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("synchronization error: %v", err))
+		return
+	}
+	dm.syncDaemonSets(list.Items)
+}
+
+// syncDaemonSets syncs the daemon sets in parallel, once the syncs in progress
+// are done.
+func (dm *DaemonManager) syncDaemonSets(daemonSets []api.DaemonSet) {
+	dm.syncLock.Lock()
+	defer dm.syncLock.Unlock()
+	wg := sync.WaitGroup{}
+	wg.Add(len(daemonSets))
+	for ix := range daemonSets {
+		go func(ix int) {
+			defer wg.Done()
+			glog.V(4).Infof("sync of %v/%v", daemonSets[ix].Namespace, daemonSets[ix].Name)
+			if err := dm.syncHandler(daemonSets[ix]); err != nil {
+				util.HandleError(fmt.Errorf("error synchronizing: %v", err))
+			}
+		}(ix)
+	}
+	wg.Wait()
+}
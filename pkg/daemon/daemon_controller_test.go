@@ -0,0 +1,378 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemon
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+type FakePodControl struct {
+	createdOnNodes []string
+	deletePodName  []string
+	err            error
+	lock           sync.Mutex
+}
+
+func (f *FakePodControl) createPod(namespace string, ds api.DaemonSet, nodeName string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.createdOnNodes = append(f.createdOnNodes, nodeName)
+	return nil
+}
+
+func (f *FakePodControl) deletePod(namespace string, podName string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.deletePodName = append(f.deletePodName, podName)
+	return nil
+}
+
+func newDaemonSet(nodeSelector map[string]string) api.DaemonSet {
+	return api.DaemonSet{
+		ObjectMeta: api.ObjectMeta{Name: "foobar", Namespace: api.NamespaceDefault, ResourceVersion: "18"},
+		Spec: api.DaemonSetSpec{
+			Selector:     map[string]string{"foo": "bar"},
+			NodeSelector: nodeSelector,
+			Template: &api.PodTemplateSpec{
+				ObjectMeta: api.ObjectMeta{
+					Labels: map[string]string{
+						"foo": "bar",
+					},
+				},
+				Spec: api.PodSpec{
+					RestartPolicy: api.RestartPolicyAlways,
+					Containers: []api.Container{
+						{Image: "foo/bar"},
+					},
+				},
+			},
+		},
+	}
+}
+
+// newNodeList returns nodes named after their index, labeled "role=logging"
+// when the corresponding entry of logging is true.
+func newNodeList(logging ...bool) api.NodeList {
+	nodes := []api.Node{}
+	for i, l := range logging {
+		node := api.Node{ObjectMeta: api.ObjectMeta{Name: fmt.Sprintf("node%d", i)}}
+		if l {
+			node.Labels = map[string]string{"role": "logging"}
+		}
+		nodes = append(nodes, node)
+	}
+	return api.NodeList{Items: nodes}
+}
+
+// newPodList returns running pods bound to the given hosts.
+func newPodList(hosts ...string) api.PodList {
+	pods := []api.Pod{}
+	for i, host := range hosts {
+		pods = append(pods, api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:   fmt.Sprintf("pod%d", i),
+				Labels: map[string]string{"foo": "bar"},
+			},
+			Spec:   api.PodSpec{Host: host},
+			Status: api.PodStatus{Phase: api.PodRunning},
+		})
+	}
+	return api.PodList{Items: pods}
+}
+
+func TestSyncDaemonSet(t *testing.T) {
+	logging := map[string]string{"role": "logging"}
+	testCases := map[string]struct {
+		nodeSelector map[string]string
+		nodes        []bool
+		pods         []string
+		podErr       error
+
+		expectedCreates      []string
+		expectedDeletes      []string
+		expectedDesired      int
+		expectedCurrent      int
+		expectedMisscheduled int
+	}{
+		"creates a pod on every node": {
+			nodes:           []bool{false, true},
+			expectedCreates: []string{"node0", "node1"},
+			expectedDesired: 2,
+		},
+		"node selector restricts nodes": {
+			nodeSelector:    logging,
+			nodes:           []bool{false, true},
+			expectedCreates: []string{"node1"},
+			expectedDesired: 1,
+		},
+		"correct number of pods": {
+			nodes:           []bool{false, false},
+			pods:            []string{"node0", "node1"},
+			expectedDesired: 2,
+			expectedCurrent: 2,
+		},
+		"duplicate pods are deleted": {
+			nodes:           []bool{false},
+			pods:            []string{"node0", "node0"},
+			expectedDeletes: []string{"pod1"},
+			expectedDesired: 1,
+			expectedCurrent: 1,
+		},
+		"pods on nodes no longer selected are deleted": {
+			nodeSelector:         logging,
+			nodes:                []bool{false, true},
+			pods:                 []string{"node0", "node1"},
+			expectedDeletes:      []string{"pod0"},
+			expectedDesired:      1,
+			expectedCurrent:      1,
+			expectedMisscheduled: 1,
+		},
+		"pods on nodes which left are deleted": {
+			nodes:                []bool{false},
+			pods:                 []string{"node0", "node7"},
+			expectedDeletes:      []string{"pod1"},
+			expectedDesired:      1,
+			expectedCurrent:      1,
+			expectedMisscheduled: 1,
+		},
+		"failed pod creation": {
+			nodes:           []bool{false},
+			podErr:          fmt.Errorf("fake error"),
+			expectedDesired: 1,
+		},
+	}
+
+	for name, tc := range testCases {
+		fakeClient := client.Fake{MinionsList: newNodeList(tc.nodes...), PodsList: newPodList(tc.pods...)}
+		fakePodControl := FakePodControl{err: tc.podErr}
+		manager := NewDaemonManager(&fakeClient)
+		manager.podControl = &fakePodControl
+
+		if err := manager.syncDaemonSet(newDaemonSet(tc.nodeSelector)); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+
+		if e, a := len(tc.expectedCreates), len(fakePodControl.createdOnNodes); e != a {
+			t.Errorf("%s: expected creates on %v, got %v", name, tc.expectedCreates, fakePodControl.createdOnNodes)
+		} else {
+			for _, node := range tc.expectedCreates {
+				if !containsString(fakePodControl.createdOnNodes, node) {
+					t.Errorf("%s: expected a create on %s, got %v", name, node, fakePodControl.createdOnNodes)
+				}
+			}
+		}
+		if e, a := len(tc.expectedDeletes), len(fakePodControl.deletePodName); e != a {
+			t.Errorf("%s: expected deletes of %v, got %v", name, tc.expectedDeletes, fakePodControl.deletePodName)
+		} else {
+			for _, pod := range tc.expectedDeletes {
+				if !containsString(fakePodControl.deletePodName, pod) {
+					t.Errorf("%s: expected %s to be deleted, got %v", name, pod, fakePodControl.deletePodName)
+				}
+			}
+		}
+		status := fakeClient.DaemonSetStatus.Status
+		if e, a := tc.expectedDesired, status.DesiredNumberScheduled; e != a {
+			t.Errorf("%s: expected %d desired, got %d", name, e, a)
+		}
+		if e, a := tc.expectedCurrent, status.CurrentNumberScheduled; e != a {
+			t.Errorf("%s: expected %d current, got %d", name, e, a)
+		}
+		if e, a := tc.expectedMisscheduled, status.NumberMisscheduled; e != a {
+			t.Errorf("%s: expected %d misscheduled, got %d", name, e, a)
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSyncDaemonSetNoStatusUpdate(t *testing.T) {
+	fakeClient := client.Fake{MinionsList: newNodeList(false), PodsList: newPodList("node0")}
+	fakePodControl := FakePodControl{}
+	manager := NewDaemonManager(&fakeClient)
+	manager.podControl = &fakePodControl
+
+	ds := newDaemonSet(nil)
+	ds.Status = api.DaemonSetStatus{CurrentNumberScheduled: 1, DesiredNumberScheduled: 1}
+	if err := manager.syncDaemonSet(ds); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, action := range fakeClient.Actions {
+		if action.Action == "update-status-daemonSet" {
+			t.Errorf("unexpected status update: %#v", action)
+		}
+	}
+}
+
+func TestSynchronize(t *testing.T) {
+	fakeClient := client.Fake{DaemonSetsList: api.DaemonSetList{Items: []api.DaemonSet{newDaemonSet(nil), newDaemonSet(nil)}}}
+	manager := NewDaemonManager(&fakeClient)
+	var lock sync.Mutex
+	synced := 0
+	manager.syncHandler = func(ds api.DaemonSet) error {
+		lock.Lock()
+		defer lock.Unlock()
+		synced++
+		return nil
+	}
+	manager.synchronize()
+	if synced != 2 {
+		t.Errorf("expected 2 daemon sets to be synced, got %d", synced)
+	}
+}
+
+func TestHandleNodeEvent(t *testing.T) {
+	logging := map[string]string{"role": "logging"}
+	all := newDaemonSet(nil)
+	all.Name = "all"
+	loggingOnly := newDaemonSet(logging)
+	loggingOnly.Name = "logging"
+	testCases := map[string]struct {
+		known     map[string]labels.Set
+		eventType watch.EventType
+		labels    map[string]string
+		synced    []string
+	}{
+		"heartbeat": {
+			known:     map[string]labels.Set{"node0": logging},
+			eventType: watch.Modified,
+			labels:    logging,
+			synced:    []string{},
+		},
+		"new node": {
+			known:     map[string]labels.Set{},
+			eventType: watch.Added,
+			labels:    nil,
+			synced:    []string{"all"},
+		},
+		"labeled": {
+			known:     map[string]labels.Set{"node0": nil},
+			eventType: watch.Modified,
+			labels:    logging,
+			synced:    []string{"logging"},
+		},
+		"deleted": {
+			known:     map[string]labels.Set{"node0": logging},
+			eventType: watch.Deleted,
+			labels:    logging,
+			synced:    []string{"all", "logging"},
+		},
+	}
+	for name, test := range testCases {
+		fakeClient := client.Fake{DaemonSetsList: api.DaemonSetList{Items: []api.DaemonSet{all, loggingOnly}}}
+		manager := NewDaemonManager(&fakeClient)
+		manager.nodeLabels = test.known
+		var lock sync.Mutex
+		synced := []string{}
+		manager.syncHandler = func(ds api.DaemonSet) error {
+			lock.Lock()
+			defer lock.Unlock()
+			synced = append(synced, ds.Name)
+			return nil
+		}
+		node := &api.Node{ObjectMeta: api.ObjectMeta{Name: "node0", Labels: test.labels}}
+		manager.handleNodeEvent(test.eventType, node)
+		sort.Strings(synced)
+		if !reflect.DeepEqual(synced, test.synced) {
+			t.Errorf("%s: expected %v to be synced, got %v", name, test.synced, synced)
+		}
+		if _, found := manager.nodeLabels["node0"]; found != (test.eventType != watch.Deleted) {
+			t.Errorf("%s: unexpected known nodes %v", name, manager.nodeLabels)
+		}
+	}
+}
+
+func TestSyncDaemonSetsSerialized(t *testing.T) {
+	manager := NewDaemonManager(&client.Fake{})
+	var lock sync.Mutex
+	running, maxRunning := 0, 0
+	manager.syncHandler = func(ds api.DaemonSet) error {
+		lock.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		lock.Lock()
+		running--
+		lock.Unlock()
+		return nil
+	}
+	wg := sync.WaitGroup{}
+	wg.Add(3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			defer wg.Done()
+			manager.syncDaemonSets([]api.DaemonSet{newDaemonSet(nil)})
+		}()
+	}
+	wg.Wait()
+	if maxRunning != 1 {
+		t.Errorf("expected syncs of a single daemon set to be serialized, got %d at once", maxRunning)
+	}
+}
+
+func TestNewDaemonPod(t *testing.T) {
+	pod, err := newDaemonPod(newDaemonSet(nil), "node1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pod.Spec.Host != "node1" {
+		t.Errorf("expected pod to be bound to node1, got %q", pod.Spec.Host)
+	}
+	if pod.GenerateName != "foobar-" {
+		t.Errorf("unexpected generate name: %q", pod.GenerateName)
+	}
+	if pod.Labels["foo"] != "bar" {
+		t.Errorf("expected template labels, got %v", pod.Labels)
+	}
+}
+
+func TestCreatePod(t *testing.T) {
+	fakeClient := client.Fake{}
+	podControl := RealPodControl{kubeClient: &fakeClient}
+	if err := podControl.createPod(api.NamespaceDefault, newDaemonSet(nil), "node1"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeClient.Actions) != 1 || fakeClient.Actions[0].Action != "create-pod" {
+		t.Errorf("unexpected actions: %#v", fakeClient.Actions)
+	}
+}
@@ -0,0 +1,513 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/controller"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+const (
+	// RevisionAnnotation is the annotation of a replication controller which
+	// records the revision of the deployment template it was created from.
+	RevisionAnnotation = "deployment.kubernetes.io/revision"
+
+	// PodTemplateHashLabel is added to the selector and to the pod template of
+	// the replication controllers of a deployment, so that the pods of
+	// different revisions do not overlap.
+	PodTemplateHashLabel = "deployment.kubernetes.io/pod-template-hash"
+)
+
+// Time period of main deployment controller sync loop
+const DefaultSyncPeriod = 10 * time.Second
+
+// DeploymentManager is responsible for synchronizing Deployment objects stored
+// in the system with the replication controllers running their revisions.
+type DeploymentManager struct {
+	kubeClient client.Interface
+	syncTime   <-chan time.Time
+
+	// To allow injection of syncDeployment for testing.
+	syncHandler func(deployment api.Deployment) error
+}
+
+// NewDeploymentManager creates a new DeploymentManager.
+func NewDeploymentManager(kubeClient client.Interface) *DeploymentManager {
+	dm := &DeploymentManager{
+		kubeClient: kubeClient,
+	}
+	dm.syncHandler = dm.syncDeployment
+	return dm
+}
+
+// Run begins watching and syncing.
+func (dm *DeploymentManager) Run(period time.Duration) {
+	dm.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { dm.watchDeployments(&resourceVersion) }, period)
+}
+
+// resourceVersion is a pointer to the resource version to use/update.
+func (dm *DeploymentManager) watchDeployments(resourceVersion *string) {
+	watching, err := dm.kubeClient.Deployments(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-dm.syncTime:
+			dm.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from watch during sync: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			glog.V(4).Infof("Got watch: %#v", event)
+			deployment, ok := event.Object.(*api.Deployment)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = deployment.ResourceVersion
+			// A deleted deployment leaves its replication controllers behind,
+			// there is nothing left to sync.
+			if event.Type == watch.Deleted {
+				continue
+			}
+			glog.V(4).Infof("About to sync from watch: %v", deployment.Name)
+			if err := dm.syncHandler(*deployment); err != nil {
+				util.HandleError(fmt.Errorf("unexpected sync error: %v", err))
+			}
+		}
+	}
+}
+
+// podTemplateHash returns a hash of the given template, identifying the
+// replication controller which runs it.
+func podTemplateHash(template *api.PodTemplateSpec) string {
+	hasher := fnv.New32a()
+	// The rest of the metadata of a template is meaningless, and must not
+	// change the hash.
+	util.DeepHashObject(hasher, []interface{}{template.Labels, template.Annotations, template.Spec})
+	return fmt.Sprintf("%d", hasher.Sum32())
+}
+
+// revisionOf returns the revision recorded on the given replication
+// controller, or 0 if it has none.
+func revisionOf(rc *api.ReplicationController) int64 {
+	revision, err := strconv.ParseInt(rc.Annotations[RevisionAnnotation], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return revision
+}
+
+// byRevision sorts replication controllers from the oldest revision to the newest.
+type byRevision []*api.ReplicationController
+
+func (r byRevision) Len() int      { return len(r) }
+func (r byRevision) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r byRevision) Less(i, j int) bool {
+	if revisionOf(r[i]) == revisionOf(r[j]) {
+		return r[i].Name < r[j].Name
+	}
+	return revisionOf(r[i]) < revisionOf(r[j])
+}
+
+// copyTemplate returns a deep copy of template, with the pod template hash
+// label added when hash is not empty and removed otherwise.
+func copyTemplate(template *api.PodTemplateSpec, hash string) (*api.PodTemplateSpec, error) {
+	result := &api.PodTemplateSpec{}
+	if err := api.Scheme.Convert(template, result); err != nil {
+		return nil, fmt.Errorf("unable to convert pod template: %v", err)
+	}
+	result.Labels = map[string]string{}
+	for k, v := range template.Labels {
+		result.Labels[k] = v
+	}
+	if len(hash) != 0 {
+		result.Labels[PodTemplateHashLabel] = hash
+	} else {
+		delete(result.Labels, PodTemplateHashLabel)
+	}
+	return result, nil
+}
+
+// newReplicationController returns the replication controller running the
+// current template of the deployment as the given revision.
+func newReplicationController(deployment *api.Deployment, hash string, revision int64, replicas int) (*api.ReplicationController, error) {
+	template, err := copyTemplate(deployment.Spec.Template, hash)
+	if err != nil {
+		return nil, err
+	}
+	selector := map[string]string{PodTemplateHashLabel: hash}
+	for k, v := range deployment.Spec.Selector {
+		selector[k] = v
+	}
+	return &api.ReplicationController{
+		ObjectMeta: api.ObjectMeta{
+			Name:        fmt.Sprintf("%s-%s", deployment.Name, hash),
+			Namespace:   deployment.Namespace,
+			Labels:      template.Labels,
+			Annotations: map[string]string{RevisionAnnotation: strconv.FormatInt(revision, 10)},
+		},
+		Spec: api.ReplicationControllerSpec{
+			Replicas: replicas,
+			Selector: selector,
+			Template: template,
+		},
+	}, nil
+}
+
+// isPodAvailable returns true if the pod is running and ready.
+func isPodAvailable(pod *api.Pod) bool {
+	if pod.Status.Phase != api.PodRunning {
+		return false
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == api.PodReady && c.Status == api.ConditionTrue {
+			return true
+		}
+	}
+	return false
+}
+
+// countPods returns the number of active pods of the given replication
+// controllers, and how many of them are available.
+func countPods(pods []api.Pod, rcs ...*api.ReplicationController) (active, available int) {
+	for i := range pods {
+		for _, rc := range rcs {
+			if labels.Set(rc.Spec.Selector).AsSelector().Matches(labels.Set(pods[i].Labels)) {
+				active++
+				if isPodAvailable(&pods[i]) {
+					available++
+				}
+				break
+			}
+		}
+	}
+	return
+}
+
+// rollingUpdateLimits returns how many pods may be created above, and how
+// many may be missing below, the desired replicas of the deployment.
+func rollingUpdateLimits(deployment *api.Deployment) (maxSurge, maxUnavailable int, err error) {
+	params := deployment.Spec.Strategy.RollingUpdate
+	if params == nil {
+		return 0, 0, fmt.Errorf("deployment %s/%s has no rolling update parameters", deployment.Namespace, deployment.Name)
+	}
+	maxSurge, err = util.GetValueFromIntOrPercent(&params.MaxSurge, deployment.Spec.Replicas, true)
+	if err != nil {
+		return 0, 0, err
+	}
+	maxUnavailable, err = util.GetValueFromIntOrPercent(&params.MaxUnavailable, deployment.Spec.Replicas, false)
+	if err != nil {
+		return 0, 0, err
+	}
+	if maxSurge == 0 && maxUnavailable == 0 {
+		// Percentages of a small number of replicas may both round to zero,
+		// which would block the rollout.
+		maxUnavailable = 1
+	}
+	return maxSurge, maxUnavailable, nil
+}
+
+// getReplicationControllers returns the replication controllers in the
+// namespace of the deployment whose pods are selected by the deployment,
+// including those which were not created by it.
+func (dm *DeploymentManager) getReplicationControllers(deployment *api.Deployment) ([]*api.ReplicationController, error) {
+	rcList, err := dm.kubeClient.ReplicationControllers(deployment.Namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	selector := labels.Set(deployment.Spec.Selector).AsSelector()
+	rcs := []*api.ReplicationController{}
+	for i := range rcList.Items {
+		rc := &rcList.Items[i]
+		if rc.Spec.Template != nil && selector.Matches(labels.Set(rc.Spec.Template.Labels)) {
+			rcs = append(rcs, rc)
+		}
+	}
+	return rcs, nil
+}
+
+// syncDeployment rolls out the current template of the given deployment
+// according to its strategy, and records the progress of the rollout in the
+// status of the deployment.
+func (dm *DeploymentManager) syncDeployment(deployment api.Deployment) error {
+	if deployment.Spec.Template == nil {
+		return fmt.Errorf("deployment %s/%s has no pod template", deployment.Namespace, deployment.Name)
+	}
+	rcs, err := dm.getReplicationControllers(&deployment)
+	if err != nil {
+		return err
+	}
+	if deployment.Spec.RollbackTo != nil {
+		return dm.rollback(deployment, rcs)
+	}
+	s := labels.Set(deployment.Spec.Selector).AsSelector()
+	podList, err := dm.kubeClient.Pods(deployment.Namespace).List(s)
+	if err != nil {
+		return err
+	}
+	pods := controller.FilterActivePods(podList.Items)
+
+	hash := podTemplateHash(deployment.Spec.Template)
+	var newRC *api.ReplicationController
+	oldRCs := []*api.ReplicationController{}
+	for _, rc := range rcs {
+		if rc.Spec.Template.Labels[PodTemplateHashLabel] == hash {
+			newRC = rc
+		} else {
+			oldRCs = append(oldRCs, rc)
+		}
+	}
+	sort.Sort(byRevision(oldRCs))
+
+	if !deployment.Spec.Paused {
+		if newRC, err = dm.rollout(&deployment, hash, newRC, oldRCs, pods); err != nil {
+			return err
+		}
+	}
+
+	status := api.DeploymentStatus{}
+	status.Replicas, status.AvailableReplicas = countPods(pods, rcs...)
+	if newRC != nil {
+		status.UpdatedReplicas, _ = countPods(pods, newRC)
+		status.Revision = revisionOf(newRC)
+	}
+	if !api.Semantic.DeepEqual(status, deployment.Status) {
+		deployment.Status = status
+		if _, err := dm.kubeClient.Deployments(deployment.Namespace).UpdateStatus(&deployment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rollout makes one step of the rollout of the current template of the
+// deployment, creating its replication controller if needed, and returns
+// that replication controller.
+func (dm *DeploymentManager) rollout(deployment *api.Deployment, hash string, newRC *api.ReplicationController, oldRCs []*api.ReplicationController, pods []api.Pod) (*api.ReplicationController, error) {
+	maxOldRevision := int64(0)
+	oldReplicas := 0
+	for _, rc := range oldRCs {
+		if revision := revisionOf(rc); revision > maxOldRevision {
+			maxOldRevision = revision
+		}
+		oldReplicas += rc.Spec.Replicas
+	}
+	oldPods, _ := countPods(pods, oldRCs...)
+	newReplicas := 0
+	if newRC != nil {
+		newReplicas = newRC.Spec.Replicas
+	}
+
+	var err error
+	desiredNewReplicas := newReplicas
+	switch deployment.Spec.Strategy.Type {
+	case api.RecreateDeploymentStrategyType:
+		// The new pods are only created once all the old pods are gone.
+		if oldReplicas == 0 && oldPods == 0 {
+			desiredNewReplicas = deployment.Spec.Replicas
+		}
+	case api.RollingUpdateDeploymentStrategyType:
+		desiredNewReplicas, err = newReplicasForRollingUpdate(deployment, newReplicas, oldReplicas+newReplicas)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("deployment %s/%s has an unsupported strategy %q", deployment.Namespace, deployment.Name, deployment.Spec.Strategy.Type)
+	}
+
+	if newRC == nil {
+		rc, err := newReplicationController(deployment, hash, maxOldRevision+1, desiredNewReplicas)
+		if err != nil {
+			return nil, err
+		}
+		glog.V(2).Infof("Creating %s for revision %d of deployment %q with %d replicas", rc.Name, maxOldRevision+1, deployment.Name, desiredNewReplicas)
+		if _, err := dm.kubeClient.ReplicationControllers(deployment.Namespace).Create(rc); err != nil {
+			return nil, fmt.Errorf("unable to create replication controller for deployment %s: %v", deployment.Name, err)
+		}
+		newRC = rc
+	} else if desiredNewReplicas != newReplicas || revisionOf(newRC) <= maxOldRevision {
+		// A rollback turns the template of an older replication controller
+		// into the newest revision.
+		if revisionOf(newRC) <= maxOldRevision {
+			if newRC.Annotations == nil {
+				newRC.Annotations = map[string]string{}
+			}
+			newRC.Annotations[RevisionAnnotation] = strconv.FormatInt(maxOldRevision+1, 10)
+		}
+		if err := dm.scale(newRC, desiredNewReplicas); err != nil {
+			return nil, err
+		}
+	}
+
+	// Scale down the old replication controllers, starting with the oldest.
+	scaleDown := oldReplicas
+	if deployment.Spec.Strategy.Type == api.RollingUpdateDeploymentStrategyType {
+		_, maxUnavailable, err := rollingUpdateLimits(deployment)
+		if err != nil {
+			return nil, err
+		}
+		_, newAvailable := countPods(pods, newRC)
+		newUnavailable := desiredNewReplicas - newAvailable
+		if newUnavailable < 0 {
+			newUnavailable = 0
+		}
+		minAvailable := deployment.Spec.Replicas - maxUnavailable
+		scaleDown = oldReplicas + desiredNewReplicas - minAvailable - newUnavailable
+	}
+	for _, rc := range oldRCs {
+		if scaleDown <= 0 {
+			break
+		}
+		if rc.Spec.Replicas == 0 {
+			continue
+		}
+		replicas := rc.Spec.Replicas - scaleDown
+		if replicas < 0 {
+			replicas = 0
+		}
+		scaleDown -= rc.Spec.Replicas - replicas
+		if err := dm.scale(rc, replicas); err != nil {
+			return nil, err
+		}
+	}
+	return newRC, nil
+}
+
+// newReplicasForRollingUpdate returns the number of replicas of the
+// replication controller of the current template, given its current number
+// of replicas and the total number of replicas of the deployment.
+func newReplicasForRollingUpdate(deployment *api.Deployment, newReplicas, allReplicas int) (int, error) {
+	if newReplicas >= deployment.Spec.Replicas {
+		return deployment.Spec.Replicas, nil
+	}
+	maxSurge, _, err := rollingUpdateLimits(deployment)
+	if err != nil {
+		return 0, err
+	}
+	maxTotal := deployment.Spec.Replicas + maxSurge
+	if allReplicas >= maxTotal {
+		return newReplicas, nil
+	}
+	newReplicas += maxTotal - allReplicas
+	if newReplicas > deployment.Spec.Replicas {
+		newReplicas = deployment.Spec.Replicas
+	}
+	return newReplicas, nil
+}
+
+// scale updates the replication controller with the given number of replicas.
+func (dm *DeploymentManager) scale(rc *api.ReplicationController, replicas int) error {
+	glog.V(2).Infof("Scaling %s/%s from %d to %d replicas", rc.Namespace, rc.Name, rc.Spec.Replicas, replicas)
+	rc.Spec.Replicas = replicas
+	if _, err := dm.kubeClient.ReplicationControllers(rc.Namespace).Update(rc); err != nil {
+		return fmt.Errorf("unable to scale %s/%s: %v", rc.Namespace, rc.Name, err)
+	}
+	return nil
+}
+
+// rollback resets the template of the deployment to the one of the
+// requested revision and clears the request. The rollout of the restored
+// template happens on the next sync.
+func (dm *DeploymentManager) rollback(deployment api.Deployment, rcs []*api.ReplicationController) error {
+	sorted := make([]*api.ReplicationController, len(rcs))
+	copy(sorted, rcs)
+	sort.Sort(byRevision(sorted))
+
+	revision := deployment.Spec.RollbackTo.Revision
+	if revision == 0 && len(sorted) > 1 {
+		// The revision before the current one.
+		revision = revisionOf(sorted[len(sorted)-2])
+	}
+	var target *api.ReplicationController
+	for _, rc := range sorted {
+		if revision > 0 && revisionOf(rc) == revision {
+			target = rc
+		}
+	}
+	if target == nil {
+		util.HandleError(fmt.Errorf("unable to roll back deployment %s/%s: revision %d not found", deployment.Namespace, deployment.Name, deployment.Spec.RollbackTo.Revision))
+	} else {
+		template, err := copyTemplate(target.Spec.Template, "")
+		if err != nil {
+			return err
+		}
+		glog.V(2).Infof("Rolling back deployment %s/%s to revision %d", deployment.Namespace, deployment.Name, revision)
+		deployment.Spec.Template = template
+	}
+	deployment.Spec.RollbackTo = nil
+	_, err := dm.kubeClient.Deployments(deployment.Namespace).Update(&deployment)
+	return err
+}
+
+func (dm *DeploymentManager) synchronize() {
+	// TODO: remove this method completely and rely on the watch.
+	// Add resource version tracking to watch to make this work.
+	list, err := dm.kubeClient.Deployments(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("synchronization error: %v", err))
+		return
+	}
+	deployments := list.Items
+	wg := sync.WaitGroup{}
+	wg.Add(len(deployments))
+	for ix := range deployments {
+		go func(ix int) {
+			defer wg.Done()
+			glog.V(4).Infof("periodic sync of %v/%v", deployments[ix].Namespace, deployments[ix].Name)
+			if err := dm.syncHandler(deployments[ix]); err != nil {
+				util.HandleError(fmt.Errorf("error synchronizing: %v", err))
+			}
+		}(ix)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,400 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func newDeployment(replicas int, image string) api.Deployment {
+	return api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "foobar", Namespace: api.NamespaceDefault, ResourceVersion: "18"},
+		Spec: api.DeploymentSpec{
+			Replicas: replicas,
+			Selector: map[string]string{"foo": "bar"},
+			Template: &api.PodTemplateSpec{
+				ObjectMeta: api.ObjectMeta{
+					Labels: map[string]string{
+						"foo": "bar",
+					},
+				},
+				Spec: api.PodSpec{
+					RestartPolicy: api.RestartPolicyAlways,
+					Containers: []api.Container{
+						{Image: image},
+					},
+				},
+			},
+			Strategy: api.DeploymentStrategy{
+				Type: api.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &api.RollingUpdateDeployment{
+					MaxUnavailable: util.NewIntOrStringFromInt(1),
+					MaxSurge:       util.NewIntOrStringFromInt(1),
+				},
+			},
+		},
+	}
+}
+
+// newRC returns the replication controller which runs the template of a
+// deployment using the given image as the given revision.
+func newRC(t *testing.T, image string, revision int64, replicas int) api.ReplicationController {
+	d := newDeployment(replicas, image)
+	rc, err := newReplicationController(&d, podTemplateHash(d.Spec.Template), revision, replicas)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return *rc
+}
+
+// newPods returns count running pods of the given replication controller,
+// of which the first available ones are ready.
+func newPods(rc api.ReplicationController, count, available int) []api.Pod {
+	pods := []api.Pod{}
+	for i := 0; i < count; i++ {
+		pod := api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:   fmt.Sprintf("%s-pod%d", rc.Name, i),
+				Labels: rc.Spec.Template.Labels,
+			},
+			Status: api.PodStatus{Phase: api.PodRunning},
+		}
+		if i < available {
+			pod.Status.Conditions = []api.PodCondition{{Type: api.PodReady, Status: api.ConditionTrue}}
+		}
+		pods = append(pods, pod)
+	}
+	return pods
+}
+
+// controllerActions returns the replication controllers created and updated
+// through the fake client.
+func controllerActions(fakeClient *client.Fake) (created, updated []*api.ReplicationController) {
+	for _, action := range fakeClient.Actions {
+		switch action.Action {
+		case "create-controller":
+			created = append(created, action.Value.(*api.ReplicationController))
+		case "update-controller":
+			updated = append(updated, action.Value.(*api.ReplicationController))
+		}
+	}
+	return
+}
+
+func TestSyncDeploymentCreatesFirstRevision(t *testing.T) {
+	fakeClient := client.Fake{}
+	manager := NewDeploymentManager(&fakeClient)
+
+	d := newDeployment(3, "foo/bar:v1")
+	if err := manager.syncDeployment(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, updated := controllerActions(&fakeClient)
+	if len(created) != 1 || len(updated) != 0 {
+		t.Fatalf("expected a single create, got %d creates and %d updates", len(created), len(updated))
+	}
+	rc := created[0]
+	if rc.Spec.Replicas != 3 {
+		t.Errorf("expected 3 replicas, got %d", rc.Spec.Replicas)
+	}
+	if e, a := "1", rc.Annotations[RevisionAnnotation]; e != a {
+		t.Errorf("expected revision %s, got %s", e, a)
+	}
+	hash := podTemplateHash(d.Spec.Template)
+	if rc.Spec.Selector[PodTemplateHashLabel] != hash || rc.Spec.Template.Labels[PodTemplateHashLabel] != hash {
+		t.Errorf("expected selector and template to carry the template hash %s: %#v", hash, rc.Spec)
+	}
+	if rc.Spec.Selector["foo"] != "bar" {
+		t.Errorf("expected selector to include the deployment selector: %v", rc.Spec.Selector)
+	}
+	if _, ok := d.Spec.Template.Labels[PodTemplateHashLabel]; ok {
+		t.Errorf("the template of the deployment must not be modified")
+	}
+	if e, a := int64(1), fakeClient.DeploymentStatus.Status.Revision; e != a {
+		t.Errorf("expected status revision %d, got %d", e, a)
+	}
+}
+
+func TestSyncDeploymentRollingUpdate(t *testing.T) {
+	testCases := map[string]struct {
+		oldReplicas  int
+		oldAvailable int
+		newReplicas  int
+		newAvailable int
+
+		expectedNewReplicas int
+		expectedOldReplicas int
+	}{
+		"rollout starts": {
+			oldReplicas:         3,
+			oldAvailable:        3,
+			newReplicas:         -1,
+			expectedNewReplicas: 1,
+			expectedOldReplicas: 2,
+		},
+		"waits for new pods to become available": {
+			oldReplicas:         2,
+			oldAvailable:        2,
+			newReplicas:         2,
+			newAvailable:        0,
+			expectedNewReplicas: 2,
+			expectedOldReplicas: 2,
+		},
+		"scales down old pods once new pods are available": {
+			oldReplicas:         2,
+			oldAvailable:        2,
+			newReplicas:         2,
+			newAvailable:        2,
+			expectedNewReplicas: 2,
+			expectedOldReplicas: 0,
+		},
+		"finishes rollout": {
+			oldReplicas:         0,
+			newReplicas:         2,
+			newAvailable:        2,
+			expectedNewReplicas: 3,
+			expectedOldReplicas: 0,
+		},
+	}
+
+	for name, tc := range testCases {
+		oldRC := newRC(t, "foo/bar:v1", 1, tc.oldReplicas)
+		rcs := []api.ReplicationController{oldRC}
+		pods := newPods(oldRC, tc.oldReplicas, tc.oldAvailable)
+		if tc.newReplicas >= 0 {
+			newRC := newRC(t, "foo/bar:v2", 2, tc.newReplicas)
+			rcs = append(rcs, newRC)
+			pods = append(pods, newPods(newRC, tc.newReplicas, tc.newAvailable)...)
+		}
+		fakeClient := client.Fake{
+			CtrlList: api.ReplicationControllerList{Items: rcs},
+			PodsList: api.PodList{Items: pods},
+		}
+		manager := NewDeploymentManager(&fakeClient)
+
+		if err := manager.syncDeployment(newDeployment(3, "foo/bar:v2")); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		replicas := map[string]int{}
+		for _, rc := range rcs {
+			replicas[rc.Name] = rc.Spec.Replicas
+		}
+		created, updated := controllerActions(&fakeClient)
+		for _, rc := range append(created, updated...) {
+			replicas[rc.Name] = rc.Spec.Replicas
+		}
+		if len(created) > 0 && len(rcs) > 1 {
+			t.Errorf("%s: unexpected create of %s", name, created[0].Name)
+		}
+		if e, a := tc.expectedOldReplicas, replicas[oldRC.Name]; e != a {
+			t.Errorf("%s: expected %d old replicas, got %d", name, e, a)
+		}
+		d := newDeployment(3, "foo/bar:v2")
+		newName := fmt.Sprintf("%s-%s", d.Name, podTemplateHash(d.Spec.Template))
+		if e, a := tc.expectedNewReplicas, replicas[newName]; e != a {
+			t.Errorf("%s: expected %d new replicas, got %d", name, e, a)
+		}
+	}
+}
+
+func TestSyncDeploymentRecreate(t *testing.T) {
+	d := newDeployment(3, "foo/bar:v2")
+	d.Spec.Strategy = api.DeploymentStrategy{Type: api.RecreateDeploymentStrategyType}
+
+	// The old pods are killed first.
+	oldRC := newRC(t, "foo/bar:v1", 1, 3)
+	fakeClient := client.Fake{
+		CtrlList: api.ReplicationControllerList{Items: []api.ReplicationController{oldRC}},
+		PodsList: api.PodList{Items: newPods(oldRC, 3, 3)},
+	}
+	manager := NewDeploymentManager(&fakeClient)
+	if err := manager.syncDeployment(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, updated := controllerActions(&fakeClient)
+	if len(created) != 1 || created[0].Spec.Replicas != 0 {
+		t.Errorf("expected the new revision to be created without replicas: %#v", created)
+	}
+	if len(updated) != 1 || updated[0].Name != oldRC.Name || updated[0].Spec.Replicas != 0 {
+		t.Errorf("expected the old revision to be scaled down: %#v", updated)
+	}
+
+	// The new pods are created once the old ones are gone.
+	oldRC.Spec.Replicas = 0
+	newRC := newRC(t, "foo/bar:v2", 2, 0)
+	fakeClient = client.Fake{
+		CtrlList: api.ReplicationControllerList{Items: []api.ReplicationController{oldRC, newRC}},
+	}
+	manager = NewDeploymentManager(&fakeClient)
+	if err := manager.syncDeployment(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, updated = controllerActions(&fakeClient)
+	if len(updated) != 1 || updated[0].Name != newRC.Name || updated[0].Spec.Replicas != 3 {
+		t.Errorf("expected the new revision to be scaled up: %#v", updated)
+	}
+}
+
+func TestSyncDeploymentPaused(t *testing.T) {
+	oldRC := newRC(t, "foo/bar:v1", 1, 3)
+	fakeClient := client.Fake{
+		CtrlList: api.ReplicationControllerList{Items: []api.ReplicationController{oldRC}},
+		PodsList: api.PodList{Items: newPods(oldRC, 3, 2)},
+	}
+	manager := NewDeploymentManager(&fakeClient)
+
+	d := newDeployment(3, "foo/bar:v2")
+	d.Spec.Paused = true
+	if err := manager.syncDeployment(d); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created, updated := controllerActions(&fakeClient); len(created) != 0 || len(updated) != 0 {
+		t.Errorf("expected a paused deployment not to change controllers: %#v %#v", created, updated)
+	}
+	status := fakeClient.DeploymentStatus.Status
+	if status.Replicas != 3 || status.AvailableReplicas != 2 || status.UpdatedReplicas != 0 {
+		t.Errorf("unexpected status: %#v", status)
+	}
+}
+
+func TestSyncDeploymentRollback(t *testing.T) {
+	rcs := []api.ReplicationController{
+		newRC(t, "foo/bar:v1", 1, 0),
+		newRC(t, "foo/bar:v2", 2, 0),
+		newRC(t, "foo/bar:v3", 3, 3),
+	}
+	testCases := map[string]struct {
+		revision      int64
+		expectedImage string
+	}{
+		"previous revision": {0, "foo/bar:v2"},
+		"given revision":    {1, "foo/bar:v1"},
+		"unknown revision":  {7, "foo/bar:v3"},
+	}
+	for name, tc := range testCases {
+		fakeClient := client.Fake{CtrlList: api.ReplicationControllerList{Items: rcs}}
+		manager := NewDeploymentManager(&fakeClient)
+
+		d := newDeployment(3, "foo/bar:v3")
+		d.Spec.RollbackTo = &api.RollbackConfig{Revision: tc.revision}
+		if err := manager.syncDeployment(d); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+		var updated *api.Deployment
+		for _, action := range fakeClient.Actions {
+			if action.Action == "update-deployment" {
+				updated = action.Value.(*api.Deployment)
+			}
+		}
+		if updated == nil {
+			t.Errorf("%s: expected the deployment to be updated", name)
+			continue
+		}
+		if updated.Spec.RollbackTo != nil {
+			t.Errorf("%s: expected the rollback request to be cleared", name)
+		}
+		if e, a := tc.expectedImage, updated.Spec.Template.Spec.Containers[0].Image; e != a {
+			t.Errorf("%s: expected image %s, got %s", name, e, a)
+		}
+		if _, ok := updated.Spec.Template.Labels[PodTemplateHashLabel]; ok {
+			t.Errorf("%s: expected the template hash label to be removed", name)
+		}
+		if created, updatedRCs := controllerActions(&fakeClient); len(created) != 0 || len(updatedRCs) != 0 {
+			t.Errorf("%s: expected no change to controllers before the next sync", name)
+		}
+	}
+}
+
+func TestSyncDeploymentReusesRolledBackRevision(t *testing.T) {
+	v1 := newRC(t, "foo/bar:v1", 1, 0)
+	v2 := newRC(t, "foo/bar:v2", 2, 3)
+	fakeClient := client.Fake{
+		CtrlList: api.ReplicationControllerList{Items: []api.ReplicationController{v1, v2}},
+		PodsList: api.PodList{Items: newPods(v2, 3, 3)},
+	}
+	manager := NewDeploymentManager(&fakeClient)
+
+	if err := manager.syncDeployment(newDeployment(3, "foo/bar:v1")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	created, updated := controllerActions(&fakeClient)
+	if len(created) != 0 {
+		t.Errorf("expected the controller of the earlier revision to be reused: %#v", created)
+	}
+	if len(updated) == 0 || updated[0].Name != v1.Name {
+		t.Fatalf("expected the controller of the earlier revision to be updated: %#v", updated)
+	}
+	if e, a := "3", updated[0].Annotations[RevisionAnnotation]; e != a {
+		t.Errorf("expected revision %s, got %s", e, a)
+	}
+	if e, a := 1, updated[0].Spec.Replicas; e != a {
+		t.Errorf("expected %d replicas, got %d", e, a)
+	}
+}
+
+func TestRollingUpdateLimits(t *testing.T) {
+	testCases := []struct {
+		replicas       int
+		maxSurge       util.IntOrString
+		maxUnavailable util.IntOrString
+
+		expectedSurge       int
+		expectedUnavailable int
+	}{
+		{10, util.NewIntOrStringFromInt(2), util.NewIntOrStringFromInt(3), 2, 3},
+		{10, util.NewIntOrStringFromString("25%"), util.NewIntOrStringFromString("25%"), 3, 2},
+		{3, util.NewIntOrStringFromString("0%"), util.NewIntOrStringFromString("10%"), 0, 1},
+	}
+	for i, tc := range testCases {
+		d := newDeployment(tc.replicas, "foo/bar")
+		d.Spec.Strategy.RollingUpdate.MaxSurge = tc.maxSurge
+		d.Spec.Strategy.RollingUpdate.MaxUnavailable = tc.maxUnavailable
+		surge, unavailable, err := rollingUpdateLimits(&d)
+		if err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+			continue
+		}
+		if surge != tc.expectedSurge || unavailable != tc.expectedUnavailable {
+			t.Errorf("%d: expected surge %d and unavailable %d, got %d and %d", i, tc.expectedSurge, tc.expectedUnavailable, surge, unavailable)
+		}
+	}
+}
+
+func TestSynchronize(t *testing.T) {
+	fakeClient := client.Fake{DeploymentsList: api.DeploymentList{Items: []api.Deployment{newDeployment(1, "a"), newDeployment(2, "b")}}}
+	manager := NewDeploymentManager(&fakeClient)
+	var lock sync.Mutex
+	synced := 0
+	manager.syncHandler = func(deployment api.Deployment) error {
+		lock.Lock()
+		defer lock.Unlock()
+		synced++
+		return nil
+	}
+	manager.synchronize()
+	if synced != 2 {
+		t.Errorf("expected 2 deployments to be synced, got %d", synced)
+	}
+}
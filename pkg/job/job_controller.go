@@ -0,0 +1,399 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/controller"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/validation"
+	"github.com/cnaize/kubernetes/pkg/types"
+	"github.com/golang/glog"
+)
+
+// JobManager is responsible for synchronizing Job objects stored in the
+// system with the pods that execute them.
+type JobManager struct {
+	kubeClient client.Interface
+	podControl PodControlInterface
+	syncTime   <-chan time.Time
+
+	// To allow injection of syncJob for testing.
+	syncHandler func(job api.Job) error
+
+	// lock guards counted and syncLocks.
+	lock sync.Mutex
+	// counted holds, by job UID, the UIDs of the finished pods which the counts
+	// in the status of the job already include.
+	counted map[types.UID]util.StringSet
+	// syncLocks serialize the syncs of each job, by namespace/name.
+	syncLocks map[string]*sync.Mutex
+}
+
+// PodControlInterface is an interface that knows how to add or delete pods
+// created as an interface to allow testing.
+type PodControlInterface interface {
+	// createPod creates a new pod from the template of the given job.
+	createPod(namespace string, job api.Job) error
+	// deletePod deletes the pod identified by podID.
+	deletePod(namespace string, podID string) error
+}
+
+// RealPodControl is the default implementation of PodControlInterface.
+type RealPodControl struct {
+	kubeClient client.Interface
+}
+
+// Time period of main job controller sync loop
+const DefaultSyncPeriod = 10 * time.Second
+
+func (r RealPodControl) createPod(namespace string, job api.Job) error {
+	desiredLabels := make(labels.Set)
+	for k, v := range job.Spec.Template.Labels {
+		desiredLabels[k] = v
+	}
+	desiredAnnotations := make(labels.Set)
+	for k, v := range job.Spec.Template.Annotations {
+		desiredAnnotations[k] = v
+	}
+
+	// use the dash (if the name isn't too long) to make the pod name a bit prettier
+	prefix := fmt.Sprintf("%s-", job.Name)
+	if ok, _ := validation.ValidatePodName(prefix, true); !ok {
+		prefix = job.Name
+	}
+
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Labels:       desiredLabels,
+			Annotations:  desiredAnnotations,
+			GenerateName: prefix,
+		},
+	}
+	if err := api.Scheme.Convert(&job.Spec.Template.Spec, &pod.Spec); err != nil {
+		return fmt.Errorf("unable to convert pod template: %v", err)
+	}
+	if labels.Set(pod.Labels).AsSelector().Empty() {
+		return fmt.Errorf("unable to create pod for job %s, no labels", job.Name)
+	}
+	if _, err := r.kubeClient.Pods(namespace).Create(pod); err != nil {
+		return fmt.Errorf("unable to create pod for job %s: %v", job.Name, err)
+	}
+	return nil
+}
+
+func (r RealPodControl) deletePod(namespace, podID string) error {
+	return r.kubeClient.Pods(namespace).Delete(podID, nil)
+}
+
+// NewJobManager creates a new JobManager.
+func NewJobManager(kubeClient client.Interface) *JobManager {
+	jm := &JobManager{
+		kubeClient: kubeClient,
+		podControl: RealPodControl{
+			kubeClient: kubeClient,
+		},
+		counted:   map[types.UID]util.StringSet{},
+		syncLocks: map[string]*sync.Mutex{},
+	}
+	jm.syncHandler = jm.syncJob
+	return jm
+}
+
+// Run begins watching and syncing.
+func (jm *JobManager) Run(period time.Duration) {
+	jm.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { jm.watchJobs(&resourceVersion) }, period)
+}
+
+// resourceVersion is a pointer to the resource version to use/update.
+func (jm *JobManager) watchJobs(resourceVersion *string) {
+	watching, err := jm.kubeClient.Jobs(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-jm.syncTime:
+			jm.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from watch during sync: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			glog.V(4).Infof("Got watch: %#v", event)
+			job, ok := event.Object.(*api.Job)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = job.ResourceVersion
+			// A deleted job leaves its pods behind, there is nothing left to sync.
+			if event.Type == watch.Deleted {
+				jm.forget(job)
+				continue
+			}
+			glog.V(4).Infof("About to sync from watch: %v", job.Name)
+			if err := jm.syncHandler(*job); err != nil {
+				util.HandleError(fmt.Errorf("unexpected sync error: %v", err))
+			}
+		}
+	}
+}
+
+// countPodsByPhase returns the number of pods which reached phase Succeeded
+// and the number of pods which reached phase Failed.
+func countPodsByPhase(pods []api.Pod) (succeeded, failed int) {
+	for i := range pods {
+		switch pods[i].Status.Phase {
+		case api.PodSucceeded:
+			succeeded++
+		case api.PodFailed:
+			failed++
+		}
+	}
+	return
+}
+
+// countFinishedPods returns the number of succeeded and failed pods of the job,
+// and the UIDs of the finished pods those numbers include. Finished pods may be
+// removed at any time, so the pods counted in a previous sync are not counted
+// again, and the ones which were removed stay counted in the status of the job.
+//
+// Whatever the status and the pods counted before, the counts are never below
+// the number of finished pods listed now.
+func (jm *JobManager) countFinishedPods(job *api.Job, pods []api.Pod) (succeeded, failed int, counted util.StringSet) {
+	jm.lock.Lock()
+	previous, found := jm.counted[job.UID]
+	jm.lock.Unlock()
+
+	// When the job was synced before this controller started, which of the
+	// finished pods the status includes is unknown. The counts recorded in the
+	// status never go down, and the finished pods are counted from now on.
+	restarted := !found && job.Status.StartTime != nil
+	counted = util.NewStringSet()
+	succeeded, failed = job.Status.Succeeded, job.Status.Failed
+	for i := range pods {
+		uid := string(pods[i].UID)
+		switch pods[i].Status.Phase {
+		case api.PodSucceeded:
+			if !restarted && !previous.Has(uid) {
+				succeeded++
+			}
+		case api.PodFailed:
+			if !restarted && !previous.Has(uid) {
+				failed++
+			}
+		default:
+			continue
+		}
+		counted.Insert(uid)
+	}
+
+	listedSucceeded, listedFailed := countPodsByPhase(pods)
+	if succeeded < listedSucceeded {
+		succeeded = listedSucceeded
+	}
+	if failed < listedFailed {
+		failed = listedFailed
+	}
+	return succeeded, failed, counted
+}
+
+// setCounted records the finished pods which the counts in the status of the job include.
+func (jm *JobManager) setCounted(jobUID types.UID, counted util.StringSet) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	jm.counted[jobUID] = counted
+}
+
+// forget drops the finished pods counted and the sync lock of a deleted job.
+func (jm *JobManager) forget(job *api.Job) {
+	jm.lock.Lock()
+	defer jm.lock.Unlock()
+	delete(jm.counted, job.UID)
+	delete(jm.syncLocks, job.Namespace+"/"+job.Name)
+}
+
+// lockSync waits for the syncs of the job in progress, and returns the
+// function which lets the next one start.
+func (jm *JobManager) lockSync(job *api.Job) func() {
+	key := job.Namespace + "/" + job.Name
+	jm.lock.Lock()
+	syncLock, found := jm.syncLocks[key]
+	if !found {
+		syncLock = &sync.Mutex{}
+		jm.syncLocks[key] = syncLock
+	}
+	jm.lock.Unlock()
+	syncLock.Lock()
+	return syncLock.Unlock
+}
+
+// syncJob creates or deletes pods of the given job so that no more than
+// Parallelism pods are active and no more than Completions pods are run to
+// success, and records what it observed in the status of the job.
+func (jm *JobManager) syncJob(job api.Job) error {
+	// The job from a watch event or a list may be older than the status an
+	// earlier sync recorded, so the syncs of a job are serialized and start
+	// from its latest version.
+	unlock := jm.lockSync(&job)
+	defer unlock()
+	latest, err := jm.kubeClient.Jobs(job.Namespace).Get(job.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			jm.forget(&job)
+			return nil
+		}
+		return err
+	}
+	job = *latest
+
+	if job.Spec.Template == nil {
+		return fmt.Errorf("job %s/%s has no pod template", job.Namespace, job.Name)
+	}
+	s := labels.Set(job.Spec.Selector).AsSelector()
+	podList, err := jm.kubeClient.Pods(job.Namespace).List(s)
+	if err != nil {
+		return err
+	}
+	activePods := controller.FilterActivePods(podList.Items)
+	succeeded, failed, counted := jm.countFinishedPods(&job, podList.Items)
+
+	desired := 0
+	if succeeded < job.Spec.Completions {
+		desired = job.Spec.Completions - succeeded
+		if desired > job.Spec.Parallelism {
+			desired = job.Spec.Parallelism
+		}
+	}
+
+	active := len(activePods)
+	diff := active - desired
+	if diff < 0 {
+		diff *= -1
+		glog.V(2).Infof("Too few pods running job %q, creating %d", job.Name, diff)
+		var lock sync.Mutex
+		wait := sync.WaitGroup{}
+		wait.Add(diff)
+		for i := 0; i < diff; i++ {
+			go func() {
+				defer wait.Done()
+				if err := jm.podControl.createPod(job.Namespace, job); err != nil {
+					util.HandleError(err)
+					return
+				}
+				lock.Lock()
+				defer lock.Unlock()
+				active++
+			}()
+		}
+		wait.Wait()
+	} else if diff > 0 {
+		glog.V(2).Infof("Too many pods running job %q, deleting %d", job.Name, diff)
+		var lock sync.Mutex
+		wait := sync.WaitGroup{}
+		wait.Add(diff)
+		for i := 0; i < diff; i++ {
+			go func(ix int) {
+				defer wait.Done()
+				if err := jm.podControl.deletePod(job.Namespace, activePods[ix].Name); err != nil {
+					util.HandleError(fmt.Errorf("unable to delete pod %s of job %s: %v", activePods[ix].Name, job.Name, err))
+					return
+				}
+				lock.Lock()
+				defer lock.Unlock()
+				active--
+			}(i)
+		}
+		wait.Wait()
+	}
+
+	status := job.Status
+	if status.StartTime == nil {
+		now := util.Now()
+		status.StartTime = &now
+	}
+	if succeeded >= job.Spec.Completions && status.CompletionTime == nil {
+		now := util.Now()
+		status.CompletionTime = &now
+	}
+	status.Active = active
+	status.Succeeded = succeeded
+	status.Failed = failed
+
+	if !api.Semantic.DeepEqual(status, job.Status) {
+		job.Status = status
+		if _, err := jm.kubeClient.Jobs(job.Namespace).UpdateStatus(&job); err != nil {
+			return err
+		}
+	}
+	// Only the pods included in the recorded status count as counted, so that
+	// a failed update counts them again in the next sync.
+	jm.setCounted(job.UID, counted)
+	return nil
+}
+
+func (jm *JobManager) synchronize() {
+	// TODO: remove this method completely and rely on the watch.
+	// Add resource version tracking to watch to make this work.
+	list, err := jm.kubeClient.Jobs(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("synchronization error: %v", err))
+		return
+	}
+	jobs := list.Items
+	wg := sync.WaitGroup{}
+	wg.Add(len(jobs))
+	for ix := range jobs {
+		go func(ix int) {
+			defer wg.Done()
+			glog.V(4).Infof("periodic sync of %v/%v", jobs[ix].Namespace, jobs[ix].Name)
+			if err := jm.syncHandler(jobs[ix]); err != nil {
+				util.HandleError(fmt.Errorf("error synchronizing: %v", err))
+			}
+		}(ix)
+	}
+	wg.Wait()
+}
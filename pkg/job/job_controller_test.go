@@ -0,0 +1,361 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/types"
+)
+
+type FakePodControl struct {
+	createdJobs   []api.Job
+	deletePodName []string
+	err           error
+	lock          sync.Mutex
+}
+
+func (f *FakePodControl) createPod(namespace string, job api.Job) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.createdJobs = append(f.createdJobs, job)
+	return nil
+}
+
+func (f *FakePodControl) deletePod(namespace string, podName string) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.err != nil {
+		return f.err
+	}
+	f.deletePodName = append(f.deletePodName, podName)
+	return nil
+}
+
+func newJob(completions, parallelism int) api.Job {
+	return api.Job{
+		ObjectMeta: api.ObjectMeta{Name: "foobar", Namespace: api.NamespaceDefault, ResourceVersion: "18"},
+		Spec: api.JobSpec{
+			Completions: completions,
+			Parallelism: parallelism,
+			Selector:    map[string]string{"foo": "bar"},
+			Template: &api.PodTemplateSpec{
+				ObjectMeta: api.ObjectMeta{
+					Labels: map[string]string{
+						"foo": "bar",
+					},
+				},
+				Spec: api.PodSpec{
+					RestartPolicy: api.RestartPolicyNever,
+					Containers: []api.Container{
+						{Image: "foo/bar"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newPodList(phases ...api.PodPhase) api.PodList {
+	pods := []api.Pod{}
+	for i, phase := range phases {
+		pods = append(pods, api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:   fmt.Sprintf("pod%d", i),
+				UID:    types.UID(fmt.Sprintf("pod%d-uid", i)),
+				Labels: map[string]string{"foo": "bar"},
+			},
+			Status: api.PodStatus{Phase: phase},
+		})
+	}
+	return api.PodList{Items: pods}
+}
+
+func TestSyncJob(t *testing.T) {
+	testCases := map[string]struct {
+		completions int
+		parallelism int
+		status      api.JobStatus
+		pods        []api.PodPhase
+		podErr      error
+
+		expectedCreates   int
+		expectedDeletes   int
+		expectedActive    int
+		expectedSucceeded int
+		expectedFailed    int
+		expectedComplete  bool
+	}{
+		"job start": {
+			completions:     5,
+			parallelism:     2,
+			expectedCreates: 2,
+			expectedActive:  2,
+		},
+		"correct number of pods": {
+			completions:    5,
+			parallelism:    2,
+			pods:           []api.PodPhase{api.PodRunning, api.PodPending},
+			expectedActive: 2,
+		},
+		"too many pods": {
+			completions:     5,
+			parallelism:     2,
+			pods:            []api.PodPhase{api.PodRunning, api.PodRunning, api.PodPending},
+			expectedDeletes: 1,
+			expectedActive:  2,
+		},
+		"failed pods are replaced": {
+			completions:     5,
+			parallelism:     2,
+			pods:            []api.PodPhase{api.PodRunning, api.PodFailed},
+			expectedCreates: 1,
+			expectedActive:  2,
+			expectedFailed:  1,
+		},
+		"no more pods than remaining completions": {
+			completions:       3,
+			parallelism:       3,
+			pods:              []api.PodPhase{api.PodSucceeded, api.PodSucceeded},
+			expectedCreates:   1,
+			expectedActive:    1,
+			expectedSucceeded: 2,
+		},
+		"succeeded pods removed since last sync are not recreated": {
+			completions:       3,
+			parallelism:       3,
+			status:            api.JobStatus{Succeeded: 3},
+			expectedSucceeded: 3,
+			expectedComplete:  true,
+		},
+		"remaining pods are deleted once complete": {
+			completions:       2,
+			parallelism:       2,
+			pods:              []api.PodPhase{api.PodSucceeded, api.PodSucceeded, api.PodRunning},
+			expectedDeletes:   1,
+			expectedSucceeded: 2,
+			expectedComplete:  true,
+		},
+		"failed pod creation is not counted": {
+			completions:     5,
+			parallelism:     2,
+			podErr:          fmt.Errorf("fake error"),
+			expectedCreates: 0,
+			expectedActive:  0,
+		},
+	}
+
+	for name, tc := range testCases {
+		fakeClient := client.Fake{PodsList: newPodList(tc.pods...)}
+		fakePodControl := FakePodControl{err: tc.podErr}
+		manager := NewJobManager(&fakeClient)
+		manager.podControl = &fakePodControl
+
+		job := newJob(tc.completions, tc.parallelism)
+		job.Status = tc.status
+		fakeClient.JobsList = api.JobList{Items: []api.Job{job}}
+		if err := manager.syncJob(job); err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+			continue
+		}
+
+		if e, a := tc.expectedCreates, len(fakePodControl.createdJobs); e != a {
+			t.Errorf("%s: expected %d creates, got %d", name, e, a)
+		}
+		if e, a := tc.expectedDeletes, len(fakePodControl.deletePodName); e != a {
+			t.Errorf("%s: expected %d deletes, got %d", name, e, a)
+		}
+		status := fakeClient.JobStatus.Status
+		if e, a := tc.expectedActive, status.Active; e != a {
+			t.Errorf("%s: expected %d active, got %d", name, e, a)
+		}
+		if e, a := tc.expectedSucceeded, status.Succeeded; e != a {
+			t.Errorf("%s: expected %d succeeded, got %d", name, e, a)
+		}
+		if e, a := tc.expectedFailed, status.Failed; e != a {
+			t.Errorf("%s: expected %d failed, got %d", name, e, a)
+		}
+		if status.StartTime == nil {
+			t.Errorf("%s: expected start time to be set", name)
+		}
+		if e, a := tc.expectedComplete, status.CompletionTime != nil; e != a {
+			t.Errorf("%s: expected complete %v, got %v", name, e, a)
+		}
+	}
+}
+
+func TestSyncJobCountsRemovedSucceededPods(t *testing.T) {
+	fakeClient := client.Fake{PodsList: newPodList(api.PodSucceeded, api.PodSucceeded, api.PodRunning)}
+	fakePodControl := FakePodControl{}
+	manager := NewJobManager(&fakeClient)
+	manager.podControl = &fakePodControl
+
+	job := newJob(4, 1)
+	fakeClient.JobsList = api.JobList{Items: []api.Job{job}}
+	if err := manager.syncJob(job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := 2, fakeClient.JobStatus.Status.Succeeded; e != a {
+		t.Fatalf("expected %d succeeded, got %d", e, a)
+	}
+
+	// pod0 is removed, and pod2 succeeds: three pods have succeeded although
+	// only two are left.
+	pods := newPodList(api.PodSucceeded, api.PodSucceeded, api.PodSucceeded)
+	fakeClient.PodsList = api.PodList{Items: pods.Items[1:]}
+	job.Status = fakeClient.JobStatus.Status
+	fakeClient.JobsList = api.JobList{Items: []api.Job{job}}
+	if err := manager.syncJob(job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status := fakeClient.JobStatus.Status
+	if e, a := 3, status.Succeeded; e != a {
+		t.Errorf("expected %d succeeded, got %d", e, a)
+	}
+	if e, a := 1, status.Active; e != a {
+		t.Errorf("expected %d active, got %d", e, a)
+	}
+
+	// pod3 succeeds, which completes the job: no more pods are created.
+	pods = newPodList(api.PodSucceeded, api.PodSucceeded, api.PodSucceeded, api.PodSucceeded)
+	fakeClient.PodsList = api.PodList{Items: pods.Items[2:]}
+	job.Status = status
+	fakeClient.JobsList = api.JobList{Items: []api.Job{job}}
+	fakePodControl.createdJobs = nil
+	if err := manager.syncJob(job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	status = fakeClient.JobStatus.Status
+	if e, a := 4, status.Succeeded; e != a {
+		t.Errorf("expected %d succeeded, got %d", e, a)
+	}
+	if status.CompletionTime == nil {
+		t.Errorf("expected the job to be complete")
+	}
+	if n := len(fakePodControl.createdJobs); n != 0 {
+		t.Errorf("expected no pods to be created, got %d", n)
+	}
+}
+
+func TestSyncJobFromStaleJob(t *testing.T) {
+	fakeClient := client.Fake{PodsList: newPodList(api.PodSucceeded)}
+	fakePodControl := FakePodControl{}
+	manager := NewJobManager(&fakeClient)
+	manager.podControl = &fakePodControl
+
+	job := newJob(1, 1)
+	stale := job
+	fakeClient.JobsList = api.JobList{Items: []api.Job{job}}
+	if err := manager.syncJob(job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := 1, fakeClient.JobStatus.Status.Succeeded; e != a {
+		t.Fatalf("expected %d succeeded, got %d", e, a)
+	}
+
+	// A queued watch event carries the job without the recorded status: the
+	// sync starts from the latest job instead.
+	fakeClient.JobsList = api.JobList{Items: []api.Job{fakeClient.JobStatus}}
+	if err := manager.syncJob(stale); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(fakePodControl.createdJobs); n != 0 {
+		t.Errorf("expected no pods to be created, got %d", n)
+	}
+
+	// Were the latest job stale as well, the succeeded pod listed is counted.
+	fakeClient.JobsList = api.JobList{Items: []api.Job{stale}}
+	if err := manager.syncJob(stale); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n := len(fakePodControl.createdJobs); n != 0 {
+		t.Errorf("expected no pods to be created, got %d", n)
+	}
+	if e, a := 1, fakeClient.JobStatus.Status.Succeeded; e != a {
+		t.Errorf("expected %d succeeded, got %d", e, a)
+	}
+}
+
+func TestSyncJobDeleted(t *testing.T) {
+	fakeClient := client.Fake{Err: errors.NewNotFound("jobs", "foobar")}
+	fakePodControl := FakePodControl{}
+	manager := NewJobManager(&fakeClient)
+	manager.podControl = &fakePodControl
+	if err := manager.syncJob(newJob(1, 1)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if n := len(fakePodControl.createdJobs); n != 0 {
+		t.Errorf("expected no pods to be created, got %d", n)
+	}
+}
+
+func TestSyncJobNoStatusUpdate(t *testing.T) {
+	now := util.Now()
+	fakeClient := client.Fake{PodsList: newPodList(api.PodRunning, api.PodSucceeded)}
+	fakePodControl := FakePodControl{}
+	manager := NewJobManager(&fakeClient)
+	manager.podControl = &fakePodControl
+
+	job := newJob(3, 1)
+	job.Status = api.JobStatus{StartTime: &now, Active: 1, Succeeded: 1}
+	fakeClient.JobsList = api.JobList{Items: []api.Job{job}}
+	if err := manager.syncJob(job); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, action := range fakeClient.Actions {
+		if action.Action == "update-status-job" {
+			t.Errorf("unexpected status update: %#v", action)
+		}
+	}
+}
+
+func TestSynchronize(t *testing.T) {
+	fakeClient := client.Fake{JobsList: api.JobList{Items: []api.Job{newJob(1, 1), newJob(2, 2)}}}
+	manager := NewJobManager(&fakeClient)
+	var lock sync.Mutex
+	synced := 0
+	manager.syncHandler = func(job api.Job) error {
+		lock.Lock()
+		defer lock.Unlock()
+		synced++
+		return nil
+	}
+	manager.synchronize()
+	if synced != 2 {
+		t.Errorf("expected 2 jobs to be synced, got %d", synced)
+	}
+}
+
+func TestCreatePod(t *testing.T) {
+	fakeClient := client.Fake{}
+	podControl := RealPodControl{kubeClient: &fakeClient}
+	if err := podControl.createPod(api.NamespaceDefault, newJob(1, 1)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(fakeClient.Actions) != 1 || fakeClient.Actions[0].Action != "create-pod" {
+		t.Errorf("unexpected actions: %#v", fakeClient.Actions)
+	}
+}
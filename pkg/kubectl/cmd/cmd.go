@@ -260,6 +260,7 @@ Find more information at https://github.com/GoogleCloudPlatform/kubernetes.`,
 	cmds.AddCommand(f.NewCmdExposeService(out))
 
 	cmds.AddCommand(f.NewCmdLabel(out))
+	cmds.AddCommand(f.NewCmdTaint(out))
 
 	cmds.AddCommand(cmdconfig.NewCmdConfig(out))
 	cmds.AddCommand(f.NewCmdClusterInfo(out))
@@ -0,0 +1,229 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/cmd/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubectl/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+const (
+	taint_long = `Update the taints on one or more nodes.
+
+A taint consists of a key, a value and an effect, written as key=value:effect. The value may be omitted.
+The only supported effect is NoSchedule: pods which do not tolerate the taint are not scheduled onto the node.
+If --overwrite is true, then an existing taint with the same key and effect can be overwritten, otherwise attempting to overwrite a taint will result in an error.`
+	taint_example = `// Update node 'foo' with a taint with key 'dedicated', value 'team-a' and effect 'NoSchedule'.
+// If a taint with that key and effect already exists, its value is replaced as specified.
+$ kubectl taint --overwrite nodes foo dedicated=team-a:NoSchedule
+
+// Remove from node 'foo' the taint with key 'dedicated' and effect 'NoSchedule' if one exists.
+$ kubectl taint nodes foo dedicated:NoSchedule-
+
+// Remove from node 'foo' all the taints with key 'dedicated'.
+$ kubectl taint nodes foo dedicated-`
+)
+
+func (f *Factory) NewCmdTaint(out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "taint [--overwrite] NODE NAME KEY_1=VAL_1:TAINT_EFFECT_1 ... KEY_N=VAL_N:TAINT_EFFECT_N",
+		Short:   "Update the taints on one or more nodes",
+		Long:    taint_long,
+		Example: taint_example,
+		Run: func(cmd *cobra.Command, args []string) {
+			err := RunTaint(f, out, cmd, args)
+			util.CheckErr(err)
+		},
+	}
+	util.AddPrinterFlags(cmd)
+	cmd.Flags().Bool("overwrite", false, "If true, allow taints to be overwritten, otherwise reject taint updates that overwrite existing taints.")
+	cmd.Flags().StringP("selector", "l", "", "Selector (label query) to filter on")
+	cmd.Flags().Bool("all", false, "select all nodes in the cluster")
+	return cmd
+}
+
+// taintRemoval identifies the taints to remove from a node. An empty effect
+// removes the taints with the key whatever their effect.
+type taintRemoval struct {
+	key    string
+	effect api.TaintEffect
+}
+
+func (r taintRemoval) matches(taint *api.Taint) bool {
+	return taint.Key == r.key && (len(r.effect) == 0 || taint.Effect == r.effect)
+}
+
+// parseTaint parses a taint written as key=value:effect or key:effect.
+func parseTaint(spec string) (api.Taint, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 2 || len(parts[1]) == 0 {
+		return api.Taint{}, fmt.Errorf("invalid taint spec: %v", spec)
+	}
+	taint := api.Taint{Key: parts[0], Effect: api.TaintEffect(parts[1])}
+	if strings.Index(parts[0], "=") != -1 {
+		keyValue := strings.Split(parts[0], "=")
+		if len(keyValue) != 2 {
+			return api.Taint{}, fmt.Errorf("invalid taint spec: %v", spec)
+		}
+		taint.Key, taint.Value = keyValue[0], keyValue[1]
+	}
+	if len(taint.Key) == 0 {
+		return api.Taint{}, fmt.Errorf("invalid taint spec: %v", spec)
+	}
+	return taint, nil
+}
+
+func parseTaints(spec []string) ([]api.Taint, []taintRemoval, error) {
+	var taints []api.Taint
+	var remove []taintRemoval
+	for _, taintSpec := range spec {
+		if strings.HasSuffix(taintSpec, "-") {
+			removal := taintRemoval{key: taintSpec[:len(taintSpec)-1]}
+			if parts := strings.Split(removal.key, ":"); len(parts) == 2 {
+				removal.key, removal.effect = parts[0], api.TaintEffect(parts[1])
+			}
+			if len(removal.key) == 0 || strings.Contains(removal.key, "=") {
+				return nil, nil, fmt.Errorf("invalid taint spec: %v", taintSpec)
+			}
+			remove = append(remove, removal)
+			continue
+		}
+		taint, err := parseTaint(taintSpec)
+		if err != nil {
+			return nil, nil, err
+		}
+		taints = append(taints, taint)
+	}
+	for _, removal := range remove {
+		for i := range taints {
+			if removal.matches(&taints[i]) {
+				return nil, nil, fmt.Errorf("can not both modify and remove a taint in the same command")
+			}
+		}
+	}
+	return taints, remove, nil
+}
+
+func taintFunc(obj runtime.Object, overwrite bool, taints []api.Taint, remove []taintRemoval) (runtime.Object, error) {
+	node, ok := obj.(*api.Node)
+	if !ok {
+		return nil, fmt.Errorf("taints can only be set on nodes, not on %T", obj)
+	}
+
+	result := []api.Taint{}
+	for _, taint := range node.Spec.Taints {
+		removed := false
+		for _, removal := range remove {
+			if removal.matches(&taint) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			result = append(result, taint)
+		}
+	}
+
+	for _, taint := range taints {
+		replaced := false
+		for i := range result {
+			if result[i].Key != taint.Key || result[i].Effect != taint.Effect {
+				continue
+			}
+			if !overwrite {
+				return nil, fmt.Errorf("node %s already has a taint with key %s and effect %s (value %s), and --overwrite is false", node.Name, taint.Key, taint.Effect, result[i].Value)
+			}
+			result[i] = taint
+			replaced = true
+		}
+		if !replaced {
+			result = append(result, taint)
+		}
+	}
+
+	node.Spec.Taints = result
+	return node, nil
+}
+
+func RunTaint(f *Factory, out io.Writer, cmd *cobra.Command, args []string) error {
+	resources, taintArgs := []string{}, []string{}
+	first := true
+	for _, s := range args {
+		isTaint := strings.Contains(s, ":") || strings.Contains(s, "=") || strings.HasSuffix(s, "-")
+		switch {
+		case first && isTaint:
+			first = false
+			fallthrough
+		case !first && isTaint:
+			taintArgs = append(taintArgs, s)
+		case first && !isTaint:
+			resources = append(resources, s)
+		case !first && !isTaint:
+			return util.UsageError(cmd, "all resources must be specified before taint changes: %s", s)
+		}
+	}
+	if len(resources) < 1 {
+		return util.UsageError(cmd, "one or more resources must be specified as <resource> <name> or <resource>/<name>")
+	}
+	if len(taintArgs) < 1 {
+		return util.UsageError(cmd, "at least one taint update is required")
+	}
+
+	selector := util.GetFlagString(cmd, "selector")
+	all := util.GetFlagBool(cmd, "all")
+	overwrite := util.GetFlagBool(cmd, "overwrite")
+
+	taints, remove, err := parseTaints(taintArgs)
+	if err != nil {
+		return err
+	}
+
+	mapper, typer := f.Object()
+	b := resource.NewBuilder(mapper, typer, f.ClientMapperForCommand()).
+		ContinueOnError().
+		SelectorParam(selector).
+		ResourceTypeOrNameArgs(all, resources...).
+		Flatten().
+		Latest()
+
+	r := b.Do()
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	return r.Visit(func(info *resource.Info) error {
+		obj, err := updateObject(info, func(obj runtime.Object) (runtime.Object, error) {
+			return taintFunc(obj, overwrite, taints, remove)
+		})
+		if err != nil {
+			return err
+		}
+
+		printer, err := f.PrinterForMapping(cmd, info.Mapping)
+		if err != nil {
+			return err
+		}
+		return printer.PrintObj(obj, out)
+	})
+}
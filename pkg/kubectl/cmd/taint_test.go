@@ -0,0 +1,250 @@
+/*
+Copyright 2014 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestParseTaints(t *testing.T) {
+	tests := []struct {
+		taints         []string
+		expected       []api.Taint
+		expectedRemove []taintRemoval
+		expectErr      bool
+	}{
+		{
+			taints: []string{"a=b:NoSchedule", "c:NoSchedule"},
+			expected: []api.Taint{
+				{Key: "a", Value: "b", Effect: api.TaintEffectNoSchedule},
+				{Key: "c", Effect: api.TaintEffectNoSchedule},
+			},
+		},
+		{
+			taints:         []string{"a=b:NoSchedule", "c:NoSchedule-", "d-"},
+			expected:       []api.Taint{{Key: "a", Value: "b", Effect: api.TaintEffectNoSchedule}},
+			expectedRemove: []taintRemoval{{key: "c", effect: api.TaintEffectNoSchedule}, {key: "d"}},
+		},
+		{
+			taints:    []string{"a=b"},
+			expectErr: true,
+		},
+		{
+			taints:    []string{"=b:NoSchedule"},
+			expectErr: true,
+		},
+		{
+			taints:    []string{"a=b=c:NoSchedule"},
+			expectErr: true,
+		},
+		{
+			taints:    []string{"a=b-"},
+			expectErr: true,
+		},
+		{
+			taints:    []string{"a=b:NoSchedule", "a-"},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		taints, remove, err := parseTaints(test.taints)
+		if test.expectErr && err == nil {
+			t.Errorf("unexpected non-error: %v", test)
+		}
+		if !test.expectErr && err != nil {
+			t.Errorf("unexpected error: %v %v", err, test)
+		}
+		if !reflect.DeepEqual(taints, test.expected) {
+			t.Errorf("expected: %v, got %v", test.expected, taints)
+		}
+		if !reflect.DeepEqual(remove, test.expectedRemove) {
+			t.Errorf("expected: %v, got %v", test.expectedRemove, remove)
+		}
+	}
+}
+
+func TestTaintFunc(t *testing.T) {
+	nodeWithTaints := func(taints ...api.Taint) *api.Node {
+		return &api.Node{Spec: api.NodeSpec{Taints: taints}}
+	}
+	a := api.Taint{Key: "a", Value: "b", Effect: api.TaintEffectNoSchedule}
+	a2 := api.Taint{Key: "a", Value: "c", Effect: api.TaintEffectNoSchedule}
+	aOther := api.Taint{Key: "a", Effect: "Other"}
+	c := api.Taint{Key: "c", Effect: api.TaintEffectNoSchedule}
+
+	tests := []struct {
+		obj       runtime.Object
+		overwrite bool
+		taints    []api.Taint
+		remove    []taintRemoval
+		expected  runtime.Object
+		expectErr bool
+	}{
+		{
+			obj:      nodeWithTaints(),
+			taints:   []api.Taint{a},
+			expected: nodeWithTaints(a),
+		},
+		{
+			obj:      nodeWithTaints(a),
+			taints:   []api.Taint{c},
+			expected: nodeWithTaints(a, c),
+		},
+		{
+			obj:       nodeWithTaints(a),
+			taints:    []api.Taint{a2},
+			expectErr: true,
+		},
+		{
+			obj:       nodeWithTaints(a),
+			overwrite: true,
+			taints:    []api.Taint{a2},
+			expected:  nodeWithTaints(a2),
+		},
+		{
+			obj:      nodeWithTaints(a, aOther, c),
+			remove:   []taintRemoval{{key: "a", effect: api.TaintEffectNoSchedule}},
+			expected: nodeWithTaints(aOther, c),
+		},
+		{
+			obj:      nodeWithTaints(a, aOther, c),
+			remove:   []taintRemoval{{key: "a"}},
+			expected: nodeWithTaints(c),
+		},
+		{
+			obj:      nodeWithTaints(a),
+			remove:   []taintRemoval{{key: "a"}},
+			expected: nodeWithTaints(),
+		},
+		{
+			obj:       &api.Pod{},
+			taints:    []api.Taint{a},
+			expectErr: true,
+		},
+	}
+	for _, test := range tests {
+		out, err := taintFunc(test.obj, test.overwrite, test.taints, test.remove)
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("unexpected non-error: %v", test)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("unexpected error: %v %v", err, test)
+		}
+		if !api.Semantic.DeepEqual(out, test.expected) {
+			t.Errorf("expected: %v, got %v", test.expected, out)
+		}
+	}
+}
+
+func TestTaintErrors(t *testing.T) {
+	testCases := map[string]struct {
+		args  []string
+		errFn func(error) bool
+	}{
+		"no args": {
+			args:  []string{},
+			errFn: func(err error) bool { return strings.Contains(err.Error(), "one or more resources must be specified") },
+		},
+		"not enough taints": {
+			args:  []string{"nodes", "foo"},
+			errFn: func(err error) bool { return strings.Contains(err.Error(), "at least one taint update is required") },
+		},
+		"resources after taints": {
+			args: []string{"nodes", "a=b:NoSchedule", "foo"},
+			errFn: func(err error) bool {
+				return strings.Contains(err.Error(), "all resources must be specified before taint changes")
+			},
+		},
+		"invalid taint": {
+			args:  []string{"nodes", "foo", "a=b"},
+			errFn: func(err error) bool { return strings.Contains(err.Error(), "invalid taint spec") },
+		},
+	}
+
+	for k, testCase := range testCases {
+		f, tf, _ := NewAPIFactory()
+		tf.Printer = &testPrinter{}
+
+		buf := bytes.NewBuffer([]byte{})
+		cmd := f.NewCmdTaint(buf)
+		cmd.SetOutput(buf)
+
+		err := RunTaint(f, buf, cmd, testCase.args)
+		if err == nil || !testCase.errFn(err) {
+			t.Errorf("%s: unexpected error: %v", k, err)
+			continue
+		}
+		if tf.Printer.(*testPrinter).Objects != nil {
+			t.Errorf("unexpected print to default printer")
+		}
+	}
+}
+
+func TestTaintNode(t *testing.T) {
+	node := &api.Node{
+		ObjectMeta: api.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: api.NodeSpec{
+			ExternalID: "ext",
+			Taints:     []api.Taint{{Key: "dedicated", Value: "team-a", Effect: api.TaintEffectNoSchedule}},
+		},
+	}
+
+	f, tf, codec := NewAPIFactory()
+	tf.Printer = &testPrinter{}
+	tf.Client = &client.FakeRESTClient{
+		Codec: codec,
+		Client: client.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+			switch {
+			case req.Method == "GET" && req.URL.Path == "/nodes/foo":
+				return &http.Response{StatusCode: 200, Body: objBody(codec, node)}, nil
+			case req.Method == "PUT" && req.URL.Path == "/nodes/foo":
+				return &http.Response{StatusCode: 200, Body: req.Body}, nil
+			default:
+				t.Fatalf("unexpected request: %s %#v\n%#v", req.Method, req.URL, req)
+				return nil, nil
+			}
+		}),
+	}
+	buf := bytes.NewBuffer([]byte{})
+
+	cmd := f.NewCmdTaint(buf)
+	if err := RunTaint(f, buf, cmd, []string{"nodes", "foo", "dedicated-", "maintenance:NoSchedule"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	objects := tf.Printer.(*testPrinter).Objects
+	if len(objects) != 1 {
+		t.Fatalf("unexpected printed objects: %#v", objects)
+	}
+	expected := []api.Taint{{Key: "maintenance", Effect: api.TaintEffectNoSchedule}}
+	if taints := objects[0].(*api.Node).Spec.Taints; !reflect.DeepEqual(taints, expected) {
+		t.Errorf("expected taints %#v, got %#v", expected, taints)
+	}
+}
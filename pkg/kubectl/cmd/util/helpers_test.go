@@ -136,15 +136,15 @@ func TestMerge(t *testing.T) {
 			kind: "Service",
 			obj: &api.Service{
 				Spec: api.ServiceSpec{
-					Port: 10,
+					Ports: []api.ServicePort{{Port: 10}},
 				},
 			},
-			fragment: `{ "apiVersion": "v1beta1", "port": 0 }`,
+			fragment: `{ "apiVersion": "v1beta1", "ports": [{ "port": 0 }] }`,
 			expected: &api.Service{
 				Spec: api.ServiceSpec{
-					Port:            0,
-					Protocol:        "TCP",
+					Ports:           []api.ServicePort{{Port: 0, Protocol: "TCP"}},
 					SessionAffinity: "None",
+					Type:            api.ServiceTypeClusterIP,
 				},
 			},
 		},
@@ -160,8 +160,8 @@ func TestMerge(t *testing.T) {
 			fragment: `{ "apiVersion": "v1beta1", "selector": { "version": "v2" } }`,
 			expected: &api.Service{
 				Spec: api.ServiceSpec{
-					Protocol:        "TCP",
 					SessionAffinity: "None",
+					Type:            api.ServiceTypeClusterIP,
 					Selector: map[string]string{
 						"version": "v2",
 					},
@@ -26,6 +26,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/cnaize/kubernetes/pkg/api"
 	"github.com/cnaize/kubernetes/pkg/types"
 	"github.com/golang/glog"
@@ -66,6 +67,14 @@ func DescriberFor(kind string, c *client.Client) (Describer, bool) {
 		return &PodDescriber{c}, true
 	case "ReplicationController":
 		return &ReplicationControllerDescriber{c}, true
+	case "Job":
+		return &JobDescriber{c}, true
+	case "DaemonSet":
+		return &DaemonSetDescriber{c}, true
+	case "Deployment":
+		return &DeploymentDescriber{c}, true
+	case "HorizontalPodAutoscaler":
+		return &HorizontalPodAutoscalerDescriber{c}, true
 	case "Service":
 		return &ServiceDescriber{c}, true
 	case "Minion", "Node":
@@ -301,6 +310,166 @@ func describeReplicationController(controller *api.ReplicationController, events
 	})
 }
 
+// JobDescriber generates information about a job and the pods it has created.
+type JobDescriber struct {
+	client.Interface
+}
+
+func (d *JobDescriber) Describe(namespace, name string) (string, error) {
+	job, err := d.Jobs(namespace).Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	events, _ := d.Events(namespace).Search(job)
+
+	return describeJob(job, events)
+}
+
+func describeJob(job *api.Job, events *api.EventList) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		fmt.Fprintf(out, "Name:\t%s\n", job.Name)
+		if job.Spec.Template != nil {
+			fmt.Fprintf(out, "Image(s):\t%s\n", makeImageList(&job.Spec.Template.Spec))
+		} else {
+			fmt.Fprintf(out, "Image(s):\t%s\n", "<no template>")
+		}
+		fmt.Fprintf(out, "Selector:\t%s\n", formatLabels(job.Spec.Selector))
+		fmt.Fprintf(out, "Labels:\t%s\n", formatLabels(job.Labels))
+		fmt.Fprintf(out, "Parallelism:\t%d\n", job.Spec.Parallelism)
+		fmt.Fprintf(out, "Completions:\t%d\n", job.Spec.Completions)
+		if job.Status.StartTime != nil {
+			fmt.Fprintf(out, "Start Time:\t%s\n", job.Status.StartTime.Time.Format(time.RFC1123Z))
+		}
+		if job.Status.CompletionTime != nil {
+			fmt.Fprintf(out, "Completion Time:\t%s\n", job.Status.CompletionTime.Time.Format(time.RFC1123Z))
+		}
+		fmt.Fprintf(out, "Pods Statuses:\t%d Active / %d Succeeded / %d Failed\n", job.Status.Active, job.Status.Succeeded, job.Status.Failed)
+		if events != nil {
+			describeEvents(events, out)
+		}
+		return nil
+	})
+}
+
+// DaemonSetDescriber generates information about a daemon set and the pods it has created.
+type DaemonSetDescriber struct {
+	client.Interface
+}
+
+func (d *DaemonSetDescriber) Describe(namespace, name string) (string, error) {
+	ds, err := d.DaemonSets(namespace).Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	events, _ := d.Events(namespace).Search(ds)
+
+	return describeDaemonSet(ds, events)
+}
+
+func describeDaemonSet(ds *api.DaemonSet, events *api.EventList) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		fmt.Fprintf(out, "Name:\t%s\n", ds.Name)
+		if ds.Spec.Template != nil {
+			fmt.Fprintf(out, "Image(s):\t%s\n", makeImageList(&ds.Spec.Template.Spec))
+		} else {
+			fmt.Fprintf(out, "Image(s):\t%s\n", "<no template>")
+		}
+		fmt.Fprintf(out, "Selector:\t%s\n", formatLabels(ds.Spec.Selector))
+		fmt.Fprintf(out, "Node-Selector:\t%s\n", formatLabels(ds.Spec.NodeSelector))
+		fmt.Fprintf(out, "Labels:\t%s\n", formatLabels(ds.Labels))
+		fmt.Fprintf(out, "Desired Number of Nodes Scheduled:\t%d\n", ds.Status.DesiredNumberScheduled)
+		fmt.Fprintf(out, "Current Number of Nodes Scheduled:\t%d\n", ds.Status.CurrentNumberScheduled)
+		fmt.Fprintf(out, "Number of Nodes Misscheduled:\t%d\n", ds.Status.NumberMisscheduled)
+		if events != nil {
+			describeEvents(events, out)
+		}
+		return nil
+	})
+}
+
+// DeploymentDescriber generates information about a deployment and the progress of its rollout.
+type DeploymentDescriber struct {
+	client.Interface
+}
+
+func (d *DeploymentDescriber) Describe(namespace, name string) (string, error) {
+	deployment, err := d.Deployments(namespace).Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	events, _ := d.Events(namespace).Search(deployment)
+
+	return describeDeployment(deployment, events)
+}
+
+func describeDeployment(deployment *api.Deployment, events *api.EventList) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		fmt.Fprintf(out, "Name:\t%s\n", deployment.Name)
+		if deployment.Spec.Template != nil {
+			fmt.Fprintf(out, "Image(s):\t%s\n", makeImageList(&deployment.Spec.Template.Spec))
+		} else {
+			fmt.Fprintf(out, "Image(s):\t%s\n", "<no template>")
+		}
+		fmt.Fprintf(out, "Selector:\t%s\n", formatLabels(deployment.Spec.Selector))
+		fmt.Fprintf(out, "Labels:\t%s\n", formatLabels(deployment.Labels))
+		fmt.Fprintf(out, "Strategy:\t%s\n", deployment.Spec.Strategy.Type)
+		if params := deployment.Spec.Strategy.RollingUpdate; params != nil {
+			fmt.Fprintf(out, "Rolling Update:\t%s max unavailable / %s max surge\n", params.MaxUnavailable.String(), params.MaxSurge.String())
+		}
+		fmt.Fprintf(out, "Paused:\t%v\n", deployment.Spec.Paused)
+		fmt.Fprintf(out, "Revision:\t%d\n", deployment.Status.Revision)
+		fmt.Fprintf(out, "Replicas:\t%d desired / %d total / %d updated / %d available\n", deployment.Spec.Replicas, deployment.Status.Replicas, deployment.Status.UpdatedReplicas, deployment.Status.AvailableReplicas)
+		if events != nil {
+			describeEvents(events, out)
+		}
+		return nil
+	})
+}
+
+// HorizontalPodAutoscalerDescriber generates information about a horizontal
+// pod autoscaler and its last scaling decision.
+type HorizontalPodAutoscalerDescriber struct {
+	client.Interface
+}
+
+func (d *HorizontalPodAutoscalerDescriber) Describe(namespace, name string) (string, error) {
+	autoscaler, err := d.HorizontalPodAutoscalers(namespace).Get(name)
+	if err != nil {
+		return "", err
+	}
+
+	events, _ := d.Events(namespace).Search(autoscaler)
+
+	return describeHorizontalPodAutoscaler(autoscaler, events)
+}
+
+func describeHorizontalPodAutoscaler(autoscaler *api.HorizontalPodAutoscaler, events *api.EventList) (string, error) {
+	return tabbedString(func(out io.Writer) error {
+		fmt.Fprintf(out, "Name:\t%s\n", autoscaler.Name)
+		fmt.Fprintf(out, "Labels:\t%s\n", formatLabels(autoscaler.Labels))
+		fmt.Fprintf(out, "Reference:\tReplicationController/%s\n", autoscaler.Spec.ScaleRef.Name)
+		fmt.Fprintf(out, "Target CPU utilization:\t%d%%\n", autoscaler.Spec.TargetCPUUtilizationPercentage)
+		if utilization := autoscaler.Status.CurrentCPUUtilizationPercentage; utilization != nil {
+			fmt.Fprintf(out, "Current CPU utilization:\t%d%%\n", *utilization)
+		} else {
+			fmt.Fprintf(out, "Current CPU utilization:\t<unknown>\n")
+		}
+		fmt.Fprintf(out, "Min replicas:\t%d\n", autoscaler.Spec.MinReplicas)
+		fmt.Fprintf(out, "Max replicas:\t%d\n", autoscaler.Spec.MaxReplicas)
+		fmt.Fprintf(out, "Replicas:\t%d current / %d desired\n", autoscaler.Status.CurrentReplicas, autoscaler.Status.DesiredReplicas)
+		if autoscaler.Status.LastScaleTime != nil {
+			fmt.Fprintf(out, "Last scale time:\t%s\n", autoscaler.Status.LastScaleTime.Time.Format(time.RFC1123Z))
+		}
+		if events != nil {
+			describeEvents(events, out)
+		}
+		return nil
+	})
+}
+
 // ServiceDescriber generates information about a service.
 type ServiceDescriber struct {
 	client.Interface
@@ -328,13 +497,25 @@ func describeService(service *api.Service, endpoints *api.Endpoints, events *api
 		fmt.Fprintf(out, "Name:\t%s\n", service.Name)
 		fmt.Fprintf(out, "Labels:\t%s\n", formatLabels(service.Labels))
 		fmt.Fprintf(out, "Selector:\t%s\n", formatLabels(service.Spec.Selector))
+		fmt.Fprintf(out, "Type:\t%s\n", service.Spec.Type)
 		fmt.Fprintf(out, "IP:\t%s\n", service.Spec.PortalIP)
 		if len(service.Spec.PublicIPs) > 0 {
 			list := strings.Join(service.Spec.PublicIPs, ", ")
 			fmt.Fprintf(out, "Public IPs:\t%s\n", list)
 		}
-		fmt.Fprintf(out, "Port:\t%d\n", service.Spec.Port)
-		fmt.Fprintf(out, "Endpoints:\t%s\n", formatEndpoints(endpoints))
+		for i := range service.Spec.Ports {
+			sp := &service.Spec.Ports[i]
+
+			name := sp.Name
+			if name == "" {
+				name = "<unnamed>"
+			}
+			fmt.Fprintf(out, "Port:\t%s\t%d/%s\n", name, sp.Port, sp.Protocol)
+			if sp.NodePort != 0 {
+				fmt.Fprintf(out, "NodePort:\t%s\t%d/%s\n", name, sp.NodePort, sp.Protocol)
+			}
+			fmt.Fprintf(out, "Endpoints:\t%s\n", formatEndpoints(endpoints, util.NewStringSet(sp.Name)))
+		}
 		fmt.Fprintf(out, "Session Affinity:\t%s\n", service.Spec.SessionAffinity)
 		if events != nil {
 			describeEvents(events, out)
@@ -31,6 +31,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/conversion"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/cnaize/kubernetes/pkg/api"
 	"github.com/docker/docker/pkg/units"
 	"github.com/ghodss/yaml"
@@ -228,7 +229,11 @@ func (h *HumanReadablePrinter) validatePrintHandlerFunc(printFunc reflect.Value)
 
 var podColumns = []string{"POD", "IP", "CONTAINER(S)", "IMAGE(S)", "HOST", "LABELS", "STATUS", "CREATED"}
 var replicationControllerColumns = []string{"CONTROLLER", "CONTAINER(S)", "IMAGE(S)", "SELECTOR", "REPLICAS"}
-var serviceColumns = []string{"NAME", "LABELS", "SELECTOR", "IP", "PORT"}
+var jobColumns = []string{"JOB", "CONTAINER(S)", "IMAGE(S)", "SELECTOR", "COMPLETIONS", "SUCCEEDED"}
+var daemonSetColumns = []string{"DAEMON SET", "CONTAINER(S)", "IMAGE(S)", "SELECTOR", "NODE-SELECTOR", "DESIRED", "CURRENT"}
+var deploymentColumns = []string{"DEPLOYMENT", "CONTAINER(S)", "IMAGE(S)", "SELECTOR", "REPLICAS", "UPDATED", "AVAILABLE"}
+var horizontalPodAutoscalerColumns = []string{"NAME", "REFERENCE", "TARGET", "CURRENT", "MINPODS", "MAXPODS", "REPLICAS"}
+var serviceColumns = []string{"NAME", "LABELS", "SELECTOR", "IP", "PORT(S)"}
 var endpointColumns = []string{"NAME", "ENDPOINTS"}
 var nodeColumns = []string{"NAME", "LABELS", "STATUS"}
 var statusColumns = []string{"STATUS"}
@@ -237,6 +242,9 @@ var limitRangeColumns = []string{"NAME"}
 var resourceQuotaColumns = []string{"NAME"}
 var namespaceColumns = []string{"NAME", "LABELS", "STATUS"}
 var secretColumns = []string{"NAME", "DATA"}
+var persistentVolumeColumns = []string{"NAME", "LABELS", "CAPACITY", "STATUS", "CLAIM"}
+var persistentVolumeClaimColumns = []string{"NAME", "LABELS", "STATUS", "VOLUME"}
+var serviceAccountColumns = []string{"NAME", "SECRETS"}
 
 // addDefaultHandlers adds print handlers for default Kubernetes types.
 func (h *HumanReadablePrinter) addDefaultHandlers() {
@@ -244,6 +252,14 @@ func (h *HumanReadablePrinter) addDefaultHandlers() {
 	h.Handler(podColumns, printPodList)
 	h.Handler(replicationControllerColumns, printReplicationController)
 	h.Handler(replicationControllerColumns, printReplicationControllerList)
+	h.Handler(jobColumns, printJob)
+	h.Handler(jobColumns, printJobList)
+	h.Handler(daemonSetColumns, printDaemonSet)
+	h.Handler(daemonSetColumns, printDaemonSetList)
+	h.Handler(deploymentColumns, printDeployment)
+	h.Handler(deploymentColumns, printDeploymentList)
+	h.Handler(horizontalPodAutoscalerColumns, printHorizontalPodAutoscaler)
+	h.Handler(horizontalPodAutoscalerColumns, printHorizontalPodAutoscalerList)
 	h.Handler(serviceColumns, printService)
 	h.Handler(serviceColumns, printServiceList)
 	h.Handler(endpointColumns, printEndpoints)
@@ -261,6 +277,12 @@ func (h *HumanReadablePrinter) addDefaultHandlers() {
 	h.Handler(namespaceColumns, printNamespaceList)
 	h.Handler(secretColumns, printSecret)
 	h.Handler(secretColumns, printSecretList)
+	h.Handler(persistentVolumeColumns, printPersistentVolume)
+	h.Handler(persistentVolumeColumns, printPersistentVolumeList)
+	h.Handler(persistentVolumeClaimColumns, printPersistentVolumeClaim)
+	h.Handler(persistentVolumeClaimColumns, printPersistentVolumeClaimList)
+	h.Handler(serviceAccountColumns, printServiceAccount)
+	h.Handler(serviceAccountColumns, printServiceAccountList)
 }
 
 func (h *HumanReadablePrinter) unknown(data []byte, w io.Writer) error {
@@ -275,7 +297,9 @@ func (h *HumanReadablePrinter) printHeader(columnNames []string, w io.Writer) er
 	return nil
 }
 
-func formatEndpoints(endpoints *api.Endpoints) string {
+// formatEndpoints lists the endpoint addresses of the given ports, or of all
+// ports if ports is nil.
+func formatEndpoints(endpoints *api.Endpoints, ports util.StringSet) string {
 	if len(endpoints.Subsets) == 0 {
 		return "<none>"
 	}
@@ -287,7 +311,7 @@ Loop:
 		ss := &endpoints.Subsets[i]
 		for i := range ss.Ports {
 			port := &ss.Ports[i]
-			if port.Name == "" { // TODO: add multi-port support.
+			if ports == nil || ports.Has(port.Name) {
 				for i := range ss.Addresses {
 					if len(list) == max {
 						more = true
@@ -389,12 +413,161 @@ func printReplicationControllerList(list *api.ReplicationControllerList, w io.Wr
 	return nil
 }
 
+func printJob(job *api.Job, w io.Writer) error {
+	var containers []api.Container
+	if job.Spec.Template != nil {
+		containers = job.Spec.Template.Spec.Containers
+	}
+	var firstContainer api.Container
+	if len(containers) > 0 {
+		firstContainer, containers = containers[0], containers[1:]
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\n",
+		job.Name,
+		firstContainer.Name,
+		firstContainer.Image,
+		formatLabels(job.Spec.Selector),
+		job.Spec.Completions,
+		job.Status.Succeeded)
+	if err != nil {
+		return err
+	}
+	// Lay out all the other containers on separate lines.
+	for _, container := range containers {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", "", container.Name, container.Image, "", "", "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printJobList(list *api.JobList, w io.Writer) error {
+	for _, job := range list.Items {
+		if err := printJob(&job, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDaemonSet(ds *api.DaemonSet, w io.Writer) error {
+	var containers []api.Container
+	if ds.Spec.Template != nil {
+		containers = ds.Spec.Template.Spec.Containers
+	}
+	var firstContainer api.Container
+	if len(containers) > 0 {
+		firstContainer, containers = containers[0], containers[1:]
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%d\n",
+		ds.Name,
+		firstContainer.Name,
+		firstContainer.Image,
+		formatLabels(ds.Spec.Selector),
+		formatLabels(ds.Spec.NodeSelector),
+		ds.Status.DesiredNumberScheduled,
+		ds.Status.CurrentNumberScheduled)
+	if err != nil {
+		return err
+	}
+	// Lay out all the other containers on separate lines.
+	for _, container := range containers {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "", container.Name, container.Image, "", "", "", "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDaemonSetList(list *api.DaemonSetList, w io.Writer) error {
+	for _, ds := range list.Items {
+		if err := printDaemonSet(&ds, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDeployment(deployment *api.Deployment, w io.Writer) error {
+	var containers []api.Container
+	if deployment.Spec.Template != nil {
+		containers = deployment.Spec.Template.Spec.Containers
+	}
+	var firstContainer api.Container
+	if len(containers) > 0 {
+		firstContainer, containers = containers[0], containers[1:]
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%d\t%d\n",
+		deployment.Name,
+		firstContainer.Name,
+		firstContainer.Image,
+		formatLabels(deployment.Spec.Selector),
+		deployment.Spec.Replicas,
+		deployment.Status.UpdatedReplicas,
+		deployment.Status.AvailableReplicas)
+	if err != nil {
+		return err
+	}
+	// Lay out all the other containers on separate lines.
+	for _, container := range containers {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", "", container.Name, container.Image, "", "", "", "")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printDeploymentList(list *api.DeploymentList, w io.Writer) error {
+	for _, deployment := range list.Items {
+		if err := printDeployment(&deployment, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printHorizontalPodAutoscaler(autoscaler *api.HorizontalPodAutoscaler, w io.Writer) error {
+	current := "<waiting>"
+	if autoscaler.Status.CurrentCPUUtilizationPercentage != nil {
+		current = fmt.Sprintf("%d%%", *autoscaler.Status.CurrentCPUUtilizationPercentage)
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%d%%\t%s\t%d\t%d\t%d\n",
+		autoscaler.Name,
+		"ReplicationController/"+autoscaler.Spec.ScaleRef.Name,
+		autoscaler.Spec.TargetCPUUtilizationPercentage,
+		current,
+		autoscaler.Spec.MinReplicas,
+		autoscaler.Spec.MaxReplicas,
+		autoscaler.Status.CurrentReplicas)
+	return err
+}
+
+func printHorizontalPodAutoscalerList(list *api.HorizontalPodAutoscalerList, w io.Writer) error {
+	for _, autoscaler := range list.Items {
+		if err := printHorizontalPodAutoscaler(&autoscaler, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func printService(svc *api.Service, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\n", svc.Name, formatLabels(svc.Labels),
-		formatLabels(svc.Spec.Selector), svc.Spec.PortalIP, svc.Spec.Port)
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", svc.Name, formatLabels(svc.Labels),
+		formatLabels(svc.Spec.Selector), svc.Spec.PortalIP, formatServicePorts(svc.Spec.Ports))
 	return err
 }
 
+func formatServicePorts(ports []api.ServicePort) string {
+	list := []string{}
+	for i := range ports {
+		list = append(list, fmt.Sprintf("%d/%s", ports[i].Port, ports[i].Protocol))
+	}
+	return strings.Join(list, ",")
+}
+
 func printServiceList(list *api.ServiceList, w io.Writer) error {
 	for _, svc := range list.Items {
 		if err := printService(&svc, w); err != nil {
@@ -405,7 +578,7 @@ func printServiceList(list *api.ServiceList, w io.Writer) error {
 }
 
 func printEndpoints(endpoints *api.Endpoints, w io.Writer) error {
-	_, err := fmt.Fprintf(w, "%s\t%s\n", endpoints.Name, formatEndpoints(endpoints))
+	_, err := fmt.Fprintf(w, "%s\t%s\n", endpoints.Name, formatEndpoints(endpoints, nil))
 	return err
 }
 
@@ -447,6 +620,58 @@ func printSecretList(list *api.SecretList, w io.Writer) error {
 	return nil
 }
 
+func printPersistentVolume(pv *api.PersistentVolume, w io.Writer) error {
+	claimRef := ""
+	if pv.Spec.ClaimRef != nil {
+		claimRef = fmt.Sprintf("%s/%s", pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+	}
+	capacity := pv.Spec.Capacity[api.ResourceStorage]
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", pv.Name, formatLabels(pv.Labels), capacity.String(),
+		pv.Status.Phase, claimRef)
+	return err
+}
+
+func printPersistentVolumeList(list *api.PersistentVolumeList, w io.Writer) error {
+	for _, pv := range list.Items {
+		if err := printPersistentVolume(&pv, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printPersistentVolumeClaim(pvc *api.PersistentVolumeClaim, w io.Writer) error {
+	volume := ""
+	if pvc.Status.VolumeRef != nil {
+		volume = pvc.Status.VolumeRef.Name
+	}
+	_, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", pvc.Name, formatLabels(pvc.Labels), pvc.Status.Phase, volume)
+	return err
+}
+
+func printPersistentVolumeClaimList(list *api.PersistentVolumeClaimList, w io.Writer) error {
+	for _, pvc := range list.Items {
+		if err := printPersistentVolumeClaim(&pvc, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printServiceAccount(item *api.ServiceAccount, w io.Writer) error {
+	_, err := fmt.Fprintf(w, "%s\t%d\n", item.Name, len(item.Secrets))
+	return err
+}
+
+func printServiceAccountList(list *api.ServiceAccountList, w io.Writer) error {
+	for _, item := range list.Items {
+		if err := printServiceAccount(&item, w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func printNode(node *api.Node, w io.Writer) error {
 	conditionMap := make(map[api.NodeConditionType]*api.NodeCondition)
 	NodeAllConditions := []api.NodeConditionType{api.NodeSchedulable, api.NodeReady, api.NodeReachable}
@@ -65,16 +65,9 @@ func (ServiceGenerator) Generate(params map[string]string) (runtime.Object, erro
 	if err != nil {
 		return nil, err
 	}
-	service := api.Service{
-		ObjectMeta: api.ObjectMeta{
-			Name:   name,
-			Labels: labels,
-		},
-		Spec: api.ServiceSpec{
-			Port:     port,
-			Protocol: api.Protocol(params["protocol"]),
-			Selector: selector,
-		},
+	servicePort := api.ServicePort{
+		Port:     port,
+		Protocol: api.Protocol(params["protocol"]),
 	}
 	targetPort, found := params["target-port"]
 	if !found {
@@ -82,12 +75,22 @@ func (ServiceGenerator) Generate(params map[string]string) (runtime.Object, erro
 	}
 	if found && len(targetPort) > 0 {
 		if portNum, err := strconv.Atoi(targetPort); err != nil {
-			service.Spec.TargetPort = util.NewIntOrStringFromString(targetPort)
+			servicePort.TargetPort = util.NewIntOrStringFromString(targetPort)
 		} else {
-			service.Spec.TargetPort = util.NewIntOrStringFromInt(portNum)
+			servicePort.TargetPort = util.NewIntOrStringFromInt(portNum)
 		}
 	} else {
-		service.Spec.TargetPort = util.NewIntOrStringFromInt(port)
+		servicePort.TargetPort = util.NewIntOrStringFromInt(port)
+	}
+	service := api.Service{
+		ObjectMeta: api.ObjectMeta{
+			Name:   name,
+			Labels: labels,
+		},
+		Spec: api.ServiceSpec{
+			Selector: selector,
+			Ports:    []api.ServicePort{servicePort},
+		},
 	}
 	if params["create-external-load-balancer"] == "true" {
 		service.Spec.CreateExternalLoadBalancer = true
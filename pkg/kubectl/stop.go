@@ -94,7 +94,7 @@ func (reaper *PodReaper) Stop(namespace, name string) (string, error) {
 	if err != nil {
 		return "", err
 	}
-	if err := pods.Delete(name); err != nil {
+	if err := pods.Delete(name, nil); err != nil {
 		return "", err
 	}
 	return fmt.Sprintf("%s stopped", name), nil
@@ -25,13 +25,23 @@ type ReadinessManager struct {
 	// guards states
 	sync.RWMutex
 	// TODO(yifan): To use strong type.
-	states map[string]bool
+	states map[string]readinessState
+}
+
+// readinessState is the readiness of a container along with the number of
+// consecutive probe results that were recorded for it.
+type readinessState struct {
+	ready bool
+	// successes and failures count the consecutive successful and failed
+	// probes; at most one of them is non-zero.
+	successes int
+	failures  int
 }
 
 // NewReadinessManager creates ane returns a readiness manager with empty
 // contents.
 func NewReadinessManager() *ReadinessManager {
-	return &ReadinessManager{states: make(map[string]bool)}
+	return &ReadinessManager{states: make(map[string]readinessState)}
 }
 
 // GetReadiness returns the readiness value for the container with the given ID.
@@ -41,14 +51,41 @@ func (r *ReadinessManager) GetReadiness(id string) bool {
 	r.RLock()
 	defer r.RUnlock()
 	state, found := r.states[id]
-	return state && found
+	return state.ready && found
 }
 
-// SetReadiness sets the readiness value for the container with the given ID.
+// SetReadiness sets the readiness value for the container with the given ID,
+// discarding any probe results recorded for it.
 func (r *ReadinessManager) SetReadiness(id string, value bool) {
 	r.Lock()
 	defer r.Unlock()
-	r.states[id] = value
+	r.states[id] = readinessState{ready: value}
+}
+
+// RecordProbeResult records the result of a readiness probe of the container
+// with the given ID and returns its resulting readiness. An unready container
+// becomes ready after successThreshold consecutive successes and a ready one
+// becomes unready after failureThreshold consecutive failures. Thresholds
+// below 1 are treated as 1.
+func (r *ReadinessManager) RecordProbeResult(id string, success bool, successThreshold, failureThreshold int) bool {
+	r.Lock()
+	defer r.Unlock()
+	state := r.states[id]
+	if success {
+		state.failures = 0
+		state.successes++
+		if state.successes >= successThreshold {
+			state.ready = true
+		}
+	} else {
+		state.successes = 0
+		state.failures++
+		if state.failures >= failureThreshold {
+			state.ready = false
+		}
+	}
+	r.states[id] = state
+	return state.ready
 }
 
 // RemoveReadiness clears the readiness value for the container with the given ID.
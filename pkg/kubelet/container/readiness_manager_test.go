@@ -0,0 +1,74 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package container
+
+import "testing"
+
+func TestRecordProbeResultThresholds(t *testing.T) {
+	r := NewReadinessManager()
+	id := "container"
+	steps := []struct {
+		success  bool
+		expected bool
+	}{
+		// Two consecutive successes are needed to become ready.
+		{true, false},
+		{false, false},
+		{true, false},
+		{true, true},
+		// Three consecutive failures are needed to become unready.
+		{false, true},
+		{false, true},
+		{true, true},
+		{false, true},
+		{false, true},
+		{false, false},
+	}
+	for i, step := range steps {
+		if ready := r.RecordProbeResult(id, step.success, 2, 3); ready != step.expected {
+			t.Errorf("step %d: expected readiness %v, got %v", i, step.expected, ready)
+		}
+		if ready := r.GetReadiness(id); ready != step.expected {
+			t.Errorf("step %d: expected stored readiness %v, got %v", i, step.expected, ready)
+		}
+	}
+}
+
+func TestRecordProbeResultDefaultThresholds(t *testing.T) {
+	r := NewReadinessManager()
+	id := "container"
+	if !r.RecordProbeResult(id, true, 0, 0) {
+		t.Errorf("expected a single success to make the container ready")
+	}
+	if r.RecordProbeResult(id, false, 0, 0) {
+		t.Errorf("expected a single failure to make the container unready")
+	}
+}
+
+func TestSetReadinessResetsProbeResults(t *testing.T) {
+	r := NewReadinessManager()
+	id := "container"
+	r.RecordProbeResult(id, true, 2, 1)
+	r.SetReadiness(id, false)
+	if r.RecordProbeResult(id, true, 2, 1) {
+		t.Errorf("expected recorded successes to be discarded by SetReadiness")
+	}
+	r.RemoveReadiness(id)
+	if r.GetReadiness(id) {
+		t.Errorf("expected no readiness after removal")
+	}
+}
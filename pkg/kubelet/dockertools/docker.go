@@ -85,7 +85,7 @@ type KubeletContainerName struct {
 
 // DockerPuller is an abstract interface for testability.  It abstracts image pull operations.
 type DockerPuller interface {
-	Pull(image string) error
+	Pull(image string, secrets []api.Secret) error
 	IsImagePresent(image string) (bool, error)
 }
 
@@ -346,7 +346,7 @@ func parseImageName(image string) (string, string) {
 	return parsers.ParseRepositoryTag(image)
 }
 
-func (p dockerPuller) Pull(image string) error {
+func (p dockerPuller) Pull(image string, secrets []api.Secret) error {
 	repoToPull, tag := parseImageName(image)
 
 	// If no tag was specified, use the default "latest".
@@ -359,12 +359,17 @@ func (p dockerPuller) Pull(image string) error {
 		Tag:        tag,
 	}
 
-	creds, ok := p.keyring.Lookup(repoToPull)
+	keyring, err := credentialprovider.MakeDockerKeyring(secrets, p.keyring)
+	if err != nil {
+		return err
+	}
+
+	creds, ok := keyring.Lookup(repoToPull)
 	if !ok {
 		glog.V(1).Infof("Pulling image %s without credentials", image)
 	}
 
-	err := p.client.PullImage(opts, creds)
+	err = p.client.PullImage(opts, creds)
 	// If there was no error, or we had credentials, just return the error.
 	if err == nil || ok {
 		return err
@@ -380,9 +385,9 @@ func (p dockerPuller) Pull(image string) error {
 	return err
 }
 
-func (p throttledDockerPuller) Pull(image string) error {
+func (p throttledDockerPuller) Pull(image string, secrets []api.Secret) error {
 	if p.limiter.CanAccept() {
-		return p.puller.Pull(image)
+		return p.puller.Pull(image, secrets)
 	}
 	return fmt.Errorf("pull QPS exceeded.")
 }
@@ -885,10 +890,12 @@ func GetPods(client DockerInterface, all bool) ([]*kubecontainer.Pod, error) {
 	return result, nil
 }
 
+// milliCPUToShares converts the CPU a container requests to CPU shares. A
+// container which requests no CPU gets the minimum shares, so it only uses
+// the CPU the containers which requested it leave idle.
 func milliCPUToShares(milliCPU int64) int64 {
 	if milliCPU == 0 {
-		// zero milliCPU means unset. Use kernel default.
-		return 0
+		return minShares
 	}
 	// Conceptually (milliCPU / milliCPUToCPU) * sharesPerCPU, but factored to improve rounding.
 	shares := (milliCPU * sharesPerCPU) / milliCPUToCPU
@@ -946,6 +953,25 @@ func makeCapabilites(capAdd []api.CapabilityType, capDrop []api.CapabilityType)
 	return addCaps, dropCaps
 }
 
+// makeSecurityOpts returns the docker security options that apply the given SELinux labels.
+func makeSecurityOpts(opts *api.SELinuxOptions) []string {
+	if opts == nil {
+		return nil
+	}
+	var securityOpts []string
+	for _, label := range []struct{ name, value string }{
+		{"user", opts.User},
+		{"role", opts.Role},
+		{"type", opts.Type},
+		{"level", opts.Level},
+	} {
+		if len(label.value) > 0 {
+			securityOpts = append(securityOpts, fmt.Sprintf("label:%s:%s", label.name, label.value))
+		}
+	}
+	return securityOpts
+}
+
 // RunContainer creates and starts a docker container with the required RunContainerOptions.
 // On success it will return the container's ID with nil error. During the process, it will
 // use the reference and event recorder to report the state of the container (e.g. created,
@@ -966,6 +992,9 @@ func RunContainer(client DockerInterface, container *api.Container, pod *api.Pod
 	if len(containerHostname) > hostnameMaxLen {
 		containerHostname = containerHostname[:hostnameMaxLen]
 	}
+	// The CPU shares guarantee the container the CPU it requests, while the
+	// memory limit stops it from using more memory than its limit.
+	requests := container.Resources.EffectiveRequests()
 	dockerOpts := docker.CreateContainerOptions{
 		Name: BuildDockerName(dockerName, container),
 		Config: &docker.Config{
@@ -975,10 +1004,13 @@ func RunContainer(client DockerInterface, container *api.Container, pod *api.Pod
 			Hostname:     containerHostname,
 			Image:        container.Image,
 			Memory:       container.Resources.Limits.Memory().Value(),
-			CPUShares:    milliCPUToShares(container.Resources.Limits.Cpu().MilliValue()),
+			CPUShares:    milliCPUToShares(requests.Cpu().MilliValue()),
 			WorkingDir:   container.WorkingDir,
 		},
 	}
+	if container.SecurityContext != nil && container.SecurityContext.RunAsUser != nil {
+		dockerOpts.Config.User = strconv.FormatInt(*container.SecurityContext.RunAsUser, 10)
+	}
 	dockerContainer, err := client.CreateContainer(dockerOpts)
 	if err != nil {
 		if ref != nil {
@@ -1033,6 +1065,10 @@ func RunContainer(client DockerInterface, container *api.Container, pod *api.Pod
 	if len(opts.DNSSearch) > 0 {
 		hc.DNSSearch = opts.DNSSearch
 	}
+	if container.SecurityContext != nil {
+		hc.SecurityOpt = makeSecurityOpts(container.SecurityContext.SELinuxOptions)
+		hc.ReadonlyRootfs = container.SecurityContext.ReadOnlyRootFilesystem
+	}
 
 	if err = client.StartContainer(dockerContainer.ID, hc); err != nil {
 		if ref != nil {
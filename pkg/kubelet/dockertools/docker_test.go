@@ -22,7 +22,9 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/credentialprovider"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/cnaize/kubernetes/pkg/api"
 	"github.com/cnaize/kubernetes/pkg/types"
@@ -222,7 +224,7 @@ func TestPull(t *testing.T) {
 			keyring: fakeKeyring,
 		}
 
-		err := dp.Pull(test.imageName)
+		err := dp.Pull(test.imageName, nil)
 		if err != nil {
 			t.Errorf("unexpected non-nil err: %s", err)
 			continue
@@ -239,6 +241,26 @@ func TestPull(t *testing.T) {
 	}
 }
 
+func TestPullWithInvalidSecret(t *testing.T) {
+	fakeClient := &FakeDockerClient{}
+	dp := dockerPuller{
+		client:  fakeClient,
+		keyring: &credentialprovider.FakeKeyring{},
+	}
+
+	secrets := []api.Secret{{
+		ObjectMeta: api.ObjectMeta{Name: "bad-creds"},
+		Type:       api.SecretTypeDockercfg,
+		Data:       map[string][]byte{api.DockerConfigKey: []byte("not json")},
+	}}
+	if err := dp.Pull("registry.example.com/foo/bar", secrets); err == nil {
+		t.Errorf("expected an error for an unparseable dockercfg secret")
+	}
+	if len(fakeClient.pulled) != 0 {
+		t.Errorf("expected no pull attempt, got %v", fakeClient.pulled)
+	}
+}
+
 func TestDockerKeyringLookupFails(t *testing.T) {
 	fakeKeyring := &credentialprovider.FakeKeyring{}
 	fakeClient := &FakeDockerClient{
@@ -250,7 +272,7 @@ func TestDockerKeyringLookupFails(t *testing.T) {
 		keyring: fakeKeyring,
 	}
 
-	err := dp.Pull("host/repository/image:version")
+	err := dp.Pull("host/repository/image:version", nil)
 	if err == nil {
 		t.Errorf("unexpected non-error")
 	}
@@ -701,3 +723,63 @@ func TestMakePortsAndBindings(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeSecurityOpts(t *testing.T) {
+	tests := []struct {
+		opts     *api.SELinuxOptions
+		expected []string
+	}{
+		{nil, nil},
+		{&api.SELinuxOptions{}, nil},
+		{&api.SELinuxOptions{Type: "svirt_lxc_net_t", Level: "s0:c1,c2"}, []string{"label:type:svirt_lxc_net_t", "label:level:s0:c1,c2"}},
+		{&api.SELinuxOptions{User: "user", Role: "role", Type: "type", Level: "level"}, []string{"label:user:user", "label:role:role", "label:type:type", "label:level:level"}},
+	}
+	for i, test := range tests {
+		if actual := makeSecurityOpts(test.opts); !reflect.DeepEqual(test.expected, actual) {
+			t.Errorf("%d: expected %v, got %v", i, test.expected, actual)
+		}
+	}
+}
+
+func TestRunContainerAppliesSecurityContext(t *testing.T) {
+	fakeDocker := &FakeDockerClient{VersionInfo: docker.Env{"Version=1.1.3", "ApiVersion=1.15"}}
+	runAsUser := int64(1000)
+	container := &api.Container{
+		Name:  "foo",
+		Image: "image",
+		SecurityContext: &api.SecurityContext{
+			RunAsUser:              &runAsUser,
+			SELinuxOptions:         &api.SELinuxOptions{Level: "s0:c1,c2"},
+			ReadOnlyRootFilesystem: true,
+		},
+	}
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "bar", Namespace: "new", UID: "12345678"}}
+	if _, err := RunContainer(fakeDocker, container, pod, &kubecontainer.RunContainerOptions{}, kubecontainer.NewRefManager(), nil, &record.FakeRecorder{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	hc := fakeDocker.Container.HostConfig
+	if !reflect.DeepEqual(hc.SecurityOpt, []string{"label:level:s0:c1,c2"}) {
+		t.Errorf("unexpected security options: %v", hc.SecurityOpt)
+	}
+	if !hc.ReadonlyRootfs {
+		t.Errorf("expected the root filesystem to be read-only")
+	}
+}
+
+func TestMilliCPUToShares(t *testing.T) {
+	tests := []struct {
+		milliCPU int64
+		shares   int64
+	}{
+		{milliCPU: 0, shares: minShares},
+		{milliCPU: 1, shares: minShares},
+		{milliCPU: 250, shares: 256},
+		{milliCPU: 1000, shares: sharesPerCPU},
+		{milliCPU: 2500, shares: 2560},
+	}
+	for _, test := range tests {
+		if shares := milliCPUToShares(test.milliCPU); shares != test.shares {
+			t.Errorf("expected %d shares for %dm CPU, got %d", test.shares, test.milliCPU, shares)
+		}
+	}
+}
@@ -30,45 +30,61 @@ func TestFromServices(t *testing.T) {
 			{
 				ObjectMeta: api.ObjectMeta{Name: "foo-bar"},
 				Spec: api.ServiceSpec{
-					Port:     8080,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports: []api.ServicePort{
+						{Port: 8080, Protocol: "TCP"},
+					},
 					PortalIP: "1.2.3.4",
 				},
 			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "abc-123"},
 				Spec: api.ServiceSpec{
-					Port:     8081,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "UDP",
+					Ports: []api.ServicePort{
+						{Port: 8081, Protocol: "UDP"},
+					},
 					PortalIP: "5.6.7.8",
 				},
 			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "q-u-u-x"},
 				Spec: api.ServiceSpec{
-					Port:     8082,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports: []api.ServicePort{
+						{Port: 8082, Protocol: "TCP"},
+					},
 					PortalIP: "9.8.7.6",
 				},
 			},
+			{
+				ObjectMeta: api.ObjectMeta{Name: "multi-port-svc"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{"bar": "baz"},
+					Ports: []api.ServicePort{
+						{Name: "http", Port: 80, Protocol: "TCP"},
+						{Name: "dns-udp", Port: 53, Protocol: "UDP"},
+					},
+					PortalIP: "9.8.7.5",
+				},
+			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "svrc-portalip-none"},
 				Spec: api.ServiceSpec{
-					Port:     8082,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports: []api.ServicePort{
+						{Port: 8082, Protocol: "TCP"},
+					},
 					PortalIP: "None",
 				},
 			},
 			{
 				ObjectMeta: api.ObjectMeta{Name: "svrc-portalip-empty"},
 				Spec: api.ServiceSpec{
-					Port:     8082,
 					Selector: map[string]string{"bar": "baz"},
-					Protocol: "TCP",
+					Ports: []api.ServicePort{
+						{Port: 8082, Protocol: "TCP"},
+					},
 					PortalIP: "",
 				},
 			},
@@ -97,6 +113,19 @@ func TestFromServices(t *testing.T) {
 		{Name: "Q_U_U_X_PORT_8082_TCP_PROTO", Value: "tcp"},
 		{Name: "Q_U_U_X_PORT_8082_TCP_PORT", Value: "8082"},
 		{Name: "Q_U_U_X_PORT_8082_TCP_ADDR", Value: "9.8.7.6"},
+		{Name: "MULTI_PORT_SVC_SERVICE_HOST", Value: "9.8.7.5"},
+		{Name: "MULTI_PORT_SVC_SERVICE_PORT", Value: "80"},
+		{Name: "MULTI_PORT_SVC_SERVICE_PORT_HTTP", Value: "80"},
+		{Name: "MULTI_PORT_SVC_SERVICE_PORT_DNS_UDP", Value: "53"},
+		{Name: "MULTI_PORT_SVC_PORT", Value: "tcp://9.8.7.5:80"},
+		{Name: "MULTI_PORT_SVC_PORT_80_TCP", Value: "tcp://9.8.7.5:80"},
+		{Name: "MULTI_PORT_SVC_PORT_80_TCP_PROTO", Value: "tcp"},
+		{Name: "MULTI_PORT_SVC_PORT_80_TCP_PORT", Value: "80"},
+		{Name: "MULTI_PORT_SVC_PORT_80_TCP_ADDR", Value: "9.8.7.5"},
+		{Name: "MULTI_PORT_SVC_PORT_53_UDP", Value: "udp://9.8.7.5:53"},
+		{Name: "MULTI_PORT_SVC_PORT_53_UDP_PROTO", Value: "udp"},
+		{Name: "MULTI_PORT_SVC_PORT_53_UDP_PORT", Value: "53"},
+		{Name: "MULTI_PORT_SVC_PORT_53_UDP_ADDR", Value: "9.8.7.5"},
 	}
 	if len(vars) != len(expected) {
 		t.Errorf("Expected %d env vars, got: %+v", len(expected), vars)
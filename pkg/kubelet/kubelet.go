@@ -30,6 +30,7 @@ import (
 	"sync"
 	"time"
 
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/capabilities"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
@@ -42,6 +43,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/envvars"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/metrics"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/network"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/qos"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/probe"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
@@ -59,9 +61,11 @@ import (
 )
 
 const (
-	// The oom_score_adj of the POD infrastructure container. The default is 0, so
-	// any value below that makes it *less* likely to get OOM killed.
-	podOomScoreAdj = -100
+	// Number of seconds docker waits for a container to exit after SIGTERM before sending SIGKILL.
+	defaultStopTimeoutInSeconds = 10
+
+	// The shortest time a container is given to stop when its pod is deleted gracefully.
+	minimumGracePeriodInSeconds = 2
 
 	// Max amount of time to wait for the Docker daemon to come up.
 	maxWaitForDocker = 5 * time.Minute
@@ -326,6 +330,8 @@ type Kubelet struct {
 
 	// Probe runner holder
 	prober probeHolder
+	// Recent probe runs of containers, used to honor probe periods and thresholds.
+	probeHistory probeHistory
 	// Container readiness state manager.
 	readinessManager *kubecontainer.ReadinessManager
 
@@ -603,7 +609,7 @@ func (kl *Kubelet) runHandler(podFullName string, uid types.UID, container *api.
 
 // generateRunContainerOptions generates the RunContainerOptions, which can be used by
 // the container runtime to set parameters for launching a container.
-func (kl *Kubelet) generateRunContainerOptions(pod *api.Pod, container *api.Container, podVolumes volumeMap, netMode, ipcMode string) (*kubecontainer.RunContainerOptions, error) {
+func (kl *Kubelet) generateRunContainerOptions(pod *api.Pod, container *api.Container, podVolumes volumeMap, netMode, ipcMode, podIP string) (*kubecontainer.RunContainerOptions, error) {
 	var err error
 	opts := &kubecontainer.RunContainerOptions{
 		NetMode: netMode,
@@ -611,7 +617,7 @@ func (kl *Kubelet) generateRunContainerOptions(pod *api.Pod, container *api.Cont
 	}
 
 	opts.Binds = makeBinds(container, podVolumes)
-	opts.Envs, err = kl.makeEnvironmentVariables(pod.Namespace, container)
+	opts.Envs, err = kl.makeEnvironmentVariables(pod, container, podIP)
 	if err != nil {
 		return nil, err
 	}
@@ -634,14 +640,19 @@ func (kl *Kubelet) generateRunContainerOptions(pod *api.Pod, container *api.Cont
 }
 
 // Run a single container from a pod. Returns the docker container ID
-func (kl *Kubelet) runContainer(pod *api.Pod, container *api.Container, podVolumes volumeMap, netMode, ipcMode string) (dockertools.DockerID, error) {
+// podIP is the IP of the pod's infra container, exposed to the container through
+// the downward API; it may be empty if the container does not reference it.
+func (kl *Kubelet) runContainer(pod *api.Pod, container *api.Container, podVolumes volumeMap, netMode, ipcMode, podIP string) (dockertools.DockerID, error) {
 	ref, err := kl.containerRefManager.GenerateContainerRef(pod, container)
 	if err != nil {
 		glog.Errorf("Couldn't make a ref to pod %v, container %v: '%v'", pod.Name, container.Name, err)
 	}
 
-	opts, err := kl.generateRunContainerOptions(pod, container, podVolumes, netMode, ipcMode)
+	opts, err := kl.generateRunContainerOptions(pod, container, podVolumes, netMode, ipcMode, podIP)
 	if err != nil {
+		if ref != nil {
+			kl.recorder.Eventf(ref, "failed", "Failed to generate run options for container %q: %v", container.Name, err)
+		}
 		return "", err
 	}
 
@@ -717,8 +728,9 @@ func (kl *Kubelet) getServiceEnvVarMap(ns string) (map[string]string, error) {
 	return m, nil
 }
 
-// Make the service environment variables for a pod in the given namespace.
-func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container) ([]string, error) {
+// Make the environment variables for a container of the given pod, including the
+// service environment variables for the pod's namespace.
+func (kl *Kubelet) makeEnvironmentVariables(pod *api.Pod, container *api.Container, podIP string) ([]string, error) {
 	var result []string
 	// Note:  These are added to the docker.Config, but are not included in the checksum computed
 	// by dockertools.BuildDockerName(...).  That way, we can still determine whether an
@@ -729,11 +741,14 @@ func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container)
 	// To avoid this users can: (1) wait between starting a service and starting; or (2) detect
 	// missing service env var and exit and be restarted; or (3) use DNS instead of env vars
 	// and keep trying to resolve the DNS name of the service (recommended).
-	serviceEnv, err := kl.getServiceEnvVarMap(ns)
+	serviceEnv, err := kl.getServiceEnvVarMap(pod.Namespace)
 	if err != nil {
 		return result, err
 	}
 
+	// Secrets referenced by more than one variable are only fetched once.
+	secrets := make(map[string]*api.Secret)
+
 	for _, value := range container.Env {
 		// Accesses apiserver+Pods.
 		// So, the master may set service env vars, or kubelet may.  In case both are doing
@@ -741,7 +756,19 @@ func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container)
 		// env vars.
 		// TODO: remove this net line once all platforms use apiserver+Pods.
 		delete(serviceEnv, value.Name)
-		result = append(result, fmt.Sprintf("%s=%s", value.Name, value.Value))
+		runtimeValue := value.Value
+		if value.ValueFrom != nil {
+			switch {
+			case value.ValueFrom.FieldRef != nil:
+				runtimeValue, err = podFieldSelectorRuntimeValue(value.ValueFrom.FieldRef, pod, podIP)
+			case value.ValueFrom.SecretKeyRef != nil:
+				runtimeValue, err = kl.secretKeySelectorRuntimeValue(value.ValueFrom.SecretKeyRef, pod.Namespace, secrets)
+			}
+			if err != nil {
+				return result, err
+			}
+		}
+		result = append(result, fmt.Sprintf("%s=%s", value.Name, runtimeValue))
 	}
 
 	// Append remaining service env vars.
@@ -751,6 +778,67 @@ func (kl *Kubelet) makeEnvironmentVariables(ns string, container *api.Container)
 	return result, nil
 }
 
+// podFieldSelectorRuntimeValue returns the runtime value of the given
+// selector for a pod.
+func podFieldSelectorRuntimeValue(fs *api.ObjectFieldSelector, pod *api.Pod, podIP string) (string, error) {
+	switch fs.FieldPath {
+	case "metadata.name":
+		return pod.Name, nil
+	case "metadata.namespace":
+		return pod.Namespace, nil
+	case "status.podIP":
+		return podIP, nil
+	}
+	return "", fmt.Errorf("unsupported field path %q", fs.FieldPath)
+}
+
+// secretKeySelectorRuntimeValue returns the value of the selected key of a secret
+// in namespace ns. Secrets are fetched through the API client unless they are
+// already present in secrets, which is updated with any newly fetched secret.
+func (kl *Kubelet) secretKeySelectorRuntimeValue(s *api.SecretKeySelector, ns string, secrets map[string]*api.Secret) (string, error) {
+	secret, ok := secrets[s.Name]
+	if !ok {
+		if kl.kubeClient == nil {
+			return "", fmt.Errorf("cannot get secret %s/%s because kube client is not configured", ns, s.Name)
+		}
+		var err error
+		secret, err = kl.kubeClient.Secrets(ns).Get(s.Name)
+		if err != nil {
+			return "", fmt.Errorf("couldn't get secret %s/%s: %v", ns, s.Name, err)
+		}
+		secrets[s.Name] = secret
+	}
+	value, ok := secret.Data[s.Key]
+	if !ok {
+		return "", fmt.Errorf("couldn't find key %q in secret %s/%s", s.Key, ns, s.Name)
+	}
+	return string(value), nil
+}
+
+// referencesPodIP returns true if any of the container's environment
+// variables is resolved from the pod IP.
+func referencesPodIP(container *api.Container) bool {
+	for _, env := range container.Env {
+		if env.ValueFrom != nil && env.ValueFrom.FieldRef != nil && env.ValueFrom.FieldRef.FieldPath == "status.podIP" {
+			return true
+		}
+	}
+	return false
+}
+
+// getPodInfraContainerIP returns the IP assigned to the network namespace
+// held by the given pod infra container.
+func (kl *Kubelet) getPodInfraContainerIP(podInfraContainerID dockertools.DockerID) (string, error) {
+	inspectResult, err := kl.dockerClient.InspectContainer(string(podInfraContainerID))
+	if err != nil {
+		return "", err
+	}
+	if inspectResult == nil || inspectResult.NetworkSettings == nil {
+		return "", fmt.Errorf("no network settings for pod infra container %q", podInfraContainerID)
+	}
+	return inspectResult.NetworkSettings.IPAddress, nil
+}
+
 // getClusterDNS returns a list of the DNS servers and a list of the DNS search
 // domains of the cluster.
 func (kl *Kubelet) getClusterDNS(pod *api.Pod) ([]string, []string, error) {
@@ -817,9 +905,16 @@ func (kl *Kubelet) killContainer(c *kubecontainer.Container) error {
 }
 
 func (kl *Kubelet) killContainerByID(ID string) error {
+	return kl.stopContainerByID(ID, defaultStopTimeoutInSeconds)
+}
+
+// stopContainerByID stops a docker container, which sends it SIGTERM and then SIGKILL
+// if it is still running after timeout seconds.
+func (kl *Kubelet) stopContainerByID(ID string, timeout uint) error {
 	glog.V(2).Infof("Killing container with id %q", ID)
 	kl.readinessManager.RemoveReadiness(ID)
-	err := kl.dockerClient.StopContainer(ID, 10)
+	kl.probeHistory.remove(ID)
+	err := kl.dockerClient.StopContainer(ID, timeout)
 
 	ref, ok := kl.containerRefManager.GetRef(ID)
 	if !ok {
@@ -884,7 +979,7 @@ func (kl *Kubelet) createPodInfraContainer(pod *api.Pod) (dockertools.DockerID,
 		return "", err
 	}
 	if !ok {
-		if err := kl.pullImage(container.Image, ref); err != nil {
+		if err := kl.pullImage(pod, container.Image, ref); err != nil {
 			return "", err
 		}
 	}
@@ -892,7 +987,7 @@ func (kl *Kubelet) createPodInfraContainer(pod *api.Pod) (dockertools.DockerID,
 		kl.recorder.Eventf(ref, "pulled", "Successfully pulled image %q", container.Image)
 	}
 
-	id, err := kl.runContainer(pod, container, nil, netNamespace, "")
+	id, err := kl.runContainer(pod, container, nil, netNamespace, "", "")
 	if err != nil {
 		return "", err
 	}
@@ -900,25 +995,73 @@ func (kl *Kubelet) createPodInfraContainer(pod *api.Pod) (dockertools.DockerID,
 	// Set OOM score of POD container to lower than those of the other
 	// containers in the pod. This ensures that it is killed only as a last
 	// resort.
+	return id, kl.applyOomScoreAdj(id, qos.PodInfraOOMScoreAdj)
+}
+
+// applyOomScoreAdj sets the oom_score_adj of the init process of a running container.
+func (kl *Kubelet) applyOomScoreAdj(id dockertools.DockerID, oomScoreAdj int) error {
 	containerInfo, err := kl.dockerClient.InspectContainer(string(id))
 	if err != nil {
-		return "", err
+		return err
 	}
 
 	// Ensure the PID actually exists, else we'll move ourselves.
 	if containerInfo.State.Pid == 0 {
-		return "", fmt.Errorf("failed to get init PID for Docker pod infra container %q", string(id))
+		return fmt.Errorf("failed to get init PID for Docker container %q", string(id))
 	}
-	return id, util.ApplyOomScoreAdj(containerInfo.State.Pid, podOomScoreAdj)
+	return util.ApplyOomScoreAdj(containerInfo.State.Pid, oomScoreAdj)
 }
 
-func (kl *Kubelet) pullImage(img string, ref *api.ObjectReference) error {
+// containerOomScoreAdj returns the oom_score_adj of a container of the pod,
+// which depends on the QoS class of the pod.
+func (kl *Kubelet) containerOomScoreAdj(pod *api.Pod, container *api.Container) int {
+	memoryCapacity := int64(0)
+	// Only the score of the containers of burstable pods depends on the memory capacity.
+	if qos.GetPodClass(pod) == qos.Burstable {
+		if info, err := kl.GetCachedMachineInfo(); err != nil {
+			glog.Warningf("Failed to get the memory capacity of the node: %v", err)
+		} else {
+			memoryCapacity = info.MemoryCapacity
+		}
+	}
+	return qos.GetContainerOOMScoreAdjust(pod, container, memoryCapacity)
+}
+
+// getPullSecretsForPod fetches the dockercfg secrets referenced by the
+// imagePullSecrets of pod.
+func (kl *Kubelet) getPullSecretsForPod(pod *api.Pod) ([]api.Secret, error) {
+	pullSecrets := []api.Secret{}
+	for _, ref := range pod.Spec.ImagePullSecrets {
+		if kl.kubeClient == nil {
+			return nil, fmt.Errorf("cannot get secret %s/%s because kube client is not configured", pod.Namespace, ref.Name)
+		}
+		secret, err := kl.kubeClient.Secrets(pod.Namespace).Get(ref.Name)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get secret %s/%s: %v", pod.Namespace, ref.Name, err)
+		}
+		if secret.Type != api.SecretTypeDockercfg {
+			return nil, fmt.Errorf("secret %s/%s has type %q, image pull secrets must be of type %q", pod.Namespace, ref.Name, secret.Type, api.SecretTypeDockercfg)
+		}
+		pullSecrets = append(pullSecrets, *secret)
+	}
+	return pullSecrets, nil
+}
+
+func (kl *Kubelet) pullImage(pod *api.Pod, img string, ref *api.ObjectReference) error {
 	start := time.Now()
 	defer func() {
 		metrics.ImagePullLatency.Observe(metrics.SinceInMicroseconds(start))
 	}()
 
-	if err := kl.dockerPuller.Pull(img); err != nil {
+	pullSecrets, err := kl.getPullSecretsForPod(pod)
+	if err != nil {
+		if ref != nil {
+			kl.recorder.Eventf(ref, "failed", "Failed to pull image %q: %v", img, err)
+		}
+		return err
+	}
+
+	if err := kl.dockerPuller.Pull(img, pullSecrets); err != nil {
 		if ref != nil {
 			kl.recorder.Eventf(ref, "failed", "Failed to pull image %q: %v", img, err)
 		}
@@ -959,6 +1102,114 @@ func (kl *Kubelet) killPod(runningPod kubecontainer.Pod) error {
 	return nil
 }
 
+// killContainerWithGracePeriod runs the preStop hook of a container, if it has one, and
+// then stops it, giving the container whatever is left of gracePeriod to exit after
+// SIGTERM before it is sent SIGKILL.
+func (kl *Kubelet) killContainerWithGracePeriod(pod *api.Pod, container *api.Container, ID string, gracePeriod time.Duration) error {
+	start := time.Now()
+	if container != nil && container.Lifecycle != nil && container.Lifecycle.PreStop != nil {
+		podFullName := kubecontainer.GetPodFullName(pod)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer util.HandleCrash()
+			if err := kl.runHandler(podFullName, pod.UID, container, container.Lifecycle.PreStop); err != nil {
+				glog.Errorf("PreStop hook for container %q in pod %q failed: %v", container.Name, podFullName, err)
+			}
+		}()
+		select {
+		case <-done:
+		case <-time.After(gracePeriod):
+			glog.Warningf("PreStop hook for container %q in pod %q did not complete within %v", container.Name, podFullName, gracePeriod)
+		}
+	}
+	timeout := uint((gracePeriod - time.Since(start)) / time.Second)
+	if timeout < minimumGracePeriodInSeconds {
+		timeout = minimumGracePeriodInSeconds
+	}
+	return kl.stopContainerByID(ID, timeout)
+}
+
+// podTerminationGracePeriod returns how long the containers of a pod that is being deleted
+// have to stop. It is bounded by the time left before the apiserver removes the pod.
+func podTerminationGracePeriod(pod *api.Pod) time.Duration {
+	seconds := api.DefaultTerminationGracePeriodSeconds
+	if pod.Spec.TerminationGracePeriodSeconds != nil {
+		seconds = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	gracePeriod := time.Duration(seconds) * time.Second
+	if pod.DeletionTimestamp != nil {
+		if remaining := pod.DeletionTimestamp.Sub(time.Now()); remaining < gracePeriod {
+			gracePeriod = remaining
+		}
+	}
+	if gracePeriod < minimumGracePeriodInSeconds*time.Second {
+		gracePeriod = minimumGracePeriodInSeconds * time.Second
+	}
+	return gracePeriod
+}
+
+// killPodWithGracePeriod kills all running containers of a pod that is being deleted. Every
+// container gets the pod's termination grace period to shut down; the pod infra container is
+// stopped last so that networking stays up while the others finish in-flight work.
+func (kl *Kubelet) killPodWithGracePeriod(pod *api.Pod, runningPod kubecontainer.Pod) error {
+	gracePeriod := podTerminationGracePeriod(pod)
+	errs := make(chan error, len(runningPod.Containers))
+	wg := sync.WaitGroup{}
+	var podInfraContainer *kubecontainer.Container
+	for _, container := range runningPod.Containers {
+		if container.Name == dockertools.PodInfraContainerName {
+			podInfraContainer = container
+			continue
+		}
+		var spec *api.Container
+		for i := range pod.Spec.Containers {
+			if pod.Spec.Containers[i].Name == container.Name {
+				spec = &pod.Spec.Containers[i]
+				break
+			}
+		}
+		wg.Add(1)
+		go func(container *kubecontainer.Container, spec *api.Container) {
+			defer util.HandleCrash()
+			defer wg.Done()
+			if err := kl.killContainerWithGracePeriod(pod, spec, string(container.ID), gracePeriod); err != nil {
+				glog.Errorf("Failed to delete container: %v; Skipping pod %q", err, runningPod.ID)
+				errs <- err
+			}
+		}(container, spec)
+	}
+	wg.Wait()
+	if podInfraContainer != nil {
+		if err := kl.killContainer(podInfraContainer); err != nil {
+			glog.Errorf("Failed to delete container: %v; Skipping pod %q", err, runningPod.ID)
+			errs <- err
+		}
+	}
+	close(errs)
+	if len(errs) > 0 {
+		errList := []error{}
+		for err := range errs {
+			errList = append(errList, err)
+		}
+		return fmt.Errorf("failed to delete containers (%v)", errList)
+	}
+	return nil
+}
+
+// confirmPodDeletion tells the apiserver that the containers of a gracefully deleted pod
+// have stopped, so that the pod can be removed without waiting out its grace period.
+func (kl *Kubelet) confirmPodDeletion(pod *api.Pod) error {
+	if kl.kubeClient == nil {
+		return nil
+	}
+	err := kl.kubeClient.Pods(pod.Namespace).Delete(pod.Name, api.NewDeleteOptions(0))
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
 type empty struct{}
 
 // makePodDataDirs creates the dirs for the pod datas.
@@ -987,6 +1238,7 @@ func (kl *Kubelet) shouldContainerBeRestarted(container *api.Container, pod *api
 	// set dead containers to unready state
 	for _, c := range recentContainers {
 		kl.readinessManager.RemoveReadiness(c.ID)
+		kl.probeHistory.remove(c.ID)
 	}
 
 	if len(recentContainers) > 0 {
@@ -1037,19 +1289,31 @@ func (kl *Kubelet) pullImageAndRunContainer(pod *api.Pod, container *api.Contain
 		}
 		if container.ImagePullPolicy == api.PullAlways ||
 			(container.ImagePullPolicy == api.PullIfNotPresent && (!present)) {
-			if err := kl.pullImage(container.Image, ref); err != nil {
+			if err := kl.pullImage(pod, container.Image, ref); err != nil {
 				return "", err
 			}
 		}
 	}
+	var podIP string
+	if referencesPodIP(container) {
+		podIP, err = kl.getPodInfraContainerIP(podInfraContainerID)
+		if err != nil {
+			glog.Errorf("Failed to get the IP of pod %q: %v; skipping container %q", podFullName, err, container.Name)
+			return "", err
+		}
+	}
 	// TODO(dawnchen): Check RestartPolicy.DelaySeconds before restart a container
 	namespaceMode := fmt.Sprintf("container:%v", podInfraContainerID)
-	containerID, err := kl.runContainer(pod, container, *podVolumes, namespaceMode, namespaceMode)
+	containerID, err := kl.runContainer(pod, container, *podVolumes, namespaceMode, namespaceMode, podIP)
 	if err != nil {
 		// TODO(bburns) : Perhaps blacklist a container after N failures?
 		glog.Errorf("Error running pod %q container %q: %v", podFullName, container.Name, err)
 		return "", err
 	}
+	if err := kl.applyOomScoreAdj(containerID, kl.containerOomScoreAdj(pod, container)); err != nil {
+		glog.Errorf("Failed to set the OOM score of pod %q container %q: %v", podFullName, container.Name, err)
+		return "", err
+	}
 	return containerID, nil
 }
 
@@ -1199,6 +1463,16 @@ func (kl *Kubelet) syncPod(pod *api.Pod, mirrorPod *api.Pod, runningPod kubecont
 	podFullName := kubecontainer.GetPodFullName(pod)
 	uid := pod.UID
 
+	// Pods that are being deleted are stopped gracefully and then removed from the apiserver;
+	// their status is not updated since the pod is about to disappear.
+	if pod.DeletionTimestamp != nil {
+		glog.V(3).Infof("Terminating pod %q", podFullName)
+		if err := kl.killPodWithGracePeriod(pod, runningPod); err != nil {
+			return err
+		}
+		return kl.confirmPodDeletion(pod)
+	}
+
 	// Before returning, regenerate status and store it in the cache.
 	defer func() {
 		if isStaticPod(pod) && mirrorPod == nil {
@@ -1397,6 +1671,7 @@ func (kl *Kubelet) SyncPods(allPods []api.Pod, podSyncTypes map[types.UID]metric
 
 	// Filter out the rejected pod. They don't have running containers.
 	kl.handleNotFittingPods(allPods)
+	kl.handlePastActiveDeadlinePods(allPods)
 	var pods []api.Pod
 	for _, pod := range allPods {
 		status, ok := kl.statusManager.GetPodStatus(kubecontainer.GetPodFullName(&pod))
@@ -1590,6 +1865,44 @@ func (kl *Kubelet) handleNotFittingPods(pods []api.Pod) {
 	}
 }
 
+// pastActiveDeadline returns true if the pod has been active on the node for
+// longer than its ActiveDeadlineSeconds, measured from the pod's StartTime.
+func (kl *Kubelet) pastActiveDeadline(pod *api.Pod) bool {
+	if pod.Spec.ActiveDeadlineSeconds == nil {
+		return false
+	}
+	status, ok := kl.statusManager.GetPodStatus(kubecontainer.GetPodFullName(pod))
+	if !ok {
+		status = pod.Status
+	}
+	if status.StartTime.IsZero() {
+		return false
+	}
+	allowedDuration := time.Duration(*pod.Spec.ActiveDeadlineSeconds) * time.Second
+	return time.Since(status.StartTime.Time) >= allowedDuration
+}
+
+// handlePastActiveDeadlinePods marks the pods that have exceeded their active
+// deadline as failed. Their containers are then killed like those of any other
+// pod that is no longer wanted on the node.
+func (kl *Kubelet) handlePastActiveDeadlinePods(pods []api.Pod) {
+	for i := range pods {
+		pod := &pods[i]
+		status, ok := kl.statusManager.GetPodStatus(kubecontainer.GetPodFullName(pod))
+		if ok && (status.Phase == api.PodFailed || status.Phase == api.PodSucceeded) {
+			continue
+		}
+		if !kl.pastActiveDeadline(pod) {
+			continue
+		}
+		kl.recorder.Eventf(pod, "deadline", "Pod was active on the node longer than specified deadline")
+		kl.statusManager.SetPodStatus(pod, api.PodStatus{
+			Phase:   api.PodFailed,
+			Reason:  "DeadlineExceeded",
+			Message: "Pod was active on the node longer than specified deadline"})
+	}
+}
+
 // syncLoop is the main loop for processing changes. It watches for changes from
 // three channels (file, apiserver, and http) and creates a union of them. For
 // any new change seen, will run a sync against desired state and running state. If
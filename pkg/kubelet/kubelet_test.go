@@ -535,7 +535,7 @@ func TestSyncPodsWithTerminationLog(t *testing.T) {
 	}
 	waitGroup.Wait()
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 	parts := strings.Split(fakeDocker.Container.HostConfig.Binds[0], ":")
@@ -587,7 +587,7 @@ func TestSyncPodsCreatesNetAndContainer(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 
@@ -642,7 +642,7 @@ func TestSyncPodsCreatesNetAndContainerPullsImage(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "create", "start", "inspect_container", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 
@@ -694,7 +694,7 @@ func TestSyncPodsWithPodInfraCreatesContainer(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "inspect_container", "list", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "inspect_container", "list", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 	if len(fakeDocker.Created) != 1 ||
@@ -753,7 +753,7 @@ func TestSyncPodsWithPodInfraCreatesContainerCallsHandler(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyCalls(t, fakeDocker, []string{
-		"list", "list", "list", "inspect_container", "list", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "inspect_container", "list", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	fakeDocker.Lock()
 	if len(fakeDocker.Created) != 1 ||
@@ -824,7 +824,7 @@ func TestSyncPodsDeletesWithNoPodInfraContainer(t *testing.T) {
 	waitGroup.Wait()
 
 	verifyUnorderedCalls(t, fakeDocker, []string{
-		"list", "list", "list", "list", "inspect_container", "inspect_container", "list", "inspect_container", "inspect_container", "stop", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+		"list", "list", "list", "list", "inspect_container", "inspect_container", "list", "inspect_container", "inspect_container", "stop", "create", "start", "inspect_container", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	// A map iteration is used to delete containers, so must not depend on
 	// order here.
@@ -1008,7 +1008,7 @@ func TestSyncPodBadHash(t *testing.T) {
 	}
 
 	//verifyCalls(t, fakeDocker, []string{"list", "stop", "list", "create", "start", "stop", "create", "start", "inspect_container"})
-	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "create", "start", "inspect_container", "create", "start", "list", "inspect_container", "inspect_container"})
+	verifyCalls(t, fakeDocker, []string{"list", "stop", "stop", "create", "start", "inspect_container", "create", "start", "inspect_container", "list", "inspect_container", "inspect_container"})
 
 	// A map interation is used to delete containers, so must not depend on
 	// order here.
@@ -1062,7 +1062,7 @@ func TestSyncPodUnhealthy(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 
-	verifyCalls(t, fakeDocker, []string{"list", "stop", "create", "start", "list", "inspect_container"})
+	verifyCalls(t, fakeDocker, []string{"list", "stop", "create", "start", "inspect_container", "list", "inspect_container"})
 
 	// A map interation is used to delete containers, so must not depend on
 	// order here.
@@ -1786,97 +1786,97 @@ func TestMakeEnvironmentVariables(t *testing.T) {
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8081,
+				Ports:    []api.ServicePort{{Port: 8081}},
 				PortalIP: "1.2.3.1",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8082,
+				Ports:    []api.ServicePort{{Port: 8082}},
 				PortalIP: "1.2.3.2",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8082,
+				Ports:    []api.ServicePort{{Port: 8082}},
 				PortalIP: "None",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: api.NamespaceDefault},
 			Spec: api.ServiceSpec{
-				Port:     8082,
+				Ports:    []api.ServicePort{{Port: 8082}},
 				PortalIP: "",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test1"},
 			Spec: api.ServiceSpec{
-				Port:     8083,
+				Ports:    []api.ServicePort{{Port: 8083}},
 				PortalIP: "1.2.3.3",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port:     8084,
+				Ports:    []api.ServicePort{{Port: 8084}},
 				PortalIP: "1.2.3.4",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port:     8085,
+				Ports:    []api.ServicePort{{Port: 8085}},
 				PortalIP: "1.2.3.5",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port:     8085,
+				Ports:    []api.ServicePort{{Port: 8085}},
 				PortalIP: "None",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "test", Namespace: "test2"},
 			Spec: api.ServiceSpec{
-				Port: 8085,
+				Ports: []api.ServicePort{{Port: 8085}},
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8086,
+				Ports:    []api.ServicePort{{Port: 8086}},
 				PortalIP: "1.2.3.6",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "kubernetes-ro", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8087,
+				Ports:    []api.ServicePort{{Port: 8087}},
 				PortalIP: "1.2.3.7",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "not-special", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8088,
+				Ports:    []api.ServicePort{{Port: 8088}},
 				PortalIP: "1.2.3.8",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "not-special", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8088,
+				Ports:    []api.ServicePort{{Port: 8088}},
 				PortalIP: "None",
 			},
 		},
 		{
 			ObjectMeta: api.ObjectMeta{Name: "not-special", Namespace: "kubernetes"},
 			Spec: api.ServiceSpec{
-				Port:     8088,
+				Ports:    []api.ServicePort{{Port: 8088}},
 				PortalIP: "",
 			},
 		},
@@ -2069,7 +2069,8 @@ func TestMakeEnvironmentVariables(t *testing.T) {
 			kl.serviceLister = testServiceLister{services}
 		}
 
-		result, err := kl.makeEnvironmentVariables(tc.ns, tc.container)
+		pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "dapi-test-pod-name", Namespace: tc.ns}}
+		result, err := kl.makeEnvironmentVariables(pod, tc.container, "")
 		if err != nil {
 			t.Errorf("[%v] Unexpected error: %v", tc.name, err)
 		}
@@ -2085,6 +2086,199 @@ func TestMakeEnvironmentVariables(t *testing.T) {
 	}
 }
 
+func TestMakeEnvironmentVariablesDownwardAPI(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	kl.serviceLister = nil
+
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:      "dapi-test-pod-name",
+			Namespace: "downward-api",
+		},
+	}
+	container := &api.Container{
+		Env: []api.EnvVar{
+			{Name: "FOO", Value: "BAR"},
+			{
+				Name: "POD_NAME",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+						FieldPath:  "metadata.name",
+					},
+				},
+			},
+			{
+				Name: "POD_NAMESPACE",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+						FieldPath:  "metadata.namespace",
+					},
+				},
+			},
+			{
+				Name: "POD_IP",
+				ValueFrom: &api.EnvVarSource{
+					FieldRef: &api.ObjectFieldSelector{
+						APIVersion: "v1beta3",
+						FieldPath:  "status.podIP",
+					},
+				},
+			},
+		},
+	}
+	if !referencesPodIP(container) {
+		t.Errorf("expected container to reference the pod IP")
+	}
+
+	result, err := kl.makeEnvironmentVariables(pod, container, "1.2.3.4")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{
+		"FOO=BAR",
+		"POD_NAME=dapi-test-pod-name",
+		"POD_NAMESPACE=downward-api",
+		"POD_IP=1.2.3.4",
+	}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+
+	container.Env = append(container.Env, api.EnvVar{
+		Name: "POD_LABELS",
+		ValueFrom: &api.EnvVarSource{
+			FieldRef: &api.ObjectFieldSelector{
+				APIVersion: "v1beta3",
+				FieldPath:  "metadata.labels",
+			},
+		},
+	})
+	if _, err := kl.makeEnvironmentVariables(pod, container, "1.2.3.4"); err == nil {
+		t.Errorf("expected an error for an unsupported field path")
+	}
+}
+
+func TestMakeEnvironmentVariablesFromSecret(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	kl.serviceLister = nil
+	testKubelet.fakeKubeClient.Secret = api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "db-credentials", Namespace: "secret-env"},
+		Data: map[string][]byte{
+			"username": []byte("admin"),
+			"password": []byte("p4ssw0rd"),
+		},
+	}
+
+	pod := &api.Pod{ObjectMeta: api.ObjectMeta{Name: "db-client", Namespace: "secret-env"}}
+	container := &api.Container{
+		Env: []api.EnvVar{
+			{
+				Name: "DB_USER",
+				ValueFrom: &api.EnvVarSource{
+					SecretKeyRef: &api.SecretKeySelector{Name: "db-credentials", Key: "username"},
+				},
+			},
+			{
+				Name: "DB_PASSWORD",
+				ValueFrom: &api.EnvVarSource{
+					SecretKeyRef: &api.SecretKeySelector{Name: "db-credentials", Key: "password"},
+				},
+			},
+		},
+	}
+
+	result, err := kl.makeEnvironmentVariables(pod, container, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := []string{"DB_USER=admin", "DB_PASSWORD=p4ssw0rd"}
+	if !reflect.DeepEqual(expected, result) {
+		t.Errorf("expected %v, got %v", expected, result)
+	}
+	if actions := testKubelet.fakeKubeClient.Actions; len(actions) != 1 || actions[0].Action != "get-secret" {
+		t.Errorf("expected the secret to be fetched once, got %#v", actions)
+	}
+
+	container.Env[1].ValueFrom.SecretKeyRef.Key = "token"
+	if _, err := kl.makeEnvironmentVariables(pod, container, ""); err == nil {
+		t.Errorf("expected an error for a missing secret key")
+	}
+}
+
+func TestGetPullSecretsForPod(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	testKubelet.fakeKubeClient.Secret = api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "registry-creds", Namespace: "private"},
+		Type:       api.SecretTypeDockercfg,
+		Data: map[string][]byte{
+			api.DockerConfigKey: []byte(`{"registry.example.com": {"auth": "Zm9vOmJhcg==", "email": "foo@example.com"}}`),
+		},
+	}
+
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "private"},
+		Spec: api.PodSpec{
+			ImagePullSecrets: []api.LocalObjectReference{{Name: "registry-creds"}},
+		},
+	}
+	secrets, err := kl.getPullSecretsForPod(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(secrets) != 1 || secrets[0].Name != "registry-creds" {
+		t.Errorf("unexpected pull secrets: %#v", secrets)
+	}
+	if actions := testKubelet.fakeKubeClient.Actions; len(actions) != 1 || actions[0].Action != "get-secret" {
+		t.Errorf("expected the secret to be fetched once, got %#v", actions)
+	}
+}
+
+func TestPullImageRejectsNonDockercfgSecret(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	testKubelet.fakeKubeClient.Secret = api.Secret{
+		ObjectMeta: api.ObjectMeta{Name: "db-credentials", Namespace: "private"},
+		Data:       map[string][]byte{"password": []byte("p4ssw0rd")},
+	}
+
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "private"},
+		Spec: api.PodSpec{
+			ImagePullSecrets: []api.LocalObjectReference{{Name: "db-credentials"}},
+		},
+	}
+	if err := kl.pullImage(pod, "registry.example.com/foo/bar", nil); err == nil {
+		t.Errorf("expected an error for a secret that is not of the dockercfg type")
+	}
+	puller := kl.dockerPuller.(*dockertools.FakeDockerPuller)
+	if len(puller.ImagesPulled) != 0 {
+		t.Errorf("expected no image to be pulled, got %v", puller.ImagesPulled)
+	}
+}
+
+func TestGetPodInfraContainerIP(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	fakeDocker := testKubelet.fakeDocker
+	fakeDocker.Container = &docker.Container{
+		ID:              "9876",
+		NetworkSettings: &docker.NetworkSettings{IPAddress: "1.2.3.4"},
+	}
+
+	ip, err := kl.getPodInfraContainerIP("9876")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ip != "1.2.3.4" {
+		t.Errorf("expected pod IP 1.2.3.4, got %q", ip)
+	}
+}
+
 func runningState(cName string) api.ContainerStatus {
 	return api.ContainerStatus{
 		Name: cName,
@@ -2880,6 +3074,65 @@ func TestHandleNodeSelector(t *testing.T) {
 	}
 }
 
+// Tests that pods past their active deadline are marked failed in the status map.
+func TestHandlePastActiveDeadlinePods(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	kl := testKubelet.kubelet
+	activeDeadlineSeconds := int64(30)
+	pods := []api.Pod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				UID:       "123456789",
+				Name:      "podA",
+				Namespace: "foo",
+			},
+			Spec: api.PodSpec{ActiveDeadlineSeconds: &activeDeadlineSeconds},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{
+				UID:       "987654321",
+				Name:      "podB",
+				Namespace: "foo",
+			},
+			Spec: api.PodSpec{ActiveDeadlineSeconds: &activeDeadlineSeconds},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{
+				UID:       "543219876",
+				Name:      "podC",
+				Namespace: "foo",
+			},
+		},
+	}
+	// podA and podC started a minute ago, podB just started.
+	startTime := util.NewTime(time.Now().Add(-1 * time.Minute))
+	recentStartTime := util.Now()
+	kl.statusManager.SetPodStatus(&pods[0], api.PodStatus{Phase: api.PodRunning, StartTime: &startTime})
+	kl.statusManager.SetPodStatus(&pods[1], api.PodStatus{Phase: api.PodRunning, StartTime: &recentStartTime})
+	kl.statusManager.SetPodStatus(&pods[2], api.PodStatus{Phase: api.PodRunning, StartTime: &startTime})
+
+	kl.handlePastActiveDeadlinePods(pods)
+
+	expected := []struct {
+		phase  api.PodPhase
+		reason string
+	}{
+		{api.PodFailed, "DeadlineExceeded"},
+		{api.PodRunning, ""},
+		{api.PodRunning, ""},
+	}
+	for i := range pods {
+		podFullName := kubecontainer.GetPodFullName(&pods[i])
+		status, found := kl.statusManager.GetPodStatus(podFullName)
+		if !found {
+			t.Fatalf("status of pod %q is not found in the status map", podFullName)
+		}
+		if status.Phase != expected[i].phase || status.Reason != expected[i].reason {
+			t.Errorf("pod %q: expected phase %q and reason %q, got %q and %q", podFullName, expected[i].phase, expected[i].reason, status.Phase, status.Reason)
+		}
+	}
+}
+
 // Tests that we handle exceeded resources correctly by setting the failed status in status map.
 func TestHandleMemExceeded(t *testing.T) {
 	testKubelet := newTestKubelet(t)
@@ -3452,3 +3705,94 @@ func TestHostNetworkDisallowed(t *testing.T) {
 		t.Errorf("expected pod infra creation to fail")
 	}
 }
+
+func TestSyncPodsTerminatingPod(t *testing.T) {
+	testKubelet := newTestKubelet(t)
+	testKubelet.fakeCadvisor.On("MachineInfo").Return(&cadvisorApi.MachineInfo{}, nil)
+	kubelet := testKubelet.kubelet
+	fakeDocker := testKubelet.fakeDocker
+	waitGroup := testKubelet.waitGroup
+	fakeCommandRunner := fakeContainerCommandRunner{}
+	kubelet.runner = &fakeCommandRunner
+
+	container := api.Container{
+		Name: "bar",
+		Lifecycle: &api.Lifecycle{
+			PreStop: &api.Handler{
+				Exec: &api.ExecAction{Command: []string{"drain"}},
+			},
+		},
+	}
+	fakeDocker.ContainerList = []docker.APIContainers{
+		{
+			// format is // k8s_<container-id>_<pod-fullname>_<pod-uid>_<random>
+			Names: []string{"/k8s_bar." + strconv.FormatUint(dockertools.HashContainer(&container), 16) + "_foo_new_12345678_0"},
+			ID:    "1234",
+		},
+		{
+			// pod infra container
+			Names: []string{"/k8s_POD_foo_new_12345678_0"},
+			ID:    "9876",
+		},
+	}
+	deletionTimestamp := util.NewTime(time.Now().Add(30 * time.Second))
+	pods := []api.Pod{
+		{
+			ObjectMeta: api.ObjectMeta{
+				UID:               "12345678",
+				Name:              "foo",
+				Namespace:         "new",
+				DeletionTimestamp: &deletionTimestamp,
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{container},
+			},
+		},
+	}
+	kubelet.podManager.SetPods(pods)
+	waitGroup.Add(1)
+	err := kubelet.SyncPods(pods, emptyPodUIDs, map[string]api.Pod{}, time.Now())
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	waitGroup.Wait()
+
+	if fakeCommandRunner.ID != "1234" || !reflect.DeepEqual(fakeCommandRunner.Cmd, []string{"drain"}) {
+		t.Errorf("expected preStop hook to run in container 1234, got %+v", fakeCommandRunner)
+	}
+	// The pod infra container must be stopped after the app containers.
+	if !reflect.DeepEqual(fakeDocker.Stopped, []string{"1234", "9876"}) {
+		t.Errorf("unexpected stopped containers: %v", fakeDocker.Stopped)
+	}
+	actions := testKubelet.fakeKubeClient.Actions
+	if len(actions) != 1 || actions[0].Action != "delete-pod" || actions[0].Value != "foo" {
+		t.Errorf("expected the pod deletion to be confirmed, got actions %#v", actions)
+	}
+}
+
+func TestPodTerminationGracePeriod(t *testing.T) {
+	gracePeriod := int64(60)
+	zero := int64(0)
+	soon := util.NewTime(time.Now().Add(10 * time.Second))
+	past := util.NewTime(time.Now().Add(-10 * time.Second))
+	tests := []struct {
+		gracePeriodSeconds *int64
+		deletionTimestamp  *util.Time
+		min, max           time.Duration
+	}{
+		{nil, nil, 30 * time.Second, 30 * time.Second},
+		{&gracePeriod, nil, 60 * time.Second, 60 * time.Second},
+		{&gracePeriod, &soon, 9 * time.Second, 10 * time.Second},
+		{&gracePeriod, &past, minimumGracePeriodInSeconds * time.Second, minimumGracePeriodInSeconds * time.Second},
+		{&zero, nil, minimumGracePeriodInSeconds * time.Second, minimumGracePeriodInSeconds * time.Second},
+	}
+	for i, test := range tests {
+		pod := &api.Pod{
+			ObjectMeta: api.ObjectMeta{DeletionTimestamp: test.deletionTimestamp},
+			Spec:       api.PodSpec{TerminationGracePeriodSeconds: test.gracePeriodSeconds},
+		}
+		if actual := podTerminationGracePeriod(pod); actual < test.min || actual > test.max {
+			t.Errorf("%d: expected a grace period between %v and %v, got %v", i, test.min, test.max, actual)
+		}
+	}
+}
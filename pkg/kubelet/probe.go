@@ -19,6 +19,7 @@ package kubelet
 import (
 	"fmt"
 	"strconv"
+	"sync"
 	"time"
 
 	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
@@ -40,7 +41,7 @@ const maxProbeRetries = 3
 // If liveness is successful, do a readiness check and set readiness accordingly.
 func (kl *Kubelet) probeContainer(pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) (probe.Result, error) {
 	// Probe liveness.
-	live, err := kl.probeContainerLiveness(pod, status, container, createdAt)
+	live, err := kl.probeContainerLiveness(pod, status, container, containerID, createdAt)
 	if err != nil {
 		glog.V(1).Infof("Liveness probe errored: %v", err)
 		kl.readinessManager.SetReadiness(containerID, false)
@@ -53,15 +54,13 @@ func (kl *Kubelet) probeContainer(pod *api.Pod, status api.PodStatus, container
 	}
 
 	// Probe readiness.
-	ready, err := kl.probeContainerReadiness(pod, status, container, createdAt)
+	ready, err := kl.probeContainerReadiness(pod, status, container, containerID, createdAt)
 	if err == nil && ready == probe.Success {
 		glog.V(3).Infof("Readiness probe successful: %v", ready)
-		kl.readinessManager.SetReadiness(containerID, true)
 		return probe.Success, nil
 	}
 
 	glog.V(1).Infof("Readiness probe failed/errored: %v, %v", ready, err)
-	kl.readinessManager.SetReadiness(containerID, false)
 
 	ref, ok := kl.containerRefManager.GetRef(containerID)
 	if !ok {
@@ -74,7 +73,7 @@ func (kl *Kubelet) probeContainer(pod *api.Pod, status api.PodStatus, container
 
 // probeContainerLiveness probes the liveness of a container.
 // If the initalDelay since container creation on liveness probe has not passed the probe will return probe.Success.
-func (kl *Kubelet) probeContainerLiveness(pod *api.Pod, status api.PodStatus, container api.Container, createdAt int64) (probe.Result, error) {
+func (kl *Kubelet) probeContainerLiveness(pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) (probe.Result, error) {
 	p := container.LivenessProbe
 	if p == nil {
 		return probe.Success, nil
@@ -82,20 +81,47 @@ func (kl *Kubelet) probeContainerLiveness(pod *api.Pod, status api.PodStatus, co
 	if time.Now().Unix()-createdAt < p.InitialDelaySeconds {
 		return probe.Success, nil
 	}
-	return kl.runProbeWithRetries(p, pod, status, container, maxProbeRetries)
+	return kl.runProbeWithThreshold(livenessProbe, p, pod, status, container, containerID)
 }
 
-// probeContainerLiveness probes the readiness of a container.
+// probeContainerLiveness probes the readiness of a container and records its readiness.
 // If the initial delay on the readiness probe has not passed the probe will return probe.Failure.
-func (kl *Kubelet) probeContainerReadiness(pod *api.Pod, status api.PodStatus, container api.Container, createdAt int64) (probe.Result, error) {
+func (kl *Kubelet) probeContainerReadiness(pod *api.Pod, status api.PodStatus, container api.Container, containerID string, createdAt int64) (probe.Result, error) {
 	p := container.ReadinessProbe
 	if p == nil {
+		kl.readinessManager.SetReadiness(containerID, true)
 		return probe.Success, nil
 	}
 	if time.Now().Unix()-createdAt < p.InitialDelaySeconds {
+		kl.readinessManager.SetReadiness(containerID, false)
 		return probe.Failure, nil
 	}
-	return kl.runProbeWithRetries(p, pod, status, container, maxProbeRetries)
+	return kl.runProbeWithThreshold(readinessProbe, p, pod, status, container, containerID)
+}
+
+// runProbeWithThreshold runs the probe of the given kind, unless it already ran within its
+// period in which case the result reported for that run is returned again. A failed run is
+// reported as probe.Success until FailureThreshold consecutive runs have failed. The results
+// of readiness probes are also recorded in the readiness manager.
+func (kl *Kubelet) runProbeWithThreshold(kind probeKind, p *api.Probe, pod *api.Pod, status api.PodStatus, container api.Container, containerID string) (probe.Result, error) {
+	key := probeKey{containerID: containerID, kind: kind}
+	now := time.Now()
+	if outcome, ok := kl.probeHistory.lastOutcome(key, p.PeriodSeconds, now); ok {
+		return outcome.result, outcome.err
+	}
+
+	result, err := kl.runProbeWithRetries(p, pod, status, container, maxProbeRetries)
+	success := err == nil && result == probe.Success
+	failures := kl.probeHistory.record(key, success, now)
+	if kind == readinessProbe {
+		kl.readinessManager.RecordProbeResult(containerID, success, p.SuccessThreshold, p.FailureThreshold)
+	}
+	if !success && failures < p.FailureThreshold {
+		glog.V(1).Infof("%v probe of container %q failed %d of %d times: %v, %v", kind, container.Name, failures, p.FailureThreshold, result, err)
+		result, err = probe.Success, nil
+	}
+	kl.probeHistory.setOutcome(key, probeOutcome{result: result, err: err})
+	return result, err
 }
 
 // runProbeWithRetries tries to probe the container in a finite loop, it returns the last result
@@ -215,3 +241,97 @@ type probeHolder struct {
 	http httprobe.HTTPProber
 	tcp  tcprobe.TCPProber
 }
+
+// probeKind distinguishes the probes of a container.
+type probeKind int
+
+const (
+	livenessProbe probeKind = iota
+	readinessProbe
+)
+
+func (k probeKind) String() string {
+	switch k {
+	case livenessProbe:
+		return "Liveness"
+	case readinessProbe:
+		return "Readiness"
+	}
+	return "Unknown"
+}
+
+type probeKey struct {
+	containerID string
+	kind        probeKind
+}
+
+// probeOutcome is what the kubelet reported for a run of a probe.
+type probeOutcome struct {
+	result probe.Result
+	err    error
+}
+
+type probeRecord struct {
+	lastRun  time.Time
+	outcome  probeOutcome
+	failures int
+}
+
+// probeHistory tracks the runs of container probes, so that probes are only run
+// once per period and failures are only acted upon once they are consecutive
+// enough. The zero value is ready to use.
+type probeHistory struct {
+	sync.Mutex
+	records map[probeKey]*probeRecord
+}
+
+// lastOutcome returns the outcome of the last run of a probe if it ran less than
+// periodSeconds before now. Probes without a period run on every sync.
+func (h *probeHistory) lastOutcome(key probeKey, periodSeconds int64, now time.Time) (probeOutcome, bool) {
+	h.Lock()
+	defer h.Unlock()
+	record, ok := h.records[key]
+	if !ok || periodSeconds <= 0 || now.Sub(record.lastRun) >= time.Duration(periodSeconds)*time.Second {
+		return probeOutcome{}, false
+	}
+	return record.outcome, true
+}
+
+// record notes a run of a probe and returns the number of consecutive runs of it
+// that failed, including this one.
+func (h *probeHistory) record(key probeKey, success bool, now time.Time) int {
+	h.Lock()
+	defer h.Unlock()
+	if h.records == nil {
+		h.records = make(map[probeKey]*probeRecord)
+	}
+	record, ok := h.records[key]
+	if !ok {
+		record = &probeRecord{}
+		h.records[key] = record
+	}
+	record.lastRun = now
+	if success {
+		record.failures = 0
+	} else {
+		record.failures++
+	}
+	return record.failures
+}
+
+// setOutcome stores the outcome reported for the last run of a probe.
+func (h *probeHistory) setOutcome(key probeKey, outcome probeOutcome) {
+	h.Lock()
+	defer h.Unlock()
+	if record, ok := h.records[key]; ok {
+		record.outcome = outcome
+	}
+}
+
+// remove forgets the probe runs of the container with the given ID.
+func (h *probeHistory) remove(containerID string) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.records, probeKey{containerID: containerID, kind: livenessProbe})
+	delete(h.records, probeKey{containerID: containerID, kind: readinessProbe})
+}
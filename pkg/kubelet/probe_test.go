@@ -417,3 +417,110 @@ func TestProbeContainer(t *testing.T) {
 		}
 	}
 }
+
+func TestProbeContainerLivenessFailureThreshold(t *testing.T) {
+	prober := &fakeExecProber{result: probe.Success}
+	kl := makeTestKubelet(probe.Success, nil)
+	kl.prober.exec = prober
+	container := api.Container{
+		LivenessProbe: &api.Probe{
+			Handler:          api.Handler{Exec: &api.ExecAction{}},
+			FailureThreshold: 3,
+		},
+	}
+
+	steps := []struct {
+		probeResult    probe.Result
+		expectedResult probe.Result
+	}{
+		{probe.Success, probe.Success},
+		{probe.Failure, probe.Success},
+		{probe.Failure, probe.Success},
+		{probe.Success, probe.Success},
+		{probe.Failure, probe.Success},
+		{probe.Failure, probe.Success},
+		{probe.Failure, probe.Failure},
+	}
+	for i, step := range steps {
+		prober.result = step.probeResult
+		result, err := kl.probeContainer(&api.Pod{}, api.PodStatus{}, container, "foobar", 0)
+		if err != nil {
+			t.Errorf("step %d: unexpected error: %v", i, err)
+		}
+		if result != step.expectedResult {
+			t.Errorf("step %d: expected result %v, got %v", i, step.expectedResult, result)
+		}
+	}
+}
+
+func TestProbeContainerReadinessThresholds(t *testing.T) {
+	prober := &fakeExecProber{result: probe.Success}
+	kl := makeTestKubelet(probe.Success, nil)
+	kl.prober.exec = prober
+	container := api.Container{
+		ReadinessProbe: &api.Probe{
+			Handler:          api.Handler{Exec: &api.ExecAction{}},
+			SuccessThreshold: 2,
+			FailureThreshold: 2,
+		},
+	}
+
+	steps := []struct {
+		probeResult       probe.Result
+		expectedResult    probe.Result
+		expectedReadiness bool
+	}{
+		{probe.Success, probe.Success, false},
+		{probe.Success, probe.Success, true},
+		{probe.Failure, probe.Success, true},
+		{probe.Success, probe.Success, true},
+		{probe.Failure, probe.Success, true},
+		{probe.Failure, probe.Failure, false},
+	}
+	for i, step := range steps {
+		prober.result = step.probeResult
+		result, err := kl.probeContainer(&api.Pod{}, api.PodStatus{}, container, "foobar", 0)
+		if err != nil {
+			t.Errorf("step %d: unexpected error: %v", i, err)
+		}
+		if result != step.expectedResult {
+			t.Errorf("step %d: expected result %v, got %v", i, step.expectedResult, result)
+		}
+		if ready := kl.readinessManager.GetReadiness("foobar"); ready != step.expectedReadiness {
+			t.Errorf("step %d: expected readiness %v, got %v", i, step.expectedReadiness, ready)
+		}
+	}
+}
+
+func TestProbeContainerPeriod(t *testing.T) {
+	prober := &fakeExecProber{result: probe.Success}
+	kl := makeTestKubelet(probe.Success, nil)
+	kl.prober.exec = prober
+	container := api.Container{
+		LivenessProbe: &api.Probe{
+			Handler:       api.Handler{Exec: &api.ExecAction{}},
+			PeriodSeconds: 60,
+		},
+	}
+
+	if result, _ := kl.probeContainer(&api.Pod{}, api.PodStatus{}, container, "foobar", 0); result != probe.Success {
+		t.Errorf("expected success, got %v", result)
+	}
+
+	// The period has not elapsed, so the last result is reported again.
+	prober.result = probe.Failure
+	if result, _ := kl.probeContainer(&api.Pod{}, api.PodStatus{}, container, "foobar", 0); result != probe.Success {
+		t.Errorf("expected the probe to be skipped within its period, got %v", result)
+	}
+
+	key := probeKey{containerID: "foobar", kind: livenessProbe}
+	kl.probeHistory.records[key].lastRun = time.Now().Add(-61 * time.Second)
+	if result, _ := kl.probeContainer(&api.Pod{}, api.PodStatus{}, container, "foobar", 0); result != probe.Failure {
+		t.Errorf("expected the probe to run after its period, got %v", result)
+	}
+
+	kl.probeHistory.remove("foobar")
+	if _, ok := kl.probeHistory.lastOutcome(key, 60, time.Now()); ok {
+		t.Errorf("expected no recorded outcome after removal")
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qos
+
+import (
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// Class is the quality of service class of a pod.
+type Class string
+
+const (
+	// Guaranteed pods have every container limited to the CPU and memory it
+	// requests. They are the last to be killed when the node runs out of memory.
+	Guaranteed Class = "Guaranteed"
+	// Burstable pods request some resources, and may use more than they request.
+	Burstable Class = "Burstable"
+	// BestEffort pods neither request nor are limited to any resource. They are
+	// the first to be killed when the node runs out of memory.
+	BestEffort Class = "BestEffort"
+)
+
+// The oom_score_adj of the node daemons and of the POD infrastructure
+// containers. They are the last to be killed, as killing one of them takes the
+// containers it runs or holds the network of down with it.
+const (
+	KubeletOOMScoreAdj   = -999
+	KubeProxyOOMScoreAdj = -999
+	DockerOOMScoreAdj    = -999
+	PodInfraOOMScoreAdj  = -999
+)
+
+const (
+	// The oom_score_adj of the containers of guaranteed pods. It is just above
+	// the ones of the node daemons and of the POD infrastructure containers.
+	guaranteedOOMScoreAdj = -998
+	// The oom_score_adj of the containers of best-effort pods, the highest one.
+	bestEffortOOMScoreAdj = 1000
+	// The range of the oom_score_adj of the containers of burstable pods, which
+	// stays between the guaranteed and the best-effort ones.
+	minBurstableOOMScoreAdj = 2
+	maxBurstableOOMScoreAdj = 999
+)
+
+// computeResources are the resources the class of a pod depends on.
+var computeResources = []api.ResourceName{api.ResourceCPU, api.ResourceMemory}
+
+// GetPodClass returns the class of the pod. Requests default to limits, so a
+// pod which only sets limits on CPU and memory is guaranteed.
+func GetPodClass(pod *api.Pod) Class {
+	guaranteed := len(pod.Spec.Containers) > 0
+	bestEffort := true
+	for i := range pod.Spec.Containers {
+		resources := &pod.Spec.Containers[i].Resources
+		requests := resources.EffectiveRequests()
+		for _, name := range computeResources {
+			request := requests[name]
+			limit := resources.Limits[name]
+			if request.MilliValue() != 0 {
+				bestEffort = false
+			}
+			if limit.MilliValue() == 0 || request.MilliValue() != limit.MilliValue() {
+				guaranteed = false
+			}
+		}
+	}
+	switch {
+	case guaranteed:
+		return Guaranteed
+	case bestEffort:
+		return BestEffort
+	}
+	return Burstable
+}
+
+// GetContainerOOMScoreAdjust returns the oom_score_adj of a container of the
+// pod on a node with the given memory capacity. Containers of burstable pods
+// are less likely to be killed the more memory they request.
+func GetContainerOOMScoreAdjust(pod *api.Pod, container *api.Container, memoryCapacity int64) int {
+	switch GetPodClass(pod) {
+	case Guaranteed:
+		return guaranteedOOMScoreAdj
+	case BestEffort:
+		return bestEffortOOMScoreAdj
+	}
+	if memoryCapacity <= 0 {
+		return maxBurstableOOMScoreAdj
+	}
+	requests := container.Resources.EffectiveRequests()
+	oomScoreAdj := 1000 - (1000*requests.Memory().Value())/memoryCapacity
+	if oomScoreAdj < minBurstableOOMScoreAdj {
+		return minBurstableOOMScoreAdj
+	}
+	if oomScoreAdj > maxBurstableOOMScoreAdj {
+		return maxBurstableOOMScoreAdj
+	}
+	return int(oomScoreAdj)
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package qos
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func getResourceList(cpu, memory string) api.ResourceList {
+	res := api.ResourceList{}
+	if cpu != "" {
+		res[api.ResourceCPU] = resource.MustParse(cpu)
+	}
+	if memory != "" {
+		res[api.ResourceMemory] = resource.MustParse(memory)
+	}
+	return res
+}
+
+func newPod(resources ...api.ResourceRequirements) *api.Pod {
+	containers := []api.Container{}
+	for _, r := range resources {
+		containers = append(containers, api.Container{Resources: r})
+	}
+	return &api.Pod{Spec: api.PodSpec{Containers: containers}}
+}
+
+func TestGetPodClass(t *testing.T) {
+	tests := []struct {
+		pod      *api.Pod
+		expected Class
+		test     string
+	}{
+		{
+			pod:      newPod(api.ResourceRequirements{}),
+			expected: BestEffort,
+			test:     "no requests nor limits",
+		},
+		{
+			pod: newPod(api.ResourceRequirements{
+				Limits: getResourceList("100m", "100Mi"),
+			}),
+			expected: Guaranteed,
+			test:     "limits only, requests default to them",
+		},
+		{
+			pod: newPod(api.ResourceRequirements{
+				Requests: getResourceList("100m", "100Mi"),
+				Limits:   getResourceList("100m", "100Mi"),
+			}),
+			expected: Guaranteed,
+			test:     "requests equal to limits",
+		},
+		{
+			pod: newPod(api.ResourceRequirements{
+				Requests: getResourceList("50m", "100Mi"),
+				Limits:   getResourceList("100m", "100Mi"),
+			}),
+			expected: Burstable,
+			test:     "cpu request below its limit",
+		},
+		{
+			pod: newPod(api.ResourceRequirements{
+				Limits: getResourceList("100m", ""),
+			}),
+			expected: Burstable,
+			test:     "no memory limit",
+		},
+		{
+			pod: newPod(api.ResourceRequirements{
+				Requests: getResourceList("", "100Mi"),
+			}),
+			expected: Burstable,
+			test:     "requests without limits",
+		},
+		{
+			pod: newPod(
+				api.ResourceRequirements{Limits: getResourceList("100m", "100Mi")},
+				api.ResourceRequirements{},
+			),
+			expected: Burstable,
+			test:     "one guaranteed and one best-effort container",
+		},
+	}
+	for _, test := range tests {
+		if class := GetPodClass(test.pod); class != test.expected {
+			t.Errorf("%s: expected %s, got %s", test.test, test.expected, class)
+		}
+	}
+}
+
+func TestGetContainerOOMScoreAdjust(t *testing.T) {
+	const memoryCapacity = 4000000000
+	tests := []struct {
+		pod      *api.Pod
+		expected int
+		test     string
+	}{
+		{
+			pod:      newPod(api.ResourceRequirements{}),
+			expected: 1000,
+			test:     "best-effort",
+		},
+		{
+			pod:      newPod(api.ResourceRequirements{Limits: getResourceList("100m", "1G")}),
+			expected: -998,
+			test:     "guaranteed",
+		},
+		{
+			pod:      newPod(api.ResourceRequirements{Requests: getResourceList("", "1G")}),
+			expected: 750,
+			test:     "burstable requesting a quarter of the memory",
+		},
+		{
+			pod:      newPod(api.ResourceRequirements{Requests: getResourceList("100m", "")}),
+			expected: 999,
+			test:     "burstable requesting no memory",
+		},
+		{
+			pod:      newPod(api.ResourceRequirements{Requests: getResourceList("", "4G")}),
+			expected: 2,
+			test:     "burstable requesting all the memory",
+		},
+	}
+	for _, test := range tests {
+		oomScoreAdj := GetContainerOOMScoreAdjust(test.pod, &test.pod.Spec.Containers[0], memoryCapacity)
+		if oomScoreAdj != test.expected {
+			t.Errorf("%s: expected %d, got %d", test.test, test.expected, oomScoreAdj)
+		}
+	}
+}
+
+func TestOOMScoreAdjustOrdering(t *testing.T) {
+	memoryCapacity := int64(4000000000)
+	guaranteed := newPod(api.ResourceRequirements{Limits: getResourceList("100m", "1G")})
+	burstable := newPod(api.ResourceRequirements{Requests: getResourceList("", "4G")})
+	bestEffort := newPod(api.ResourceRequirements{})
+	guaranteedOOMScoreAdj := GetContainerOOMScoreAdjust(guaranteed, &guaranteed.Spec.Containers[0], memoryCapacity)
+	burstableOOMScoreAdj := GetContainerOOMScoreAdjust(burstable, &burstable.Spec.Containers[0], memoryCapacity)
+	bestEffortOOMScoreAdj := GetContainerOOMScoreAdjust(bestEffort, &bestEffort.Spec.Containers[0], memoryCapacity)
+
+	for name, oomScoreAdj := range map[string]int{
+		"kubelet":                      KubeletOOMScoreAdj,
+		"kube-proxy":                   KubeProxyOOMScoreAdj,
+		"docker":                       DockerOOMScoreAdj,
+		"POD infrastructure container": PodInfraOOMScoreAdj,
+	} {
+		if oomScoreAdj < -1000 || oomScoreAdj >= guaranteedOOMScoreAdj {
+			t.Errorf("expected the %s oom_score_adj %d to be below the guaranteed one %d", name, oomScoreAdj, guaranteedOOMScoreAdj)
+		}
+	}
+	if guaranteedOOMScoreAdj >= burstableOOMScoreAdj || burstableOOMScoreAdj >= bestEffortOOMScoreAdj || bestEffortOOMScoreAdj > 1000 {
+		t.Errorf("expected guaranteed < burstable < best-effort oom_score_adj, got %d, %d and %d", guaranteedOOMScoreAdj, burstableOOMScoreAdj, bestEffortOOMScoreAdj)
+	}
+}
@@ -73,6 +73,21 @@ func (s *statusManager) SetPodStatus(pod *api.Pod, status api.PodStatus) {
 	s.podStatusesLock.Lock()
 	defer s.podStatusesLock.Unlock()
 	oldStatus, found := s.podStatuses[podFullName]
+
+	// Set the start time of the pod the first time the kubelet reports its status;
+	// it must not change afterwards since the pod's active deadline is measured from it.
+	if status.StartTime.IsZero() {
+		switch {
+		case found && !oldStatus.StartTime.IsZero():
+			status.StartTime = oldStatus.StartTime
+		case !pod.Status.StartTime.IsZero():
+			status.StartTime = pod.Status.StartTime
+		default:
+			now := util.Now()
+			status.StartTime = &now
+		}
+	}
+
 	if !found || !reflect.DeepEqual(oldStatus, status) {
 		s.podStatuses[podFullName] = status
 		s.podStatusChannel <- podStatusSyncRequest{pod, status}
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	kubecontainer "github.com/GoogleCloudPlatform/kubernetes/pkg/kubelet/container"
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
@@ -103,6 +104,24 @@ func TestUnchangedStatus(t *testing.T) {
 	verifyUpdates(t, syncer, 1)
 }
 
+func TestStartTimeIsPreserved(t *testing.T) {
+	syncer := newTestStatusManager()
+	syncer.SetPodStatus(testPod, getRandomPodStatus())
+	firstStatus, found := syncer.GetPodStatus(kubecontainer.GetPodFullName(testPod))
+	if !found {
+		t.Fatalf("expected the pod status to be stored")
+	}
+	if firstStatus.StartTime.IsZero() {
+		t.Fatalf("expected the start time to be set")
+	}
+
+	syncer.SetPodStatus(testPod, getRandomPodStatus())
+	secondStatus, _ := syncer.GetPodStatus(kubecontainer.GetPodFullName(testPod))
+	if !firstStatus.StartTime.Equal(secondStatus.StartTime.Time) {
+		t.Errorf("expected start time %v to be preserved, got %v", firstStatus.StartTime, secondStatus.StartTime)
+	}
+}
+
 func TestSyncBatch(t *testing.T) {
 	syncer := newTestStatusManager()
 	syncer.SetPodStatus(testPod, getRandomPodStatus())
@@ -39,20 +39,27 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/master/ports"
 	controlleretcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/controller/etcd"
+	daemonsetetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/daemonset/etcd"
+	deploymentetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/deployment/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/endpoint"
 	endpointsetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/endpoint/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/event"
+	autoscaleretcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/horizontalpodautoscaler/etcd"
+	jobetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/job/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/limitrange"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/minion"
 	nodeetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/minion/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/namespace"
 	namespaceetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/namespace/etcd"
+	pvetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/persistentvolume/etcd"
+	pvcetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/persistentvolumeclaim/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod"
 	podetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod/etcd"
 	resourcequotaetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/resourcequota/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/secret"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/service"
+	serviceaccountetcd "github.com/GoogleCloudPlatform/kubernetes/pkg/registry/serviceaccount/etcd"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/ui"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -119,6 +126,10 @@ type Config struct {
 
 	// The name of the cluster.
 	ClusterName string
+
+	// The range of ports from which node ports are allocated to services
+	// of type NodePort.
+	ServiceNodePortRange util.PortRange
 }
 
 // Master contains state for a Kubernetes cluster master/api server.
@@ -155,6 +166,7 @@ type Master struct {
 	serviceReadWriteIP   net.IP
 	serviceReadWritePort int
 	masterServices       *util.Runner
+	serviceStorage       *service.REST
 
 	// storage contains the RESTful endpoints exposed by this master
 	storage map[string]rest.Storage
@@ -196,6 +208,15 @@ func setDefaults(c *Config) {
 		}
 		c.PortalNet = portalNet
 	}
+	if c.ServiceNodePortRange.Size == 0 {
+		defaultRange := "30000-32767"
+		glog.Warningf("Service node port range unspecified. Defaulting to %v.", defaultRange)
+		nodePortRange, err := util.ParsePortRange(defaultRange)
+		if err != nil {
+			glog.Fatalf("Unable to parse port range: %v", err)
+		}
+		c.ServiceNodePortRange = *nodePortRange
+	}
 	if c.MasterCount == 0 {
 		// Clearly, there will be at least one master.
 		c.MasterCount = 1
@@ -228,6 +249,7 @@ func setDefaults(c *Config) {
 // Certain config fields will be set to a default value if unset,
 // including:
 //   PortalNet
+//   ServiceNodePortRange
 //   MasterCount
 //   ReadOnlyPort
 //   ReadWritePort
@@ -307,7 +329,7 @@ func New(c *Config) *Master {
 	m.handlerContainer.Router(restful.CurlyRouter{})
 	m.muxHelper = &apiserver.MuxHelper{m.mux, []string{}}
 
-	m.masterServices = util.NewRunner(m.serviceWriterLoop, m.roServiceWriterLoop)
+	m.masterServices = util.NewRunner(m.serviceWriterLoop, m.roServiceWriterLoop, m.nodePortRepairLoop)
 	m.init(c)
 	return m
 }
@@ -378,6 +400,14 @@ func (m *Master) init(c *Config) {
 	m.serviceRegistry = registry
 
 	controllerStorage := controlleretcd.NewREST(c.EtcdHelper)
+	jobStorage, jobStatusStorage := jobetcd.NewStorage(c.EtcdHelper)
+	daemonSetStorage, daemonSetStatusStorage := daemonsetetcd.NewStorage(c.EtcdHelper)
+	deploymentStorage, deploymentStatusStorage := deploymentetcd.NewStorage(c.EtcdHelper)
+	autoscalerStorage, autoscalerStatusStorage := autoscaleretcd.NewStorage(c.EtcdHelper)
+	persistentVolumeStorage, persistentVolumeStatusStorage := pvetcd.NewStorage(c.EtcdHelper)
+	persistentVolumeClaimStorage, persistentVolumeClaimStatusStorage := pvcetcd.NewStorage(c.EtcdHelper)
+	serviceAccountStorage := serviceaccountetcd.NewStorage(c.EtcdHelper)
+	m.serviceStorage = service.NewStorage(m.serviceRegistry, c.Cloud, m.nodeRegistry, m.endpointRegistry, m.portalNet, c.ServiceNodePortRange, c.ClusterName)
 
 	// TODO: Factor out the core API registration
 	m.storage = map[string]rest.Storage{
@@ -387,7 +417,13 @@ func (m *Master) init(c *Config) {
 		"bindings":     bindingStorage,
 
 		"replicationControllers": controllerStorage,
-		"services":               service.NewStorage(m.serviceRegistry, c.Cloud, m.nodeRegistry, m.endpointRegistry, m.portalNet, c.ClusterName),
+		"jobs":                   jobStorage,
+		"jobs/status":            jobStatusStorage,
+		"daemonSets":             daemonSetStorage,
+		"daemonSets/status":      daemonSetStatusStorage,
+		"deployments":            deploymentStorage,
+		"deployments/status":     deploymentStatusStorage,
+		"services":               m.serviceStorage,
 		"endpoints":              endpointsStorage,
 		"minions":                nodeStorage,
 		"nodes":                  nodeStorage,
@@ -400,6 +436,15 @@ func (m *Master) init(c *Config) {
 		"namespaces/status":     namespaceStatusStorage,
 		"namespaces/finalize":   namespaceFinalizeStorage,
 		"secrets":               secret.NewStorage(secretRegistry),
+		"serviceAccounts":       serviceAccountStorage,
+
+		"horizontalPodAutoscalers":        autoscalerStorage,
+		"horizontalPodAutoscalers/status": autoscalerStatusStorage,
+
+		"persistentVolumes":             persistentVolumeStorage,
+		"persistentVolumes/status":      persistentVolumeStatusStorage,
+		"persistentVolumeClaims":        persistentVolumeClaimStorage,
+		"persistentVolumeClaims/status": persistentVolumeClaimStatusStorage,
 	}
 
 	apiVersions := []string{"v1beta1", "v1beta2"}
@@ -23,6 +23,7 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/cnaize/kubernetes/pkg/api"
 
 	"github.com/golang/glog"
@@ -54,6 +55,16 @@ func (m *Master) serviceWriterLoop(stop chan struct{}) {
 	}
 }
 
+// nodePortRepairLoop periodically reconciles the node port allocator with the
+// services stored in etcd.
+func (m *Master) nodePortRepairLoop(stop chan struct{}) {
+	util.Until(func() {
+		if err := m.serviceStorage.RepairNodePorts(); err != nil {
+			glog.Errorf("Can't repair service node ports: %v", err)
+		}
+	}, time.Minute, stop)
+}
+
 func (m *Master) roServiceWriterLoop(stop chan struct{}) {
 	for {
 		// Update service & endpoint records.
@@ -114,12 +125,16 @@ func (m *Master) createMasterServiceIfNeeded(serviceName string, serviceIP net.I
 			Labels:    map[string]string{"provider": "kubernetes", "component": "apiserver"},
 		},
 		Spec: api.ServiceSpec{
-			Port: servicePort,
+			Ports: []api.ServicePort{{
+				Port:       servicePort,
+				Protocol:   api.ProtocolTCP,
+				TargetPort: util.NewIntOrStringFromInt(servicePort),
+			}},
 			// maintained by this code, not by the pod selector
 			Selector:        nil,
 			PortalIP:        serviceIP.String(),
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	_, err := m.storage["services"].(rest.Creater).Create(ctx, svc)
@@ -0,0 +1,307 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podautoscaler
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+// Time period of main horizontal pod autoscaler sync loop
+const DefaultSyncPeriod = 30 * time.Second
+
+const (
+	// tolerance is the relative distance to the target utilization below
+	// which the number of replicas is left alone.
+	tolerance = 0.1
+
+	// upscaleForbiddenWindow and downscaleForbiddenWindow are the periods
+	// after a rescale during which the autoscaler does not scale up or down
+	// again, so that the pods have time to settle.
+	upscaleForbiddenWindow   = 3 * time.Minute
+	downscaleForbiddenWindow = 5 * time.Minute
+)
+
+// HorizontalPodAutoscalerManager is responsible for synchronizing
+// HorizontalPodAutoscaler objects stored in the system with the number of
+// replicas of the replication controllers they target.
+type HorizontalPodAutoscalerManager struct {
+	kubeClient    client.Interface
+	metricsClient MetricsClient
+	recorder      record.EventRecorder
+	syncTime      <-chan time.Time
+
+	// To allow injection of syncAutoscaler and of the clock for testing.
+	syncHandler func(autoscaler api.HorizontalPodAutoscaler) error
+	now         func() time.Time
+
+	// specs holds the specs of the autoscalers seen by watchAutoscalers, by
+	// namespace/name.
+	specs map[string]api.HorizontalPodAutoscalerSpec
+}
+
+// NewHorizontalPodAutoscalerManager creates a new HorizontalPodAutoscalerManager.
+func NewHorizontalPodAutoscalerManager(kubeClient client.Interface, metricsClient MetricsClient, recorder record.EventRecorder) *HorizontalPodAutoscalerManager {
+	am := &HorizontalPodAutoscalerManager{
+		kubeClient:    kubeClient,
+		metricsClient: metricsClient,
+		recorder:      recorder,
+		now:           time.Now,
+		specs:         map[string]api.HorizontalPodAutoscalerSpec{},
+	}
+	am.syncHandler = am.syncAutoscaler
+	return am
+}
+
+// Run begins watching and syncing.
+func (am *HorizontalPodAutoscalerManager) Run(period time.Duration) {
+	am.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { am.watchAutoscalers(&resourceVersion) }, period)
+}
+
+// resourceVersion is a pointer to the resource version to use/update.
+func (am *HorizontalPodAutoscalerManager) watchAutoscalers(resourceVersion *string) {
+	watching, err := am.kubeClient.HorizontalPodAutoscalers(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-am.syncTime:
+			am.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from watch during sync: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			glog.V(4).Infof("Got watch: %#v", event)
+			autoscaler, ok := event.Object.(*api.HorizontalPodAutoscaler)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = autoscaler.ResourceVersion
+			// A deleted autoscaler leaves its replication controller at its
+			// current size. Updates which leave the spec alone, such as our
+			// own status updates, wait for the periodic sync to poll the
+			// metrics again.
+			if !am.specChanged(event.Type, autoscaler) {
+				continue
+			}
+			glog.V(4).Infof("About to sync from watch: %v", autoscaler.Name)
+			if err := am.syncHandler(*autoscaler); err != nil {
+				util.HandleError(fmt.Errorf("unexpected sync error: %v", err))
+			}
+		}
+	}
+}
+
+// specChanged records the spec of the autoscaler, and returns true if the
+// autoscaler was not deleted and its spec differs from the last one seen.
+func (am *HorizontalPodAutoscalerManager) specChanged(eventType watch.EventType, autoscaler *api.HorizontalPodAutoscaler) bool {
+	key := autoscaler.Namespace + "/" + autoscaler.Name
+	if eventType == watch.Deleted {
+		delete(am.specs, key)
+		return false
+	}
+	spec, found := am.specs[key]
+	am.specs[key] = autoscaler.Spec
+	return !found || !api.Semantic.DeepEqual(spec, autoscaler.Spec)
+}
+
+// podCPURequest returns the CPU requested by all the containers of the pod,
+// in millicores. Containers without a request are accounted for by their
+// limit.
+func podCPURequest(pod *api.Pod) (int64, error) {
+	total := int64(0)
+	for _, container := range pod.Spec.Containers {
+		request, ok := container.Resources.Requests[api.ResourceCPU]
+		if !ok {
+			request, ok = container.Resources.Limits[api.ResourceCPU]
+		}
+		if !ok {
+			return 0, fmt.Errorf("container %s of pod %s/%s has no CPU request", container.Name, pod.Namespace, pod.Name)
+		}
+		total += request.MilliValue()
+	}
+	return total, nil
+}
+
+// getCPUUtilization returns the CPU usage of the running pods of the
+// replication controller, as a percentage of the CPU they request.
+func (am *HorizontalPodAutoscalerManager) getCPUUtilization(rc *api.ReplicationController) (int, error) {
+	podList, err := am.kubeClient.Pods(rc.Namespace).List(labels.Set(rc.Spec.Selector).AsSelector())
+	if err != nil {
+		return 0, err
+	}
+	requested, used := int64(0), int64(0)
+	for i := range podList.Items {
+		pod := &podList.Items[i]
+		if pod.Status.Phase != api.PodRunning {
+			continue
+		}
+		request, err := podCPURequest(pod)
+		if err != nil {
+			return 0, err
+		}
+		usage, err := am.metricsClient.GetCPUUsage(pod)
+		if err != nil {
+			return 0, err
+		}
+		requested += request
+		used += usage
+	}
+	if requested == 0 {
+		return 0, fmt.Errorf("no running pods with a CPU request")
+	}
+	return int(used * 100 / requested), nil
+}
+
+// desiredReplicas returns the number of replicas which brings the utilization
+// back to the target, within the bounds of the autoscaler.
+func desiredReplicas(autoscaler *api.HorizontalPodAutoscaler, currentReplicas, utilization int) int {
+	desired := currentReplicas
+	ratio := float64(utilization) / float64(autoscaler.Spec.TargetCPUUtilizationPercentage)
+	if math.Abs(ratio-1) > tolerance {
+		desired = int(math.Ceil(ratio * float64(currentReplicas)))
+	}
+	if desired < autoscaler.Spec.MinReplicas {
+		desired = autoscaler.Spec.MinReplicas
+	}
+	if desired > autoscaler.Spec.MaxReplicas {
+		desired = autoscaler.Spec.MaxReplicas
+	}
+	return desired
+}
+
+// canRescale returns true if the stabilization window following the last
+// rescale of the autoscaler allows to move in the given direction at now.
+func canRescale(autoscaler *api.HorizontalPodAutoscaler, upscale bool, now time.Time) bool {
+	if autoscaler.Status.LastScaleTime == nil {
+		return true
+	}
+	window := downscaleForbiddenWindow
+	if upscale {
+		window = upscaleForbiddenWindow
+	}
+	return !autoscaler.Status.LastScaleTime.Add(window).After(now)
+}
+
+// syncAutoscaler sets the number of replicas of the replication controller
+// targeted by the autoscaler from the CPU utilization of its pods, and
+// records the decision in the status of the autoscaler.
+func (am *HorizontalPodAutoscalerManager) syncAutoscaler(autoscaler api.HorizontalPodAutoscaler) error {
+	rc, err := am.kubeClient.ReplicationControllers(autoscaler.Namespace).Get(autoscaler.Spec.ScaleRef.Name)
+	if err != nil {
+		am.recorder.Eventf(&autoscaler, "failedGetScale", "Unable to get replication controller %s: %v", autoscaler.Spec.ScaleRef.Name, err)
+		return err
+	}
+	currentReplicas := rc.Spec.Replicas
+	utilization, err := am.getCPUUtilization(rc)
+	if err != nil {
+		am.recorder.Eventf(&autoscaler, "failedGetMetrics", "Unable to get the CPU utilization of %s: %v", rc.Name, err)
+		return fmt.Errorf("unable to get the CPU utilization of %s/%s: %v", rc.Namespace, rc.Name, err)
+	}
+	desired := desiredReplicas(&autoscaler, currentReplicas, utilization)
+
+	now := am.now()
+	status := api.HorizontalPodAutoscalerStatus{
+		CurrentReplicas:                 currentReplicas,
+		DesiredReplicas:                 desired,
+		CurrentCPUUtilizationPercentage: &utilization,
+		LastScaleTime:                   autoscaler.Status.LastScaleTime,
+	}
+	// Replicas outside of the bounds of the autoscaler are corrected right
+	// away, whatever the stabilization window.
+	outOfBounds := currentReplicas < autoscaler.Spec.MinReplicas || currentReplicas > autoscaler.Spec.MaxReplicas
+	if desired != currentReplicas && (outOfBounds || canRescale(&autoscaler, desired > currentReplicas, now)) {
+		glog.V(2).Infof("Scaling %s/%s from %d to %d replicas, CPU utilization is %d%% for a target of %d%%",
+			rc.Namespace, rc.Name, currentReplicas, desired, utilization, autoscaler.Spec.TargetCPUUtilizationPercentage)
+		rc.Spec.Replicas = desired
+		if _, err := am.kubeClient.ReplicationControllers(rc.Namespace).Update(rc); err != nil {
+			am.recorder.Eventf(&autoscaler, "failedRescale", "Unable to scale %s to %d replicas: %v", rc.Name, desired, err)
+			return fmt.Errorf("unable to scale %s/%s: %v", rc.Namespace, rc.Name, err)
+		}
+		am.recorder.Eventf(&autoscaler, "rescaled", "Scaled %s from %d to %d replicas, CPU utilization is %d%% for a target of %d%%",
+			rc.Name, currentReplicas, desired, utilization, autoscaler.Spec.TargetCPUUtilizationPercentage)
+		status.CurrentReplicas = desired
+		lastScaleTime := util.NewTime(now)
+		status.LastScaleTime = &lastScaleTime
+	}
+
+	if !api.Semantic.DeepEqual(status, autoscaler.Status) {
+		autoscaler.Status = status
+		if _, err := am.kubeClient.HorizontalPodAutoscalers(autoscaler.Namespace).UpdateStatus(&autoscaler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// synchronize syncs all the autoscalers of the cluster.
+func (am *HorizontalPodAutoscalerManager) synchronize() {
+	// TODO: remove this method completely and rely on the watch.
+	// Add resource version tracking to watch to make this work.
+	list, err := am.kubeClient.HorizontalPodAutoscalers(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("synchronization error: %v", err))
+		return
+	}
+	autoscalers := list.Items
+	wg := sync.WaitGroup{}
+	wg.Add(len(autoscalers))
+	for ix := range autoscalers {
+		go func(ix int) {
+			defer wg.Done()
+			glog.V(4).Infof("periodic sync of %v/%v", autoscalers[ix].Namespace, autoscalers[ix].Name)
+			if err := am.syncHandler(autoscalers[ix]); err != nil {
+				util.HandleError(fmt.Errorf("error synchronizing: %v", err))
+			}
+		}(ix)
+	}
+	wg.Wait()
+}
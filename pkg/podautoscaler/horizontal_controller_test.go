@@ -0,0 +1,305 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podautoscaler
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// fakeMetricsClient returns the usage of pods by name.
+type fakeMetricsClient struct {
+	usage map[string]int64
+	err   error
+}
+
+func (c *fakeMetricsClient) GetCPUUsage(pod *api.Pod) (int64, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.usage[pod.Name], nil
+}
+
+// capturingRecorder records the reasons of the events it is given.
+type capturingRecorder struct {
+	reasons []string
+}
+
+func (r *capturingRecorder) Event(object runtime.Object, reason, message string) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func (r *capturingRecorder) Eventf(object runtime.Object, reason, messageFmt string, args ...interface{}) {
+	r.reasons = append(r.reasons, reason)
+}
+
+func (r *capturingRecorder) has(reason string) bool {
+	for _, r := range r.reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+func newAutoscaler(min, max, target int) api.HorizontalPodAutoscaler {
+	return api.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: "frontend-autoscaler", Namespace: api.NamespaceDefault, ResourceVersion: "18"},
+		Spec: api.HorizontalPodAutoscalerSpec{
+			ScaleRef:                       api.LocalObjectReference{Name: "frontend"},
+			MinReplicas:                    min,
+			MaxReplicas:                    max,
+			TargetCPUUtilizationPercentage: target,
+		},
+	}
+}
+
+// newFakeClient returns a client holding a replication controller with the
+// given number of replicas, and as many running pods requesting 100
+// millicores each.
+func newFakeClient(replicas int) *client.Fake {
+	fakeClient := &client.Fake{}
+	fakeClient.Ctrl = api.ReplicationController{
+		ObjectMeta: api.ObjectMeta{Name: "frontend", Namespace: api.NamespaceDefault},
+		Spec: api.ReplicationControllerSpec{
+			Replicas: replicas,
+			Selector: map[string]string{"name": "frontend"},
+		},
+	}
+	for i := 0; i < replicas; i++ {
+		fakeClient.PodsList.Items = append(fakeClient.PodsList.Items, api.Pod{
+			ObjectMeta: api.ObjectMeta{
+				Name:      fmt.Sprintf("frontend-%d", i),
+				Namespace: api.NamespaceDefault,
+				Labels:    map[string]string{"name": "frontend"},
+			},
+			Spec: api.PodSpec{
+				Containers: []api.Container{{
+					Name: "web",
+					Resources: api.ResourceRequirements{
+						Requests: api.ResourceList{api.ResourceCPU: resource.MustParse("100m")},
+					},
+				}},
+			},
+			Status: api.PodStatus{Phase: api.PodRunning},
+		})
+	}
+	return fakeClient
+}
+
+// uniformUsage returns the given usage for each of the pods of the client.
+func uniformUsage(fakeClient *client.Fake, milliCores int64) *fakeMetricsClient {
+	metrics := &fakeMetricsClient{usage: map[string]int64{}}
+	for _, pod := range fakeClient.PodsList.Items {
+		metrics.usage[pod.Name] = milliCores
+	}
+	return metrics
+}
+
+// scaledReplicas returns the number of replicas the replication controller
+// was updated to, or -1 if it was not updated.
+func scaledReplicas(fakeClient *client.Fake) int {
+	for _, action := range fakeClient.Actions {
+		if action.Action == "update-controller" {
+			return action.Value.(*api.ReplicationController).Spec.Replicas
+		}
+	}
+	return -1
+}
+
+func TestSyncAutoscalerScalesUp(t *testing.T) {
+	fakeClient := newFakeClient(2)
+	recorder := &capturingRecorder{}
+	manager := NewHorizontalPodAutoscalerManager(fakeClient, uniformUsage(fakeClient, 90), recorder)
+
+	if err := manager.syncAutoscaler(newAutoscaler(1, 10, 50)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A utilization of 90% for a target of 50% needs 1.8 times as many pods.
+	if replicas := scaledReplicas(fakeClient); replicas != 4 {
+		t.Errorf("expected to scale to 4 replicas, got %d", replicas)
+	}
+	if !recorder.has("rescaled") {
+		t.Errorf("expected a rescaled event, got %v", recorder.reasons)
+	}
+	status := fakeClient.HorizontalPodAutoscalerStatus.Status
+	if status.CurrentReplicas != 4 || status.DesiredReplicas != 4 {
+		t.Errorf("unexpected status: %#v", status)
+	}
+	if status.CurrentCPUUtilizationPercentage == nil || *status.CurrentCPUUtilizationPercentage != 90 {
+		t.Errorf("expected a utilization of 90%%, got %v", status.CurrentCPUUtilizationPercentage)
+	}
+	if status.LastScaleTime == nil {
+		t.Errorf("expected the scale time to be recorded")
+	}
+}
+
+func TestSyncAutoscalerRespectsMaxReplicas(t *testing.T) {
+	fakeClient := newFakeClient(2)
+	manager := NewHorizontalPodAutoscalerManager(fakeClient, uniformUsage(fakeClient, 200), &capturingRecorder{})
+
+	if err := manager.syncAutoscaler(newAutoscaler(1, 3, 50)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replicas := scaledReplicas(fakeClient); replicas != 3 {
+		t.Errorf("expected to scale to 3 replicas, got %d", replicas)
+	}
+}
+
+func TestSyncAutoscalerWithinTolerance(t *testing.T) {
+	fakeClient := newFakeClient(3)
+	recorder := &capturingRecorder{}
+	manager := NewHorizontalPodAutoscalerManager(fakeClient, uniformUsage(fakeClient, 53), recorder)
+
+	if err := manager.syncAutoscaler(newAutoscaler(1, 10, 50)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replicas := scaledReplicas(fakeClient); replicas != -1 {
+		t.Errorf("expected no rescale, got %d replicas", replicas)
+	}
+	if len(recorder.reasons) != 0 {
+		t.Errorf("unexpected events: %v", recorder.reasons)
+	}
+	status := fakeClient.HorizontalPodAutoscalerStatus.Status
+	if status.CurrentReplicas != 3 || status.DesiredReplicas != 3 || status.LastScaleTime != nil {
+		t.Errorf("unexpected status: %#v", status)
+	}
+}
+
+func TestSyncAutoscalerStabilizationWindow(t *testing.T) {
+	now := time.Now()
+	table := []struct {
+		usage        int64
+		sinceLast    time.Duration
+		expectScaled bool
+	}{
+		// Scale up.
+		{usage: 100, sinceLast: time.Minute, expectScaled: false},
+		{usage: 100, sinceLast: 4 * time.Minute, expectScaled: true},
+		// Scale down.
+		{usage: 10, sinceLast: 4 * time.Minute, expectScaled: false},
+		{usage: 10, sinceLast: 6 * time.Minute, expectScaled: true},
+	}
+	for i, item := range table {
+		fakeClient := newFakeClient(4)
+		manager := NewHorizontalPodAutoscalerManager(fakeClient, uniformUsage(fakeClient, item.usage), &capturingRecorder{})
+		manager.now = func() time.Time { return now }
+
+		autoscaler := newAutoscaler(1, 10, 50)
+		lastScaleTime := util.NewTime(now.Add(-item.sinceLast))
+		autoscaler.Status.LastScaleTime = &lastScaleTime
+		if err := manager.syncAutoscaler(autoscaler); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if scaled := scaledReplicas(fakeClient) != -1; scaled != item.expectScaled {
+			t.Errorf("%d: expected rescale %v, got %v", i, item.expectScaled, scaled)
+		}
+	}
+}
+
+func TestSyncAutoscalerCorrectsOutOfBoundsReplicas(t *testing.T) {
+	now := time.Now()
+	fakeClient := newFakeClient(6)
+	manager := NewHorizontalPodAutoscalerManager(fakeClient, uniformUsage(fakeClient, 50), &capturingRecorder{})
+	manager.now = func() time.Time { return now }
+
+	autoscaler := newAutoscaler(1, 4, 50)
+	lastScaleTime := util.NewTime(now.Add(-time.Minute))
+	autoscaler.Status.LastScaleTime = &lastScaleTime
+	if err := manager.syncAutoscaler(autoscaler); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replicas := scaledReplicas(fakeClient); replicas != 4 {
+		t.Errorf("expected to scale to 4 replicas, got %d", replicas)
+	}
+}
+
+func TestSyncAutoscalerMetricsFailure(t *testing.T) {
+	fakeClient := newFakeClient(2)
+	recorder := &capturingRecorder{}
+	manager := NewHorizontalPodAutoscalerManager(fakeClient, &fakeMetricsClient{err: fmt.Errorf("no stats")}, recorder)
+
+	if err := manager.syncAutoscaler(newAutoscaler(1, 10, 50)); err == nil {
+		t.Errorf("expected an error")
+	}
+	if replicas := scaledReplicas(fakeClient); replicas != -1 {
+		t.Errorf("expected no rescale, got %d replicas", replicas)
+	}
+	if !recorder.has("failedGetMetrics") {
+		t.Errorf("expected a failedGetMetrics event, got %v", recorder.reasons)
+	}
+}
+
+func TestSyncAutoscalerRequiresCPURequest(t *testing.T) {
+	fakeClient := newFakeClient(2)
+	fakeClient.PodsList.Items[1].Spec.Containers[0].Resources = api.ResourceRequirements{}
+	recorder := &capturingRecorder{}
+	manager := NewHorizontalPodAutoscalerManager(fakeClient, uniformUsage(fakeClient, 90), recorder)
+
+	if err := manager.syncAutoscaler(newAutoscaler(1, 10, 50)); err == nil {
+		t.Errorf("expected an error")
+	}
+	if !recorder.has("failedGetMetrics") {
+		t.Errorf("expected a failedGetMetrics event, got %v", recorder.reasons)
+	}
+}
+
+func TestWatchAutoscalersSyncsOnSpecChanges(t *testing.T) {
+	fakeWatch := watch.NewFake()
+	manager := NewHorizontalPodAutoscalerManager(&client.Fake{Watch: fakeWatch}, &fakeMetricsClient{}, &capturingRecorder{})
+	synced := []int{}
+	manager.syncHandler = func(autoscaler api.HorizontalPodAutoscaler) error {
+		synced = append(synced, autoscaler.Spec.MaxReplicas)
+		return nil
+	}
+	resourceVersion := ""
+	done := make(chan struct{})
+	go func() {
+		manager.watchAutoscalers(&resourceVersion)
+		close(done)
+	}()
+
+	autoscaler := newAutoscaler(1, 10, 50)
+	fakeWatch.Add(&autoscaler)
+	// A status update, such as the one following a sync, is left to the
+	// periodic sync.
+	utilization := 90
+	updated := autoscaler
+	updated.Status.CurrentCPUUtilizationPercentage = &utilization
+	fakeWatch.Modify(&updated)
+	resized := updated
+	resized.Spec.MaxReplicas = 20
+	fakeWatch.Modify(&resized)
+	fakeWatch.Delete(&resized)
+	// A recreated autoscaler is synced again.
+	fakeWatch.Add(&autoscaler)
+	fakeWatch.Stop()
+	<-done
+
+	if expected := []int{10, 20, 10}; !reflect.DeepEqual(synced, expected) {
+		t.Errorf("expected syncs for autoscalers with %v max replicas, got %v", expected, synced)
+	}
+}
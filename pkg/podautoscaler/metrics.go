@@ -0,0 +1,138 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podautoscaler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/cnaize/kubernetes/pkg/api"
+
+	cadvisorApi "github.com/google/cadvisor/info/v1"
+)
+
+// statsSamples is the number of cadvisor samples the CPU usage of a container
+// is averaged over.
+const statsSamples = 60
+
+// MetricsClient provides the resource usage of running pods.
+type MetricsClient interface {
+	// GetCPUUsage returns the recent CPU usage of all the containers of the
+	// given pod, in millicores.
+	GetCPUUsage(pod *api.Pod) (int64, error)
+}
+
+// kubeletMetricsClient reads the usage of containers from the cadvisor-backed
+// stats endpoint of the kubelet running them.
+type kubeletMetricsClient struct {
+	connection client.ConnectionInfoGetter
+}
+
+// NewKubeletMetricsClient returns a MetricsClient which queries the kubelets
+// reached through the given connection information.
+func NewKubeletMetricsClient(connection client.ConnectionInfoGetter) MetricsClient {
+	return &kubeletMetricsClient{connection: connection}
+}
+
+func (c *kubeletMetricsClient) GetCPUUsage(pod *api.Pod) (int64, error) {
+	host := pod.Spec.Host
+	if len(host) == 0 {
+		return 0, fmt.Errorf("pod %s/%s is not bound to a node", pod.Namespace, pod.Name)
+	}
+	scheme, port, transport, err := c.connection.GetConnectionInfo(host)
+	if err != nil {
+		return 0, err
+	}
+	httpClient := &http.Client{Transport: transport}
+	total := int64(0)
+	for _, container := range pod.Spec.Containers {
+		statsURL := &url.URL{
+			Scheme: scheme,
+			Host:   net.JoinHostPort(host, strconv.FormatUint(uint64(port), 10)),
+			Path:   path.Join("/stats", pod.Namespace, pod.Name, string(pod.UID), container.Name),
+		}
+		info, err := getContainerInfo(httpClient, statsURL.String())
+		if err != nil {
+			return 0, fmt.Errorf("unable to get stats of container %s of pod %s/%s: %v", container.Name, pod.Namespace, pod.Name, err)
+		}
+		usage, err := cpuUsage(info)
+		if err != nil {
+			return 0, fmt.Errorf("unable to compute CPU usage of container %s of pod %s/%s: %v", container.Name, pod.Namespace, pod.Name, err)
+		}
+		total += usage
+	}
+	return total, nil
+}
+
+// getContainerInfo requests the latest samples of a container from the given
+// stats URL.
+func getContainerInfo(httpClient *http.Client, statsURL string) (*cadvisorApi.ContainerInfo, error) {
+	body, err := json.Marshal(cadvisorApi.ContainerInfoRequest{NumStats: statsSamples})
+	if err != nil {
+		return nil, err
+	}
+	request, err := http.NewRequest("GET", statsURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kubelet responded with HTTP status %s", response.Status)
+	}
+	var info cadvisorApi.ContainerInfo
+	if err := json.NewDecoder(response.Body).Decode(&info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// cpuUsage returns the average CPU usage, in millicores, between the oldest
+// and the newest samples of the given container.
+func cpuUsage(info *cadvisorApi.ContainerInfo) (int64, error) {
+	var oldest, newest *cadvisorApi.ContainerStats
+	for _, stats := range info.Stats {
+		if stats == nil {
+			continue
+		}
+		if oldest == nil || stats.Timestamp.Before(oldest.Timestamp) {
+			oldest = stats
+		}
+		if newest == nil || stats.Timestamp.After(newest.Timestamp) {
+			newest = stats
+		}
+	}
+	if oldest == nil || !newest.Timestamp.After(oldest.Timestamp) {
+		return 0, fmt.Errorf("not enough samples")
+	}
+	if newest.Cpu.Usage.Total < oldest.Cpu.Usage.Total {
+		return 0, fmt.Errorf("CPU usage counter went backwards")
+	}
+	elapsed := uint64(newest.Timestamp.Sub(oldest.Timestamp).Nanoseconds())
+	// The usage counter is in nanoseconds of CPU time.
+	return int64((newest.Cpu.Usage.Total - oldest.Cpu.Usage.Total) * 1000 / elapsed), nil
+}
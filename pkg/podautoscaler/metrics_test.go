@@ -0,0 +1,121 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package podautoscaler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+
+	cadvisorApi "github.com/google/cadvisor/info/v1"
+)
+
+// fakeConnectionInfoGetter points all the hosts at the same port.
+type fakeConnectionInfoGetter struct {
+	port uint
+}
+
+func (f fakeConnectionInfoGetter) GetConnectionInfo(host string) (string, uint, http.RoundTripper, error) {
+	return "http", f.port, http.DefaultTransport, nil
+}
+
+func newContainerInfo(start time.Time, usages ...uint64) *cadvisorApi.ContainerInfo {
+	info := &cadvisorApi.ContainerInfo{}
+	for i, usage := range usages {
+		stats := &cadvisorApi.ContainerStats{Timestamp: start.Add(time.Duration(i) * time.Second)}
+		stats.Cpu.Usage.Total = usage
+		info.Stats = append(info.Stats, stats)
+	}
+	return info
+}
+
+func TestCPUUsage(t *testing.T) {
+	start := time.Now()
+	// Half a core over two seconds.
+	usage, err := cpuUsage(newContainerInfo(start, 0, 400000000, 1000000000))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage != 500 {
+		t.Errorf("expected 500 millicores, got %d", usage)
+	}
+
+	// Samples out of order.
+	info := newContainerInfo(start, 0, 1000000000)
+	info.Stats[0], info.Stats[1] = info.Stats[1], info.Stats[0]
+	if usage, err := cpuUsage(info); err != nil || usage != 1000 {
+		t.Errorf("expected 1000 millicores, got %d (%v)", usage, err)
+	}
+
+	if _, err := cpuUsage(newContainerInfo(start, 10)); err == nil {
+		t.Errorf("expected an error with a single sample")
+	}
+	if _, err := cpuUsage(newContainerInfo(start, 10, 5)); err == nil {
+		t.Errorf("expected an error with a decreasing counter")
+	}
+}
+
+func TestKubeletMetricsClient(t *testing.T) {
+	start := time.Now()
+	paths := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		paths = append(paths, req.URL.Path)
+		query := cadvisorApi.ContainerInfoRequest{}
+		if err := json.NewDecoder(req.Body).Decode(&query); err != nil || query.NumStats != statsSamples {
+			t.Errorf("unexpected request: %#v (%v)", query, err)
+		}
+		json.NewEncoder(w).Encode(newContainerInfo(start, 0, 250000000))
+	}))
+	defer server.Close()
+
+	host, portString, err := net.SplitHostPort(server.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	port, _ := strconv.Atoi(portString)
+	metricsClient := NewKubeletMetricsClient(fakeConnectionInfoGetter{uint(port)})
+
+	pod := &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar", UID: "1234"},
+		Spec: api.PodSpec{
+			Host:       host,
+			Containers: []api.Container{{Name: "a"}, {Name: "b"}},
+		},
+	}
+	usage, err := metricsClient.GetCPUUsage(pod)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if usage != 500 {
+		t.Errorf("expected 500 millicores, got %d", usage)
+	}
+	if e, a := fmt.Sprint([]string{"/stats/bar/foo/1234/a", "/stats/bar/foo/1234/b"}), fmt.Sprint(paths); e != a {
+		t.Errorf("expected requests to %s, got %s", e, a)
+	}
+
+	pod.Spec.Host = ""
+	if _, err := metricsClient.GetCPUUsage(pod); err == nil {
+		t.Errorf("expected an error for an unbound pod")
+	}
+}
@@ -37,6 +37,7 @@ import (
 type serviceInfo struct {
 	portalIP   net.IP
 	portalPort int
+	nodePort   int
 	protocol   api.Protocol
 	proxyPort  int
 	socket     proxySocket
@@ -59,7 +60,7 @@ type proxySocket interface {
 	// while sessions are active.
 	Close() error
 	// ProxyLoop proxies incoming connections for the specified service to the service endpoints.
-	ProxyLoop(service types.NamespacedName, info *serviceInfo, proxier *Proxier)
+	ProxyLoop(service servicePort, info *serviceInfo, proxier *Proxier)
 }
 
 // tcpProxySocket implements proxySocket.  Close() is implemented by net.Listener.  When Close() is called,
@@ -68,10 +69,9 @@ type tcpProxySocket struct {
 	net.Listener
 }
 
-func tryConnect(service types.NamespacedName, srcAddr net.Addr, protocol string, proxier *Proxier) (out net.Conn, err error) {
+func tryConnect(service servicePort, srcAddr net.Addr, protocol string, proxier *Proxier) (out net.Conn, err error) {
 	for _, retryTimeout := range endpointDialTimeout {
-		// TODO: support multiple service ports
-		endpoint, err := proxier.loadBalancer.NextEndpoint(service, "", srcAddr)
+		endpoint, err := proxier.loadBalancer.NextEndpoint(service.NamespacedName, service.port, srcAddr)
 		if err != nil {
 			glog.Errorf("Couldn't find an endpoint for %s: %v", service, err)
 			return nil, err
@@ -89,7 +89,7 @@ func tryConnect(service types.NamespacedName, srcAddr net.Addr, protocol string,
 	return nil, fmt.Errorf("failed to connect to an endpoint.")
 }
 
-func (tcp *tcpProxySocket) ProxyLoop(service types.NamespacedName, myInfo *serviceInfo, proxier *Proxier) {
+func (tcp *tcpProxySocket) ProxyLoop(service servicePort, myInfo *serviceInfo, proxier *Proxier) {
 	for {
 		if info, exists := proxier.getServiceInfo(service); !exists || info != myInfo {
 			// The service port was closed or replaced.
@@ -164,7 +164,7 @@ func newClientCache() *clientCache {
 	return &clientCache{clients: map[string]net.Conn{}}
 }
 
-func (udp *udpProxySocket) ProxyLoop(service types.NamespacedName, myInfo *serviceInfo, proxier *Proxier) {
+func (udp *udpProxySocket) ProxyLoop(service servicePort, myInfo *serviceInfo, proxier *Proxier) {
 	activeClients := newClientCache()
 	var buffer [4096]byte // 4KiB should be enough for most whole-packets
 	for {
@@ -209,7 +209,7 @@ func (udp *udpProxySocket) ProxyLoop(service types.NamespacedName, myInfo *servi
 	}
 }
 
-func (udp *udpProxySocket) getBackendConn(activeClients *clientCache, cliAddr net.Addr, proxier *Proxier, service types.NamespacedName, timeout time.Duration) (net.Conn, error) {
+func (udp *udpProxySocket) getBackendConn(activeClients *clientCache, cliAddr net.Addr, proxier *Proxier, service servicePort, timeout time.Duration) (net.Conn, error) {
 	activeClients.mu.Lock()
 	defer activeClients.mu.Unlock()
 
@@ -305,7 +305,7 @@ func newProxySocket(protocol api.Protocol, ip net.IP, port int) (proxySocket, er
 type Proxier struct {
 	loadBalancer  LoadBalancer
 	mu            sync.Mutex // protects serviceMap
-	serviceMap    map[types.NamespacedName]*serviceInfo
+	serviceMap    map[servicePort]*serviceInfo
 	numProxyLoops int32 // use atomic ops to access this; mostly for testing
 	listenIP      net.IP
 	iptables      iptables.Interface
@@ -347,7 +347,7 @@ func CreateProxier(loadBalancer LoadBalancer, listenIP net.IP, iptables iptables
 	}
 	return &Proxier{
 		loadBalancer: loadBalancer,
-		serviceMap:   make(map[types.NamespacedName]*serviceInfo),
+		serviceMap:   make(map[servicePort]*serviceInfo),
 		listenIP:     listenIP,
 		iptables:     iptables,
 		hostIP:       hostIP,
@@ -389,33 +389,32 @@ func (proxier *Proxier) ensurePortals() {
 func (proxier *Proxier) cleanupStaleStickySessions() {
 	for name, info := range proxier.serviceMap {
 		if info.sessionAffinityType != api.AffinityTypeNone {
-			// TODO: support multiple service ports
-			proxier.loadBalancer.CleanupStaleStickySessions(name, "")
+			proxier.loadBalancer.CleanupStaleStickySessions(name.NamespacedName, name.port)
 		}
 	}
 }
 
 // This assumes proxier.mu is not locked.
-func (proxier *Proxier) stopProxy(service types.NamespacedName, info *serviceInfo) error {
+func (proxier *Proxier) stopProxy(service servicePort, info *serviceInfo) error {
 	proxier.mu.Lock()
 	defer proxier.mu.Unlock()
 	return proxier.stopProxyInternal(service, info)
 }
 
 // This assumes proxier.mu is locked.
-func (proxier *Proxier) stopProxyInternal(service types.NamespacedName, info *serviceInfo) error {
+func (proxier *Proxier) stopProxyInternal(service servicePort, info *serviceInfo) error {
 	delete(proxier.serviceMap, service)
 	return info.socket.Close()
 }
 
-func (proxier *Proxier) getServiceInfo(service types.NamespacedName) (*serviceInfo, bool) {
+func (proxier *Proxier) getServiceInfo(service servicePort) (*serviceInfo, bool) {
 	proxier.mu.Lock()
 	defer proxier.mu.Unlock()
 	info, ok := proxier.serviceMap[service]
 	return info, ok
 }
 
-func (proxier *Proxier) setServiceInfo(service types.NamespacedName, info *serviceInfo) {
+func (proxier *Proxier) setServiceInfo(service servicePort, info *serviceInfo) {
 	proxier.mu.Lock()
 	defer proxier.mu.Unlock()
 	proxier.serviceMap[service] = info
@@ -424,7 +423,7 @@ func (proxier *Proxier) setServiceInfo(service types.NamespacedName, info *servi
 // addServiceOnPort starts listening for a new service, returning the serviceInfo.
 // Pass proxyPort=0 to allocate a random port. The timeout only applies to UDP
 // connections, for now.
-func (proxier *Proxier) addServiceOnPort(service types.NamespacedName, protocol api.Protocol, proxyPort int, timeout time.Duration) (*serviceInfo, error) {
+func (proxier *Proxier) addServiceOnPort(service servicePort, protocol api.Protocol, proxyPort int, timeout time.Duration) (*serviceInfo, error) {
 	sock, err := newProxySocket(protocol, proxier.listenIP, proxyPort)
 	if err != nil {
 		return nil, err
@@ -450,7 +449,7 @@ func (proxier *Proxier) addServiceOnPort(service types.NamespacedName, protocol
 	proxier.setServiceInfo(service, si)
 
 	glog.V(1).Infof("Proxying for service %q on %s port %d", service, protocol, portNum)
-	go func(service types.NamespacedName, proxier *Proxier) {
+	go func(service servicePort, proxier *Proxier) {
 		defer util.HandleCrash()
 		atomic.AddInt32(&proxier.numProxyLoops, 1)
 		sock.ProxyLoop(service, si, proxier)
@@ -468,51 +467,58 @@ const udpIdleTimeout = 1 * time.Minute
 // shutdown if missing from the update set.
 func (proxier *Proxier) OnUpdate(services []api.Service) {
 	glog.V(4).Infof("Received update notice: %+v", services)
-	activeServices := make(map[types.NamespacedName]bool) // use a map as a set
-	for _, service := range services {
+	activeServices := make(map[servicePort]bool) // use a map as a set
+	for i := range services {
+		service := &services[i]
+
 		// if PortalIP is "None" or empty, skip proxying
-		if !api.IsServiceIPSet(&service) {
-			continue
-		}
-		serviceName := types.NamespacedName{service.Namespace, service.Name}
-		activeServices[serviceName] = true
-		info, exists := proxier.getServiceInfo(serviceName)
-		serviceIP := net.ParseIP(service.Spec.PortalIP)
-		// TODO: check health of the socket?  What if ProxyLoop exited?
-		if exists && info.portalPort == service.Spec.Port && info.portalIP.Equal(serviceIP) {
+		if !api.IsServiceIPSet(service) {
 			continue
 		}
-		if exists && (info.portalPort != service.Spec.Port || !info.portalIP.Equal(serviceIP) || !ipsEqual(service.Spec.PublicIPs, info.publicIP)) {
-			glog.V(4).Infof("Something changed for service %q: stopping it", serviceName.String())
-			err := proxier.closePortal(serviceName, info)
+
+		for i := range service.Spec.Ports {
+			port := &service.Spec.Ports[i]
+
+			serviceName := servicePort{types.NamespacedName{service.Namespace, service.Name}, port.Name}
+			activeServices[serviceName] = true
+			info, exists := proxier.getServiceInfo(serviceName)
+			serviceIP := net.ParseIP(service.Spec.PortalIP)
+			// TODO: check health of the socket?  What if ProxyLoop exited?
+			if exists && info.portalPort == port.Port && info.portalIP.Equal(serviceIP) && info.nodePort == port.NodePort {
+				continue
+			}
+			if exists && (info.portalPort != port.Port || !info.portalIP.Equal(serviceIP) || !ipsEqual(service.Spec.PublicIPs, info.publicIP) || info.nodePort != port.NodePort) {
+				glog.V(4).Infof("Something changed for service %q: stopping it", serviceName)
+				err := proxier.closePortal(serviceName, info)
+				if err != nil {
+					glog.Errorf("Failed to close portal for %q: %v", serviceName, err)
+				}
+				err = proxier.stopProxy(serviceName, info)
+				if err != nil {
+					glog.Errorf("Failed to stop service %q: %v", serviceName, err)
+				}
+			}
+			glog.V(1).Infof("Adding new service %q at %s:%d/%s", serviceName, serviceIP, port.Port, port.Protocol)
+			info, err := proxier.addServiceOnPort(serviceName, port.Protocol, 0, udpIdleTimeout)
 			if err != nil {
-				glog.Errorf("Failed to close portal for %q: %v", serviceName, err)
+				glog.Errorf("Failed to start proxy for %q: %v", serviceName, err)
+				continue
 			}
-			err = proxier.stopProxy(serviceName, info)
+			info.portalIP = serviceIP
+			info.portalPort = port.Port
+			info.nodePort = port.NodePort
+			info.publicIP = service.Spec.PublicIPs
+			info.sessionAffinityType = service.Spec.SessionAffinity
+			// TODO: paramaterize this in the types api file as an attribute of sticky session.   For now it's hardcoded to 3 hours.
+			info.stickyMaxAgeMinutes = 180
+			glog.V(4).Infof("info: %+v", info)
+
+			err = proxier.openPortal(serviceName, info)
 			if err != nil {
-				glog.Errorf("Failed to stop service %q: %v", serviceName, err)
+				glog.Errorf("Failed to open portal for %q: %v", serviceName, err)
 			}
+			proxier.loadBalancer.NewService(serviceName.NamespacedName, serviceName.port, info.sessionAffinityType, info.stickyMaxAgeMinutes)
 		}
-		glog.V(1).Infof("Adding new service %q at %s:%d/%s", serviceName, serviceIP, service.Spec.Port, service.Spec.Protocol)
-		info, err := proxier.addServiceOnPort(serviceName, service.Spec.Protocol, 0, udpIdleTimeout)
-		if err != nil {
-			glog.Errorf("Failed to start proxy for %q: %v", serviceName, err)
-			continue
-		}
-		info.portalIP = serviceIP
-		info.portalPort = service.Spec.Port
-		info.publicIP = service.Spec.PublicIPs
-		info.sessionAffinityType = service.Spec.SessionAffinity
-		// TODO: paramaterize this in the types api file as an attribute of sticky session.   For now it's hardcoded to 3 hours.
-		info.stickyMaxAgeMinutes = 180
-		glog.V(4).Infof("info: %+v", info)
-
-		err = proxier.openPortal(serviceName, info)
-		if err != nil {
-			glog.Errorf("Failed to open portal for %q: %v", serviceName, err)
-		}
-		// TODO: support multiple service ports
-		proxier.loadBalancer.NewService(serviceName, "", info.sessionAffinityType, info.stickyMaxAgeMinutes)
 	}
 	proxier.mu.Lock()
 	defer proxier.mu.Unlock()
@@ -543,7 +549,7 @@ func ipsEqual(lhs, rhs []string) bool {
 	return true
 }
 
-func (proxier *Proxier) openPortal(service types.NamespacedName, info *serviceInfo) error {
+func (proxier *Proxier) openPortal(service servicePort, info *serviceInfo) error {
 	err := proxier.openOnePortal(info.portalIP, info.portalPort, info.protocol, proxier.listenIP, info.proxyPort, service)
 	if err != nil {
 		return err
@@ -554,10 +560,16 @@ func (proxier *Proxier) openPortal(service types.NamespacedName, info *serviceIn
 			return err
 		}
 	}
+	if info.nodePort != 0 {
+		err = proxier.openNodePort(info.nodePort, info.protocol, proxier.listenIP, info.proxyPort, service)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (proxier *Proxier) openOnePortal(portalIP net.IP, portalPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, name types.NamespacedName) error {
+func (proxier *Proxier) openOnePortal(portalIP net.IP, portalPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, name servicePort) error {
 	// Handle traffic from containers.
 	args := proxier.iptablesContainerPortalArgs(portalIP, portalPort, protocol, proxyIP, proxyPort, name)
 	existed, err := proxier.iptables.EnsureRule(iptables.TableNAT, iptablesContainerPortalChain, args...)
@@ -582,12 +594,43 @@ func (proxier *Proxier) openOnePortal(portalIP net.IP, portalPort int, protocol
 	return nil
 }
 
-func (proxier *Proxier) closePortal(service types.NamespacedName, info *serviceInfo) error {
+// openNodePort forwards traffic sent to nodePort on any local address to the
+// proxy.  The jump rules installed by iptablesInit only send locally-destined
+// traffic to the node port chains.
+func (proxier *Proxier) openNodePort(nodePort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, name servicePort) error {
+	// Handle traffic from containers and other hosts.
+	args := proxier.iptablesContainerPortalArgs(nil, nodePort, protocol, proxyIP, proxyPort, name)
+	existed, err := proxier.iptables.EnsureRule(iptables.TableNAT, iptablesContainerNodePortChain, args...)
+	if err != nil {
+		glog.Errorf("Failed to install iptables %s rule for service %q", iptablesContainerNodePortChain, name)
+		return err
+	}
+	if !existed {
+		glog.Infof("Opened iptables from-containers node port for service %q on %s port %d", name, protocol, nodePort)
+	}
+
+	// Handle traffic from the host.
+	args = proxier.iptablesHostPortalArgs(nil, nodePort, protocol, proxyIP, proxyPort, name)
+	existed, err = proxier.iptables.EnsureRule(iptables.TableNAT, iptablesHostNodePortChain, args...)
+	if err != nil {
+		glog.Errorf("Failed to install iptables %s rule for service %q", iptablesHostNodePortChain, name)
+		return err
+	}
+	if !existed {
+		glog.Infof("Opened iptables from-host node port for service %q on %s port %d", name, protocol, nodePort)
+	}
+	return nil
+}
+
+func (proxier *Proxier) closePortal(service servicePort, info *serviceInfo) error {
 	// Collect errors and report them all at the end.
 	el := proxier.closeOnePortal(info.portalIP, info.portalPort, info.protocol, proxier.listenIP, info.proxyPort, service)
 	for _, publicIP := range info.publicIP {
 		el = append(el, proxier.closeOnePortal(net.ParseIP(publicIP), info.portalPort, info.protocol, proxier.listenIP, info.proxyPort, service)...)
 	}
+	if info.nodePort != 0 {
+		el = append(el, proxier.closeNodePort(info.nodePort, info.protocol, proxier.listenIP, info.proxyPort, service)...)
+	}
 	if len(el) == 0 {
 		glog.Infof("Closed iptables portals for service %q", service)
 	} else {
@@ -596,7 +639,7 @@ func (proxier *Proxier) closePortal(service types.NamespacedName, info *serviceI
 	return errors.NewAggregate(el)
 }
 
-func (proxier *Proxier) closeOnePortal(portalIP net.IP, portalPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, name types.NamespacedName) []error {
+func (proxier *Proxier) closeOnePortal(portalIP net.IP, portalPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, name servicePort) []error {
 	el := []error{}
 
 	// Handle traffic from containers.
@@ -616,10 +659,32 @@ func (proxier *Proxier) closeOnePortal(portalIP net.IP, portalPort int, protocol
 	return el
 }
 
+func (proxier *Proxier) closeNodePort(nodePort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, name servicePort) []error {
+	el := []error{}
+
+	// Handle traffic from containers and other hosts.
+	args := proxier.iptablesContainerPortalArgs(nil, nodePort, protocol, proxyIP, proxyPort, name)
+	if err := proxier.iptables.DeleteRule(iptables.TableNAT, iptablesContainerNodePortChain, args...); err != nil {
+		glog.Errorf("Failed to delete iptables %s rule for service %q", iptablesContainerNodePortChain, name)
+		el = append(el, err)
+	}
+
+	// Handle traffic from the host.
+	args = proxier.iptablesHostPortalArgs(nil, nodePort, protocol, proxyIP, proxyPort, name)
+	if err := proxier.iptables.DeleteRule(iptables.TableNAT, iptablesHostNodePortChain, args...); err != nil {
+		glog.Errorf("Failed to delete iptables %s rule for service %q", iptablesHostNodePortChain, name)
+		el = append(el, err)
+	}
+
+	return el
+}
+
 // See comments in the *PortalArgs() functions for some details about why we
 // use two chains.
 var iptablesContainerPortalChain iptables.Chain = "KUBE-PORTALS-CONTAINER"
 var iptablesHostPortalChain iptables.Chain = "KUBE-PORTALS-HOST"
+var iptablesContainerNodePortChain iptables.Chain = "KUBE-NODEPORT-CONTAINER"
+var iptablesHostNodePortChain iptables.Chain = "KUBE-NODEPORT-HOST"
 var iptablesOldPortalChain iptables.Chain = "KUBE-PROXY"
 
 // Ensure that the iptables infrastructure we use is set up.  This can safely be called periodically.
@@ -639,6 +704,21 @@ func iptablesInit(ipt iptables.Interface) error {
 	if _, err := ipt.EnsureRule(iptables.TableNAT, iptables.ChainOutput, "-j", string(iptablesHostPortalChain)); err != nil {
 		return err
 	}
+
+	// Node ports are matched on any local address, so these must come after
+	// the portal rules above.
+	if _, err := ipt.EnsureChain(iptables.TableNAT, iptablesContainerNodePortChain); err != nil {
+		return err
+	}
+	if _, err := ipt.EnsureRule(iptables.TableNAT, iptables.ChainPrerouting, "-m", "addrtype", "--dst-type", "LOCAL", "-j", string(iptablesContainerNodePortChain)); err != nil {
+		return err
+	}
+	if _, err := ipt.EnsureChain(iptables.TableNAT, iptablesHostNodePortChain); err != nil {
+		return err
+	}
+	if _, err := ipt.EnsureRule(iptables.TableNAT, iptables.ChainOutput, "-m", "addrtype", "--dst-type", "LOCAL", "-j", string(iptablesHostNodePortChain)); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -661,6 +741,12 @@ func iptablesFlush(ipt iptables.Interface) error {
 	if err := ipt.FlushChain(iptables.TableNAT, iptablesHostPortalChain); err != nil {
 		el = append(el, err)
 	}
+	if err := ipt.FlushChain(iptables.TableNAT, iptablesContainerNodePortChain); err != nil {
+		el = append(el, err)
+	}
+	if err := ipt.FlushChain(iptables.TableNAT, iptablesHostNodePortChain); err != nil {
+		el = append(el, err)
+	}
 	if len(el) != 0 {
 		glog.Errorf("Some errors flushing old iptables portals: %v", el)
 	}
@@ -675,7 +761,8 @@ var zeroIPv6 = net.ParseIP("::0")
 var localhostIPv6 = net.ParseIP("::1")
 
 // Build a slice of iptables args that are common to from-container and from-host portal rules.
-func iptablesCommonPortalArgs(destIP net.IP, destPort int, protocol api.Protocol, service types.NamespacedName) []string {
+// A nil destIP matches any destination address, which is what node port rules want.
+func iptablesCommonPortalArgs(destIP net.IP, destPort int, protocol api.Protocol, service servicePort) []string {
 	// This list needs to include all fields as they are eventually spit out
 	// by iptables-save.  This is because some systems do not support the
 	// 'iptables -C' arg, and so fall back on parsing iptables-save output.
@@ -689,14 +776,16 @@ func iptablesCommonPortalArgs(destIP net.IP, destPort int, protocol api.Protocol
 		"--comment", service.String(),
 		"-p", strings.ToLower(string(protocol)),
 		"-m", strings.ToLower(string(protocol)),
-		"-d", fmt.Sprintf("%s/32", destIP.String()),
-		"--dport", fmt.Sprintf("%d", destPort),
 	}
+	if destIP != nil {
+		args = append(args, "-d", fmt.Sprintf("%s/32", destIP.String()))
+	}
+	args = append(args, "--dport", fmt.Sprintf("%d", destPort))
 	return args
 }
 
 // Build a slice of iptables args for a from-container portal rule.
-func (proxier *Proxier) iptablesContainerPortalArgs(destIP net.IP, destPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, service types.NamespacedName) []string {
+func (proxier *Proxier) iptablesContainerPortalArgs(destIP net.IP, destPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, service servicePort) []string {
 	args := iptablesCommonPortalArgs(destIP, destPort, protocol, service)
 
 	// This is tricky.
@@ -743,7 +832,7 @@ func (proxier *Proxier) iptablesContainerPortalArgs(destIP net.IP, destPort int,
 }
 
 // Build a slice of iptables args for a from-host portal rule.
-func (proxier *Proxier) iptablesHostPortalArgs(destIP net.IP, destPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, service types.NamespacedName) []string {
+func (proxier *Proxier) iptablesHostPortalArgs(destIP net.IP, destPort int, protocol api.Protocol, proxyIP net.IP, proxyPort int, service servicePort) []string {
 	args := iptablesCommonPortalArgs(destIP, destPort, protocol, service)
 
 	// This is tricky.
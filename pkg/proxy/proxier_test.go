@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"reflect"
 	"strconv"
 	"sync/atomic"
 	"testing"
@@ -195,13 +196,13 @@ func waitForNumProxyLoops(t *testing.T, p *Proxier, want int32) {
 
 func TestTCPProxy(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: tcpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: tcpServerPort}},
 			}},
 		},
 	})
@@ -219,13 +220,13 @@ func TestTCPProxy(t *testing.T) {
 
 func TestUDPProxy(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: udpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: udpServerPort}},
 			}},
 		},
 	})
@@ -242,7 +243,7 @@ func TestUDPProxy(t *testing.T) {
 }
 
 // Helper: Stops the proxy for the named service.
-func stopProxyByName(proxier *Proxier, service types.NamespacedName) error {
+func stopProxyByName(proxier *Proxier, service servicePort) error {
 	info, found := proxier.getServiceInfo(service)
 	if !found {
 		return fmt.Errorf("unknown service: %s", service)
@@ -252,13 +253,13 @@ func stopProxyByName(proxier *Proxier, service types.NamespacedName) error {
 
 func TestTCPProxyStop(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Namespace: service.Namespace, Name: service.Name},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: tcpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: tcpServerPort}},
 			}},
 		},
 	})
@@ -287,13 +288,13 @@ func TestTCPProxyStop(t *testing.T) {
 
 func TestUDPProxyStop(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Namespace: service.Namespace, Name: service.Name},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: udpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: udpServerPort}},
 			}},
 		},
 	})
@@ -322,13 +323,13 @@ func TestUDPProxyStop(t *testing.T) {
 
 func TestTCPProxyUpdateDelete(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Namespace: service.Namespace, Name: service.Name},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: tcpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: tcpServerPort}},
 			}},
 		},
 	})
@@ -356,13 +357,13 @@ func TestTCPProxyUpdateDelete(t *testing.T) {
 
 func TestUDPProxyUpdateDelete(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Namespace: service.Namespace, Name: service.Name},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: udpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: udpServerPort}},
 			}},
 		},
 	})
@@ -390,13 +391,13 @@ func TestUDPProxyUpdateDelete(t *testing.T) {
 
 func TestTCPProxyUpdateDeleteUpdate(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: tcpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: tcpServerPort}},
 			}},
 		},
 	})
@@ -421,7 +422,7 @@ func TestTCPProxyUpdateDeleteUpdate(t *testing.T) {
 	}
 	waitForNumProxyLoops(t, p, 0)
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: svcInfo.proxyPort, Protocol: "TCP", PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: svcInfo.proxyPort, Protocol: "TCP"}}, PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
 	})
 	svcInfo, exists := p.getServiceInfo(service)
 	if !exists {
@@ -433,13 +434,13 @@ func TestTCPProxyUpdateDeleteUpdate(t *testing.T) {
 
 func TestUDPProxyUpdateDeleteUpdate(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: udpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: udpServerPort}},
 			}},
 		},
 	})
@@ -464,7 +465,7 @@ func TestUDPProxyUpdateDeleteUpdate(t *testing.T) {
 	}
 	waitForNumProxyLoops(t, p, 0)
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: svcInfo.proxyPort, Protocol: "UDP", PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: svcInfo.proxyPort, Protocol: "UDP"}}, PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
 	})
 	svcInfo, exists := p.getServiceInfo(service)
 	if !exists {
@@ -476,13 +477,13 @@ func TestUDPProxyUpdateDeleteUpdate(t *testing.T) {
 
 func TestTCPProxyUpdatePort(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: tcpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: tcpServerPort}},
 			}},
 		},
 	})
@@ -498,7 +499,7 @@ func TestTCPProxyUpdatePort(t *testing.T) {
 	waitForNumProxyLoops(t, p, 1)
 
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: 99, Protocol: "TCP", PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: 99, Protocol: "TCP"}}, PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
 	})
 	// Wait for the socket to actually get free.
 	if err := waitForClosedPortTCP(p, svcInfo.proxyPort); err != nil {
@@ -516,13 +517,13 @@ func TestTCPProxyUpdatePort(t *testing.T) {
 
 func TestUDPProxyUpdatePort(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: udpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: udpServerPort}},
 			}},
 		},
 	})
@@ -537,7 +538,7 @@ func TestUDPProxyUpdatePort(t *testing.T) {
 	waitForNumProxyLoops(t, p, 1)
 
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: 99, Protocol: "UDP", PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: 99, Protocol: "UDP"}}, PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
 	})
 	// Wait for the socket to actually get free.
 	if err := waitForClosedPortUDP(p, svcInfo.proxyPort); err != nil {
@@ -553,13 +554,13 @@ func TestUDPProxyUpdatePort(t *testing.T) {
 
 func TestProxyUpdatePortal(t *testing.T) {
 	lb := NewLoadBalancerRR()
-	service := types.NewNamespacedNameOrDie("testnamespace", "echo")
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
 	lb.OnUpdate([]api.Endpoints{
 		{
 			ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace},
 			Subsets: []api.EndpointSubset{{
 				Addresses: []api.EndpointAddress{{IP: "127.0.0.1"}},
-				Ports:     []api.EndpointPort{{Port: tcpServerPort}},
+				Ports:     []api.EndpointPort{{Name: "p", Port: tcpServerPort}},
 			}},
 		},
 	})
@@ -575,7 +576,7 @@ func TestProxyUpdatePortal(t *testing.T) {
 	waitForNumProxyLoops(t, p, 1)
 
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: svcInfo.proxyPort, Protocol: "TCP"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: svcInfo.proxyPort, Protocol: "TCP"}}}, Status: api.ServiceStatus{}},
 	})
 	_, exists := p.getServiceInfo(service)
 	if exists {
@@ -583,7 +584,7 @@ func TestProxyUpdatePortal(t *testing.T) {
 	}
 
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: svcInfo.proxyPort, Protocol: "TCP", PortalIP: ""}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: svcInfo.proxyPort, Protocol: "TCP"}}, PortalIP: ""}, Status: api.ServiceStatus{}},
 	})
 	_, exists = p.getServiceInfo(service)
 	if exists {
@@ -591,7 +592,7 @@ func TestProxyUpdatePortal(t *testing.T) {
 	}
 
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: svcInfo.proxyPort, Protocol: "TCP", PortalIP: "None"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: svcInfo.proxyPort, Protocol: "TCP"}}, PortalIP: "None"}, Status: api.ServiceStatus{}},
 	})
 	_, exists = p.getServiceInfo(service)
 	if exists {
@@ -599,7 +600,7 @@ func TestProxyUpdatePortal(t *testing.T) {
 	}
 
 	p.OnUpdate([]api.Service{
-		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Port: svcInfo.proxyPort, Protocol: "TCP", PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{Name: "p", Port: svcInfo.proxyPort, Protocol: "TCP"}}, PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
 	})
 	svcInfo, exists = p.getServiceInfo(service)
 	if !exists {
@@ -610,3 +611,100 @@ func TestProxyUpdatePortal(t *testing.T) {
 }
 
 // TODO: Test UDP timeouts.
+
+func TestProxyUpdateMultiplePorts(t *testing.T) {
+	lb := NewLoadBalancerRR()
+	name := types.NewNamespacedNameOrDie("testnamespace", "echo")
+
+	p := CreateProxier(lb, net.ParseIP("0.0.0.0"), &fakeIptables{}, net.ParseIP("127.0.0.1"))
+	waitForNumProxyLoops(t, p, 0)
+
+	p.OnUpdate([]api.Service{
+		{ObjectMeta: api.ObjectMeta{Name: name.Name, Namespace: name.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{
+			{Name: "http", Port: 80, Protocol: "TCP"},
+			{Name: "dns", Port: 53, Protocol: "UDP"},
+		}, PortalIP: "1.2.3.4"}, Status: api.ServiceStatus{}},
+	})
+	for port, protocol := range map[string]api.Protocol{"http": "TCP", "dns": "UDP"} {
+		svcInfo, exists := p.getServiceInfo(servicePort{name, port})
+		if !exists {
+			t.Fatalf("can't find serviceInfo for port %q", port)
+		}
+		if svcInfo.protocol != protocol {
+			t.Errorf("expected protocol %s for port %q, got %s", protocol, port, svcInfo.protocol)
+		}
+	}
+	waitForNumProxyLoops(t, p, 2)
+
+	p.OnUpdate([]api.Service{})
+	waitForNumProxyLoops(t, p, 0)
+}
+
+func TestProxyUpdateNodePort(t *testing.T) {
+	lb := NewLoadBalancerRR()
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
+
+	p := CreateProxier(lb, net.ParseIP("0.0.0.0"), &fakeIptables{}, net.ParseIP("127.0.0.1"))
+	waitForNumProxyLoops(t, p, 0)
+
+	p.OnUpdate([]api.Service{
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{
+			Name:     "p",
+			Port:     80,
+			Protocol: "TCP",
+			NodePort: 30080,
+		}}, PortalIP: "1.2.3.4", Type: api.ServiceTypeNodePort}, Status: api.ServiceStatus{}},
+	})
+	svcInfo, exists := p.getServiceInfo(service)
+	if !exists {
+		t.Fatalf("can't find serviceInfo for %s", service)
+	}
+	if svcInfo.nodePort != 30080 {
+		t.Errorf("expected node port 30080, got %d", svcInfo.nodePort)
+	}
+	waitForNumProxyLoops(t, p, 1)
+
+	p.OnUpdate([]api.Service{
+		{ObjectMeta: api.ObjectMeta{Name: service.Name, Namespace: service.Namespace}, Spec: api.ServiceSpec{Ports: []api.ServicePort{{
+			Name:     "p",
+			Port:     80,
+			Protocol: "TCP",
+			NodePort: 30081,
+		}}, PortalIP: "1.2.3.4", Type: api.ServiceTypeNodePort}, Status: api.ServiceStatus{}},
+	})
+	newSvcInfo, exists := p.getServiceInfo(service)
+	if !exists {
+		t.Fatalf("can't find serviceInfo for %s", service)
+	}
+	if newSvcInfo == svcInfo || newSvcInfo.nodePort != 30081 {
+		t.Errorf("expected the proxy to be restarted on node port 30081, got %#v", newSvcInfo)
+	}
+	waitForNumProxyLoops(t, p, 1)
+}
+
+func TestIptablesNodePortArgs(t *testing.T) {
+	service := servicePort{types.NewNamespacedNameOrDie("testnamespace", "echo"), "p"}
+	p := &Proxier{hostIP: net.ParseIP("10.0.0.1")}
+
+	args := p.iptablesContainerPortalArgs(nil, 30080, "TCP", net.ParseIP("0.0.0.0"), 12345, service)
+	expected := []string{
+		"-m", "comment", "--comment", service.String(),
+		"-p", "tcp", "-m", "tcp",
+		"--dport", "30080",
+		"-j", "REDIRECT", "--to-ports", "12345",
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v, got %v", expected, args)
+	}
+
+	args = p.iptablesHostPortalArgs(nil, 30080, "TCP", net.ParseIP("0.0.0.0"), 12345, service)
+	expected = []string{
+		"-m", "comment", "--comment", service.String(),
+		"-p", "tcp", "-m", "tcp",
+		"--dport", "30080",
+		"-j", "DNAT", "--to-destination", "10.0.0.1:12345",
+	}
+	if !reflect.DeepEqual(args, expected) {
+		t.Errorf("expected %v, got %v", expected, args)
+	}
+}
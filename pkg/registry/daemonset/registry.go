@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// Registry is an interface implemented by things that know how to store DaemonSet objects.
+type Registry interface {
+	// ListDaemonSets obtains a list of daemon sets having labels which match selector.
+	ListDaemonSets(ctx api.Context, selector labels.Selector) (*api.DaemonSetList, error)
+	// Watch for new/changed/deleted daemon sets
+	WatchDaemonSets(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+	// Get a specific daemon set
+	GetDaemonSet(ctx api.Context, daemonSetID string) (*api.DaemonSet, error)
+	// Create a daemon set based on a specification.
+	CreateDaemonSet(ctx api.Context, daemonSet *api.DaemonSet) error
+	// Update an existing daemon set
+	UpdateDaemonSet(ctx api.Context, daemonSet *api.DaemonSet) error
+	// Delete an existing daemon set
+	DeleteDaemonSet(ctx api.Context, daemonSetID string) error
+}
+
+// storage puts strong typing around storage calls
+type storage struct {
+	rest.StandardStorage
+}
+
+// NewRegistry returns a new Registry interface for the given Storage. Any mismatched
+// types will panic.
+func NewRegistry(s rest.StandardStorage) Registry {
+	return &storage{s}
+}
+
+func (s *storage) ListDaemonSets(ctx api.Context, label labels.Selector) (*api.DaemonSetList, error) {
+	obj, err := s.List(ctx, label, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.DaemonSetList), nil
+}
+
+func (s *storage) WatchDaemonSets(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.Watch(ctx, label, field, resourceVersion)
+}
+
+func (s *storage) GetDaemonSet(ctx api.Context, daemonSetID string) (*api.DaemonSet, error) {
+	obj, err := s.Get(ctx, daemonSetID)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.DaemonSet), nil
+}
+
+func (s *storage) CreateDaemonSet(ctx api.Context, daemonSet *api.DaemonSet) error {
+	_, err := s.Create(ctx, daemonSet)
+	return err
+}
+
+func (s *storage) UpdateDaemonSet(ctx api.Context, daemonSet *api.DaemonSet) error {
+	_, _, err := s.Update(ctx, daemonSet)
+	return err
+}
+
+func (s *storage) DeleteDaemonSet(ctx api.Context, daemonSetID string) error {
+	_, err := s.Delete(ctx, daemonSetID, nil)
+	return err
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package daemonset
+
+import (
+	"testing"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestDaemonSetStrategy(t *testing.T) {
+	if !Strategy.NamespaceScoped() {
+		t.Errorf("DaemonSet should be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("DaemonSet should not allow create on update")
+	}
+	daemonSet := &api.DaemonSet{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Status: api.DaemonSetStatus{
+			CurrentNumberScheduled: 1,
+			NumberMisscheduled:     2,
+			DesiredNumberScheduled: 3,
+		},
+	}
+	Strategy.PrepareForCreate(daemonSet)
+	if daemonSet.Status.CurrentNumberScheduled != 0 || daemonSet.Status.NumberMisscheduled != 0 || daemonSet.Status.DesiredNumberScheduled != 0 {
+		t.Errorf("DaemonSet does not allow setting status on create")
+	}
+
+	oldDaemonSet := &api.DaemonSet{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.DaemonSetSpec{NodeSelector: map[string]string{"a": "b"}},
+		Status:     api.DaemonSetStatus{CurrentNumberScheduled: 1},
+	}
+	updatedDaemonSet := &api.DaemonSet{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.DaemonSetSpec{NodeSelector: map[string]string{"a": "b"}},
+		Status:     api.DaemonSetStatus{CurrentNumberScheduled: 2},
+	}
+	Strategy.PrepareForUpdate(updatedDaemonSet, oldDaemonSet)
+	if updatedDaemonSet.Status.CurrentNumberScheduled != 1 {
+		t.Errorf("DaemonSet does not allow updating status through the main resource")
+	}
+
+	updatedDaemonSet = &api.DaemonSet{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.DaemonSetSpec{NodeSelector: map[string]string{"c": "d"}},
+		Status:     api.DaemonSetStatus{CurrentNumberScheduled: 2},
+	}
+	StatusStrategy.PrepareForUpdate(updatedDaemonSet, oldDaemonSet)
+	if updatedDaemonSet.Spec.NodeSelector["a"] != "b" {
+		t.Errorf("DaemonSet does not allow updating spec through the status resource")
+	}
+	if updatedDaemonSet.Status.CurrentNumberScheduled != 2 {
+		t.Errorf("DaemonSet should allow updating status through the status resource")
+	}
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/deployment"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/latest"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newHelper(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	return fakeEtcdClient, helper
+}
+
+func newStorage(t *testing.T) (*REST, *StatusREST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient, h := newHelper(t)
+	storage, statusStorage := NewStorage(h)
+	return storage, statusStorage, fakeEtcdClient, h
+}
+
+func validNewDeployment() *api.Deployment {
+	return &api.Deployment{
+		ObjectMeta: api.ObjectMeta{
+			Name:      "foo",
+			Namespace: api.NamespaceDefault,
+		},
+		Spec: api.DeploymentSpec{
+			Replicas: 2,
+			Selector: map[string]string{"a": "b"},
+			Template: &api.PodTemplateSpec{
+				ObjectMeta: api.ObjectMeta{
+					Labels: map[string]string{"a": "b"},
+				},
+				Spec: api.PodSpec{
+					Containers: []api.Container{
+						{
+							Name:                   "test",
+							Image:                  "test_image",
+							ImagePullPolicy:        api.PullIfNotPresent,
+							TerminationMessagePath: api.TerminationMessagePathDefault,
+						},
+					},
+					RestartPolicy: api.RestartPolicyAlways,
+					DNSPolicy:     api.DNSClusterFirst,
+				},
+			},
+			Strategy: api.DeploymentStrategy{
+				Type: api.RollingUpdateDeploymentStrategyType,
+				RollingUpdate: &api.RollingUpdateDeployment{
+					MaxUnavailable: util.NewIntOrStringFromInt(1),
+					MaxSurge:       util.NewIntOrStringFromString("25%"),
+				},
+			},
+		},
+	}
+}
+
+func TestStorage(t *testing.T) {
+	storage, _, _, _ := newStorage(t)
+	deployment.NewRegistry(storage)
+}
+
+func TestCreate(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	storage, _ := NewStorage(helper)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError)
+	validDeployment := validNewDeployment()
+	validDeployment.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		validDeployment,
+		// invalid
+		&api.Deployment{
+			ObjectMeta: api.ObjectMeta{Name: "_-a123-a_"},
+		},
+	)
+}
+
+func TestCreateSetsFields(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	storage, _ := NewStorage(helper)
+	deployment := validNewDeployment()
+	deployment.Status.Replicas = 5
+	_, err := storage.Create(api.NewDefaultContext(), deployment)
+	if err != fakeEtcdClient.Err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := &api.Deployment{}
+	if err := helper.ExtractObj("/registry/deployments/default/foo", actual, false); err != nil {
+		t.Fatalf("unexpected extraction error: %v", err)
+	}
+	if actual.Name != deployment.Name {
+		t.Errorf("unexpected deployment: %#v", actual)
+	}
+	if len(actual.UID) == 0 {
+		t.Errorf("expected deployment UID to be set: %#v", actual)
+	}
+	if actual.Status.Replicas != 0 {
+		t.Errorf("expected deployment status to be cleared: %#v", actual.Status)
+	}
+}
+
+func TestDeploymentDecode(t *testing.T) {
+	storage, _ := NewStorage(tools.EtcdHelper{})
+	expected := validNewDeployment()
+	body, err := latest.Codec.Encode(expected)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := storage.New()
+	if err := latest.Codec.DecodeInto(body, actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !api.Semantic.DeepEqual(expected, actual) {
+		t.Errorf("mismatch: %s", util.ObjectDiff(expected, actual))
+	}
+}
+
+func TestEtcdGet(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, validNewDeployment()), 0)
+	obj, err := registry.Get(ctx, "foo")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	deployment := obj.(*api.Deployment)
+	if deployment.Name != "foo" {
+		t.Errorf("Unexpected deployment: %#v", deployment)
+	}
+}
+
+func TestEtcdGetNotFound(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	_, err := registry.Get(ctx, "foo")
+	if !errors.IsNotFound(err) {
+		t.Errorf("Unexpected error returned: %#v", err)
+	}
+}
+
+func TestEtcdCreateFailsWithoutNamespace(t *testing.T) {
+	registry, _, _, _ := newStorage(t)
+	deployment := validNewDeployment()
+	deployment.Namespace = ""
+	_, err := registry.Create(api.NewContext(), deployment)
+	// Accept "namespace" or "Namespace".
+	if err == nil || !strings.Contains(err.Error(), "amespace") {
+		t.Fatalf("expected error that namespace was missing from context, got: %v", err)
+	}
+}
+
+func TestEtcdListSelection(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key := registry.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.Deployment{
+						ObjectMeta: api.ObjectMeta{Name: "foo"},
+					})},
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.Deployment{
+						ObjectMeta: api.ObjectMeta{
+							Name:   "qux",
+							Labels: map[string]string{"label": "qux"},
+						},
+					})},
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.Deployment{
+						ObjectMeta: api.ObjectMeta{Name: "zot"},
+					})},
+				},
+			},
+		},
+	}
+
+	table := []struct {
+		label, field string
+		expectedIDs  util.StringSet
+	}{
+		{
+			expectedIDs: util.NewStringSet("foo", "qux", "zot"),
+		}, {
+			field:       "name=zot",
+			expectedIDs: util.NewStringSet("zot"),
+		}, {
+			label:       "label=qux",
+			expectedIDs: util.NewStringSet("qux"),
+		},
+	}
+
+	for index, item := range table {
+		label, err := labels.Parse(item.label)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		field, err := fields.ParseSelector(item.field)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		deploymentsObj, err := registry.List(ctx, label, field)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		deployments := deploymentsObj.(*api.DeploymentList)
+
+		set := util.NewStringSet()
+		for i := range deployments.Items {
+			set.Insert(deployments.Items[i].Name)
+		}
+		if !set.IsSuperset(item.expectedIDs) || len(set) != len(item.expectedIDs) {
+			t.Errorf("%v: Expected %v, got %v", index, item.expectedIDs, set)
+		}
+	}
+}
+
+func TestEtcdUpdateStatus(t *testing.T) {
+	registry, status, fakeClient, helper := newStorage(t)
+	ctx := api.NewDefaultContext()
+
+	key, _ := registry.KeyFunc(ctx, "foo")
+	deploymentStart := validNewDeployment()
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, deploymentStart), 1)
+
+	deploymentIn := validNewDeployment()
+	deploymentIn.ResourceVersion = "1"
+	deploymentIn.Spec.Replicas = 7
+	deploymentIn.Status = api.DeploymentStatus{
+		Replicas:          3,
+		UpdatedReplicas:   1,
+		AvailableReplicas: 2,
+		Revision:          2,
+	}
+
+	expected := *deploymentStart
+	expected.ResourceVersion = "2"
+	expected.Status = deploymentIn.Status
+
+	_, _, err := status.Update(ctx, deploymentIn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var deploymentOut api.Deployment
+	if err := helper.ExtractObj(key, &deploymentOut, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !api.Semantic.DeepEqual(expected, deploymentOut) {
+		t.Errorf("unexpected object: %s", util.ObjectDiff(expected, deploymentOut))
+	}
+}
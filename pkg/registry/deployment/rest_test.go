@@ -0,0 +1,72 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package deployment
+
+import (
+	"testing"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestDeploymentStrategy(t *testing.T) {
+	if !Strategy.NamespaceScoped() {
+		t.Errorf("Deployment should be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("Deployment should not allow create on update")
+	}
+	deployment := &api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Status: api.DeploymentStatus{
+			Replicas:          1,
+			UpdatedReplicas:   2,
+			AvailableReplicas: 3,
+		},
+	}
+	Strategy.PrepareForCreate(deployment)
+	if deployment.Status.Replicas != 0 || deployment.Status.UpdatedReplicas != 0 || deployment.Status.AvailableReplicas != 0 {
+		t.Errorf("Deployment does not allow setting status on create")
+	}
+
+	oldDeployment := &api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.DeploymentSpec{Replicas: 2},
+		Status:     api.DeploymentStatus{Replicas: 1},
+	}
+	updatedDeployment := &api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.DeploymentSpec{Replicas: 2},
+		Status:     api.DeploymentStatus{Replicas: 2},
+	}
+	Strategy.PrepareForUpdate(updatedDeployment, oldDeployment)
+	if updatedDeployment.Status.Replicas != 1 {
+		t.Errorf("Deployment does not allow updating status through the main resource")
+	}
+
+	updatedDeployment = &api.Deployment{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.DeploymentSpec{Replicas: 5},
+		Status:     api.DeploymentStatus{Replicas: 2},
+	}
+	StatusStrategy.PrepareForUpdate(updatedDeployment, oldDeployment)
+	if updatedDeployment.Spec.Replicas != 2 {
+		t.Errorf("Deployment does not allow updating spec through the status resource")
+	}
+	if updatedDeployment.Status.Replicas != 2 {
+		t.Errorf("Deployment should allow updating status through the status resource")
+	}
+}
@@ -573,11 +573,12 @@ func TestEtcdUpdateService(t *testing.T) {
 			},
 		},
 		Spec: api.ServiceSpec{
+			Ports: []api.ServicePort{{Protocol: "TCP"}},
 			Selector: map[string]string{
 				"baz": "bar",
 			},
-			Protocol:        "TCP",
 			SessionAffinity: "None",
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	_, err := registry.UpdateService(ctx, &testService)
@@ -0,0 +1,71 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package horizontalpodautoscaler
+
+import (
+	"testing"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestHorizontalPodAutoscalerStrategy(t *testing.T) {
+	if !Strategy.NamespaceScoped() {
+		t.Errorf("HorizontalPodAutoscaler should be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("HorizontalPodAutoscaler should not allow create on update")
+	}
+	autoscaler := &api.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Status: api.HorizontalPodAutoscalerStatus{
+			CurrentReplicas: 1,
+			DesiredReplicas: 2,
+		},
+	}
+	Strategy.PrepareForCreate(autoscaler)
+	if autoscaler.Status.CurrentReplicas != 0 || autoscaler.Status.DesiredReplicas != 0 {
+		t.Errorf("HorizontalPodAutoscaler does not allow setting status on create")
+	}
+
+	oldAutoscaler := &api.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.HorizontalPodAutoscalerSpec{MaxReplicas: 2},
+		Status:     api.HorizontalPodAutoscalerStatus{CurrentReplicas: 1},
+	}
+	updatedAutoscaler := &api.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.HorizontalPodAutoscalerSpec{MaxReplicas: 2},
+		Status:     api.HorizontalPodAutoscalerStatus{CurrentReplicas: 2},
+	}
+	Strategy.PrepareForUpdate(updatedAutoscaler, oldAutoscaler)
+	if updatedAutoscaler.Status.CurrentReplicas != 1 {
+		t.Errorf("HorizontalPodAutoscaler does not allow updating status through the main resource")
+	}
+
+	updatedAutoscaler = &api.HorizontalPodAutoscaler{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.HorizontalPodAutoscalerSpec{MaxReplicas: 5},
+		Status:     api.HorizontalPodAutoscalerStatus{CurrentReplicas: 2},
+	}
+	StatusStrategy.PrepareForUpdate(updatedAutoscaler, oldAutoscaler)
+	if updatedAutoscaler.Spec.MaxReplicas != 2 {
+		t.Errorf("HorizontalPodAutoscaler does not allow updating spec through the status resource")
+	}
+	if updatedAutoscaler.Status.CurrentReplicas != 2 {
+		t.Errorf("HorizontalPodAutoscaler should allow updating status through the status resource")
+	}
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/job"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/latest"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newHelper(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	return fakeEtcdClient, helper
+}
+
+func newStorage(t *testing.T) (*REST, *StatusREST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient, h := newHelper(t)
+	storage, statusStorage := NewStorage(h)
+	return storage, statusStorage, fakeEtcdClient, h
+}
+
+func validNewJob() *api.Job {
+	return &api.Job{
+		ObjectMeta: api.ObjectMeta{
+			Name:      "foo",
+			Namespace: api.NamespaceDefault,
+		},
+		Spec: api.JobSpec{
+			Completions: 2,
+			Parallelism: 1,
+			Selector:    map[string]string{"a": "b"},
+			Template: &api.PodTemplateSpec{
+				ObjectMeta: api.ObjectMeta{
+					Labels: map[string]string{"a": "b"},
+				},
+				Spec: api.PodSpec{
+					Containers: []api.Container{
+						{
+							Name:                   "test",
+							Image:                  "test_image",
+							ImagePullPolicy:        api.PullIfNotPresent,
+							TerminationMessagePath: api.TerminationMessagePathDefault,
+						},
+					},
+					RestartPolicy: api.RestartPolicyOnFailure,
+					DNSPolicy:     api.DNSClusterFirst,
+				},
+			},
+		},
+	}
+}
+
+func TestStorage(t *testing.T) {
+	storage, _, _, _ := newStorage(t)
+	job.NewRegistry(storage)
+}
+
+func TestCreate(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	storage, _ := NewStorage(helper)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError)
+	validJob := validNewJob()
+	validJob.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		validJob,
+		// invalid
+		&api.Job{
+			ObjectMeta: api.ObjectMeta{Name: "_-a123-a_"},
+		},
+	)
+}
+
+func TestCreateSetsFields(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	storage, _ := NewStorage(helper)
+	job := validNewJob()
+	job.Status.Succeeded = 5
+	_, err := storage.Create(api.NewDefaultContext(), job)
+	if err != fakeEtcdClient.Err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := &api.Job{}
+	if err := helper.ExtractObj("/registry/jobs/default/foo", actual, false); err != nil {
+		t.Fatalf("unexpected extraction error: %v", err)
+	}
+	if actual.Name != job.Name {
+		t.Errorf("unexpected job: %#v", actual)
+	}
+	if len(actual.UID) == 0 {
+		t.Errorf("expected job UID to be set: %#v", actual)
+	}
+	if actual.Status.Succeeded != 0 {
+		t.Errorf("expected job status to be cleared: %#v", actual.Status)
+	}
+}
+
+func TestJobDecode(t *testing.T) {
+	storage, _ := NewStorage(tools.EtcdHelper{})
+	expected := validNewJob()
+	body, err := latest.Codec.Encode(expected)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := storage.New()
+	if err := latest.Codec.DecodeInto(body, actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !api.Semantic.DeepEqual(expected, actual) {
+		t.Errorf("mismatch: %s", util.ObjectDiff(expected, actual))
+	}
+}
+
+func TestEtcdGet(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, validNewJob()), 0)
+	obj, err := registry.Get(ctx, "foo")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	job := obj.(*api.Job)
+	if job.Name != "foo" {
+		t.Errorf("Unexpected job: %#v", job)
+	}
+}
+
+func TestEtcdGetNotFound(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	_, err := registry.Get(ctx, "foo")
+	if !errors.IsNotFound(err) {
+		t.Errorf("Unexpected error returned: %#v", err)
+	}
+}
+
+func TestEtcdCreateFailsWithoutNamespace(t *testing.T) {
+	registry, _, _, _ := newStorage(t)
+	job := validNewJob()
+	job.Namespace = ""
+	_, err := registry.Create(api.NewContext(), job)
+	// Accept "namespace" or "Namespace".
+	if err == nil || !strings.Contains(err.Error(), "amespace") {
+		t.Fatalf("expected error that namespace was missing from context, got: %v", err)
+	}
+}
+
+func TestEtcdListSelection(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key := registry.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.Job{
+						ObjectMeta: api.ObjectMeta{Name: "foo"},
+					})},
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.Job{
+						ObjectMeta: api.ObjectMeta{
+							Name:   "qux",
+							Labels: map[string]string{"label": "qux"},
+						},
+					})},
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.Job{
+						ObjectMeta: api.ObjectMeta{Name: "zot"},
+					})},
+				},
+			},
+		},
+	}
+
+	table := []struct {
+		label, field string
+		expectedIDs  util.StringSet
+	}{
+		{
+			expectedIDs: util.NewStringSet("foo", "qux", "zot"),
+		}, {
+			field:       "name=zot",
+			expectedIDs: util.NewStringSet("zot"),
+		}, {
+			label:       "label=qux",
+			expectedIDs: util.NewStringSet("qux"),
+		},
+	}
+
+	for index, item := range table {
+		label, err := labels.Parse(item.label)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		field, err := fields.ParseSelector(item.field)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		jobsObj, err := registry.List(ctx, label, field)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		jobs := jobsObj.(*api.JobList)
+
+		set := util.NewStringSet()
+		for i := range jobs.Items {
+			set.Insert(jobs.Items[i].Name)
+		}
+		if !set.IsSuperset(item.expectedIDs) || len(set) != len(item.expectedIDs) {
+			t.Errorf("%v: Expected %v, got %v", index, item.expectedIDs, set)
+		}
+	}
+}
+
+func TestEtcdUpdateStatus(t *testing.T) {
+	registry, status, fakeClient, helper := newStorage(t)
+	ctx := api.NewDefaultContext()
+
+	key, _ := registry.KeyFunc(ctx, "foo")
+	jobStart := validNewJob()
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, jobStart), 1)
+
+	jobIn := validNewJob()
+	jobIn.ResourceVersion = "1"
+	jobIn.Spec.Parallelism = 7
+	jobIn.Status = api.JobStatus{
+		Active:    1,
+		Succeeded: 1,
+		Failed:    2,
+	}
+
+	expected := *jobStart
+	expected.ResourceVersion = "2"
+	expected.Status = jobIn.Status
+
+	_, _, err := status.Update(ctx, jobIn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var jobOut api.Job
+	if err := helper.ExtractObj(key, &jobOut, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !api.Semantic.DeepEqual(expected, jobOut) {
+		t.Errorf("unexpected object: %s", util.ObjectDiff(expected, jobOut))
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// Registry is an interface implemented by things that know how to store Job objects.
+type Registry interface {
+	// ListJobs obtains a list of jobs having labels which match selector.
+	ListJobs(ctx api.Context, selector labels.Selector) (*api.JobList, error)
+	// Watch for new/changed/deleted jobs
+	WatchJobs(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+	// Get a specific job
+	GetJob(ctx api.Context, jobID string) (*api.Job, error)
+	// Create a job based on a specification.
+	CreateJob(ctx api.Context, job *api.Job) error
+	// Update an existing job
+	UpdateJob(ctx api.Context, job *api.Job) error
+	// Delete an existing job
+	DeleteJob(ctx api.Context, jobID string) error
+}
+
+// storage puts strong typing around storage calls
+type storage struct {
+	rest.StandardStorage
+}
+
+// NewRegistry returns a new Registry interface for the given Storage. Any mismatched
+// types will panic.
+func NewRegistry(s rest.StandardStorage) Registry {
+	return &storage{s}
+}
+
+func (s *storage) ListJobs(ctx api.Context, label labels.Selector) (*api.JobList, error) {
+	obj, err := s.List(ctx, label, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.JobList), nil
+}
+
+func (s *storage) WatchJobs(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.Watch(ctx, label, field, resourceVersion)
+}
+
+func (s *storage) GetJob(ctx api.Context, jobID string) (*api.Job, error) {
+	obj, err := s.Get(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.Job), nil
+}
+
+func (s *storage) CreateJob(ctx api.Context, job *api.Job) error {
+	_, err := s.Create(ctx, job)
+	return err
+}
+
+func (s *storage) UpdateJob(ctx api.Context, job *api.Job) error {
+	_, _, err := s.Update(ctx, job)
+	return err
+}
+
+func (s *storage) DeleteJob(ctx api.Context, jobID string) error {
+	_, err := s.Delete(ctx, jobID, nil)
+	return err
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/validation"
+)
+
+// jobStrategy implements behavior for Job objects
+type jobStrategy struct {
+	runtime.ObjectTyper
+	api.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating Job
+// objects via the REST API.
+var Strategy = jobStrategy{api.Scheme, api.SimpleNameGenerator}
+
+// NamespaceScoped is true for jobs.
+func (jobStrategy) NamespaceScoped() bool {
+	return true
+}
+
+// PrepareForCreate clears fields that are not allowed to be set by end users on creation.
+func (jobStrategy) PrepareForCreate(obj runtime.Object) {
+	job := obj.(*api.Job)
+	job.Status = api.JobStatus{}
+}
+
+// PrepareForUpdate clears fields that are not allowed to be set by end users on update.
+func (jobStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	newJob := obj.(*api.Job)
+	oldJob := old.(*api.Job)
+	newJob.Status = oldJob.Status
+}
+
+// Validate validates a new job.
+func (jobStrategy) Validate(obj runtime.Object) fielderrors.ValidationErrorList {
+	job := obj.(*api.Job)
+	return validation.ValidateJob(job)
+}
+
+// AllowCreateOnUpdate is false for jobs.
+func (jobStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// ValidateUpdate is the default update validation for an end user.
+func (jobStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateJobUpdate(old.(*api.Job), obj.(*api.Job))
+}
+
+type jobStatusStrategy struct {
+	jobStrategy
+}
+
+var StatusStrategy = jobStatusStrategy{Strategy}
+
+func (jobStatusStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	newJob := obj.(*api.Job)
+	oldJob := old.(*api.Job)
+	newJob.Spec = oldJob.Spec
+}
+
+func (jobStatusStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidateJobStatusUpdate(obj.(*api.Job), old.(*api.Job))
+}
+
+// MatchJob returns a generic matcher for a given label and field selector.
+func MatchJob(label labels.Selector, field fields.Selector) generic.Matcher {
+	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
+		jobObj, ok := obj.(*api.Job)
+		if !ok {
+			return false, fmt.Errorf("not a job")
+		}
+		fields := JobToSelectableFields(jobObj)
+		return label.Matches(labels.Set(jobObj.Labels)) && field.Matches(fields), nil
+	})
+}
+
+// JobToSelectableFields returns a label set that represents the object
+// TODO: fields are not labels, and the validation rules for them do not apply.
+func JobToSelectableFields(job *api.Job) labels.Set {
+	return labels.Set{
+		"name": job.Name,
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package job
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestJobStrategy(t *testing.T) {
+	if !Strategy.NamespaceScoped() {
+		t.Errorf("Job should be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("Job should not allow create on update")
+	}
+	now := util.Now()
+	job := &api.Job{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Status: api.JobStatus{
+			StartTime: &now,
+			Active:    1,
+			Succeeded: 2,
+			Failed:    3,
+		},
+	}
+	Strategy.PrepareForCreate(job)
+	if job.Status.StartTime != nil || job.Status.Active != 0 || job.Status.Succeeded != 0 || job.Status.Failed != 0 {
+		t.Errorf("Job does not allow setting status on create")
+	}
+
+	oldJob := &api.Job{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.JobSpec{Completions: 2},
+		Status:     api.JobStatus{Succeeded: 1},
+	}
+	updatedJob := &api.Job{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.JobSpec{Completions: 2},
+		Status:     api.JobStatus{Succeeded: 2},
+	}
+	Strategy.PrepareForUpdate(updatedJob, oldJob)
+	if updatedJob.Status.Succeeded != 1 {
+		t.Errorf("Job does not allow updating status through the main resource")
+	}
+
+	updatedJob = &api.Job{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.JobSpec{Completions: 5},
+		Status:     api.JobStatus{Succeeded: 2},
+	}
+	StatusStrategy.PrepareForUpdate(updatedJob, oldJob)
+	if updatedJob.Spec.Completions != 2 {
+		t.Errorf("Job does not allow updating spec through the status resource")
+	}
+	if updatedJob.Status.Succeeded != 2 {
+		t.Errorf("Job should allow updating status through the status resource")
+	}
+}
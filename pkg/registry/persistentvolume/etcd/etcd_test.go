@@ -0,0 +1,273 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package etcd
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest/resttest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/persistentvolume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/latest"
+
+	"github.com/coreos/go-etcd/etcd"
+)
+
+func newHelper(t *testing.T) (*tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient := tools.NewFakeEtcdClient(t)
+	fakeEtcdClient.TestIndex = true
+	helper := tools.NewEtcdHelper(fakeEtcdClient, latest.Codec)
+	return fakeEtcdClient, helper
+}
+
+func newStorage(t *testing.T) (*REST, *StatusREST, *tools.FakeEtcdClient, tools.EtcdHelper) {
+	fakeEtcdClient, h := newHelper(t)
+	storage, statusStorage := NewStorage(h)
+	return storage, statusStorage, fakeEtcdClient, h
+}
+
+func validNewPersistentVolume() *api.PersistentVolume {
+	return &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{
+			Name: "foo",
+		},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{
+				api.ResourceStorage: resource.MustParse("10G"),
+			},
+			PersistentVolumeSource: api.PersistentVolumeSource{
+				HostPath: &api.HostPathVolumeSource{Path: "/foo"},
+			},
+			PersistentVolumeReclaimPolicy: api.PersistentVolumeReclaimRetain,
+		},
+	}
+}
+
+func TestStorage(t *testing.T) {
+	storage, _, _, _ := newStorage(t)
+	persistentvolume.NewRegistry(storage)
+}
+
+func TestCreate(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	storage, _ := NewStorage(helper)
+	test := resttest.New(t, storage, fakeEtcdClient.SetError).ClusterScope()
+	validPersistentVolume := validNewPersistentVolume()
+	validPersistentVolume.ObjectMeta = api.ObjectMeta{}
+	test.TestCreate(
+		// valid
+		validPersistentVolume,
+		// invalid
+		&api.PersistentVolume{
+			ObjectMeta: api.ObjectMeta{Name: "_-a123-a_"},
+		},
+	)
+}
+
+func TestCreateSetsFields(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	storage, _ := NewStorage(helper)
+	persistentVolume := validNewPersistentVolume()
+	persistentVolume.Status.Phase = api.VolumeBound
+	_, err := storage.Create(api.NewContext(), persistentVolume)
+	if err != fakeEtcdClient.Err {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := &api.PersistentVolume{}
+	if err := helper.ExtractObj("/registry/persistentvolumes/foo", actual, false); err != nil {
+		t.Fatalf("unexpected extraction error: %v", err)
+	}
+	if actual.Name != persistentVolume.Name {
+		t.Errorf("unexpected persistent volume: %#v", actual)
+	}
+	if len(actual.UID) == 0 {
+		t.Errorf("expected persistent volume UID to be set: %#v", actual)
+	}
+	if actual.Status.Phase != "" {
+		t.Errorf("expected persistent volume status to be cleared: %#v", actual.Status)
+	}
+}
+
+func TestPersistentVolumeDecode(t *testing.T) {
+	storage, _ := NewStorage(tools.EtcdHelper{})
+	expected := validNewPersistentVolume()
+	body, err := latest.Codec.Encode(expected)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	actual := storage.New()
+	if err := latest.Codec.DecodeInto(body, actual); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !api.Semantic.DeepEqual(expected, actual) {
+		t.Errorf("mismatch: %s", util.ObjectDiff(expected, actual))
+	}
+}
+
+func TestEtcdGet(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, validNewPersistentVolume()), 0)
+	obj, err := registry.Get(ctx, "foo")
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	persistentVolume := obj.(*api.PersistentVolume)
+	if persistentVolume.Name != "foo" {
+		t.Errorf("Unexpected persistent volume: %#v", persistentVolume)
+	}
+}
+
+func TestEtcdGetNotFound(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: nil,
+		},
+		E: tools.EtcdErrorNotFound,
+	}
+	_, err := registry.Get(ctx, "foo")
+	if !errors.IsNotFound(err) {
+		t.Errorf("Unexpected error returned: %#v", err)
+	}
+}
+
+func TestEtcdCreateClearsNamespace(t *testing.T) {
+	registry, _, _, _ := newStorage(t)
+	persistentVolume := validNewPersistentVolume()
+	persistentVolume.Namespace = api.NamespaceDefault
+	obj, err := registry.Create(api.NewDefaultContext(), persistentVolume)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ns := obj.(*api.PersistentVolume).Namespace; ns != api.NamespaceNone {
+		t.Errorf("expected namespace to be cleared, got %q", ns)
+	}
+}
+
+func TestEtcdListSelection(t *testing.T) {
+	registry, _, fakeClient, _ := newStorage(t)
+	ctx := api.NewContext()
+	key := registry.KeyRootFunc(ctx)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Nodes: []*etcd.Node{
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.PersistentVolume{
+						ObjectMeta: api.ObjectMeta{Name: "foo"},
+					})},
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.PersistentVolume{
+						ObjectMeta: api.ObjectMeta{
+							Name:   "qux",
+							Labels: map[string]string{"label": "qux"},
+						},
+					})},
+					{Value: runtime.EncodeOrDie(latest.Codec, &api.PersistentVolume{
+						ObjectMeta: api.ObjectMeta{Name: "zot"},
+					})},
+				},
+			},
+		},
+	}
+
+	table := []struct {
+		label, field string
+		expectedIDs  util.StringSet
+	}{
+		{
+			expectedIDs: util.NewStringSet("foo", "qux", "zot"),
+		}, {
+			field:       "name=zot",
+			expectedIDs: util.NewStringSet("zot"),
+		}, {
+			label:       "label=qux",
+			expectedIDs: util.NewStringSet("qux"),
+		},
+	}
+
+	for index, item := range table {
+		label, err := labels.Parse(item.label)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		field, err := fields.ParseSelector(item.field)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		persistentVolumesObj, err := registry.List(ctx, label, field)
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+			continue
+		}
+		persistentVolumes := persistentVolumesObj.(*api.PersistentVolumeList)
+
+		set := util.NewStringSet()
+		for i := range persistentVolumes.Items {
+			set.Insert(persistentVolumes.Items[i].Name)
+		}
+		if !set.IsSuperset(item.expectedIDs) || len(set) != len(item.expectedIDs) {
+			t.Errorf("%v: Expected %v, got %v", index, item.expectedIDs, set)
+		}
+	}
+}
+
+func TestEtcdUpdateStatus(t *testing.T) {
+	registry, status, fakeClient, helper := newStorage(t)
+	ctx := api.NewContext()
+
+	key, _ := registry.KeyFunc(ctx, "foo")
+	persistentVolumeStart := validNewPersistentVolume()
+	fakeClient.Set(key, runtime.EncodeOrDie(latest.Codec, persistentVolumeStart), 1)
+
+	persistentVolumeIn := validNewPersistentVolume()
+	persistentVolumeIn.ResourceVersion = "1"
+	persistentVolumeIn.Spec.ClaimRef = &api.ObjectReference{Name: "bar", Namespace: api.NamespaceDefault}
+	persistentVolumeIn.Status = api.PersistentVolumeStatus{
+		Phase: api.VolumeBound,
+	}
+
+	expected := *persistentVolumeStart
+	expected.ResourceVersion = "2"
+	expected.Status = persistentVolumeIn.Status
+
+	_, _, err := status.Update(ctx, persistentVolumeIn)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var persistentVolumeOut api.PersistentVolume
+	if err := helper.ExtractObj(key, &persistentVolumeOut, false); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !api.Semantic.DeepEqual(expected, persistentVolumeOut) {
+		t.Errorf("unexpected object: %s", util.ObjectDiff(expected, persistentVolumeOut))
+	}
+}
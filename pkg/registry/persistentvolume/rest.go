@@ -0,0 +1,110 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolume
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/validation"
+)
+
+// persistentVolumeStrategy implements behavior for PersistentVolume objects
+type persistentVolumeStrategy struct {
+	runtime.ObjectTyper
+	api.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating PersistentVolume
+// objects via the REST API.
+var Strategy = persistentVolumeStrategy{api.Scheme, api.SimpleNameGenerator}
+
+// NamespaceScoped is false for persistent volumes.
+func (persistentVolumeStrategy) NamespaceScoped() bool {
+	return false
+}
+
+// PrepareForCreate clears fields that are not allowed to be set by end users on creation.
+func (persistentVolumeStrategy) PrepareForCreate(obj runtime.Object) {
+	pv := obj.(*api.PersistentVolume)
+	pv.Status = api.PersistentVolumeStatus{}
+}
+
+// PrepareForUpdate clears fields that are not allowed to be set by end users on update.
+func (persistentVolumeStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	newPv := obj.(*api.PersistentVolume)
+	oldPv := old.(*api.PersistentVolume)
+	newPv.Status = oldPv.Status
+}
+
+// Validate validates a new persistent volume.
+func (persistentVolumeStrategy) Validate(obj runtime.Object) fielderrors.ValidationErrorList {
+	pv := obj.(*api.PersistentVolume)
+	return validation.ValidatePersistentVolume(pv)
+}
+
+// AllowCreateOnUpdate is false for persistent volumes.
+func (persistentVolumeStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// ValidateUpdate is the default update validation for an end user.
+func (persistentVolumeStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidatePersistentVolumeUpdate(obj.(*api.PersistentVolume), old.(*api.PersistentVolume))
+}
+
+type persistentVolumeStatusStrategy struct {
+	persistentVolumeStrategy
+}
+
+var StatusStrategy = persistentVolumeStatusStrategy{Strategy}
+
+func (persistentVolumeStatusStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	newPv := obj.(*api.PersistentVolume)
+	oldPv := old.(*api.PersistentVolume)
+	newPv.Spec = oldPv.Spec
+}
+
+func (persistentVolumeStatusStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidatePersistentVolumeStatusUpdate(obj.(*api.PersistentVolume), old.(*api.PersistentVolume))
+}
+
+// MatchPersistentVolume returns a generic matcher for a given label and field selector.
+func MatchPersistentVolume(label labels.Selector, field fields.Selector) generic.Matcher {
+	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
+		pv, ok := obj.(*api.PersistentVolume)
+		if !ok {
+			return false, fmt.Errorf("not a persistent volume")
+		}
+		fields := PersistentVolumeToSelectableFields(pv)
+		return label.Matches(labels.Set(pv.Labels)) && field.Matches(fields), nil
+	})
+}
+
+// PersistentVolumeToSelectableFields returns a label set that represents the object
+// TODO: fields are not labels, and the validation rules for them do not apply.
+func PersistentVolumeToSelectableFields(pv *api.PersistentVolume) labels.Set {
+	return labels.Set{
+		"name":         pv.Name,
+		"status.phase": string(pv.Status.Phase),
+	}
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolume
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestPersistentVolumeStrategy(t *testing.T) {
+	if Strategy.NamespaceScoped() {
+		t.Errorf("PersistentVolume should not be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("PersistentVolume should not allow create on update")
+	}
+	pv := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Status:     api.PersistentVolumeStatus{Phase: api.VolumeBound},
+	}
+	Strategy.PrepareForCreate(pv)
+	if pv.Status.Phase != "" {
+		t.Errorf("PersistentVolume does not allow setting status on create")
+	}
+
+	oldPv := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{api.ResourceStorage: resource.MustParse("10G")},
+		},
+		Status: api.PersistentVolumeStatus{Phase: api.VolumeAvailable},
+	}
+	updatedPv := &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{api.ResourceStorage: resource.MustParse("10G")},
+		},
+		Status: api.PersistentVolumeStatus{Phase: api.VolumeBound},
+	}
+	Strategy.PrepareForUpdate(updatedPv, oldPv)
+	if updatedPv.Status.Phase != api.VolumeAvailable {
+		t.Errorf("PersistentVolume does not allow updating status through the main resource")
+	}
+
+	updatedPv = &api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "foo"},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{api.ResourceStorage: resource.MustParse("20G")},
+		},
+		Status: api.PersistentVolumeStatus{Phase: api.VolumeBound},
+	}
+	StatusStrategy.PrepareForUpdate(updatedPv, oldPv)
+	capacity := updatedPv.Spec.Capacity[api.ResourceStorage]
+	if capacity.Value() != 10000000000 {
+		t.Errorf("PersistentVolume does not allow updating spec through the status resource")
+	}
+	if updatedPv.Status.Phase != api.VolumeBound {
+		t.Errorf("PersistentVolume should allow updating status through the status resource")
+	}
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// Registry is an interface implemented by things that know how to store PersistentVolumeClaim objects.
+type Registry interface {
+	// ListPersistentVolumeClaims obtains a list of persistent volume claims having labels which match selector.
+	ListPersistentVolumeClaims(ctx api.Context, selector labels.Selector) (*api.PersistentVolumeClaimList, error)
+	// Watch for new/changed/deleted persistent volume claims
+	WatchPersistentVolumeClaims(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+	// Get a specific persistent volume claim
+	GetPersistentVolumeClaim(ctx api.Context, persistentVolumeClaimID string) (*api.PersistentVolumeClaim, error)
+	// Create a persistent volume claim based on a specification.
+	CreatePersistentVolumeClaim(ctx api.Context, persistentVolumeClaim *api.PersistentVolumeClaim) error
+	// Update an existing persistent volume claim
+	UpdatePersistentVolumeClaim(ctx api.Context, persistentVolumeClaim *api.PersistentVolumeClaim) error
+	// Delete an existing persistent volume claim
+	DeletePersistentVolumeClaim(ctx api.Context, persistentVolumeClaimID string) error
+}
+
+// storage puts strong typing around storage calls
+type storage struct {
+	rest.StandardStorage
+}
+
+// NewRegistry returns a new Registry interface for the given Storage. Any mismatched
+// types will panic.
+func NewRegistry(s rest.StandardStorage) Registry {
+	return &storage{s}
+}
+
+func (s *storage) ListPersistentVolumeClaims(ctx api.Context, label labels.Selector) (*api.PersistentVolumeClaimList, error) {
+	obj, err := s.List(ctx, label, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.PersistentVolumeClaimList), nil
+}
+
+func (s *storage) WatchPersistentVolumeClaims(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.Watch(ctx, label, field, resourceVersion)
+}
+
+func (s *storage) GetPersistentVolumeClaim(ctx api.Context, persistentVolumeClaimID string) (*api.PersistentVolumeClaim, error) {
+	obj, err := s.Get(ctx, persistentVolumeClaimID)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.PersistentVolumeClaim), nil
+}
+
+func (s *storage) CreatePersistentVolumeClaim(ctx api.Context, persistentVolumeClaim *api.PersistentVolumeClaim) error {
+	_, err := s.Create(ctx, persistentVolumeClaim)
+	return err
+}
+
+func (s *storage) UpdatePersistentVolumeClaim(ctx api.Context, persistentVolumeClaim *api.PersistentVolumeClaim) error {
+	_, _, err := s.Update(ctx, persistentVolumeClaim)
+	return err
+}
+
+func (s *storage) DeletePersistentVolumeClaim(ctx api.Context, persistentVolumeClaimID string) error {
+	_, err := s.Delete(ctx, persistentVolumeClaimID, nil)
+	return err
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/generic"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/validation"
+)
+
+// persistentVolumeClaimStrategy implements behavior for PersistentVolumeClaim objects
+type persistentVolumeClaimStrategy struct {
+	runtime.ObjectTyper
+	api.NameGenerator
+}
+
+// Strategy is the default logic that applies when creating and updating PersistentVolumeClaim
+// objects via the REST API.
+var Strategy = persistentVolumeClaimStrategy{api.Scheme, api.SimpleNameGenerator}
+
+// NamespaceScoped is true for persistent volume claims.
+func (persistentVolumeClaimStrategy) NamespaceScoped() bool {
+	return true
+}
+
+// PrepareForCreate clears fields that are not allowed to be set by end users on creation.
+func (persistentVolumeClaimStrategy) PrepareForCreate(obj runtime.Object) {
+	pvc := obj.(*api.PersistentVolumeClaim)
+	pvc.Status = api.PersistentVolumeClaimStatus{}
+}
+
+// PrepareForUpdate clears fields that are not allowed to be set by end users on update.
+func (persistentVolumeClaimStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	newPvc := obj.(*api.PersistentVolumeClaim)
+	oldPvc := old.(*api.PersistentVolumeClaim)
+	newPvc.Status = oldPvc.Status
+}
+
+// Validate validates a new persistent volume claim.
+func (persistentVolumeClaimStrategy) Validate(obj runtime.Object) fielderrors.ValidationErrorList {
+	pvc := obj.(*api.PersistentVolumeClaim)
+	return validation.ValidatePersistentVolumeClaim(pvc)
+}
+
+// AllowCreateOnUpdate is false for persistent volume claims.
+func (persistentVolumeClaimStrategy) AllowCreateOnUpdate() bool {
+	return false
+}
+
+// ValidateUpdate is the default update validation for an end user.
+func (persistentVolumeClaimStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidatePersistentVolumeClaimUpdate(obj.(*api.PersistentVolumeClaim), old.(*api.PersistentVolumeClaim))
+}
+
+type persistentVolumeClaimStatusStrategy struct {
+	persistentVolumeClaimStrategy
+}
+
+var StatusStrategy = persistentVolumeClaimStatusStrategy{Strategy}
+
+func (persistentVolumeClaimStatusStrategy) PrepareForUpdate(obj, old runtime.Object) {
+	newPvc := obj.(*api.PersistentVolumeClaim)
+	oldPvc := old.(*api.PersistentVolumeClaim)
+	newPvc.Spec = oldPvc.Spec
+}
+
+func (persistentVolumeClaimStatusStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
+	return validation.ValidatePersistentVolumeClaimStatusUpdate(obj.(*api.PersistentVolumeClaim), old.(*api.PersistentVolumeClaim))
+}
+
+// MatchPersistentVolumeClaim returns a generic matcher for a given label and field selector.
+func MatchPersistentVolumeClaim(label labels.Selector, field fields.Selector) generic.Matcher {
+	return generic.MatcherFunc(func(obj runtime.Object) (bool, error) {
+		pvc, ok := obj.(*api.PersistentVolumeClaim)
+		if !ok {
+			return false, fmt.Errorf("not a persistent volume claim")
+		}
+		fields := PersistentVolumeClaimToSelectableFields(pvc)
+		return label.Matches(labels.Set(pvc.Labels)) && field.Matches(fields), nil
+	})
+}
+
+// PersistentVolumeClaimToSelectableFields returns a label set that represents the object
+// TODO: fields are not labels, and the validation rules for them do not apply.
+func PersistentVolumeClaimToSelectableFields(pvc *api.PersistentVolumeClaim) labels.Set {
+	return labels.Set{
+		"name":         pvc.Name,
+		"status.phase": string(pvc.Status.Phase),
+	}
+}
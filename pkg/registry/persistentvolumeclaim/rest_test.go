@@ -0,0 +1,71 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistentvolumeclaim
+
+import (
+	"testing"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestPersistentVolumeClaimStrategy(t *testing.T) {
+	if !Strategy.NamespaceScoped() {
+		t.Errorf("PersistentVolumeClaim should be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("PersistentVolumeClaim should not allow create on update")
+	}
+	pvc := &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Status: api.PersistentVolumeClaimStatus{
+			Phase:     api.ClaimBound,
+			VolumeRef: &api.ObjectReference{Name: "bar"},
+		},
+	}
+	Strategy.PrepareForCreate(pvc)
+	if pvc.Status.Phase != "" || pvc.Status.VolumeRef != nil {
+		t.Errorf("PersistentVolumeClaim does not allow setting status on create")
+	}
+
+	oldPvc := &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.PersistentVolumeClaimSpec{AccessModes: []api.AccessModeType{api.ReadWriteOnce}},
+		Status:     api.PersistentVolumeClaimStatus{Phase: api.ClaimPending},
+	}
+	updatedPvc := &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.PersistentVolumeClaimSpec{AccessModes: []api.AccessModeType{api.ReadWriteOnce}},
+		Status:     api.PersistentVolumeClaimStatus{Phase: api.ClaimBound},
+	}
+	Strategy.PrepareForUpdate(updatedPvc, oldPvc)
+	if updatedPvc.Status.Phase != api.ClaimPending {
+		t.Errorf("PersistentVolumeClaim does not allow updating status through the main resource")
+	}
+
+	updatedPvc = &api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
+		Spec:       api.PersistentVolumeClaimSpec{AccessModes: []api.AccessModeType{api.ReadWriteMany}},
+		Status:     api.PersistentVolumeClaimStatus{Phase: api.ClaimBound},
+	}
+	StatusStrategy.PrepareForUpdate(updatedPvc, oldPvc)
+	if updatedPvc.Spec.AccessModes[0] != api.ReadWriteOnce {
+		t.Errorf("PersistentVolumeClaim does not allow updating spec through the status resource")
+	}
+	if updatedPvc.Status.Phase != api.ClaimBound {
+		t.Errorf("PersistentVolumeClaim should allow updating status through the status resource")
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
 	etcderr "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors/etcd"
@@ -31,6 +32,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/pod"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/tools"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
 	"github.com/cnaize/kubernetes/pkg/api"
 )
@@ -55,6 +57,9 @@ func NewStorage(h tools.EtcdHelper) (*REST, *BindingREST, *StatusREST) {
 		ObjectNameFunc: func(obj runtime.Object) (string, error) {
 			return obj.(*api.Pod).Name, nil
 		},
+		TTLFunc: func(obj runtime.Object, update bool) (uint64, error) {
+			return deletionTTL(obj.(*api.Pod)), nil
+		},
 		PredicateFunc: func(label labels.Selector, field fields.Selector) generic.Matcher {
 			return pod.MatchPod(label, field)
 		},
@@ -77,6 +82,20 @@ func NewStorage(h tools.EtcdHelper) (*REST, *BindingREST, *StatusREST) {
 	return &REST{*store}, &BindingREST{store: store}, &StatusREST{store: &statusStore}
 }
 
+// deletionTTL returns the number of seconds a pod that is being gracefully deleted
+// has left before it is removed, so that updates to a terminating pod do not clear
+// its expiration. It returns 0 (no expiration) for pods that are not being deleted.
+func deletionTTL(pod *api.Pod) uint64 {
+	if pod.DeletionTimestamp == nil {
+		return 0
+	}
+	remaining := pod.DeletionTimestamp.Sub(util.Now().Time)
+	if remaining < time.Second {
+		return 1
+	}
+	return uint64((remaining + time.Second - 1) / time.Second)
+}
+
 // Implement Redirector.
 var _ = rest.Redirector(&REST{})
 
@@ -122,6 +141,9 @@ func (r *BindingREST) setPodHostAndAnnotations(ctx api.Context, podID, oldMachin
 		if !ok {
 			return nil, 0, fmt.Errorf("unexpected object: %#v", obj)
 		}
+		if pod.DeletionTimestamp != nil {
+			return nil, 0, fmt.Errorf("pod %v is being deleted, cannot be assigned to a host", pod.Name)
+		}
 		if pod.Spec.Host != oldMachine || pod.Status.Host != oldMachine {
 			return nil, 0, fmt.Errorf("pod %v is already assigned to host %q or %q", pod.Name, pod.Spec.Host, pod.Status.Host)
 		}
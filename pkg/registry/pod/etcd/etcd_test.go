@@ -129,6 +129,21 @@ func TestDelete(t *testing.T) {
 		return fakeEtcdClient.Data["/registry/pods/default/foo"].R.Node.TTL == 30
 	}
 	test.TestDelete(createFn, gracefulSetFn)
+
+	scheduledFn := func() runtime.Object {
+		pod := validChangedPod()
+		pod.Spec.Host = "machine"
+		fakeEtcdClient.Data["/registry/pods/default/foo"] = tools.EtcdResponseWithError{
+			R: &etcd.Response{
+				Node: &etcd.Node{
+					Value:         runtime.EncodeOrDie(latest.Codec, pod),
+					ModifiedIndex: 1,
+				},
+			},
+		}
+		return pod
+	}
+	test.TestDeleteGraceful(scheduledFn, api.DefaultTerminationGracePeriodSeconds, gracefulSetFn)
 }
 
 func expectPod(t *testing.T, out runtime.Object) (*api.Pod, bool) {
@@ -623,6 +638,101 @@ func TestDeletePod(t *testing.T) {
 	}
 }
 
+func TestDeletePodUsesTerminationGracePeriod(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	grace := int64(5)
+	pod := validChangedPod()
+	pod.Spec.Host = "machine"
+	pod.Spec.TerminationGracePeriodSeconds = &grace
+	fakeEtcdClient.Data["/registry/pods/default/foo"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Value:         runtime.EncodeOrDie(latest.Codec, pod),
+				ModifiedIndex: 1,
+			},
+		},
+	}
+	storage, _, _ := NewStorage(helper)
+
+	if _, err := storage.Delete(api.NewDefaultContext(), "foo", &api.DeleteOptions{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := int64(5), fakeEtcdClient.Data["/registry/pods/default/foo"].R.Node.TTL; e != a {
+		t.Errorf("expected ttl %d, got %d", e, a)
+	}
+}
+
+func TestDeleteTerminatingPod(t *testing.T) {
+	fakeEtcdClient, helper := newHelper(t)
+	pod := validChangedPod()
+	pod.Spec.Host = "machine"
+	expiration := time.Now().Add(20 * time.Second)
+	fakeEtcdClient.Data["/registry/pods/default/foo"] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Value:         runtime.EncodeOrDie(latest.Codec, pod),
+				ModifiedIndex: 1,
+				Expiration:    &expiration,
+				TTL:           20,
+			},
+		},
+	}
+	storage, _, _ := NewStorage(helper)
+	ctx := api.NewDefaultContext()
+
+	// a longer grace period does not extend the pending deletion
+	if _, err := storage.Delete(ctx, "foo", api.NewDeleteOptions(30)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := int64(20), fakeEtcdClient.Data["/registry/pods/default/foo"].R.Node.TTL; e != a {
+		t.Errorf("expected ttl %d, got %d", e, a)
+	}
+
+	// a shorter grace period replaces it
+	if _, err := storage.Delete(ctx, "foo", api.NewDeleteOptions(5)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := int64(5), fakeEtcdClient.Data["/registry/pods/default/foo"].R.Node.TTL; e != a {
+		t.Errorf("expected ttl %d, got %d", e, a)
+	}
+
+	// a zero grace period removes it immediately
+	if _, err := storage.Delete(ctx, "foo", api.NewDeleteOptions(0)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := storage.Get(ctx, "foo"); !errors.IsNotFound(err) {
+		t.Errorf("expected pod to be deleted: %v", err)
+	}
+}
+
+func TestEtcdUpdateStatusTerminatingPod(t *testing.T) {
+	registry, _, status, fakeClient, _ := newStorage(t)
+	ctx := api.NewDefaultContext()
+	key, _ := registry.KeyFunc(ctx, "foo")
+	pod := validChangedPod()
+	pod.Spec.Host = "machine"
+	expiration := time.Now().Add(20 * time.Second)
+	fakeClient.Data[key] = tools.EtcdResponseWithError{
+		R: &etcd.Response{
+			Node: &etcd.Node{
+				Value:         runtime.EncodeOrDie(latest.Codec, pod),
+				ModifiedIndex: 1,
+				Expiration:    &expiration,
+				TTL:           20,
+			},
+		},
+	}
+
+	podIn := *pod
+	podIn.Status = api.PodStatus{Phase: api.PodSucceeded}
+	if _, _, err := status.Update(ctx, &podIn); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ttl := fakeClient.Data[key].R.Node.TTL; ttl < 19 || ttl > 20 {
+		t.Errorf("expected the remaining ttl to be preserved, got %d", ttl)
+	}
+}
+
 // TestEtcdGetDifferentNamespace ensures same-name pods in different namespaces do not clash
 func TestEtcdGetDifferentNamespace(t *testing.T) {
 	registry, _, _, fakeClient, _ := newStorage(t)
@@ -64,6 +64,7 @@ func (podStrategy) PrepareForUpdate(obj, old runtime.Object) {
 	newPod := obj.(*api.Pod)
 	oldPod := old.(*api.Pod)
 	newPod.Status = oldPod.Status
+	newPod.DeletionTimestamp = oldPod.DeletionTimestamp
 }
 
 // Validate validates a new pod.
@@ -82,9 +83,25 @@ func (podStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.Validatio
 	return validation.ValidatePodUpdate(obj.(*api.Pod), old.(*api.Pod))
 }
 
-// CheckGracefulDelete allows a pod to be gracefully deleted.
+// CheckGracefulDelete allows a pod to be gracefully deleted. The grace period defaults
+// to the pod's termination grace period, and pods that were never scheduled to a host
+// are deleted immediately since no kubelet will confirm their termination.
 func (podStrategy) CheckGracefulDelete(obj runtime.Object, options *api.DeleteOptions) bool {
-	return false
+	if options == nil {
+		return false
+	}
+	pod := obj.(*api.Pod)
+	period := api.DefaultTerminationGracePeriodSeconds
+	if options.GracePeriodSeconds != nil {
+		period = *options.GracePeriodSeconds
+	} else if pod.Spec.TerminationGracePeriodSeconds != nil {
+		period = *pod.Spec.TerminationGracePeriodSeconds
+	}
+	if len(pod.Spec.Host) == 0 {
+		period = 0
+	}
+	options.GracePeriodSeconds = &period
+	return true
 }
 
 type podStatusStrategy struct {
@@ -97,6 +114,7 @@ func (podStatusStrategy) PrepareForUpdate(obj, old runtime.Object) {
 	newPod := obj.(*api.Pod)
 	oldPod := old.(*api.Pod)
 	newPod.Spec = oldPod.Spec
+	newPod.DeletionTimestamp = oldPod.DeletionTimestamp
 }
 
 func (podStatusStrategy) ValidateUpdate(obj, old runtime.Object) fielderrors.ValidationErrorList {
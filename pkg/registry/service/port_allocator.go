@@ -0,0 +1,136 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"fmt"
+	math_rand "math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+// portAllocator hands out node ports from a fixed range.  Like ipAllocator,
+// it only lives in memory and is rebuilt from the services in the registry.
+type portAllocator struct {
+	lock sync.Mutex // protects 'used'
+
+	portRange      util.PortRange
+	used           map[int]bool
+	randomAttempts int
+
+	random *math_rand.Rand
+}
+
+// newPortAllocator creates and intializes a new portAllocator object.
+func newPortAllocator(portRange util.PortRange) *portAllocator {
+	if portRange.Size <= 0 {
+		return nil
+	}
+
+	seed := time.Now().UTC().UnixNano()
+	r := math_rand.New(math_rand.NewSource(seed))
+
+	return &portAllocator{
+		portRange:      portRange,
+		used:           map[int]bool{},
+		random:         r,
+		randomAttempts: 1000,
+	}
+}
+
+// Allocate allocates a specific port.  This is useful when recovering saved state.
+func (pa *portAllocator) Allocate(port int) error {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	if !pa.portRange.Contains(port) {
+		return fmt.Errorf("port %d does not fall within port range %s", port, pa.portRange)
+	}
+
+	if pa.used[port] {
+		return fmt.Errorf("port %d is already allocated", port)
+	}
+	pa.used[port] = true
+
+	return nil
+}
+
+// AllocateNext allocates and returns a new port.
+func (pa *portAllocator) AllocateNext() (int, error) {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	if len(pa.used) >= pa.portRange.Size {
+		return 0, fmt.Errorf("can't find a free port in %s", pa.portRange)
+	}
+
+	// Try randomly first
+	for i := 0; i < pa.randomAttempts; i++ {
+		port := pa.portRange.Base + pa.random.Intn(pa.portRange.Size)
+		if !pa.used[port] {
+			pa.used[port] = true
+			return port, nil
+		}
+	}
+
+	// If that doesn't work, try a linear search
+	for i := 0; i < pa.portRange.Size; i++ {
+		port := pa.portRange.Base + i
+		if !pa.used[port] {
+			pa.used[port] = true
+			return port, nil
+		}
+	}
+
+	return 0, fmt.Errorf("can't find a free port in %s", pa.portRange)
+}
+
+// Release de-allocates a port.
+func (pa *portAllocator) Release(port int) error {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	if !pa.portRange.Contains(port) {
+		return fmt.Errorf("port %d does not fall within port range %s", port, pa.portRange)
+	}
+	delete(pa.used, port)
+	return nil
+}
+
+// Has returns true if the port is currently allocated.
+func (pa *portAllocator) Has(port int) bool {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	return pa.used[port]
+}
+
+// Allocated returns the currently allocated ports, in ascending order.
+func (pa *portAllocator) Allocated() []int {
+	pa.lock.Lock()
+	defer pa.lock.Unlock()
+
+	ports := make([]int, 0, len(pa.used))
+	for port := range pa.used {
+		ports = append(ports, port)
+	}
+	sort.Ints(ports)
+	return ports
+}
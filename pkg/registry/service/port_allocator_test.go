@@ -0,0 +1,129 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package service
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+)
+
+func TestNewPortAllocator(t *testing.T) {
+	if newPortAllocator(util.PortRange{}) != nil {
+		t.Errorf("expected nil")
+	}
+	pa := newPortAllocator(util.PortRange{Base: 30000, Size: 100})
+	if pa == nil {
+		t.Fatalf("expected non-nil")
+	}
+	if len(pa.Allocated()) != 0 {
+		t.Errorf("expected no ports to be allocated, got %v", pa.Allocated())
+	}
+}
+
+func TestPortAllocate(t *testing.T) {
+	pa := newPortAllocator(util.PortRange{Base: 30000, Size: 100})
+
+	if err := pa.Allocate(29999); err == nil {
+		t.Errorf("expected failure")
+	}
+	if err := pa.Allocate(30100); err == nil {
+		t.Errorf("expected failure")
+	}
+	if err := pa.Allocate(30000); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	if err := pa.Allocate(30099); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	if err := pa.Allocate(30000); err == nil {
+		t.Errorf("expected failure")
+	}
+	if !pa.Has(30000) || !pa.Has(30099) || pa.Has(30001) {
+		t.Errorf("unexpected allocations: %v", pa.Allocated())
+	}
+}
+
+func TestPortAllocateNext(t *testing.T) {
+	pa := newPortAllocator(util.PortRange{Base: 30000, Size: 8})
+
+	// Turn off random allocation attempts, so we just allocate in sequence
+	pa.randomAttempts = 0
+	for i := 0; i < 8; i++ {
+		port, err := pa.AllocateNext()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port != 30000+i {
+			t.Errorf("expected %d, got %d", 30000+i, port)
+		}
+	}
+	if _, err := pa.AllocateNext(); err == nil {
+		t.Errorf("expected failure on an exhausted range")
+	}
+
+	if err := pa.Release(30003); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	port, err := pa.AllocateNext()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if port != 30003 {
+		t.Errorf("expected 30003, got %d", port)
+	}
+}
+
+func TestPortAllocateNextRandom(t *testing.T) {
+	pa := newPortAllocator(util.PortRange{Base: 30000, Size: 50})
+
+	seen := map[int]bool{}
+	for i := 0; i < 50; i++ {
+		port, err := pa.AllocateNext()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if port < 30000 || port >= 30050 {
+			t.Errorf("port %d is out of range", port)
+		}
+		if seen[port] {
+			t.Errorf("port %d was allocated twice", port)
+		}
+		seen[port] = true
+	}
+}
+
+func TestPortRelease(t *testing.T) {
+	pa := newPortAllocator(util.PortRange{Base: 30000, Size: 100})
+
+	if err := pa.Release(29999); err == nil {
+		t.Errorf("expected failure")
+	}
+	pa.Allocate(30001)
+	pa.Allocate(30002)
+	if err := pa.Release(30001); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	// Releasing an unallocated port is a no-op.
+	if err := pa.Release(30050); err != nil {
+		t.Errorf("expected success, got %v", err)
+	}
+	if e, a := []int{30002}, pa.Allocated(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+}
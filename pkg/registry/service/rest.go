@@ -33,6 +33,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/endpoint"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/minion"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util/fielderrors"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
 	"github.com/cnaize/kubernetes/pkg/api"
@@ -47,12 +48,17 @@ type REST struct {
 	machines    minion.Registry
 	endpoints   endpoint.Registry
 	portalMgr   *ipAllocator
+	nodePortMgr *portAllocator
 	clusterName string
+
+	// nodePortLeaks counts, per node port, the consecutive repair passes
+	// that found the port allocated but not used by any service.
+	nodePortLeaks map[int]int
 }
 
 // NewStorage returns a new REST.
 func NewStorage(registry Registry, cloud cloudprovider.Interface, machines minion.Registry, endpoints endpoint.Registry, portalNet *net.IPNet,
-	clusterName string) *REST {
+	nodePortRange util.PortRange, clusterName string) *REST {
 	// TODO: Before we can replicate masters, this has to be synced (e.g. lives in etcd)
 	ipa := newIPAllocator(portalNet)
 	if ipa == nil {
@@ -60,14 +66,28 @@ func NewStorage(registry Registry, cloud cloudprovider.Interface, machines minio
 	}
 	reloadIPsFromStorage(ipa, registry)
 
-	return &REST{
-		registry:    registry,
-		cloud:       cloud,
-		machines:    machines,
-		endpoints:   endpoints,
-		portalMgr:   ipa,
-		clusterName: clusterName,
+	// TODO: Like the portal IPs, node ports have to be synced before we can replicate masters.
+	pa := newPortAllocator(nodePortRange)
+	if pa == nil {
+		glog.Fatalf("Failed to create a node port allocator. Is port range '%v' valid?", nodePortRange)
+	}
+
+	rs := &REST{
+		registry:      registry,
+		cloud:         cloud,
+		machines:      machines,
+		endpoints:     endpoints,
+		portalMgr:     ipa,
+		nodePortMgr:   pa,
+		clusterName:   clusterName,
+		nodePortLeaks: map[int]int{},
+	}
+	// Mark all previously allocated node ports in the allocator.
+	if err := rs.RepairNodePorts(); err != nil {
+		// This is really bad.
+		glog.Errorf("can't init node ports for service REST: %v", err)
 	}
+	return rs
 }
 
 // Helper: mark all previously allocated IPs in the allocator.
@@ -90,6 +110,123 @@ func reloadIPsFromStorage(ipa *ipAllocator, registry Registry) {
 	}
 }
 
+// numRepairsBeforeLeakCleanup is the number of consecutive repair passes
+// which must find a node port allocated but unused before it is released.
+// This gives a create that has allocated a port time to persist its service.
+const numRepairsBeforeLeakCleanup = 3
+
+// RepairNodePorts reconciles the node port allocator with the services in
+// the registry: ports used by a stored service but missing from the allocator
+// are marked allocated, and ports which stay allocated without any service
+// using them are released.  It is not safe to call concurrently with itself.
+func (rs *REST) RepairNodePorts() error {
+	services, err := rs.registry.ListServices(api.NewContext())
+	if err != nil {
+		return fmt.Errorf("unable to list services to repair node ports: %v", err)
+	}
+
+	inUse := map[int]bool{}
+	for i := range services.Items {
+		service := &services.Items[i]
+		for _, port := range serviceNodePorts(service) {
+			if inUse[port] {
+				// This is really bad.
+				glog.Errorf("service %s/%s node port %d is also used by another service", service.Namespace, service.Name, port)
+				continue
+			}
+			inUse[port] = true
+			if rs.nodePortMgr.Has(port) {
+				continue
+			}
+			if err := rs.nodePortMgr.Allocate(port); err != nil {
+				// This is really bad.
+				glog.Errorf("service %s/%s node port %d could not be allocated: %v", service.Namespace, service.Name, port, err)
+				continue
+			}
+			glog.V(2).Infof("Repaired node port %d for service %s/%s", port, service.Namespace, service.Name)
+		}
+	}
+
+	leaks := map[int]int{}
+	for _, port := range rs.nodePortMgr.Allocated() {
+		if inUse[port] {
+			continue
+		}
+		count := rs.nodePortLeaks[port] + 1
+		if count < numRepairsBeforeLeakCleanup {
+			leaks[port] = count
+			continue
+		}
+		glog.Warningf("Releasing node port %d, which is allocated but not used by any service", port)
+		rs.nodePortMgr.Release(port)
+	}
+	rs.nodePortLeaks = leaks
+	return nil
+}
+
+// serviceNodePorts returns the node ports assigned to a service.
+func serviceNodePorts(service *api.Service) []int {
+	ports := []int{}
+	if service.Spec.Type != api.ServiceTypeNodePort {
+		return ports
+	}
+	for i := range service.Spec.Ports {
+		if port := service.Spec.Ports[i].NodePort; port != 0 {
+			ports = append(ports, port)
+		}
+	}
+	return ports
+}
+
+// allocateNodePorts assigns a node port to each port of a NodePort service,
+// respecting any port the user requested.  Ports in 'held' already belong to
+// the service and are left alone.  It returns the newly allocated ports; on
+// failure, nothing stays allocated.
+func (rs *REST) allocateNodePorts(service *api.Service, held []int) ([]int, error) {
+	allocated := []int{}
+	if service.Spec.Type != api.ServiceTypeNodePort {
+		return allocated, nil
+	}
+	isHeld := map[int]bool{}
+	for _, port := range held {
+		isHeld[port] = true
+	}
+
+	// Requested ports go first, so that AllocateNext can not take them.
+	for i := range service.Spec.Ports {
+		sp := &service.Spec.Ports[i]
+		if sp.NodePort == 0 || isHeld[sp.NodePort] {
+			continue
+		}
+		if err := rs.nodePortMgr.Allocate(sp.NodePort); err != nil {
+			rs.releaseNodePorts(allocated)
+			el := fielderrors.ValidationErrorList{fielderrors.NewFieldInvalid(fmt.Sprintf("spec.ports[%d].nodePort", i), sp.NodePort, err.Error())}
+			return nil, errors.NewInvalid("Service", service.Name, el)
+		}
+		allocated = append(allocated, sp.NodePort)
+	}
+	for i := range service.Spec.Ports {
+		sp := &service.Spec.Ports[i]
+		if sp.NodePort != 0 {
+			continue
+		}
+		port, err := rs.nodePortMgr.AllocateNext()
+		if err != nil {
+			rs.releaseNodePorts(allocated)
+			return nil, err
+		}
+		sp.NodePort = port
+		allocated = append(allocated, port)
+	}
+	return allocated, nil
+}
+
+func (rs *REST) releaseNodePorts(ports []int) {
+	for _, port := range ports {
+		rs.nodePortMgr.Release(port)
+	}
+}
+
 func (rs *REST) Create(ctx api.Context, obj runtime.Object) (runtime.Object, error) {
 	service := obj.(*api.Service)
 
@@ -112,6 +249,14 @@ func (rs *REST) Create(ctx api.Context, obj runtime.Object) (runtime.Object, err
 		}
 	}
 
+	nodePorts, err := rs.allocateNodePorts(service, nil)
+	if err != nil {
+		if api.IsServiceIPSet(service) {
+			rs.portalMgr.Release(net.ParseIP(service.Spec.PortalIP))
+		}
+		return nil, err
+	}
+
 	// TODO: Move this to post-creation rectification loop, so that we make/remove external load balancers
 	// correctly no matter what http operations happen.
 	if service.Spec.CreateExternalLoadBalancer {
@@ -120,6 +265,7 @@ func (rs *REST) Create(ctx api.Context, obj runtime.Object) (runtime.Object, err
 			if api.IsServiceIPSet(service) {
 				rs.portalMgr.Release(net.ParseIP(service.Spec.PortalIP))
 			}
+			rs.releaseNodePorts(nodePorts)
 			return nil, err
 		}
 	}
@@ -129,6 +275,7 @@ func (rs *REST) Create(ctx api.Context, obj runtime.Object) (runtime.Object, err
 		if api.IsServiceIPSet(service) {
 			rs.portalMgr.Release(net.ParseIP(service.Spec.PortalIP))
 		}
+		rs.releaseNodePorts(nodePorts)
 		err = rest.CheckGeneratedNameError(rest.Services, err, service)
 	}
 	return out, err
@@ -150,6 +297,7 @@ func (rs *REST) Delete(ctx api.Context, id string) (runtime.Object, error) {
 	if api.IsServiceIPSet(service) {
 		rs.portalMgr.Release(net.ParseIP(service.Spec.PortalIP))
 	}
+	rs.releaseNodePorts(serviceNodePorts(service))
 	if service.Spec.CreateExternalLoadBalancer {
 		rs.deleteExternalLoadBalancer(ctx, service)
 	}
@@ -209,23 +357,44 @@ func (rs *REST) Update(ctx api.Context, obj runtime.Object) (runtime.Object, boo
 	if errs := validation.ValidateServiceUpdate(oldService, service); len(errs) > 0 {
 		return nil, false, errors.NewInvalid("service", service.Name, errs)
 	}
+	oldNodePorts := serviceNodePorts(oldService)
+	nodePorts, err := rs.allocateNodePorts(service, oldNodePorts)
+	if err != nil {
+		return nil, false, err
+	}
 	// Recreate external load balancer if changed.
 	if externalLoadBalancerNeedsUpdate(oldService, service) {
 		// TODO: support updating existing balancers
 		if oldService.Spec.CreateExternalLoadBalancer {
 			err = rs.deleteExternalLoadBalancer(ctx, oldService)
 			if err != nil {
+				rs.releaseNodePorts(nodePorts)
 				return nil, false, err
 			}
 		}
 		if service.Spec.CreateExternalLoadBalancer {
 			err = rs.createExternalLoadBalancer(ctx, service)
 			if err != nil {
+				rs.releaseNodePorts(nodePorts)
 				return nil, false, err
 			}
 		}
 	}
 	out, err := rs.registry.UpdateService(ctx, service)
+	if err != nil {
+		rs.releaseNodePorts(nodePorts)
+		return nil, false, err
+	}
+	// Release the node ports which the service no longer uses.
+	stillUsed := map[int]bool{}
+	for _, port := range serviceNodePorts(service) {
+		stillUsed[port] = true
+	}
+	for _, port := range oldNodePorts {
+		if !stillUsed[port] {
+			rs.nodePortMgr.Release(port)
+		}
+	}
 	return out, false, err
 }
 
@@ -281,9 +450,9 @@ func (rs *REST) createExternalLoadBalancer(ctx api.Context, service *api.Service
 	if rs.cloud == nil {
 		return fmt.Errorf("requested an external service, but no cloud provider supplied.")
 	}
-	if service.Spec.Protocol != api.ProtocolTCP {
-		// TODO: Support UDP here too.
-		return fmt.Errorf("external load balancers for non TCP services are not currently supported.")
+	ports, err := getPortsForLB(service)
+	if err != nil {
+		return err
 	}
 	balancer, ok := rs.cloud.TCPLoadBalancer()
 	if !ok {
@@ -306,14 +475,14 @@ func (rs *REST) createExternalLoadBalancer(ctx api.Context, service *api.Service
 	var affinityType api.AffinityType = service.Spec.SessionAffinity
 	if len(service.Spec.PublicIPs) > 0 {
 		for _, publicIP := range service.Spec.PublicIPs {
-			_, err = balancer.CreateTCPLoadBalancer(name, zone.Region, net.ParseIP(publicIP), service.Spec.Port, hostsFromMinionList(hosts), affinityType)
+			_, err = balancer.CreateTCPLoadBalancer(name, zone.Region, net.ParseIP(publicIP), ports, hostsFromMinionList(hosts), affinityType)
 			if err != nil {
 				// TODO: have to roll-back any successful calls.
 				return err
 			}
 		}
 	} else {
-		endpoint, err := balancer.CreateTCPLoadBalancer(name, zone.Region, nil, service.Spec.Port, hostsFromMinionList(hosts), affinityType)
+		endpoint, err := balancer.CreateTCPLoadBalancer(name, zone.Region, nil, ports, hostsFromMinionList(hosts), affinityType)
 		if err != nil {
 			return err
 		}
@@ -322,6 +491,21 @@ func (rs *REST) createExternalLoadBalancer(ctx api.Context, service *api.Service
 	return nil
 }
 
+// getPortsForLB returns the service ports to be exposed by an external load
+// balancer.  Only TCP ports are currently supported.
+func getPortsForLB(service *api.Service) ([]int, error) {
+	ports := []int{}
+	for i := range service.Spec.Ports {
+		sp := &service.Spec.Ports[i]
+		if sp.Protocol != api.ProtocolTCP {
+			// TODO: Support UDP here too.
+			return nil, fmt.Errorf("external load balancers for non TCP services are not currently supported.")
+		}
+		ports = append(ports, sp.Port)
+	}
+	return ports, nil
+}
+
 func (rs *REST) deleteExternalLoadBalancer(ctx api.Context, service *api.Service) error {
 	if rs.cloud == nil {
 		return fmt.Errorf("requested an external service, but no cloud provider supplied.")
@@ -353,11 +537,18 @@ func externalLoadBalancerNeedsUpdate(old, new *api.Service) bool {
 		return false
 	}
 	if old.Spec.CreateExternalLoadBalancer != new.Spec.CreateExternalLoadBalancer ||
-		old.Spec.Port != new.Spec.Port ||
-		old.Spec.SessionAffinity != new.Spec.SessionAffinity ||
-		old.Spec.Protocol != new.Spec.Protocol {
+		old.Spec.SessionAffinity != new.Spec.SessionAffinity {
 		return true
 	}
+	if len(old.Spec.Ports) != len(new.Spec.Ports) {
+		return true
+	}
+	for i := range old.Spec.Ports {
+		if old.Spec.Ports[i].Port != new.Spec.Ports[i].Port ||
+			old.Spec.Ports[i].Protocol != new.Spec.Ports[i].Protocol {
+			return true
+		}
+	}
 	if len(old.Spec.PublicIPs) != len(new.Spec.PublicIPs) {
 		return true
 	}
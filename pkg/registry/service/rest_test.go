@@ -19,6 +19,7 @@ package service
 import (
 	"fmt"
 	"net"
+	"reflect"
 	"strings"
 	"testing"
 
@@ -29,6 +30,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/registry/registrytest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
@@ -40,7 +42,7 @@ func NewTestREST(t *testing.T, endpoints *api.EndpointsList) (*REST, *registryte
 		Endpoints: endpoints,
 	}
 	nodeRegistry := registrytest.NewMinionRegistry(machines, api.NodeResources{})
-	storage := NewStorage(registry, fakeCloud, nodeRegistry, endpointRegistry, makeIPNet(t), "kubernetes")
+	storage := NewStorage(registry, fakeCloud, nodeRegistry, endpointRegistry, makeIPNet(t), makePortRange(t), "kubernetes")
 	return storage, registry, fakeCloud
 }
 
@@ -52,6 +54,14 @@ func makeIPNet(t *testing.T) *net.IPNet {
 	return net
 }
 
+func makePortRange(t *testing.T) util.PortRange {
+	pr, err := util.ParsePortRange("30000-30099")
+	if err != nil {
+		t.Error(err)
+	}
+	return *pr
+}
+
 func TestServiceRegistryCreate(t *testing.T) {
 	storage, registry, fakeCloud := NewTestREST(t, nil)
 	storage.portalMgr.randomAttempts = 0
@@ -59,10 +69,10 @@ func TestServiceRegistryCreate(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:            6502,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
@@ -98,18 +108,19 @@ func TestServiceStorageValidatesCreate(t *testing.T) {
 		"empty ID": {
 			ObjectMeta: api.ObjectMeta{Name: ""},
 			Spec: api.ServiceSpec{
-				Port:            6502,
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"bar": "baz"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
+				Type:            api.ServiceTypeClusterIP,
 			},
 		},
 		"empty port": {
 			ObjectMeta: api.ObjectMeta{Name: "foo"},
 			Spec: api.ServiceSpec{
+				Ports:           []api.ServicePort{{Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"bar": "baz"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
+				Type:            api.ServiceTypeClusterIP,
 			},
 		},
 	}
@@ -132,7 +143,7 @@ func TestServiceRegistryUpdate(t *testing.T) {
 	svc, err := registry.CreateService(ctx, &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
-			Port:     6502,
+			Ports:    []api.ServicePort{{Port: 6502}},
 			Selector: map[string]string{"bar": "baz1"},
 		},
 	})
@@ -145,10 +156,10 @@ func TestServiceRegistryUpdate(t *testing.T) {
 			Name:            "foo",
 			ResourceVersion: svc.ResourceVersion},
 		Spec: api.ServiceSpec{
-			Port:            6502,
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz2"},
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	})
 	if err != nil {
@@ -175,7 +186,7 @@ func TestServiceStorageValidatesUpdate(t *testing.T) {
 	registry.CreateService(ctx, &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:     6502,
+			Ports:    []api.ServicePort{{Port: 6502}},
 			Selector: map[string]string{"bar": "baz"},
 		},
 	})
@@ -183,19 +194,19 @@ func TestServiceStorageValidatesUpdate(t *testing.T) {
 		"empty ID": {
 			ObjectMeta: api.ObjectMeta{Name: ""},
 			Spec: api.ServiceSpec{
-				Port:            6502,
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"bar": "baz"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
+				Type:            api.ServiceTypeClusterIP,
 			},
 		},
 		"invalid selector": {
 			ObjectMeta: api.ObjectMeta{Name: "foo"},
 			Spec: api.ServiceSpec{
-				Port:            6502,
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 				Selector:        map[string]string{"ThisSelectorFailsValidation": "ok"},
-				Protocol:        api.ProtocolTCP,
 				SessionAffinity: api.AffinityTypeNone,
+				Type:            api.ServiceTypeClusterIP,
 			},
 		},
 	}
@@ -216,11 +227,11 @@ func TestServiceRegistryExternalService(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:                       6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
+			Type:                       api.ServiceTypeClusterIP,
 		},
 	}
 	storage.Create(ctx, svc)
@@ -234,7 +245,7 @@ func TestServiceRegistryExternalService(t *testing.T) {
 	if srv == nil {
 		t.Errorf("Failed to find service: %s", svc.Name)
 	}
-	if len(fakeCloud.Balancers) != 1 || fakeCloud.Balancers[0].Name != "kubernetes-default-foo" || fakeCloud.Balancers[0].Port != 6502 {
+	if len(fakeCloud.Balancers) != 1 || fakeCloud.Balancers[0].Name != "kubernetes-default-foo" || !reflect.DeepEqual(fakeCloud.Balancers[0].Ports, []int{6502}) {
 		t.Errorf("Unexpected balancer created: %v", fakeCloud.Balancers)
 	}
 }
@@ -245,11 +256,11 @@ func TestServiceRegistryExternalServiceError(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
-			Port:                       6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
+			Type:                       api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
@@ -268,9 +279,10 @@ func TestServiceRegistryDelete(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	registry.CreateService(ctx, svc)
@@ -289,10 +301,11 @@ func TestServiceRegistryDeleteExternal(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
+			Ports:                      []api.ServicePort{{Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
+			Type:                       api.ServiceTypeClusterIP,
 		},
 	}
 	registry.CreateService(ctx, svc)
@@ -313,11 +326,11 @@ func TestServiceRegistryUpdateExternalService(t *testing.T) {
 	svc1 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1"},
 		Spec: api.ServiceSpec{
-			Port:                       6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: false,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
+			Type:                       api.ServiceTypeClusterIP,
 		},
 	}
 	storage.Create(ctx, svc1)
@@ -337,7 +350,7 @@ func TestServiceRegistryUpdateExternalService(t *testing.T) {
 	// Change port.
 	svc3 := new(api.Service)
 	*svc3 = *svc2
-	svc3.Spec.Port = 6504
+	svc3.Spec.Ports = []api.ServicePort{{Port: 6504, Protocol: api.ProtocolTCP}}
 	storage.Update(ctx, svc3)
 	if len(fakeCloud.Calls) != 6 || fakeCloud.Calls[0] != "get-zone" || fakeCloud.Calls[1] != "create" ||
 		fakeCloud.Calls[2] != "get-zone" || fakeCloud.Calls[3] != "delete" ||
@@ -467,10 +480,10 @@ func TestServiceRegistryIPAllocation(t *testing.T) {
 	svc1 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
@@ -486,10 +499,10 @@ func TestServiceRegistryIPAllocation(t *testing.T) {
 	svc2 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "bar"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		}}
 	ctx = api.NewDefaultContext()
 	created_svc2, _ := rest.Create(ctx, svc2)
@@ -504,11 +517,11 @@ func TestServiceRegistryIPAllocation(t *testing.T) {
 	svc3 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "quux"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
 			PortalIP:        "1.2.3.93",
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx = api.NewDefaultContext()
@@ -526,10 +539,10 @@ func TestServiceRegistryIPReallocation(t *testing.T) {
 	svc1 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
@@ -547,10 +560,10 @@ func TestServiceRegistryIPReallocation(t *testing.T) {
 	svc2 := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "bar"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx = api.NewDefaultContext()
@@ -571,17 +584,17 @@ func TestServiceRegistryIPUpdate(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1"},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
 	created_svc, _ := rest.Create(ctx, svc)
 	created_service := created_svc.(*api.Service)
-	if created_service.Spec.Port != 6502 {
-		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Port)
+	if created_service.Spec.Ports[0].Port != 6502 {
+		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Ports[0].Port)
 	}
 	if created_service.Spec.PortalIP != "1.2.3.1" {
 		t.Errorf("Unexpected PortalIP: %s", created_service.Spec.PortalIP)
@@ -589,16 +602,16 @@ func TestServiceRegistryIPUpdate(t *testing.T) {
 
 	update := new(api.Service)
 	*update = *created_service
-	update.Spec.Port = 6503
+	update.Spec.Ports[0].Port = 6503
 
 	updated_svc, _, _ := rest.Update(ctx, update)
 	updated_service := updated_svc.(*api.Service)
-	if updated_service.Spec.Port != 6503 {
-		t.Errorf("Expected port 6503, but got %v", updated_service.Spec.Port)
+	if updated_service.Spec.Ports[0].Port != 6503 {
+		t.Errorf("Expected port 6503, but got %v", updated_service.Spec.Ports[0].Port)
 	}
 
 	*update = *created_service
-	update.Spec.Port = 6503
+	update.Spec.Ports[0].Port = 6503
 	update.Spec.PortalIP = "1.2.3.76" // error
 
 	_, _, err := rest.Update(ctx, update)
@@ -614,18 +627,18 @@ func TestServiceRegistryIPExternalLoadBalancer(t *testing.T) {
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", ResourceVersion: "1"},
 		Spec: api.ServiceSpec{
-			Selector: map[string]string{"bar": "baz"},
-			Port:     6502,
+			Ports:                      []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
+			Selector:                   map[string]string{"bar": "baz"},
 			CreateExternalLoadBalancer: true,
-			Protocol:                   api.ProtocolTCP,
 			SessionAffinity:            api.AffinityTypeNone,
+			Type:                       api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
 	created_svc, _ := rest.Create(ctx, svc)
 	created_service := created_svc.(*api.Service)
-	if created_service.Spec.Port != 6502 {
-		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Port)
+	if created_service.Spec.Ports[0].Port != 6502 {
+		t.Errorf("Expected port 6502, but got %v", created_service.Spec.Ports[0].Port)
 	}
 	if created_service.Spec.PortalIP != "1.2.3.1" {
 		t.Errorf("Unexpected PortalIP: %s", created_service.Spec.PortalIP)
@@ -638,7 +651,7 @@ func TestServiceRegistryIPExternalLoadBalancer(t *testing.T) {
 	if err != nil {
 		t.Errorf("Unexpected error %v", err)
 	}
-	if len(fakeCloud.Balancers) != 1 || fakeCloud.Balancers[0].Name != "kubernetes-default-foo" || fakeCloud.Balancers[0].Port != 6502 {
+	if len(fakeCloud.Balancers) != 1 || fakeCloud.Balancers[0].Name != "kubernetes-default-foo" || !reflect.DeepEqual(fakeCloud.Balancers[0].Ports, []int{6502}) {
 		t.Errorf("Unexpected balancer created: %v", fakeCloud.Balancers)
 	}
 }
@@ -649,16 +662,16 @@ func TestServiceRegistryIPReloadFromStorage(t *testing.T) {
 	machines := []string{"foo", "bar", "baz"}
 	nodeRegistry := registrytest.NewMinionRegistry(machines, api.NodeResources{})
 	endpoints := &registrytest.EndpointRegistry{}
-	rest1 := NewStorage(registry, fakeCloud, nodeRegistry, endpoints, makeIPNet(t), "kubernetes")
+	rest1 := NewStorage(registry, fakeCloud, nodeRegistry, endpoints, makeIPNet(t), makePortRange(t), "kubernetes")
 	rest1.portalMgr.randomAttempts = 0
 
 	svc := &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	ctx := api.NewDefaultContext()
@@ -666,26 +679,26 @@ func TestServiceRegistryIPReloadFromStorage(t *testing.T) {
 	svc = &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	rest1.Create(ctx, svc)
 
 	// This will reload from storage, finding the previous 2
 	nodeRegistry = registrytest.NewMinionRegistry(machines, api.NodeResources{})
-	rest2 := NewStorage(registry, fakeCloud, nodeRegistry, endpoints, makeIPNet(t), "kubernetes")
+	rest2 := NewStorage(registry, fakeCloud, nodeRegistry, endpoints, makeIPNet(t), makePortRange(t), "kubernetes")
 	rest2.portalMgr.randomAttempts = 0
 
 	svc = &api.Service{
 		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault},
 		Spec: api.ServiceSpec{
+			Ports:           []api.ServicePort{{Port: 6502, Protocol: api.ProtocolTCP}},
 			Selector:        map[string]string{"bar": "baz"},
-			Port:            6502,
-			Protocol:        api.ProtocolTCP,
 			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeClusterIP,
 		},
 	}
 	created_svc, _ := rest2.Create(ctx, svc)
@@ -695,6 +708,171 @@ func TestServiceRegistryIPReloadFromStorage(t *testing.T) {
 	}
 }
 
+func makeNodePortService(name string, nodePorts ...int) *api.Service {
+	svc := &api.Service{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: api.NamespaceDefault},
+		Spec: api.ServiceSpec{
+			Selector:        map[string]string{"bar": "baz"},
+			SessionAffinity: api.AffinityTypeNone,
+			Type:            api.ServiceTypeNodePort,
+		},
+	}
+	for i, nodePort := range nodePorts {
+		svc.Spec.Ports = append(svc.Spec.Ports, api.ServicePort{
+			Name:     fmt.Sprintf("p%d", i),
+			Port:     6502 + i,
+			Protocol: api.ProtocolTCP,
+			NodePort: nodePort,
+		})
+	}
+	return svc
+}
+
+func TestServiceRegistryCreateNodePort(t *testing.T) {
+	storage, _, _ := NewTestREST(t, nil)
+	ctx := api.NewDefaultContext()
+
+	created, err := storage.Create(ctx, makeNodePortService("foo", 0, 30050))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	ports := created.(*api.Service).Spec.Ports
+	if pr := makePortRange(t); !pr.Contains(ports[0].NodePort) {
+		t.Errorf("Unexpected allocated node port: %d", ports[0].NodePort)
+	}
+	if ports[1].NodePort != 30050 {
+		t.Errorf("Expected requested node port 30050, got %d", ports[1].NodePort)
+	}
+	for _, sp := range ports {
+		if !storage.nodePortMgr.Has(sp.NodePort) {
+			t.Errorf("Expected node port %d to be allocated", sp.NodePort)
+		}
+	}
+
+	// A second service can not take the same port.
+	_, err = storage.Create(ctx, makeNodePortService("bar", 30050))
+	if err == nil || !errors.IsInvalid(err) {
+		t.Errorf("Expected invalid error for a conflicting node port, got %v", err)
+	}
+	// Nor one outside of the range.
+	_, err = storage.Create(ctx, makeNodePortService("baz", 31000))
+	if err == nil || !errors.IsInvalid(err) {
+		t.Errorf("Expected invalid error for an out of range node port, got %v", err)
+	}
+	if e, a := 2, len(storage.nodePortMgr.Allocated()); e != a {
+		t.Errorf("Expected %d allocated node ports, got %d", e, a)
+	}
+}
+
+func TestServiceRegistryCreateNodePortRollback(t *testing.T) {
+	storage, registry, _ := NewTestREST(t, nil)
+	registry.Err = fmt.Errorf("test error")
+	ctx := api.NewDefaultContext()
+
+	if _, err := storage.Create(ctx, makeNodePortService("foo", 0, 30050)); err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if allocated := storage.nodePortMgr.Allocated(); len(allocated) != 0 {
+		t.Errorf("Expected node ports to be released, got %v", allocated)
+	}
+}
+
+func TestServiceRegistryDeleteNodePort(t *testing.T) {
+	storage, _, _ := NewTestREST(t, nil)
+	ctx := api.NewDefaultContext()
+
+	if _, err := storage.Create(ctx, makeNodePortService("foo", 30050)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := storage.Delete(ctx, "foo"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if storage.nodePortMgr.Has(30050) {
+		t.Errorf("Expected node port 30050 to be released")
+	}
+}
+
+func TestServiceRegistryUpdateNodePort(t *testing.T) {
+	storage, _, _ := NewTestREST(t, nil)
+	ctx := api.NewDefaultContext()
+
+	created, err := storage.Create(ctx, makeNodePortService("foo", 30050, 30051))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	portalIP := created.(*api.Service).Spec.PortalIP
+
+	// Keep one port, move the other.
+	update := makeNodePortService("foo", 30050, 30060)
+	update.ResourceVersion = "1"
+	update.Spec.PortalIP = portalIP
+	if _, _, err := storage.Update(ctx, update); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if e, a := []int{30050, 30060}, storage.nodePortMgr.Allocated(); !reflect.DeepEqual(e, a) {
+		t.Errorf("Expected allocated node ports %v, got %v", e, a)
+	}
+
+	// Switching to ClusterIP gives up all node ports.
+	update = makeNodePortService("foo", 0, 0)
+	update.Spec.Type = api.ServiceTypeClusterIP
+	update.ResourceVersion = "1"
+	update.Spec.PortalIP = portalIP
+	if _, _, err := storage.Update(ctx, update); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if allocated := storage.nodePortMgr.Allocated(); len(allocated) != 0 {
+		t.Errorf("Expected node ports to be released, got %v", allocated)
+	}
+}
+
+func TestServiceRegistryRepairNodePorts(t *testing.T) {
+	storage, registry, _ := NewTestREST(t, nil)
+
+	// The registry knows about a port the allocator has lost track of, and
+	// the allocator holds a port no service uses.
+	registry.List.Items = []api.Service{*makeNodePortService("foo", 30010)}
+	storage.nodePortMgr.Allocate(30020)
+
+	if err := storage.RepairNodePorts(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !storage.nodePortMgr.Has(30010) {
+		t.Errorf("Expected node port 30010 to be repaired")
+	}
+	for i := 1; i < numRepairsBeforeLeakCleanup; i++ {
+		if !storage.nodePortMgr.Has(30020) {
+			t.Errorf("Expected leaked node port 30020 to be kept after %d repairs", i)
+		}
+		storage.RepairNodePorts()
+	}
+	if storage.nodePortMgr.Has(30020) {
+		t.Errorf("Expected leaked node port 30020 to be released")
+	}
+	if !storage.nodePortMgr.Has(30010) {
+		t.Errorf("Expected node port 30010 to stay allocated")
+	}
+
+	registry.SetError(fmt.Errorf("test error"))
+	if err := storage.RepairNodePorts(); err == nil {
+		t.Errorf("Expected an error")
+	}
+}
+
+func TestServiceRegistryNodePortReloadFromStorage(t *testing.T) {
+	storage, registry, _ := NewTestREST(t, nil)
+	ctx := api.NewDefaultContext()
+	if _, err := storage.Create(ctx, makeNodePortService("foo", 30050)); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	nodeRegistry := registrytest.NewMinionRegistry([]string{"foo"}, api.NodeResources{})
+	storage2 := NewStorage(registry, &cloud.FakeCloud{}, nodeRegistry, &registrytest.EndpointRegistry{}, makeIPNet(t), makePortRange(t), "kubernetes")
+	if !storage2.nodePortMgr.Has(30050) {
+		t.Errorf("Expected node port 30050 to be reloaded from storage")
+	}
+}
+
 // TODO: remove, covered by TestCreate
 func TestCreateServiceWithConflictingNamespace(t *testing.T) {
 	storage := REST{}
@@ -741,11 +919,11 @@ func TestCreate(t *testing.T) {
 		// valid
 		&api.Service{
 			Spec: api.ServiceSpec{
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: "TCP"}},
 				Selector:        map[string]string{"bar": "baz"},
 				PortalIP:        "None",
-				Port:            6502,
-				Protocol:        "TCP",
 				SessionAffinity: "None",
+				Type:            api.ServiceTypeClusterIP,
 			},
 		},
 		// invalid
@@ -755,11 +933,11 @@ func TestCreate(t *testing.T) {
 		// invalid
 		&api.Service{
 			Spec: api.ServiceSpec{
+				Ports:           []api.ServicePort{{Port: 6502, Protocol: "TCP"}},
 				Selector:        map[string]string{"bar": "baz"},
-				Port:            6502,
-				Protocol:        "TCP",
 				PortalIP:        "invalid",
 				SessionAffinity: "None",
+				Type:            api.ServiceTypeClusterIP,
 			},
 		},
 	)
@@ -0,0 +1,87 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/rest"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// Registry is an interface implemented by things that know how to store ServiceAccount objects.
+type Registry interface {
+	// ListServiceAccounts obtains a list of service accounts having labels which match selector.
+	ListServiceAccounts(ctx api.Context, selector labels.Selector) (*api.ServiceAccountList, error)
+	// Watch for new/changed/deleted service accounts
+	WatchServiceAccounts(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error)
+	// Get a specific service account
+	GetServiceAccount(ctx api.Context, serviceAccountID string) (*api.ServiceAccount, error)
+	// Create a service account based on a specification.
+	CreateServiceAccount(ctx api.Context, serviceAccount *api.ServiceAccount) error
+	// Update an existing service account
+	UpdateServiceAccount(ctx api.Context, serviceAccount *api.ServiceAccount) error
+	// Delete an existing service account
+	DeleteServiceAccount(ctx api.Context, serviceAccountID string) error
+}
+
+// storage puts strong typing around storage calls
+type storage struct {
+	rest.StandardStorage
+}
+
+// NewRegistry returns a new Registry interface for the given Storage. Any mismatched
+// types will panic.
+func NewRegistry(s rest.StandardStorage) Registry {
+	return &storage{s}
+}
+
+func (s *storage) ListServiceAccounts(ctx api.Context, label labels.Selector) (*api.ServiceAccountList, error) {
+	obj, err := s.List(ctx, label, fields.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.ServiceAccountList), nil
+}
+
+func (s *storage) WatchServiceAccounts(ctx api.Context, label labels.Selector, field fields.Selector, resourceVersion string) (watch.Interface, error) {
+	return s.Watch(ctx, label, field, resourceVersion)
+}
+
+func (s *storage) GetServiceAccount(ctx api.Context, serviceAccountID string) (*api.ServiceAccount, error) {
+	obj, err := s.Get(ctx, serviceAccountID)
+	if err != nil {
+		return nil, err
+	}
+	return obj.(*api.ServiceAccount), nil
+}
+
+func (s *storage) CreateServiceAccount(ctx api.Context, serviceAccount *api.ServiceAccount) error {
+	_, err := s.Create(ctx, serviceAccount)
+	return err
+}
+
+func (s *storage) UpdateServiceAccount(ctx api.Context, serviceAccount *api.ServiceAccount) error {
+	_, _, err := s.Update(ctx, serviceAccount)
+	return err
+}
+
+func (s *storage) DeleteServiceAccount(ctx api.Context, serviceAccountID string) error {
+	_, err := s.Delete(ctx, serviceAccountID, nil)
+	return err
+}
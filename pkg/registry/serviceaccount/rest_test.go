@@ -0,0 +1,62 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestServiceAccountStrategy(t *testing.T) {
+	if !Strategy.NamespaceScoped() {
+		t.Errorf("ServiceAccount should be namespace scoped")
+	}
+	if Strategy.AllowCreateOnUpdate() {
+		t.Errorf("ServiceAccount should not allow create on update")
+	}
+	oldServiceAccount := &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault, ResourceVersion: "1"},
+	}
+	updatedServiceAccount := &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault, ResourceVersion: "1"},
+		Secrets:    []api.ObjectReference{{Name: "foo-token-abcde"}},
+	}
+	if errs := Strategy.ValidateUpdate(updatedServiceAccount, oldServiceAccount); len(errs) != 0 {
+		t.Errorf("unexpected error: %v", errs)
+	}
+	updatedServiceAccount.Name = "bar"
+	if errs := Strategy.ValidateUpdate(updatedServiceAccount, oldServiceAccount); len(errs) == 0 {
+		t.Errorf("expected an error renaming a service account")
+	}
+}
+
+func TestMatchServiceAccount(t *testing.T) {
+	serviceAccount := &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{Name: "foo", Labels: map[string]string{"a": "b"}},
+	}
+	matches, err := MatchServiceAccount(labels.SelectorFromSet(labels.Set{"a": "b"}), fields.OneTermEqualSelector("name", "foo")).Matches(serviceAccount)
+	if err != nil || !matches {
+		t.Errorf("expected service account to match: %v", err)
+	}
+	matches, err = MatchServiceAccount(labels.Everything(), fields.OneTermEqualSelector("name", "bar")).Matches(serviceAccount)
+	if err != nil || matches {
+		t.Errorf("expected service account not to match: %v", err)
+	}
+}
@@ -0,0 +1,383 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// topologyIndex pairs each scheduled pod with the labels of the node it runs
+// on. It is built with a single lookup per node, so that matching a term
+// against every node of the cluster costs one scan of the pods rather than
+// one per node.
+type topologyIndex struct {
+	pods []api.Pod
+	// nodeLabels holds the labels of the node of each pod, indexed like pods.
+	nodeLabels []labels.Set
+}
+
+func newTopologyIndex(podLister PodLister, info NodeInfo) (*topologyIndex, error) {
+	pods, err := podLister.List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	idx := &topologyIndex{}
+	nodeLabels := map[string]labels.Set{}
+	for _, pod := range pods {
+		// See MapPodsToMachines for why the location of the pod is read from its status.
+		host := pod.Status.Host
+		if len(host) == 0 {
+			continue
+		}
+		set, found := nodeLabels[host]
+		if !found {
+			// The pods of a node which cannot be found belong to no topology domain.
+			if node, err := info.GetNodeInfo(host); err == nil {
+				set = labels.Set(node.Labels)
+			}
+			nodeLabels[host] = set
+		}
+		idx.pods = append(idx.pods, pod)
+		idx.nodeLabels = append(idx.nodeLabels, set)
+	}
+	return idx, nil
+}
+
+// podMatchesTerm checks whether the term held by owner selects pod.
+func podMatchesTerm(pod, owner *api.Pod, term *api.PodAffinityTerm) bool {
+	if len(term.Namespaces) == 0 {
+		if pod.Namespace != owner.Namespace {
+			return false
+		}
+	} else if !util.NewStringSet(term.Namespaces...).Has(pod.Namespace) {
+		return false
+	}
+	return labels.SelectorFromSet(term.LabelSelector).Matches(labels.Set(pod.Labels))
+}
+
+// domainCounts returns the number of pods selected by the term held by owner
+// in each topology domain, keyed by the value of the topology key.
+func (idx *topologyIndex) domainCounts(owner *api.Pod, term *api.PodAffinityTerm) map[string]int {
+	counts := map[string]int{}
+	for i := range idx.pods {
+		value, ok := idx.nodeLabels[i][term.TopologyKey]
+		if ok && podMatchesTerm(&idx.pods[i], owner, term) {
+			counts[value]++
+		}
+	}
+	return counts
+}
+
+// topologyCounts counts pods by topology domain, keeping the part of each
+// minion, so that the pods of a minion can be counted from another state.
+type topologyCounts struct {
+	// byDomain holds the counts by "key=value" of the topology domains.
+	byDomain map[string]int
+	// byNode holds the part of each minion by topology key.
+	byNode map[string]map[string]int
+	total  int
+}
+
+func newTopologyCounts() *topologyCounts {
+	return &topologyCounts{byDomain: map[string]int{}, byNode: map[string]map[string]int{}}
+}
+
+// add counts n pods in the domain of the minion with the given labels for
+// the topology key. The pods of a minion without the key belong to no domain.
+func (c *topologyCounts) add(node string, nodeLabels labels.Set, key string, n int) {
+	value, ok := nodeLabels[key]
+	if !ok || n == 0 {
+		return
+	}
+	c.byDomain[labelKey(key, value)] += n
+	if _, found := c.byNode[node]; !found {
+		c.byNode[node] = map[string]int{}
+	}
+	c.byNode[node][key] += n
+	c.total += n
+}
+
+// count returns the number of pods in the domain of the minion with the given
+// labels for the topology key, n of which are on the minion.
+func (c *topologyCounts) count(node string, nodeLabels labels.Set, key string, n int) int {
+	value, ok := nodeLabels[key]
+	if !ok {
+		return 0
+	}
+	return c.byDomain[labelKey(key, value)] - c.byNode[node][key] + n
+}
+
+// countOutside returns the number of pods counted on other minions than the given one.
+func (c *topologyCounts) countOutside(node string, key string) int {
+	return c.total - c.byNode[node][key]
+}
+
+// countSelected returns the number of the pods the term held by owner selects.
+func countSelected(pods []api.Pod, owner *api.Pod, term *api.PodAffinityTerm) int {
+	n := 0
+	for i := range pods {
+		if podMatchesTerm(&pods[i], owner, term) {
+			n++
+		}
+	}
+	return n
+}
+
+// countForbidding returns the number of the required anti-affinity terms of
+// the pods which select pod, by topology key.
+func countForbidding(pods []api.Pod, pod *api.Pod) map[string]int {
+	counts := map[string]int{}
+	for i := range pods {
+		existing := &pods[i]
+		if existing.Spec.Affinity == nil || existing.Spec.Affinity.PodAntiAffinity == nil {
+			continue
+		}
+		for j := range existing.Spec.Affinity.PodAntiAffinity.Required {
+			term := &existing.Spec.Affinity.PodAntiAffinity.Required[j]
+			if podMatchesTerm(pod, existing, term) {
+				counts[term.TopologyKey]++
+			}
+		}
+	}
+	return counts
+}
+
+// requiredTerms returns the required pod affinity and anti-affinity terms of the pod.
+func requiredTerms(pod *api.Pod) (affinityTerms, antiAffinityTerms []api.PodAffinityTerm) {
+	if affinity := pod.Spec.Affinity; affinity != nil {
+		if affinity.PodAffinity != nil {
+			affinityTerms = affinity.PodAffinity.Required
+		}
+		if affinity.PodAntiAffinity != nil {
+			antiAffinityTerms = affinity.PodAntiAffinity.Required
+		}
+	}
+	return affinityTerms, antiAffinityTerms
+}
+
+// podAffinityIndex counts, for the pod being scheduled, the pods its required
+// terms select and the required anti-affinity terms of the scheduled pods
+// which select it, by topology domain. It is built once per attempt to
+// schedule the pod; checking a minion then only counts the pods on it.
+type podAffinityIndex struct {
+	// affinity and antiAffinity hold the counts of the required terms of the
+	// pod, indexed like the terms.
+	affinity     []*topologyCounts
+	antiAffinity []*topologyCounts
+	forbidding   *topologyCounts
+	// forbiddingKeys are the topology keys of the terms counted in forbidding.
+	forbiddingKeys util.StringSet
+}
+
+func newPodAffinityIndex(pod *api.Pod, nodes map[string]*NodeState, info NodeInfo) *podAffinityIndex {
+	affinityTerms, antiAffinityTerms := requiredTerms(pod)
+	idx := &podAffinityIndex{forbidding: newTopologyCounts(), forbiddingKeys: util.StringSet{}}
+	for range affinityTerms {
+		idx.affinity = append(idx.affinity, newTopologyCounts())
+	}
+	for range antiAffinityTerms {
+		idx.antiAffinity = append(idx.antiAffinity, newTopologyCounts())
+	}
+	for name, state := range nodes {
+		// The pods of a node which cannot be found belong to no topology domain.
+		node, err := info.GetNodeInfo(name)
+		if err != nil {
+			continue
+		}
+		nodeLabels := labels.Set(node.Labels)
+		pods := state.Pods()
+		for i := range affinityTerms {
+			term := &affinityTerms[i]
+			idx.affinity[i].add(name, nodeLabels, term.TopologyKey, countSelected(pods, pod, term))
+		}
+		for i := range antiAffinityTerms {
+			term := &antiAffinityTerms[i]
+			idx.antiAffinity[i].add(name, nodeLabels, term.TopologyKey, countSelected(pods, pod, term))
+		}
+		for key, n := range countForbidding(pods, pod) {
+			idx.forbidding.add(name, nodeLabels, key, n)
+			idx.forbiddingKeys.Insert(key)
+		}
+	}
+	return idx
+}
+
+// fits checks the pod against the node, counting the pods of the state in
+// place of the pods the index saw on the node.
+func (idx *podAffinityIndex) fits(pod *api.Pod, node *api.Node, state *NodeState) bool {
+	nodeLabels := labels.Set(node.Labels)
+	pods := state.Pods()
+	affinityTerms, antiAffinityTerms := requiredTerms(pod)
+	for i := range affinityTerms {
+		term := &affinityTerms[i]
+		if _, ok := nodeLabels[term.TopologyKey]; !ok {
+			return false
+		}
+		selected := countSelected(pods, pod, term)
+		if idx.affinity[i].count(node.Name, nodeLabels, term.TopologyKey, selected) > 0 {
+			continue
+		}
+		// The first pod of a group of pods with affinity for each other
+		// may go anywhere, as long as no pod of the group runs yet.
+		if idx.affinity[i].countOutside(node.Name, term.TopologyKey) == 0 && podMatchesTerm(pod, pod, term) {
+			continue
+		}
+		return false
+	}
+	for i := range antiAffinityTerms {
+		term := &antiAffinityTerms[i]
+		if idx.antiAffinity[i].count(node.Name, nodeLabels, term.TopologyKey, countSelected(pods, pod, term)) > 0 {
+			return false
+		}
+	}
+	// The pods of the node forbid every domain of the node they have a term for.
+	forbidding := countForbidding(pods, pod)
+	for key := range forbidding {
+		if _, ok := nodeLabels[key]; ok {
+			return false
+		}
+	}
+	for key := range idx.forbiddingKeys {
+		if idx.forbidding.count(node.Name, nodeLabels, key, 0) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+type PodAffinityChecker struct {
+	podLister PodLister
+	info      NodeInfo
+}
+
+func NewPodAffinityPredicate(podLister PodLister, info NodeInfo) FitPredicate {
+	checker := &PodAffinityChecker{
+		podLister: podLister,
+		info:      info,
+	}
+	return checker.CheckPodAffinity
+}
+
+// getIndex returns the index of the attempt the state is looked at by, which
+// is built the first time a minion is checked in the attempt. A state looked
+// at by no attempt is checked against the pods the lister lists.
+func (c *PodAffinityChecker) getIndex(pod *api.Pod, state *NodeState) (*podAffinityIndex, error) {
+	if state == nil || state.attempt == nil {
+		nodes, err := GetNodeStates(c.podLister)
+		if err != nil {
+			return nil, err
+		}
+		return newPodAffinityIndex(pod, nodes, c.info), nil
+	}
+	idx := state.attempt.value(c, func(nodes map[string]*NodeState) interface{} {
+		return newPodAffinityIndex(pod, nodes, c.info)
+	})
+	return idx.(*podAffinityIndex), nil
+}
+
+// CheckPodAffinity checks whether the node satisfies the required pod affinity
+// and anti-affinity terms of the pod, and whether the required anti-affinity
+// terms of the scheduled pods allow the pod on the node. The pods on the node
+// are the ones of the state; the pods on the other nodes are the ones of the
+// attempt to schedule the pod.
+func (c *PodAffinityChecker) CheckPodAffinity(pod api.Pod, state *NodeState, node string) (bool, error) {
+	minion, err := c.info.GetNodeInfo(node)
+	if err != nil {
+		return false, err
+	}
+	idx, err := c.getIndex(&pod, state)
+	if err != nil {
+		return false, err
+	}
+	return idx.fits(&pod, minion, state), nil
+}
+
+type InterPodAffinity struct {
+	info NodeInfo
+}
+
+func NewInterPodAffinityPriority(info NodeInfo) PriorityFunction {
+	interPodAffinity := &InterPodAffinity{
+		info: info,
+	}
+	return interPodAffinity.CalculateInterPodAffinityPriority
+}
+
+// CalculateInterPodAffinityPriority favors the nodes whose topology domains run
+// the pods selected by the preferred pod affinity terms of the pod, and
+// disfavors the ones running the pods selected by its preferred anti-affinity
+// terms. Each term counts its weight once per selected pod; the sums are
+// scaled to 0-10 between the worst and the best node.
+func (p *InterPodAffinity) CalculateInterPodAffinityPriority(pod api.Pod, podLister PodLister, minionLister MinionLister) (HostPriorityList, error) {
+	minions, err := minionLister.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var affinityTerms, antiAffinityTerms []api.WeightedPodAffinityTerm
+	if affinity := pod.Spec.Affinity; affinity != nil {
+		if affinity.PodAffinity != nil {
+			affinityTerms = affinity.PodAffinity.Preferred
+		}
+		if affinity.PodAntiAffinity != nil {
+			antiAffinityTerms = affinity.PodAntiAffinity.Preferred
+		}
+	}
+
+	counts := map[string]int{}
+	if len(affinityTerms) > 0 || len(antiAffinityTerms) > 0 {
+		idx, err := newTopologyIndex(podLister, p.info)
+		if err != nil {
+			return nil, err
+		}
+		addWeights := func(terms []api.WeightedPodAffinityTerm, sign int) {
+			for i := range terms {
+				domainCounts := idx.domainCounts(&pod, &terms[i].PodAffinityTerm)
+				for _, minion := range minions.Items {
+					if value, ok := minion.Labels[terms[i].PodAffinityTerm.TopologyKey]; ok {
+						counts[minion.Name] += sign * terms[i].Weight * domainCounts[value]
+					}
+				}
+			}
+		}
+		addWeights(affinityTerms, 1)
+		addWeights(antiAffinityTerms, -1)
+	}
+
+	minCount, maxCount := 0, 0
+	for i, minion := range minions.Items {
+		count := counts[minion.Name]
+		if i == 0 || count < minCount {
+			minCount = count
+		}
+		if i == 0 || count > maxCount {
+			maxCount = count
+		}
+	}
+
+	result := []HostPriority{}
+	for _, minion := range minions.Items {
+		score := 0
+		if maxCount > minCount {
+			score = 10 * (counts[minion.Name] - minCount) / (maxCount - minCount)
+		}
+		result = append(result, HostPriority{Host: minion.Name, Score: score})
+	}
+	return result, nil
+}
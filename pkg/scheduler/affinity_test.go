@@ -0,0 +1,307 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/types"
+)
+
+func affinityTestNodes() *api.NodeList {
+	return &api.NodeList{Items: []api.Node{
+		{ObjectMeta: api.ObjectMeta{Name: "machine1", Labels: map[string]string{"hostname": "machine1", "zone": "z1"}}},
+		{ObjectMeta: api.ObjectMeta{Name: "machine2", Labels: map[string]string{"hostname": "machine2", "zone": "z1"}}},
+		{ObjectMeta: api.ObjectMeta{Name: "machine3", Labels: map[string]string{"hostname": "machine3", "zone": "z2"}}},
+		{ObjectMeta: api.ObjectMeta{Name: "machine4", Labels: map[string]string{"hostname": "machine4"}}},
+	}}
+}
+
+func affinityTestPod(name, host string, labels map[string]string, affinity *api.Affinity) api.Pod {
+	return api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: api.NamespaceDefault, UID: types.UID(name), Labels: labels},
+		Spec:       api.PodSpec{Affinity: affinity},
+		Status:     api.PodStatus{Host: host},
+	}
+}
+
+func TestCheckPodAffinity(t *testing.T) {
+	db := map[string]string{"app": "db"}
+	cache := map[string]string{"app": "cache"}
+	batch := map[string]string{"app": "batch"}
+	queue := map[string]string{"app": "queue"}
+	existingPods := []api.Pod{
+		affinityTestPod("db-0", "machine1", db, nil),
+		affinityTestPod("cache-0", "machine3", cache, nil),
+		affinityTestPod("web-0", "machine2", map[string]string{"app": "web"}, &api.Affinity{
+			PodAntiAffinity: &api.PodAntiAffinity{Required: []api.PodAffinityTerm{{LabelSelector: batch, TopologyKey: "hostname"}}},
+		}),
+		affinityTestPod("lost-0", "machine9", db, nil),
+	}
+	requiredAffinity := func(selector map[string]string, key string, namespaces ...string) *api.Affinity {
+		return &api.Affinity{PodAffinity: &api.PodAffinity{
+			Required: []api.PodAffinityTerm{{LabelSelector: selector, Namespaces: namespaces, TopologyKey: key}},
+		}}
+	}
+	requiredAntiAffinity := func(selector map[string]string, key string, namespaces ...string) *api.Affinity {
+		return &api.Affinity{PodAntiAffinity: &api.PodAntiAffinity{
+			Required: []api.PodAffinityTerm{{LabelSelector: selector, Namespaces: namespaces, TopologyKey: key}},
+		}}
+	}
+
+	tests := []struct {
+		pod  api.Pod
+		node string
+		fits bool
+		test string
+	}{
+		{
+			pod:  affinityTestPod("p", "", nil, nil),
+			node: "machine1",
+			fits: true,
+			test: "no affinity",
+		},
+		{
+			pod:  affinityTestPod("p", "", db, requiredAntiAffinity(db, "hostname")),
+			node: "machine1",
+			fits: false,
+			test: "anti-affinity, matching pod on the node",
+		},
+		{
+			pod:  affinityTestPod("p", "", db, requiredAntiAffinity(db, "hostname")),
+			node: "machine2",
+			fits: true,
+			test: "anti-affinity, matching pod on another node",
+		},
+		{
+			pod:  affinityTestPod("p", "", db, requiredAntiAffinity(db, "zone")),
+			node: "machine2",
+			fits: false,
+			test: "anti-affinity, matching pod in the zone",
+		},
+		{
+			pod:  affinityTestPod("p", "", db, requiredAntiAffinity(db, "zone")),
+			node: "machine3",
+			fits: true,
+			test: "anti-affinity, matching pod in another zone",
+		},
+		{
+			pod:  affinityTestPod("p", "", db, requiredAntiAffinity(db, "zone")),
+			node: "machine4",
+			fits: true,
+			test: "anti-affinity, node without topology key",
+		},
+		{
+			pod:  affinityTestPod("p", "", db, requiredAntiAffinity(db, "hostname", "other")),
+			node: "machine1",
+			fits: true,
+			test: "anti-affinity, matching pod in another namespace",
+		},
+		{
+			pod:  affinityTestPod("p", "", nil, requiredAffinity(cache, "zone")),
+			node: "machine3",
+			fits: true,
+			test: "affinity, matching pod in the zone",
+		},
+		{
+			pod:  affinityTestPod("p", "", nil, requiredAffinity(cache, "zone")),
+			node: "machine1",
+			fits: false,
+			test: "affinity, matching pod in another zone",
+		},
+		{
+			pod:  affinityTestPod("p", "", nil, requiredAffinity(cache, "zone")),
+			node: "machine4",
+			fits: false,
+			test: "affinity, node without topology key",
+		},
+		{
+			pod:  affinityTestPod("p", "", queue, requiredAffinity(queue, "hostname")),
+			node: "machine1",
+			fits: true,
+			test: "affinity, first pod of the group",
+		},
+		{
+			pod:  affinityTestPod("p", "", nil, requiredAffinity(queue, "hostname")),
+			node: "machine1",
+			fits: false,
+			test: "affinity, no matching pod",
+		},
+		{
+			pod:  affinityTestPod("p", "", batch, nil),
+			node: "machine2",
+			fits: false,
+			test: "anti-affinity of a scheduled pod",
+		},
+		{
+			pod:  affinityTestPod("p", "", batch, nil),
+			node: "machine1",
+			fits: true,
+			test: "anti-affinity of a scheduled pod on another node",
+		},
+	}
+
+	nodeStates, err := GetNodeStates(FakePodLister(existingPods))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, test := range tests {
+		checker := PodAffinityChecker{
+			podLister: FakePodLister(existingPods),
+			info:      StaticNodeInfo{affinityTestNodes()},
+		}
+		state := NewNodeState(nodeStates[test.node].Pods()...)
+		fits, err := checker.CheckPodAffinity(test.pod, state, test.node)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+		// Within an attempt to schedule the pod, the pods come from the
+		// states of the attempt instead of the lister.
+		checker.podLister = FakePodLister{}
+		fits, err = checker.CheckPodAffinity(test.pod, state.inAttempt(newSchedulingAttempt(nodeStates)), test.node)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
+
+// countingPodLister counts the lists of the pods.
+type countingPodLister struct {
+	FakePodLister
+	lists int
+}
+
+func (l *countingPodLister) List(selector labels.Selector) ([]api.Pod, error) {
+	l.lists++
+	return l.FakePodLister.List(selector)
+}
+
+func TestCheckPodAffinityInAttempt(t *testing.T) {
+	batch := map[string]string{"app": "batch"}
+	antiAffinity := &api.Affinity{
+		PodAntiAffinity: &api.PodAntiAffinity{Required: []api.PodAffinityTerm{{LabelSelector: batch, TopologyKey: "zone"}}},
+	}
+	web := affinityTestPod("web-0", "machine1", nil, antiAffinity)
+	podLister := &countingPodLister{FakePodLister: FakePodLister{web}}
+	checker := PodAffinityChecker{
+		podLister: podLister,
+		info:      StaticNodeInfo{affinityTestNodes()},
+	}
+	nodeStates, err := GetNodeStates(FakePodLister{web})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	attempt := newSchedulingAttempt(nodeStates)
+	pod := affinityTestPod("p", "", batch, nil)
+
+	tests := []struct {
+		node  string
+		state *NodeState
+		fits  bool
+		test  string
+	}{
+		{"machine2", NewNodeState(), false, "anti-affinity of a pod on another node of the zone"},
+		{"machine3", NewNodeState(), true, "anti-affinity of a pod in another zone"},
+		{"machine1", NewNodeState(web), false, "anti-affinity of a pod on the node"},
+		{"machine1", NewNodeState(), true, "pod evicted from the node"},
+		{"machine3", NewNodeState(web), false, "pod added to the node"},
+	}
+	for _, test := range tests {
+		fits, err := checker.CheckPodAffinity(pod, test.state.inAttempt(attempt), test.node)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+	if podLister.lists != 0 {
+		t.Errorf("expected the checker not to list the pods within an attempt, got %d lists", podLister.lists)
+	}
+}
+
+func TestInterPodAffinityPriority(t *testing.T) {
+	db := map[string]string{"app": "db"}
+	cache := map[string]string{"app": "cache"}
+	existingPods := []api.Pod{
+		affinityTestPod("db-0", "machine1", db, nil),
+		affinityTestPod("cache-0", "machine3", cache, nil),
+		affinityTestPod("cache-1", "machine3", cache, nil),
+	}
+	tests := []struct {
+		affinity     *api.Affinity
+		expectedList HostPriorityList
+		test         string
+	}{
+		{
+			expectedList: []HostPriority{{"machine1", 0}, {"machine2", 0}, {"machine3", 0}, {"machine4", 0}},
+			test:         "no affinity",
+		},
+		{
+			affinity: &api.Affinity{PodAffinity: &api.PodAffinity{
+				Preferred: []api.WeightedPodAffinityTerm{{Weight: 5, PodAffinityTerm: api.PodAffinityTerm{LabelSelector: db, TopologyKey: "zone"}}},
+			}},
+			expectedList: []HostPriority{{"machine1", 10}, {"machine2", 10}, {"machine3", 0}, {"machine4", 0}},
+			test:         "affinity for the zone",
+		},
+		{
+			affinity: &api.Affinity{PodAntiAffinity: &api.PodAntiAffinity{
+				Preferred: []api.WeightedPodAffinityTerm{{Weight: 5, PodAffinityTerm: api.PodAffinityTerm{LabelSelector: db, TopologyKey: "hostname"}}},
+			}},
+			expectedList: []HostPriority{{"machine1", 0}, {"machine2", 10}, {"machine3", 10}, {"machine4", 10}},
+			test:         "anti-affinity for the node",
+		},
+		{
+			affinity: &api.Affinity{
+				PodAffinity: &api.PodAffinity{
+					Preferred: []api.WeightedPodAffinityTerm{{Weight: 3, PodAffinityTerm: api.PodAffinityTerm{LabelSelector: cache, TopologyKey: "zone"}}},
+				},
+				PodAntiAffinity: &api.PodAntiAffinity{
+					Preferred: []api.WeightedPodAffinityTerm{{Weight: 4, PodAffinityTerm: api.PodAffinityTerm{LabelSelector: db, TopologyKey: "hostname"}}},
+				},
+			},
+			// machine1: -4, machine2: 0, machine3: 3*2 = 6, machine4: 0
+			expectedList: []HostPriority{{"machine1", 0}, {"machine2", 4}, {"machine3", 10}, {"machine4", 4}},
+			test:         "weighted affinity and anti-affinity",
+		},
+	}
+
+	for _, test := range tests {
+		pod := affinityTestPod("p", "", nil, test.affinity)
+		prioritizer := InterPodAffinity{info: StaticNodeInfo{affinityTestNodes()}}
+		list, err := prioritizer.CalculateInterPodAffinityPriority(pod, FakePodLister(existingPods), FakeMinionLister(*affinityTestNodes()))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		// sort the two lists to avoid failures on account of different ordering
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
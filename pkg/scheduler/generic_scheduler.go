@@ -46,6 +46,7 @@ func (f *FitError) Error() string {
 type genericScheduler struct {
 	predicates   map[string]FitPredicate
 	prioritizers []PriorityConfig
+	extenders    []SchedulerExtender
 	pods         PodLister
 	random       *rand.Rand
 	randomLock   sync.Mutex
@@ -60,12 +61,12 @@ func (g *genericScheduler) Schedule(pod api.Pod, minionLister MinionLister) (str
 		return "", fmt.Errorf("no minions available to schedule pods")
 	}
 
-	filteredNodes, failedPredicateMap, err := findNodesThatFit(pod, g.pods, g.predicates, minions)
+	filteredNodes, failedPredicateMap, err := findNodesThatFit(pod, g.pods, g.predicates, g.extenders, minions)
 	if err != nil {
 		return "", err
 	}
 
-	priorityList, err := prioritizeNodes(pod, g.pods, g.prioritizers, FakeMinionLister(filteredNodes))
+	priorityList, err := prioritizeNodes(pod, g.pods, g.prioritizers, g.extenders, FakeMinionLister(filteredNodes))
 	if err != nil {
 		return "", err
 	}
@@ -97,17 +98,20 @@ func (g *genericScheduler) selectHost(priorityList HostPriorityList) (string, er
 
 // Filters the minions to find the ones that fit based on the given predicate functions
 // Each minion is passed through the predicate functions to determine if it is a fit
-func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]FitPredicate, nodes api.NodeList) (api.NodeList, FailedPredicateMap, error) {
+// The minions which fit are then filtered by each of the extenders in turn
+func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]FitPredicate, extenders []SchedulerExtender, nodes api.NodeList) (api.NodeList, FailedPredicateMap, error) {
 	filtered := []api.Node{}
-	machineToPods, err := MapPodsToMachines(podLister)
+	nodeStates, err := GetNodeStates(podLister)
 	failedPredicateMap := FailedPredicateMap{}
 	if err != nil {
 		return api.NodeList{}, FailedPredicateMap{}, err
 	}
+	attempt := newSchedulingAttempt(nodeStates)
 	for _, node := range nodes.Items {
 		fits := true
+		state := nodeStates[node.Name].inAttempt(attempt)
 		for name, predicate := range predicates {
-			fit, err := predicate(pod, machineToPods[node.Name], node.Name)
+			fit, err := predicate(pod, state, node.Name)
 			if err != nil {
 				return api.NodeList{}, FailedPredicateMap{}, err
 			}
@@ -124,6 +128,31 @@ func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]Fi
 			filtered = append(filtered, node)
 		}
 	}
+	for _, extender := range extenders {
+		if len(filtered) == 0 {
+			break
+		}
+		filteredList, err := extender.Filter(pod, api.NodeList{Items: filtered})
+		if err != nil {
+			return api.NodeList{}, FailedPredicateMap{}, err
+		}
+		kept := util.NewStringSet()
+		for _, node := range filteredList.Items {
+			kept.Insert(node.Name)
+		}
+		remaining := []api.Node{}
+		for _, node := range filtered {
+			if kept.Has(node.Name) {
+				remaining = append(remaining, node)
+				continue
+			}
+			if _, found := failedPredicateMap[node.Name]; !found {
+				failedPredicateMap[node.Name] = util.StringSet{}
+			}
+			failedPredicateMap[node.Name].Insert("Extender")
+		}
+		filtered = remaining
+	}
 	return api.NodeList{Items: filtered}, failedPredicateMap, nil
 }
 
@@ -132,13 +161,14 @@ func findNodesThatFit(pod api.Pod, podLister PodLister, predicates map[string]Fi
 // 0 is the lowest priority score (least preferred minion) and 10 is the highest
 // Each priority function can also have its own weight
 // The minion scores returned by the priority function are multiplied by the weights to get weighted scores
+// The weighted scores returned by the extenders are added in the same way
 // All scores are finally combined (added) to get the total weighted scores of all minions
-func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []PriorityConfig, minionLister MinionLister) (HostPriorityList, error) {
+func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []PriorityConfig, extenders []SchedulerExtender, minionLister MinionLister) (HostPriorityList, error) {
 	result := HostPriorityList{}
 
 	// If no priority configs are provided, then the EqualPriority function is applied
 	// This is required to generate the priority list in the required format
-	if len(priorityConfigs) == 0 {
+	if len(priorityConfigs) == 0 && len(extenders) == 0 {
 		return EqualPriority(pod, podLister, minionLister)
 	}
 
@@ -155,11 +185,34 @@ func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []Priorit
 			return HostPriorityList{}, err
 		}
 		for _, hostEntry := range prioritizedList {
-			combinedScores[hostEntry.host] += hostEntry.score * weight
+			combinedScores[hostEntry.Host] += hostEntry.Score * weight
+		}
+	}
+	if len(extenders) > 0 {
+		minions, err := minionLister.List()
+		if err != nil {
+			return HostPriorityList{}, err
+		}
+		// The minions an extender does not score get no points from it, but stay candidates.
+		for _, minion := range minions.Items {
+			if _, found := combinedScores[minion.Name]; !found {
+				combinedScores[minion.Name] = 0
+			}
+		}
+		for _, extender := range extenders {
+			prioritizedList, weight, err := extender.Prioritize(pod, minions)
+			if err != nil {
+				return HostPriorityList{}, err
+			}
+			for _, hostEntry := range prioritizedList {
+				if _, found := combinedScores[hostEntry.Host]; found {
+					combinedScores[hostEntry.Host] += hostEntry.Score * weight
+				}
+			}
 		}
 	}
 	for host, score := range combinedScores {
-		result = append(result, HostPriority{host: host, score: score})
+		result = append(result, HostPriority{Host: host, Score: score})
 	}
 	return result, nil
 }
@@ -167,8 +220,8 @@ func prioritizeNodes(pod api.Pod, podLister PodLister, priorityConfigs []Priorit
 func getBestHosts(list HostPriorityList) []string {
 	result := []string{}
 	for _, hostEntry := range list {
-		if hostEntry.score == list[0].score {
-			result = append(result, hostEntry.host)
+		if hostEntry.Score == list[0].Score {
+			result = append(result, hostEntry.Host)
 		} else {
 			break
 		}
@@ -187,17 +240,18 @@ func EqualPriority(pod api.Pod, podLister PodLister, minionLister MinionLister)
 	result := []HostPriority{}
 	for _, minion := range nodes.Items {
 		result = append(result, HostPriority{
-			host:  minion.Name,
-			score: 1,
+			Host:  minion.Name,
+			Score: 1,
 		})
 	}
 	return result, nil
 }
 
-func NewGenericScheduler(predicates map[string]FitPredicate, prioritizers []PriorityConfig, pods PodLister, random *rand.Rand) Scheduler {
+func NewGenericScheduler(predicates map[string]FitPredicate, prioritizers []PriorityConfig, extenders []SchedulerExtender, pods PodLister, random *rand.Rand) Scheduler {
 	return &genericScheduler{
 		predicates:   predicates,
 		prioritizers: prioritizers,
+		extenders:    extenders,
 		pods:         pods,
 		random:       random,
 	}
@@ -27,15 +27,15 @@ import (
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
-func falsePredicate(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func falsePredicate(pod api.Pod, state *NodeState, node string) (bool, error) {
 	return false, nil
 }
 
-func truePredicate(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func truePredicate(pod api.Pod, state *NodeState, node string) (bool, error) {
 	return true, nil
 }
 
-func matchesPredicate(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func matchesPredicate(pod api.Pod, state *NodeState, node string) (bool, error) {
 	return pod.Name == node, nil
 }
 
@@ -53,8 +53,8 @@ func numericPriority(pod api.Pod, podLister PodLister, minionLister MinionLister
 			return nil, err
 		}
 		result = append(result, HostPriority{
-			host:  minion.Name,
-			score: score,
+			Host:  minion.Name,
+			Score: score,
 		})
 	}
 	return result, nil
@@ -70,19 +70,53 @@ func reverseNumericPriority(pod api.Pod, podLister PodLister, minionLister Minio
 	}
 
 	for _, hostPriority := range result {
-		maxScore = math.Max(maxScore, float64(hostPriority.score))
-		minScore = math.Min(minScore, float64(hostPriority.score))
+		maxScore = math.Max(maxScore, float64(hostPriority.Score))
+		minScore = math.Min(minScore, float64(hostPriority.Score))
 	}
 	for _, hostPriority := range result {
 		reverseResult = append(reverseResult, HostPriority{
-			host:  hostPriority.host,
-			score: int(maxScore + minScore - float64(hostPriority.score)),
+			Host:  hostPriority.Host,
+			Score: int(maxScore + minScore - float64(hostPriority.Score)),
 		})
 	}
 
 	return reverseResult, nil
 }
 
+type fakeExtender struct {
+	filter     func(node string) bool
+	prioritize PriorityFunction
+	weight     int
+	err        error
+}
+
+func (f *fakeExtender) Filter(pod api.Pod, nodes api.NodeList) (api.NodeList, error) {
+	if f.err != nil {
+		return api.NodeList{}, f.err
+	}
+	if f.filter == nil {
+		return nodes, nil
+	}
+	filtered := api.NodeList{}
+	for _, node := range nodes.Items {
+		if f.filter(node.Name) {
+			filtered.Items = append(filtered.Items, node)
+		}
+	}
+	return filtered, nil
+}
+
+func (f *fakeExtender) Prioritize(pod api.Pod, nodes api.NodeList) (HostPriorityList, int, error) {
+	if f.err != nil {
+		return nil, 0, f.err
+	}
+	if f.prioritize == nil {
+		return HostPriorityList{}, 0, nil
+	}
+	result, err := f.prioritize(pod, FakePodLister([]api.Pod{}), FakeMinionLister(nodes))
+	return result, f.weight, err
+}
+
 func makeNodeList(nodeNames []string) api.NodeList {
 	result := api.NodeList{
 		Items: make([]api.Node, len(nodeNames)),
@@ -102,8 +136,8 @@ func TestSelectHost(t *testing.T) {
 	}{
 		{
 			list: []HostPriority{
-				{host: "machine1.1", score: 1},
-				{host: "machine2.1", score: 2},
+				{Host: "machine1.1", Score: 1},
+				{Host: "machine2.1", Score: 2},
 			},
 			possibleHosts: util.NewStringSet("machine2.1"),
 			expectsErr:    false,
@@ -111,10 +145,10 @@ func TestSelectHost(t *testing.T) {
 		// equal scores
 		{
 			list: []HostPriority{
-				{host: "machine1.1", score: 1},
-				{host: "machine1.2", score: 2},
-				{host: "machine1.3", score: 2},
-				{host: "machine2.1", score: 2},
+				{Host: "machine1.1", Score: 1},
+				{Host: "machine1.2", Score: 2},
+				{Host: "machine1.3", Score: 2},
+				{Host: "machine2.1", Score: 2},
 			},
 			possibleHosts: util.NewStringSet("machine1.2", "machine1.3", "machine2.1"),
 			expectsErr:    false,
@@ -122,11 +156,11 @@ func TestSelectHost(t *testing.T) {
 		// out of order scores
 		{
 			list: []HostPriority{
-				{host: "machine1.1", score: 3},
-				{host: "machine1.2", score: 3},
-				{host: "machine2.1", score: 2},
-				{host: "machine3.1", score: 1},
-				{host: "machine1.3", score: 3},
+				{Host: "machine1.1", Score: 3},
+				{Host: "machine1.2", Score: 3},
+				{Host: "machine2.1", Score: 2},
+				{Host: "machine3.1", Score: 1},
+				{Host: "machine1.3", Score: 3},
 			},
 			possibleHosts: util.NewStringSet("machine1.1", "machine1.2", "machine1.3"),
 			expectsErr:    false,
@@ -227,7 +261,7 @@ func TestGenericScheduler(t *testing.T) {
 
 	for _, test := range tests {
 		random := rand.New(rand.NewSource(0))
-		scheduler := NewGenericScheduler(test.predicates, test.prioritizers, FakePodLister([]api.Pod{}), random)
+		scheduler := NewGenericScheduler(test.predicates, test.prioritizers, []SchedulerExtender{}, FakePodLister([]api.Pod{}), random)
 		machine, err := scheduler.Schedule(test.pod, FakeMinionLister(makeNodeList(test.nodes)))
 		if test.expectsErr {
 			if err == nil {
@@ -244,10 +278,80 @@ func TestGenericScheduler(t *testing.T) {
 	}
 }
 
+func TestGenericSchedulerWithExtenders(t *testing.T) {
+	tests := []struct {
+		name         string
+		predicates   map[string]FitPredicate
+		prioritizers []PriorityConfig
+		extenders    []SchedulerExtender
+		nodes        []string
+		expectedHost string
+		expectsErr   bool
+	}{
+		{
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			prioritizers: []PriorityConfig{{Function: numericPriority, Weight: 1}},
+			extenders:    []SchedulerExtender{&fakeExtender{filter: func(node string) bool { return node != "3" }}},
+			nodes:        []string{"3", "2", "1"},
+			expectedHost: "2",
+			name:         "extender filters out the best minion",
+		},
+		{
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			prioritizers: []PriorityConfig{{Function: numericPriority, Weight: 1}},
+			extenders:    []SchedulerExtender{&fakeExtender{filter: func(node string) bool { return false }}},
+			nodes:        []string{"3", "2", "1"},
+			expectsErr:   true,
+			name:         "extender filters out all the minions",
+		},
+		{
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			prioritizers: []PriorityConfig{{Function: numericPriority, Weight: 1}},
+			extenders:    []SchedulerExtender{&fakeExtender{prioritize: reverseNumericPriority, weight: 2}},
+			nodes:        []string{"3", "2", "1"},
+			expectedHost: "1",
+			name:         "extender score outweighs the priority functions",
+		},
+		{
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			extenders:    []SchedulerExtender{&fakeExtender{prioritize: numericPriority, weight: 1}},
+			nodes:        []string{"3", "2", "1"},
+			expectedHost: "3",
+			name:         "extender is the only prioritizer",
+		},
+		{
+			predicates:   map[string]FitPredicate{"true": truePredicate},
+			prioritizers: []PriorityConfig{{Function: numericPriority, Weight: 1}},
+			extenders:    []SchedulerExtender{&fakeExtender{err: fmt.Errorf("extender failed")}},
+			nodes:        []string{"3", "2", "1"},
+			expectsErr:   true,
+			name:         "extender error",
+		},
+	}
+
+	for _, test := range tests {
+		random := rand.New(rand.NewSource(0))
+		scheduler := NewGenericScheduler(test.predicates, test.prioritizers, test.extenders, FakePodLister([]api.Pod{}), random)
+		machine, err := scheduler.Schedule(api.Pod{}, FakeMinionLister(makeNodeList(test.nodes)))
+		if test.expectsErr {
+			if err == nil {
+				t.Errorf("%s: unexpected non-error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+		}
+		if test.expectedHost != machine {
+			t.Errorf("%s: expected %s, saw %s", test.name, test.expectedHost, machine)
+		}
+	}
+}
+
 func TestFindFitAllError(t *testing.T) {
 	nodes := []string{"3", "2", "1"}
 	predicates := map[string]FitPredicate{"true": truePredicate, "false": falsePredicate}
-	_, predicateMap, err := findNodesThatFit(api.Pod{}, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes))
+	_, predicateMap, err := findNodesThatFit(api.Pod{}, FakePodLister([]api.Pod{}), predicates, nil, makeNodeList(nodes))
 
 	if err != nil {
 		t.Errorf("unexpected error: %v")
@@ -272,7 +376,7 @@ func TestFindFitSomeError(t *testing.T) {
 	nodes := []string{"3", "2", "1"}
 	predicates := map[string]FitPredicate{"true": truePredicate, "match": matchesPredicate}
 	pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "1"}}
-	_, predicateMap, err := findNodesThatFit(pod, FakePodLister([]api.Pod{}), predicates, makeNodeList(nodes))
+	_, predicateMap, err := findNodesThatFit(pod, FakePodLister([]api.Pod{}), predicates, nil, makeNodeList(nodes))
 
 	if err != nil {
 		t.Errorf("unexpected error: %v")
@@ -42,6 +42,30 @@ type PodLister interface {
 	List(labels.Selector) ([]api.Pod, error)
 }
 
+// NodeStateLister is implemented by the PodListers which keep the state of
+// every minion aggregated from the pods on it, such as SchedulerCache.
+type NodeStateLister interface {
+	// NodeStates returns the states of the minions which run pods, by minion name.
+	NodeStates() (map[string]*NodeState, error)
+}
+
+// LabeledPodLister is implemented by the PodListers which index pods by
+// label, such as SchedulerCache.
+type LabeledPodLister interface {
+	// ListWithLabels returns the pods carrying all the labels of the set.
+	ListWithLabels(set labels.Set) ([]api.Pod, error)
+}
+
+// ListPodsWithLabels returns the pods the lister lists which carry all the
+// labels of the set. They come from the index of the lister when it keeps one,
+// and from matching every pod against the set otherwise.
+func ListPodsWithLabels(podLister PodLister, set labels.Set) ([]api.Pod, error) {
+	if lister, ok := podLister.(LabeledPodLister); ok {
+		return lister.ListWithLabels(set)
+	}
+	return podLister.List(labels.SelectorFromSet(set))
+}
+
 // FakePodLister implements PodLister on an []api.Pods for test purposes.
 type FakePodLister []api.Pod
 
@@ -91,3 +115,28 @@ func (f FakeServiceLister) GetPodServices(pod api.Pod) (services []api.Service,
 
 	return
 }
+
+// PersistentVolumeLister interface represents anything that can list persistent volumes for a scheduler.
+type PersistentVolumeLister interface {
+	List() (api.PersistentVolumeList, error)
+}
+
+// FakePersistentVolumeLister implements PersistentVolumeLister on []api.PersistentVolume for test purposes.
+type FakePersistentVolumeLister []api.PersistentVolume
+
+// List returns api.PersistentVolumeList, the list of all persistent volumes.
+func (f FakePersistentVolumeLister) List() (api.PersistentVolumeList, error) {
+	return api.PersistentVolumeList{Items: f}, nil
+}
+
+// FakeDiskZoneInfo implements DiskZoneInfo on the zone labels of disks by name for test purposes.
+type FakeDiskZoneInfo map[string]map[string]string
+
+// GetDiskZoneLabels returns the zone labels of the disk, or an error if the disk doesn't exist.
+func (f FakeDiskZoneInfo) GetDiskZoneLabels(pdName string) (map[string]string, error) {
+	labels, found := f[pdName]
+	if !found {
+		return nil, fmt.Errorf("disk %q not found", pdName)
+	}
+	return labels, nil
+}
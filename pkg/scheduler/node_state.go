@@ -0,0 +1,281 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sync"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// NodeState is the state of a minion aggregated from the pods on it, so that
+// the predicates and priorities do not walk the pods of every minion each
+// time a pod is scheduled. A NodeState is never modified once built: adding
+// or removing a pod builds a new one, so that a NodeState can be shared with
+// the scheduling algorithm while the cache it comes from keeps changing.
+type NodeState struct {
+	pods      []api.Pod
+	requested resourceRequest
+	// Number of the containers of the pods using each host port.
+	hostPorts map[int]int
+	// Number of the pods mounting each GCE persistent disk.
+	gcePDs map[string]int
+	// Indices in pods of the pods carrying each label, by "key=value".
+	podsByLabel map[string][]int
+	// The attempt to schedule a pod the minion is looked at by, if any.
+	attempt *schedulingAttempt
+}
+
+// schedulingAttempt holds the states of all the minions when an attempt to
+// schedule a pod starts, so that the predicates needing the whole cluster
+// build what they need from it once for all the minions they check.
+type schedulingAttempt struct {
+	nodes map[string]*NodeState
+
+	lock sync.Mutex
+	// Values built from nodes by the predicates, by key.
+	values map[interface{}]interface{}
+}
+
+func newSchedulingAttempt(nodes map[string]*NodeState) *schedulingAttempt {
+	return &schedulingAttempt{
+		nodes:  nodes,
+		values: map[interface{}]interface{}{},
+	}
+}
+
+// value returns the value stored under the key, which build returns the
+// first time the key is asked for.
+func (a *schedulingAttempt) value(key interface{}, build func(nodes map[string]*NodeState) interface{}) interface{} {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+	value, found := a.values[key]
+	if !found {
+		value = build(a.nodes)
+		a.values[key] = value
+	}
+	return value
+}
+
+// NewNodeState returns the state of a minion running the given pods.
+func NewNodeState(pods ...api.Pod) *NodeState {
+	state := &NodeState{
+		pods:        make([]api.Pod, len(pods)),
+		hostPorts:   map[int]int{},
+		gcePDs:      map[string]int{},
+		podsByLabel: map[string][]int{},
+	}
+	copy(state.pods, pods)
+	for ix := range pods {
+		state.update(&pods[ix], 1)
+		state.indexLabels(ix)
+	}
+	return state
+}
+
+// GetNodeStates returns the states of the minions which run the pods the
+// lister lists. They come straight from the lister when it keeps them, and are
+// aggregated from all its pods otherwise.
+func GetNodeStates(podLister PodLister) (map[string]*NodeState, error) {
+	if lister, ok := podLister.(NodeStateLister); ok {
+		return lister.NodeStates()
+	}
+	machineToPods, err := MapPodsToMachines(podLister)
+	if err != nil {
+		return nil, err
+	}
+	states := map[string]*NodeState{}
+	for machine, pods := range machineToPods {
+		states[machine] = NewNodeState(pods...)
+	}
+	return states, nil
+}
+
+// Pods returns the pods on the minion. The slice must not be modified.
+func (n *NodeState) Pods() []api.Pod {
+	if n == nil {
+		return nil
+	}
+	return n.pods
+}
+
+// PodsWithLabels returns the pods on the minion carrying all the labels of the
+// set, which is all of them for an empty set. Only the pods carrying the
+// rarest of the labels are looked at.
+func (n *NodeState) PodsWithLabels(set labels.Set) []api.Pod {
+	pods := []api.Pod{}
+	if n == nil {
+		return pods
+	}
+	if len(set) == 0 {
+		return append(pods, n.pods...)
+	}
+	var candidates []int
+	first := true
+	for key, value := range set {
+		indices := n.podsByLabel[labelKey(key, value)]
+		if first || len(indices) < len(candidates) {
+			candidates, first = indices, false
+		}
+	}
+	for _, ix := range candidates {
+		if hasLabels(n.pods[ix].Labels, set) {
+			pods = append(pods, n.pods[ix])
+		}
+	}
+	return pods
+}
+
+// RequestedMilliCPU returns the CPU the pods on the minion request.
+func (n *NodeState) RequestedMilliCPU() int64 {
+	if n == nil {
+		return 0
+	}
+	return n.requested.milliCPU
+}
+
+// RequestedMemory returns the memory the pods on the minion request.
+func (n *NodeState) RequestedMemory() int64 {
+	if n == nil {
+		return 0
+	}
+	return n.requested.memory
+}
+
+// UsesHostPort returns whether a container on the minion uses the host port.
+func (n *NodeState) UsesHostPort(port int) bool {
+	return n != nil && n.hostPorts[port] > 0
+}
+
+// MountsGCEPersistentDisk returns whether a pod on the minion mounts the disk.
+func (n *NodeState) MountsGCEPersistentDisk(pdName string) bool {
+	return n != nil && n.gcePDs[pdName] > 0
+}
+
+// inAttempt returns the state of the minion as looked at by the attempt. The
+// state itself is shared, since it is never modified.
+func (n *NodeState) inAttempt(attempt *schedulingAttempt) *NodeState {
+	if n == nil {
+		n = NewNodeState()
+	}
+	state := *n
+	state.attempt = attempt
+	return &state
+}
+
+// withPod returns the state of the minion once the pod runs on it.
+func (n *NodeState) withPod(pod *api.Pod) *NodeState {
+	state := n.clone()
+	state.pods = append(state.pods, *pod)
+	state.update(pod, 1)
+	state.indexLabels(len(state.pods) - 1)
+	return state
+}
+
+// withoutPod returns the state of the minion once the pod with the given
+// namespace and name is gone from it.
+func (n *NodeState) withoutPod(namespace, name string) *NodeState {
+	state := n.clone()
+	// The indices of the pods after the removed one shift, so the label
+	// index is built again.
+	state.pods = state.pods[:0]
+	state.podsByLabel = map[string][]int{}
+	for ix := range n.pods {
+		pod := &n.pods[ix]
+		if pod.Namespace == namespace && pod.Name == name {
+			state.update(pod, -1)
+			continue
+		}
+		state.pods = append(state.pods, *pod)
+		state.indexLabels(len(state.pods) - 1)
+	}
+	return state
+}
+
+func (n *NodeState) clone() *NodeState {
+	state := &NodeState{
+		pods:        make([]api.Pod, len(n.pods), len(n.pods)+1),
+		requested:   n.requested,
+		hostPorts:   make(map[int]int, len(n.hostPorts)),
+		gcePDs:      make(map[string]int, len(n.gcePDs)),
+		podsByLabel: make(map[string][]int, len(n.podsByLabel)),
+	}
+	copy(state.pods, n.pods)
+	for port, count := range n.hostPorts {
+		state.hostPorts[port] = count
+	}
+	for pdName, count := range n.gcePDs {
+		state.gcePDs[pdName] = count
+	}
+	for label, indices := range n.podsByLabel {
+		// Leave room for the pod withPod adds, without sharing the array.
+		state.podsByLabel[label] = append(make([]int, 0, len(indices)+1), indices...)
+	}
+	return state
+}
+
+// indexLabels adds the pod at the index in pods to the label index.
+func (n *NodeState) indexLabels(ix int) {
+	for key, value := range n.pods[ix].Labels {
+		label := labelKey(key, value)
+		n.podsByLabel[label] = append(n.podsByLabel[label], ix)
+	}
+}
+
+func labelKey(key, value string) string {
+	return key + "=" + value
+}
+
+// hasLabels returns true if podLabels holds all the labels of the set.
+func hasLabels(podLabels map[string]string, set labels.Set) bool {
+	for key, value := range set {
+		if podValue, found := podLabels[key]; !found || podValue != value {
+			return false
+		}
+	}
+	return true
+}
+
+// update adds the resources, host ports and disks the pod uses to the
+// aggregated state when sign is 1, and removes them when it is -1.
+func (n *NodeState) update(pod *api.Pod, sign int) {
+	request := getResourceRequest(pod)
+	n.requested.milliCPU += int64(sign) * request.milliCPU
+	n.requested.memory += int64(sign) * request.memory
+	for _, container := range pod.Spec.Containers {
+		for _, port := range container.Ports {
+			if port.HostPort == 0 {
+				continue
+			}
+			n.hostPorts[port.HostPort] += sign
+			if n.hostPorts[port.HostPort] <= 0 {
+				delete(n.hostPorts, port.HostPort)
+			}
+		}
+	}
+	for _, volume := range pod.Spec.Volumes {
+		if volume.GCEPersistentDisk == nil {
+			continue
+		}
+		pdName := volume.GCEPersistentDisk.PDName
+		n.gcePDs[pdName] += sign
+		if n.gcePDs[pdName] <= 0 {
+			delete(n.gcePDs, pdName)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package scheduler
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
@@ -49,20 +50,11 @@ func (nodes ClientNodeInfo) GetNodeInfo(nodeID string) (*api.Node, error) {
 	return nodes.Nodes().Get(nodeID)
 }
 
-func isVolumeConflict(volume api.Volume, pod *api.Pod) bool {
+func isVolumeConflict(volume api.Volume, state *NodeState) bool {
 	if volume.GCEPersistentDisk == nil {
 		return false
 	}
-	pdName := volume.GCEPersistentDisk.PDName
-
-	manifest := &(pod.Spec)
-	for ix := range manifest.Volumes {
-		if manifest.Volumes[ix].GCEPersistentDisk != nil &&
-			manifest.Volumes[ix].GCEPersistentDisk.PDName == pdName {
-			return true
-		}
-	}
-	return false
+	return state.MountsGCEPersistentDisk(volume.GCEPersistentDisk.PDName)
 }
 
 // NoDiskConflict evaluates if a pod can fit due to the volumes it requests, and those that
@@ -70,11 +62,87 @@ func isVolumeConflict(volume api.Volume, pod *api.Pod) bool {
 // are exclusive so if there is already a volume mounted on that node, another pod can't schedule
 // there. This is GCE specific for now.
 // TODO: migrate this into some per-volume specific code?
-func NoDiskConflict(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func NoDiskConflict(pod api.Pod, state *NodeState, node string) (bool, error) {
 	manifest := &(pod.Spec)
 	for ix := range manifest.Volumes {
-		for podIx := range existingPods {
-			if isVolumeConflict(manifest.Volumes[ix], &existingPods[podIx]) {
+		if isVolumeConflict(manifest.Volumes[ix], state) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// DiskZoneInfo looks up the zone of GCE persistent disks in the cloud.
+type DiskZoneInfo interface {
+	// GetDiskZoneLabels returns the zone and region labels of the disk.
+	GetDiskZoneLabels(pdName string) (map[string]string, error)
+}
+
+type VolumeZoneChecker struct {
+	volumeLister PersistentVolumeLister
+	diskZones    DiskZoneInfo
+	info         NodeInfo
+
+	// lock guards diskLabels, the zone labels of the disks looked up in the
+	// cloud so far. Disks don't move, so they are looked up once.
+	lock       sync.Mutex
+	diskLabels map[string]map[string]string
+}
+
+// NewVolumeZonePredicate returns a predicate which keeps the pods using zonal GCE
+// persistent disks on the minions in the zones of the disks. The zones of the disks
+// which aren't labeled through their persistent volumes are looked up in diskZones,
+// which may be nil.
+func NewVolumeZonePredicate(volumeLister PersistentVolumeLister, diskZones DiskZoneInfo, info NodeInfo) FitPredicate {
+	checker := &VolumeZoneChecker{
+		volumeLister: volumeLister,
+		diskZones:    diskZones,
+		info:         info,
+		diskLabels:   map[string]map[string]string{},
+	}
+	return checker.PodFitsVolumeZones
+}
+
+// PodFitsVolumeZones checks that the minion is in the zone of every GCE persistent disk
+// the pod uses, either directly or through a claim bound to a persistent volume. The zone
+// and region of a disk are the labels of its persistent volume, or else those looked up
+// in the cloud. Disks without a zone, and minions which aren't labeled with a zone, fit.
+func (c *VolumeZoneChecker) PodFitsVolumeZones(pod api.Pod, state *NodeState, node string) (bool, error) {
+	if len(pod.Spec.Volumes) == 0 {
+		return true, nil
+	}
+	minion, err := c.info.GetNodeInfo(node)
+	if err != nil {
+		return false, err
+	}
+	if minion.Labels[api.LabelZoneFailureDomain] == "" && minion.Labels[api.LabelZoneRegion] == "" {
+		return true, nil
+	}
+	volumes, err := c.volumeLister.List()
+	if err != nil {
+		return false, err
+	}
+	disks := map[string]map[string]string{}
+	for _, podVolume := range pod.Spec.Volumes {
+		if podVolume.GCEPersistentDisk != nil {
+			disks[podVolume.GCEPersistentDisk.PDName] = nil
+		}
+	}
+	for i := range volumes.Items {
+		volume := &volumes.Items[i]
+		if volume.Spec.GCEPersistentDisk == nil || !podUsesPersistentVolume(&pod, volume) {
+			continue
+		}
+		disks[volume.Spec.GCEPersistentDisk.PDName] = volume.Labels
+	}
+	for pdName, labels := range disks {
+		if labels[api.LabelZoneFailureDomain] == "" && labels[api.LabelZoneRegion] == "" {
+			if labels, err = c.getDiskZoneLabels(pdName); err != nil {
+				return false, err
+			}
+		}
+		for _, key := range []string{api.LabelZoneFailureDomain, api.LabelZoneRegion} {
+			if zone, ok := labels[key]; ok && zone != minion.Labels[key] {
 				return false, nil
 			}
 		}
@@ -82,6 +150,44 @@ func NoDiskConflict(pod api.Pod, existingPods []api.Pod, node string) (bool, err
 	return true, nil
 }
 
+// getDiskZoneLabels returns the zone labels of the disk from the cloud, or none
+// if the checker can't look disks up.
+func (c *VolumeZoneChecker) getDiskZoneLabels(pdName string) (map[string]string, error) {
+	if c.diskZones == nil {
+		return nil, nil
+	}
+	c.lock.Lock()
+	labels, found := c.diskLabels[pdName]
+	c.lock.Unlock()
+	if found {
+		return labels, nil
+	}
+	labels, err := c.diskZones.GetDiskZoneLabels(pdName)
+	if err != nil {
+		return nil, err
+	}
+	c.lock.Lock()
+	c.diskLabels[pdName] = labels
+	c.lock.Unlock()
+	return labels, nil
+}
+
+// podUsesPersistentVolume returns true if the pod mounts the GCE persistent disk
+// of the volume, or claims the volume.
+func podUsesPersistentVolume(pod *api.Pod, volume *api.PersistentVolume) bool {
+	for _, podVolume := range pod.Spec.Volumes {
+		if podVolume.GCEPersistentDisk != nil && podVolume.GCEPersistentDisk.PDName == volume.Spec.GCEPersistentDisk.PDName {
+			return true
+		}
+		claimRef := volume.Spec.ClaimRef
+		if podVolume.PersistentVolumeClaim != nil && claimRef != nil &&
+			claimRef.Namespace == pod.Namespace && claimRef.Name == podVolume.PersistentVolumeClaim.ClaimName {
+			return true
+		}
+	}
+	return false
+}
+
 type ResourceFit struct {
 	info NodeInfo
 }
@@ -91,12 +197,14 @@ type resourceRequest struct {
 	memory   int64
 }
 
+// getResourceRequest returns the resources requested by the containers of
+// the pod, which default to their limits.
 func getResourceRequest(pod *api.Pod) resourceRequest {
 	result := resourceRequest{}
 	for ix := range pod.Spec.Containers {
-		limits := pod.Spec.Containers[ix].Resources.Limits
-		result.memory += limits.Memory().Value()
-		result.milliCPU += limits.Cpu().MilliValue()
+		requests := pod.Spec.Containers[ix].Resources.EffectiveRequests()
+		result.memory += requests.Memory().Value()
+		result.milliCPU += requests.Cpu().MilliValue()
 	}
 	return result
 }
@@ -124,7 +232,7 @@ func CheckPodsExceedingCapacity(pods []api.Pod, capacity api.ResourceList) (fitt
 }
 
 // PodFitsResources calculates fit based on requested, rather than used resources
-func (r *ResourceFit) PodFitsResources(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func (r *ResourceFit) PodFitsResources(pod api.Pod, state *NodeState, node string) (bool, error) {
 	podRequest := getResourceRequest(&pod)
 	if podRequest.milliCPU == 0 && podRequest.memory == 0 {
 		// no resources requested always fits.
@@ -134,14 +242,11 @@ func (r *ResourceFit) PodFitsResources(pod api.Pod, existingPods []api.Pod, node
 	if err != nil {
 		return false, err
 	}
-	pods := []api.Pod{}
-	copy(pods, existingPods)
-	pods = append(existingPods, pod)
-	_, exceeding := CheckPodsExceedingCapacity(pods, info.Status.Capacity)
-	if len(exceeding) > 0 {
-		return false, nil
-	}
-	return true, nil
+	totalMilliCPU := info.Status.Capacity.Cpu().MilliValue()
+	totalMemory := info.Status.Capacity.Memory().Value()
+	fitsCPU := totalMilliCPU == 0 || (totalMilliCPU-state.RequestedMilliCPU()) >= podRequest.milliCPU
+	fitsMemory := totalMemory == 0 || (totalMemory-state.RequestedMemory()) >= podRequest.memory
+	return fitsCPU && fitsMemory, nil
 }
 
 func NewResourceFitPredicate(info NodeInfo) FitPredicate {
@@ -170,7 +275,7 @@ type NodeSelector struct {
 	info NodeInfo
 }
 
-func (n *NodeSelector) PodSelectorMatches(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func (n *NodeSelector) PodSelectorMatches(pod api.Pod, state *NodeState, node string) (bool, error) {
 	minion, err := n.info.GetNodeInfo(node)
 	if err != nil {
 		return false, err
@@ -178,7 +283,109 @@ func (n *NodeSelector) PodSelectorMatches(pod api.Pod, existingPods []api.Pod, n
 	return PodMatchesNodeLabels(&pod, minion), nil
 }
 
-func PodFitsHost(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func NewNodeAffinityPredicate(info NodeInfo) FitPredicate {
+	matcher := &NodeAffinityMatcher{
+		info: info,
+	}
+	return matcher.PodMatchesNodeAffinity
+}
+
+// PodMatchesRequiredNodeAffinity checks whether the node matches at least one
+// of the terms the pod requires through its node affinity. A pod without
+// required node affinity matches all the nodes.
+func PodMatchesRequiredNodeAffinity(pod *api.Pod, node *api.Node) bool {
+	if pod.Spec.Affinity == nil || pod.Spec.Affinity.NodeAffinity == nil || pod.Spec.Affinity.NodeAffinity.Required == nil {
+		return true
+	}
+	for _, term := range pod.Spec.Affinity.NodeAffinity.Required.NodeSelectorTerms {
+		if nodeMatchesSelectorTerm(node, &term) {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeMatchesSelectorTerm checks whether the labels of the node satisfy all
+// the requirements of the term. A term which cannot be converted to a
+// selector matches no node.
+func nodeMatchesSelectorTerm(node *api.Node, term *api.NodeSelectorTerm) bool {
+	selector, err := api.NodeSelectorRequirementsAsSelector(term.MatchExpressions)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(node.Labels))
+}
+
+type NodeAffinityMatcher struct {
+	info NodeInfo
+}
+
+func (n *NodeAffinityMatcher) PodMatchesNodeAffinity(pod api.Pod, state *NodeState, node string) (bool, error) {
+	minion, err := n.info.GetNodeInfo(node)
+	if err != nil {
+		return false, err
+	}
+	return PodMatchesRequiredNodeAffinity(&pod, minion), nil
+}
+
+func NewTaintTolerationPredicate(info NodeInfo) FitPredicate {
+	taintToleration := &TaintToleration{
+		info: info,
+	}
+	return taintToleration.PodToleratesNodeTaints
+}
+
+type TaintToleration struct {
+	info NodeInfo
+}
+
+// PodToleratesNodeTaints checks that the pod tolerates every NoSchedule taint of the node.
+func (t *TaintToleration) PodToleratesNodeTaints(pod api.Pod, state *NodeState, node string) (bool, error) {
+	minion, err := t.info.GetNodeInfo(node)
+	if err != nil {
+		return false, err
+	}
+	for i := range minion.Spec.Taints {
+		taint := &minion.Spec.Taints[i]
+		if taint.Effect != api.TaintEffectNoSchedule {
+			continue
+		}
+		if !TolerationsTolerateTaint(pod.Spec.Tolerations, taint) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// TolerationsTolerateTaint returns true if one of the tolerations tolerates the taint.
+func TolerationsTolerateTaint(tolerations []api.Toleration, taint *api.Taint) bool {
+	for i := range tolerations {
+		if ToleratesTaint(&tolerations[i], taint) {
+			return true
+		}
+	}
+	return false
+}
+
+// ToleratesTaint returns true if the toleration matches the key, the value and the effect of the taint.
+// An empty effect matches all effects, and the Exists operator matches all values.
+func ToleratesTaint(toleration *api.Toleration, taint *api.Taint) bool {
+	if toleration.Key != taint.Key {
+		return false
+	}
+	if len(toleration.Effect) > 0 && toleration.Effect != taint.Effect {
+		return false
+	}
+	switch toleration.Operator {
+	case api.TolerationOpExists:
+		return true
+	case "", api.TolerationOpEqual:
+		return toleration.Value == taint.Value
+	}
+	return false
+}
+
+func PodFitsHost(pod api.Pod, state *NodeState, node string) (bool, error) {
 	if len(pod.Spec.Host) == 0 {
 		return true, nil
 	}
@@ -212,7 +419,7 @@ func NewNodeLabelPredicate(info NodeInfo, labels []string, presence bool) FitPre
 // Alternately, eliminating minions that have a certain label, regardless of value, is also useful
 // A minion may have a label with "retiring" as key and the date as the value
 // and it may be desirable to avoid scheduling new pods on this minion
-func (n *NodeLabelChecker) CheckNodeLabelPresence(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func (n *NodeLabelChecker) CheckNodeLabelPresence(pod api.Pod, state *NodeState, node string) (bool, error) {
 	var exists bool
 	minion, err := n.info.GetNodeInfo(node)
 	if err != nil {
@@ -254,7 +461,7 @@ func NewServiceAffinityPredicate(podLister PodLister, serviceLister ServiceListe
 // - L is listed in the ServiceAffinity object that is passed into the function
 // - the pod does not have any NodeSelector for L
 // - some other pod from the same service is already scheduled onto a minion that has value V for label L
-func (s *ServiceAffinity) CheckServiceAffinity(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func (s *ServiceAffinity) CheckServiceAffinity(pod api.Pod, state *NodeState, node string) (bool, error) {
 	var affinitySelector labels.Selector
 
 	// check if the pod being scheduled has the affinity labels specified in its NodeSelector
@@ -323,14 +530,13 @@ func (s *ServiceAffinity) CheckServiceAffinity(pod api.Pod, existingPods []api.P
 	return affinitySelector.Matches(labels.Set(minion.Labels)), nil
 }
 
-func PodFitsPorts(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
-	existingPorts := getUsedPorts(existingPods...)
+func PodFitsPorts(pod api.Pod, state *NodeState, node string) (bool, error) {
 	wantPorts := getUsedPorts(pod)
 	for wport := range wantPorts {
 		if wport == 0 {
 			continue
 		}
-		if existingPorts[wport] {
+		if state.UsesHostPort(wport) {
 			return false, nil
 		}
 	}
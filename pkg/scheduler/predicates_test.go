@@ -71,6 +71,27 @@ func newResourcePod(usage ...resourceRequest) api.Pod {
 	}
 }
 
+func newRequestPod(request, limit resourceRequest) api.Pod {
+	return api.Pod{
+		Spec: api.PodSpec{
+			Containers: []api.Container{
+				{
+					Resources: api.ResourceRequirements{
+						Requests: api.ResourceList{
+							"cpu":    *resource.NewMilliQuantity(request.milliCPU, resource.DecimalSI),
+							"memory": *resource.NewQuantity(request.memory, resource.BinarySI),
+						},
+						Limits: api.ResourceList{
+							"cpu":    *resource.NewMilliQuantity(limit.milliCPU, resource.DecimalSI),
+							"memory": *resource.NewQuantity(limit.memory, resource.BinarySI),
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func TestPodFitsResources(t *testing.T) {
 	tests := []struct {
 		pod          api.Pod
@@ -118,12 +139,28 @@ func TestPodFitsResources(t *testing.T) {
 			fits: true,
 			test: "equal edge case",
 		},
+		{
+			pod: newResourcePod(resourceRequest{milliCPU: 1, memory: 1}),
+			existingPods: []api.Pod{
+				newRequestPod(resourceRequest{milliCPU: 5, memory: 5}, resourceRequest{milliCPU: 10, memory: 20}),
+			},
+			fits: true,
+			test: "existing pods are counted by their requests, not their limits",
+		},
+		{
+			pod: newRequestPod(resourceRequest{milliCPU: 6, memory: 1}, resourceRequest{milliCPU: 6, memory: 20}),
+			existingPods: []api.Pod{
+				newRequestPod(resourceRequest{milliCPU: 5, memory: 5}, resourceRequest{milliCPU: 10, memory: 20}),
+			},
+			fits: false,
+			test: "requests exceed capacity",
+		},
 	}
 	for _, test := range tests {
 		node := api.Node{Status: api.NodeStatus{Capacity: makeResources(10, 20).Capacity}}
 
 		fit := ResourceFit{FakeNodeInfo(node)}
-		fits, err := fit.PodFitsResources(test.pod, test.existingPods, "machine")
+		fits, err := fit.PodFitsResources(test.pod, NewNodeState(test.existingPods...), "machine")
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -169,7 +206,7 @@ func TestPodFitsHost(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		result, err := PodFitsHost(test.pod, []api.Pod{}, test.node)
+		result, err := PodFitsHost(test.pod, NewNodeState(), test.node)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -226,7 +263,7 @@ func TestPodFitsPorts(t *testing.T) {
 		},
 	}
 	for _, test := range tests {
-		fits, err := PodFitsPorts(test.pod, test.existingPods, "machine")
+		fits, err := PodFitsPorts(test.pod, NewNodeState(test.existingPods...), "machine")
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -308,7 +345,7 @@ func TestDiskConflicts(t *testing.T) {
 	}
 
 	for _, test := range tests {
-		ok, err := NoDiskConflict(test.pod, test.existingPods, "machine")
+		ok, err := NoDiskConflict(test.pod, NewNodeState(test.existingPods...), "machine")
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -321,6 +358,89 @@ func TestDiskConflicts(t *testing.T) {
 	}
 }
 
+func TestPodFitsVolumeZones(t *testing.T) {
+	zoneA := map[string]string{api.LabelZoneRegion: "region", api.LabelZoneFailureDomain: "zone-a"}
+	zoneB := map[string]string{api.LabelZoneRegion: "region", api.LabelZoneFailureDomain: "zone-b"}
+	volumes := []api.PersistentVolume{
+		{
+			ObjectMeta: api.ObjectMeta{Name: "zonal", Labels: zoneA},
+			Spec: api.PersistentVolumeSpec{
+				PersistentVolumeSource: api.PersistentVolumeSource{
+					GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{PDName: "zonal-disk"},
+				},
+				ClaimRef: &api.ObjectReference{Namespace: "ns", Name: "zonal-claim"},
+			},
+		},
+		{
+			ObjectMeta: api.ObjectMeta{Name: "unlabeled"},
+			Spec: api.PersistentVolumeSpec{
+				PersistentVolumeSource: api.PersistentVolumeSource{
+					GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{PDName: "unlabeled-disk"},
+				},
+			},
+		},
+	}
+	// The zones of the disks in the cloud.
+	diskZones := FakeDiskZoneInfo{
+		"zonal-disk":     zoneB,
+		"unlabeled-disk": {},
+		"unknown-disk":   {},
+		"inline-disk":    zoneA,
+	}
+	diskPod := func(pdName string) api.Pod {
+		return api.Pod{
+			ObjectMeta: api.ObjectMeta{Namespace: "ns"},
+			Spec: api.PodSpec{Volumes: []api.Volume{{
+				VolumeSource: api.VolumeSource{GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{PDName: pdName}},
+			}}},
+		}
+	}
+	claimPod := func(namespace, claimName string) api.Pod {
+		return api.Pod{
+			ObjectMeta: api.ObjectMeta{Namespace: namespace},
+			Spec: api.PodSpec{Volumes: []api.Volume{{
+				VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: claimName}},
+			}}},
+		}
+	}
+	tests := []struct {
+		pod        api.Pod
+		nodeLabels map[string]string
+		fits       bool
+		test       string
+	}{
+		{api.Pod{}, zoneB, true, "no volumes"},
+		{diskPod("zonal-disk"), zoneA, true, "disk in the zone of the minion"},
+		{diskPod("zonal-disk"), zoneB, false, "disk in another zone"},
+		{diskPod("zonal-disk"), nil, true, "minion without a zone"},
+		{diskPod("unlabeled-disk"), zoneB, true, "disk without a zone"},
+		{diskPod("unknown-disk"), zoneB, true, "disk without a persistent volume or a zone"},
+		{diskPod("inline-disk"), zoneA, true, "disk without a persistent volume in the zone of the minion"},
+		{diskPod("inline-disk"), zoneB, false, "disk without a persistent volume in another zone"},
+		{claimPod("ns", "zonal-claim"), zoneA, true, "claimed volume in the zone of the minion"},
+		{claimPod("ns", "zonal-claim"), zoneB, false, "claimed volume in another zone"},
+		{claimPod("other", "zonal-claim"), zoneB, true, "claim in another namespace"},
+	}
+
+	for _, test := range tests {
+		node := api.Node{ObjectMeta: api.ObjectMeta{Name: "machine", Labels: test.nodeLabels}}
+		predicate := NewVolumeZonePredicate(FakePersistentVolumeLister(volumes), diskZones, StaticNodeInfo{&api.NodeList{Items: []api.Node{node}}})
+		fits, err := predicate(test.pod, NewNodeState(), "machine")
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected %v, got %v", test.test, test.fits, fits)
+		}
+	}
+
+	node := api.Node{ObjectMeta: api.ObjectMeta{Name: "machine", Labels: zoneA}}
+	predicate := NewVolumeZonePredicate(FakePersistentVolumeLister(volumes), diskZones, StaticNodeInfo{&api.NodeList{Items: []api.Node{node}}})
+	if _, err := predicate(diskPod("missing-disk"), NewNodeState(), "machine"); err == nil {
+		t.Errorf("expected an error for a disk missing from the cloud")
+	}
+}
+
 func TestPodFitsSelector(t *testing.T) {
 	tests := []struct {
 		pod    api.Pod
@@ -393,7 +513,164 @@ func TestPodFitsSelector(t *testing.T) {
 		node := api.Node{ObjectMeta: api.ObjectMeta{Labels: test.labels}}
 
 		fit := NodeSelector{FakeNodeInfo(node)}
-		fits, err := fit.PodSelectorMatches(test.pod, []api.Pod{}, "machine")
+		fits, err := fit.PodSelectorMatches(test.pod, NewNodeState(), "machine")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
+
+func TestPodMatchesNodeAffinity(t *testing.T) {
+	highMemory := api.NodeSelectorTerm{
+		MatchExpressions: []api.NodeSelectorRequirement{
+			{Key: "memory", Operator: api.NodeSelectorOpIn, Values: []string{"high", "huge"}},
+			{Key: "gpu", Operator: api.NodeSelectorOpNotIn, Values: []string{"true"}},
+		},
+	}
+	ssd := api.NodeSelectorTerm{
+		MatchExpressions: []api.NodeSelectorRequirement{{Key: "ssd", Operator: api.NodeSelectorOpExists}},
+	}
+	tests := []struct {
+		affinity *api.Affinity
+		labels   map[string]string
+		fits     bool
+		test     string
+	}{
+		{
+			labels: map[string]string{"memory": "low"},
+			fits:   true,
+			test:   "no affinity",
+		},
+		{
+			affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+				Preferred: []api.PreferredSchedulingTerm{{Weight: 1, Preference: highMemory}},
+			}},
+			labels: map[string]string{"memory": "low"},
+			fits:   true,
+			test:   "only preferred terms",
+		},
+		{
+			affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+				Required: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{highMemory}},
+			}},
+			labels: map[string]string{"memory": "huge"},
+			fits:   true,
+			test:   "all requirements of the term matched",
+		},
+		{
+			affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+				Required: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{highMemory}},
+			}},
+			labels: map[string]string{"memory": "high", "gpu": "true"},
+			fits:   false,
+			test:   "notin requirement not matched",
+		},
+		{
+			affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+				Required: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{highMemory}},
+			}},
+			fits: false,
+			test: "node without labels",
+		},
+		{
+			affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+				Required: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{highMemory, ssd}},
+			}},
+			labels: map[string]string{"ssd": ""},
+			fits:   true,
+			test:   "second term matched",
+		},
+		{
+			affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{
+				Required: &api.NodeSelector{NodeSelectorTerms: []api.NodeSelectorTerm{{
+					MatchExpressions: []api.NodeSelectorRequirement{{Key: "ssd", Operator: "Gt", Values: []string{"1"}}},
+				}}},
+			}},
+			labels: map[string]string{"ssd": "2"},
+			fits:   false,
+			test:   "invalid term",
+		},
+	}
+
+	for _, test := range tests {
+		node := api.Node{ObjectMeta: api.ObjectMeta{Labels: test.labels}}
+		pod := api.Pod{Spec: api.PodSpec{Affinity: test.affinity}}
+
+		fit := NodeAffinityMatcher{FakeNodeInfo(node)}
+		fits, err := fit.PodMatchesNodeAffinity(pod, NewNodeState(), "machine")
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		if fits != test.fits {
+			t.Errorf("%s: expected: %v got %v", test.test, test.fits, fits)
+		}
+	}
+}
+
+func TestPodToleratesNodeTaints(t *testing.T) {
+	dedicated := api.Taint{Key: "dedicated", Value: "team-a", Effect: api.TaintEffectNoSchedule}
+	tests := []struct {
+		tolerations []api.Toleration
+		taints      []api.Taint
+		fits        bool
+		test        string
+	}{
+		{
+			fits: true,
+			test: "no taints",
+		},
+		{
+			taints: []api.Taint{dedicated},
+			fits:   false,
+			test:   "no tolerations",
+		},
+		{
+			tolerations: []api.Toleration{{Key: "dedicated", Value: "team-a"}},
+			taints:      []api.Taint{dedicated},
+			fits:        true,
+			test:        "equal toleration",
+		},
+		{
+			tolerations: []api.Toleration{{Key: "dedicated", Operator: api.TolerationOpEqual, Value: "team-b"}},
+			taints:      []api.Taint{dedicated},
+			fits:        false,
+			test:        "toleration with another value",
+		},
+		{
+			tolerations: []api.Toleration{{Key: "dedicated", Operator: api.TolerationOpExists, Effect: api.TaintEffectNoSchedule}},
+			taints:      []api.Taint{dedicated},
+			fits:        true,
+			test:        "exists toleration",
+		},
+		{
+			tolerations: []api.Toleration{{Key: "maintenance", Operator: api.TolerationOpExists}},
+			taints:      []api.Taint{dedicated},
+			fits:        false,
+			test:        "toleration of another key",
+		},
+		{
+			tolerations: []api.Toleration{{Key: "dedicated", Value: "team-a"}},
+			taints:      []api.Taint{dedicated, {Key: "maintenance", Effect: api.TaintEffectNoSchedule}},
+			fits:        false,
+			test:        "one of two taints tolerated",
+		},
+		{
+			tolerations: []api.Toleration{{Key: "dedicated", Value: "team-a"}, {Key: "maintenance", Operator: api.TolerationOpExists}},
+			taints:      []api.Taint{dedicated, {Key: "maintenance", Effect: api.TaintEffectNoSchedule}},
+			fits:        true,
+			test:        "all taints tolerated",
+		},
+	}
+
+	for _, test := range tests {
+		node := api.Node{Spec: api.NodeSpec{Taints: test.taints}}
+		pod := api.Pod{Spec: api.PodSpec{Tolerations: test.tolerations}}
+
+		fit := TaintToleration{FakeNodeInfo(node)}
+		fits, err := fit.PodToleratesNodeTaints(pod, NewNodeState(), "machine")
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -453,7 +730,7 @@ func TestNodeLabelPresence(t *testing.T) {
 	for _, test := range tests {
 		node := api.Node{ObjectMeta: api.ObjectMeta{Labels: label}}
 		labelChecker := NodeLabelChecker{FakeNodeInfo(node), test.labels, test.presence}
-		fits, err := labelChecker.CheckNodeLabelPresence(test.pod, test.existingPods, "machine")
+		fits, err := labelChecker.CheckNodeLabelPresence(test.pod, NewNodeState(test.existingPods...), "machine")
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
@@ -592,7 +869,7 @@ func TestServiceAffinity(t *testing.T) {
 	for _, test := range tests {
 		nodes := []api.Node{node1, node2, node3, node4, node5}
 		serviceAffinity := ServiceAffinity{FakePodLister(test.pods), FakeServiceLister(test.services), FakeNodeListInfo(nodes), test.labels}
-		fits, err := serviceAffinity.CheckServiceAffinity(test.pod, []api.Pod{}, test.node)
+		fits, err := serviceAffinity.CheckServiceAffinity(test.pod, NewNodeState(), test.node)
 		if err != nil {
 			t.Errorf("unexpected error: %v", err)
 		}
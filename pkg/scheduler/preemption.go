@@ -0,0 +1,146 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// preemptionCandidate is a node and the pods to evict from it to make room
+// for the pod being scheduled.
+type preemptionCandidate struct {
+	node    string
+	victims []api.Pod
+}
+
+// betterThan prefers the candidate evicting no pod, then the one whose
+// highest priority victim has the lowest priority, then the one evicting
+// the fewest pods, then the one whose victims have the lowest total priority.
+func (c *preemptionCandidate) betterThan(other *preemptionCandidate) bool {
+	if len(c.victims) == 0 || len(other.victims) == 0 {
+		return len(c.victims) < len(other.victims)
+	}
+	if a, b := highestPriority(c.victims), highestPriority(other.victims); a != b {
+		return a < b
+	}
+	if len(c.victims) != len(other.victims) {
+		return len(c.victims) < len(other.victims)
+	}
+	return prioritySum(c.victims) < prioritySum(other.victims)
+}
+
+func highestPriority(pods []api.Pod) int {
+	highest := pods[0].Spec.Priority
+	for _, pod := range pods[1:] {
+		if pod.Spec.Priority > highest {
+			highest = pod.Spec.Priority
+		}
+	}
+	return highest
+}
+
+func prioritySum(pods []api.Pod) int {
+	sum := 0
+	for _, pod := range pods {
+		sum += pod.Spec.Priority
+	}
+	return sum
+}
+
+// byPriority sorts pods from the highest priority to the lowest.
+type byPriority []api.Pod
+
+func (p byPriority) Len() int           { return len(p) }
+func (p byPriority) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+func (p byPriority) Less(i, j int) bool { return p[i].Spec.Priority > p[j].Spec.Priority }
+
+// Preempt looks for the node where evicting pods of lower priority than the
+// pod makes room for it. Pods already terminating are counted as gone, so
+// that the pods evicted by an earlier attempt are not replaced by new victims.
+// The predicates see the evictions through the state of the node they check,
+// which replaces the state of that node in the rest of the cluster.
+func (g *genericScheduler) Preempt(pod api.Pod, minionLister MinionLister) (string, []api.Pod, error) {
+	minions, err := minionLister.List()
+	if err != nil {
+		return "", nil, err
+	}
+	nodeStates, err := GetNodeStates(g.pods)
+	if err != nil {
+		return "", nil, err
+	}
+
+	attempt := newSchedulingAttempt(nodeStates)
+	var best *preemptionCandidate
+	for _, minion := range minions.Items {
+		candidate := g.selectVictims(pod, minion.Name, nodeStates[minion.Name].Pods(), attempt)
+		if candidate != nil && (best == nil || candidate.betterThan(best)) {
+			best = candidate
+		}
+	}
+	if best == nil {
+		return "", nil, fmt.Errorf("no node where evicting pods of lower priority makes room for pod %v", pod.Name)
+	}
+	return best.node, best.victims, nil
+}
+
+// selectVictims returns the pods to evict from the node to make room for the
+// pod, sparing the pods of highest priority first, or nil when evicting all
+// the pods of lower priority is not enough.
+func (g *genericScheduler) selectVictims(pod api.Pod, node string, pods []api.Pod, attempt *schedulingAttempt) *preemptionCandidate {
+	remaining := []api.Pod{}
+	lower := []api.Pod{}
+	for _, existing := range pods {
+		switch {
+		case existing.DeletionTimestamp != nil:
+		case existing.Spec.Priority < pod.Spec.Priority:
+			lower = append(lower, existing)
+		default:
+			remaining = append(remaining, existing)
+		}
+	}
+	if !g.podFits(pod, remaining, node, attempt) {
+		return nil
+	}
+
+	sort.Stable(byPriority(lower))
+	candidate := &preemptionCandidate{node: node}
+	for _, existing := range lower {
+		spared := append(remaining, existing)
+		if g.podFits(pod, spared, node, attempt) {
+			remaining = spared
+		} else {
+			candidate.victims = append(candidate.victims, existing)
+		}
+	}
+	return candidate
+}
+
+// podFits checks whether all the predicates accept the pod on the node when
+// it runs the given pods.
+func (g *genericScheduler) podFits(pod api.Pod, existingPods []api.Pod, node string, attempt *schedulingAttempt) bool {
+	state := NewNodeState(existingPods...).inAttempt(attempt)
+	for _, predicate := range g.predicates {
+		fit, err := predicate(pod, state, node)
+		if err != nil || !fit {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// podCountPredicate fits a pod on a node running fewer pods than its capacity.
+func podCountPredicate(capacity map[string]int) FitPredicate {
+	return func(pod api.Pod, state *NodeState, node string) (bool, error) {
+		return len(state.Pods()) < capacity[node], nil
+	}
+}
+
+func priorityPod(name, host string, priority int) api.Pod {
+	return api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: name},
+		Spec:       api.PodSpec{Priority: priority},
+		Status:     api.PodStatus{Host: host},
+	}
+}
+
+func TestPreempt(t *testing.T) {
+	now := util.Now()
+	terminating := priorityPod("terminating", "machine1", 0)
+	terminating.DeletionTimestamp = &now
+
+	tests := []struct {
+		pod             api.Pod
+		pods            []api.Pod
+		capacity        map[string]int
+		expectedNode    string
+		expectedVictims []string
+		expectErr       bool
+		test            string
+	}{
+		{
+			pod:       priorityPod("p", "", 10),
+			pods:      []api.Pod{priorityPod("a", "machine1", 10), priorityPod("b", "machine2", 20)},
+			capacity:  map[string]int{"machine1": 1, "machine2": 1},
+			expectErr: true,
+			test:      "no pod of lower priority",
+		},
+		{
+			pod:             priorityPod("p", "", 10),
+			pods:            []api.Pod{priorityPod("a", "machine1", 1), priorityPod("b", "machine1", 2), priorityPod("c", "machine2", 5)},
+			capacity:        map[string]int{"machine1": 2, "machine2": 1},
+			expectedNode:    "machine1",
+			expectedVictims: []string{"a"},
+			test:            "lowest priority victims",
+		},
+		{
+			pod:             priorityPod("p", "", 10),
+			pods:            []api.Pod{priorityPod("a", "machine1", 1), priorityPod("b", "machine1", 1), priorityPod("c", "machine2", 1), priorityPod("d", "machine2", 1)},
+			capacity:        map[string]int{"machine1": 1, "machine2": 2},
+			expectedNode:    "machine2",
+			expectedVictims: []string{"d"},
+			test:            "fewest victims",
+		},
+		{
+			pod:             priorityPod("p", "", 10),
+			pods:            []api.Pod{priorityPod("a", "machine1", 3), priorityPod("b", "machine2", 1), priorityPod("c", "machine2", 2)},
+			capacity:        map[string]int{"machine1": 1, "machine2": 1},
+			expectedNode:    "machine2",
+			expectedVictims: []string{"c", "b"},
+			test:            "more victims of lower priority",
+		},
+		{
+			pod:             priorityPod("p", "", 10),
+			pods:            []api.Pod{priorityPod("a", "machine1", 1), priorityPod("b", "machine1", 2), priorityPod("c", "machine1", 20)},
+			capacity:        map[string]int{"machine1": 2},
+			expectedNode:    "machine1",
+			expectedVictims: []string{"b", "a"},
+			test:            "pod of higher priority kept",
+		},
+		{
+			pod:          priorityPod("p", "", 10),
+			pods:         []api.Pod{terminating, priorityPod("b", "machine2", 1)},
+			capacity:     map[string]int{"machine1": 1, "machine2": 1},
+			expectedNode: "machine1",
+			test:         "terminating pod already makes room",
+		},
+	}
+
+	for _, test := range tests {
+		scheduler := &genericScheduler{
+			predicates: map[string]FitPredicate{"count": podCountPredicate(test.capacity)},
+			pods:       FakePodLister(test.pods),
+		}
+		nodes := api.NodeList{}
+		for _, name := range []string{"machine1", "machine2"} {
+			nodes.Items = append(nodes.Items, api.Node{ObjectMeta: api.ObjectMeta{Name: name}})
+		}
+		node, victims, err := scheduler.Preempt(test.pod, FakeMinionLister(nodes))
+		if test.expectErr {
+			if err == nil {
+				t.Errorf("%s: unexpected non-error", test.test)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.test, err)
+			continue
+		}
+		if node != test.expectedNode {
+			t.Errorf("%s: expected node %s, got %s", test.test, test.expectedNode, node)
+		}
+		names := []string{}
+		for _, victim := range victims {
+			names = append(names, victim.Name)
+		}
+		if len(test.expectedVictims) == 0 {
+			test.expectedVictims = []string{}
+		}
+		if !reflect.DeepEqual(names, test.expectedVictims) {
+			t.Errorf("%s: expected victims %v, got %v", test.test, test.expectedVictims, names)
+		}
+	}
+}
+
+func TestPreemptPodAffinity(t *testing.T) {
+	batch := map[string]string{"app": "batch"}
+	antiAffinity := &api.Affinity{
+		PodAntiAffinity: &api.PodAntiAffinity{Required: []api.PodAffinityTerm{{LabelSelector: batch, TopologyKey: "hostname"}}},
+	}
+	web := affinityTestPod("web-0", "machine1", nil, antiAffinity)
+	db := affinityTestPod("db-0", "machine2", nil, antiAffinity)
+	web.Spec.Priority, db.Spec.Priority = 1, 20
+	pod := affinityTestPod("p", "", batch, nil)
+	pod.Spec.Priority = 10
+
+	podLister := &countingPodLister{FakePodLister: FakePodLister{web, db}}
+	scheduler := &genericScheduler{
+		predicates: map[string]FitPredicate{"affinity": NewPodAffinityPredicate(podLister, StaticNodeInfo{affinityTestNodes()})},
+		pods:       podLister,
+	}
+	nodes := api.NodeList{}
+	for _, name := range []string{"machine1", "machine2"} {
+		nodes.Items = append(nodes.Items, api.Node{ObjectMeta: api.ObjectMeta{Name: name}})
+	}
+	node, victims, err := scheduler.Preempt(pod, FakeMinionLister(nodes))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if node != "machine1" || len(victims) != 1 || victims[0].Name != "web-0" {
+		t.Errorf("expected to evict web-0 from machine1, got %v from %s", victims, node)
+	}
+	// The pods are listed once, for the states of the nodes.
+	if podLister.lists != 1 {
+		t.Errorf("expected the pods to be listed once, got %d lists", podLister.lists)
+	}
+}
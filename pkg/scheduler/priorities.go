@@ -36,22 +36,15 @@ func calculateScore(requested, capacity int64, node string) int {
 }
 
 // Calculate the occupancy on a node.  'node' has information about the resources on the node.
-// 'pods' is a list of pods currently scheduled on the node.
-func calculateOccupancy(pod api.Pod, node api.Node, pods []api.Pod) HostPriority {
-	totalMilliCPU := int64(0)
-	totalMemory := int64(0)
-	for _, existingPod := range pods {
-		for _, container := range existingPod.Spec.Containers {
-			totalMilliCPU += container.Resources.Limits.Cpu().MilliValue()
-			totalMemory += container.Resources.Limits.Memory().Value()
-		}
-	}
+// 'state' is the state of the node aggregated from the pods currently scheduled on it.
+func calculateOccupancy(pod api.Pod, node api.Node, state *NodeState) HostPriority {
+	totalMilliCPU := state.RequestedMilliCPU()
+	totalMemory := state.RequestedMemory()
 	// Add the resources requested by the current pod being scheduled.
 	// This also helps differentiate between differently sized, but empty, minions.
-	for _, container := range pod.Spec.Containers {
-		totalMilliCPU += container.Resources.Limits.Cpu().MilliValue()
-		totalMemory += container.Resources.Limits.Memory().Value()
-	}
+	podRequest := getResourceRequest(&pod)
+	totalMilliCPU += podRequest.milliCPU
+	totalMemory += podRequest.memory
 
 	capacityMilliCPU := node.Status.Capacity.Cpu().MilliValue()
 	capacityMemory := node.Status.Capacity.Memory().Value()
@@ -67,8 +60,8 @@ func calculateOccupancy(pod api.Pod, node api.Node, pods []api.Pod) HostPriority
 	)
 
 	return HostPriority{
-		host:  node.Name,
-		score: int((cpuScore + memoryScore) / 2),
+		Host:  node.Name,
+		Score: int((cpuScore + memoryScore) / 2),
 	}
 }
 
@@ -81,11 +74,14 @@ func LeastRequestedPriority(pod api.Pod, podLister PodLister, minionLister Minio
 	if err != nil {
 		return HostPriorityList{}, err
 	}
-	podsToMachines, err := MapPodsToMachines(podLister)
+	nodeStates, err := GetNodeStates(podLister)
+	if err != nil {
+		return HostPriorityList{}, err
+	}
 
 	list := HostPriorityList{}
 	for _, node := range nodes.Items {
-		list = append(list, calculateOccupancy(pod, node, podsToMachines[node.Name]))
+		list = append(list, calculateOccupancy(pod, node, nodeStates[node.Name]))
 	}
 	return list, nil
 }
@@ -128,7 +124,46 @@ func (n *NodeLabelPrioritizer) CalculateNodeLabelPriority(pod api.Pod, podLister
 		} else {
 			score = 0
 		}
-		result = append(result, HostPriority{host: minionName, score: score})
+		result = append(result, HostPriority{Host: minionName, Score: score})
+	}
+	return result, nil
+}
+
+// NodeAffinityPriority favors the nodes matching the preferred terms of the
+// node affinity of the pod. The score of a node is the sum of the weights of
+// the terms it matches, scaled to 0-10 relative to the best scoring node.
+func NodeAffinityPriority(pod api.Pod, podLister PodLister, minionLister MinionLister) (HostPriorityList, error) {
+	minions, err := minionLister.List()
+	if err != nil {
+		return nil, err
+	}
+
+	var preferred []api.PreferredSchedulingTerm
+	if pod.Spec.Affinity != nil && pod.Spec.Affinity.NodeAffinity != nil {
+		preferred = pod.Spec.Affinity.NodeAffinity.Preferred
+	}
+
+	counts := map[string]int{}
+	maxCount := 0
+	for i := range minions.Items {
+		minion := &minions.Items[i]
+		for j := range preferred {
+			if nodeMatchesSelectorTerm(minion, &preferred[j].Preference) {
+				counts[minion.Name] += preferred[j].Weight
+			}
+		}
+		if counts[minion.Name] > maxCount {
+			maxCount = counts[minion.Name]
+		}
+	}
+
+	result := []HostPriority{}
+	for _, minion := range minions.Items {
+		score := 0
+		if maxCount > 0 {
+			score = 10 * counts[minion.Name] / maxCount
+		}
+		result = append(result, HostPriority{Host: minion.Name, Score: score})
 	}
 	return result, nil
 }
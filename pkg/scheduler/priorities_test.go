@@ -93,6 +93,22 @@ func TestLeastRequested(t *testing.T) {
 			},
 		},
 	}
+	requestsBelowLimits := api.PodSpec{
+		Containers: []api.Container{
+			{
+				Resources: api.ResourceRequirements{
+					Requests: api.ResourceList{
+						"cpu":    resource.MustParse("1000m"),
+						"memory": resource.MustParse("2000"),
+					},
+					Limits: api.ResourceList{
+						"cpu":    resource.MustParse("3000m"),
+						"memory": resource.MustParse("5000"),
+					},
+				},
+			},
+		},
+	}
 	tests := []struct {
 		pod          api.Pod
 		pods         []api.Pod
@@ -134,6 +150,23 @@ func TestLeastRequested(t *testing.T) {
 			expectedList: []HostPriority{{"machine1", 3}, {"machine2", 5}},
 			test:         "nothing scheduled, resources requested, differently sized machines",
 		},
+		{
+			/*
+				Minion1 scores on 0-10 scale
+				CPU Score: ((4000 - 1000) *10) / 4000 = 7.5
+				Memory Score: ((10000 - 2000) *10) / 10000 = 8
+				Minion1 Score: (7.5 + 8) / 2 = 7
+
+				Minion2 scores on 0-10 scale
+				CPU Score: ((6000 - 1000) *10) / 6000 = 8.3
+				Memory Score: ((10000 - 2000) *10) / 10000 = 8
+				Minion2 Score: (8.3 + 8) / 2 = 8
+			*/
+			pod:          api.Pod{Spec: requestsBelowLimits},
+			nodes:        []api.Node{makeMinion("machine1", 4000, 10000), makeMinion("machine2", 6000, 10000)},
+			expectedList: []HostPriority{{"machine1", 7}, {"machine2", 8}},
+			test:         "nothing scheduled, requests below limits",
+		},
 		{
 			/*
 				Minion1 scores on 0-10 scale
@@ -364,3 +397,63 @@ func TestNewNodeLabelPriority(t *testing.T) {
 		}
 	}
 }
+
+func TestNodeAffinityPriority(t *testing.T) {
+	label1 := map[string]string{"memory": "high"}
+	label2 := map[string]string{"memory": "high", "ssd": "true"}
+	label3 := map[string]string{"ssd": "true"}
+	highMemory := api.NodeSelectorTerm{
+		MatchExpressions: []api.NodeSelectorRequirement{{Key: "memory", Operator: api.NodeSelectorOpIn, Values: []string{"high"}}},
+	}
+	ssd := api.NodeSelectorTerm{
+		MatchExpressions: []api.NodeSelectorRequirement{{Key: "ssd", Operator: api.NodeSelectorOpExists}},
+	}
+	nodes := []api.Node{
+		{ObjectMeta: api.ObjectMeta{Name: "machine1", Labels: label1}},
+		{ObjectMeta: api.ObjectMeta{Name: "machine2", Labels: label2}},
+		{ObjectMeta: api.ObjectMeta{Name: "machine3", Labels: label3}},
+	}
+	tests := []struct {
+		preferred    []api.PreferredSchedulingTerm
+		expectedList HostPriorityList
+		test         string
+	}{
+		{
+			expectedList: []HostPriority{{"machine1", 0}, {"machine2", 0}, {"machine3", 0}},
+			test:         "no preferred terms",
+		},
+		{
+			preferred: []api.PreferredSchedulingTerm{
+				{Weight: 5, Preference: api.NodeSelectorTerm{
+					MatchExpressions: []api.NodeSelectorRequirement{{Key: "gpu", Operator: api.NodeSelectorOpExists}},
+				}},
+			},
+			expectedList: []HostPriority{{"machine1", 0}, {"machine2", 0}, {"machine3", 0}},
+			test:         "no node matches",
+		},
+		{
+			preferred:    []api.PreferredSchedulingTerm{{Weight: 1, Preference: highMemory}},
+			expectedList: []HostPriority{{"machine1", 10}, {"machine2", 10}, {"machine3", 0}},
+			test:         "one term",
+		},
+		{
+			preferred:    []api.PreferredSchedulingTerm{{Weight: 3, Preference: highMemory}, {Weight: 1, Preference: ssd}},
+			expectedList: []HostPriority{{"machine1", 7}, {"machine2", 10}, {"machine3", 2}},
+			test:         "weighted terms",
+		},
+	}
+
+	for _, test := range tests {
+		pod := api.Pod{Spec: api.PodSpec{Affinity: &api.Affinity{NodeAffinity: &api.NodeAffinity{Preferred: test.preferred}}}}
+		list, err := NodeAffinityPriority(pod, FakePodLister([]api.Pod{}), FakeMinionLister(api.NodeList{Items: nodes}))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		// sort the two lists to avoid failures on account of different ordering
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
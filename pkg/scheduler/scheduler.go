@@ -25,3 +25,11 @@ import (
 type Scheduler interface {
 	Schedule(api.Pod, MinionLister) (selectedMachine string, err error)
 }
+
+// Preemptor is implemented by the schedulers which can make room for a pod
+// no node fits by evicting pods of lower priority.
+type Preemptor interface {
+	// Preempt returns the node the pod fits on once the returned pods, which
+	// run on that node with a lower priority than the pod, are evicted.
+	Preempt(api.Pod, MinionLister) (selectedMachine string, victims []api.Pod, err error)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+
+	"github.com/golang/glog"
+)
+
+var (
+	_ = PodLister(&SchedulerCache{})
+	_ = NodeStateLister(&SchedulerCache{})
+	_ = LabeledPodLister(&SchedulerCache{})
+)
+
+// SchedulerCache keeps the pods bound to minions, both the ones the scheduler
+// observed to be bound and the ones it assumes to be bound because it bound
+// them itself, and the state of every minion aggregated from its pods. It is
+// kept up to date pod by pod, so that scheduling a pod does not walk all the
+// pods of the cluster.
+type SchedulerCache struct {
+	// How long a pod assumed to be bound stays in the cache when its binding
+	// is not observed, because it failed or the pod was deleted meanwhile.
+	assumedPodTTL time.Duration
+	// now is time.Now, except in tests.
+	now func() time.Time
+
+	lock sync.Mutex
+	// The pods by namespace and name.
+	pods map[string]*api.Pod
+	// The deadlines of the pods assumed to be bound, by namespace and name.
+	assumed map[string]time.Time
+	// The states of the minions which run pods, by minion name.
+	nodes map[string]*NodeState
+}
+
+// NewSchedulerCache returns an empty cache, which forgets the pods assumed
+// to be bound after assumedPodTTL unless their binding is observed.
+func NewSchedulerCache(assumedPodTTL time.Duration) *SchedulerCache {
+	return &SchedulerCache{
+		assumedPodTTL: assumedPodTTL,
+		now:           time.Now,
+		pods:          map[string]*api.Pod{},
+		assumed:       map[string]time.Time{},
+		nodes:         map[string]*NodeState{},
+	}
+}
+
+func podKey(pod *api.Pod) string {
+	return pod.Namespace + "/" + pod.Name
+}
+
+// AddPod adds a pod observed to be bound, replacing the pod with the same
+// namespace and name, were it assumed to be bound or not.
+func (c *SchedulerCache) AddPod(pod *api.Pod) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := podKey(pod)
+	delete(c.assumed, key)
+	c.setPod(key, pod)
+}
+
+// UpdatePod updates a pod observed to be bound.
+func (c *SchedulerCache) UpdatePod(pod *api.Pod) {
+	c.AddPod(pod)
+}
+
+// RemovePod removes a pod, were it assumed to be bound or not.
+func (c *SchedulerCache) RemovePod(pod *api.Pod) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := podKey(pod)
+	delete(c.assumed, key)
+	c.deletePod(key)
+}
+
+// Replace replaces the pods observed to be bound with the given ones. The pods
+// assumed to be bound that are not among them stay in the cache.
+func (c *SchedulerCache) Replace(pods []api.Pod) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	all := map[string]*api.Pod{}
+	for key := range c.assumed {
+		all[key] = c.pods[key]
+	}
+	for ix := range pods {
+		pod := pods[ix]
+		key := podKey(&pod)
+		delete(c.assumed, key)
+		all[key] = &pod
+	}
+
+	machineToPods := map[string][]api.Pod{}
+	for _, pod := range all {
+		machineToPods[pod.Status.Host] = append(machineToPods[pod.Status.Host], *pod)
+	}
+	c.pods = all
+	c.nodes = map[string]*NodeState{}
+	for machine, pods := range machineToPods {
+		c.nodes[machine] = NewNodeState(pods...)
+	}
+}
+
+// AssumePod adds a pod the scheduler bound, until its binding is observed or
+// the pod is forgotten. A pod already observed to be bound is left as is.
+func (c *SchedulerCache) AssumePod(pod *api.Pod) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := podKey(pod)
+	_, found := c.pods[key]
+	_, assumed := c.assumed[key]
+	if found && !assumed {
+		return
+	}
+	c.assumed[key] = c.now().Add(c.assumedPodTTL)
+	c.setPod(key, pod)
+}
+
+// ForgetPod removes a pod assumed to be bound, unless its binding was observed.
+func (c *SchedulerCache) ForgetPod(pod *api.Pod) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	key := podKey(pod)
+	if _, assumed := c.assumed[key]; assumed {
+		delete(c.assumed, key)
+		c.deletePod(key)
+	}
+}
+
+// List returns the pods matching the selector, observed or assumed to be bound.
+func (c *SchedulerCache) List(selector labels.Selector) ([]api.Pod, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expireAssumedPods()
+	pods := []api.Pod{}
+	for _, pod := range c.pods {
+		if selector.Matches(labels.Set(pod.Labels)) {
+			pods = append(pods, *pod)
+		}
+	}
+	return pods, nil
+}
+
+// ListWithLabels returns the pods carrying all the labels of the set, observed
+// or assumed to be bound. Unlike List, it only looks at the pods which carry
+// the labels, through the label index of every minion.
+func (c *SchedulerCache) ListWithLabels(set labels.Set) ([]api.Pod, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expireAssumedPods()
+	pods := []api.Pod{}
+	for _, state := range c.nodes {
+		pods = append(pods, state.PodsWithLabels(set)...)
+	}
+	return pods, nil
+}
+
+// NodeStates returns the states of the minions which run pods, by minion name.
+// The states do not change once returned, the cache replaces them instead.
+func (c *SchedulerCache) NodeStates() (map[string]*NodeState, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.expireAssumedPods()
+	states := make(map[string]*NodeState, len(c.nodes))
+	for machine, state := range c.nodes {
+		states[machine] = state
+	}
+	return states, nil
+}
+
+func (c *SchedulerCache) expireAssumedPods() {
+	now := c.now()
+	for key, deadline := range c.assumed {
+		if now.After(deadline) {
+			glog.V(2).Infof("Forgetting pod %s: its binding was not observed in %v", key, c.assumedPodTTL)
+			delete(c.assumed, key)
+			c.deletePod(key)
+		}
+	}
+}
+
+// setPod adds the pod, or replaces the pod with the same key. Like in
+// MapPodsToMachines, the minion of a pod is read from its status.
+func (c *SchedulerCache) setPod(key string, pod *api.Pod) {
+	c.deletePod(key)
+	stored := *pod
+	c.pods[key] = &stored
+	state, found := c.nodes[stored.Status.Host]
+	if !found {
+		state = NewNodeState()
+	}
+	c.nodes[stored.Status.Host] = state.withPod(&stored)
+}
+
+func (c *SchedulerCache) deletePod(key string) {
+	pod, found := c.pods[key]
+	if !found {
+		return
+	}
+	delete(c.pods, key)
+	state := c.nodes[pod.Status.Host].withoutPod(pod.Namespace, pod.Name)
+	if len(state.pods) == 0 {
+		delete(c.nodes, pod.Status.Host)
+		return
+	}
+	c.nodes[pod.Status.Host] = state
+}
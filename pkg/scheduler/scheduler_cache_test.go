@@ -0,0 +1,296 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func cachedPod(name, host string, milliCPU int64, hostPort int) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{Namespace: "default", Name: name, Labels: map[string]string{"name": name}},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{
+				Resources: api.ResourceRequirements{
+					Requests: api.ResourceList{"cpu": *resource.NewMilliQuantity(milliCPU, resource.DecimalSI)},
+				},
+				Ports: []api.ContainerPort{{HostPort: hostPort}},
+			}},
+		},
+		Status: api.PodStatus{Host: host},
+	}
+}
+
+func podNames(pods []api.Pod) []string {
+	names := []string{}
+	for _, pod := range pods {
+		names = append(names, pod.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestSchedulerCache(t *testing.T) {
+	cache := NewSchedulerCache(time.Minute)
+	cache.AddPod(cachedPod("a", "machine1", 100, 80))
+	cache.AddPod(cachedPod("b", "machine1", 200, 0))
+	cache.AddPod(cachedPod("c", "machine2", 300, 0))
+
+	states, err := cache.NodeStates()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"a", "b"}, podNames(states["machine1"].Pods()); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v on machine1, got %v", e, a)
+	}
+	if cpu := states["machine1"].RequestedMilliCPU(); cpu != 300 {
+		t.Errorf("expected 300m CPU requested on machine1, got %d", cpu)
+	}
+	if !states["machine1"].UsesHostPort(80) || states["machine2"].UsesHostPort(80) {
+		t.Errorf("expected host port 80 used on machine1 only")
+	}
+
+	// Moving a pod updates both minions, and leaves the returned states as they were.
+	cache.UpdatePod(cachedPod("a", "machine2", 100, 80))
+	updated, _ := cache.NodeStates()
+	if updated["machine1"].UsesHostPort(80) || !updated["machine2"].UsesHostPort(80) {
+		t.Errorf("expected host port 80 used on machine2 only")
+	}
+	if cpu := updated["machine2"].RequestedMilliCPU(); cpu != 400 {
+		t.Errorf("expected 400m CPU requested on machine2, got %d", cpu)
+	}
+	if cpu := states["machine1"].RequestedMilliCPU(); cpu != 300 {
+		t.Errorf("expected the earlier state of machine1 unchanged, got %dm CPU", cpu)
+	}
+
+	cache.RemovePod(cachedPod("b", "machine1", 200, 0))
+	updated, _ = cache.NodeStates()
+	if _, found := updated["machine1"]; found {
+		t.Errorf("expected no state for machine1 once its pods are gone")
+	}
+
+	pods, err := cache.List(labels.SelectorFromSet(labels.Set{"name": "c"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"c"}, podNames(pods); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v, got %v", e, a)
+	}
+	pods, err = ListPodsWithLabels(cache, labels.Set{"name": "a"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"a"}, podNames(pods); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v, got %v", e, a)
+	}
+}
+
+func TestNodeStatePodsWithLabels(t *testing.T) {
+	labeled := func(name string, podLabels map[string]string) api.Pod {
+		pod := cachedPod(name, "machine1", 100, 0)
+		pod.Labels = podLabels
+		return *pod
+	}
+	state := NewNodeState(
+		labeled("a", map[string]string{"app": "web", "tier": "frontend"}),
+		labeled("b", map[string]string{"app": "web", "tier": "backend"}),
+	)
+	c := labeled("c", map[string]string{"app": "db", "tier": "backend"})
+	state = state.withPod(&c)
+	withoutA := state.withoutPod("default", "a")
+
+	tests := []struct {
+		state    *NodeState
+		set      labels.Set
+		expected []string
+	}{
+		{state, labels.Set{"app": "web"}, []string{"a", "b"}},
+		{state, labels.Set{"tier": "backend"}, []string{"b", "c"}},
+		{state, labels.Set{"app": "web", "tier": "backend"}, []string{"b"}},
+		{state, labels.Set{"app": "cache"}, []string{}},
+		{state, labels.Set{}, []string{"a", "b", "c"}},
+		{withoutA, labels.Set{"app": "web"}, []string{"b"}},
+		{withoutA, labels.Set{"tier": "backend"}, []string{"b", "c"}},
+		{nil, labels.Set{"app": "web"}, []string{}},
+	}
+	for _, test := range tests {
+		pods := test.state.PodsWithLabels(test.set)
+		if e, a := test.expected, podNames(pods); !reflect.DeepEqual(e, a) {
+			t.Errorf("expected pods %v with labels %v, got %v", e, test.set, a)
+		}
+		// The index agrees with matching every pod against the selector.
+		matched, _ := FakePodLister(test.state.Pods()).List(labels.SelectorFromSet(test.set))
+		if e, a := podNames(matched), podNames(pods); !reflect.DeepEqual(e, a) {
+			t.Errorf("expected pods %v with labels %v, got %v", e, test.set, a)
+		}
+	}
+}
+
+func TestSchedulerCacheAssumedPods(t *testing.T) {
+	now := time.Now()
+	cache := NewSchedulerCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	// An assumed pod is replaced by the observed one, which forgetting leaves.
+	cache.AssumePod(cachedPod("a", "machine1", 100, 0))
+	cache.AddPod(cachedPod("a", "machine1", 100, 0))
+	cache.ForgetPod(cachedPod("a", "machine1", 100, 0))
+	// An observed pod is not replaced by an assumed one.
+	cache.AssumePod(cachedPod("a", "machine2", 100, 0))
+	// Assumed pods are forgotten, or expire.
+	cache.AssumePod(cachedPod("b", "machine1", 100, 0))
+	cache.ForgetPod(cachedPod("b", "machine1", 100, 0))
+	cache.AssumePod(cachedPod("c", "machine1", 100, 0))
+	cache.AssumePod(cachedPod("d", "machine2", 100, 0))
+
+	states, _ := cache.NodeStates()
+	if e, a := []string{"a", "c"}, podNames(states["machine1"].Pods()); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v on machine1, got %v", e, a)
+	}
+	if e, a := []string{"d"}, podNames(states["machine2"].Pods()); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v on machine2, got %v", e, a)
+	}
+
+	// Replacing the observed pods keeps the assumed ones.
+	cache.Replace([]api.Pod{*cachedPod("e", "machine2", 100, 0)})
+	pods, _ := cache.List(labels.Everything())
+	if e, a := []string{"c", "d", "e"}, podNames(pods); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v, got %v", e, a)
+	}
+
+	now = now.Add(2 * time.Minute)
+	pods, _ = cache.List(labels.Everything())
+	if e, a := []string{"e"}, podNames(pods); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected pods %v once the assumed pods expired, got %v", e, a)
+	}
+	states, _ = cache.NodeStates()
+	if _, found := states["machine1"]; found {
+		t.Errorf("expected no state for machine1 once its assumed pod expired")
+	}
+}
+
+func TestNodeState(t *testing.T) {
+	pod := cachedPod("a", "machine1", 100, 80)
+	pod.Spec.Volumes = []api.Volume{{VolumeSource: api.VolumeSource{GCEPersistentDisk: &api.GCEPersistentDiskVolumeSource{PDName: "foo"}}}}
+	state := NewNodeState(*pod, *cachedPod("b", "machine1", 200, 80))
+	if state.RequestedMilliCPU() != 300 || !state.UsesHostPort(80) || !state.MountsGCEPersistentDisk("foo") {
+		t.Errorf("unexpected state %#v", state)
+	}
+	state = state.withoutPod("default", "b")
+	if state.RequestedMilliCPU() != 100 || !state.UsesHostPort(80) {
+		t.Errorf("expected the port still used by a, got %#v", state)
+	}
+	state = state.withoutPod("default", "a")
+	if state.RequestedMilliCPU() != 0 || state.UsesHostPort(80) || state.MountsGCEPersistentDisk("foo") || len(state.Pods()) != 0 {
+		t.Errorf("expected an empty state, got %#v", state)
+	}
+	var missing *NodeState
+	if len(missing.Pods()) != 0 || missing.RequestedMemory() != 0 || missing.UsesHostPort(80) {
+		t.Errorf("expected a nil state to be empty")
+	}
+}
+
+// benchmarkCluster returns minions with room for their share of the pods.
+func benchmarkCluster(minions, pods int) (api.NodeList, []api.Pod) {
+	nodes := api.NodeList{}
+	for i := 0; i < minions; i++ {
+		nodes.Items = append(nodes.Items, makeMinion(fmt.Sprintf("machine%d", i), 64000, 256*1024*1024*1024))
+	}
+	existing := []api.Pod{}
+	for i := 0; i < pods; i++ {
+		pod := cachedPod(fmt.Sprintf("pod%d", i), nodes.Items[i%minions].Name, 100, 8000+i%10)
+		pod.Labels["app"] = fmt.Sprintf("app%d", i%benchmarkServices)
+		existing = append(existing, *pod)
+	}
+	return nodes, existing
+}
+
+// benchmarkServices is the number of services the pods of benchmarkCluster
+// are spread across.
+const benchmarkServices = 30
+
+func benchmarkSchedule(b *testing.B, podLister PodLister, nodes api.NodeList) {
+	prioritizers := []PriorityConfig{{Function: LeastRequestedPriority, Weight: 1}}
+	benchmarkScheduleWithPriorities(b, podLister, nodes, prioritizers)
+}
+
+func benchmarkScheduleWithPriorities(b *testing.B, podLister PodLister, nodes api.NodeList, prioritizers []PriorityConfig) {
+	nodeInfo := StaticNodeInfo{&nodes}
+	predicates := map[string]FitPredicate{
+		"PodFitsPorts":     PodFitsPorts,
+		"PodFitsResources": NewResourceFitPredicate(nodeInfo),
+		"NoDiskConflict":   NoDiskConflict,
+	}
+	scheduler := NewGenericScheduler(predicates, prioritizers, nil, podLister, rand.New(rand.NewSource(0)))
+	pod := *cachedPod("new", "", 100, 9000)
+	pod.Labels["app"] = "app0"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := scheduler.Schedule(pod, FakeMinionLister(nodes)); err != nil {
+			b.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkScheduleFromPodLister(b *testing.B) {
+	nodes, pods := benchmarkCluster(100, 3000)
+	benchmarkSchedule(b, FakePodLister(pods), nodes)
+}
+
+func BenchmarkScheduleFromSchedulerCache(b *testing.B) {
+	nodes, pods := benchmarkCluster(100, 3000)
+	cache := NewSchedulerCache(time.Minute)
+	cache.Replace(pods)
+	benchmarkSchedule(b, cache, nodes)
+}
+
+// spreadingPriorities spreads the pods of the services of benchmarkCluster.
+func spreadingPriorities() []PriorityConfig {
+	services := FakeServiceLister{}
+	for i := 0; i < benchmarkServices; i++ {
+		services = append(services, api.Service{
+			ObjectMeta: api.ObjectMeta{Namespace: "default", Name: fmt.Sprintf("app%d", i)},
+			Spec:       api.ServiceSpec{Selector: map[string]string{"app": fmt.Sprintf("app%d", i)}},
+		})
+	}
+	return []PriorityConfig{
+		{Function: LeastRequestedPriority, Weight: 1},
+		{Function: NewServiceSpreadPriority(services), Weight: 1},
+	}
+}
+
+func BenchmarkScheduleWithSpreadingFromPodLister(b *testing.B) {
+	nodes, pods := benchmarkCluster(100, 3000)
+	benchmarkScheduleWithPriorities(b, FakePodLister(pods), nodes, spreadingPriorities())
+}
+
+func BenchmarkScheduleWithSpreadingFromSchedulerCache(b *testing.B) {
+	nodes, pods := benchmarkCluster(100, 3000)
+	cache := NewSchedulerCache(time.Minute)
+	cache.Replace(pods)
+	benchmarkScheduleWithPriorities(b, cache, nodes, spreadingPriorities())
+}
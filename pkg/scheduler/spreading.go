@@ -21,6 +21,11 @@ import (
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
+// zoneWeighting is the weight of spreading across zones in the spreading priority;
+// the rest of the weight goes to spreading across minions. Losing a zone takes out
+// all the pods in it, so zones weigh more than minions.
+const zoneWeighting = 2.0 / 3.0
+
 type ServiceSpread struct {
 	serviceLister ServiceLister
 }
@@ -42,8 +47,7 @@ func (s *ServiceSpread) CalculateSpreadPriority(pod api.Pod, podLister PodLister
 	if err == nil {
 		// just use the first service and get the other pods within the service
 		// TODO: a separate predicate can be created that tries to handle all services for the pod
-		selector := labels.SelectorFromSet(services[0].Spec.Selector)
-		pods, err := podLister.List(selector)
+		pods, err := ListPodsWithLabels(podLister, services[0].Spec.Selector)
 		if err != nil {
 			return nil, err
 		}
@@ -60,7 +64,16 @@ func (s *ServiceSpread) CalculateSpreadPriority(pod api.Pod, podLister PodLister
 		return nil, err
 	}
 
+	minionZones := map[string]string{}
+	for i := range minions.Items {
+		if zone := getZoneKey(&minions.Items[i]); zone != "" {
+			minionZones[minions.Items[i].Name] = zone
+		}
+	}
+
 	counts := map[string]int{}
+	zoneCounts := map[string]int{}
+	var maxZoneCount int
 	if len(nsServicePods) > 0 {
 		for _, pod := range nsServicePods {
 			counts[pod.Status.Host]++
@@ -68,6 +81,13 @@ func (s *ServiceSpread) CalculateSpreadPriority(pod api.Pod, podLister PodLister
 			if counts[pod.Status.Host] > maxCount {
 				maxCount = counts[pod.Status.Host]
 			}
+			// Compute the maximum number of pods hosted in any zone
+			if zone, ok := minionZones[pod.Status.Host]; ok {
+				zoneCounts[zone]++
+				if zoneCounts[zone] > maxZoneCount {
+					maxZoneCount = zoneCounts[zone]
+				}
+			}
 		}
 	}
 
@@ -80,11 +100,30 @@ func (s *ServiceSpread) CalculateSpreadPriority(pod api.Pod, podLister PodLister
 		if maxCount > 0 {
 			fScore = 10 * (float32(maxCount-counts[minion.Name]) / float32(maxCount))
 		}
-		result = append(result, HostPriority{host: minion.Name, score: int(fScore)})
+		// spread across zones as well, when the minions are labeled with their zone
+		if zone, ok := minionZones[minion.Name]; ok {
+			zoneScore := float32(10)
+			if maxZoneCount > 0 {
+				zoneScore = 10 * (float32(maxZoneCount-zoneCounts[zone]) / float32(maxZoneCount))
+			}
+			fScore = fScore*(1-zoneWeighting) + zoneScore*zoneWeighting
+		}
+		result = append(result, HostPriority{Host: minion.Name, Score: int(fScore)})
 	}
 	return result, nil
 }
 
+// getZoneKey returns a key identifying the zone of the minion, made of its region
+// and failure domain labels, or "" if the minion isn't labeled with a zone.
+func getZoneKey(minion *api.Node) string {
+	zone := minion.Labels[api.LabelZoneFailureDomain]
+	if zone == "" {
+		return ""
+	}
+	// the region is part of the key, as zone names are only unique within a region
+	return minion.Labels[api.LabelZoneRegion] + "/" + zone
+}
+
 type ServiceAntiAffinity struct {
 	serviceLister ServiceLister
 	label         string
@@ -108,8 +147,7 @@ func (s *ServiceAntiAffinity) CalculateAntiAffinityPriority(pod api.Pod, podList
 	if err == nil {
 		// just use the first service and get the other pods within the service
 		// TODO: a separate predicate can be created that tries to handle all services for the pod
-		selector := labels.SelectorFromSet(services[0].Spec.Selector)
-		pods, err := podLister.List(selector)
+		pods, err := ListPodsWithLabels(podLister, services[0].Spec.Selector)
 		if err != nil {
 			return nil, err
 		}
@@ -157,11 +195,11 @@ func (s *ServiceAntiAffinity) CalculateAntiAffinityPriority(pod api.Pod, podList
 		if numServicePods > 0 {
 			fScore = 10 * (float32(numServicePods-podCounts[labeledMinions[minion]]) / float32(numServicePods))
 		}
-		result = append(result, HostPriority{host: minion, score: int(fScore)})
+		result = append(result, HostPriority{Host: minion, Score: int(fScore)})
 	}
 	// add the open minions with a score of 0
 	for _, minion := range otherMinions {
-		result = append(result, HostPriority{host: minion, score: 0})
+		result = append(result, HostPriority{Host: minion, Score: 0})
 	}
 
 	return result, nil
@@ -170,6 +170,58 @@ func TestServiceSpreadPriority(t *testing.T) {
 	}
 }
 
+func TestServiceSpreadPriorityAcrossZones(t *testing.T) {
+	labels1 := map[string]string{"foo": "bar"}
+	zoneA := map[string]string{api.LabelZoneRegion: "region", api.LabelZoneFailureDomain: "zone-a"}
+	zoneB := map[string]string{api.LabelZoneRegion: "region", api.LabelZoneFailureDomain: "zone-b"}
+	nodes := map[string]map[string]string{
+		"machine1": zoneA,
+		"machine2": zoneA,
+		"machine3": zoneB,
+		"machine4": {},
+	}
+	services := []api.Service{{Spec: api.ServiceSpec{Selector: labels1}}}
+	tests := []struct {
+		pods         []api.Pod
+		expectedList HostPriorityList
+		test         string
+	}{
+		{
+			expectedList: []HostPriority{{"machine1", 10}, {"machine2", 10}, {"machine3", 10}, {"machine4", 10}},
+			test:         "nothing scheduled",
+		},
+		{
+			pods: []api.Pod{
+				{Status: api.PodStatus{Host: "machine1"}, ObjectMeta: api.ObjectMeta{Labels: labels1}},
+			},
+			expectedList: []HostPriority{{"machine1", 0}, {"machine2", 3}, {"machine3", 10}, {"machine4", 10}},
+			test:         "one service pod, its zone is avoided",
+		},
+		{
+			pods: []api.Pod{
+				{Status: api.PodStatus{Host: "machine1"}, ObjectMeta: api.ObjectMeta{Labels: labels1}},
+				{Status: api.PodStatus{Host: "machine3"}, ObjectMeta: api.ObjectMeta{Labels: labels1}},
+				{Status: api.PodStatus{Host: "machine3"}, ObjectMeta: api.ObjectMeta{Labels: labels1}},
+			},
+			expectedList: []HostPriority{{"machine1", 5}, {"machine2", 6}, {"machine3", 0}, {"machine4", 10}},
+			test:         "three service pods, two zones",
+		},
+	}
+
+	for _, test := range tests {
+		serviceSpread := ServiceSpread{serviceLister: FakeServiceLister(services)}
+		list, err := serviceSpread.CalculateSpreadPriority(api.Pod{ObjectMeta: api.ObjectMeta{Labels: labels1}}, FakePodLister(test.pods), FakeMinionLister(makeLabeledMinionList(nodes)))
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+		sort.Sort(test.expectedList)
+		sort.Sort(list)
+		if !reflect.DeepEqual(test.expectedList, list) {
+			t.Errorf("%s: expected %#v, got %#v", test.test, test.expectedList, list)
+		}
+	}
+}
+
 func TestZoneSpreadPriority(t *testing.T) {
 	labels1 := map[string]string{
 		"foo": "bar",
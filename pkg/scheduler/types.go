@@ -20,13 +20,14 @@ import (
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
-// FitPredicate is a function that indicates if a pod fits into an existing node.
-type FitPredicate func(pod api.Pod, existingPods []api.Pod, node string) (bool, error)
+// FitPredicate is a function that indicates if a pod fits into an existing node,
+// given the state of the node aggregated from the pods already on it.
+type FitPredicate func(pod api.Pod, state *NodeState, node string) (bool, error)
 
 // HostPriority represents the priority of scheduling to a particular host, lower priority is better.
 type HostPriority struct {
-	host  string
-	score int
+	Host  string
+	Score int
 }
 
 type HostPriorityList []HostPriority
@@ -36,10 +37,10 @@ func (h HostPriorityList) Len() int {
 }
 
 func (h HostPriorityList) Less(i, j int) bool {
-	if h[i].score == h[j].score {
-		return h[i].host < h[j].host
+	if h[i].Score == h[j].Score {
+		return h[i].Host < h[j].Host
 	}
-	return h[i].score < h[j].score
+	return h[i].Score < h[j].Score
 }
 
 func (h HostPriorityList) Swap(i, j int) {
@@ -52,3 +53,15 @@ type PriorityConfig struct {
 	Function PriorityFunction
 	Weight   int
 }
+
+// SchedulerExtender is implemented by the processes external to the scheduler
+// which take part in its decisions, typically because they manage resources
+// the scheduler does not know about.
+type SchedulerExtender interface {
+	// Filter returns the nodes, among the given ones, the pod fits on.
+	Filter(pod api.Pod, nodes api.NodeList) (filteredNodes api.NodeList, err error)
+	// Prioritize returns the scores of the given nodes for the pod and the
+	// weight they are multiplied by before being added to the scores of the
+	// priority functions.
+	Prioritize(pod api.Pod, nodes api.NodeList) (hostPriorities HostPriorityList, weight int, err error)
+}
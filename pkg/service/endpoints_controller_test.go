@@ -203,7 +203,7 @@ func TestFindPort(t *testing.T) {
 
 	for _, tc := range testCases {
 		port, err := findPort(&api.Pod{Spec: api.PodSpec{Containers: tc.containers}},
-			&api.Service{Spec: api.ServiceSpec{Protocol: "TCP", Port: servicePort, TargetPort: tc.port}})
+			&api.ServicePort{Protocol: "TCP", Port: servicePort, TargetPort: tc.port})
 		if err != nil && tc.pass {
 			t.Errorf("unexpected error for %s: %v", tc.name, err)
 		}
@@ -310,7 +310,7 @@ func TestSyncEndpointsProtocolTCP(t *testing.T) {
 				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
 				Spec: api.ServiceSpec{
 					Selector: map[string]string{},
-					Protocol: api.ProtocolTCP,
+					Ports:    []api.ServicePort{{Port: 80, Protocol: api.ProtocolTCP}},
 				},
 			},
 		},
@@ -344,7 +344,7 @@ func TestSyncEndpointsProtocolUDP(t *testing.T) {
 				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
 				Spec: api.ServiceSpec{
 					Selector: map[string]string{},
-					Protocol: api.ProtocolUDP,
+					Ports:    []api.ServicePort{{Port: 80, Protocol: api.ProtocolUDP}},
 				},
 			},
 		},
@@ -378,6 +378,7 @@ func TestSyncEndpointsItemsEmptySelectorSelectsAll(t *testing.T) {
 				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
 				Spec: api.ServiceSpec{
 					Selector: map[string]string{},
+					Ports:    []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
@@ -420,6 +421,7 @@ func TestSyncEndpointsItemsPreexisting(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
@@ -465,6 +467,7 @@ func TestSyncEndpointsItemsPreexistingIdentical(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
@@ -499,6 +502,7 @@ func TestSyncEndpointsItems(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
@@ -534,6 +538,99 @@ func TestSyncEndpointsItems(t *testing.T) {
 	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
 }
 
+func TestSyncEndpointsItemsSkipsTerminatingPods(t *testing.T) {
+	serviceList := api.ServiceList{
+		Items: []api.Service{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{
+						"foo": "bar",
+					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
+				},
+			},
+		},
+	}
+	pods := newPodList(3, 1)
+	now := util.Now()
+	pods.Items[1].DeletionTimestamp = &now
+	testServer, endpointsHandler := makeTestServer(t, "other",
+		serverResponse{http.StatusOK, pods},
+		serverResponse{http.StatusOK, &serviceList},
+		serverResponse{http.StatusOK, &api.Endpoints{}})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+	endpoints := NewEndpointController(client)
+	if err := endpoints.SyncServiceEndpoints(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectedSubsets := []api.EndpointSubset{{
+		Addresses: []api.EndpointAddress{
+			{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Name: "pod0"}},
+			{IP: "1.2.3.6", TargetRef: &api.ObjectReference{Kind: "Pod", Name: "pod2"}},
+		},
+		Ports: []api.EndpointPort{
+			{Port: 8080, Protocol: "TCP"},
+		},
+	}}
+	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{
+			ResourceVersion: "",
+		},
+		Subsets: endptspkg.SortSubsets(expectedSubsets),
+	})
+	endpointsHandler.ValidateRequestCount(t, 2)
+	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
+}
+
+func TestSyncEndpointsItemsMultiplePorts(t *testing.T) {
+	serviceList := api.ServiceList{
+		Items: []api.Service{
+			{
+				ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "other"},
+				Spec: api.ServiceSpec{
+					Selector: map[string]string{
+						"foo": "bar",
+					},
+					Ports: []api.ServicePort{
+						{Name: "http", Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)},
+						{Name: "https", Port: 443, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8081)},
+					},
+				},
+			},
+		},
+	}
+	testServer, endpointsHandler := makeTestServer(t, "other",
+		serverResponse{http.StatusOK, newPodList(2, 2)},
+		serverResponse{http.StatusOK, &serviceList},
+		serverResponse{http.StatusOK, &api.Endpoints{}})
+	defer testServer.Close()
+	client := client.NewOrDie(&client.Config{Host: testServer.URL, Version: testapi.Version()})
+	endpoints := NewEndpointController(client)
+	if err := endpoints.SyncServiceEndpoints(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	expectedSubsets := []api.EndpointSubset{{
+		Addresses: []api.EndpointAddress{
+			{IP: "1.2.3.4", TargetRef: &api.ObjectReference{Kind: "Pod", Name: "pod0"}},
+			{IP: "1.2.3.5", TargetRef: &api.ObjectReference{Kind: "Pod", Name: "pod1"}},
+		},
+		Ports: []api.EndpointPort{
+			{Name: "http", Port: 8080, Protocol: "TCP"},
+			{Name: "https", Port: 8081, Protocol: "TCP"},
+		},
+	}}
+	data := runtime.EncodeOrDie(testapi.Codec(), &api.Endpoints{
+		ObjectMeta: api.ObjectMeta{
+			ResourceVersion: "",
+		},
+		Subsets: endptspkg.SortSubsets(expectedSubsets),
+	})
+	endpointsHandler.ValidateRequestCount(t, 2)
+	endpointsHandler.ValidateRequest(t, testapi.ResourcePathWithQueryParams("endpoints", "other", ""), "POST", &data)
+}
+
 func TestSyncEndpointsPodError(t *testing.T) {
 	serviceList := api.ServiceList{
 		Items: []api.Service{
@@ -543,6 +640,7 @@ func TestSyncEndpointsPodError(t *testing.T) {
 					Selector: map[string]string{
 						"foo": "bar",
 					},
+					Ports: []api.ServicePort{{Port: 80, Protocol: "TCP", TargetPort: util.NewIntOrStringFromInt(8080)}},
 				},
 			},
 		},
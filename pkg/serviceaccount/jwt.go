@@ -0,0 +1,278 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/authenticator"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+const (
+	// Issuer is the issuer of every service account token.
+	Issuer = "kubernetes/serviceaccount"
+
+	// The claims carried by a service account token.
+	SubjectClaim            = "sub"
+	IssuerClaim             = "iss"
+	ServiceAccountNameClaim = "kubernetes.io/serviceaccount/service-account.name"
+	ServiceAccountUIDClaim  = "kubernetes.io/serviceaccount/service-account.uid"
+	SecretNameClaim         = "kubernetes.io/serviceaccount/secret.name"
+	NamespaceClaim          = "kubernetes.io/serviceaccount/namespace"
+)
+
+// jwtHeader is the header of every token; tokens are always signed with RS256.
+var jwtHeader = map[string]string{"alg": "RS256", "typ": "JWT"}
+
+// ServiceAccountTokenGetter defines functions to retrieve a named service account and secret
+type ServiceAccountTokenGetter interface {
+	GetServiceAccount(namespace, name string) (*api.ServiceAccount, error)
+	GetSecret(namespace, name string) (*api.Secret, error)
+}
+
+// clientGetter implements ServiceAccountTokenGetter using a client.Interface
+type clientGetter struct {
+	client client.Interface
+}
+
+// NewGetterFromClient returns a ServiceAccountTokenGetter that uses the specified
+// client to retrieve service accounts and secrets.
+func NewGetterFromClient(c client.Interface) ServiceAccountTokenGetter {
+	return clientGetter{c}
+}
+
+func (c clientGetter) GetServiceAccount(namespace, name string) (*api.ServiceAccount, error) {
+	return c.client.ServiceAccounts(namespace).Get(name)
+}
+
+func (c clientGetter) GetSecret(namespace, name string) (*api.Secret, error) {
+	return c.client.Secrets(namespace).Get(name)
+}
+
+// TokenGenerator generates the api token stored in a service account token secret.
+type TokenGenerator interface {
+	// GenerateToken generates a token which will identify the given ServiceAccount.
+	// The returned token will be stored in the given (and yet-unpersisted) Secret.
+	GenerateToken(serviceAccount api.ServiceAccount, secret api.Secret) (string, error)
+}
+
+// ReadPrivateKey is a helper function for reading an rsa.PrivateKey from a PEM-encoded file
+func ReadPrivateKey(file string) (*rsa.PrivateKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", file)
+	}
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
+
+// ReadPublicKey is a helper function for reading an rsa.PublicKey from a PEM-encoded file.
+// Reads public keys from both public and private key files.
+func ReadPublicKey(file string) (*rsa.PublicKey, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", file)
+	}
+	if privateKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return &privateKey.PublicKey, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s does not contain an RSA public key", file)
+	}
+	return publicKey, nil
+}
+
+// JWTTokenGenerator returns a TokenGenerator that generates JWT tokens signed with the given private key.
+func JWTTokenGenerator(key *rsa.PrivateKey) TokenGenerator {
+	return &jwtTokenGenerator{key}
+}
+
+type jwtTokenGenerator struct {
+	key *rsa.PrivateKey
+}
+
+func (j *jwtTokenGenerator) GenerateToken(serviceAccount api.ServiceAccount, secret api.Secret) (string, error) {
+	claims := map[string]string{
+		IssuerClaim:  Issuer,
+		SubjectClaim: MakeUsername(serviceAccount.Namespace, serviceAccount.Name),
+
+		NamespaceClaim:          serviceAccount.Namespace,
+		ServiceAccountNameClaim: serviceAccount.Name,
+		ServiceAccountUIDClaim:  string(serviceAccount.UID),
+		SecretNameClaim:         secret.Name,
+	}
+	return signJWT(claims, j.key)
+}
+
+// JWTTokenAuthenticator authenticates tokens as JWT tokens produced by JWTTokenGenerator
+// Token signatures are verified using each of the given public keys until one works (allowing key rotation)
+// If lookup is true, the service account and secret referenced as claims inside the token are retrieved and verified with the provided ServiceAccountTokenGetter
+func JWTTokenAuthenticator(keys []*rsa.PublicKey, lookup bool, getter ServiceAccountTokenGetter) authenticator.Token {
+	return &jwtTokenAuthenticator{keys, lookup, getter}
+}
+
+type jwtTokenAuthenticator struct {
+	keys   []*rsa.PublicKey
+	lookup bool
+	getter ServiceAccountTokenGetter
+}
+
+// errInvalidSignature is returned when no key verifies the signature of a token.
+var errInvalidSignature = errors.New("token signature is invalid")
+
+func (j *jwtTokenAuthenticator) AuthenticateToken(token string) (user.Info, bool, error) {
+	claims, signingInput, signature, ok := parseJWT(token)
+	if !ok {
+		// Not a JWT, leave it to the other authenticators.
+		return nil, false, nil
+	}
+	if claims[IssuerClaim] != Issuer {
+		// A JWT from someone else, leave it to the other authenticators.
+		return nil, false, nil
+	}
+	if !j.verify(signingInput, signature) {
+		return nil, false, errInvalidSignature
+	}
+
+	namespace := claims[NamespaceClaim]
+	serviceAccountName := claims[ServiceAccountNameClaim]
+	serviceAccountUID := claims[ServiceAccountUIDClaim]
+	secretName := claims[SecretNameClaim]
+	if len(namespace) == 0 || len(serviceAccountName) == 0 || len(serviceAccountUID) == 0 || len(secretName) == 0 {
+		return nil, false, errors.New("token is missing required claims")
+	}
+	if claims[SubjectClaim] != MakeUsername(namespace, serviceAccountName) {
+		return nil, false, errors.New("token subject does not match its service account")
+	}
+
+	if j.lookup {
+		// Make sure the token is still stored in a secret of the service account.
+		secret, err := j.getter.GetSecret(namespace, secretName)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to look up token secret %s/%s: %v", namespace, secretName, err)
+		}
+		if !bytes.Equal(secret.Data[api.ServiceAccountTokenKey], []byte(token)) {
+			return nil, false, errors.New("token does not match its secret")
+		}
+
+		// Make sure the service account still exists and is the one the token was issued to.
+		serviceAccount, err := j.getter.GetServiceAccount(namespace, serviceAccountName)
+		if err != nil {
+			return nil, false, fmt.Errorf("unable to look up service account %s/%s: %v", namespace, serviceAccountName, err)
+		}
+		if string(serviceAccount.UID) != serviceAccountUID {
+			return nil, false, fmt.Errorf("service account UID (%s) does not match claim (%s)", serviceAccount.UID, serviceAccountUID)
+		}
+	}
+
+	return UserInfo(namespace, serviceAccountName, serviceAccountUID), true, nil
+}
+
+// verify returns true if any of the keys verifies the signature.
+func (j *jwtTokenAuthenticator) verify(signingInput string, signature []byte) bool {
+	hashed := sha256.Sum256([]byte(signingInput))
+	for _, key := range j.keys {
+		if rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed[:], signature) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// signJWT returns the compact serialization of a JWT carrying claims, signed with RS256.
+func signJWT(claims map[string]string, key *rsa.PrivateKey) (string, error) {
+	header, err := json.Marshal(jwtHeader)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encodeSegment(signature), nil
+}
+
+// parseJWT splits an RS256 JWT into its claims, the input its signature covers
+// and the signature. It returns false if token is not such a JWT.
+func parseJWT(token string) (map[string]string, string, []byte, bool) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, "", nil, false
+	}
+	header := map[string]string{}
+	if data, err := decodeSegment(parts[0]); err != nil || json.Unmarshal(data, &header) != nil {
+		return nil, "", nil, false
+	}
+	if header["alg"] != jwtHeader["alg"] {
+		return nil, "", nil, false
+	}
+	claims := map[string]string{}
+	if data, err := decodeSegment(parts[1]); err != nil || json.Unmarshal(data, &claims) != nil {
+		return nil, "", nil, false
+	}
+	signature, err := decodeSegment(parts[2])
+	if err != nil {
+		return nil, "", nil, false
+	}
+	return claims, parts[0] + "." + parts[1], signature, true
+}
+
+// encodeSegment encodes a JWT segment as unpadded base64url.
+func encodeSegment(data []byte) string {
+	return strings.TrimRight(base64.URLEncoding.EncodeToString(data), "=")
+}
+
+// decodeSegment decodes an unpadded base64url JWT segment.
+func decodeSegment(segment string) ([]byte, error) {
+	if l := len(segment) % 4; l > 0 {
+		segment += strings.Repeat("=", 4-l)
+	}
+	return base64.URLEncoding.DecodeString(segment)
+}
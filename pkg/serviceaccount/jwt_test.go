@@ -0,0 +1,223 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// testGetter is a ServiceAccountTokenGetter serving fixed objects.
+type testGetter struct {
+	serviceAccounts map[string]*api.ServiceAccount
+	secrets         map[string]*api.Secret
+}
+
+func (g *testGetter) GetServiceAccount(namespace, name string) (*api.ServiceAccount, error) {
+	if serviceAccount, ok := g.serviceAccounts[namespace+"/"+name]; ok {
+		return serviceAccount, nil
+	}
+	return nil, errors.NewNotFound("serviceAccounts", name)
+}
+
+func (g *testGetter) GetSecret(namespace, name string) (*api.Secret, error) {
+	if secret, ok := g.secrets[namespace+"/"+name]; ok {
+		return secret, nil
+	}
+	return nil, errors.NewNotFound("secrets", name)
+}
+
+func newKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return key
+}
+
+func newServiceAccount() *api.ServiceAccount {
+	return &api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{Name: "my-service-account", Namespace: "test", UID: "12345"},
+	}
+}
+
+func newTokenSecret(serviceAccount *api.ServiceAccount, name string) *api.Secret {
+	return &api.Secret{
+		ObjectMeta: api.ObjectMeta{
+			Name:      name,
+			Namespace: serviceAccount.Namespace,
+			Annotations: map[string]string{
+				api.ServiceAccountNameKey: serviceAccount.Name,
+				api.ServiceAccountUIDKey:  string(serviceAccount.UID),
+			},
+		},
+		Type: api.SecretTypeServiceAccountToken,
+		Data: map[string][]byte{},
+	}
+}
+
+func TestReadKeys(t *testing.T) {
+	key := newKey(t)
+	dir, err := ioutil.TempDir("", "serviceaccount")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	privateFile := dir + "/private.pem"
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := ioutil.WriteFile(privateFile, privatePEM, 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	publicBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	publicFile := dir + "/public.pem"
+	if err := ioutil.WriteFile(publicFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicBytes}), 0600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	privateKey, err := ReadPrivateKey(privateFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if privateKey.N.Cmp(key.N) != 0 {
+		t.Errorf("read the wrong private key")
+	}
+	for _, file := range []string{privateFile, publicFile} {
+		publicKey, err := ReadPublicKey(file)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", file, err)
+		}
+		if publicKey.N.Cmp(key.N) != 0 {
+			t.Errorf("%s: read the wrong public key", file)
+		}
+	}
+	if _, err := ReadPrivateKey(dir + "/missing.pem"); err == nil {
+		t.Errorf("expected an error reading a missing key")
+	}
+}
+
+func TestTokenGenerateAndValidate(t *testing.T) {
+	key := newKey(t)
+	otherKey := newKey(t)
+
+	serviceAccount := newServiceAccount()
+	secret := newTokenSecret(serviceAccount, "my-secret")
+	token, err := JWTTokenGenerator(key).GenerateToken(*serviceAccount, *secret)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(strings.Split(token, ".")) != 3 {
+		t.Fatalf("expected a JWT, got %q", token)
+	}
+	secret.Data[api.ServiceAccountTokenKey] = []byte(token)
+
+	getter := &testGetter{
+		serviceAccounts: map[string]*api.ServiceAccount{"test/my-service-account": serviceAccount},
+		secrets:         map[string]*api.Secret{"test/my-secret": secret},
+	}
+	recreatedServiceAccount := newServiceAccount()
+	recreatedServiceAccount.UID = "67890"
+
+	testCases := map[string]struct {
+		token  string
+		keys   []*rsa.PublicKey
+		lookup bool
+		getter ServiceAccountTokenGetter
+
+		expectedOK  bool
+		expectedErr bool
+	}{
+		"valid": {
+			token:      token,
+			keys:       []*rsa.PublicKey{&key.PublicKey},
+			expectedOK: true,
+		},
+		"valid with rotated keys": {
+			token:      token,
+			keys:       []*rsa.PublicKey{&otherKey.PublicKey, &key.PublicKey},
+			expectedOK: true,
+		},
+		"invalid signature": {
+			token:       token,
+			keys:        []*rsa.PublicKey{&otherKey.PublicKey},
+			expectedErr: true,
+		},
+		"not a jwt": {
+			token: "abc123",
+			keys:  []*rsa.PublicKey{&key.PublicKey},
+		},
+		"valid with lookup": {
+			token:      token,
+			keys:       []*rsa.PublicKey{&key.PublicKey},
+			lookup:     true,
+			getter:     getter,
+			expectedOK: true,
+		},
+		"deleted secret": {
+			token:       token,
+			keys:        []*rsa.PublicKey{&key.PublicKey},
+			lookup:      true,
+			getter:      &testGetter{serviceAccounts: getter.serviceAccounts},
+			expectedErr: true,
+		},
+		"recreated service account": {
+			token:       token,
+			keys:        []*rsa.PublicKey{&key.PublicKey},
+			lookup:      true,
+			getter:      &testGetter{serviceAccounts: map[string]*api.ServiceAccount{"test/my-service-account": recreatedServiceAccount}, secrets: getter.secrets},
+			expectedErr: true,
+		},
+	}
+
+	for name, tc := range testCases {
+		authenticator := JWTTokenAuthenticator(tc.keys, tc.lookup, tc.getter)
+		user, ok, err := authenticator.AuthenticateToken(tc.token)
+		if (err != nil) != tc.expectedErr {
+			t.Errorf("%s: expected error %v, got %v", name, tc.expectedErr, err)
+			continue
+		}
+		if ok != tc.expectedOK {
+			t.Errorf("%s: expected ok %v, got %v", name, tc.expectedOK, ok)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if user.GetName() != "system:serviceaccount:test:my-service-account" {
+			t.Errorf("%s: unexpected user name %q", name, user.GetName())
+		}
+		if user.GetUID() != "12345" {
+			t.Errorf("%s: unexpected user UID %q", name, user.GetUID())
+		}
+		if groups := util.NewStringSet(user.GetGroups()...); !groups.HasAll("system:serviceaccounts", "system:serviceaccounts:test") {
+			t.Errorf("%s: unexpected groups %v", name, user.GetGroups())
+		}
+	}
+}
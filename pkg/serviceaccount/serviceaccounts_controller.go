@@ -0,0 +1,149 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+// DefaultServiceAccountName is the name of the service account pods run as
+// when they don't name one.
+const DefaultServiceAccountName = "default"
+
+// ServiceAccountsController makes sure a set of service accounts exists in
+// every active namespace.
+type ServiceAccountsController struct {
+	kubeClient client.Interface
+	names      util.StringSet
+	syncTime   <-chan time.Time
+
+	// lock serializes synchronize calls from the watches and the sync loop.
+	lock sync.Mutex
+}
+
+// NewServiceAccountsController returns a new *ServiceAccountsController
+// maintaining the service accounts in names.
+func NewServiceAccountsController(kubeClient client.Interface, names util.StringSet) *ServiceAccountsController {
+	return &ServiceAccountsController{
+		kubeClient: kubeClient,
+		names:      names,
+	}
+}
+
+// Run begins watching and syncing.
+func (e *ServiceAccountsController) Run(period time.Duration) {
+	e.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { e.watchNamespaces(&resourceVersion) }, period)
+}
+
+// watchNamespaces resyncs whenever a namespace changes, and periodically, so
+// that deleted accounts are recreated as well.
+// resourceVersion is a pointer to the resource version to use/update.
+func (e *ServiceAccountsController) watchNamespaces(resourceVersion *string) {
+	watching, err := e.kubeClient.Namespaces().Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch namespaces: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-e.syncTime:
+			e.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from namespace watch: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			namespace, ok := event.Object.(*api.Namespace)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = namespace.ResourceVersion
+			glog.V(4).Infof("Namespace %s %s, syncing", namespace.Name, event.Type)
+			e.synchronize()
+		}
+	}
+}
+
+// synchronize creates the missing service accounts in every active namespace.
+func (e *ServiceAccountsController) synchronize() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	namespaces, err := e.kubeClient.Namespaces().List(labels.Everything(), fields.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list namespaces: %v", err))
+		return
+	}
+	serviceAccounts, err := e.kubeClient.ServiceAccounts(api.NamespaceAll).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list service accounts: %v", err))
+		return
+	}
+	existing := util.NewStringSet()
+	for _, serviceAccount := range serviceAccounts.Items {
+		existing.Insert(serviceAccount.Namespace + "/" + serviceAccount.Name)
+	}
+
+	for _, namespace := range namespaces.Items {
+		// Terminating namespaces refuse new objects.
+		if namespace.Status.Phase != api.NamespaceActive {
+			continue
+		}
+		for _, name := range e.names.List() {
+			if existing.Has(namespace.Name + "/" + name) {
+				continue
+			}
+			serviceAccount := &api.ServiceAccount{
+				ObjectMeta: api.ObjectMeta{Name: name, Namespace: namespace.Name},
+			}
+			glog.V(2).Infof("Creating service account %s/%s", namespace.Name, name)
+			if _, err := e.kubeClient.ServiceAccounts(namespace.Name).Create(serviceAccount); err != nil && !errors.IsAlreadyExists(err) {
+				util.HandleError(fmt.Errorf("unable to create service account %s/%s: %v", namespace.Name, name, err))
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestServiceAccountsControllerCreatesMissingAccounts(t *testing.T) {
+	fakeClient := &client.Fake{
+		NamespacesList: api.NamespaceList{Items: []api.Namespace{
+			{ObjectMeta: api.ObjectMeta{Name: "has-default"}, Status: api.NamespaceStatus{Phase: api.NamespaceActive}},
+			{ObjectMeta: api.ObjectMeta{Name: "missing-default"}, Status: api.NamespaceStatus{Phase: api.NamespaceActive}},
+			{ObjectMeta: api.ObjectMeta{Name: "terminating"}, Status: api.NamespaceStatus{Phase: api.NamespaceTerminating}},
+		}},
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{
+			{ObjectMeta: api.ObjectMeta{Name: DefaultServiceAccountName, Namespace: "has-default"}},
+		}},
+	}
+	NewServiceAccountsController(fakeClient, util.NewStringSet(DefaultServiceAccountName)).synchronize()
+
+	created := actionsOf(fakeClient, "create-serviceAccount")
+	if len(created) != 1 {
+		t.Fatalf("expected one service account to be created, got %#v", fakeClient.Actions)
+	}
+	serviceAccount := created[0].(*api.ServiceAccount)
+	if serviceAccount.Namespace != "missing-default" || serviceAccount.Name != DefaultServiceAccountName {
+		t.Errorf("unexpected service account %s/%s", serviceAccount.Namespace, serviceAccount.Name)
+	}
+}
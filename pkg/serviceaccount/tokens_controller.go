@@ -0,0 +1,256 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+// TokensController makes sure every ServiceAccount references a Secret holding
+// an API token for it, and deletes token Secrets whose ServiceAccount is gone.
+type TokensController struct {
+	kubeClient client.Interface
+	token      TokenGenerator
+	syncTime   <-chan time.Time
+
+	// lock serializes synchronize calls from the watches and the sync loop,
+	// so that an account never gets two tokens minted concurrently.
+	lock sync.Mutex
+}
+
+// Time period of main tokens controller sync loop
+const DefaultSyncPeriod = 10 * time.Second
+
+// NewTokensController returns a new *TokensController minting tokens with token.
+func NewTokensController(kubeClient client.Interface, token TokenGenerator) *TokensController {
+	return &TokensController{
+		kubeClient: kubeClient,
+		token:      token,
+	}
+}
+
+// Run begins watching and syncing.
+func (e *TokensController) Run(period time.Duration) {
+	e.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { e.watchServiceAccounts(&resourceVersion) }, period)
+	secretResourceVersion := ""
+	go util.Forever(func() { e.watchSecrets(&secretResourceVersion) }, period)
+}
+
+// watchServiceAccounts resyncs whenever a service account changes, and periodically.
+// resourceVersion is a pointer to the resource version to use/update.
+func (e *TokensController) watchServiceAccounts(resourceVersion *string) {
+	watching, err := e.kubeClient.ServiceAccounts(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch service accounts: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-e.syncTime:
+			e.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from service account watch: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			serviceAccount, ok := event.Object.(*api.ServiceAccount)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = serviceAccount.ResourceVersion
+			glog.V(4).Infof("Service account %s/%s %s, syncing", serviceAccount.Namespace, serviceAccount.Name, event.Type)
+			e.synchronize()
+		}
+	}
+}
+
+// watchSecrets resyncs whenever a secret is added, changed or removed, so that
+// deleted tokens are replaced promptly.
+// resourceVersion is a pointer to the resource version to use/update.
+func (e *TokensController) watchSecrets(resourceVersion *string) {
+	watching, err := e.kubeClient.Secrets(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch secrets: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		event, open := <-watching.ResultChan()
+		if !open {
+			return
+		}
+		if event.Type == watch.Error {
+			util.HandleError(fmt.Errorf("error from secret watch: %v", errors.FromObject(event.Object)))
+			*resourceVersion = ""
+			continue
+		}
+		secret, ok := event.Object.(*api.Secret)
+		if !ok {
+			util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+			continue
+		}
+		*resourceVersion = secret.ResourceVersion
+		if secret.Type != api.SecretTypeServiceAccountToken {
+			continue
+		}
+		glog.V(4).Infof("Token secret %s/%s %s, syncing", secret.Namespace, secret.Name, event.Type)
+		e.synchronize()
+	}
+}
+
+// synchronize makes sure every service account has a token, and that every
+// token belongs to an existing service account.
+func (e *TokensController) synchronize() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	serviceAccounts, err := e.kubeClient.ServiceAccounts(api.NamespaceAll).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list service accounts: %v", err))
+		return
+	}
+	secrets, err := e.kubeClient.Secrets(api.NamespaceAll).List(labels.Everything(), fields.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to list secrets: %v", err))
+		return
+	}
+
+	// Index the token secrets by namespace and name.
+	tokens := map[string]*api.Secret{}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Type == api.SecretTypeServiceAccountToken {
+			tokens[secret.Namespace+"/"+secret.Name] = secret
+		}
+	}
+
+	accounts := map[string]*api.ServiceAccount{}
+	for i := range serviceAccounts.Items {
+		serviceAccount := &serviceAccounts.Items[i]
+		accounts[serviceAccount.Namespace+"/"+serviceAccount.Name] = serviceAccount
+		if err := e.syncServiceAccount(serviceAccount, tokens); err != nil {
+			util.HandleError(fmt.Errorf("unable to sync service account %s/%s: %v", serviceAccount.Namespace, serviceAccount.Name, err))
+		}
+	}
+
+	for _, secret := range tokens {
+		serviceAccount, ok := accounts[secret.Namespace+"/"+secret.Annotations[api.ServiceAccountNameKey]]
+		if ok && IsServiceAccountToken(secret, serviceAccount) {
+			continue
+		}
+		glog.V(2).Infof("Deleting token %s/%s of missing service account %s", secret.Namespace, secret.Name, secret.Annotations[api.ServiceAccountNameKey])
+		if err := e.kubeClient.Secrets(secret.Namespace).Delete(secret.Name); err != nil && !errors.IsNotFound(err) {
+			util.HandleError(fmt.Errorf("unable to delete token %s/%s: %v", secret.Namespace, secret.Name, err))
+		}
+	}
+}
+
+// syncServiceAccount makes sure serviceAccount references a token secret,
+// minting one if none of the existing tokens belong to it.
+func (e *TokensController) syncServiceAccount(serviceAccount *api.ServiceAccount, tokens map[string]*api.Secret) error {
+	for _, ref := range serviceAccount.Secrets {
+		if secret, ok := tokens[serviceAccount.Namespace+"/"+ref.Name]; ok && IsServiceAccountToken(secret, serviceAccount) {
+			return nil
+		}
+	}
+
+	// A token left behind by an interrupted sync is referenced instead of
+	// minting another one.
+	var token *api.Secret
+	for _, secret := range tokens {
+		if IsServiceAccountToken(secret, serviceAccount) {
+			token = secret
+			break
+		}
+	}
+	if token == nil {
+		var err error
+		if token, err = e.createToken(serviceAccount); err != nil {
+			return err
+		}
+	}
+
+	serviceAccount.Secrets = append(serviceAccount.Secrets, api.ObjectReference{Name: token.Name})
+	if _, err := e.kubeClient.ServiceAccounts(serviceAccount.Namespace).Update(serviceAccount); err != nil {
+		// The token is picked up again on the next sync.
+		return err
+	}
+	return nil
+}
+
+// createToken creates a secret holding a new token for serviceAccount.
+func (e *TokensController) createToken(serviceAccount *api.ServiceAccount) (*api.Secret, error) {
+	secret := &api.Secret{
+		ObjectMeta: api.ObjectMeta{
+			Name:      api.SimpleNameGenerator.GenerateName(fmt.Sprintf("%s-token-", serviceAccount.Name)),
+			Namespace: serviceAccount.Namespace,
+			Annotations: map[string]string{
+				api.ServiceAccountNameKey: serviceAccount.Name,
+				api.ServiceAccountUIDKey:  string(serviceAccount.UID),
+			},
+		},
+		Type: api.SecretTypeServiceAccountToken,
+		Data: map[string][]byte{},
+	}
+
+	token, err := e.token.GenerateToken(*serviceAccount, *secret)
+	if err != nil {
+		return nil, err
+	}
+	secret.Data[api.ServiceAccountTokenKey] = []byte(token)
+
+	glog.V(2).Infof("Creating token %s/%s for service account %s", secret.Namespace, secret.Name, serviceAccount.Name)
+	if _, err := e.kubeClient.Secrets(serviceAccount.Namespace).Create(secret); err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// testGenerator mints the same token for every account.
+type testGenerator struct {
+	Token string
+}
+
+func (g *testGenerator) GenerateToken(serviceAccount api.ServiceAccount, secret api.Secret) (string, error) {
+	return g.Token, nil
+}
+
+func actionsOf(fakeClient *client.Fake, action string) []interface{} {
+	values := []interface{}{}
+	for _, a := range fakeClient.Actions {
+		if a.Action == action {
+			values = append(values, a.Value)
+		}
+	}
+	return values
+}
+
+func TestTokensControllerCreatesToken(t *testing.T) {
+	serviceAccount := newServiceAccount()
+	fakeClient := &client.Fake{
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{*serviceAccount}},
+	}
+	NewTokensController(fakeClient, &testGenerator{"ABC"}).synchronize()
+
+	created := actionsOf(fakeClient, "create-secret")
+	if len(created) != 1 {
+		t.Fatalf("expected one token to be created, got %#v", fakeClient.Actions)
+	}
+	secret := created[0].(*api.Secret)
+	if !strings.HasPrefix(secret.Name, "my-service-account-token-") || secret.Namespace != "test" {
+		t.Errorf("unexpected token name %s/%s", secret.Namespace, secret.Name)
+	}
+	if !IsServiceAccountToken(secret, serviceAccount) {
+		t.Errorf("expected a token of the service account, got %#v", secret)
+	}
+	if string(secret.Data[api.ServiceAccountTokenKey]) != "ABC" {
+		t.Errorf("unexpected token data %#v", secret.Data)
+	}
+
+	updated := actionsOf(fakeClient, "update-serviceAccount")
+	if len(updated) != 1 {
+		t.Fatalf("expected the service account to be updated, got %#v", fakeClient.Actions)
+	}
+	refs := updated[0].(*api.ServiceAccount).Secrets
+	if len(refs) != 1 || refs[0].Name != secret.Name {
+		t.Errorf("expected the service account to reference %s, got %#v", secret.Name, refs)
+	}
+}
+
+func TestTokensControllerKeepsReferencedToken(t *testing.T) {
+	serviceAccount := newServiceAccount()
+	serviceAccount.Secrets = []api.ObjectReference{{Name: "token"}}
+	fakeClient := &client.Fake{
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{*serviceAccount}},
+		SecretList:          api.SecretList{Items: []api.Secret{*newTokenSecret(serviceAccount, "token")}},
+	}
+	NewTokensController(fakeClient, &testGenerator{"ABC"}).synchronize()
+
+	for _, action := range []string{"create-secret", "delete-secret", "update-serviceAccount"} {
+		if values := actionsOf(fakeClient, action); len(values) != 0 {
+			t.Errorf("unexpected %s: %#v", action, values)
+		}
+	}
+}
+
+func TestTokensControllerReferencesExistingToken(t *testing.T) {
+	serviceAccount := newServiceAccount()
+	fakeClient := &client.Fake{
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{*serviceAccount}},
+		SecretList:          api.SecretList{Items: []api.Secret{*newTokenSecret(serviceAccount, "token")}},
+	}
+	NewTokensController(fakeClient, &testGenerator{"ABC"}).synchronize()
+
+	if created := actionsOf(fakeClient, "create-secret"); len(created) != 0 {
+		t.Errorf("expected no token to be created, got %#v", created)
+	}
+	updated := actionsOf(fakeClient, "update-serviceAccount")
+	if len(updated) != 1 {
+		t.Fatalf("expected the service account to be updated, got %#v", fakeClient.Actions)
+	}
+	if refs := updated[0].(*api.ServiceAccount).Secrets; len(refs) != 1 || refs[0].Name != "token" {
+		t.Errorf("expected the service account to reference the existing token, got %#v", refs)
+	}
+}
+
+func TestTokensControllerDeletesOrphanedTokens(t *testing.T) {
+	serviceAccount := newServiceAccount()
+	serviceAccount.Secrets = []api.ObjectReference{{Name: "old-token"}}
+	recreated := newServiceAccount()
+	recreated.UID = "67890"
+	otherAccount := newServiceAccount()
+	otherAccount.Name = "deleted"
+	opaque := newTokenSecret(otherAccount, "opaque")
+	opaque.Type = api.SecretTypeOpaque
+
+	fakeClient := &client.Fake{
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{*recreated}},
+		SecretList: api.SecretList{Items: []api.Secret{
+			*newTokenSecret(serviceAccount, "old-token"),
+			*newTokenSecret(otherAccount, "deleted-token"),
+			*opaque,
+		}},
+	}
+	NewTokensController(fakeClient, &testGenerator{"ABC"}).synchronize()
+
+	deleted := map[string]bool{}
+	for _, value := range actionsOf(fakeClient, "delete-secret") {
+		deleted[value.(string)] = true
+	}
+	if len(deleted) != 2 || !deleted["old-token"] || !deleted["deleted-token"] {
+		t.Errorf("expected the orphaned tokens to be deleted, got %v", deleted)
+	}
+	// The recreated account gets a token of its own.
+	if created := actionsOf(fakeClient, "create-secret"); len(created) != 1 {
+		t.Errorf("expected a token for the recreated account, got %#v", created)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/auth/user"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+const (
+	// ServiceAccountUsernamePrefix prefixes the user name of every service account.
+	ServiceAccountUsernamePrefix = "system:serviceaccount"
+	// ServiceAccountUsernameSeparator separates the parts of a service account user name.
+	ServiceAccountUsernameSeparator = ":"
+	// AllServiceAccountsGroup is the group every service account belongs to.
+	AllServiceAccountsGroup = "system:serviceaccounts"
+)
+
+// MakeUsername generates a username from the given namespace and ServiceAccount name.
+// The resulting username can be passed to SplitUsername to extract the original namespace and ServiceAccount name.
+func MakeUsername(namespace, name string) string {
+	return strings.Join([]string{ServiceAccountUsernamePrefix, namespace, name}, ServiceAccountUsernameSeparator)
+}
+
+// SplitUsername returns the namespace and ServiceAccount name embedded in the given username,
+// or an error if the username is not a valid name produced by MakeUsername.
+func SplitUsername(username string) (string, string, error) {
+	prefix := ServiceAccountUsernamePrefix + ServiceAccountUsernameSeparator
+	if !strings.HasPrefix(username, prefix) {
+		return "", "", fmt.Errorf("username %q is not a service account username", username)
+	}
+	parts := strings.Split(strings.TrimPrefix(username, prefix), ServiceAccountUsernameSeparator)
+	if len(parts) != 2 || len(parts[0]) == 0 || len(parts[1]) == 0 {
+		return "", "", fmt.Errorf("username %q is not a valid service account username", username)
+	}
+	return parts[0], parts[1], nil
+}
+
+// MakeGroupNames generates the groups a service account in the given namespace belongs to.
+func MakeGroupNames(namespace string) []string {
+	return []string{AllServiceAccountsGroup, AllServiceAccountsGroup + ServiceAccountUsernameSeparator + namespace}
+}
+
+// UserInfo returns the user.Info a service account authenticates as.
+func UserInfo(namespace, name, uid string) user.Info {
+	return &user.DefaultInfo{
+		Name:   MakeUsername(namespace, name),
+		UID:    uid,
+		Groups: MakeGroupNames(namespace),
+	}
+}
+
+// IsServiceAccountToken returns true if the secret is a valid api token for the service account.
+func IsServiceAccountToken(secret *api.Secret, sa *api.ServiceAccount) bool {
+	if secret.Type != api.SecretTypeServiceAccountToken {
+		return false
+	}
+	if secret.Namespace != sa.Namespace {
+		return false
+	}
+	if secret.Annotations[api.ServiceAccountNameKey] != sa.Name {
+		return false
+	}
+	if uid := secret.Annotations[api.ServiceAccountUIDKey]; len(uid) > 0 && uid != string(sa.UID) {
+		return false
+	}
+	return true
+}
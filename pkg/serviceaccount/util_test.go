@@ -0,0 +1,70 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"testing"
+
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func TestMakeSplitUsername(t *testing.T) {
+	username := MakeUsername("ns", "name")
+	namespace, name, err := SplitUsername(username)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if namespace != "ns" || name != "name" {
+		t.Errorf("expected ns/name, got %s/%s", namespace, name)
+	}
+
+	invalid := []string{"test", "system:serviceaccount", "system:serviceaccount:", "system:serviceaccount:ns", "system:serviceaccount:ns:name:extra"}
+	for _, username := range invalid {
+		if _, _, err := SplitUsername(username); err == nil {
+			t.Errorf("expected an error splitting %q", username)
+		}
+	}
+}
+
+func TestIsServiceAccountToken(t *testing.T) {
+	serviceAccount := newServiceAccount()
+
+	opaque := newTokenSecret(serviceAccount, "opaque")
+	opaque.Type = api.SecretTypeOpaque
+	otherNamespace := newTokenSecret(serviceAccount, "other-namespace")
+	otherNamespace.Namespace = "other"
+	otherAccount := newTokenSecret(serviceAccount, "other-account")
+	otherAccount.Annotations[api.ServiceAccountNameKey] = "other"
+	otherUID := newTokenSecret(serviceAccount, "other-uid")
+	otherUID.Annotations[api.ServiceAccountUIDKey] = "67890"
+	noUID := newTokenSecret(serviceAccount, "no-uid")
+	delete(noUID.Annotations, api.ServiceAccountUIDKey)
+
+	testCases := map[*api.Secret]bool{
+		newTokenSecret(serviceAccount, "token"): true,
+		noUID:                                   true,
+		opaque:                                  false,
+		otherNamespace:                          false,
+		otherAccount:                            false,
+		otherUID:                                false,
+	}
+	for secret, expected := range testCases {
+		if actual := IsServiceAccountToken(secret, serviceAccount); actual != expected {
+			t.Errorf("%s: expected %v, got %v", secret.Name, expected, actual)
+		}
+	}
+}
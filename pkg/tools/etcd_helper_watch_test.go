@@ -63,14 +63,14 @@ func TestWatchInterpretations(t *testing.T) {
 			expectEmit: false,
 		},
 		"delete": {
-			actions:       []string{"delete"},
+			actions:       []string{"delete", "expire"},
 			prevNodeValue: runtime.EncodeOrDie(codec, podBar),
 			expectEmit:    true,
 			expectType:    watch.Deleted,
 			expectObject:  podBar,
 		},
 		"delete but filter blocks": {
-			actions:    []string{"delete"},
+			actions:    []string{"delete", "expire"},
 			nodeValue:  runtime.EncodeOrDie(codec, podFoo),
 			expectEmit: false,
 		},
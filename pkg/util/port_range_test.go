@@ -0,0 +1,75 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package util
+
+import (
+	"testing"
+)
+
+func TestPortRange(t *testing.T) {
+	testCases := []struct {
+		input    string
+		success  bool
+		expected string
+		included int
+		excluded int
+	}{
+		{"100-200", true, "100-200", 200, 201},
+		{" 100-200 ", true, "100-200", 200, 201},
+		{"0-0", false, "", 0, 0},
+		{"100", false, "", 0, 0},
+		{"100 - 200", false, "", 0, 0},
+		{"-100", false, "", 0, 0},
+		{"100-", false, "", 0, 0},
+		{"200-100", false, "", 0, 0},
+		{"60000-70000", false, "", 0, 0},
+		{"70000-80000", false, "", 0, 0},
+		{"1-65535", true, "1-65535", 65535, 65536},
+		{"30000-32767", true, "30000-32767", 30000, 29999},
+	}
+
+	for i := range testCases {
+		tc := &testCases[i]
+		pr := &PortRange{}
+		var f interface{} = pr
+		if _, ok := f.(interface {
+			Set(string) error
+			String() string
+			Type() string
+		}); !ok {
+			t.Fatalf("PortRange does not implement pflag.Value")
+		}
+		err := pr.Set(tc.input)
+		if err != nil && tc.success {
+			t.Errorf("expected success, got %q", err)
+			continue
+		} else if err == nil && !tc.success {
+			t.Errorf("expected failure for %q", tc.input)
+			continue
+		} else if tc.success {
+			if pr.String() != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, pr.String())
+			}
+			if !pr.Contains(tc.included) {
+				t.Errorf("expected %q to include %d", pr.String(), tc.included)
+			}
+			if pr.Contains(tc.excluded) {
+				t.Errorf("expected %q to exclude %d", pr.String(), tc.excluded)
+			}
+		}
+	}
+}
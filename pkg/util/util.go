@@ -168,6 +168,26 @@ func (intstr IntOrString) MarshalJSON() ([]byte, error) {
 	}
 }
 
+// GetValueFromIntOrPercent returns the int value of intstr or, when intstr
+// holds a percentage such as "25%", that percentage of total, rounded up or
+// down as requested.
+func GetValueFromIntOrPercent(intstr *IntOrString, total int, roundUp bool) (int, error) {
+	if intstr.Kind == IntstrInt {
+		return intstr.IntVal, nil
+	}
+	if !strings.HasSuffix(intstr.StrVal, "%") {
+		return 0, fmt.Errorf("invalid value %q: must be an integer or a percentage", intstr.StrVal)
+	}
+	percent, err := strconv.Atoi(strings.TrimSuffix(intstr.StrVal, "%"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid percentage %q: %v", intstr.StrVal, err)
+	}
+	if roundUp {
+		return (percent*total + 99) / 100, nil
+	}
+	return percent * total / 100, nil
+}
+
 func (intstr *IntOrString) Fuzz(c fuzz.Continue) {
 	if c.RandBool() {
 		intstr.Kind = IntstrInt
@@ -111,6 +111,40 @@ func TestNewIntOrStringFromString(t *testing.T) {
 	}
 }
 
+func TestGetValueFromIntOrPercent(t *testing.T) {
+	cases := []struct {
+		input    IntOrString
+		total    int
+		roundUp  bool
+		expected int
+		err      bool
+	}{
+		{NewIntOrStringFromInt(3), 10, false, 3, false},
+		{NewIntOrStringFromString("25%"), 10, false, 2, false},
+		{NewIntOrStringFromString("25%"), 10, true, 3, false},
+		{NewIntOrStringFromString("100%"), 10, true, 10, false},
+		{NewIntOrStringFromString("0%"), 10, true, 0, false},
+		{NewIntOrStringFromString("25"), 10, false, 0, true},
+		{NewIntOrStringFromString("a%"), 10, false, 0, true},
+	}
+	for _, c := range cases {
+		value, err := GetValueFromIntOrPercent(&c.input, c.total, c.roundUp)
+		if c.err {
+			if err == nil {
+				t.Errorf("%v: expected an error", c.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: unexpected error: %v", c.input, err)
+			continue
+		}
+		if value != c.expected {
+			t.Errorf("%v: expected %d, got %d", c.input, c.expected, value)
+		}
+	}
+}
+
 type IntOrStringHolder struct {
 	IOrS IntOrString `json:"val"`
 }
@@ -65,6 +65,17 @@ func IsDNS1123Subdomain(value string) bool {
 	return len(value) <= DNS1123SubdomainMaxLength && dns1123SubdomainRegexp.MatchString(value)
 }
 
+const SecretKeyFmt string = "\\.?" + DNS1123SubdomainFmt
+
+var secretKeyRegexp = regexp.MustCompile("^" + SecretKeyFmt + "$")
+
+// IsSecretKey tests for a string that conforms to the definition of a
+// subdomain in DNS (RFC 1123), optionally prefixed by a single dot so that
+// dotfiles such as .dockercfg can be stored in a secret.
+func IsSecretKey(value string) bool {
+	return len(value) <= DNS1123SubdomainMaxLength && secretKeyRegexp.MatchString(value)
+}
+
 const DNS952LabelFmt string = "[a-z]([-a-z0-9]*[a-z0-9])?"
 const DNS952LabelMaxLength int = 24
 
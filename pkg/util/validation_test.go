@@ -86,6 +86,28 @@ func TestIsDNS1123Subdomain(t *testing.T) {
 	}
 }
 
+func TestIsSecretKey(t *testing.T) {
+	goodValues := []string{
+		"a", "ab", "a-1", "a.b", "1.2.3", ".a", ".dockercfg", ".a.b",
+		strings.Repeat("a", 253),
+	}
+	for _, val := range goodValues {
+		if !IsSecretKey(val) {
+			t.Errorf("expected true for '%s'", val)
+		}
+	}
+
+	badValues := []string{
+		"", ".", "..a", "a.", "-a", "A", ".A", "a_b", "a/b", "a b",
+		strings.Repeat("a", 254),
+	}
+	for _, val := range badValues {
+		if IsSecretKey(val) {
+			t.Errorf("expected false for '%s'", val)
+		}
+	}
+}
+
 func TestIsDNS952Label(t *testing.T) {
 	goodValues := []string{
 		"a", "ab", "abc", "a1", "a-1", "a--1--2--b",
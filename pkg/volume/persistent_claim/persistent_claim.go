@@ -0,0 +1,97 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistent_claim
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/types"
+	"github.com/golang/glog"
+)
+
+// This is the primary entrypoint for volume plugins.
+func ProbeVolumePlugins() []volume.VolumePlugin {
+	return []volume.VolumePlugin{&persistentClaimPlugin{nil}}
+}
+
+// persistentClaimPlugin mounts the persistent volume bound to a claim by
+// delegating to the plugin that supports the volume's source.
+type persistentClaimPlugin struct {
+	host volume.VolumeHost
+}
+
+var _ volume.VolumePlugin = &persistentClaimPlugin{}
+
+const (
+	persistentClaimPluginName = "kubernetes.io/persistent-claim"
+)
+
+func (plugin *persistentClaimPlugin) Init(host volume.VolumeHost) {
+	plugin.host = host
+}
+
+func (plugin *persistentClaimPlugin) Name() string {
+	return persistentClaimPluginName
+}
+
+func (plugin *persistentClaimPlugin) CanSupport(spec *api.Volume) bool {
+	return spec.PersistentVolumeClaim != nil
+}
+
+func (plugin *persistentClaimPlugin) NewBuilder(spec *api.Volume, podRef *api.ObjectReference) (volume.Builder, error) {
+	source := spec.PersistentVolumeClaim
+	kubeClient := plugin.host.GetKubeClient()
+	if kubeClient == nil {
+		return nil, fmt.Errorf("cannot look up claim %s/%s without a kube client", podRef.Namespace, source.ClaimName)
+	}
+
+	claim, err := kubeClient.PersistentVolumeClaims(podRef.Namespace).Get(source.ClaimName)
+	if err != nil {
+		glog.Errorf("Error finding claim %s/%s: %v", podRef.Namespace, source.ClaimName, err)
+		return nil, err
+	}
+	if claim.Status.Phase != api.ClaimBound || claim.Status.VolumeRef == nil {
+		return nil, fmt.Errorf("claim %s/%s is not bound to a volume", claim.Namespace, claim.Name)
+	}
+
+	pv, err := kubeClient.PersistentVolumes().Get(claim.Status.VolumeRef.Name)
+	if err != nil {
+		glog.Errorf("Error finding volume %s bound to claim %s/%s: %v", claim.Status.VolumeRef.Name, claim.Namespace, claim.Name, err)
+		return nil, err
+	}
+	ref := pv.Spec.ClaimRef
+	if ref == nil || ref.Namespace != claim.Namespace || ref.Name != claim.Name || ref.UID != claim.UID {
+		return nil, fmt.Errorf("volume %s is not bound to claim %s/%s", pv.Name, claim.Namespace, claim.Name)
+	}
+
+	wrapped := volume.NewSpecFromPersistentVolume(pv, spec.Name)
+	if source.ReadOnly && wrapped.GCEPersistentDisk != nil {
+		disk := *wrapped.GCEPersistentDisk
+		disk.ReadOnly = true
+		wrapped.GCEPersistentDisk = &disk
+	}
+	return plugin.host.NewWrapperBuilder(wrapped, podRef)
+}
+
+// NewCleaner is never expected to succeed: the builder of a claim is the
+// builder of its volume's plugin, so that plugin owns the volume on disk
+// and will be asked to clean it up.
+func (plugin *persistentClaimPlugin) NewCleaner(volName string, podUID types.UID) (volume.Cleaner, error) {
+	return nil, fmt.Errorf("volume %s of pod %s is cleaned up by the plugin of its persistent volume", volName, podUID)
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package persistent_claim
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume/host_path"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/types"
+)
+
+func newTestHost(t *testing.T, client client.Interface) (volume.VolumeHost, string) {
+	tempDir, err := ioutil.TempDir("/tmp", "persistent_claim_test.")
+	if err != nil {
+		t.Fatalf("can't make a temp rootdir: %v", err)
+	}
+	plugins := append(ProbeVolumePlugins(), host_path.ProbeVolumePlugins()...)
+	return volume.NewFakeVolumeHost(tempDir, client, plugins), tempDir
+}
+
+func TestCanSupport(t *testing.T) {
+	host, tempDir := newTestHost(t, nil)
+	defer os.RemoveAll(tempDir)
+	pluginMgr := volume.VolumePluginMgr{}
+	pluginMgr.InitPlugins(ProbeVolumePlugins(), host)
+
+	plugin, err := pluginMgr.FindPluginByName(persistentClaimPluginName)
+	if err != nil {
+		t.Fatalf("Can't find the plugin by name")
+	}
+	if plugin.Name() != persistentClaimPluginName {
+		t.Errorf("Wrong name: %s", plugin.Name())
+	}
+	if !plugin.CanSupport(&api.Volume{VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: "foo"}}}) {
+		t.Errorf("Expected true")
+	}
+	if plugin.CanSupport(&api.Volume{VolumeSource: api.VolumeSource{HostPath: &api.HostPathVolumeSource{Path: "/foo"}}}) {
+		t.Errorf("Expected false")
+	}
+}
+
+func newBoundClaimAndVolume() (*api.PersistentVolumeClaim, *api.PersistentVolume) {
+	claim := api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: "claim", Namespace: "ns", UID: "claim-uid"},
+		Status: api.PersistentVolumeClaimStatus{
+			Phase:     api.ClaimBound,
+			VolumeRef: &api.ObjectReference{Name: "volume"},
+		},
+	}
+	pv := api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: "volume"},
+		Spec: api.PersistentVolumeSpec{
+			PersistentVolumeSource: api.PersistentVolumeSource{
+				HostPath: &api.HostPathVolumeSource{Path: "/data"},
+			},
+			ClaimRef: &api.ObjectReference{Name: "claim", Namespace: "ns", UID: "claim-uid"},
+		},
+	}
+	return &claim, &pv
+}
+
+func TestNewBuilder(t *testing.T) {
+	tests := []struct {
+		name        string
+		mutate      func(*api.PersistentVolumeClaim, *api.PersistentVolume)
+		expectError bool
+	}{
+		{
+			name:   "bound claim",
+			mutate: func(*api.PersistentVolumeClaim, *api.PersistentVolume) {},
+		},
+		{
+			name: "pending claim",
+			mutate: func(claim *api.PersistentVolumeClaim, _ *api.PersistentVolume) {
+				claim.Status = api.PersistentVolumeClaimStatus{Phase: api.ClaimPending}
+			},
+			expectError: true,
+		},
+		{
+			name: "volume bound to another claim",
+			mutate: func(_ *api.PersistentVolumeClaim, pv *api.PersistentVolume) {
+				pv.Spec.ClaimRef.UID = "other-uid"
+			},
+			expectError: true,
+		},
+		{
+			name: "missing volume",
+			mutate: func(claim *api.PersistentVolumeClaim, _ *api.PersistentVolume) {
+				claim.Status.VolumeRef.Name = "missing"
+			},
+			expectError: true,
+		},
+	}
+
+	for _, test := range tests {
+		claim, pv := newBoundClaimAndVolume()
+		test.mutate(claim, pv)
+		fakeClient := &client.Fake{
+			PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{*claim}},
+			PersistentVolumesList:      api.PersistentVolumeList{Items: []api.PersistentVolume{*pv}},
+		}
+		host, tempDir := newTestHost(t, fakeClient)
+		pluginMgr := volume.VolumePluginMgr{}
+		pluginMgr.InitPlugins(ProbeVolumePlugins(), host)
+		plugin, err := pluginMgr.FindPluginByName(persistentClaimPluginName)
+		if err != nil {
+			t.Fatalf("Can't find the plugin by name")
+		}
+
+		spec := &api.Volume{
+			Name:         "vol",
+			VolumeSource: api.VolumeSource{PersistentVolumeClaim: &api.PersistentVolumeClaimVolumeSource{ClaimName: "claim"}},
+		}
+		builder, err := plugin.NewBuilder(spec, &api.ObjectReference{Namespace: "ns", UID: types.UID("pod-uid")})
+		os.RemoveAll(tempDir)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("%s: expected an error", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if builder.GetPath() != "/data" {
+			t.Errorf("%s: expected the host path of the bound volume, got %s", test.name, builder.GetPath())
+		}
+	}
+}
@@ -194,3 +194,29 @@ func (pm *VolumePluginMgr) FindPersistentPluginByName(name string) (PersistentVo
 	}
 	return nil, fmt.Errorf("no persistent volume plugin matched")
 }
+
+// FindPersistentPluginBySpec looks for a persistent volume plugin that can
+// support a given volume specification.  If no plugin or more than one
+// plugin can support it, return error.
+func (pm *VolumePluginMgr) FindPersistentPluginBySpec(spec *api.Volume) (PersistentVolumePlugin, error) {
+	volumePlugin, err := pm.FindPluginBySpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	if persistentVolumePlugin, ok := volumePlugin.(PersistentVolumePlugin); ok {
+		return persistentVolumePlugin, nil
+	}
+	return nil, fmt.Errorf("no persistent volume plugin matched")
+}
+
+// NewSpecFromPersistentVolume returns a pod volume spec with the given name
+// which mounts the storage backing a persistent volume.
+func NewSpecFromPersistentVolume(pv *api.PersistentVolume, name string) *api.Volume {
+	return &api.Volume{
+		Name: name,
+		VolumeSource: api.VolumeSource{
+			GCEPersistentDisk: pv.Spec.GCEPersistentDisk,
+			HostPath:          pv.Spec.HostPath,
+		},
+	}
+}
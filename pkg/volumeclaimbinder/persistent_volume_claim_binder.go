@@ -0,0 +1,577 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaimbinder
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/fields"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/watch"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/golang/glog"
+)
+
+// PersistentVolumeClaimBinder is responsible for binding the pending
+// PersistentVolumeClaims stored in the system to available PersistentVolumes,
+// and for releasing, and if asked to, recycling volumes whose claims are gone.
+type PersistentVolumeClaimBinder struct {
+	kubeClient client.Interface
+	// pluginMgr knows the access modes supported by each kind of volume.
+	pluginMgr volume.VolumePluginMgr
+	syncTime  <-chan time.Time
+
+	// lock serializes the syncs from the watches and the sync loop, so that
+	// a volume is never handed out twice.
+	lock sync.Mutex
+}
+
+// Time period of main binder sync loop
+const DefaultSyncPeriod = 10 * time.Second
+
+const (
+	// RecyclerNamespace is the namespace the pods scrubbing recycled volumes run in.
+	RecyclerNamespace = api.NamespaceDefault
+	// RecyclerImage is the image of the pods scrubbing recycled volumes.
+	RecyclerImage = "busybox"
+	// recyclerPodPrefix prefixes the name of the volume in the name of its recycler pod.
+	recyclerPodPrefix = "pv-recycler-"
+	// recyclerMountPath is where the volume is mounted in the recycler pod.
+	recyclerMountPath = "/scrub"
+)
+
+// NewPersistentVolumeClaimBinder creates a new PersistentVolumeClaimBinder.
+// plugins are the persistent volume plugins used to find out the access
+// modes a volume supports.
+func NewPersistentVolumeClaimBinder(kubeClient client.Interface, plugins []volume.VolumePlugin) *PersistentVolumeClaimBinder {
+	binder := &PersistentVolumeClaimBinder{
+		kubeClient: kubeClient,
+	}
+	// The binder never builds volumes, so the plugins get no host.
+	if err := binder.pluginMgr.InitPlugins(plugins, nil); err != nil {
+		util.HandleError(fmt.Errorf("unable to initialize volume plugins: %v", err))
+	}
+	return binder
+}
+
+// Run begins watching and syncing.
+func (b *PersistentVolumeClaimBinder) Run(period time.Duration) {
+	b.syncTime = time.Tick(period)
+	resourceVersion := ""
+	go util.Forever(func() { b.watchClaims(&resourceVersion) }, period)
+	volumeResourceVersion := ""
+	go util.Forever(func() { b.watchVolumes(&volumeResourceVersion) }, period)
+}
+
+// watchClaims syncs a claim whenever it changes, and resyncs everything periodically.
+// resourceVersion is a pointer to the resource version to use/update.
+func (b *PersistentVolumeClaimBinder) watchClaims(resourceVersion *string) {
+	watching, err := b.kubeClient.PersistentVolumeClaims(api.NamespaceAll).Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch claims: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		select {
+		case <-b.syncTime:
+			b.synchronize()
+		case event, open := <-watching.ResultChan():
+			if !open {
+				// watchChannel has been closed, or something else went
+				// wrong with our watch call. Let the util.Forever()
+				// that called us call us again.
+				return
+			}
+			if event.Type == watch.Error {
+				util.HandleError(fmt.Errorf("error from claim watch: %v", errors.FromObject(event.Object)))
+				// Clear the resource version, this may cause us to skip some elements on the watch,
+				// but we'll catch them on the synchronize() call, so it works out.
+				*resourceVersion = ""
+				continue
+			}
+			claim, ok := event.Object.(*api.PersistentVolumeClaim)
+			if !ok {
+				util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+				continue
+			}
+			// If we get disconnected, start where we left off.
+			*resourceVersion = claim.ResourceVersion
+			glog.V(4).Infof("Claim %s/%s %s, syncing", claim.Namespace, claim.Name, event.Type)
+			b.syncClaimEvent(claim)
+		}
+	}
+}
+
+// watchVolumes syncs a volume whenever it is added, changed or removed.
+// resourceVersion is a pointer to the resource version to use/update.
+func (b *PersistentVolumeClaimBinder) watchVolumes(resourceVersion *string) {
+	watching, err := b.kubeClient.PersistentVolumes().Watch(
+		labels.Everything(),
+		fields.Everything(),
+		*resourceVersion,
+	)
+	if err != nil {
+		util.HandleError(fmt.Errorf("unable to watch volumes: %v", err))
+		time.Sleep(5 * time.Second)
+		return
+	}
+
+	for {
+		event, open := <-watching.ResultChan()
+		if !open {
+			return
+		}
+		if event.Type == watch.Error {
+			util.HandleError(fmt.Errorf("error from volume watch: %v", errors.FromObject(event.Object)))
+			*resourceVersion = ""
+			continue
+		}
+		pv, ok := event.Object.(*api.PersistentVolume)
+		if !ok {
+			util.HandleError(fmt.Errorf("unexpected object: %#v", event.Object))
+			continue
+		}
+		*resourceVersion = pv.ResourceVersion
+		glog.V(4).Infof("Volume %s %s, syncing", pv.Name, event.Type)
+		b.syncVolumeEvent(pv)
+	}
+}
+
+// syncClaimEvent syncs the claim a watch event is about, then the volume the
+// claim was bound to, which is released when the claim is gone.  The volumes
+// the claim could be bound to are only listed when it is not bound yet.
+func (b *PersistentVolumeClaimBinder) syncClaimEvent(claim *api.PersistentVolumeClaim) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.syncClaimNamed(claim.Namespace, claim.Name); err != nil {
+		util.HandleError(fmt.Errorf("error syncing claim %s/%s: %v", claim.Namespace, claim.Name, err))
+	}
+	if ref := claim.Status.VolumeRef; ref != nil {
+		if err := b.syncVolumeNamed(ref.Name); err != nil {
+			util.HandleError(fmt.Errorf("error syncing volume %s: %v", ref.Name, err))
+		}
+	}
+}
+
+// syncVolumeEvent syncs the volume a watch event is about, then the claim the
+// volume was bound to, which is lost when the volume is gone.  A volume made
+// available is handed out by the next periodic resync.
+func (b *PersistentVolumeClaimBinder) syncVolumeEvent(pv *api.PersistentVolume) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.syncVolumeNamed(pv.Name); err != nil {
+		util.HandleError(fmt.Errorf("error syncing volume %s: %v", pv.Name, err))
+	}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		if err := b.syncClaimNamed(ref.Namespace, ref.Name); err != nil {
+			util.HandleError(fmt.Errorf("error syncing claim %s/%s: %v", ref.Namespace, ref.Name, err))
+		}
+	}
+}
+
+// syncClaimNamed syncs the latest version of the claim, if it still exists,
+// against the volume it is bound to, or against all the volumes when it is
+// not bound.
+func (b *PersistentVolumeClaimBinder) syncClaimNamed(namespace, name string) error {
+	claim, err := b.kubeClient.PersistentVolumeClaims(namespace).Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	volumes := []*api.PersistentVolume{}
+	switch claim.Status.Phase {
+	case api.ClaimLost:
+	case api.ClaimBound:
+		if ref := claim.Status.VolumeRef; ref != nil {
+			pv, err := b.kubeClient.PersistentVolumes().Get(ref.Name)
+			if err != nil && !errors.IsNotFound(err) {
+				return err
+			}
+			if err == nil {
+				volumes = append(volumes, pv)
+			}
+		}
+	default:
+		if volumes, err = b.listVolumes(); err != nil {
+			return err
+		}
+	}
+	return b.syncClaim(claim, volumes)
+}
+
+// syncVolumeNamed syncs the latest version of the volume, if it still exists,
+// against the claim it is bound to.
+func (b *PersistentVolumeClaimBinder) syncVolumeNamed(name string) error {
+	pv, err := b.kubeClient.PersistentVolumes().Get(name)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	claims := map[string]*api.PersistentVolumeClaim{}
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		claim, err := b.kubeClient.PersistentVolumeClaims(ref.Namespace).Get(ref.Name)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil {
+			claims[claimKey(claim.Namespace, claim.Name)] = claim
+		}
+	}
+	return b.syncVolume(pv, claims)
+}
+
+// listVolumes returns all the volumes, sorted by increasing capacity.
+func (b *PersistentVolumeClaimBinder) listVolumes() ([]*api.PersistentVolume, error) {
+	volumeList, err := b.kubeClient.PersistentVolumes().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	volumes := make([]*api.PersistentVolume, len(volumeList.Items))
+	for i := range volumeList.Items {
+		volumes[i] = &volumeList.Items[i]
+	}
+	sort.Sort(byCapacity(volumes))
+	return volumes, nil
+}
+
+// synchronize brings every volume to the phase matching its claim, then
+// binds every pending claim it can to an available volume.
+func (b *PersistentVolumeClaimBinder) synchronize() {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// Volumes are listed before claims, so that any claim a volume refers
+	// to and which is not listed has really been deleted.
+	volumes, err := b.listVolumes()
+	if err != nil {
+		util.HandleError(fmt.Errorf("synchronization error: %v", err))
+		return
+	}
+	claimList, err := b.kubeClient.PersistentVolumeClaims(api.NamespaceAll).List(labels.Everything())
+	if err != nil {
+		util.HandleError(fmt.Errorf("synchronization error: %v", err))
+		return
+	}
+
+	claims := map[string]*api.PersistentVolumeClaim{}
+	for i := range claimList.Items {
+		claim := &claimList.Items[i]
+		claims[claimKey(claim.Namespace, claim.Name)] = claim
+	}
+	for _, pv := range volumes {
+		if err := b.syncVolume(pv, claims); err != nil {
+			util.HandleError(fmt.Errorf("error syncing volume %s: %v", pv.Name, err))
+		}
+	}
+
+	// The oldest claims get the first pick of the volumes.
+	sort.Sort(byCreationTimestamp(claimList.Items))
+	for i := range claimList.Items {
+		claim := &claimList.Items[i]
+		if err := b.syncClaim(claim, volumes); err != nil {
+			util.HandleError(fmt.Errorf("error syncing claim %s/%s: %v", claim.Namespace, claim.Name, err))
+		}
+	}
+}
+
+// syncVolume moves a volume to the Available phase when it has no claim, to
+// the Bound phase when its claim exists, and to the Released phase when its
+// claim has been deleted.  Released volumes with the Recycle policy are
+// scrubbed and made Available again.
+func (b *PersistentVolumeClaimBinder) syncVolume(pv *api.PersistentVolume, claims map[string]*api.PersistentVolumeClaim) error {
+	nextPhase := api.VolumeAvailable
+	if ref := pv.Spec.ClaimRef; ref != nil {
+		claim, found := claims[claimKey(ref.Namespace, ref.Name)]
+		if found && claim.UID == ref.UID {
+			nextPhase = api.VolumeBound
+		} else {
+			nextPhase = api.VolumeReleased
+		}
+	}
+
+	if nextPhase == api.VolumeReleased && pv.Status.Phase == api.VolumeReleased &&
+		pv.Spec.PersistentVolumeReclaimPolicy == api.PersistentVolumeReclaimRecycle {
+		recycled, err := b.recycle(pv)
+		if err != nil || !recycled {
+			return err
+		}
+		nextPhase = api.VolumeAvailable
+	}
+
+	if pv.Status.Phase == nextPhase {
+		return nil
+	}
+	glog.V(2).Infof("Volume %s moves from phase %q to %q", pv.Name, pv.Status.Phase, nextPhase)
+	return b.updateVolumePhase(pv, nextPhase)
+}
+
+// recycle scrubs a released volume by running a pod which removes its
+// contents.  Once the pod has succeeded, the volume is unbound from its
+// claim and recycle returns true.  A failed recycler pod is left in place
+// for inspection; deleting it retries the recycling.
+func (b *PersistentVolumeClaimBinder) recycle(pv *api.PersistentVolume) (bool, error) {
+	podName := recyclerPodPrefix + pv.Name
+	pod, err := b.kubeClient.Pods(RecyclerNamespace).Get(podName)
+	if errors.IsNotFound(err) {
+		glog.V(2).Infof("Recycling volume %s released by claim %s/%s", pv.Name, pv.Spec.ClaimRef.Namespace, pv.Spec.ClaimRef.Name)
+		_, err := b.kubeClient.Pods(RecyclerNamespace).Create(newRecyclerPod(pv, podName))
+		return false, err
+	}
+	if err != nil {
+		return false, err
+	}
+
+	switch pod.Status.Phase {
+	case api.PodSucceeded:
+		pv.Spec.ClaimRef = nil
+		updated, err := b.kubeClient.PersistentVolumes().Update(pv)
+		if err != nil {
+			return false, err
+		}
+		*pv = *updated
+		if err := b.kubeClient.Pods(RecyclerNamespace).Delete(podName, nil); err != nil {
+			util.HandleError(fmt.Errorf("unable to delete recycler pod %s: %v", podName, err))
+		}
+		return true, nil
+	case api.PodFailed:
+		return false, fmt.Errorf("recycler pod %s/%s failed, delete it to retry", RecyclerNamespace, podName)
+	}
+	return false, nil
+}
+
+// newRecyclerPod returns a pod which removes everything stored in the volume.
+func newRecyclerPod(pv *api.PersistentVolume, podName string) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{
+			Name:      podName,
+			Namespace: RecyclerNamespace,
+		},
+		Spec: api.PodSpec{
+			Volumes: []api.Volume{*volume.NewSpecFromPersistentVolume(pv, "vol")},
+			Containers: []api.Container{
+				{
+					Name:    "scrubber",
+					Image:   RecyclerImage,
+					Command: []string{"/bin/sh", "-c", "test -e /scrub && rm -rf /scrub/..?* /scrub/.[!.]* /scrub/* && test -z \"$(ls -A /scrub)\" || exit 1"},
+					VolumeMounts: []api.VolumeMount{
+						{
+							Name:      "vol",
+							MountPath: recyclerMountPath,
+						},
+					},
+				},
+			},
+			RestartPolicy: api.RestartPolicyNever,
+		},
+	}
+}
+
+// syncClaim binds a pending claim to the smallest available volume which
+// supports all the access modes and holds the storage requested by the claim.
+// A bound claim whose volume was deleted or bound to another claim is lost.
+func (b *PersistentVolumeClaimBinder) syncClaim(claim *api.PersistentVolumeClaim, volumes []*api.PersistentVolume) error {
+	switch claim.Status.Phase {
+	case api.ClaimLost:
+		return nil
+	case api.ClaimBound:
+		if isBoundToClaim(claim, volumes) {
+			return nil
+		}
+		glog.V(2).Infof("Claim %s/%s lost its volume", claim.Namespace, claim.Name)
+		claim.Status.Phase = api.ClaimLost
+		_, err := b.kubeClient.PersistentVolumeClaims(claim.Namespace).UpdateStatus(claim)
+		return err
+	}
+
+	// A previous pass may have bound a volume to the claim without updating the claim.
+	var match *api.PersistentVolume
+	for _, pv := range volumes {
+		if ref := pv.Spec.ClaimRef; ref != nil && ref.Namespace == claim.Namespace && ref.Name == claim.Name && ref.UID == claim.UID {
+			match = pv
+			break
+		}
+	}
+	if match == nil {
+		match = b.findMatchingVolume(claim, volumes)
+		if match == nil {
+			if claim.Status.Phase == api.ClaimPending {
+				return nil
+			}
+			claim.Status = api.PersistentVolumeClaimStatus{Phase: api.ClaimPending}
+			_, err := b.kubeClient.PersistentVolumeClaims(claim.Namespace).UpdateStatus(claim)
+			return err
+		}
+
+		glog.V(2).Infof("Binding claim %s/%s to volume %s", claim.Namespace, claim.Name, match.Name)
+		match.Spec.ClaimRef = &api.ObjectReference{
+			Kind:      "PersistentVolumeClaim",
+			Namespace: claim.Namespace,
+			Name:      claim.Name,
+			UID:       claim.UID,
+		}
+		updated, err := b.kubeClient.PersistentVolumes().Update(match)
+		if err != nil {
+			// The claim reference is kept in memory, so that a volume which may
+			// or may not have been bound is not handed out again in this pass.
+			return err
+		}
+		*match = *updated
+	}
+	if match.Status.Phase != api.VolumeBound {
+		if err := b.updateVolumePhase(match, api.VolumeBound); err != nil {
+			return err
+		}
+	}
+
+	claim.Status = api.PersistentVolumeClaimStatus{
+		Phase:       api.ClaimBound,
+		AccessModes: b.accessModes(match),
+		Capacity:    match.Spec.Capacity,
+		VolumeRef: &api.ObjectReference{
+			Kind: "PersistentVolume",
+			Name: match.Name,
+			UID:  match.UID,
+		},
+	}
+	_, err := b.kubeClient.PersistentVolumeClaims(claim.Namespace).UpdateStatus(claim)
+	return err
+}
+
+// isBoundToClaim returns true if the volume the claim is bound to still exists
+// and still refers to the claim.
+func isBoundToClaim(claim *api.PersistentVolumeClaim, volumes []*api.PersistentVolume) bool {
+	if claim.Status.VolumeRef == nil {
+		return false
+	}
+	for _, pv := range volumes {
+		if pv.Name != claim.Status.VolumeRef.Name || pv.UID != claim.Status.VolumeRef.UID {
+			continue
+		}
+		ref := pv.Spec.ClaimRef
+		return ref != nil && ref.Namespace == claim.Namespace && ref.Name == claim.Name && ref.UID == claim.UID
+	}
+	return false
+}
+
+// findMatchingVolume returns the smallest available volume which satisfies the
+// claim, or nil.  volumes must be sorted by increasing capacity.
+func (b *PersistentVolumeClaimBinder) findMatchingVolume(claim *api.PersistentVolumeClaim, volumes []*api.PersistentVolume) *api.PersistentVolume {
+	requested := claim.Spec.Resources.Requests[api.ResourceStorage]
+	for _, pv := range volumes {
+		if pv.Status.Phase != api.VolumeAvailable || pv.Spec.ClaimRef != nil {
+			continue
+		}
+		capacity := pv.Spec.Capacity[api.ResourceStorage]
+		if capacity.Value() < requested.Value() {
+			continue
+		}
+		if !containsAllModes(b.accessModes(pv), claim.Spec.AccessModes) {
+			continue
+		}
+		return pv
+	}
+	return nil
+}
+
+// accessModes returns the ways the volume can be mounted, as reported by the
+// plugin supporting its source.
+func (b *PersistentVolumeClaimBinder) accessModes(pv *api.PersistentVolume) []api.AccessModeType {
+	plugin, err := b.pluginMgr.FindPersistentPluginBySpec(volume.NewSpecFromPersistentVolume(pv, pv.Name))
+	if err != nil {
+		glog.V(4).Infof("No persistent volume plugin supports volume %s: %v", pv.Name, err)
+		return nil
+	}
+	return plugin.GetAccessModes()
+}
+
+// updateVolumePhase records the phase in the status of the volume.
+func (b *PersistentVolumeClaimBinder) updateVolumePhase(pv *api.PersistentVolume, phase api.PersistentVolumePhase) error {
+	pv.Status.Phase = phase
+	updated, err := b.kubeClient.PersistentVolumes().UpdateStatus(pv)
+	if err != nil {
+		return err
+	}
+	*pv = *updated
+	return nil
+}
+
+func containsAllModes(modes, requested []api.AccessModeType) bool {
+	for _, r := range requested {
+		found := false
+		for _, m := range modes {
+			if m == r {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func claimKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+// byCapacity sorts volumes by increasing storage capacity, then by name.
+type byCapacity []*api.PersistentVolume
+
+func (v byCapacity) Len() int      { return len(v) }
+func (v byCapacity) Swap(i, j int) { v[i], v[j] = v[j], v[i] }
+func (v byCapacity) Less(i, j int) bool {
+	ci := v[i].Spec.Capacity[api.ResourceStorage]
+	cj := v[j].Spec.Capacity[api.ResourceStorage]
+	if ci.Value() != cj.Value() {
+		return ci.Value() < cj.Value()
+	}
+	return v[i].Name < v[j].Name
+}
+
+// byCreationTimestamp sorts claims from the oldest to the newest.
+type byCreationTimestamp []api.PersistentVolumeClaim
+
+func (c byCreationTimestamp) Len() int      { return len(c) }
+func (c byCreationTimestamp) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c byCreationTimestamp) Less(i, j int) bool {
+	if c[i].CreationTimestamp.Equal(c[j].CreationTimestamp.Time) {
+		return claimKey(c[i].Namespace, c[i].Name) < claimKey(c[j].Namespace, c[j].Name)
+	}
+	return c[i].CreationTimestamp.Before(c[j].CreationTimestamp)
+}
@@ -0,0 +1,550 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package volumeclaimbinder
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/resource"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume/gce_pd"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/volume/host_path"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/types"
+)
+
+func newBinder(kubeClient client.Interface) *PersistentVolumeClaimBinder {
+	plugins := []volume.VolumePlugin{}
+	plugins = append(plugins, gce_pd.ProbeVolumePlugins()...)
+	plugins = append(plugins, host_path.ProbeVolumePlugins()...)
+	return NewPersistentVolumeClaimBinder(kubeClient, plugins)
+}
+
+func newHostPathVolume(name, capacity string, phase api.PersistentVolumePhase) api.PersistentVolume {
+	return api.PersistentVolume{
+		ObjectMeta: api.ObjectMeta{Name: name, UID: types.UID(name + "-uid")},
+		Spec: api.PersistentVolumeSpec{
+			Capacity: api.ResourceList{api.ResourceStorage: resource.MustParse(capacity)},
+			PersistentVolumeSource: api.PersistentVolumeSource{
+				HostPath: &api.HostPathVolumeSource{Path: "/" + name},
+			},
+			PersistentVolumeReclaimPolicy: api.PersistentVolumeReclaimRetain,
+		},
+		Status: api.PersistentVolumeStatus{Phase: phase},
+	}
+}
+
+func newGCEVolume(name, capacity string, phase api.PersistentVolumePhase) api.PersistentVolume {
+	pv := newHostPathVolume(name, capacity, phase)
+	pv.Spec.HostPath = nil
+	pv.Spec.GCEPersistentDisk = &api.GCEPersistentDiskVolumeSource{PDName: name, FSType: "ext4"}
+	return pv
+}
+
+func newClaim(name, request string, modes ...api.AccessModeType) api.PersistentVolumeClaim {
+	return api.PersistentVolumeClaim{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: "ns", UID: types.UID(name + "-uid")},
+		Spec: api.PersistentVolumeClaimSpec{
+			AccessModes: modes,
+			Resources: api.ResourceRequirements{
+				Requests: api.ResourceList{api.ResourceStorage: resource.MustParse(request)},
+			},
+		},
+		Status: api.PersistentVolumeClaimStatus{Phase: api.ClaimPending},
+	}
+}
+
+func claimRef(claim *api.PersistentVolumeClaim) *api.ObjectReference {
+	return &api.ObjectReference{
+		Kind:      "PersistentVolumeClaim",
+		Namespace: claim.Namespace,
+		Name:      claim.Name,
+		UID:       claim.UID,
+	}
+}
+
+// actionsOf returns the values recorded for the given action, in order.
+func actionsOf(fakeClient *client.Fake, action string) []interface{} {
+	values := []interface{}{}
+	for _, a := range fakeClient.Actions {
+		if a.Action == action {
+			values = append(values, a.Value)
+		}
+	}
+	return values
+}
+
+func TestBindsClaimToSmallestMatchingVolume(t *testing.T) {
+	claim := newClaim("claim", "8G", api.ReadWriteOnce, api.ReadOnlyMany)
+	fakeClient := &client.Fake{
+		PersistentVolumesList: api.PersistentVolumeList{Items: []api.PersistentVolume{
+			newGCEVolume("gce-big", "20G", api.VolumeAvailable),
+			newHostPathVolume("host", "10G", api.VolumeAvailable),
+			newGCEVolume("gce-small", "5G", api.VolumeAvailable),
+			newGCEVolume("gce", "10G", api.VolumeAvailable),
+		}},
+		PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{claim}},
+	}
+	newBinder(fakeClient).synchronize()
+
+	updates := actionsOf(fakeClient, "update-persistentVolume")
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 volume update, got %#v", fakeClient.Actions)
+	}
+	pv := updates[0].(*api.PersistentVolume)
+	if pv.Name != "gce" {
+		t.Errorf("expected the claim to be bound to volume gce, got %s", pv.Name)
+	}
+	if !api.Semantic.DeepEqual(pv.Spec.ClaimRef, claimRef(&claim)) {
+		t.Errorf("unexpected claim reference %#v", pv.Spec.ClaimRef)
+	}
+
+	statuses := actionsOf(fakeClient, "update-status-persistentVolume")
+	if len(statuses) != 1 || statuses[0].(*api.PersistentVolume).Status.Phase != api.VolumeBound {
+		t.Errorf("expected the volume to be marked bound, got %#v", statuses)
+	}
+
+	claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim")
+	if len(claims) != 1 {
+		t.Fatalf("expected 1 claim status update, got %#v", fakeClient.Actions)
+	}
+	status := claims[0].(*api.PersistentVolumeClaim).Status
+	if status.Phase != api.ClaimBound || status.VolumeRef == nil || status.VolumeRef.Name != "gce" {
+		t.Errorf("unexpected claim status %#v", status)
+	}
+	if len(status.AccessModes) != 2 {
+		t.Errorf("expected the access modes of a GCE disk, got %v", status.AccessModes)
+	}
+	capacity := status.Capacity[api.ResourceStorage]
+	if capacity.Value() != 10000000000 {
+		t.Errorf("unexpected claim capacity %v", capacity)
+	}
+}
+
+func TestBindsOldestClaimFirst(t *testing.T) {
+	older := newClaim("older", "1G", api.ReadWriteOnce)
+	older.CreationTimestamp = util.Now()
+	newer := newClaim("newer", "1G", api.ReadWriteOnce)
+	newer.CreationTimestamp = util.Unix(older.CreationTimestamp.Unix()+60, 0)
+	fakeClient := &client.Fake{
+		PersistentVolumesList: api.PersistentVolumeList{Items: []api.PersistentVolume{
+			newHostPathVolume("host", "10G", api.VolumeAvailable),
+		}},
+		PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{newer, older}},
+	}
+	newBinder(fakeClient).synchronize()
+
+	updates := actionsOf(fakeClient, "update-persistentVolume")
+	if len(updates) != 1 || updates[0].(*api.PersistentVolume).Spec.ClaimRef.Name != "older" {
+		t.Fatalf("expected the volume to be bound to the older claim only, got %#v", fakeClient.Actions)
+	}
+	claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim")
+	if len(claims) != 1 || claims[0].(*api.PersistentVolumeClaim).Name != "older" {
+		t.Errorf("expected only the older claim to be bound, got %#v", claims)
+	}
+}
+
+func TestClaimStaysPendingWithoutMatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		volume api.PersistentVolume
+		claim  api.PersistentVolumeClaim
+	}{
+		{
+			name:   "too small",
+			volume: newGCEVolume("gce", "5G", api.VolumeAvailable),
+			claim:  newClaim("claim", "10G", api.ReadWriteOnce),
+		},
+		{
+			name:   "unsupported access mode",
+			volume: newHostPathVolume("host", "10G", api.VolumeAvailable),
+			claim:  newClaim("claim", "1G", api.ReadOnlyMany),
+		},
+		{
+			name:   "volume released",
+			volume: newHostPathVolume("host", "10G", api.VolumeReleased),
+			claim:  newClaim("claim", "1G", api.ReadWriteOnce),
+		},
+	}
+
+	for _, test := range tests {
+		if test.volume.Status.Phase == api.VolumeReleased {
+			test.volume.Spec.ClaimRef = &api.ObjectReference{Namespace: "ns", Name: "gone", UID: "gone-uid"}
+		}
+		test.claim.Status.Phase = ""
+		fakeClient := &client.Fake{
+			PersistentVolumesList:      api.PersistentVolumeList{Items: []api.PersistentVolume{test.volume}},
+			PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{test.claim}},
+		}
+		newBinder(fakeClient).synchronize()
+
+		if updates := actionsOf(fakeClient, "update-persistentVolume"); len(updates) != 0 {
+			t.Errorf("%s: unexpected volume updates %#v", test.name, updates)
+		}
+		claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim")
+		if len(claims) != 1 || claims[0].(*api.PersistentVolumeClaim).Status.Phase != api.ClaimPending {
+			t.Errorf("%s: expected the claim to be marked pending, got %#v", test.name, claims)
+		}
+	}
+}
+
+func TestCompletesInterruptedBinding(t *testing.T) {
+	claim := newClaim("claim", "1G", api.ReadWriteOnce)
+	pv := newHostPathVolume("host", "10G", api.VolumeAvailable)
+	pv.Spec.ClaimRef = claimRef(&claim)
+	fakeClient := &client.Fake{
+		PersistentVolumesList:      api.PersistentVolumeList{Items: []api.PersistentVolume{pv}},
+		PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{claim}},
+	}
+	newBinder(fakeClient).synchronize()
+
+	if updates := actionsOf(fakeClient, "update-persistentVolume"); len(updates) != 0 {
+		t.Errorf("unexpected volume updates %#v", updates)
+	}
+	statuses := actionsOf(fakeClient, "update-status-persistentVolume")
+	if len(statuses) != 1 || statuses[0].(*api.PersistentVolume).Status.Phase != api.VolumeBound {
+		t.Errorf("expected the volume to be marked bound, got %#v", statuses)
+	}
+	claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim")
+	if len(claims) != 1 || claims[0].(*api.PersistentVolumeClaim).Status.VolumeRef.Name != "host" {
+		t.Errorf("expected the claim to be bound to the volume, got %#v", claims)
+	}
+}
+
+func TestBoundClaimLosesVolume(t *testing.T) {
+	claim := newClaim("claim", "1G", api.ReadWriteOnce)
+	claim.Status.Phase = api.ClaimBound
+	claim.Status.VolumeRef = &api.ObjectReference{Kind: "PersistentVolume", Name: "host", UID: "host-uid"}
+	bound := newHostPathVolume("host", "10G", api.VolumeBound)
+	bound.Spec.ClaimRef = claimRef(&claim)
+	rebound := newHostPathVolume("host", "10G", api.VolumeBound)
+	rebound.Spec.ClaimRef = &api.ObjectReference{Namespace: "ns", Name: "other", UID: "other-uid"}
+	recreated := newHostPathVolume("host", "10G", api.VolumeBound)
+	recreated.UID = "new-uid"
+	recreated.Spec.ClaimRef = claimRef(&claim)
+
+	tests := []struct {
+		name    string
+		volumes []api.PersistentVolume
+		lost    bool
+	}{
+		{"volume bound to the claim", []api.PersistentVolume{bound}, false},
+		{"volume deleted", []api.PersistentVolume{}, true},
+		{"volume bound to another claim", []api.PersistentVolume{rebound}, true},
+		{"volume recreated", []api.PersistentVolume{recreated}, true},
+	}
+	for _, test := range tests {
+		fakeClient := &client.Fake{
+			PersistentVolumesList:      api.PersistentVolumeList{Items: test.volumes},
+			PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{claim}},
+		}
+		newBinder(fakeClient).synchronize()
+
+		claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim")
+		if !test.lost {
+			if len(claims) != 0 {
+				t.Errorf("%s: unexpected claim status updates %#v", test.name, claims)
+			}
+			continue
+		}
+		if len(claims) != 1 || claims[0].(*api.PersistentVolumeClaim).Status.Phase != api.ClaimLost {
+			t.Errorf("%s: expected the claim to be marked lost, got %#v", test.name, claims)
+		}
+		if updates := actionsOf(fakeClient, "update-persistentVolume"); len(updates) != 0 {
+			t.Errorf("%s: expected the lost claim not to be bound again, got %#v", test.name, updates)
+		}
+	}
+
+	// A lost claim is left alone, even when a volume is available.
+	lost := claim
+	lost.Status.Phase = api.ClaimLost
+	fakeClient := &client.Fake{
+		PersistentVolumesList:      api.PersistentVolumeList{Items: []api.PersistentVolume{newHostPathVolume("free", "10G", api.VolumeAvailable)}},
+		PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{lost}},
+	}
+	newBinder(fakeClient).synchronize()
+	if claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim"); len(claims) != 0 {
+		t.Errorf("unexpected claim status updates %#v", claims)
+	}
+}
+
+func TestSyncVolumePhases(t *testing.T) {
+	claim := newClaim("claim", "1G", api.ReadWriteOnce)
+	claim.Status.Phase = api.ClaimBound
+	tests := []struct {
+		name          string
+		phase         api.PersistentVolumePhase
+		ref           *api.ObjectReference
+		expectedPhase api.PersistentVolumePhase
+	}{
+		{
+			name:          "new volume",
+			phase:         "",
+			expectedPhase: api.VolumeAvailable,
+		},
+		{
+			name:  "available volume",
+			phase: api.VolumeAvailable,
+		},
+		{
+			name:          "bound volume",
+			phase:         api.VolumeAvailable,
+			ref:           claimRef(&claim),
+			expectedPhase: api.VolumeBound,
+		},
+		{
+			name:          "claim deleted",
+			phase:         api.VolumeBound,
+			ref:           &api.ObjectReference{Namespace: "ns", Name: "gone", UID: "gone-uid"},
+			expectedPhase: api.VolumeReleased,
+		},
+		{
+			name:          "claim recreated",
+			phase:         api.VolumeBound,
+			ref:           &api.ObjectReference{Namespace: "ns", Name: "claim", UID: "old-uid"},
+			expectedPhase: api.VolumeReleased,
+		},
+		{
+			name:  "released volume retained",
+			phase: api.VolumeReleased,
+			ref:   &api.ObjectReference{Namespace: "ns", Name: "gone", UID: "gone-uid"},
+		},
+		{
+			name:          "released volume reclaimed by the administrator",
+			phase:         api.VolumeReleased,
+			expectedPhase: api.VolumeAvailable,
+		},
+	}
+
+	for _, test := range tests {
+		pv := newHostPathVolume("host", "10G", test.phase)
+		pv.Spec.ClaimRef = test.ref
+		fakeClient := &client.Fake{
+			PersistentVolumesList:      api.PersistentVolumeList{Items: []api.PersistentVolume{pv}},
+			PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: []api.PersistentVolumeClaim{claim}},
+		}
+		newBinder(fakeClient).synchronize()
+
+		statuses := actionsOf(fakeClient, "update-status-persistentVolume")
+		if test.expectedPhase == "" {
+			if len(statuses) != 0 {
+				t.Errorf("%s: unexpected status updates %#v", test.name, statuses)
+			}
+			continue
+		}
+		if len(statuses) != 1 || statuses[0].(*api.PersistentVolume).Status.Phase != test.expectedPhase {
+			t.Errorf("%s: expected the volume to move to %s, got %#v", test.name, test.expectedPhase, statuses)
+		}
+	}
+}
+
+// lists returns the list actions recorded by the client.
+func lists(fakeClient *client.Fake) []string {
+	names := []string{}
+	for _, a := range fakeClient.Actions {
+		if a.Action == "list-persistentVolumes" || a.Action == "list-persistentVolumeClaims" {
+			names = append(names, a.Action)
+		}
+	}
+	return names
+}
+
+func TestSyncEvents(t *testing.T) {
+	claim := newClaim("claim", "1G", api.ReadWriteOnce)
+	bound := claim
+	bound.Status = api.PersistentVolumeClaimStatus{
+		Phase:     api.ClaimBound,
+		VolumeRef: &api.ObjectReference{Kind: "PersistentVolume", Name: "host", UID: "host-uid"},
+	}
+	pv := newHostPathVolume("host", "10G", api.VolumeBound)
+	pv.Spec.ClaimRef = claimRef(&claim)
+	available := newHostPathVolume("host", "10G", api.VolumeAvailable)
+
+	tests := []struct {
+		name          string
+		volumes       []api.PersistentVolume
+		claims        []api.PersistentVolumeClaim
+		sync          func(b *PersistentVolumeClaimBinder)
+		expectedLists []string
+		volumePhase   api.PersistentVolumePhase
+		claimPhase    api.PersistentVolumeClaimPhase
+	}{
+		{
+			name:          "new claim bound",
+			volumes:       []api.PersistentVolume{available},
+			claims:        []api.PersistentVolumeClaim{claim},
+			sync:          func(b *PersistentVolumeClaimBinder) { b.syncClaimEvent(&claim) },
+			expectedLists: []string{"list-persistentVolumes"},
+			volumePhase:   api.VolumeBound,
+			claimPhase:    api.ClaimBound,
+		},
+		{
+			name:    "update of a bound claim",
+			volumes: []api.PersistentVolume{pv},
+			claims:  []api.PersistentVolumeClaim{bound},
+			sync:    func(b *PersistentVolumeClaimBinder) { b.syncClaimEvent(&bound) },
+		},
+		{
+			name:    "update of a bound volume",
+			volumes: []api.PersistentVolume{pv},
+			claims:  []api.PersistentVolumeClaim{bound},
+			sync:    func(b *PersistentVolumeClaimBinder) { b.syncVolumeEvent(&pv) },
+		},
+		{
+			name:        "claim deleted",
+			volumes:     []api.PersistentVolume{pv},
+			sync:        func(b *PersistentVolumeClaimBinder) { b.syncClaimEvent(&bound) },
+			volumePhase: api.VolumeReleased,
+		},
+		{
+			name:       "volume deleted",
+			claims:     []api.PersistentVolumeClaim{bound},
+			sync:       func(b *PersistentVolumeClaimBinder) { b.syncVolumeEvent(&pv) },
+			claimPhase: api.ClaimLost,
+		},
+	}
+
+	for _, test := range tests {
+		fakeClient := &client.Fake{
+			PersistentVolumesList:      api.PersistentVolumeList{Items: test.volumes},
+			PersistentVolumeClaimsList: api.PersistentVolumeClaimList{Items: test.claims},
+		}
+		test.sync(newBinder(fakeClient))
+
+		if test.expectedLists == nil {
+			test.expectedLists = []string{}
+		}
+		if names := lists(fakeClient); !reflect.DeepEqual(names, test.expectedLists) {
+			t.Errorf("%s: expected lists %v, got %v", test.name, test.expectedLists, names)
+		}
+		statuses := actionsOf(fakeClient, "update-status-persistentVolume")
+		if test.volumePhase == "" && len(statuses) != 0 {
+			t.Errorf("%s: unexpected volume status updates %#v", test.name, statuses)
+		}
+		if test.volumePhase != "" && (len(statuses) != 1 || statuses[0].(*api.PersistentVolume).Status.Phase != test.volumePhase) {
+			t.Errorf("%s: expected the volume to move to %s, got %#v", test.name, test.volumePhase, statuses)
+		}
+		claims := actionsOf(fakeClient, "update-status-persistentVolumeClaim")
+		if test.claimPhase == "" && len(claims) != 0 {
+			t.Errorf("%s: unexpected claim status updates %#v", test.name, claims)
+		}
+		if test.claimPhase != "" && (len(claims) != 1 || claims[0].(*api.PersistentVolumeClaim).Status.Phase != test.claimPhase) {
+			t.Errorf("%s: expected the claim to move to %s, got %#v", test.name, test.claimPhase, claims)
+		}
+	}
+}
+
+// recyclerClient serves the given recycler pod, or none if it is nil.
+type recyclerClient struct {
+	*client.Fake
+	pod *api.Pod
+}
+
+func (c *recyclerClient) Pods(namespace string) client.PodInterface {
+	return &recyclerPods{client.FakePods{Fake: c.Fake, Namespace: namespace}, c.pod}
+}
+
+type recyclerPods struct {
+	client.FakePods
+	pod *api.Pod
+}
+
+func (c *recyclerPods) Get(name string) (*api.Pod, error) {
+	c.FakePods.Get(name)
+	if c.pod == nil {
+		return nil, errors.NewNotFound("pods", name)
+	}
+	return c.pod, nil
+}
+
+func TestRecycleReleasedVolume(t *testing.T) {
+	tests := []struct {
+		name            string
+		pod             *api.Pod
+		expectCreate    bool
+		expectAvailable bool
+	}{
+		{
+			name:         "no recycler yet",
+			expectCreate: true,
+		},
+		{
+			name: "recycler running",
+			pod:  &api.Pod{Status: api.PodStatus{Phase: api.PodRunning}},
+		},
+		{
+			name: "recycler failed",
+			pod:  &api.Pod{Status: api.PodStatus{Phase: api.PodFailed}},
+		},
+		{
+			name:            "recycler succeeded",
+			pod:             &api.Pod{Status: api.PodStatus{Phase: api.PodSucceeded}},
+			expectAvailable: true,
+		},
+	}
+
+	for _, test := range tests {
+		pv := newHostPathVolume("host", "10G", api.VolumeReleased)
+		pv.Spec.PersistentVolumeReclaimPolicy = api.PersistentVolumeReclaimRecycle
+		pv.Spec.ClaimRef = &api.ObjectReference{Namespace: "ns", Name: "gone", UID: "gone-uid"}
+		fakeClient := &client.Fake{
+			PersistentVolumesList: api.PersistentVolumeList{Items: []api.PersistentVolume{pv}},
+		}
+		newBinder(&recyclerClient{fakeClient, test.pod}).synchronize()
+
+		if created := len(actionsOf(fakeClient, "create-pod")) == 1; created != test.expectCreate {
+			t.Errorf("%s: expected recycler pod creation to be %v, got %#v", test.name, test.expectCreate, fakeClient.Actions)
+		}
+		updates := actionsOf(fakeClient, "update-persistentVolume")
+		statuses := actionsOf(fakeClient, "update-status-persistentVolume")
+		deletes := actionsOf(fakeClient, "delete-pod")
+		if !test.expectAvailable {
+			if len(updates) != 0 || len(statuses) != 0 || len(deletes) != 0 {
+				t.Errorf("%s: expected the volume to stay released, got %#v", test.name, fakeClient.Actions)
+			}
+			continue
+		}
+		if len(updates) != 1 || updates[0].(*api.PersistentVolume).Spec.ClaimRef != nil {
+			t.Errorf("%s: expected the claim reference to be cleared, got %#v", test.name, updates)
+		}
+		if len(statuses) != 1 || statuses[0].(*api.PersistentVolume).Status.Phase != api.VolumeAvailable {
+			t.Errorf("%s: expected the volume to be available, got %#v", test.name, statuses)
+		}
+		if len(deletes) != 1 || deletes[0] != recyclerPodPrefix+"host" {
+			t.Errorf("%s: expected the recycler pod to be deleted, got %#v", test.name, deletes)
+		}
+	}
+}
+
+func TestNewRecyclerPod(t *testing.T) {
+	pv := newGCEVolume("gce", "10G", api.VolumeReleased)
+	pod := newRecyclerPod(&pv, "recycler")
+	if pod.Namespace != RecyclerNamespace || pod.Spec.RestartPolicy != api.RestartPolicyNever {
+		t.Errorf("unexpected recycler pod %#v", pod)
+	}
+	if len(pod.Spec.Volumes) != 1 || pod.Spec.Volumes[0].GCEPersistentDisk == nil || pod.Spec.Volumes[0].GCEPersistentDisk.PDName != "gce" {
+		t.Errorf("expected the recycler to mount the released disk, got %#v", pod.Spec.Volumes)
+	}
+	mounts := pod.Spec.Containers[0].VolumeMounts
+	if len(mounts) != 1 || mounts[0].Name != pod.Spec.Volumes[0].Name || mounts[0].MountPath != recyclerMountPath {
+		t.Errorf("unexpected volume mounts %#v", mounts)
+	}
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package app implements a SchedulerSimulator object for placing the pending
+// pods of a cluster dump offline.
+package app
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
+	_ "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/algorithmprovider"
+	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
+	latestschedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api/latest"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/factory"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/simulator"
+
+	"github.com/spf13/pflag"
+)
+
+// SchedulerSimulator has all the params needed to simulate a Scheduler
+type SchedulerSimulator struct {
+	ClusterDumpFile   string
+	AlgorithmProvider string
+	PolicyConfigFile  string
+}
+
+// NewSchedulerSimulator creates a new SchedulerSimulator with default parameters
+func NewSchedulerSimulator() *SchedulerSimulator {
+	s := SchedulerSimulator{
+		AlgorithmProvider: factory.DefaultProvider,
+	}
+	return &s
+}
+
+// AddFlags adds flags for a specific SchedulerSimulator to the specified FlagSet
+func (s *SchedulerSimulator) AddFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&s.ClusterDumpFile, "cluster_dump", s.ClusterDumpFile, "File with a List of the minions, pods, services and persistent volumes of the cluster; the pods without a host are placed")
+	fs.StringVar(&s.AlgorithmProvider, "algorithm_provider", s.AlgorithmProvider, "The scheduling algorithm provider to use")
+	fs.StringVar(&s.PolicyConfigFile, "policy_config_file", s.PolicyConfigFile, "File with scheduler policy configuration")
+}
+
+// Run places the pending pods of the cluster dump, and prints where they went.
+func (s *SchedulerSimulator) Run(_ []string) error {
+	if len(s.ClusterDumpFile) == 0 {
+		return fmt.Errorf("--cluster_dump is required")
+	}
+	data, err := ioutil.ReadFile(s.ClusterDumpFile)
+	if err != nil {
+		return fmt.Errorf("Unable to read cluster dump: %v", err)
+	}
+	objects, err := simulator.DecodeList(data)
+	if err != nil {
+		return fmt.Errorf("Invalid cluster dump: %v", err)
+	}
+	sim, err := simulator.NewSimulator(objects)
+	if err != nil {
+		return fmt.Errorf("Unable to load cluster dump: %v", err)
+	}
+
+	algo, err := s.createAlgorithm(sim.Factory)
+	if err != nil {
+		return fmt.Errorf("Failed to create scheduling algorithm: %v", err)
+	}
+
+	simulator.PrintPlacements(os.Stdout, sim.Run(algo))
+	return nil
+}
+
+func (s *SchedulerSimulator) createAlgorithm(configFactory *factory.ConfigFactory) (algorithm.Scheduler, error) {
+	var policy schedulerapi.Policy
+
+	if len(s.PolicyConfigFile) > 0 {
+		configData, err := ioutil.ReadFile(s.PolicyConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("Unable to read policy config: %v", err)
+		}
+		err = latestschedulerapi.Codec.DecodeInto(configData, &policy)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid configuration: %v", err)
+		}
+
+		return configFactory.CreateAlgorithmFromConfig(policy)
+	}
+
+	return configFactory.CreateAlgorithmFromProvider(s.AlgorithmProvider)
+}
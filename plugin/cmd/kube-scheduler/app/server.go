@@ -28,6 +28,8 @@ import (
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/record"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider"
+	gce_cloud "github.com/GoogleCloudPlatform/kubernetes/pkg/cloudprovider/gce"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/master/ports"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler"
@@ -35,6 +37,7 @@ import (
 	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
 	latestschedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api/latest"
 	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/factory"
+	"github.com/cnaize/kubernetes/pkg/api"
 
 	"github.com/golang/glog"
 	"github.com/spf13/pflag"
@@ -48,6 +51,9 @@ type SchedulerServer struct {
 	AlgorithmProvider string
 	PolicyConfigFile  string
 	EnableProfiling   bool
+	SchedulerName     string
+	CloudProvider     string
+	CloudConfigFile   string
 }
 
 // NewSchedulerServer creates a new SchedulerServer with default parameters
@@ -56,6 +62,7 @@ func NewSchedulerServer() *SchedulerServer {
 		Port:              ports.SchedulerPort,
 		Address:           util.IP(net.ParseIP("127.0.0.1")),
 		AlgorithmProvider: factory.DefaultProvider,
+		SchedulerName:     api.DefaultSchedulerName,
 	}
 	return &s
 }
@@ -68,6 +75,9 @@ func (s *SchedulerServer) AddFlags(fs *pflag.FlagSet) {
 	fs.StringVar(&s.AlgorithmProvider, "algorithm_provider", s.AlgorithmProvider, "The scheduling algorithm provider to use")
 	fs.StringVar(&s.PolicyConfigFile, "policy_config_file", s.PolicyConfigFile, "File with scheduler policy configuration")
 	fs.BoolVar(&s.EnableProfiling, "profiling", false, "Enable profiling via web interface host:port/debug/pprof/")
+	fs.StringVar(&s.SchedulerName, "scheduler_name", s.SchedulerName, "Name of the scheduler; only pods whose spec.schedulerName matches are placed by this scheduler")
+	fs.StringVar(&s.CloudProvider, "cloud_provider", s.CloudProvider, "The provider for cloud services.  Empty string for no provider.")
+	fs.StringVar(&s.CloudConfigFile, "cloud_config", s.CloudConfigFile, "The path to the cloud provider configuration file.  Empty string for no configuration file.")
 }
 
 // Run runs the specified SchedulerServer.  This should never exit.
@@ -89,7 +99,11 @@ func (s *SchedulerServer) Run(_ []string) error {
 		http.ListenAndServe(net.JoinHostPort(s.Address.String(), strconv.Itoa(s.Port)), nil)
 	}()
 
-	configFactory := factory.NewConfigFactory(kubeClient)
+	configFactory := factory.NewConfigFactory(kubeClient, s.SchedulerName)
+	cloud := cloudprovider.InitCloudProvider(s.CloudProvider, s.CloudConfigFile)
+	if gce, ok := cloud.(*gce_cloud.GCECloud); ok {
+		configFactory.DiskZoneInfo = gceDiskZones{gce}
+	}
 	config, err := s.createConfig(configFactory)
 	if err != nil {
 		glog.Fatalf("Failed to create scheduler configuration: %v", err)
@@ -127,3 +141,20 @@ func (s *SchedulerServer) createConfig(configFactory *factory.ConfigFactory) (*s
 
 	return configFactory.CreateFromProvider(s.AlgorithmProvider)
 }
+
+// gceDiskZones looks up the zones of persistent disks in GCE.
+type gceDiskZones struct {
+	cloud *gce_cloud.GCECloud
+}
+
+// GetDiskZoneLabels returns the zone and region of the disk as the labels of a minion.
+func (z gceDiskZones) GetDiskZoneLabels(pdName string) (map[string]string, error) {
+	zone, err := z.cloud.GetDiskZone(pdName)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]string{
+		api.LabelZoneFailureDomain: zone.FailureDomain,
+		api.LabelZoneRegion:        zone.Region,
+	}, nil
+}
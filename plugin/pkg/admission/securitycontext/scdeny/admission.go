@@ -0,0 +1,65 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scdeny
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func init() {
+	admission.RegisterPlugin("SecurityContextDeny", func(client client.Interface, config io.Reader) (admission.Interface, error) {
+		return NewSecurityContextDeny(), nil
+	})
+}
+
+// securityContextDeny is an implementation of admission.Interface.
+// It denies pods whose containers set a run-as user or SELinux options.
+type securityContextDeny struct{}
+
+func (securityContextDeny) Admit(a admission.Attributes) (err error) {
+	if a.GetResource() != "pods" || (a.GetOperation() != "CREATE" && a.GetOperation() != "UPDATE") {
+		return nil
+	}
+	pod, ok := a.GetObject().(*api.Pod)
+	if !ok {
+		return nil
+	}
+	for _, container := range pod.Spec.Containers {
+		sc := container.SecurityContext
+		if sc == nil {
+			continue
+		}
+		if sc.RunAsUser != nil {
+			return apierrors.NewForbidden(a.GetResource(), pod.Name, fmt.Errorf("container %q: securityContext.runAsUser is forbidden", container.Name))
+		}
+		if sc.SELinuxOptions != nil {
+			return apierrors.NewForbidden(a.GetResource(), pod.Name, fmt.Errorf("container %q: securityContext.seLinuxOptions is forbidden", container.Name))
+		}
+	}
+	return nil
+}
+
+// NewSecurityContextDeny creates a new instance of the SecurityContextDeny admission controller.
+func NewSecurityContextDeny() admission.Interface {
+	return new(securityContextDeny)
+}
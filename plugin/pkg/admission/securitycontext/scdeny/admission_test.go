@@ -0,0 +1,67 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scdeny
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func validPod(sc *api.SecurityContext) *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "123", Namespace: "ns"},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "ctr", Image: "image", SecurityContext: sc}},
+		},
+	}
+}
+
+func TestAdmission(t *testing.T) {
+	runAsUser := int64(1000)
+	handler := NewSecurityContextDeny()
+	tests := map[string]struct {
+		sc        *api.SecurityContext
+		operation string
+		admit     bool
+	}{
+		"no security context":     {nil, "CREATE", true},
+		"read-only root":          {&api.SecurityContext{ReadOnlyRootFilesystem: true}, "CREATE", true},
+		"run as user":             {&api.SecurityContext{RunAsUser: &runAsUser}, "CREATE", false},
+		"run as user on update":   {&api.SecurityContext{RunAsUser: &runAsUser}, "UPDATE", false},
+		"selinux options":         {&api.SecurityContext{SELinuxOptions: &api.SELinuxOptions{Level: "s0:c1,c2"}}, "CREATE", false},
+		"run as user on deletion": {&api.SecurityContext{RunAsUser: &runAsUser}, "DELETE", true},
+	}
+	for name, test := range tests {
+		err := handler.Admit(admission.NewAttributesRecord(validPod(test.sc), "ns", "pods", test.operation))
+		if test.admit && err != nil {
+			t.Errorf("%s: unexpected error: %v", name, err)
+		}
+		if !test.admit && !apierrors.IsForbidden(err) {
+			t.Errorf("%s: expected a forbidden error, got %v", name, err)
+		}
+	}
+}
+
+func TestIgnoreNonPods(t *testing.T) {
+	handler := NewSecurityContextDeny()
+	if err := handler.Admit(admission.NewAttributesRecord(&api.Service{}, "ns", "services", "CREATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
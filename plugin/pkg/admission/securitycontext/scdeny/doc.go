@@ -0,0 +1,22 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package scdeny contains an admission control plug-in that denies
+// pods which set security context options a cluster may not want end
+// users to control. It rejects any pod whose containers request to run
+// as a specific user or with specific SELinux labels. Mounting the root
+// filesystem read-only only restricts a container, so it is allowed.
+package scdeny
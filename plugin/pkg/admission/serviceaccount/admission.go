@@ -0,0 +1,146 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	apierrors "github.com/GoogleCloudPlatform/kubernetes/pkg/api/errors"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/serviceaccount"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func init() {
+	admission.RegisterPlugin("ServiceAccount", func(client client.Interface, config io.Reader) (admission.Interface, error) {
+		return NewServiceAccount(client), nil
+	})
+}
+
+// DefaultAPITokenMountPath is the path the API token of the service account is mounted at in every container.
+const DefaultAPITokenMountPath = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// serviceAccount is an implementation of admission.Interface.
+// It sets the service account of pods that don't name one, makes sure the service account exists,
+// and mounts its API token into the containers of the pod.
+type serviceAccount struct {
+	client client.Interface
+}
+
+func (s *serviceAccount) Admit(a admission.Attributes) (err error) {
+	// only pods are run as a service account, and only when they are created
+	if a.GetResource() != "pods" || a.GetOperation() != "CREATE" {
+		return nil
+	}
+	pod, ok := a.GetObject().(*api.Pod)
+	if !ok {
+		return nil
+	}
+
+	if len(pod.Spec.ServiceAccount) == 0 {
+		pod.Spec.ServiceAccount = serviceaccount.DefaultServiceAccountName
+	}
+
+	account, err := s.client.ServiceAccounts(a.GetNamespace()).Get(pod.Spec.ServiceAccount)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return apierrors.NewForbidden("pods", pod.Name, fmt.Errorf("service account %s/%s was not found, retry after the service account is created", a.GetNamespace(), pod.Spec.ServiceAccount))
+		}
+		return apierrors.NewInternalError(err)
+	}
+
+	token, err := s.findToken(account)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if len(token) == 0 {
+		return apierrors.NewForbidden("pods", pod.Name, fmt.Errorf("no API token found for service account %s/%s, retry after the token is automatically created and added to the service account", account.Namespace, account.Name))
+	}
+
+	return mountToken(pod, token)
+}
+
+// findToken returns the name of a token secret referenced by the service account,
+// or an empty string if it references none yet.
+func (s *serviceAccount) findToken(account *api.ServiceAccount) (string, error) {
+	for _, ref := range account.Secrets {
+		secret, err := s.client.Secrets(account.Namespace).Get(ref.Name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return "", err
+		}
+		if serviceaccount.IsServiceAccountToken(secret, account) {
+			return secret.Name, nil
+		}
+	}
+	return "", nil
+}
+
+// mountToken adds a volume for the token secret to the pod, and mounts it at
+// DefaultAPITokenMountPath in every container that doesn't already mount
+// something there.
+func mountToken(pod *api.Pod, token string) error {
+	volumeName := ""
+	for _, volume := range pod.Spec.Volumes {
+		if volume.Secret != nil && volume.Secret.SecretName == token {
+			volumeName = volume.Name
+			break
+		}
+	}
+	if len(volumeName) == 0 {
+		for _, volume := range pod.Spec.Volumes {
+			if volume.Name == token {
+				return apierrors.NewForbidden("pods", pod.Name, fmt.Errorf("volume %s conflicts with the API token volume of the service account", token))
+			}
+		}
+		volumeName = token
+		pod.Spec.Volumes = append(pod.Spec.Volumes, api.Volume{
+			Name: volumeName,
+			VolumeSource: api.VolumeSource{
+				Secret: &api.SecretVolumeSource{SecretName: token},
+			},
+		})
+	}
+
+	for i := range pod.Spec.Containers {
+		container := &pod.Spec.Containers[i]
+		mounted := false
+		for _, mount := range container.VolumeMounts {
+			if mount.MountPath == DefaultAPITokenMountPath {
+				mounted = true
+				break
+			}
+		}
+		if !mounted {
+			container.VolumeMounts = append(container.VolumeMounts, api.VolumeMount{
+				Name:      volumeName,
+				ReadOnly:  true,
+				MountPath: DefaultAPITokenMountPath,
+			})
+		}
+	}
+	return nil
+}
+
+// NewServiceAccount returns an admission.Interface that runs pods as service accounts.
+func NewServiceAccount(c client.Interface) admission.Interface {
+	return &serviceAccount{client: c}
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"testing"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/admission"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+func newAccount(name string, secrets ...string) api.ServiceAccount {
+	account := api.ServiceAccount{
+		ObjectMeta: api.ObjectMeta{Name: name, Namespace: "myns", UID: "12345"},
+	}
+	for _, secret := range secrets {
+		account.Secrets = append(account.Secrets, api.ObjectReference{Name: secret})
+	}
+	return account
+}
+
+func newToken(account api.ServiceAccount, name string) api.Secret {
+	return api.Secret{
+		ObjectMeta: api.ObjectMeta{
+			Name:      name,
+			Namespace: account.Namespace,
+			Annotations: map[string]string{
+				api.ServiceAccountNameKey: account.Name,
+				api.ServiceAccountUIDKey:  string(account.UID),
+			},
+		},
+		Type: api.SecretTypeServiceAccountToken,
+	}
+}
+
+func newPod() *api.Pod {
+	return &api.Pod{
+		ObjectMeta: api.ObjectMeta{Name: "pod", Namespace: "myns"},
+		Spec: api.PodSpec{
+			Containers: []api.Container{{Name: "a"}, {Name: "b"}},
+		},
+	}
+}
+
+func TestAssignsDefaultServiceAccountAndMountsToken(t *testing.T) {
+	account := newAccount("default", "default-token-abcde")
+	fakeClient := &client.Fake{
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{account}},
+		Secret:              newToken(account, "default-token-abcde"),
+	}
+	pod := newPod()
+	if err := NewServiceAccount(fakeClient).Admit(admission.NewAttributesRecord(pod, "myns", "pods", "CREATE")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pod.Spec.ServiceAccount != "default" {
+		t.Errorf("expected the default service account, got %q", pod.Spec.ServiceAccount)
+	}
+	if len(pod.Spec.Volumes) != 1 {
+		t.Fatalf("expected a token volume, got %#v", pod.Spec.Volumes)
+	}
+	volume := pod.Spec.Volumes[0]
+	if volume.Secret == nil || volume.Secret.SecretName != "default-token-abcde" {
+		t.Errorf("expected a secret volume of the token, got %#v", volume)
+	}
+	for _, container := range pod.Spec.Containers {
+		if len(container.VolumeMounts) != 1 {
+			t.Errorf("%s: expected the token to be mounted, got %#v", container.Name, container.VolumeMounts)
+			continue
+		}
+		mount := container.VolumeMounts[0]
+		if mount.Name != volume.Name || mount.MountPath != DefaultAPITokenMountPath || !mount.ReadOnly {
+			t.Errorf("%s: unexpected mount %#v", container.Name, mount)
+		}
+	}
+}
+
+func TestKeepsNamedServiceAccountAndExistingMounts(t *testing.T) {
+	account := newAccount("builder", "builder-token-abcde")
+	fakeClient := &client.Fake{
+		ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{account}},
+		Secret:              newToken(account, "builder-token-abcde"),
+	}
+	pod := newPod()
+	pod.Spec.ServiceAccount = "builder"
+	pod.Spec.Volumes = []api.Volume{{Name: "custom", VolumeSource: api.VolumeSource{EmptyDir: &api.EmptyDirVolumeSource{}}}}
+	pod.Spec.Containers[1].VolumeMounts = []api.VolumeMount{{Name: "custom", MountPath: DefaultAPITokenMountPath}}
+	if err := NewServiceAccount(fakeClient).Admit(admission.NewAttributesRecord(pod, "myns", "pods", "CREATE")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if pod.Spec.ServiceAccount != "builder" {
+		t.Errorf("expected the named service account to be kept, got %q", pod.Spec.ServiceAccount)
+	}
+	if len(pod.Spec.Volumes) != 2 {
+		t.Errorf("expected a token volume to be added, got %#v", pod.Spec.Volumes)
+	}
+	if mounts := pod.Spec.Containers[0].VolumeMounts; len(mounts) != 1 || mounts[0].Name != "builder-token-abcde" {
+		t.Errorf("expected the token to be mounted in the first container, got %#v", mounts)
+	}
+	if mounts := pod.Spec.Containers[1].VolumeMounts; len(mounts) != 1 || mounts[0].Name != "custom" {
+		t.Errorf("expected the existing mount of the second container to be kept, got %#v", mounts)
+	}
+}
+
+func TestRejectsMissingServiceAccountOrToken(t *testing.T) {
+	account := newAccount("default", "opaque")
+	opaque := newToken(account, "opaque")
+	opaque.Type = api.SecretTypeOpaque
+
+	testCases := map[string]*client.Fake{
+		"missing service account": {},
+		"missing token": {
+			ServiceAccountsList: api.ServiceAccountList{Items: []api.ServiceAccount{account}},
+			Secret:              opaque,
+		},
+	}
+	for name, fakeClient := range testCases {
+		pod := newPod()
+		if err := NewServiceAccount(fakeClient).Admit(admission.NewAttributesRecord(pod, "myns", "pods", "CREATE")); err == nil {
+			t.Errorf("%s: expected the pod to be rejected", name)
+		}
+	}
+}
+
+func TestIgnoresOtherResourcesAndUpdates(t *testing.T) {
+	handler := NewServiceAccount(&client.Fake{})
+	pod := newPod()
+	if err := handler.Admit(admission.NewAttributesRecord(pod, "myns", "pods", "UPDATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := handler.Admit(admission.NewAttributesRecord(&api.Service{}, "myns", "services", "CREATE")); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if len(pod.Spec.ServiceAccount) != 0 {
+		t.Errorf("expected pod updates to be ignored")
+	}
+}
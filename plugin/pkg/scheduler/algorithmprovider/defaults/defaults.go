@@ -40,6 +40,13 @@ func defaultPredicates() util.StringSet {
 		),
 		// Fit is determined by non-conflicting disk volumes.
 		factory.RegisterFitPredicate("NoDiskConflict", algorithm.NoDiskConflict),
+		// Fit is determined by the zones of the persistent disks the pod uses.
+		factory.RegisterFitPredicateFactory(
+			"NoVolumeZoneConflict",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return algorithm.NewVolumeZonePredicate(args.PersistentVolumeLister, args.DiskZoneInfo, args.NodeInfo)
+			},
+		),
 		// Fit is determined by node selector query.
 		factory.RegisterFitPredicateFactory(
 			"MatchNodeSelector",
@@ -47,6 +54,27 @@ func defaultPredicates() util.StringSet {
 				return algorithm.NewSelectorMatchPredicate(args.NodeInfo)
 			},
 		),
+		// Fit is determined by the required node affinity of the pod.
+		factory.RegisterFitPredicateFactory(
+			"MatchNodeAffinity",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return algorithm.NewNodeAffinityPredicate(args.NodeInfo)
+			},
+		),
+		// Fit is determined by the required inter-pod affinity and anti-affinity of the pod.
+		factory.RegisterFitPredicateFactory(
+			"MatchInterPodAffinity",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return algorithm.NewPodAffinityPredicate(args.PodLister, args.NodeInfo)
+			},
+		),
+		// Fit is determined by the tolerations of the pod for the taints of the node.
+		factory.RegisterFitPredicateFactory(
+			"PodToleratesNodeTaints",
+			func(args factory.PluginFactoryArgs) algorithm.FitPredicate {
+				return algorithm.NewTaintTolerationPredicate(args.NodeInfo)
+			},
+		),
 		// Fit is determined by the presence of the Host parameter and a string match
 		factory.RegisterFitPredicate("HostName", algorithm.PodFitsHost),
 	)
@@ -66,6 +94,18 @@ func defaultPriorities() util.StringSet {
 				}
 			},
 		),
+		// Prioritize nodes by the preferred node affinity of the pod.
+		factory.RegisterPriorityFunction("NodeAffinityPriority", algorithm.NodeAffinityPriority, 1),
+		// Prioritize nodes by the preferred inter-pod affinity and anti-affinity of the pod.
+		factory.RegisterPriorityConfigFactory(
+			"InterPodAffinityPriority",
+			func(args factory.PluginFactoryArgs) algorithm.PriorityConfig {
+				return algorithm.PriorityConfig{
+					Function: algorithm.NewInterPodAffinityPriority(args.NodeInfo),
+					Weight:   1,
+				}
+			},
+		),
 		// EqualPriority is a prioritizer function that gives an equal weight of one to all minions
 		factory.RegisterPriorityFunction("EqualPriority", algorithm.EqualPriority, 0),
 	)
@@ -17,6 +17,8 @@ limitations under the License.
 package api
 
 import (
+	"time"
+
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
@@ -26,6 +28,8 @@ type Policy struct {
 	Predicates []PredicatePolicy `json:"predicates"`
 	// Holds the information to configure the priority functions
 	Priorities []PriorityPolicy `json:"priorities"`
+	// Holds the information to communicate with the extenders
+	Extenders []ExtenderConfig `json:"extenders"`
 }
 
 type PredicatePolicy struct {
@@ -101,3 +105,30 @@ type LabelPreference struct {
 	// If false, higher priority is given to minions that do not have the label
 	Presence bool `json:"presence"`
 }
+
+// ExtenderFailurePolicy is what the scheduler does when a call to an extender fails.
+type ExtenderFailurePolicy string
+
+const (
+	// The pod is not scheduled, and scheduling it is retried later
+	ExtenderFailurePolicyFail ExtenderFailurePolicy = "Fail"
+	// The call is ignored, as if the extender kept all the minions and scored none
+	ExtenderFailurePolicyIgnore ExtenderFailurePolicy = "Ignore"
+)
+
+// Holds the parameters used to communicate with an extender
+// A verb left empty means the extender does not provide the corresponding call
+type ExtenderConfig struct {
+	// URL prefix the verbs are appended to
+	URLPrefix string `json:"urlPrefix"`
+	// Verb of the filter call
+	FilterVerb string `json:"filterVerb,omitempty"`
+	// Verb of the prioritize call
+	PrioritizeVerb string `json:"prioritizeVerb,omitempty"`
+	// The numeric multiplier for the minion scores that the prioritize call generates
+	Weight int `json:"weight,omitempty"`
+	// Timeout of a call to the extender; zero means the default timeout
+	HTTPTimeout time.Duration `json:"httpTimeout,omitempty"`
+	// What to do when a call to the extender fails; empty means Fail
+	FailurePolicy ExtenderFailurePolicy `json:"failurePolicy,omitempty"`
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
+	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api/v1"
+	"github.com/cnaize/kubernetes/pkg/api"
+
+	"github.com/golang/glog"
+)
+
+// DefaultExtenderTimeout is the timeout of the calls to the extenders which do not set one.
+const DefaultExtenderTimeout = 5 * time.Second
+
+// HTTPExtender calls an extender over HTTP. The pod and the candidate
+// minions are POSTed as JSON to the filter and prioritize verbs of the
+// extender, in the v1beta3 version of the API.
+type HTTPExtender struct {
+	extenderURL    string
+	filterVerb     string
+	prioritizeVerb string
+	weight         int
+	failurePolicy  schedulerapi.ExtenderFailurePolicy
+	client         *http.Client
+}
+
+// NewHTTPExtender returns the extender described by config.
+func NewHTTPExtender(config *schedulerapi.ExtenderConfig) (algorithm.SchedulerExtender, error) {
+	if len(config.URLPrefix) == 0 {
+		return nil, fmt.Errorf("the URL prefix of an extender is required")
+	}
+	if len(config.PrioritizeVerb) > 0 && config.Weight <= 0 {
+		return nil, fmt.Errorf("the weight of extender %s must be positive", config.URLPrefix)
+	}
+	switch config.FailurePolicy {
+	case "", schedulerapi.ExtenderFailurePolicyFail, schedulerapi.ExtenderFailurePolicyIgnore:
+	default:
+		return nil, fmt.Errorf("unknown failure policy %q of extender %s", config.FailurePolicy, config.URLPrefix)
+	}
+	timeout := config.HTTPTimeout
+	if timeout == 0 {
+		timeout = DefaultExtenderTimeout
+	}
+	return &HTTPExtender{
+		extenderURL:    strings.TrimRight(config.URLPrefix, "/"),
+		filterVerb:     config.FilterVerb,
+		prioritizeVerb: config.PrioritizeVerb,
+		weight:         config.Weight,
+		failurePolicy:  config.FailurePolicy,
+		client:         &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// Filter returns the minions the extender keeps, among the given ones.
+func (h *HTTPExtender) Filter(pod api.Pod, nodes api.NodeList) (api.NodeList, error) {
+	if len(h.filterVerb) == 0 {
+		return nodes, nil
+	}
+	var result v1.ExtenderFilterResult
+	if err := h.send(h.filterVerb, &pod, &nodes, &result); err != nil {
+		return h.filterFailed(nodes, err)
+	}
+	if len(result.Error) > 0 {
+		return h.filterFailed(nodes, fmt.Errorf("extender %s failed to filter: %s", h.extenderURL, result.Error))
+	}
+	// Only the names of the returned minions matter: the minions are the ones sent.
+	kept := util.NewStringSet()
+	for _, node := range result.Nodes.Items {
+		kept.Insert(node.Name)
+	}
+	filtered := api.NodeList{}
+	for _, node := range nodes.Items {
+		if kept.Has(node.Name) {
+			filtered.Items = append(filtered.Items, node)
+		}
+	}
+	return filtered, nil
+}
+
+func (h *HTTPExtender) filterFailed(nodes api.NodeList, err error) (api.NodeList, error) {
+	if h.failurePolicy == schedulerapi.ExtenderFailurePolicyIgnore {
+		glog.Warningf("Ignoring the failure of extender %s: %v", h.extenderURL, err)
+		return nodes, nil
+	}
+	return api.NodeList{}, err
+}
+
+// Prioritize returns the scores the extender gives to the minions, and its weight.
+func (h *HTTPExtender) Prioritize(pod api.Pod, nodes api.NodeList) (algorithm.HostPriorityList, int, error) {
+	if len(h.prioritizeVerb) == 0 {
+		return algorithm.HostPriorityList{}, 0, nil
+	}
+	var result v1.HostPriorityList
+	if err := h.send(h.prioritizeVerb, &pod, &nodes, &result); err != nil {
+		if h.failurePolicy == schedulerapi.ExtenderFailurePolicyIgnore {
+			glog.Warningf("Ignoring the failure of extender %s: %v", h.extenderURL, err)
+			return algorithm.HostPriorityList{}, 0, nil
+		}
+		return nil, 0, err
+	}
+	list := algorithm.HostPriorityList{}
+	for _, hostPriority := range result {
+		list = append(list, algorithm.HostPriority{Host: hostPriority.Host, Score: hostPriority.Score})
+	}
+	return list, h.weight, nil
+}
+
+// send POSTs the pod and the minions to the verb of the extender and decodes
+// its response into result.
+func (h *HTTPExtender) send(verb string, pod *api.Pod, nodes *api.NodeList, result interface{}) error {
+	args := v1.ExtenderArgs{}
+	if err := api.Scheme.Convert(pod, &args.Pod); err != nil {
+		return err
+	}
+	if err := api.Scheme.Convert(nodes, &args.Nodes); err != nil {
+		return err
+	}
+	body, err := json.Marshal(&args)
+	if err != nil {
+		return err
+	}
+
+	url := h.extenderURL + "/" + verb
+	resp, err := h.client.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("extender %s returned status %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package scheduler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
+	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api/v1"
+	"github.com/cnaize/kubernetes/pkg/api"
+)
+
+// fakeExtenderServer stands in for an extender which keeps the minions whose
+// name is not "machine1", and scores each minion by the length of its name.
+func fakeExtenderServer(t *testing.T, filterError string, delay time.Duration) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		time.Sleep(delay)
+		var args v1.ExtenderArgs
+		if err := json.NewDecoder(req.Body).Decode(&args); err != nil {
+			t.Errorf("Unexpected error decoding the extender args: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if args.Pod.Name != "foo" {
+			t.Errorf("Expected pod foo, got %q", args.Pod.Name)
+		}
+		switch req.URL.Path {
+		case "/scheduler/filter":
+			result := v1.ExtenderFilterResult{Error: filterError}
+			for _, node := range args.Nodes.Items {
+				if node.Name != "machine1" {
+					result.Nodes.Items = append(result.Nodes.Items, node)
+				}
+			}
+			json.NewEncoder(w).Encode(&result)
+		case "/scheduler/prioritize":
+			result := v1.HostPriorityList{}
+			for _, node := range args.Nodes.Items {
+				result = append(result, v1.HostPriority{Host: node.Name, Score: len(node.Name)})
+			}
+			json.NewEncoder(w).Encode(&result)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func extenderNodes(names ...string) api.NodeList {
+	nodes := api.NodeList{}
+	for _, name := range names {
+		nodes.Items = append(nodes.Items, api.Node{ObjectMeta: api.ObjectMeta{Name: name}})
+	}
+	return nodes
+}
+
+func TestHTTPExtender(t *testing.T) {
+	server := fakeExtenderServer(t, "", 0)
+	defer server.Close()
+
+	extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{
+		URLPrefix:      server.URL + "/scheduler/",
+		FilterVerb:     "filter",
+		PrioritizeVerb: "prioritize",
+		Weight:         3,
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: api.NamespaceDefault}}
+
+	filtered, err := extender.Filter(pod, extenderNodes("machine1", "machine2", "machine10"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if expected := extenderNodes("machine2", "machine10"); !reflect.DeepEqual(expected, filtered) {
+		t.Errorf("Expected %#v, got %#v", expected, filtered)
+	}
+
+	priorities, weight, err := extender.Prioritize(pod, extenderNodes("machine2", "machine10"))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := algorithm.HostPriorityList{{Host: "machine2", Score: 8}, {Host: "machine10", Score: 9}}
+	if !reflect.DeepEqual(expected, priorities) {
+		t.Errorf("Expected %#v, got %#v", expected, priorities)
+	}
+	if weight != 3 {
+		t.Errorf("Expected weight 3, got %d", weight)
+	}
+}
+
+func TestHTTPExtenderWithoutVerbs(t *testing.T) {
+	extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{URLPrefix: "http://127.0.0.1:0"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	nodes := extenderNodes("machine1")
+	filtered, err := extender.Filter(api.Pod{}, nodes)
+	if err != nil || !reflect.DeepEqual(nodes, filtered) {
+		t.Errorf("Expected the minions unchanged, got %#v, %v", filtered, err)
+	}
+	priorities, weight, err := extender.Prioritize(api.Pod{}, nodes)
+	if err != nil || len(priorities) != 0 || weight != 0 {
+		t.Errorf("Expected no scores, got %#v, %d, %v", priorities, weight, err)
+	}
+}
+
+func TestHTTPExtenderFailures(t *testing.T) {
+	tests := []struct {
+		name        string
+		path        string
+		filterError string
+		delay       time.Duration
+	}{
+		{name: "unknown verbs", path: "/unknown/"},
+		{name: "filter error", path: "/scheduler", filterError: "inventory unavailable"},
+		{name: "timeout", path: "/scheduler", delay: 200 * time.Millisecond},
+	}
+
+	for _, test := range tests {
+		server := fakeExtenderServer(t, test.filterError, test.delay)
+		pod := api.Pod{ObjectMeta: api.ObjectMeta{Name: "foo"}}
+		nodes := extenderNodes("machine1", "machine2")
+		for _, policy := range []schedulerapi.ExtenderFailurePolicy{"", schedulerapi.ExtenderFailurePolicyFail, schedulerapi.ExtenderFailurePolicyIgnore} {
+			extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{
+				URLPrefix:      server.URL + test.path,
+				FilterVerb:     "filter",
+				PrioritizeVerb: "prioritize",
+				Weight:         1,
+				HTTPTimeout:    50 * time.Millisecond,
+				FailurePolicy:  policy,
+			})
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", test.name, err)
+			}
+			filtered, filterErr := extender.Filter(pod, nodes)
+			if policy == schedulerapi.ExtenderFailurePolicyIgnore {
+				if filterErr != nil || !reflect.DeepEqual(nodes, filtered) {
+					t.Errorf("%s: expected the failure to be ignored, got %#v, %v", test.name, filtered, filterErr)
+				}
+			} else if filterErr == nil {
+				t.Errorf("%s: expected a filter error with policy %q", test.name, policy)
+			}
+		}
+		server.Close()
+	}
+
+	// Prioritize fails the same way when the extender cannot be reached.
+	for _, policy := range []schedulerapi.ExtenderFailurePolicy{schedulerapi.ExtenderFailurePolicyFail, schedulerapi.ExtenderFailurePolicyIgnore} {
+		extender, err := NewHTTPExtender(&schedulerapi.ExtenderConfig{
+			URLPrefix:      "http://127.0.0.1:0",
+			PrioritizeVerb: "prioritize",
+			Weight:         1,
+			FailurePolicy:  policy,
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		priorities, weight, err := extender.Prioritize(api.Pod{}, extenderNodes("machine1"))
+		if policy == schedulerapi.ExtenderFailurePolicyIgnore {
+			if err != nil || len(priorities) != 0 || weight != 0 {
+				t.Errorf("Expected the failure to be ignored, got %#v, %d, %v", priorities, weight, err)
+			}
+		} else if err == nil {
+			t.Errorf("Expected a prioritize error")
+		}
+	}
+}
+
+func TestNewHTTPExtenderErrors(t *testing.T) {
+	configs := []schedulerapi.ExtenderConfig{
+		{FilterVerb: "filter"},
+		{URLPrefix: "http://127.0.0.1", PrioritizeVerb: "prioritize"},
+		{URLPrefix: "http://127.0.0.1", FailurePolicy: "Retry"},
+	}
+	for i := range configs {
+		if _, err := NewHTTPExtender(&configs[i]); err == nil {
+			t.Errorf("Expected an error for %#v", configs[i])
+		}
+	}
+}
@@ -36,6 +36,10 @@ import (
 	"github.com/golang/glog"
 )
 
+// How long a pod the scheduler bound is assumed to be bound when the binding
+// is not observed.
+const assumedPodTTL = 30 * time.Second
+
 // ConfigFactory knows how to fill out a scheduler config with its support functions.
 type ConfigFactory struct {
 	Client *client.Client
@@ -45,26 +49,36 @@ type ConfigFactory struct {
 	ScheduledPodLister *cache.StoreToPodLister
 	// a means to list all known scheduled pods and pods assumed to have been scheduled.
 	PodLister algorithm.PodLister
+	// the scheduled pods and the pods assumed to have been scheduled, aggregated per minion.
+	SchedulerCache *algorithm.SchedulerCache
 	// a means to list all minions
 	NodeLister *cache.StoreToNodeLister
 	// a means to list all services
 	ServiceLister *cache.StoreToServiceLister
+	// a means to list all persistent volumes
+	PersistentVolumeLister *cache.StoreToPersistentVolumeLister
+	// a means to look up the zones of persistent disks in the cloud; may be nil
+	DiskZoneInfo algorithm.DiskZoneInfo
+	// the name of this scheduler; only pods naming it are placed by this scheduler
+	SchedulerName string
 
 	modeler scheduler.SystemModeler
 }
 
 // Initializes the factory.
-func NewConfigFactory(client *client.Client) *ConfigFactory {
+func NewConfigFactory(client *client.Client, schedulerName string) *ConfigFactory {
 	c := &ConfigFactory{
-		Client:             client,
-		PodQueue:           cache.NewFIFO(cache.MetaNamespaceKeyFunc),
-		ScheduledPodLister: &cache.StoreToPodLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
-		NodeLister:         &cache.StoreToNodeLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
-		ServiceLister:      &cache.StoreToServiceLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
-	}
-	modeler := scheduler.NewSimpleModeler(&cache.StoreToPodLister{c.PodQueue}, c.ScheduledPodLister)
-	c.modeler = modeler
-	c.PodLister = modeler.PodLister()
+		Client:                 client,
+		SchedulerName:          schedulerName,
+		PodQueue:               cache.NewFIFO(cache.MetaNamespaceKeyFunc),
+		ScheduledPodLister:     &cache.StoreToPodLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
+		NodeLister:             &cache.StoreToNodeLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
+		ServiceLister:          &cache.StoreToServiceLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
+		PersistentVolumeLister: &cache.StoreToPersistentVolumeLister{cache.NewStore(cache.MetaNamespaceKeyFunc)},
+		SchedulerCache:         algorithm.NewSchedulerCache(assumedPodTTL),
+	}
+	c.modeler = scheduler.NewCachedModeler(c.SchedulerCache)
+	c.PodLister = c.SchedulerCache
 	return c
 }
 
@@ -81,13 +95,45 @@ func (f *ConfigFactory) CreateFromProvider(providerName string) (*scheduler.Conf
 		return nil, err
 	}
 
-	return f.CreateFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys)
+	return f.CreateFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys, []algorithm.SchedulerExtender{})
+}
+
+// CreateAlgorithmFromProvider creates the scheduling algorithm of a registered algorithm
+// provider, without starting to watch the apiserver.
+func (f *ConfigFactory) CreateAlgorithmFromProvider(providerName string) (algorithm.Scheduler, error) {
+	glog.V(2).Infof("creating scheduling algorithm from algorithm provider '%v'", providerName)
+	provider, err := GetAlgorithmProvider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	return f.CreateAlgorithmFromKeys(provider.FitPredicateKeys, provider.PriorityFunctionKeys, []algorithm.SchedulerExtender{})
 }
 
 // Creates a scheduler from the configuration file
 func (f *ConfigFactory) CreateFromConfig(policy schedulerapi.Policy) (*scheduler.Config, error) {
 	glog.V(2).Infof("creating scheduler from configuration: %v", policy)
+	predicateKeys, priorityKeys, extenders, err := getPolicyKeys(policy)
+	if err != nil {
+		return nil, err
+	}
+	return f.CreateFromKeys(predicateKeys, priorityKeys, extenders)
+}
+
+// CreateAlgorithmFromConfig creates the scheduling algorithm of the configuration
+// file, without starting to watch the apiserver.
+func (f *ConfigFactory) CreateAlgorithmFromConfig(policy schedulerapi.Policy) (algorithm.Scheduler, error) {
+	glog.V(2).Infof("creating scheduling algorithm from configuration: %v", policy)
+	predicateKeys, priorityKeys, extenders, err := getPolicyKeys(policy)
+	if err != nil {
+		return nil, err
+	}
+	return f.CreateAlgorithmFromKeys(predicateKeys, priorityKeys, extenders)
+}
 
+// getPolicyKeys registers the predicates and priorities of the policy, and returns
+// their keys along with the extenders of the policy.
+func getPolicyKeys(policy schedulerapi.Policy) (util.StringSet, util.StringSet, []algorithm.SchedulerExtender, error) {
 	predicateKeys := util.NewStringSet()
 	for _, predicate := range policy.Predicates {
 		glog.V(2).Infof("Registering predicate: %s", predicate.Name)
@@ -100,39 +146,72 @@ func (f *ConfigFactory) CreateFromConfig(policy schedulerapi.Policy) (*scheduler
 		priorityKeys.Insert(RegisterCustomPriorityFunction(priority))
 	}
 
-	return f.CreateFromKeys(predicateKeys, priorityKeys)
+	extenders := []algorithm.SchedulerExtender{}
+	for i := range policy.Extenders {
+		glog.V(2).Infof("Creating extender with config %+v", policy.Extenders[i])
+		extender, err := scheduler.NewHTTPExtender(&policy.Extenders[i])
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		extenders = append(extenders, extender)
+	}
+	return predicateKeys, priorityKeys, extenders, nil
 }
 
-// ReflectorDeletionHook passes all operations through to Store, but calls
-// OnDelete in a goroutine if there is a deletion.
-type ReflectorDeletionHook struct {
+// schedulerCacheStore passes all operations through to Store, and applies
+// them to the scheduler cache as well.
+type schedulerCacheStore struct {
 	cache.Store
-	OnDelete func(obj interface{})
+	schedulerCache *algorithm.SchedulerCache
 }
 
-func (r ReflectorDeletionHook) Delete(obj interface{}) error {
-	go func() {
-		defer util.HandleCrash()
-		r.OnDelete(obj)
-	}()
-	return r.Store.Delete(obj)
+func (s schedulerCacheStore) Add(obj interface{}) error {
+	if err := s.Store.Add(obj); err != nil {
+		return err
+	}
+	if pod, ok := obj.(*api.Pod); ok {
+		s.schedulerCache.AddPod(pod)
+	}
+	return nil
 }
 
-// Creates a scheduler from a set of registered fit predicate keys and priority keys.
-func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSet) (*scheduler.Config, error) {
-	glog.V(2).Infof("creating scheduler with fit predicates '%v' and priority functions '%v", predicateKeys, priorityKeys)
-	pluginArgs := PluginFactoryArgs{
-		PodLister:     f.PodLister,
-		ServiceLister: f.ServiceLister,
-		NodeLister:    f.NodeLister,
-		NodeInfo:      f.NodeLister,
+func (s schedulerCacheStore) Update(obj interface{}) error {
+	if err := s.Store.Update(obj); err != nil {
+		return err
 	}
-	predicateFuncs, err := getFitPredicateFunctions(predicateKeys, pluginArgs)
-	if err != nil {
-		return nil, err
+	if pod, ok := obj.(*api.Pod); ok {
+		s.schedulerCache.UpdatePod(pod)
 	}
+	return nil
+}
 
-	priorityConfigs, err := getPriorityFunctionConfigs(priorityKeys, pluginArgs)
+func (s schedulerCacheStore) Delete(obj interface{}) error {
+	if err := s.Store.Delete(obj); err != nil {
+		return err
+	}
+	if pod, ok := obj.(*api.Pod); ok {
+		s.schedulerCache.RemovePod(pod)
+	}
+	return nil
+}
+
+func (s schedulerCacheStore) Replace(list []interface{}) error {
+	pods := []api.Pod{}
+	for _, obj := range list {
+		if pod, ok := obj.(*api.Pod); ok {
+			pods = append(pods, *pod)
+		}
+	}
+	if err := s.Store.Replace(list); err != nil {
+		return err
+	}
+	s.schedulerCache.Replace(pods)
+	return nil
+}
+
+// Creates a scheduler from a set of registered fit predicate keys and priority keys, and extenders.
+func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSet, extenders []algorithm.SchedulerExtender) (*scheduler.Config, error) {
+	algo, err := f.CreateAlgorithmFromKeys(predicateKeys, priorityKeys, extenders)
 	if err != nil {
 		return nil, err
 	}
@@ -142,22 +221,16 @@ func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSe
 	// shouldn't be neeeded once that is resolved.
 	cache.NewReflector(f.createUnassignedPodLW(), &api.Pod{}, f.PodQueue, 30*time.Second).Run()
 
-	// Pass through all events to the scheduled pod store, but on a deletion,
-	// also remove from the assumed pods.
-	assumedPodDeleter := ReflectorDeletionHook{
-		Store: f.ScheduledPodLister.Store,
-		OnDelete: func(obj interface{}) {
-			if pod, ok := obj.(*api.Pod); ok {
-				f.modeler.LockedAction(func() {
-					f.modeler.ForgetPod(pod)
-				})
-			}
-		},
+	// Pass through all events to the scheduled pod store and to the scheduler
+	// cache, where they replace or remove the assumed pods.
+	scheduledPodStore := schedulerCacheStore{
+		Store:          f.ScheduledPodLister.Store,
+		schedulerCache: f.SchedulerCache,
 	}
 
 	// Watch and cache all running pods. Scheduler needs to find all pods
 	// so it knows where it's safe to place a pod. Cache this locally.
-	cache.NewReflector(f.createAssignedPodLW(), &api.Pod{}, assumedPodDeleter, 0).Run()
+	cache.NewReflector(f.createAssignedPodLW(), &api.Pod{}, scheduledPodStore, 0).Run()
 
 	// Watch minions.
 	// Minions may be listed frequently, so provide a local up-to-date cache.
@@ -174,9 +247,9 @@ func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSe
 	// Cache this locally.
 	cache.NewReflector(f.createServiceLW(), &api.Service{}, f.ServiceLister.Store, 0).Run()
 
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-
-	algo := algorithm.NewGenericScheduler(predicateFuncs, priorityConfigs, f.PodLister, r)
+	// Watch and cache all persistent volumes. Scheduler needs to find the zones
+	// of the disks used by pods. Cache this locally.
+	cache.NewReflector(f.createPersistentVolumeLW(), &api.PersistentVolume{}, f.PersistentVolumeLister.Store, 0).Run()
 
 	podBackoff := podBackoff{
 		perPodBackoff: map[string]*backoffEntry{},
@@ -191,16 +264,65 @@ func (f *ConfigFactory) CreateFromKeys(predicateKeys, priorityKeys util.StringSe
 		MinionLister: f.NodeLister,
 		Algorithm:    algo,
 		Binder:       &binder{f.Client},
+		Evictor:      &evictor{f.Client},
 		NextPod: func() *api.Pod {
-			pod := f.PodQueue.Pop().(*api.Pod)
-			glog.V(2).Infof("About to try and schedule pod %v", pod.Name)
-			return pod
+			return f.getNextPod()
 		},
 		Error:    f.makeDefaultErrorFunc(&podBackoff, f.PodQueue),
-		Recorder: record.FromSource(api.EventSource{Component: "scheduler"}),
+		Recorder: record.FromSource(api.EventSource{Component: f.SchedulerName}),
 	}, nil
 }
 
+// CreateAlgorithmFromKeys creates the scheduling algorithm from a set of registered fit
+// predicate keys and priority keys, and extenders. The algorithm reads the listers of
+// the factory; unlike CreateFromKeys, it doesn't start filling them from the apiserver.
+func (f *ConfigFactory) CreateAlgorithmFromKeys(predicateKeys, priorityKeys util.StringSet, extenders []algorithm.SchedulerExtender) (algorithm.Scheduler, error) {
+	glog.V(2).Infof("creating scheduler with fit predicates '%v' and priority functions '%v", predicateKeys, priorityKeys)
+	pluginArgs := PluginFactoryArgs{
+		PodLister:              f.PodLister,
+		ServiceLister:          f.ServiceLister,
+		NodeLister:             f.NodeLister,
+		NodeInfo:               f.NodeLister,
+		PersistentVolumeLister: f.PersistentVolumeLister,
+		DiskZoneInfo:           f.DiskZoneInfo,
+	}
+	predicateFuncs, err := getFitPredicateFunctions(predicateKeys, pluginArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	priorityConfigs, err := getPriorityFunctionConfigs(priorityKeys, pluginArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	return algorithm.NewGenericScheduler(predicateFuncs, priorityConfigs, extenders, f.PodLister, r), nil
+}
+
+// getNextPod pops pods off the queue until it finds one this scheduler is
+// responsible for. Pods naming another scheduler are left to that scheduler.
+func (f *ConfigFactory) getNextPod() *api.Pod {
+	for {
+		pod := f.PodQueue.Pop().(*api.Pod)
+		if f.responsibleForPod(pod) {
+			glog.V(2).Infof("About to try and schedule pod %v", pod.Name)
+			return pod
+		}
+		glog.V(4).Infof("Ignoring pod %v, which is placed by scheduler %q", pod.Name, pod.Spec.SchedulerName)
+	}
+}
+
+// responsibleForPod returns true if the pod names this scheduler, or names no
+// scheduler and this is the default scheduler.
+func (f *ConfigFactory) responsibleForPod(pod *api.Pod) bool {
+	if len(pod.Spec.SchedulerName) == 0 {
+		return f.SchedulerName == api.DefaultSchedulerName
+	}
+	return pod.Spec.SchedulerName == f.SchedulerName
+}
+
 // Returns a cache.ListWatch that finds all pods that need to be
 // scheduled.
 func (factory *ConfigFactory) createUnassignedPodLW() *cache.ListWatch {
@@ -272,6 +394,11 @@ func (factory *ConfigFactory) createServiceLW() *cache.ListWatch {
 	return cache.NewListWatchFromClient(factory.Client, "services", api.NamespaceAll, parseSelectorOrDie(""))
 }
 
+// Returns a cache.ListWatch that gets all changes to persistent volumes.
+func (factory *ConfigFactory) createPersistentVolumeLW() *cache.ListWatch {
+	return cache.NewListWatchFromClient(factory.Client, "persistentVolumes", api.NamespaceAll, parseSelectorOrDie(""))
+}
+
 func (factory *ConfigFactory) makeDefaultErrorFunc(backoff *podBackoff, podQueue *cache.FIFO) func(pod *api.Pod, err error) {
 	return func(pod *api.Pod, err error) {
 		glog.Errorf("Error scheduling %v %v: %v; retrying", pod.Namespace, pod.Name, err)
@@ -337,6 +464,16 @@ func (b *binder) Bind(binding *api.Binding) error {
 	// return b.Pods(binding.Namespace).Bind(binding)
 }
 
+type evictor struct {
+	*client.Client
+}
+
+// Evict deletes the pod with its own termination grace period.
+func (e *evictor) Evict(pod *api.Pod) error {
+	glog.V(2).Infof("Attempting to evict %v/%v", pod.Namespace, pod.Name)
+	return e.Pods(pod.Namespace).Delete(pod.Name, &api.DeleteOptions{})
+}
+
 type clock interface {
 	Now() time.Time
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/api/testapi"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
 	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/util"
@@ -44,7 +45,7 @@ func TestCreate(t *testing.T) {
 	server := httptest.NewServer(&handler)
 	defer server.Close()
 	client := client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()})
-	factory := NewConfigFactory(client)
+	factory := NewConfigFactory(client, api.DefaultSchedulerName)
 	factory.Create()
 }
 
@@ -62,7 +63,7 @@ func TestCreateFromConfig(t *testing.T) {
 	server := httptest.NewServer(&handler)
 	defer server.Close()
 	client := client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()})
-	factory := NewConfigFactory(client)
+	factory := NewConfigFactory(client, api.DefaultSchedulerName)
 
 	// Pre-register some predicate and priority functions
 	RegisterFitPredicate("PredicateOne", PredicateOne)
@@ -92,6 +93,52 @@ func TestCreateFromConfig(t *testing.T) {
 	factory.CreateFromConfig(policy)
 }
 
+func TestCreateFromConfigWithExtenders(t *testing.T) {
+	var policy schedulerapi.Policy
+
+	handler := util.FakeHandler{
+		StatusCode:   500,
+		ResponseBody: "",
+		T:            t,
+	}
+	server := httptest.NewServer(&handler)
+	defer server.Close()
+	client := client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()})
+	factory := NewConfigFactory(client, api.DefaultSchedulerName)
+
+	configData := []byte(`{
+		"kind" : "Policy",
+		"apiVersion" : "v1",
+		"predicates" : [],
+		"priorities" : [],
+		"extenders" : [
+			{"urlPrefix" : "http://127.0.0.1:12346/scheduler", "filterVerb" : "filter", "prioritizeVerb" : "prioritize", "weight" : 5, "httpTimeout" : 1000000000, "failurePolicy" : "Ignore"}
+		]
+	}`)
+	if err := latestschedulerapi.Codec.DecodeInto(configData, &policy); err != nil {
+		t.Fatalf("Invalid configuration: %v", err)
+	}
+	expected := []schedulerapi.ExtenderConfig{{
+		URLPrefix:      "http://127.0.0.1:12346/scheduler",
+		FilterVerb:     "filter",
+		PrioritizeVerb: "prioritize",
+		Weight:         5,
+		HTTPTimeout:    time.Second,
+		FailurePolicy:  schedulerapi.ExtenderFailurePolicyIgnore,
+	}}
+	if !reflect.DeepEqual(expected, policy.Extenders) {
+		t.Errorf("Expected %#v, got %#v", expected, policy.Extenders)
+	}
+	if _, err := factory.CreateFromConfig(policy); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	policy.Extenders[0].Weight = 0
+	if _, err := factory.CreateFromConfig(policy); err == nil {
+		t.Errorf("Expected an error for an extender without weight")
+	}
+}
+
 func TestCreateFromEmptyConfig(t *testing.T) {
 	var configData []byte
 	var policy schedulerapi.Policy
@@ -104,7 +151,7 @@ func TestCreateFromEmptyConfig(t *testing.T) {
 	server := httptest.NewServer(&handler)
 	defer server.Close()
 	client := client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()})
-	factory := NewConfigFactory(client)
+	factory := NewConfigFactory(client, api.DefaultSchedulerName)
 
 	configData = []byte(`{}`)
 	err := latestschedulerapi.Codec.DecodeInto(configData, &policy)
@@ -115,11 +162,11 @@ func TestCreateFromEmptyConfig(t *testing.T) {
 	factory.CreateFromConfig(policy)
 }
 
-func PredicateOne(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func PredicateOne(pod api.Pod, state *algorithm.NodeState, node string) (bool, error) {
 	return true, nil
 }
 
-func PredicateTwo(pod api.Pod, existingPods []api.Pod, node string) (bool, error) {
+func PredicateTwo(pod api.Pod, state *algorithm.NodeState, node string) (bool, error) {
 	return true, nil
 }
 
@@ -320,7 +367,7 @@ func TestPollMinions(t *testing.T) {
 		server := httptest.NewServer(mux)
 		defer server.Close()
 		client := client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()})
-		cf := NewConfigFactory(client)
+		cf := NewConfigFactory(client, api.DefaultSchedulerName)
 
 		ce, err := cf.pollMinions()
 		if err != nil {
@@ -354,7 +401,7 @@ func TestDefaultErrorFunc(t *testing.T) {
 	mux.Handle(testapi.ResourcePath("pods", "bar", "foo"), &handler)
 	server := httptest.NewServer(mux)
 	defer server.Close()
-	factory := NewConfigFactory(client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()}))
+	factory := NewConfigFactory(client.NewOrDie(&client.Config{Host: server.URL, Version: testapi.Version()}), api.DefaultSchedulerName)
 	queue := cache.NewFIFO(cache.MetaNamespaceKeyFunc)
 	podBackoff := podBackoff{
 		perPodBackoff:   map[string]*backoffEntry{},
@@ -502,3 +549,91 @@ func TestBackoff(t *testing.T) {
 		t.Errorf("expected: 60, got %s", duration.String())
 	}
 }
+
+func TestSchedulerCacheStore(t *testing.T) {
+	schedulerCache := algorithm.NewSchedulerCache(time.Minute)
+	store := schedulerCacheStore{
+		Store:          cache.NewStore(cache.MetaNamespaceKeyFunc),
+		schedulerCache: schedulerCache,
+	}
+	pod := func(name, host string) *api.Pod {
+		return &api.Pod{
+			ObjectMeta: api.ObjectMeta{Namespace: "bar", Name: name},
+			Status:     api.PodStatus{Host: host},
+		}
+	}
+	listed := func() map[string]string {
+		pods, err := schedulerCache.List(labels.Everything())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		hosts := map[string]string{}
+		for _, pod := range pods {
+			hosts[pod.Name] = pod.Status.Host
+		}
+		return hosts
+	}
+
+	schedulerCache.AssumePod(pod("assumed", "machine1"))
+	store.Replace([]interface{}{pod("foo", "machine1")})
+	store.Add(pod("baz", "machine1"))
+	store.Update(pod("baz", "machine2"))
+	store.Delete(pod("foo", "machine1"))
+	if e, a := map[string]string{"assumed": "machine1", "baz": "machine2"}, listed(); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+	if e, a := 1, len(store.List()); e != a {
+		t.Errorf("expected %d pods in the store, got %d", e, a)
+	}
+}
+
+func TestResponsibleForPod(t *testing.T) {
+	client := client.NewOrDie(&client.Config{Host: "", Version: testapi.Version()})
+	defaultFactory := NewConfigFactory(client, api.DefaultSchedulerName)
+	batchFactory := NewConfigFactory(client, "batch-scheduler")
+
+	table := []struct {
+		schedulerName   string
+		expectedDefault bool
+		expectedBatch   bool
+	}{
+		{"", true, false},
+		{api.DefaultSchedulerName, true, false},
+		{"batch-scheduler", false, true},
+		{"other-scheduler", false, false},
+	}
+	for _, item := range table {
+		pod := &api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: "foo", Namespace: "bar"},
+			Spec:       api.PodSpec{SchedulerName: item.schedulerName},
+		}
+		if e, a := item.expectedDefault, defaultFactory.responsibleForPod(pod); e != a {
+			t.Errorf("%q: expected default scheduler responsible %v, got %v", item.schedulerName, e, a)
+		}
+		if e, a := item.expectedBatch, batchFactory.responsibleForPod(pod); e != a {
+			t.Errorf("%q: expected batch scheduler responsible %v, got %v", item.schedulerName, e, a)
+		}
+	}
+}
+
+func TestGetNextPodSkipsOtherSchedulers(t *testing.T) {
+	client := client.NewOrDie(&client.Config{Host: "", Version: testapi.Version()})
+	factory := NewConfigFactory(client, "batch-scheduler")
+	for _, p := range []struct{ name, schedulerName string }{
+		{"default", ""},
+		{"other", "other-scheduler"},
+		{"batch", "batch-scheduler"},
+	} {
+		factory.PodQueue.Add(&api.Pod{
+			ObjectMeta: api.ObjectMeta{Name: p.name, Namespace: "bar"},
+			Spec:       api.PodSpec{SchedulerName: p.schedulerName},
+		})
+	}
+
+	if e, a := "batch", factory.getNextPod().Name; e != a {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+	if n := len(factory.PodQueue.List()); n != 0 {
+		t.Errorf("expected an empty queue, got %d pods", n)
+	}
+}
@@ -32,8 +32,10 @@ import (
 type PluginFactoryArgs struct {
 	algorithm.PodLister
 	algorithm.ServiceLister
-	NodeLister algorithm.MinionLister
-	NodeInfo   algorithm.NodeInfo
+	NodeLister             algorithm.MinionLister
+	NodeInfo               algorithm.NodeInfo
+	PersistentVolumeLister algorithm.PersistentVolumeLister
+	DiskZoneInfo           algorithm.DiskZoneInfo
 }
 
 // A FitPredicateFactory produces a FitPredicate from the given args.
@@ -32,6 +32,7 @@ import (
 var (
 	_ = SystemModeler(&FakeModeler{})
 	_ = SystemModeler(&SimpleModeler{})
+	_ = SystemModeler(&CachedModeler{})
 )
 
 // ExtendedPodLister: SimpleModeler needs to be able to check for a pod's
@@ -182,3 +183,26 @@ type simpleModelerPods struct {
 func (s simpleModelerPods) List(selector labels.Selector) (pods []api.Pod, err error) {
 	return s.simpleModeler.listPods(selector)
 }
+
+// CachedModeler implements the SystemModeler interface with a scheduler cache,
+// which keeps the pods assumed to be bound along with the ones observed to be.
+type CachedModeler struct {
+	cache *algorithm.SchedulerCache
+
+	actionLocker
+}
+
+// NewCachedModeler returns a new CachedModeler.
+func NewCachedModeler(cache *algorithm.SchedulerCache) *CachedModeler {
+	return &CachedModeler{cache: cache}
+}
+
+// AssumePod adds the pod to the cache until its binding is observed.
+func (c *CachedModeler) AssumePod(pod *api.Pod) {
+	c.cache.AssumePod(pod)
+}
+
+// ForgetPod removes the pod from the cache, unless its binding was observed.
+func (c *CachedModeler) ForgetPod(pod *api.Pod) {
+	c.cache.ForgetPod(pod)
+}
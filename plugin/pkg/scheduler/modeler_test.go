@@ -17,10 +17,13 @@ limitations under the License.
 package scheduler
 
 import (
+	"reflect"
 	"testing"
+	"time"
 
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/client/cache"
 	"github.com/GoogleCloudPlatform/kubernetes/pkg/labels"
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
 	"github.com/cnaize/kubernetes/pkg/api"
 )
 
@@ -109,3 +112,20 @@ func TestModeler(t *testing.T) {
 		}
 	}
 }
+
+func TestCachedModeler(t *testing.T) {
+	schedulerCache := algorithm.NewSchedulerCache(time.Minute)
+	m := NewCachedModeler(schedulerCache)
+	pods := names{{"default", "foo"}, {"default", "bar"}}.list()
+	m.AssumePod(&pods[0])
+	m.AssumePod(&pods[1])
+	m.ForgetPod(&pods[1])
+
+	list, err := schedulerCache.List(labels.Everything())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e, a := []string{"'default/foo ()'"}, podNames(list); !reflect.DeepEqual(e, a) {
+		t.Errorf("expected %v, got %v", e, a)
+	}
+}
@@ -31,6 +31,12 @@ type Binder interface {
 	Bind(binding *api.Binding) error
 }
 
+// PodEvictor knows how to evict a pod.
+type PodEvictor interface {
+	// Evict deletes the pod, giving it its termination grace period.
+	Evict(pod *api.Pod) error
+}
+
 // SystemModeler can help scheduler produce a model of the system that
 // anticipates reality. For example, if scheduler has pods A and B both
 // using hostPort 80, when it binds A to machine M it should not bind B
@@ -69,6 +75,10 @@ type Config struct {
 	MinionLister scheduler.MinionLister
 	Algorithm    scheduler.Scheduler
 	Binder       Binder
+	// Evictor evicts the pods preempted to make room for pods of higher
+	// priority. Preemption is disabled when it is nil or when Algorithm
+	// does not implement scheduler.Preemptor.
+	Evictor PodEvictor
 
 	// NextPod should be a function that blocks until the next pod
 	// is available. We don't use a channel for this, because scheduling
@@ -104,6 +114,9 @@ func (s *Scheduler) scheduleOne() {
 	if err != nil {
 		glog.V(1).Infof("Failed to schedule: %v", pod)
 		s.config.Recorder.Eventf(pod, "failedScheduling", "Error scheduling: %v", err)
+		if _, ok := err.(*scheduler.FitError); ok {
+			s.preempt(pod)
+		}
 		s.config.Error(pod, err)
 		return
 	}
@@ -132,3 +145,30 @@ func (s *Scheduler) scheduleOne() {
 		s.config.Modeler.AssumePod(&assumed)
 	})
 }
+
+// preempt evicts pods of lower priority than the pod from a node where that
+// makes room for it. The pod itself goes back to the queue, to be scheduled
+// once the evicted pods are gone.
+func (s *Scheduler) preempt(pod *api.Pod) {
+	preemptor, ok := s.config.Algorithm.(scheduler.Preemptor)
+	if !ok || s.config.Evictor == nil {
+		return
+	}
+	dest, victims, err := preemptor.Preempt(*pod, s.config.MinionLister)
+	if err != nil {
+		glog.V(3).Infof("Failed to preempt pods for %v: %v", pod.Name, err)
+		return
+	}
+	if len(victims) == 0 {
+		return
+	}
+	s.config.Recorder.Eventf(pod, "preempting", "Preempting %d pod(s) on %v", len(victims), dest)
+	for i := range victims {
+		victim := &victims[i]
+		if err := s.config.Evictor.Evict(victim); err != nil {
+			glog.Errorf("Failed to evict pod %v/%v: %v", victim.Namespace, victim.Name, err)
+			continue
+		}
+		s.config.Recorder.Eventf(victim, "preempted", "Preempted by %v/%v to make room on %v", pod.Namespace, pod.Name, dest)
+	}
+}
@@ -146,3 +146,104 @@ func TestScheduler(t *testing.T) {
 		events.Stop()
 	}
 }
+
+type mockPreemptor struct {
+	mockScheduler
+	machine string
+	victims []api.Pod
+	err     error
+}
+
+func (mp mockPreemptor) Preempt(pod api.Pod, ml scheduler.MinionLister) (string, []api.Pod, error) {
+	return mp.machine, mp.victims, mp.err
+}
+
+type fakeEvictor struct {
+	e func(pod *api.Pod) error
+}
+
+func (fe fakeEvictor) Evict(pod *api.Pod) error { return fe.e(pod) }
+
+func TestSchedulerPreemption(t *testing.T) {
+	defer record.StartLogging(t.Logf).Stop()
+	fitErr := &scheduler.FitError{Pod: *podWithID("foo", "")}
+	victims := []api.Pod{*podWithID("bar", "machine1"), *podWithID("baz", "machine1")}
+
+	table := []struct {
+		algo           scheduler.Scheduler
+		expectEvicted  []string
+		expectReasons  []string
+		withoutEvictor bool
+	}{
+		{
+			algo:          mockPreemptor{mockScheduler{"", fitErr}, "machine1", victims, nil},
+			expectEvicted: []string{"bar", "baz"},
+			expectReasons: []string{"failedScheduling", "preempting", "preempted", "preempted"},
+		}, {
+			algo:          mockPreemptor{mockScheduler{"", errors.New("scheduler")}, "machine1", victims, nil},
+			expectReasons: []string{"failedScheduling"},
+		}, {
+			algo:          mockPreemptor{mockScheduler{"", fitErr}, "", nil, errors.New("preemption")},
+			expectReasons: []string{"failedScheduling"},
+		}, {
+			algo:          mockScheduler{"", fitErr},
+			expectReasons: []string{"failedScheduling"},
+		}, {
+			algo:           mockPreemptor{mockScheduler{"", fitErr}, "machine1", victims, nil},
+			expectReasons:  []string{"failedScheduling"},
+			withoutEvictor: true,
+		},
+	}
+
+	for i, item := range table {
+		evicted := []string{}
+		var gotError error
+		c := &Config{
+			Modeler: &FakeModeler{},
+			MinionLister: scheduler.FakeMinionLister(
+				api.NodeList{Items: []api.Node{{ObjectMeta: api.ObjectMeta{Name: "machine1"}}}},
+			),
+			Algorithm: item.algo,
+			Binder: fakeBinder{func(b *api.Binding) error {
+				t.Errorf("%v: unexpected binding %v", i, b)
+				return nil
+			}},
+			Evictor: fakeEvictor{func(pod *api.Pod) error {
+				evicted = append(evicted, pod.Name)
+				return nil
+			}},
+			Error: func(p *api.Pod, err error) {
+				gotError = err
+			},
+			NextPod: func() *api.Pod {
+				return podWithID("foo", "")
+			},
+			Recorder: record.FromSource(api.EventSource{Component: "scheduler"}),
+		}
+		if item.withoutEvictor {
+			c.Evictor = nil
+		}
+		reasons := make(chan string, 10)
+		events := record.GetEvents(func(e *api.Event) {
+			reasons <- e.Reason
+		})
+		New(c).scheduleOne()
+		if gotError == nil {
+			t.Errorf("%v: expected the pod to be sent back with an error", i)
+		}
+		if len(item.expectEvicted) == 0 {
+			item.expectEvicted = []string{}
+		}
+		if e, a := item.expectEvicted, evicted; !reflect.DeepEqual(e, a) {
+			t.Errorf("%v: evicted: wanted %v, got %v", i, e, a)
+		}
+		got := []string{}
+		for range item.expectReasons {
+			got = append(got, <-reasons)
+		}
+		if e, a := item.expectReasons, got; !reflect.DeepEqual(e, a) {
+			t.Errorf("%v: event reasons: wanted %v, got %v", i, e, a)
+		}
+		events.Stop()
+	}
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/GoogleCloudPlatform/kubernetes/pkg/runtime"
+	algorithm "github.com/GoogleCloudPlatform/kubernetes/pkg/scheduler"
+	"github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/factory"
+	"github.com/cnaize/kubernetes/pkg/api"
+	"github.com/cnaize/kubernetes/pkg/api/latest"
+
+	"github.com/golang/glog"
+)
+
+// Placement is the outcome of scheduling a pending pod.
+type Placement struct {
+	Pod api.Pod
+	// Host is the minion the pod was placed on, empty if the pod wasn't placed.
+	Host string
+	// FailedPredicates are the predicates each minion failed, if no minion fit the pod.
+	FailedPredicates algorithm.FailedPredicateMap
+	// Err is the error scheduling the pod, other than no minion fitting it.
+	Err error
+}
+
+// Simulator holds a cluster loaded from a dump, and the pods pending in it.
+type Simulator struct {
+	// Factory holds the listers the cluster is loaded into. The scheduling
+	// algorithm is created from it, so that it reads the cluster.
+	Factory *factory.ConfigFactory
+	// Pending are the pods to place, in order.
+	Pending []api.Pod
+}
+
+// DecodeList decodes the objects of a List, in any API version, from data.
+func DecodeList(data []byte) ([]runtime.Object, error) {
+	obj, err := latest.Codec.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	return runtime.ExtractList(obj)
+}
+
+// NewSimulator creates a simulator of the cluster made of the objects: minions,
+// services, persistent volumes and pods, and lists of them. The pods without a
+// host are pending; the others run on their host.
+func NewSimulator(objects []runtime.Object) (*Simulator, error) {
+	s := &Simulator{
+		Factory: factory.NewConfigFactory(nil, api.DefaultSchedulerName),
+	}
+	if err := s.load(objects); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Simulator) load(objects []runtime.Object) error {
+	for _, obj := range objects {
+		var err error
+		switch obj := obj.(type) {
+		case *api.Node:
+			err = s.Factory.NodeLister.Store.Add(obj)
+		case *api.Service:
+			err = s.Factory.ServiceLister.Store.Add(obj)
+		case *api.PersistentVolume:
+			err = s.Factory.PersistentVolumeLister.Store.Add(obj)
+		case *api.Pod:
+			if len(obj.Status.Host) == 0 {
+				s.Pending = append(s.Pending, *obj)
+			} else {
+				s.Factory.SchedulerCache.AddPod(obj)
+			}
+		default:
+			items, listErr := runtime.ExtractList(obj)
+			if listErr != nil {
+				glog.V(2).Infof("Ignoring %T in the cluster", obj)
+				continue
+			}
+			err = s.load(items)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Run places the pending pods, in order, with the algorithm. The pods placed
+// run on their minion when the next pods are placed.
+func (s *Simulator) Run(algo algorithm.Scheduler) []Placement {
+	placements := []Placement{}
+	for _, pod := range s.Pending {
+		placement := Placement{Pod: pod}
+		host, err := algo.Schedule(pod, s.Factory.NodeLister)
+		if err == nil {
+			placement.Host = host
+			placed := pod
+			placed.Status.Host = host
+			s.Factory.SchedulerCache.AddPod(&placed)
+		} else if fitErr, ok := err.(*algorithm.FitError); ok {
+			placement.FailedPredicates = fitErr.FailedPredicates
+		} else {
+			placement.Err = err
+		}
+		placements = append(placements, placement)
+	}
+	return placements
+}
+
+// PrintPlacements writes the minion of every pod, and the predicates each minion
+// failed for the pods which weren't placed.
+func PrintPlacements(w io.Writer, placements []Placement) {
+	placed := 0
+	for _, placement := range placements {
+		name := placement.Pod.Name
+		if len(placement.Pod.Namespace) > 0 {
+			name = placement.Pod.Namespace + "/" + name
+		}
+		switch {
+		case len(placement.Host) > 0:
+			placed++
+			fmt.Fprintf(w, "%s\t%s\n", name, placement.Host)
+		case placement.Err != nil:
+			fmt.Fprintf(w, "%s\t<none>\terror: %v\n", name, placement.Err)
+		default:
+			fmt.Fprintf(w, "%s\t<none>\tno minion fits\n", name)
+			minions := []string{}
+			for minion := range placement.FailedPredicates {
+				minions = append(minions, minion)
+			}
+			sort.Strings(minions)
+			for _, minion := range minions {
+				fmt.Fprintf(w, "\t%s: %s\n", minion, strings.Join(placement.FailedPredicates[minion].List(), ", "))
+			}
+		}
+	}
+	fmt.Fprintf(w, "placed %d of %d pods\n", placed, len(placements))
+}
@@ -0,0 +1,121 @@
+/*
+Copyright 2015 Google Inc. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package simulator
+
+import (
+	"bytes"
+	"testing"
+
+	_ "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/algorithmprovider"
+	schedulerapi "github.com/GoogleCloudPlatform/kubernetes/plugin/pkg/scheduler/api"
+)
+
+const clusterDump = `{
+  "kind": "List",
+  "apiVersion": "v1beta3",
+  "items": [
+    {
+      "kind": "Node",
+      "apiVersion": "v1beta3",
+      "metadata": {"name": "minion-1"},
+      "status": {"capacity": {"cpu": "1", "memory": "1Gi"}}
+    },
+    {
+      "kind": "Node",
+      "apiVersion": "v1beta3",
+      "metadata": {"name": "minion-2"},
+      "status": {"capacity": {"cpu": "1", "memory": "1Gi"}}
+    },
+    {
+      "kind": "PodList",
+      "apiVersion": "v1beta3",
+      "items": [
+        {
+          "metadata": {"name": "running", "namespace": "default"},
+          "spec": {"containers": [{"name": "ctr", "image": "image", "resources": {"limits": {"cpu": "800m"}}}]},
+          "status": {"host": "minion-1"}
+        }
+      ]
+    },
+    {
+      "kind": "Pod",
+      "apiVersion": "v1beta3",
+      "metadata": {"name": "small", "namespace": "default"},
+      "spec": {"containers": [{"name": "ctr", "image": "image", "resources": {"limits": {"cpu": "500m"}}}]}
+    },
+    {
+      "kind": "Pod",
+      "apiVersion": "v1beta3",
+      "metadata": {"name": "big", "namespace": "default"},
+      "spec": {"containers": [{"name": "ctr", "image": "image", "resources": {"limits": {"cpu": "600m"}}}]}
+    }
+  ]
+}`
+
+func TestSimulator(t *testing.T) {
+	objects, err := DecodeList([]byte(clusterDump))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, err := NewSimulator(objects)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(s.Pending) != 2 {
+		t.Fatalf("expected 2 pending pods, got %d", len(s.Pending))
+	}
+
+	policy := schedulerapi.Policy{
+		Predicates: []schedulerapi.PredicatePolicy{{Name: "PodFitsResources"}},
+		Priorities: []schedulerapi.PriorityPolicy{{Name: "LeastRequestedPriority", Weight: 1}},
+	}
+	algo, err := s.Factory.CreateAlgorithmFromConfig(policy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	placements := s.Run(algo)
+	if len(placements) != 2 {
+		t.Fatalf("expected 2 placements, got %d", len(placements))
+	}
+
+	// Only minion-2 has room for the small pod, and then no minion has room for the big one.
+	if e, a := "minion-2", placements[0].Host; e != a {
+		t.Errorf("expected small pod on %v, got %v", e, a)
+	}
+	if a := placements[1].Host; a != "" {
+		t.Errorf("expected big pod not to be placed, got %v", a)
+	}
+	if placements[1].Err != nil {
+		t.Errorf("unexpected error: %v", placements[1].Err)
+	}
+	for _, minion := range []string{"minion-1", "minion-2"} {
+		if !placements[1].FailedPredicates[minion].Has("PodFitsResources") {
+			t.Errorf("expected %v to fail PodFitsResources, got %v", minion, placements[1].FailedPredicates)
+		}
+	}
+
+	out := &bytes.Buffer{}
+	PrintPlacements(out, placements)
+	expected := "default/small\tminion-2\n" +
+		"default/big\t<none>\tno minion fits\n" +
+		"\tminion-1: PodFitsResources\n" +
+		"\tminion-2: PodFitsResources\n" +
+		"placed 1 of 2 pods\n"
+	if e, a := expected, out.String(); e != a {
+		t.Errorf("expected output:\n%s\ngot:\n%s", e, a)
+	}
+}
@@ -71,8 +71,8 @@ var _ = Describe("PD", func() {
 			By("cleaning up PD-RW test environment")
 			// Teardown pods, PD. Ignore errors.
 			// Teardown should do nothing unless test failed.
-			podClient.Delete(host0Pod.Name)
-			podClient.Delete(host1Pod.Name)
+			podClient.Delete(host0Pod.Name, nil)
+			podClient.Delete(host1Pod.Name, nil)
 			detachPD(host0Name, diskName, testContext.gceConfig.Zone)
 			detachPD(host1Name, diskName, testContext.gceConfig.Zone)
 			deletePD(diskName, testContext.gceConfig.Zone)
@@ -85,7 +85,7 @@ var _ = Describe("PD", func() {
 		expectNoError(waitForPodRunning(c, host0Pod.Name))
 
 		By("deleting host0Pod")
-		expectNoError(podClient.Delete(host0Pod.Name), "Failed to delete host0Pod")
+		expectNoError(podClient.Delete(host0Pod.Name, nil), "Failed to delete host0Pod")
 
 		By("submitting host1Pod to kubernetes")
 		_, err = podClient.Create(host1Pod)
@@ -94,7 +94,7 @@ var _ = Describe("PD", func() {
 		expectNoError(waitForPodRunning(c, host1Pod.Name))
 
 		By("deleting host1Pod")
-		expectNoError(podClient.Delete(host1Pod.Name), "Failed to delete host1Pod")
+		expectNoError(podClient.Delete(host1Pod.Name, nil), "Failed to delete host1Pod")
 
 		By(fmt.Sprintf("deleting PD %q", diskName))
 		for start := time.Now(); time.Since(start) < 180*time.Second; time.Sleep(5 * time.Second) {
@@ -124,9 +124,9 @@ var _ = Describe("PD", func() {
 			By("cleaning up PD-RO test environment")
 			// Teardown pods, PD. Ignore errors.
 			// Teardown should do nothing unless test failed.
-			podClient.Delete(rwPod.Name)
-			podClient.Delete(host0ROPod.Name)
-			podClient.Delete(host1ROPod.Name)
+			podClient.Delete(rwPod.Name, nil)
+			podClient.Delete(host0ROPod.Name, nil)
+			podClient.Delete(host1ROPod.Name, nil)
 			detachPD(host0Name, diskName, testContext.gceConfig.Zone)
 			detachPD(host1Name, diskName, testContext.gceConfig.Zone)
 			deletePD(diskName, testContext.gceConfig.Zone)
@@ -139,7 +139,7 @@ var _ = Describe("PD", func() {
 		_, err := podClient.Create(rwPod)
 		expectNoError(err, "Failed to create rwPod")
 		expectNoError(waitForPodRunning(c, rwPod.Name))
-		expectNoError(podClient.Delete(rwPod.Name), "Failed to delete host0Pod")
+		expectNoError(podClient.Delete(rwPod.Name, nil), "Failed to delete host0Pod")
 
 		By("submitting host0ROPod to kubernetes")
 		_, err = podClient.Create(host0ROPod)
@@ -154,10 +154,10 @@ var _ = Describe("PD", func() {
 		expectNoError(waitForPodRunning(c, host1ROPod.Name))
 
 		By("deleting host0ROPod")
-		expectNoError(podClient.Delete(host0ROPod.Name), "Failed to delete host0ROPod")
+		expectNoError(podClient.Delete(host0ROPod.Name, nil), "Failed to delete host0ROPod")
 
 		By("deleting host1ROPod")
-		expectNoError(podClient.Delete(host1ROPod.Name), "Failed to delete host1ROPod")
+		expectNoError(podClient.Delete(host1ROPod.Name, nil), "Failed to delete host1ROPod")
 
 		By(fmt.Sprintf("deleting PD %q", diskName))
 		for start := time.Now(); time.Since(start) < 180*time.Second; time.Sleep(5 * time.Second) {
@@ -114,7 +114,7 @@ var _ = Describe("Services", func() {
 		defer func() {
 			By("deleting the pod")
 			defer GinkgoRecover()
-			podClient.Delete(pod.Name)
+			podClient.Delete(pod.Name, nil)
 		}()
 		if _, err := podClient.Create(pod); err != nil {
 			Failf("Failed to create %s pod: %v", pod.Name, err)
@@ -200,9 +200,11 @@ var _ = Describe("Services", func() {
 				Name: serviceName,
 			},
 			Spec: api.ServiceSpec{
-				Port:       80,
-				Selector:   labels,
-				TargetPort: util.NewIntOrStringFromInt(80),
+				Ports: []api.ServicePort{{
+					Port:       80,
+					TargetPort: util.NewIntOrStringFromInt(80),
+				}},
+				Selector: labels,
 			},
 		}
 		_, err := c.Services(ns).Create(service)
@@ -214,7 +216,7 @@ var _ = Describe("Services", func() {
 		var names []string
 		defer func() {
 			for _, name := range names {
-				err := c.Pods(ns).Delete(name)
+				err := c.Pods(ns).Delete(name, nil)
 				Expect(err).NotTo(HaveOccurred())
 			}
 		}()
@@ -231,13 +233,13 @@ var _ = Describe("Services", func() {
 
 		validateEndpointsOrFail(c, ns, serviceName, expectedPort, names)
 
-		err = c.Pods(ns).Delete(name1)
+		err = c.Pods(ns).Delete(name1, nil)
 		Expect(err).NotTo(HaveOccurred())
 		names = []string{name2}
 
 		validateEndpointsOrFail(c, ns, serviceName, expectedPort, names)
 
-		err = c.Pods(ns).Delete(name2)
+		err = c.Pods(ns).Delete(name2, nil)
 		Expect(err).NotTo(HaveOccurred())
 		names = []string{}
 
@@ -260,9 +262,11 @@ var _ = Describe("Services", func() {
 				Name: serviceName,
 			},
 			Spec: api.ServiceSpec{
-				Port:                       80,
+				Ports: []api.ServicePort{{
+					Port:       80,
+					TargetPort: util.NewIntOrStringFromInt(80),
+				}},
 				Selector:                   labels,
-				TargetPort:                 util.NewIntOrStringFromInt(80),
 				CreateExternalLoadBalancer: true,
 			},
 		}
@@ -283,7 +287,7 @@ var _ = Describe("Services", func() {
 			Failf("got unexpected number (%d) of public IPs for externally load balanced service: %v", result.Spec.PublicIPs, result)
 		}
 		ip := result.Spec.PublicIPs[0]
-		port := result.Spec.Port
+		port := result.Spec.Ports[0].Port
 
 		pod := &api.Pod{
 			TypeMeta: api.TypeMeta{
@@ -309,7 +313,7 @@ var _ = Describe("Services", func() {
 		defer func() {
 			By("deleting pod " + pod.Name)
 			defer GinkgoRecover()
-			podClient.Delete(pod.Name)
+			podClient.Delete(pod.Name, nil)
 		}()
 		if _, err := podClient.Create(pod); err != nil {
 			Failf("Failed to create pod %s: %v", pod.Name, err)
@@ -347,9 +351,11 @@ var _ = Describe("Services", func() {
 		service := &api.Service{
 			ObjectMeta: api.ObjectMeta{},
 			Spec: api.ServiceSpec{
-				Port:                       80,
+				Ports: []api.ServicePort{{
+					Port:       80,
+					TargetPort: util.NewIntOrStringFromInt(80),
+				}},
 				Selector:                   labels,
-				TargetPort:                 util.NewIntOrStringFromInt(80),
 				CreateExternalLoadBalancer: true,
 			},
 		}
@@ -37,7 +37,7 @@ func init() {
 }
 
 func deletePodOrErrorf(t *testing.T, c *client.Client, ns, name string) {
-	if err := c.Pods(ns).Delete(name); err != nil {
+	if err := c.Pods(ns).Delete(name, nil); err != nil {
 		t.Errorf("unable to delete pods %v: %v", name, err)
 	}
 }